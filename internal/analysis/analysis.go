@@ -0,0 +1,140 @@
+// Package analysis gathers static analysis and coverage data for planning:
+// vet and unused-code findings from whichever tools the project supports
+// and are installed, plus coverage reports a previous test run left
+// behind. Findings are summarized so the planner can propose cleanup and
+// test-gap tasks grounded in real data. Nothing here runs the test suite.
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	maxFindings = 25 // listed per tool
+	toolTimeout = 3 * time.Minute
+)
+
+// Report is one tool's findings.
+type Report struct {
+	Tool     string   // e.g. "go vet", "coverage (coverage.out)"
+	Summary  string   // one line, e.g. "7 findings" or "61.3% of statements covered"
+	Findings []string // "internal/x.go:12: unreachable code", capped at maxFindings
+	Omitted  int      // findings beyond the cap
+}
+
+// tools runs commands; tests substitute fakes.
+type tools struct {
+	lookPath func(name string) (string, error)
+	run      func(ctx context.Context, dir, name string, args ...string) (string, error)
+}
+
+var system = tools{lookPath: exec.LookPath, run: runCommand}
+
+// analyzer produces a report if it applies to the project at root. ok is
+// false when it doesn't (wrong language, tool not installed, no report).
+type analyzer func(ctx context.Context, t tools, root string) (r Report, ok bool, err error)
+
+var analyzers = []analyzer{goVet, staticcheckUnused, tsPrune, vulture, coverage}
+
+// Run applies every analyzer that fits the project. A tool that fails is
+// reported in errs and doesn't stop the others.
+func Run(ctx context.Context, root string) ([]Report, []error) {
+	return system.runAll(ctx, root)
+}
+
+func (t tools) runAll(ctx context.Context, root string) ([]Report, []error) {
+	var reports []Report
+	var errs []error
+	for _, a := range analyzers {
+		r, ok, err := a(ctx, t, root)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case ok:
+			reports = append(reports, r)
+		}
+	}
+	return reports, errs
+}
+
+// Summary renders reports as plain text, one block per tool.
+func Summary(reports []Report, errs []error) string {
+	var b strings.Builder
+	for _, r := range reports {
+		fmt.Fprintf(&b, "%s: %s\n", r.Tool, r.Summary)
+		for _, f := range r.Findings {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+		if r.Omitted > 0 {
+			fmt.Fprintf(&b, "  … and %d more\n", r.Omitted)
+		}
+	}
+	for _, err := range errs {
+		fmt.Fprintf(&b, "(skipped: %v)\n", err)
+	}
+	return b.String()
+}
+
+// Section renders the reports for the planning prompt, or "" if no
+// analyzer applied.
+func Section(reports []Report, errs []error) string {
+	if len(reports)+len(errs) == 0 {
+		return ""
+	}
+	return "\n\nSTATIC ANALYSIS AND COVERAGE (measured on the current code — propose cleanup and test-gap tasks grounded in these findings where they matter for the project's goals, citing file:line):\n" +
+		Summary(reports, errs)
+}
+
+// Instruction is the chat message /analyze sends in the planning phase.
+func Instruction(reports []Report, errs []error) string {
+	if len(reports)+len(errs) == 0 {
+		return "I ran forge's static analysis but no supported tools or coverage reports apply to this project. Suggest how we could measure code health and test coverage here, and whether the plan should include setting that up."
+	}
+	return "Here are static analysis and coverage findings for this project:\n\n" + Summary(reports, errs) +
+		"\nPropose cleanup and test-gap tasks grounded in these findings (cite file:line, group related findings into one task), and ask which ones I want included in the plan."
+}
+
+// newReport caps findings and fills in the summary count.
+func newReport(tool string, findings []string, noun string) Report {
+	r := Report{Tool: tool, Summary: plural(len(findings), noun)}
+	if len(findings) > maxFindings {
+		r.Omitted = len(findings) - maxFindings
+		findings = findings[:maxFindings]
+	}
+	r.Findings = findings
+	return r
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func runCommand(ctx context.Context, dir, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, toolTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out.String(), fmt.Errorf("%s timed out after %s", name, toolTimeout)
+	}
+	return out.String(), err
+}
+
+func fileExists(root string, rel ...string) bool {
+	_, err := os.Stat(filepath.Join(append([]string{root}, rel...)...))
+	return err == nil
+}
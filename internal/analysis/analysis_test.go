@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeTools reports only the named binaries as installed and answers each
+// command with canned output.
+func fakeTools(installed []string, outputs map[string]string, errs map[string]error) tools {
+	return tools{
+		lookPath: func(name string) (string, error) {
+			for _, n := range installed {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		run: func(_ context.Context, _ string, name string, _ ...string) (string, error) {
+			name = filepath.Base(name)
+			return outputs[name], errs[name]
+		},
+	}
+}
+
+func TestRunAll_Go(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, dir, "coverage.out", `mode: set
+example.com/app/internal/store/store.go:10.2,12.3 4 1
+example.com/app/internal/store/store.go:14.2,20.3 6 0
+example.com/app/internal/store/store.go:14.2,20.3 6 0
+example.com/app/main.go:5.13,8.2 2 1
+`)
+	vet := "# example.com/app/internal/store\n" +
+		"internal/store/store.go:31:2: unreachable code\n" +
+		"./main.go:9:14: fmt.Sprintf format %d has arg x of wrong type string\n"
+	unused := "internal/store/store.go:40:6: func legacyKey is unused (U1000)\n"
+	tl := fakeTools([]string{"go", "staticcheck"},
+		map[string]string{"go": vet, "staticcheck": unused},
+		map[string]error{"go": errors.New("exit status 1"), "staticcheck": errors.New("exit status 1")})
+
+	reports, errs := tl.runAll(context.Background(), dir)
+	if len(errs) != 0 {
+		t.Fatalf("errs = %v", errs)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("got %d reports, want vet, staticcheck and coverage: %+v", len(reports), reports)
+	}
+	if want := []string{"internal/store/store.go:31: unreachable code", "main.go:9: fmt.Sprintf format %d has arg x of wrong type string"}; !reflect.DeepEqual(reports[0].Findings, want) {
+		t.Errorf("go vet findings = %q, want %q", reports[0].Findings, want)
+	}
+	if want := []string{"internal/store/store.go:40: func legacyKey is unused"}; !reflect.DeepEqual(reports[1].Findings, want) {
+		t.Errorf("staticcheck findings = %q, want %q", reports[1].Findings, want)
+	}
+	cov := reports[2]
+	if !strings.HasPrefix(cov.Tool, "coverage (coverage.out, from ") {
+		t.Errorf("coverage tool = %q", cov.Tool)
+	}
+	if want := "50.0% of 12 statements covered; 1 of 2 files below 60%"; cov.Summary != want {
+		t.Errorf("coverage summary = %q, want %q", cov.Summary, want)
+	}
+	if want := []string{"internal/store/store.go: 40% (4/10)"}; !reflect.DeepEqual(cov.Findings, want) {
+		t.Errorf("coverage findings = %q, want %q", cov.Findings, want)
+	}
+}
+
+func TestRunAll_ToolFailureIsReported(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n")
+	tl := fakeTools([]string{"go"},
+		map[string]string{"go": "go: updates to go.mod needed\n"},
+		map[string]error{"go": errors.New("exit status 1")})
+
+	reports, errs := tl.runAll(context.Background(), dir)
+	if len(reports) != 0 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "updates to go.mod needed") {
+		t.Errorf("runAll() = %+v, %v; want one go vet error", reports, errs)
+	}
+}
+
+func TestRunAll_NothingApplies(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, dir, "index.html", "<p>hi</p>")
+	reports, errs := fakeTools(nil, nil, nil).runAll(context.Background(), dir)
+	if len(reports)+len(errs) != 0 {
+		t.Errorf("runAll() = %+v, %v; want nothing", reports, errs)
+	}
+	if Section(reports, errs) != "" {
+		t.Error("Section() should be empty when nothing applies")
+	}
+	if !strings.Contains(Instruction(reports, errs), "no supported tools") {
+		t.Errorf("Instruction() = %q", Instruction(reports, errs))
+	}
+}
+
+func TestParseTSPrune(t *testing.T) {
+	t.Parallel()
+	out := "src/util/format.ts:12 - formatDate\nsrc/index.ts:3 - helper (used in module)\nsrc/api.tsx:40 - default\n"
+	want := []string{"src/util/format.ts:12: unused export formatDate", "src/api.tsx:40: unused export default"}
+	if got := parseTSPrune(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTSPrune() = %q, want %q", got, want)
+	}
+}
+
+func TestVulture(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, dir, "pyproject.toml", "[project]\nname = 'app'\n")
+	out := "./app/models.py:14: unused import 'os' (90% confidence)\napp/views.py:88: unused function 'old_view' (60% confidence)\n"
+	r, ok, err := vulture(context.Background(), fakeTools([]string{"vulture"}, map[string]string{"vulture": out}, nil), dir)
+	want := []string{"app/models.py:14: unused import 'os'", "app/views.py:88: unused function 'old_view'"}
+	if err != nil || !ok || !reflect.DeepEqual(r.Findings, want) || r.Summary != "2 findings" {
+		t.Errorf("vulture() = %+v, %v, %v; want findings %q", r, ok, err, want)
+	}
+}
+
+// ============================================================
+// Coverage reports
+// ============================================================
+
+func TestParseLCOV(t *testing.T) {
+	t.Parallel()
+	root := "/work/app"
+	content := "TN:\nSF:/work/app/src/a.ts\nLF:10\nLH:9\nend_of_record\nSF:src/b.ts\nLF:20\nLH:2\nend_of_record\n"
+	want := []fileCoverage{{path: "src/a.ts", covered: 9, total: 10}, {path: "src/b.ts", covered: 2, total: 20}}
+	if got := parseLCOV(root, content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLCOV() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCobertura(t *testing.T) {
+	t.Parallel()
+	content := `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <packages><package name="app"><classes>
+    <class name="models.py" filename="app/models.py" line-rate="0.75">
+      <lines><line number="1" hits="1"/><line number="2" hits="3"/><line number="3" hits="0"/><line number="4" hits="1"/></lines>
+    </class>
+    <class name="views.py" filename="app/views.py" line-rate="0">
+      <lines><line number="1" hits="0"/><line number="2" hits="0"/></lines>
+    </class>
+  </classes></package></packages>
+</coverage>`
+	want := []fileCoverage{{path: "app/models.py", covered: 3, total: 4}, {path: "app/views.py", covered: 0, total: 2}}
+	if got := parseCobertura("/x", content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCobertura() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseGoProfile_NotAProfile(t *testing.T) {
+	t.Parallel()
+	if got := parseGoProfile(t.TempDir(), "ok  \texample.com/app\t0.2s\n"); got != nil {
+		t.Errorf("parseGoProfile() = %+v, want nil for plain test output", got)
+	}
+}
+
+func TestSummary_CapsFindings(t *testing.T) {
+	t.Parallel()
+	var findings []string
+	for range maxFindings + 3 {
+		findings = append(findings, "a.go:1: x")
+	}
+	r := newReport("go vet", findings, "finding")
+	got := Summary([]Report{r}, []error{errors.New("ts-prune: boom")})
+	if !strings.HasPrefix(got, "go vet: 28 findings\n") || !strings.Contains(got, "… and 3 more") || !strings.Contains(got, "(skipped: ts-prune: boom)") {
+		t.Errorf("Summary() =\n%s", got)
+	}
+}
@@ -0,0 +1,247 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lowCoverage is the per-file line below which a file is listed as a gap.
+const lowCoverage = 60.0
+
+// coverageReports are the report files looked for, in order; the first
+// found is used. Go profiles are recognized by their "mode:" header.
+var coverageReports = []struct {
+	path  string
+	unit  string
+	parse func(root, content string) []fileCoverage
+}{
+	{"coverage.out", "statements", parseGoProfile},
+	{"cover.out", "statements", parseGoProfile},
+	{"coverage.txt", "statements", parseGoProfile},
+	{"c.out", "statements", parseGoProfile},
+	{"coverage/lcov.info", "lines", parseLCOV},
+	{"lcov.info", "lines", parseLCOV},
+	{"coverage.xml", "lines", parseCobertura},
+	{"coverage/cobertura-coverage.xml", "lines", parseCobertura},
+}
+
+// fileCoverage is how much of one file the tests exercised, in statements
+// (Go) or lines (lcov, Cobertura).
+type fileCoverage struct {
+	path           string
+	covered, total int
+}
+
+func (f fileCoverage) percent() float64 {
+	if f.total == 0 {
+		return 100
+	}
+	return 100 * float64(f.covered) / float64(f.total)
+}
+
+// coverage summarizes the first coverage report a test run left in the
+// project: the overall figure and the least covered files.
+func coverage(_ context.Context, _ tools, root string) (Report, bool, error) {
+	for _, c := range coverageReports {
+		path := filepath.Join(root, filepath.FromSlash(c.path))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		files := c.parse(root, string(data))
+		if len(files) == 0 {
+			continue
+		}
+		r := coverageReport(files, c.unit)
+		r.Tool = "coverage (" + c.path
+		if info, err := os.Stat(path); err == nil {
+			r.Tool += ", from " + info.ModTime().Format("2006-01-02")
+		}
+		r.Tool += ")"
+		return r, true, nil
+	}
+	return Report{}, false, nil
+}
+
+// coverageReport lists files under lowCoverage, least covered first.
+func coverageReport(files []fileCoverage, unit string) Report {
+	var covered, total int
+	var low []fileCoverage
+	for _, f := range files {
+		covered += f.covered
+		total += f.total
+		if f.total > 0 && f.percent() < lowCoverage {
+			low = append(low, f)
+		}
+	}
+	sort.SliceStable(low, func(i, j int) bool {
+		if low[i].percent() != low[j].percent() {
+			return low[i].percent() < low[j].percent()
+		}
+		return low[i].total > low[j].total
+	})
+
+	r := Report{Summary: fmt.Sprintf("%.1f%% of %d %s covered; %d of %d files below %.0f%%",
+		fileCoverage{covered: covered, total: total}.percent(), total, unit, len(low), len(files), lowCoverage)}
+	for i, f := range low {
+		if i == maxFindings {
+			r.Omitted = len(low) - maxFindings
+			break
+		}
+		r.Findings = append(r.Findings, fmt.Sprintf("%s: %.0f%% (%d/%d)", f.path, f.percent(), f.covered, f.total))
+	}
+	return r
+}
+
+// parseGoProfile reads a `go test -coverprofile` file. Blocks repeated by
+// -coverpkg runs count once, covered if any run covered them.
+func parseGoProfile(root, content string) []fileCoverage {
+	lines := strings.Split(content, "\n")
+	if !strings.HasPrefix(lines[0], "mode:") {
+		return nil
+	}
+	module := goModule(root)
+
+	type block struct{ stmts, count int }
+	blocks := map[string]block{}
+	var order []string
+	for _, line := range lines[1:] {
+		// github.com/x/y/file.go:12.3,14.5 2 1
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		b, seen := blocks[fields[0]]
+		if !seen {
+			order = append(order, fields[0])
+		}
+		blocks[fields[0]] = block{stmts: stmts, count: max(b.count, count)}
+	}
+
+	byFile := map[string]*fileCoverage{}
+	var files []string
+	for _, key := range order {
+		name, _, _ := strings.Cut(key, ":")
+		if module != "" {
+			name = strings.TrimPrefix(name, module+"/")
+		}
+		f, ok := byFile[name]
+		if !ok {
+			f = &fileCoverage{path: name}
+			byFile[name] = f
+			files = append(files, name)
+		}
+		b := blocks[key]
+		f.total += b.stmts
+		if b.count > 0 {
+			f.covered += b.stmts
+		}
+	}
+	return collect(files, byFile)
+}
+
+var goModuleRe = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+func goModule(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	if m := goModuleRe.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// parseLCOV reads an lcov tracefile (SF/LF/LH records).
+func parseLCOV(root, content string) []fileCoverage {
+	byFile := map[string]*fileCoverage{}
+	var files []string
+	var cur *fileCoverage
+	for _, line := range strings.Split(content, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch key {
+		case "SF":
+			name := relPath(root, value)
+			cur = byFile[name]
+			if cur == nil {
+				cur = &fileCoverage{path: name}
+				byFile[name] = cur
+				files = append(files, name)
+			}
+		case "LF":
+			if n, err := strconv.Atoi(value); err == nil && cur != nil {
+				cur.total += n
+			}
+		case "LH":
+			if n, err := strconv.Atoi(value); err == nil && cur != nil {
+				cur.covered += n
+			}
+		case "end_of_record":
+			cur = nil
+		}
+	}
+	return collect(files, byFile)
+}
+
+var (
+	coberturaClassRe = regexp.MustCompile(`(?s)<class\b[^>]*>.*?</class>`)
+	coberturaFileRe  = regexp.MustCompile(`\bfilename="([^"]+)"`)
+	coberturaLineRe  = regexp.MustCompile(`<line\b[^>]*\bhits="(\d+)"`)
+)
+
+// parseCobertura reads a Cobertura XML report (coverage.py, Jest, and
+// others), counting each class's <line> hits.
+func parseCobertura(root, content string) []fileCoverage {
+	byFile := map[string]*fileCoverage{}
+	var files []string
+	for _, class := range coberturaClassRe.FindAllString(content, -1) {
+		m := coberturaFileRe.FindStringSubmatch(class)
+		if m == nil {
+			continue
+		}
+		name := relPath(root, m[1])
+		f := byFile[name]
+		if f == nil {
+			f = &fileCoverage{path: name}
+			byFile[name] = f
+			files = append(files, name)
+		}
+		for _, hit := range coberturaLineRe.FindAllStringSubmatch(class, -1) {
+			f.total++
+			if hit[1] != "0" {
+				f.covered++
+			}
+		}
+	}
+	return collect(files, byFile)
+}
+
+func collect(order []string, byFile map[string]*fileCoverage) []fileCoverage {
+	out := make([]fileCoverage, 0, len(order))
+	for _, name := range order {
+		out = append(out, *byFile[name])
+	}
+	return out
+}
+
+// relPath makes report paths, often absolute, relative to root.
+func relPath(root, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
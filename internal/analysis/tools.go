@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ============================================================
+// Go
+// ============================================================
+
+var goDiagRe = regexp.MustCompile(`^(?:\./)?(\S+\.go):(\d+)(?::\d+)?: (.+)$`)
+
+// goVet reports `go vet ./...` diagnostics. vet exits non-zero when it
+// finds something, so the exit status only matters if nothing parsed.
+func goVet(ctx context.Context, t tools, root string) (Report, bool, error) {
+	if !fileExists(root, "go.mod") {
+		return Report{}, false, nil
+	}
+	if _, err := t.lookPath("go"); err != nil {
+		return Report{}, false, nil
+	}
+	out, err := t.run(ctx, root, "go", "vet", "./...")
+	findings := parseGoDiagnostics(out)
+	if err != nil && len(findings) == 0 {
+		return Report{}, false, fmt.Errorf("go vet: %s", firstLine(out, err))
+	}
+	return newReport("go vet", findings, "finding"), true, nil
+}
+
+// staticcheckUnused reports unused code (check U1000) when staticcheck is
+// installed.
+func staticcheckUnused(ctx context.Context, t tools, root string) (Report, bool, error) {
+	if !fileExists(root, "go.mod") {
+		return Report{}, false, nil
+	}
+	if _, err := t.lookPath("staticcheck"); err != nil {
+		return Report{}, false, nil
+	}
+	out, err := t.run(ctx, root, "staticcheck", "-checks", "U1000", "./...")
+	findings := parseGoDiagnostics(out)
+	if err != nil && len(findings) == 0 {
+		return Report{}, false, fmt.Errorf("staticcheck: %s", firstLine(out, err))
+	}
+	for i, f := range findings {
+		findings[i] = strings.TrimSuffix(f, " (U1000)")
+	}
+	return newReport("staticcheck (unused code)", findings, "unused identifier"), true, nil
+}
+
+// parseGoDiagnostics keeps "file.go:line: message" lines from go vet and
+// staticcheck output, dropping columns and package headers.
+func parseGoDiagnostics(out string) []string {
+	var findings []string
+	for _, line := range strings.Split(out, "\n") {
+		if m := goDiagRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			findings = append(findings, fmt.Sprintf("%s:%s: %s", filepath.ToSlash(m[1]), m[2], m[3]))
+		}
+	}
+	return findings
+}
+
+// ============================================================
+// TypeScript
+// ============================================================
+
+var tsPruneRe = regexp.MustCompile(`^(\S+\.[cm]?[jt]sx?):(\d+) - (\S+)(.*)$`)
+
+// tsPrune reports unused exports when the project has ts-prune installed.
+func tsPrune(ctx context.Context, t tools, root string) (Report, bool, error) {
+	bin := filepath.Join(root, "node_modules", ".bin", "ts-prune")
+	if !fileExists(bin) || !fileExists(root, "tsconfig.json") {
+		return Report{}, false, nil
+	}
+	out, err := t.run(ctx, root, bin)
+	findings := parseTSPrune(out)
+	if err != nil && len(findings) == 0 {
+		return Report{}, false, fmt.Errorf("ts-prune: %s", firstLine(out, err))
+	}
+	return newReport("ts-prune (unused exports)", findings, "unused export"), true, nil
+}
+
+// parseTSPrune keeps unused exports, skipping those only used inside
+// their own module.
+func parseTSPrune(out string) []string {
+	var findings []string
+	for _, line := range strings.Split(out, "\n") {
+		m := tsPruneRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || strings.Contains(m[4], "used in module") {
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("%s:%s: unused export %s", m[1], m[2], m[3]))
+	}
+	return findings
+}
+
+// ============================================================
+// Python
+// ============================================================
+
+var vultureRe = regexp.MustCompile(`^(\S+\.py):(\d+): (.+?)(?: \(\d+% confidence\))?$`)
+
+// vulture reports dead Python code when vulture is installed.
+func vulture(ctx context.Context, t tools, root string) (Report, bool, error) {
+	if !fileExists(root, "pyproject.toml") && !fileExists(root, "setup.py") && !fileExists(root, "requirements.txt") {
+		return Report{}, false, nil
+	}
+	if _, err := t.lookPath("vulture"); err != nil {
+		return Report{}, false, nil
+	}
+	out, err := t.run(ctx, root, "vulture", ".", "--exclude", ".venv,venv,node_modules,.forge")
+	var findings []string
+	for _, line := range strings.Split(out, "\n") {
+		if m := vultureRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			findings = append(findings, fmt.Sprintf("%s:%s: %s", strings.TrimPrefix(m[1], "./"), m[2], m[3]))
+		}
+	}
+	if err != nil && len(findings) == 0 {
+		return Report{}, false, fmt.Errorf("vulture: %s", firstLine(out, err))
+	}
+	return newReport("vulture (dead code)", findings, "finding"), true, nil
+}
+
+// firstLine is the first line of a failed tool's output, or its error.
+func firstLine(out string, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
@@ -97,16 +97,16 @@ func (c *Client) WithEnvVars(envVars map[string]string) *Client {
 }
 
 // Send sends a one-shot message to Claude Code (non-streaming).
-// Runs: claude --print --output-format json "<prompt>"
+// Runs: claude --print --output-format json --model <model> "<prompt>"
 func (c *Client) Send(ctx context.Context, prompt string) (*Response, error) {
-	args := []string{"--print", "--output-format", "json", prompt}
+	args := []string{"--print", "--output-format", "json", "--model", c.model, prompt}
 	return c.runClaude(ctx, args)
 }
 
 // Continue sends a follow-up message in an existing session (non-streaming).
-// Runs: claude --print --continue --output-format json "<message>"
+// Runs: claude --print --continue --output-format json --model <model> "<message>"
 func (c *Client) Continue(ctx context.Context, message string) (*Response, error) {
-	args := []string{"--print", "--continue", "--output-format", "json", message}
+	args := []string{"--print", "--continue", "--output-format", "json", "--model", c.model, message}
 	return c.runClaude(ctx, args)
 }
 
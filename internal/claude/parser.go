@@ -9,10 +9,24 @@ import (
 
 // PlanJSON represents the structured plan output from Claude during initial planning.
 type PlanJSON struct {
-	ProjectName string         `json:"project_name"`
-	Description string         `json:"description"`
-	TechStack   []string       `json:"tech_stack"`
-	Tasks       []PlanTaskJSON `json:"tasks"`
+	SchemaVersion int            `json:"schema_version,omitempty"` // PlanSchemaVersion; 0 = from before versioning
+	ProjectName   string         `json:"project_name"`
+	Description   string         `json:"description"`
+	TechStack     []string       `json:"tech_stack"`
+	Tasks         []PlanTaskJSON `json:"tasks"`
+	Memory        *MemoryJSON    `json:"memory,omitempty"`
+	Architecture  string         `json:"architecture,omitempty"` // Markdown, saved as .forge/architecture.md
+
+	// Warnings are the schema's notes on the reply, e.g. unknown fields.
+	Warnings []string `json:"-"`
+}
+
+// MemoryJSON holds what the planner wants remembered for later plans in
+// the same repository.
+type MemoryJSON struct {
+	Decisions   []string `json:"decisions,omitempty"`
+	Constraints []string `json:"constraints,omitempty"`
+	Conventions []string `json:"conventions,omitempty"`
 }
 
 // PlanTaskJSON represents a single task in the initial plan.
@@ -22,12 +36,53 @@ type PlanTaskJSON struct {
 	AcceptanceCriteria []string `json:"acceptance_criteria"`
 	DependsOn          []int    `json:"depends_on,omitempty"`
 	Complexity         string   `json:"estimated_complexity"`
+	Type               string   `json:"type,omitempty"`
+	Commands           []string `json:"commands,omitempty"`
+	Artifacts          []string `json:"artifacts,omitempty"`
+	Repo               string   `json:"repo,omitempty"`
+}
+
+// PlanCandidatesJSON holds alternative plans proposed for the user to
+// choose from (the /alternatives command).
+type PlanCandidatesJSON struct {
+	SchemaVersion int                 `json:"schema_version,omitempty"`
+	Candidates    []PlanCandidateJSON `json:"candidates"`
+
+	// Warnings are the schema's notes on the reply, e.g. unknown fields.
+	Warnings []string `json:"-"`
+}
+
+// PlanCandidateJSON is one alternative: a complete plan and what sets it
+// apart.
+type PlanCandidateJSON struct {
+	Name    string   `json:"name"`
+	Summary string   `json:"summary"`
+	Plan    PlanJSON `json:"plan"`
+}
+
+// CriteriaJSON holds tightened acceptance criteria proposed for pending
+// tasks, only for the tasks whose criteria change.
+type CriteriaJSON struct {
+	SchemaVersion int                `json:"schema_version,omitempty"`
+	Tasks         []TaskCriteriaJSON `json:"tasks"`
+}
+
+// TaskCriteriaJSON is the full new criteria list for one task.
+type TaskCriteriaJSON struct {
+	ID                 string   `json:"id"`
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
 }
 
 // PlanUpdateJSON represents the structured output from a replanning session.
 type PlanUpdateJSON struct {
-	Summary string               `json:"summary"`
-	Tasks   []PlanUpdateTaskJSON `json:"tasks"`
+	SchemaVersion int                  `json:"schema_version,omitempty"`
+	Summary       string               `json:"summary"`
+	Tasks         []PlanUpdateTaskJSON `json:"tasks"`
+	Memory        *MemoryJSON          `json:"memory,omitempty"`
+	Architecture  string               `json:"architecture,omitempty"` // full revised document; "" keeps the current one
+
+	// Warnings are the schema's notes on the reply, e.g. unknown fields.
+	Warnings []string `json:"-"`
 }
 
 // PlanUpdateTaskJSON represents a single task action in a plan update.
@@ -39,7 +94,16 @@ type PlanUpdateTaskJSON struct {
 	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
 	DependsOn          []string `json:"depends_on,omitempty"`
 	Complexity         string   `json:"estimated_complexity,omitempty"`
+	Type               string   `json:"type,omitempty"`
+	Commands           []string `json:"commands,omitempty"`
+	Artifacts          []string `json:"artifacts,omitempty"`
+	Repo               string   `json:"repo,omitempty"`
 	Reason             string   `json:"reason,omitempty"`
+
+	// BranchDisposition is the user's choice for the branch of a removed
+	// task that has work on it ("kept" or "deleted", see
+	// state.BranchDisposition); never from the model.
+	BranchDisposition string `json:"-"`
 }
 
 // parseResponse parses the raw JSON output from claude --output-format json.
@@ -79,26 +143,24 @@ func parseResponse(raw []byte) (*Response, error) {
 }
 
 // ExtractFinalPlan checks if the response text contains <final_plan>...</final_plan> tags.
-// If found, parses the JSON inside and returns the plan.
+// If found, parses the JSON inside, repairing common mistakes, checks it
+// against FinalPlanSchema and returns the plan.
 // Returns nil, nil if no tags found.
-// Returns nil, error if tags found but JSON is malformed or missing required fields.
+// Returns nil, error if tags found but JSON is malformed or doesn't match the schema.
 func ExtractFinalPlan(text string) (*PlanJSON, error) {
 	content, found := extractTagContent(text, "final_plan")
 	if !found {
 		return nil, nil
 	}
 
-	var plan PlanJSON
-	if err := json.Unmarshal([]byte(content), &plan); err != nil {
-		return nil, fmt.Errorf("invalid JSON in <final_plan>: %w", err)
+	plan, warnings, err := decodeReply[PlanJSON](content, FinalPlanSchema)
+	if err != nil {
+		return nil, err
 	}
-
 	if plan.ProjectName == "" {
 		return nil, fmt.Errorf("invalid plan: missing project_name")
 	}
-	if len(plan.Tasks) == 0 {
-		return nil, fmt.Errorf("invalid plan: at least one task is required")
-	}
+	plan.Warnings = warnings
 
 	return &plan, nil
 }
@@ -106,27 +168,184 @@ func ExtractFinalPlan(text string) (*PlanJSON, error) {
 // ExtractPlanUpdate checks if the response text contains <plan_update>...</plan_update> tags.
 // If found, parses the JSON inside and returns the update.
 // Returns nil, nil if no tags found.
-// Returns nil, error if tags found but JSON is malformed or invalid.
+// Returns nil, error if tags found but JSON is malformed or doesn't match PlanUpdateSchema.
 func ExtractPlanUpdate(text string) (*PlanUpdateJSON, error) {
 	content, found := extractTagContent(text, "plan_update")
 	if !found {
 		return nil, nil
 	}
 
-	var update PlanUpdateJSON
-	if err := json.Unmarshal([]byte(content), &update); err != nil {
-		return nil, fmt.Errorf("invalid JSON in <plan_update>: %w", err)
+	update, warnings, err := decodeReply[PlanUpdateJSON](content, PlanUpdateSchema)
+	if err != nil {
+		return nil, err
 	}
-
 	for i, task := range update.Tasks {
 		if task.Action == "" {
 			return nil, fmt.Errorf("invalid plan update: task %d is missing action field", i)
 		}
 	}
+	update.Warnings = warnings
 
 	return &update, nil
 }
 
+// ExtractCritique returns the findings inside <critique>...</critique> tags,
+// one per "- " line. found is false if there are no tags; an empty critique
+// (no problems) returns nil, true.
+func ExtractCritique(text string) (findings []string, found bool) {
+	content, found := extractTagContent(text, "critique")
+	if !found {
+		return nil, false
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "-*•"))
+		if line != "" {
+			findings = append(findings, line)
+		}
+	}
+	return findings, true
+}
+
+// ExtractPlanPreview checks if the response text contains
+// <plan_preview>...</plan_preview> tags, a provisional task list asked for
+// with /preview. Only the tasks are required.
+// Returns nil, nil if no tags found.
+func ExtractPlanPreview(text string) (*PlanJSON, error) {
+	content, found := extractTagContent(text, "plan_preview")
+	if !found {
+		return nil, nil
+	}
+
+	plan, warnings, err := decodeReply[PlanJSON](content, PlanPreviewSchema)
+	if err != nil {
+		return nil, err
+	}
+	plan.Warnings = warnings
+	return &plan, nil
+}
+
+// PartialPlanTasks returns the tasks already complete in a <final_plan>
+// block that may still be streaming in, for showing the plan as it is
+// written. A task is complete once its closing brace arrives; ones that
+// don't parse are skipped here and reported by ExtractFinalPlan when the
+// reply ends. Returns nil if the block hasn't started.
+func PartialPlanTasks(text string) []PlanTaskJSON {
+	openIdx := strings.Index(text, "<final_plan>")
+	if openIdx == -1 {
+		return nil
+	}
+	s := text[openIdx+len("<final_plan>"):]
+	if closeIdx := strings.Index(s, "</final_plan>"); closeIdx != -1 {
+		s = s[:closeIdx]
+	}
+
+	// Depth 1 is the plan object, 2 the tasks array and 3 a task in it
+	var tasks []PlanTaskJSON
+	depth, taskStart, strStart := 0, -1, 0
+	inTasks, inString, escaped := false, false, false
+	lastKey := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				if depth == 1 {
+					lastKey = s[strStart:i]
+				}
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString, strStart = true, i+1
+		case '{', '[':
+			depth++
+			if c == '[' && depth == 2 && lastKey == "tasks" {
+				inTasks = true
+			}
+			if c == '{' && depth == 3 && inTasks {
+				taskStart = i
+			}
+		case '}', ']':
+			if c == '}' && depth == 3 && taskStart >= 0 {
+				if obj, err := lenientJSON(s[taskStart : i+1]); err == nil {
+					var task PlanTaskJSON
+					if json.Unmarshal([]byte(obj), &task) == nil {
+						tasks = append(tasks, task)
+					}
+				}
+				taskStart = -1
+			}
+			depth--
+			if inTasks && depth < 2 {
+				return tasks
+			}
+		}
+	}
+	return tasks
+}
+
+// ExtractPlanCandidates checks if the response text contains
+// <plan_candidates>...</plan_candidates> tags and returns the alternative
+// plans, each of which must be a valid final plan.
+// Returns nil, nil if no tags found.
+func ExtractPlanCandidates(text string) (*PlanCandidatesJSON, error) {
+	content, found := extractTagContent(text, "plan_candidates")
+	if !found {
+		return nil, nil
+	}
+
+	candidates, warnings, err := decodeReply[PlanCandidatesJSON](content, PlanCandidatesSchema)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range candidates.Candidates {
+		if c.Plan.ProjectName == "" {
+			return nil, fmt.Errorf("invalid plan candidates: candidate %d (%s) is missing project_name", i, c.Name)
+		}
+	}
+	candidates.Warnings = warnings
+	return &candidates, nil
+}
+
+// ExtractCriteria checks if the response text contains
+// <criteria>...</criteria> tags and returns the proposed criteria.
+// Returns nil, nil if no tags found.
+func ExtractCriteria(text string) (*CriteriaJSON, error) {
+	content, found := extractTagContent(text, "criteria")
+	if !found {
+		return nil, nil
+	}
+
+	criteria, _, err := decodeReply[CriteriaJSON](content, CriteriaSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &criteria, nil
+}
+
+// StripTag removes a <tag>...</tag> block, for showing a reply without
+// the JSON it carried.
+func StripTag(text, tag string) string {
+	openIdx := strings.Index(text, "<"+tag+">")
+	if openIdx == -1 {
+		return text
+	}
+	closeTag := "</" + tag + ">"
+	closeIdx := strings.Index(text[openIdx:], closeTag)
+	if closeIdx == -1 {
+		return text
+	}
+	return strings.TrimSpace(text[:openIdx] + text[openIdx+closeIdx+len(closeTag):])
+}
+
 // parseStreamChunk extracts displayable text from a single line of stream-json output.
 // Returns empty string if the line doesn't contain displayable text.
 // Must handle unknown/unexpected JSON structures gracefully.
@@ -1,6 +1,9 @@
 package claude
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -378,6 +381,203 @@ Let me know if you'd like any changes!`
 			t.Errorf("Tasks length = %d, want 1", len(plan.Tasks))
 		}
 	})
+
+	t.Run("plan with memory", func(t *testing.T) {
+		t.Parallel()
+		text := `<final_plan>
+{
+  "project_name": "test",
+  "tasks": [{"title": "Init project", "description": "Set up Go module", "acceptance_criteria": ["go.mod exists"]}],
+  "memory": {"decisions": ["Use SQLite"], "conventions": ["Commands live under cmd/"]}
+}
+</final_plan>`
+
+		plan, err := ExtractFinalPlan(text)
+		if err != nil {
+			t.Fatalf("ExtractFinalPlan() error: %v", err)
+		}
+		want := &MemoryJSON{Decisions: []string{"Use SQLite"}, Conventions: []string{"Commands live under cmd/"}}
+		if !reflect.DeepEqual(plan.Memory, want) {
+			t.Errorf("Memory = %+v, want %+v", plan.Memory, want)
+		}
+	})
+
+	t.Run("plan with architecture", func(t *testing.T) {
+		t.Parallel()
+		text := `<final_plan>
+{
+  "project_name": "test",
+  "tasks": [{"title": "Init project", "description": "Set up Go module", "acceptance_criteria": ["go.mod exists"]}],
+  "architecture": "## Components\n- cli: parses flags"
+}
+</final_plan>`
+
+		plan, err := ExtractFinalPlan(text)
+		if err != nil {
+			t.Fatalf("ExtractFinalPlan() error: %v", err)
+		}
+		if plan.Architecture != "## Components\n- cli: parses flags" {
+			t.Errorf("Architecture = %q", plan.Architecture)
+		}
+		if len(plan.Warnings) != 0 {
+			t.Errorf("Warnings = %v, want none", plan.Warnings)
+		}
+	})
+}
+
+func TestExtractPlanPreview(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		text      string
+		wantTasks int
+		wantErr   bool
+	}{
+		{name: "no tags", text: "What database do you want?"},
+		{
+			name:      "tasks only",
+			text:      "So far:\n<plan_preview>{\"tasks\": [{\"title\": \"Init\"}, {\"title\": \"Auth\", \"depends_on\": [0]}]}</plan_preview>\nStill open: hosting.",
+			wantTasks: 2,
+		},
+		{name: "empty", text: `<plan_preview>{"tasks": []}</plan_preview>`, wantErr: true},
+		{name: "malformed", text: `<plan_preview>{"tasks": [</plan_preview>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			plan, err := ExtractPlanPreview(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractPlanPreview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := 0; plan != nil {
+				got = len(plan.Tasks)
+				if got != tt.wantTasks {
+					t.Errorf("tasks = %d, want %d", got, tt.wantTasks)
+				}
+			} else if tt.wantTasks > 0 {
+				t.Errorf("ExtractPlanPreview() = nil, want %d tasks", tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestPartialPlanTasks(t *testing.T) {
+	t.Parallel()
+	full := `Here is the plan.
+<final_plan>
+{"project_name": "todo", "description": "tasks: {a} [b]", "tech_stack": ["go"], "tasks": [
+  {"title": "Set up", "description": "Say \"}\" and {braces}", "depends_on": []},
+  {"title": "Auth", "acceptance_criteria": ["login works",], "depends_on": [0]},
+  {"title": "API", "depends_on": [1]}
+], "memory": {"decisions": ["x"]}}
+</final_plan>`
+	tests := []struct {
+		name string
+		text string
+		want []string // task titles
+	}{
+		{name: "no plan yet", text: "Let me think about it."},
+		{name: "before the tasks", text: full[:strings.Index(full, "{\"title\"")]},
+		{name: "inside the first task", text: full[:strings.Index(full, "{braces}")]},
+		{name: "first task complete", text: full[:strings.Index(full, "{\"title\": \"Auth")], want: []string{"Set up"}},
+		{name: "repaired task", text: full[:strings.Index(full, "{\"title\": \"API")], want: []string{"Set up", "Auth"}},
+		{name: "whole plan", text: full, want: []string{"Set up", "Auth", "API"}},
+		{name: "other arrays are not tasks", text: `<final_plan>{"tech_stack": [{"title": "go"}], "tasks": [`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, task := range PartialPlanTasks(tt.text) {
+				got = append(got, task.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("titles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	final, err := ExtractFinalPlan(full)
+	if err != nil || len(final.Tasks) != 3 || final.Tasks[0].Description != `Say "}" and {braces}` {
+		t.Errorf("the streamed plan should parse in full: %+v, %v", final, err)
+	}
+}
+
+func TestExtractPlanCandidates(t *testing.T) {
+	t.Parallel()
+	plan := func(name string, tasks int) string {
+		list := make([]string, tasks)
+		for i := range list {
+			list[i] = fmt.Sprintf(`{"title": "t%d"}`, i)
+		}
+		return fmt.Sprintf(`{"project_name": %q, "tasks": [%s]}`, name, strings.Join(list, ", "))
+	}
+	tests := []struct {
+		name      string
+		text      string
+		wantTasks []int
+		wantErr   string
+	}{
+		{name: "no tags", text: "<final_plan>{}</final_plan>"},
+		{
+			name: "two candidates",
+			text: fmt.Sprintf(`Two ways:<plan_candidates>{"schema_version": 1, "candidates": [
+				{"name": "minimal", "summary": "core only", "plan": %s},
+				{"name": "thorough", "plan": %s}]}</plan_candidates>`, plan("todo", 2), plan("todo", 5)),
+			wantTasks: []int{2, 5},
+		},
+		{
+			name:    "only one",
+			text:    fmt.Sprintf(`<plan_candidates>{"candidates": [{"name": "a", "plan": %s}]}</plan_candidates>`, plan("todo", 1)),
+			wantErr: "candidates needs at least 2",
+		},
+		{
+			name: "candidate without a project name",
+			text: fmt.Sprintf(`<plan_candidates>{"candidates": [{"name": "a", "plan": %s}, {"name": "b", "plan": %s}]}</plan_candidates>`,
+				plan("todo", 1), plan("", 1)),
+			wantErr: "candidate 1 (b) is missing project_name",
+		},
+		{
+			name:    "candidate without tasks",
+			text:    fmt.Sprintf(`<plan_candidates>{"candidates": [{"name": "a", "plan": %s}, {"name": "b", "plan": %s}]}</plan_candidates>`, plan("x", 1), plan("x", 0)),
+			wantErr: "candidates[1].plan.tasks needs at least 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ExtractPlanCandidates(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tasks []int
+			if got != nil {
+				for _, c := range got.Candidates {
+					tasks = append(tasks, len(c.Plan.Tasks))
+				}
+			}
+			if !reflect.DeepEqual(tasks, tt.wantTasks) {
+				t.Errorf("task counts = %v, want %v", tasks, tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestStripTag(t *testing.T) {
+	t.Parallel()
+	got := StripTag("So far:\n<plan_preview>{}</plan_preview>\nStill open: hosting.", "plan_preview")
+	if want := "So far:\n\nStill open: hosting."; got != want {
+		t.Errorf("StripTag() = %q, want %q", got, want)
+	}
+	if got := StripTag("no tags <plan_preview> here", "plan_preview"); got != "no tags <plan_preview> here" {
+		t.Errorf("StripTag() without a closing tag = %q", got)
+	}
 }
 
 func TestExtractPlanUpdate(t *testing.T) {
@@ -604,3 +804,34 @@ func TestParseResponse(t *testing.T) {
 		}
 	})
 }
+
+func TestExtractCritique(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		text      string
+		want      []string
+		wantFound bool
+	}{
+		{name: "no tags", text: "The plan looks fine.", wantFound: false},
+		{name: "empty critique", text: "<critique></critique>", wantFound: true},
+		{
+			name:      "findings",
+			text:      "Review:\n<critique>\n- No task writes integration tests.\n\n* \"Deploy\" runs before \"Add config\".\n</critique>",
+			want:      []string{"No task writes integration tests.", "\"Deploy\" runs before \"Add config\"."},
+			wantFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, found := ExtractCritique(tt.text)
+			if found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", found, tt.wantFound)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
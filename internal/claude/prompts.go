@@ -1,7 +1,17 @@
 package claude
 
-// InitialPlanningPrompt is the system prompt for the first planning session.
-const InitialPlanningPrompt = `You are an expert software project planner helping the user define their project through conversation.
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manasm11/forge/internal/scanner"
+)
+
+// InitialPlanningPrompt is the system prompt for the first planning session,
+// ending with the schema the plan must follow.
+var InitialPlanningPrompt = initialPlanningRules + FinalPlanSchema.PromptSection()
+
+const initialPlanningRules = `You are an expert software project planner helping the user define their project through conversation.
 
 RULES:
 - Ask focused questions, maximum 3 at a time
@@ -13,9 +23,23 @@ RULES:
 - Order tasks by dependency (foundational tasks first)
 - Each task must have clear, testable acceptance criteria
 - Include project setup/scaffolding as the first task
+- Task "type" is "code" (default, implemented by an AI agent), "verify" (only runs
+  "commands", e.g. an integration test suite) or "manual" (a human must do it, e.g.
+  rotating an API key; the description holds the instructions)
+- A task may list "artifacts": globs of files worth keeping after it succeeds
+  (coverage reports, built binaries, generated docs)
+- In a workspace of several repositories, "repo" names the one a task works in;
+  split work touching two repos into one task per repo
+- "memory" keeps what later plans in this repository must know: key decisions,
+  architectural constraints and naming conventions agreed in this conversation.
+  Only list new entries, not ones already in the project memory
+- "architecture" is a short Markdown document every coding task will read:
+  the components and what each owns, how data flows between them, and the
+  major decisions with their reasons. Keep it to what a task needs to fit in
 
 OUTPUT FORMAT (inside <final_plan> tags):
 {
+  "schema_version": 1,
   "project_name": "string",
   "description": "string",
   "tech_stack": ["string"],
@@ -25,15 +49,27 @@ OUTPUT FORMAT (inside <final_plan> tags):
       "description": "detailed description of what to implement",
       "acceptance_criteria": ["specific, testable criterion"],
       "depends_on": [0, 1],
-      "estimated_complexity": "small|medium|large"
+      "estimated_complexity": "small|medium|large",
+      "type": "code|verify|manual",
+      "commands": ["only for verify tasks"],
+      "artifacts": ["optional globs, e.g. coverage.out"],
+      "repo": "workspace repository path, if any"
     }
-  ]
+  ],
+  "memory": {
+    "decisions": ["optional, e.g. PostgreSQL over SQLite for concurrent writers"],
+    "constraints": ["optional, e.g. handlers never touch the database directly"],
+    "conventions": ["optional, e.g. errors wrap with fmt.Errorf(\"doing x: %w\", err)"]
+  },
+  "architecture": "## Components\n- ...\n\n## Data flow\n...\n\n## Decisions\n- ..."
 }`
 
 // ReplanningPrompt is the system prompt used when the user returns to planning
 // to revise requirements. It includes the current task state via %s placeholder.
 // The caller uses fmt.Sprintf to inject GenerateReplanContext() output.
-const ReplanningPrompt = `You are an expert software project planner. The user is revising their project plan.
+var ReplanningPrompt = replanningRules + PlanUpdateSchema.PromptSection()
+
+const replanningRules = `You are an expert software project planner. The user is revising their project plan.
 
 %s
 
@@ -44,27 +80,173 @@ RULES:
 - For failed tasks, you can suggest redesigned replacements as new tasks
 - Ask clarifying questions if the changes are ambiguous
 - Keep tasks small and atomic
+- Tasks may set "type" to "verify" (runs "commands" only) or "manual" (done by a human)
+- Tasks may list "artifacts" (globs of files to keep after success, e.g. coverage reports)
+- In a workspace of several repositories, tasks may set "repo" to the one they work in
+- Record new decisions, constraints and conventions agreed in this conversation in "memory"
+- If the changes affect the components, data flow or major decisions, put the full
+  revised architecture document (Markdown) in "architecture"; otherwise leave it out
 - When the user confirms changes, output the updated plan inside <plan_update> tags
 
 OUTPUT FORMAT (inside <plan_update> tags):
 {
+  "schema_version": 1,
   "summary": "brief description of what changed in this revision",
   "tasks": [
     {"id": "task-001", "action": "keep"},
     {"id": "task-002", "action": "modify", "title": "...", "description": "...", "acceptance_criteria": ["..."], "depends_on": ["task-001"], "estimated_complexity": "small|medium|large"},
-    {"action": "add", "title": "...", "description": "...", "acceptance_criteria": ["..."], "depends_on": ["task-001"], "estimated_complexity": "small|medium|large"},
+    {"id": "new-1", "action": "add", "title": "...", "description": "...", "acceptance_criteria": ["..."], "depends_on": ["task-001"], "estimated_complexity": "small|medium|large"},
+    {"action": "add", "title": "...", "description": "...", "acceptance_criteria": ["..."], "depends_on": ["new-1"], "estimated_complexity": "small|medium|large"},
     {"id": "task-003", "action": "remove", "reason": "why this task is no longer needed"}
-  ]
+  ],
+  "memory": {"decisions": ["..."], "constraints": ["..."], "conventions": ["..."]},
+  "architecture": "optional: the full revised architecture document"
 }
 
 ACTIONS:
 - "keep" — task stays exactly as is (use for completed tasks and unchanged pending tasks)
 - "modify" — update a pending task's details (must include id and updated fields)
-- "add" — create a new task (no id needed — forge assigns one automatically)
+- "add" — create a new task (forge assigns its ID; give it a temporary id such as "new-1" only when other tasks depend on it)
 - "remove" — cancel a pending task (must include id and reason)
 
 IMPORTANT:
 - Every existing non-cancelled task must appear in the update with an action
-- New tasks use "add" without an id
-- Dependencies use task IDs (e.g., "task-001"), not indices
+- New tasks use "add", with a temporary id ("new-1", "new-2", ...) only if other tasks depend on them
+- Dependencies use task IDs (e.g., "task-001") or the temporary id of a task added in this update, not indices
 - Only reference task IDs that exist or that you're adding in this update`
+
+// FinalPlanInstruction asks for the plan once the conversation is over
+// (the /done command, or the end of a headless session).
+const FinalPlanInstruction = "The user has requested the final plan. Based on everything discussed, generate the plan now. Output inside <final_plan> tags with the JSON format specified."
+
+// JSONFixInstruction asks the model to resend the <tag> block whose JSON
+// could not be used, quoting the error. Forge sends it once, automatically,
+// before showing the error to the user.
+func JSONFixInstruction(tag string, err error) string {
+	return fmt.Sprintf("The JSON inside your <%s> tags could not be used: %v. "+
+		"Reply with the corrected <%s>...</%s> block only: valid JSON (double-quoted strings, \\n for line breaks inside strings, "+
+		"no trailing commas, no comments) with every required field, and the same content otherwise.", tag, err, tag, tag)
+}
+
+// PlanRefsFixInstruction asks the model to resend a <plan_update> whose
+// dependencies could not be resolved to tasks, listing each problem.
+func PlanRefsFixInstruction(problems []string) string {
+	return "Some task references in your <plan_update> could not be resolved:\n- " + strings.Join(problems, "\n- ") +
+		"\nReply with the corrected <plan_update>...</plan_update> block only. In depends_on, use existing task IDs exactly as listed " +
+		"(e.g. \"task-003\") and, for tasks added in this update, the temporary id you gave them (e.g. \"new-1\"); never indices or titles."
+}
+
+// PlanPreviewInstruction asks for a provisional task list mid-conversation
+// (the /preview command). The conversation carries on afterwards.
+const PlanPreviewInstruction = "Show me a provisional task list based on what we've discussed so far. This is a preview, not the final plan: " +
+	"don't end the conversation. Output it inside <plan_preview> tags as JSON with a \"tasks\" array in the same task format as the final plan " +
+	"(title, description, estimated_complexity, depends_on as indices into this list), then name the open questions that could still change it."
+
+// PlanCandidatesInstruction asks for alternative plans to compare side by
+// side (the /alternatives command).
+var PlanCandidatesInstruction = "Based on everything discussed, propose 2 alternative plans instead of one, for example a minimal plan " +
+	"that ships the core quickly and a thorough one with tests, hardening and polish. Make them genuinely different in scope or approach. " +
+	"Output them inside <plan_candidates> tags as JSON: {\"schema_version\": 1, \"candidates\": [{\"name\": \"minimal\", " +
+	"\"summary\": \"the approach and what it leaves out\", \"plan\": {a complete final plan}}, ...]}. The user will pick one or ask you to merge them." +
+	PlanCandidatesSchema.PromptSection()
+
+// PlanMergeInstruction asks for one final plan combining the candidates
+// of the last <plan_candidates> reply.
+const PlanMergeInstruction = "Merge your candidate plans into a single final plan that keeps the strongest parts of each " +
+	"(the core of the smaller plan, plus the tests and hardening from the larger one that are worth their cost). " +
+	"Output inside <final_plan> tags with the JSON format specified."
+
+// CritiquePrompt asks a second model to review a finished plan. The plan
+// JSON is injected via fmt.Sprintf.
+const CritiquePrompt = `You are a senior engineer reviewing a project plan written by another planner before any work starts.
+
+PLAN:
+%s
+
+Look for:
+- Missing steps (setup, migrations, configuration, error handling, documentation the other tasks rely on)
+- Risky orderings (a task that needs something a later task builds, or dependencies that are missing)
+- Absent testing (features with no tests in their acceptance criteria, no integration or end-to-end verification)
+- Tasks too large for a single coding session
+
+Do not rewrite the plan. Output your findings inside <critique> tags, one per line,
+each starting with "- " and at most two sentences, most important first. Reference
+tasks by title. If the plan has no real problems, output empty <critique></critique> tags.`
+
+// CriteriaPrompt asks the model to tighten vague acceptance criteria of
+// pending tasks. The tasks JSON is injected via fmt.Sprintf.
+const CriteriaPrompt = `You are reviewing the acceptance criteria of a project's pending tasks before a coding agent implements them.
+
+TASKS:
+%s
+
+Rewrite criteria that are vague ("works well", "code is clean", "handles errors properly") as verifiable statements.
+Each criterion must name observable behavior (an HTTP status, command output, a file, a UI state) or a command
+that passes (e.g. "go test ./internal/auth/... passes"). Keep criteria that are already verifiable as they are,
+keep each task's scope, and don't add requirements the task didn't have.
+
+Output inside <criteria> tags as JSON: {"schema_version": 1, "tasks": [{"id": "task-003", "acceptance_criteria": ["..."]}]},
+with the full new list for each task you change and only those tasks. If nothing needs tightening, output {"tasks": []}.`
+
+// ProjectContext renders the existing-project section appended to
+// InitialPlanningPrompt. Returns "" for new projects.
+func ProjectContext(snap *scanner.ProjectSnapshot) string {
+	if snap == nil || !snap.IsExisting {
+		return ""
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("\n\nEXISTING PROJECT CONTEXT:\n")
+	if snap.Language != "" {
+		fmt.Fprintf(&prompt, "Language: %s\n", snap.Language)
+	}
+	if len(snap.Frameworks) > 0 {
+		fmt.Fprintf(&prompt, "Frameworks: %s\n", strings.Join(snap.Frameworks, ", "))
+	}
+	if len(snap.Dependencies) > 0 {
+		fmt.Fprintf(&prompt, "Dependencies: %s\n", strings.Join(snap.Dependencies, ", "))
+	}
+	if len(snap.TestFrameworks) > 0 {
+		fmt.Fprintf(&prompt, "Test Frameworks: %s\n", strings.Join(snap.TestFrameworks, ", "))
+	}
+	if len(snap.CISystems) > 0 {
+		fmt.Fprintf(&prompt, "CI Systems: %s\n", strings.Join(snap.CISystems, ", "))
+	}
+	if len(snap.Services) > 0 {
+		fmt.Fprintf(&prompt, "Docker Compose Services: %s\n", strings.Join(snap.Services, ", "))
+	}
+	if snap.Structure != "" {
+		fmt.Fprintf(&prompt, "Project Structure:\n%s\n", snap.Structure)
+	}
+	if len(snap.KeyFiles) > 0 {
+		fmt.Fprintf(&prompt, "Key Files: %s\n", strings.Join(snap.KeyFiles, ", "))
+	}
+	if len(snap.APISchemas) > 0 {
+		fmt.Fprintf(&prompt, "API Schemas (plan against these contracts):\n%s", scanner.FormatAPISchemas(snap.APISchemas))
+	}
+	if len(snap.Todos) > 0 {
+		fmt.Fprintf(&prompt, "TODO/FIXME/HACK Comments (%d found — early in the conversation, tell the user how many you found and ask whether to include any as tasks; cite file:line in tasks that address them):\n%s",
+			len(snap.Todos), scanner.FormatTodos(snap.Todos))
+	}
+	if len(snap.Repos) > 0 {
+		fmt.Fprintf(&prompt, "Workspace Repositories (separate git repos; set each task's \"repo\" to the one it changes, or leave it empty for the top-level project): %s\n",
+			strings.Join(snap.Repos, ", "))
+	}
+	if readOnly := snap.ReadOnlyPaths(); len(readOnly) > 0 {
+		fmt.Fprintf(&prompt, "Submodules and Vendored Code (never plan edits inside these; changes belong upstream): %s\n",
+			strings.Join(readOnly, ", "))
+	}
+	if len(snap.RecentCommits) > 0 {
+		prompt.WriteString("Recent Git History:\n")
+		for _, c := range snap.RecentCommits {
+			fmt.Fprintf(&prompt, "  %s\n", c)
+		}
+	}
+	if snap.ReadmeContent != "" {
+		fmt.Fprintf(&prompt, "README Summary:\n%s\n", snap.ReadmeContent)
+	}
+	if snap.ClaudeMD != "" {
+		fmt.Fprintf(&prompt, "CLAUDE.md:\n%s\n", snap.ClaudeMD)
+	}
+	return prompt.String()
+}
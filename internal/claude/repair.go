@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// lenientJSON returns JSON a model wrote, repaired with RepairJSON when it
+// doesn't parse as is. The error is the one for the original text, since
+// that is what the model has to fix.
+func lenientJSON(content string) (string, error) {
+	var v any
+	err := json.Unmarshal([]byte(content), &v)
+	if err == nil {
+		return content, nil
+	}
+	if repaired := RepairJSON(content); json.Valid([]byte(repaired)) {
+		return repaired, nil
+	}
+	return content, err
+}
+
+// RepairJSON fixes the mistakes models commonly make in JSON: a code fence
+// or prose around the value, trailing commas, and raw newlines, tabs or
+// other control characters inside strings. Anything else is left for the
+// parser to report.
+func RepairJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if start := strings.IndexAny(s, "{["); start > 0 {
+		s = s[start:]
+	}
+	if end := strings.LastIndexAny(s, "}]"); end >= 0 {
+		s = s[:end+1]
+	}
+
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			case c == '\n':
+				b.WriteString(`\n`)
+				continue
+			case c == '\r':
+				b.WriteString(`\r`)
+				continue
+			case c == '\t':
+				b.WriteString(`\t`)
+				continue
+			case c < 0x20:
+				fmt.Fprintf(&b, `\u%04x`, c)
+				continue
+			}
+			b.WriteByte(c)
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			// Drop a comma that only whitespace separates from a closing bracket
+			rest := strings.TrimLeft(s[i+1:], " \t\r\n")
+			if rest != "" && (rest[0] == '}' || rest[0] == ']') {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRepairJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "valid JSON is unchanged", in: `{"a": [1, 2], "b": "x, ]"}`, want: `{"a": [1, 2], "b": "x, ]"}`},
+		{name: "trailing commas", in: "{\"a\": [1, 2,\n], \"b\": {\"c\": 1,},\n}", want: "{\"a\": [1, 2\n], \"b\": {\"c\": 1}\n}"},
+		{name: "raw newline and tab in a string", in: "{\"d\": \"line one\nline\ttwo\"}", want: `{"d": "line one\nline\ttwo"}`},
+		{name: "escaped quote keeps the string open", in: "{\"d\": \"say \\\"hi\\\",\n}\"}", want: `{"d": "say \"hi\",\n}"}`},
+		{name: "other control characters", in: "{\"d\": \"bell\a\"}", want: `{"d": "bell\u0007"}`},
+		{name: "code fence and prose", in: "Here it is:\n```json\n{\"a\": 1,}\n```\nDone.", want: `{"a": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := RepairJSON(tt.in)
+			if got != tt.want {
+				t.Errorf("RepairJSON(%q)\n got  %q\n want %q", tt.in, got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("repaired JSON is not valid: %q", got)
+			}
+		})
+	}
+}
+
+func TestExtractFinalPlan_Repairs(t *testing.T) {
+	t.Parallel()
+	text := "<final_plan>\n```json\n{\n  \"project_name\": \"todo\",\n  \"tasks\": [\n    {\"title\": \"Set up\", \"description\": \"Create the module.\nAdd a Makefile.\", \"estimated_complexity\": \"small\",},\n  ],\n}\n```\n</final_plan>"
+	plan, err := ExtractFinalPlan(text)
+	if err != nil {
+		t.Fatalf("ExtractFinalPlan: %v", err)
+	}
+	if plan.ProjectName != "todo" || len(plan.Tasks) != 1 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if got := plan.Tasks[0].Description; got != "Create the module.\nAdd a Makefile." {
+		t.Errorf("description = %q", got)
+	}
+
+	update, err := ExtractPlanUpdate("<plan_update>{\"summary\": \"s\", \"tasks\": [{\"action\": \"add\", \"title\": \"t\",},]}</plan_update>")
+	if err != nil || update == nil || len(update.Tasks) != 1 {
+		t.Errorf("ExtractPlanUpdate = %+v, %v", update, err)
+	}
+
+	// Unrepairable JSON reports the error for what the model wrote
+	_, err = ExtractFinalPlan(`<final_plan>{"project_name": "todo" "tasks": []}</final_plan>`)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("error = %v, want a JSON syntax error", err)
+	}
+}
+
+func TestJSONFixInstruction(t *testing.T) {
+	t.Parallel()
+	got := JSONFixInstruction("final_plan", errors.New("invalid plan: missing project_name"))
+	for _, want := range []string{"<final_plan>...</final_plan>", "missing project_name", "no trailing commas"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("instruction missing %q:\n%s", want, got)
+		}
+	}
+}
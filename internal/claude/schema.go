@@ -0,0 +1,279 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// PlanSchemaVersion is the version of the plan formats this forge reads
+// and asks for. Bump it, and keep reading the old version, when a change
+// would make older replies mean something else.
+const PlanSchemaVersion = 1
+
+// Field describes one JSON value of a plan format.
+type Field struct {
+	Name     string // property name; "" for array items
+	Type     string // "string", "integer", "object" or "array"
+	Desc     string // shown to the model in the schema
+	Required bool   // the property must be present
+	Enum     []string
+	MinItems int     // arrays only
+	Items    *Field  // arrays only
+	Fields   []Field // objects only
+}
+
+// Schema is a versioned plan format: the JSON the model puts inside <Tag>
+// tags. It is shown in the prompts and checked on every reply, so a model
+// or provider that drifts from it is caught instead of silently losing
+// fields.
+type Schema struct {
+	Tag     string
+	Version int
+	Root    Field
+}
+
+var (
+	memoryField = Field{Name: "memory", Type: "object", Desc: "what later plans in this repository must know", Fields: []Field{
+		{Name: "decisions", Type: "array", Items: &Field{Type: "string"}},
+		{Name: "constraints", Type: "array", Items: &Field{Type: "string"}},
+		{Name: "conventions", Type: "array", Items: &Field{Type: "string"}},
+	}}
+	architectureField = Field{Name: "architecture", Type: "string",
+		Desc: "Markdown architecture document: components, data flow, major decisions"}
+	complexityEnum = []string{"small", "medium", "large"}
+	taskTypeEnum   = []string{"code", "verify", "manual"}
+)
+
+// planTaskFields are the fields every task format shares; depends_on
+// differs between plans (indices) and updates (IDs).
+func planTaskFields(dependsOn Field, requireTitle bool) []Field {
+	return []Field{
+		{Name: "title", Type: "string", Required: requireTitle, Desc: "short action-oriented title"},
+		{Name: "description", Type: "string", Desc: "what to implement"},
+		{Name: "acceptance_criteria", Type: "array", Items: &Field{Type: "string"}},
+		dependsOn,
+		{Name: "estimated_complexity", Type: "string", Enum: complexityEnum},
+		{Name: "type", Type: "string", Enum: taskTypeEnum, Desc: "code (default), verify or manual"},
+		{Name: "commands", Type: "array", Items: &Field{Type: "string"}, Desc: "verify tasks only"},
+		{Name: "artifacts", Type: "array", Items: &Field{Type: "string"}, Desc: "globs of files to keep"},
+		{Name: "repo", Type: "string", Desc: "workspace repository the task works in"},
+	}
+}
+
+func planSchema(tag string, requireProject bool) Schema {
+	task := Field{Type: "object", Fields: planTaskFields(Field{Name: "depends_on", Type: "array",
+		Items: &Field{Type: "integer"}, Desc: "indices of earlier tasks in this list"}, true)}
+	return Schema{Tag: tag, Version: PlanSchemaVersion, Root: Field{Type: "object", Fields: []Field{
+		{Name: "schema_version", Type: "integer", Desc: fmt.Sprintf("always %d", PlanSchemaVersion)},
+		{Name: "project_name", Type: "string", Required: requireProject},
+		{Name: "description", Type: "string"},
+		{Name: "tech_stack", Type: "array", Items: &Field{Type: "string"}},
+		{Name: "tasks", Type: "array", Required: true, MinItems: 1, Items: &task},
+		memoryField,
+		architectureField,
+	}}}
+}
+
+// FinalPlanSchema is the format of <final_plan>.
+var FinalPlanSchema = planSchema("final_plan", true)
+
+// PlanPreviewSchema is the format of <plan_preview>: a final plan in which
+// only the tasks are required.
+var PlanPreviewSchema = planSchema("plan_preview", false)
+
+// PlanUpdateSchema is the format of <plan_update>.
+var PlanUpdateSchema = func() Schema {
+	fields := append([]Field{
+		{Name: "id", Type: "string", Desc: "existing task ID; for add, an optional temporary ID (new-1) other tasks can depend on"},
+		{Name: "action", Type: "string", Required: true, Enum: []string{"keep", "modify", "add", "remove"}},
+		{Name: "reason", Type: "string", Desc: "why a task is removed"},
+	}, planTaskFields(Field{Name: "depends_on", Type: "array", Items: &Field{Type: "string"}, Desc: "task IDs"}, false)...)
+	return Schema{Tag: "plan_update", Version: PlanSchemaVersion, Root: Field{Type: "object", Fields: []Field{
+		{Name: "schema_version", Type: "integer", Desc: fmt.Sprintf("always %d", PlanSchemaVersion)},
+		{Name: "summary", Type: "string", Desc: "what changed in this revision"},
+		{Name: "tasks", Type: "array", Required: true, Items: &Field{Type: "object", Fields: fields}},
+		memoryField,
+		{Name: "architecture", Type: "string", Desc: "the full revised architecture document, only if it changes"},
+	}}}
+}()
+
+// PlanCandidatesSchema is the format of <plan_candidates>: alternative
+// final plans for the user to compare and choose from.
+var PlanCandidatesSchema = func() Schema {
+	plan := FinalPlanSchema.Root
+	plan.Name, plan.Required, plan.Desc = "plan", true, "a complete final plan"
+	candidate := Field{Type: "object", Fields: []Field{
+		{Name: "name", Type: "string", Required: true, Desc: "short label, e.g. minimal or thorough"},
+		{Name: "summary", Type: "string", Desc: "the approach and its trade-offs"},
+		plan,
+	}}
+	return Schema{Tag: "plan_candidates", Version: PlanSchemaVersion, Root: Field{Type: "object", Fields: []Field{
+		{Name: "schema_version", Type: "integer", Desc: fmt.Sprintf("always %d", PlanSchemaVersion)},
+		{Name: "candidates", Type: "array", Required: true, MinItems: 2, Items: &candidate},
+	}}}
+}()
+
+// CriteriaSchema is the format of <criteria>: tightened acceptance
+// criteria for some of the pending tasks.
+var CriteriaSchema = Schema{Tag: "criteria", Version: PlanSchemaVersion, Root: Field{Type: "object", Fields: []Field{
+	{Name: "schema_version", Type: "integer", Desc: fmt.Sprintf("always %d", PlanSchemaVersion)},
+	{Name: "tasks", Type: "array", Required: true, Items: &Field{Type: "object", Fields: []Field{
+		{Name: "id", Type: "string", Required: true, Desc: "task ID"},
+		{Name: "acceptance_criteria", Type: "array", Required: true, MinItems: 1, Items: &Field{Type: "string"},
+			Desc: "the task's full new list"},
+	}}},
+}}}
+
+// JSONSchema renders the schema as JSON Schema.
+func (s Schema) JSONSchema() string {
+	doc := s.Root.jsonSchema()
+	doc["$id"] = fmt.Sprintf("forge/%s/v%d", s.Tag, s.Version)
+	data, _ := json.MarshalIndent(doc, "", "  ")
+	return string(data)
+}
+
+func (f Field) jsonSchema() map[string]any {
+	out := map[string]any{"type": f.Type}
+	if f.Desc != "" {
+		out["description"] = f.Desc
+	}
+	if len(f.Enum) > 0 {
+		out["enum"] = f.Enum
+	}
+	if f.MinItems > 0 {
+		out["minItems"] = f.MinItems
+	}
+	if f.Items != nil {
+		out["items"] = f.Items.jsonSchema()
+	}
+	if f.Type == "object" {
+		props := map[string]any{}
+		var required []string
+		for _, p := range f.Fields {
+			props[p.Name] = p.jsonSchema()
+			if p.Required {
+				required = append(required, p.Name)
+			}
+		}
+		out["properties"] = props
+		if len(required) > 0 {
+			out["required"] = required
+		}
+	}
+	return out
+}
+
+// PromptSection is the schema as shown in the prompts.
+func (s Schema) PromptSection() string {
+	return fmt.Sprintf("\n\nSCHEMA: the JSON inside <%s> tags must validate against this JSON Schema (schema_version %d):\n%s",
+		s.Tag, s.Version, s.JSONSchema())
+}
+
+// Validate checks a decoded reply against the schema. Problems that would
+// lose or misread data are errors; properties the schema doesn't know are
+// only warnings, since they are ignored.
+func (s Schema) Validate(v any) (errs, warnings []string) {
+	if obj, ok := v.(map[string]any); ok {
+		if n, ok := obj["schema_version"].(float64); ok && int(n) != s.Version {
+			return []string{fmt.Sprintf("schema_version %v is not supported (this forge reads version %d)", n, s.Version)}, nil
+		}
+	}
+	s.Root.validate("", v, &errs, &warnings)
+	return errs, warnings
+}
+
+func (f Field) validate(path string, v any, errs, warnings *[]string) {
+	where := path
+	if where == "" {
+		where = "the reply"
+	}
+	switch f.Type {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s must be a string", where))
+			return
+		}
+		if str != "" && len(f.Enum) > 0 && !slices.Contains(f.Enum, str) {
+			*errs = append(*errs, fmt.Sprintf("%s: %q is not one of %s", where, str, strings.Join(f.Enum, ", ")))
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			*errs = append(*errs, fmt.Sprintf("%s must be an integer", where))
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s must be an array", where))
+			return
+		}
+		if len(items) < f.MinItems {
+			*errs = append(*errs, fmt.Sprintf("%s needs at least %d item(s)", where, f.MinItems))
+		}
+		for i, item := range items {
+			f.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs, warnings)
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s must be an object", where))
+			return
+		}
+		for _, p := range f.Fields {
+			pv, present := obj[p.Name]
+			switch {
+			case !present && p.Required:
+				*errs = append(*errs, fmt.Sprintf("%s is required", join(path, p.Name)))
+			case present && pv != nil:
+				p.validate(join(path, p.Name), pv, errs, warnings)
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			if !slices.ContainsFunc(f.Fields, func(p Field) bool { return p.Name == name }) {
+				*warnings = append(*warnings, fmt.Sprintf("unknown field %s was ignored", join(path, name)))
+			}
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// decodeReply decodes the JSON inside a <tag> block: repaired if need be,
+// checked against the schema, then unmarshaled into T. Warnings are the
+// schema's.
+func decodeReply[T any](content string, s Schema) (T, []string, error) {
+	var v T
+	text, err := lenientJSON(content)
+	if err != nil {
+		return v, nil, fmt.Errorf("invalid JSON in <%s>: %w", s.Tag, err)
+	}
+	var raw any
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return v, nil, fmt.Errorf("invalid JSON in <%s>: %w", s.Tag, err)
+	}
+	errs, warnings := s.Validate(raw)
+	if len(errs) > 0 {
+		return v, warnings, fmt.Errorf("<%s> does not match schema version %d: %s", s.Tag, s.Version, strings.Join(errs, "; "))
+	}
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return v, warnings, fmt.Errorf("invalid JSON in <%s>: %w", s.Tag, err)
+	}
+	return v, warnings, nil
+}
@@ -0,0 +1,146 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		schema       Schema
+		json         string
+		wantErrs     []string
+		wantWarnings []string
+	}{
+		{
+			name:   "valid plan",
+			schema: FinalPlanSchema,
+			json: `{"schema_version": 1, "project_name": "p", "tasks": [{"title": "t", "depends_on": [0],
+				"estimated_complexity": "small", "type": "verify", "commands": ["make"]}], "memory": {"decisions": ["d"]}}`,
+		},
+		{
+			name:   "no schema_version is read as the current one",
+			schema: FinalPlanSchema,
+			json:   `{"project_name": "p", "tasks": [{"title": "t"}]}`,
+		},
+		{
+			name:     "newer schema_version",
+			schema:   FinalPlanSchema,
+			json:     `{"schema_version": 2, "project_name": "p", "tasks": [{"title": "t"}]}`,
+			wantErrs: []string{"schema_version 2 is not supported (this forge reads version 1)"},
+		},
+		{
+			name:   "wrong types, enums and missing fields",
+			schema: FinalPlanSchema,
+			json: `{"project_name": 3, "tasks": [{"description": "d", "depends_on": ["task-001"],
+				"estimated_complexity": "huge", "acceptance_criteria": "one"}]}`,
+			wantErrs: []string{
+				"project_name must be a string",
+				"tasks[0].title is required",
+				"tasks[0].acceptance_criteria must be an array",
+				"tasks[0].depends_on[0] must be an integer",
+				`tasks[0].estimated_complexity: "huge" is not one of small, medium, large`,
+			},
+		},
+		{
+			name:         "unknown fields only warn",
+			schema:       FinalPlanSchema,
+			json:         `{"project_name": "p", "tasks": [{"title": "t", "complexity": "small"}], "notes": null}`,
+			wantWarnings: []string{"unknown field tasks[0].complexity was ignored", "unknown field notes was ignored"},
+		},
+		{
+			name:     "empty task list",
+			schema:   FinalPlanSchema,
+			json:     `{"project_name": "p", "tasks": []}`,
+			wantErrs: []string{"tasks needs at least 1 item(s)"},
+		},
+		{
+			name:   "preview needs no project name",
+			schema: PlanPreviewSchema,
+			json:   `{"tasks": [{"title": "t", "depends_on": [0]}]}`,
+		},
+		{
+			name:   "update",
+			schema: PlanUpdateSchema,
+			json: `{"schema_version": 1, "summary": "s", "tasks": [{"id": "task-001", "action": "keep"},
+				{"action": "add", "title": "t", "depends_on": ["task-001"]}, {"id": "task-002", "action": "drop"}]}`,
+			wantErrs: []string{`tasks[2].action: "drop" is not one of keep, modify, add, remove`},
+		},
+		{
+			name:     "not an object",
+			schema:   PlanUpdateSchema,
+			json:     `[1]`,
+			wantErrs: []string{"the reply must be an object"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var v any
+			if err := json.Unmarshal([]byte(tt.json), &v); err != nil {
+				t.Fatal(err)
+			}
+			errs, warnings := tt.schema.Validate(v)
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("errors:\n got  %q\n want %q", errs, tt.wantErrs)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("warnings:\n got  %q\n want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestSchema_JSONSchema(t *testing.T) {
+	t.Parallel()
+	for _, s := range []Schema{FinalPlanSchema, PlanPreviewSchema, PlanUpdateSchema} {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(s.JSONSchema()), &doc); err != nil {
+			t.Fatalf("%s: schema is not JSON: %v", s.Tag, err)
+		}
+		if doc["$id"] != fmt.Sprintf("forge/%s/v%d", s.Tag, PlanSchemaVersion) || doc["type"] != "object" {
+			t.Errorf("%s: $id = %v, type = %v", s.Tag, doc["$id"], doc["type"])
+		}
+	}
+	tasks := FinalPlanSchema.Root.jsonSchema()["properties"].(map[string]any)["tasks"].(map[string]any)
+	if req := tasks["items"].(map[string]any)["required"]; !reflect.DeepEqual(req, []string{"title"}) {
+		t.Errorf("task required = %v, want [title]", req)
+	}
+}
+
+func TestPrompts_IncludeSchema(t *testing.T) {
+	t.Parallel()
+	version := fmt.Sprintf(`"schema_version": %d`, PlanSchemaVersion)
+	for name, prompt := range map[string]string{"initial": InitialPlanningPrompt, "replanning": ReplanningPrompt} {
+		if !strings.Contains(prompt, version) {
+			t.Errorf("%s prompt example lacks %s", name, version)
+		}
+		if !strings.Contains(prompt, "SCHEMA: ") || !strings.Contains(prompt, `"$id"`) {
+			t.Errorf("%s prompt lacks the JSON Schema", name)
+		}
+	}
+	if got := fmt.Sprintf(ReplanningPrompt, "CTX"); strings.Contains(got, "%!") {
+		t.Error("the schema breaks ReplanningPrompt's format string")
+	}
+}
+
+func TestExtractPlanUpdate_SchemaWarnings(t *testing.T) {
+	t.Parallel()
+	update, err := ExtractPlanUpdate(`<plan_update>{"summary": "s", "tasks": [{"id": "task-001", "action": "keep", "why": "x"}]}</plan_update>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"unknown field tasks[0].why was ignored"}; !reflect.DeepEqual(update.Warnings, want) {
+		t.Errorf("Warnings = %q, want %q", update.Warnings, want)
+	}
+
+	_, err = ExtractPlanUpdate(`<plan_update>{"schema_version": 9, "tasks": []}</plan_update>`)
+	if err == nil || !strings.Contains(err.Error(), "schema_version 9") {
+		t.Errorf("error = %v, want an unsupported schema_version", err)
+	}
+}
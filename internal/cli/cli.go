@@ -0,0 +1,161 @@
+// Package cli is forge's small command framework: a tree of commands with
+// standard-library flag sets, generated --help text and shell completions.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ArgKind says what a command's positional arguments are, so completions
+// can offer them.
+type ArgKind int
+
+const (
+	ArgNone   ArgKind = iota
+	ArgTaskID         // IDs from `forge task list --ids`
+	ArgFile           // file names
+)
+
+// Command is one node of the command tree. The root is forge itself.
+type Command struct {
+	Name     string
+	Synopsis string                 // what follows the name in usage, e.g. "[-o FILE] [TASK-ID]"
+	Summary  string                 // one line for command lists and the top of --help
+	Flags    func(fs *flag.FlagSet) // registers the command's flags (nil = none)
+	Args     ArgKind                // positional arguments, for completion
+	Raw      bool                   // the command parses its own flags; Parse only catches --help
+	Commands []*Command
+}
+
+// Invocation is a parsed command line.
+type Invocation struct {
+	Path    string   // subcommand names joined by spaces; "" for the root
+	Command *Command // the command found
+	Args    []string // positional arguments, or every remaining argument of a Raw command
+}
+
+// Parse finds the command named by the leading words of args and parses
+// its flags. -h, -help and --help return the invocation with flag.ErrHelp.
+func (c *Command) Parse(args []string) (Invocation, error) {
+	inv := Invocation{Command: c}
+	for len(args) > 0 && len(inv.Command.Commands) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub := inv.Command.find(args[0])
+		if sub == nil {
+			return inv, fmt.Errorf("unknown command %q (run `%s` for the list)", joinPath(inv.Path, args[0]), helpHint(c.Name, inv.Path))
+		}
+		inv.Command, inv.Path, args = sub, joinPath(inv.Path, sub.Name), args[1:]
+	}
+
+	if inv.Command.Raw {
+		for _, a := range args {
+			if a == "-h" || a == "-help" || a == "--help" {
+				return inv, flag.ErrHelp
+			}
+		}
+		inv.Args = args
+		return inv, nil
+	}
+
+	fs := inv.Command.flagSet(c.Name, inv.Path)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return inv, err
+		}
+		return inv, fmt.Errorf("%s: %w (see `%s`)", fs.Name(), err, helpHint(c.Name, inv.Path))
+	}
+	inv.Args = fs.Args()
+	return inv, nil
+}
+
+// Find returns the command at the given path of subcommand names.
+func (c *Command) Find(names []string) (*Command, error) {
+	cmd := c
+	for i, name := range names {
+		sub := cmd.find(name)
+		if sub == nil {
+			return nil, fmt.Errorf("unknown command %q", strings.Join(names[:i+1], " "))
+		}
+		cmd = sub
+	}
+	return cmd, nil
+}
+
+// WriteHelp writes the --help text of the command at path (see Invocation.Path).
+func (c *Command) WriteHelp(w io.Writer, path string) error {
+	cmd, err := c.Find(strings.Fields(path))
+	if err != nil {
+		return err
+	}
+	name := joinPath(c.Name, path)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage: %s", name)
+	if cmd.Synopsis != "" {
+		fmt.Fprintf(&b, " %s", cmd.Synopsis)
+	}
+	b.WriteString("\n")
+	if cmd.Summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", cmd.Summary)
+	}
+
+	if len(cmd.Commands) > 0 {
+		b.WriteString("\nCommands:\n")
+		width := 0
+		for _, sub := range cmd.Commands {
+			width = max(width, len(sub.Name))
+		}
+		for _, sub := range cmd.Commands {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, sub.Name, sub.Summary)
+		}
+	}
+
+	fs := cmd.flagSet(c.Name, path)
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		b.WriteString("\nFlags:\n")
+		fs.SetOutput(&b)
+		fs.PrintDefaults()
+	}
+
+	if len(cmd.Commands) > 0 {
+		fmt.Fprintf(&b, "\nRun `%s COMMAND` for details on a command.\n", helpHint(c.Name, path))
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// flagSet builds the command's flags with output silenced; callers report
+// errors themselves.
+func (c *Command) flagSet(root, path string) *flag.FlagSet {
+	fs := flag.NewFlagSet(joinPath(root, path), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	return fs
+}
+
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" || name == "" {
+		return path + name
+	}
+	return path + " " + name
+}
+
+func helpHint(root, path string) string {
+	return strings.TrimSpace(root + " help " + path)
+}
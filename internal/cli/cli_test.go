@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testTree is a small forge-like command tree. Flags bind into throwaway
+// variables; the tests only look at parsing results.
+func testTree() *Command {
+	return &Command{
+		Name:     "forge",
+		Synopsis: "[COMMAND]",
+		Summary:  "Build things.",
+		Flags:    func(fs *flag.FlagSet) { fs.String("critic", "", "critic model") },
+		Commands: []*Command{
+			{Name: "run", Summary: "Run the plan", Flags: func(fs *flag.FlagSet) { fs.String("at", "", "start time") }},
+			{Name: "transcript", Synopsis: "[-o FILE] [TASK-ID]", Summary: "Export a transcript", Args: ArgTaskID,
+				Flags: func(fs *flag.FlagSet) { fs.String("o", "", "output file") }},
+			{Name: "replay", Summary: "Replay a journal", Args: ArgFile},
+			{Name: "task", Summary: "Manage tasks", Commands: []*Command{
+				{Name: "add", Summary: "Add a task", Raw: true, Flags: func(fs *flag.FlagSet) { fs.String("title", "", "task title") }},
+				{Name: "show", Summary: "Show a task", Raw: true, Args: ArgTaskID},
+			}},
+		},
+	}
+}
+
+// ============================================================
+// Parse
+// ============================================================
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantArgs []string
+	}{
+		{name: "root", args: nil, wantPath: ""},
+		{name: "root flags", args: []string{"--critic", "opus"}, wantPath: ""},
+		{name: "subcommand", args: []string{"run", "--at", "02:00"}, wantPath: "run"},
+		{name: "positional", args: []string{"transcript", "-o", "t.md", "task-001"}, wantPath: "transcript", wantArgs: []string{"task-001"}},
+		{name: "nested raw keeps flags", args: []string{"task", "add", "--title", "x"}, wantPath: "task add", wantArgs: []string{"--title", "x"}},
+		{name: "group without action", args: []string{"task"}, wantPath: "task"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inv, err := testTree().Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.args, err)
+			}
+			if inv.Path != tt.wantPath || len(inv.Args)+len(tt.wantArgs) > 0 && !reflect.DeepEqual(inv.Args, tt.wantArgs) {
+				t.Errorf("Parse(%q) = %q %q, want %q %q", tt.args, inv.Path, inv.Args, tt.wantPath, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParse_Help(t *testing.T) {
+	t.Parallel()
+	for _, args := range [][]string{{"-h"}, {"run", "--help"}, {"task", "add", "--title", "x", "-help"}} {
+		inv, err := testTree().Parse(args)
+		if !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("Parse(%q) error = %v, want flag.ErrHelp", args, err)
+		}
+		if want := strings.Join(stripFlags(args), " "); inv.Path != want {
+			t.Errorf("Parse(%q) path = %q, want %q", args, inv.Path, want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"bogus"}, wantErr: "unknown command \"bogus\" (run `forge help` for the list)"},
+		{args: []string{"task", "rename"}, wantErr: "unknown command \"task rename\" (run `forge help task` for the list)"},
+		{args: []string{"run", "--nope"}, wantErr: "forge run: flag provided but not defined: -nope (see `forge help run`)"},
+	}
+	for _, tt := range tests {
+		if _, err := testTree().Parse(tt.args); err == nil || err.Error() != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+// ============================================================
+// WriteHelp
+// ============================================================
+
+func TestWriteHelp(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	if err := testTree().WriteHelp(&b, "task"); err != nil {
+		t.Fatal(err)
+	}
+	want := "Usage: forge task\n\nManage tasks\n\nCommands:\n  add   Add a task\n  show  Show a task\n\nRun `forge help task COMMAND` for details on a command.\n"
+	if b.String() != want {
+		t.Errorf("WriteHelp(task) =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := testTree().WriteHelp(&b, "transcript"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Usage: forge transcript [-o FILE] [TASK-ID]\n", "Flags:\n  -o string\n    \toutput file\n"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteHelp(transcript) missing %q in:\n%s", want, b.String())
+		}
+	}
+
+	if err := testTree().WriteHelp(&b, "task nope"); err == nil {
+		t.Error("WriteHelp() of an unknown command should fail")
+	}
+}
+
+// ============================================================
+// Completion
+// ============================================================
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{shell: "bash", want: []string{
+			"complete -F _forge forge\n",
+			`"/run"|"/transcript"|"/replay"|"/task"|"task/add"|"task/show")`,
+			"words=\"$(forge task list --ids 2>/dev/null)\"",
+		}},
+		{shell: "zsh", want: []string{
+			"#compdef forge\n",
+			"cands=(run transcript replay task)",
+			"flags=(-o --help)",
+		}},
+		{shell: "fish", want: []string{
+			"complete -c forge -n '__fish_use_subcommand' -a task -d 'Manage tasks'\n",
+			"complete -c forge -n '__fish_seen_subcommand_from task; and not __fish_seen_subcommand_from add show' -a add -d 'Add a task'\n",
+			"complete -c forge -n '__fish_seen_subcommand_from task; and __fish_seen_subcommand_from add' -l title -d 'task title'\n",
+			"complete -c forge -n '__fish_seen_subcommand_from replay' -F\n",
+		}},
+	}
+	for _, tt := range tests {
+		got, err := testTree().Completion(tt.shell)
+		if err != nil {
+			t.Fatalf("Completion(%s) error: %v", tt.shell, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("Completion(%s) missing %q in:\n%s", tt.shell, want, got)
+			}
+		}
+	}
+
+	if _, err := testTree().Completion("tcsh"); err == nil {
+		t.Error("Completion(tcsh) should fail")
+	}
+}
+
+func TestCompletion_BashCompletes(t *testing.T) {
+	t.Parallel()
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	script, err := testTree().Completion("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string // words typed so far; the last is being completed
+		want string
+	}{
+		{line: "forge ''", want: "run transcript replay task"},
+		{line: "forge ta", want: "task"},
+		{line: "forge task ''", want: "add show"},
+		{line: "forge task add --t", want: "--title"},
+		{line: "forge run -", want: "--at --help"},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command(bash, "--norc", "-c", script+`
+COMP_WORDS=(`+tt.line+`); COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+_forge; echo "${COMPREPLY[*]}"`)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%q: bash error: %v", tt.line, err)
+		}
+		if got := strings.TrimSpace(string(out)); got != tt.want {
+			t.Errorf("completing %q = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// stripFlags keeps the leading subcommand words of args.
+func stripFlags(args []string) []string {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	return words
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Shells lists the shells Completion supports.
+var Shells = []string{"bash", "zsh", "fish"}
+
+// node is one command of the tree with its path below the root.
+type node struct {
+	path []string
+	cmd  *Command
+}
+
+// Completion generates a completion script for shell covering every
+// command, flag and, where a command takes them, task IDs or file names.
+func (c *Command) Completion(shell string) (string, error) {
+	var nodes []node
+	var walk func(path []string, cmd *Command)
+	walk = func(path []string, cmd *Command) {
+		nodes = append(nodes, node{path: path, cmd: cmd})
+		for _, sub := range cmd.Commands {
+			walk(append(path[:len(path):len(path)], sub.Name), sub)
+		}
+	}
+	walk(nil, c)
+
+	switch shell {
+	case "bash":
+		return c.bashCompletion(nodes), nil
+	case "zsh":
+		return c.zshCompletion(nodes), nil
+	case "fish":
+		return c.fishCompletion(nodes), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (want %s)", shell, strings.Join(Shells, ", "))
+}
+
+// bashCompletion tracks the subcommand path typed so far, then offers the
+// flags or the words (subcommands, task IDs) of the command it names.
+func (c *Command) bashCompletion(nodes []node) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %[1]s. Generated by `%[1]s completion bash`; load it with\n#   source <(%[1]s completion bash)\n", c.Name)
+	fmt.Fprintf(&b, "_%s() {\n", c.Name)
+	b.WriteString("    local cur=${COMP_WORDS[COMP_CWORD]} cmdpath=\"\" word i\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n        word=${COMP_WORDS[i]}\n        case \"$cmdpath/$word\" in\n")
+	fmt.Fprintf(&b, "            %s) cmdpath=\"${cmdpath:+$cmdpath }$word\" ;;\n", strings.Join(pathPatterns(nodes), "|"))
+	b.WriteString("        esac\n    done\n\n    local words=\"\" flags=\"\" files=0\n    case $cmdpath in\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "        %q)\n", strings.Join(n.path, " "))
+		if w := c.shellWords(n.cmd, "bash"); w != "" {
+			fmt.Fprintf(&b, "            words=%s\n", w)
+		}
+		fmt.Fprintf(&b, "            flags=%q\n", strings.Join(flagNames(n.cmd), " "))
+		if n.cmd.Args == ArgFile {
+			b.WriteString("            files=1\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ $cur == -* ]]; then\n        COMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n    else\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("        ((files)) && COMPREPLY+=($(compgen -f -- \"$cur\"))\n    fi\n}\n")
+	fmt.Fprintf(&b, "complete -F _%[1]s %[1]s\n", c.Name)
+	return b.String()
+}
+
+// zshCompletion mirrors the bash script with zsh's completion builtins.
+func (c *Command) zshCompletion(nodes []node) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %[1]s\n# zsh completion for %[1]s. Generated by `%[1]s completion zsh`; load it with\n#   source <(%[1]s completion zsh)\n", c.Name)
+	fmt.Fprintf(&b, "_%s() {\n", c.Name)
+	b.WriteString("    local cmdpath=\"\" word i files=0\n    local -a cands flags\n")
+	b.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n        word=${words[i]}\n        case \"$cmdpath/$word\" in\n")
+	fmt.Fprintf(&b, "            %s) cmdpath=\"${cmdpath:+$cmdpath }$word\" ;;\n", strings.Join(pathPatterns(nodes), "|"))
+	b.WriteString("        esac\n    done\n\n    case $cmdpath in\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "        %q)\n", strings.Join(n.path, " "))
+		if w := c.shellWords(n.cmd, "zsh"); w != "" {
+			fmt.Fprintf(&b, "            cands=(%s)\n", w)
+		}
+		fmt.Fprintf(&b, "            flags=(%s)\n", strings.Join(flagNames(n.cmd), " "))
+		if n.cmd.Args == ArgFile {
+			b.WriteString("            files=1\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ $PREFIX == -* ]]; then\n        compadd -- $flags\n    else\n")
+	b.WriteString("        compadd -- $cands\n        ((files)) && _files\n    fi\n}\n")
+	fmt.Fprintf(&b, "compdef _%[1]s %[1]s\n", c.Name)
+	return b.String()
+}
+
+// fishCompletion emits one `complete` line per subcommand, flag and
+// argument source, conditioned on the subcommands already typed.
+func (c *Command) fishCompletion(nodes []node) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %[1]s. Generated by `%[1]s completion fish`; load it with\n#   %[1]s completion fish | source\n", c.Name)
+	fmt.Fprintf(&b, "complete -c %s -f\n", c.Name)
+	for _, n := range nodes {
+		cond := "__fish_use_subcommand"
+		if len(n.path) > 0 {
+			var parts []string
+			for _, name := range n.path {
+				parts = append(parts, "__fish_seen_subcommand_from "+name)
+			}
+			cond = strings.Join(parts, "; and ")
+		}
+
+		if len(n.cmd.Commands) > 0 {
+			subCond := cond
+			if len(n.path) > 0 {
+				var names []string
+				for _, sub := range n.cmd.Commands {
+					names = append(names, sub.Name)
+				}
+				subCond += "; and not __fish_seen_subcommand_from " + strings.Join(names, " ")
+			}
+			for _, sub := range n.cmd.Commands {
+				fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n", c.Name, fishQuote(subCond), sub.Name, fishQuote(sub.Summary))
+			}
+		}
+
+		fs := n.cmd.flagSet(c.Name, "")
+		fs.VisitAll(func(f *flag.Flag) {
+			opt := "-l"
+			if len(f.Name) == 1 {
+				opt = "-o"
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s %s %s -d %s\n", c.Name, fishQuote(cond), opt, f.Name, fishQuote(f.Usage))
+		})
+
+		switch n.cmd.Args {
+		case ArgTaskID:
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", c.Name, fishQuote(cond), fishQuote("("+c.Name+" task list --ids 2>/dev/null)"))
+		case ArgFile:
+			fmt.Fprintf(&b, "complete -c %s -n %s -F\n", c.Name, fishQuote(cond))
+		}
+	}
+	return b.String()
+}
+
+// shellWords is the bash string or zsh array body of a command's
+// non-flag completions: its subcommands, or task IDs looked up at
+// completion time.
+func (c *Command) shellWords(cmd *Command, shell string) string {
+	var words []string
+	for _, sub := range cmd.Commands {
+		words = append(words, sub.Name)
+	}
+	if cmd.Args == ArgTaskID {
+		lookup := "$(" + c.Name + " task list --ids 2>/dev/null)"
+		if shell == "zsh" {
+			return strings.TrimSpace(strings.Join(words, " ") + ` ${(f)"` + lookup + `"}`)
+		}
+		return `"` + strings.TrimSpace(strings.Join(words, " ")+" "+lookup) + `"`
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	if shell == "zsh" {
+		return strings.Join(words, " ")
+	}
+	return `"` + strings.Join(words, " ") + `"`
+}
+
+// pathPatterns are the case patterns "<parent path>/<name>" matching each
+// subcommand word after its parent.
+func pathPatterns(nodes []node) []string {
+	var patterns []string
+	for _, n := range nodes {
+		if len(n.path) == 0 {
+			continue
+		}
+		parent := strings.Join(n.path[:len(n.path)-1], " ")
+		patterns = append(patterns, fmt.Sprintf("%q", parent+"/"+n.path[len(n.path)-1]))
+	}
+	return patterns
+}
+
+// flagNames lists a command's flags as typed on the command line, plus --help.
+func flagNames(cmd *Command) []string {
+	var names []string
+	cmd.flagSet("", "").VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 {
+			names = append(names, "-"+f.Name)
+		} else {
+			names = append(names, "--"+f.Name)
+		}
+	})
+	return append(names, "--help")
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,272 @@
+// Package codeindex keeps an embedding index of the project's source code
+// in .forge/index/, so task prompts can include the snippets most similar
+// to the task instead of only what file paths suggest. The index is built
+// when forge scans the project, re-embeds only files whose contents
+// changed, and is updated with the files each completed task committed.
+// Embeddings come from a local model (see provider.EmbedOllama); the
+// index is machine-specific and never committed.
+package codeindex
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/scanner"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Dir is the index directory's name under .forge/.
+const Dir = "index"
+
+const (
+	chunkLines  = 40      // lines per snippet
+	maxFileSize = 1 << 18 // larger files are not indexed
+	embedBatch  = 32      // snippets per embedding request
+)
+
+// Embedder turns texts into vectors, one per text.
+type Embedder func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Chunk is an indexed snippet of a file.
+type Chunk struct {
+	Path      string    `json:"path"` // slash-separated, relative to the project root
+	StartLine int       `json:"start_line"`
+	EndLine   int       `json:"end_line"`
+	Text      string    `json:"text"`
+	Vector    []float32 `json:"vector"`
+}
+
+// Index is the contents of .forge/index/index.json.
+type Index struct {
+	Model  string            `json:"model"`  // embedding model; a different one rebuilds the index
+	Files  map[string]string `json:"files"`  // path -> sha256 of the indexed contents
+	Chunks []Chunk           `json:"chunks"` // grouped by file
+}
+
+// Model returns the embedding model the settings ask for.
+func Model(settings *state.Settings) string {
+	return cmp.Or(settings.EmbeddingModel, provider.DefaultEmbeddingModel)
+}
+
+// OllamaEmbedder embeds with Model(settings) on a local Ollama, or on the
+// Ollama server the provider settings point at. Returns nil unless
+// Settings.CodeIndex is on.
+func OllamaEmbedder(settings *state.Settings) Embedder {
+	if settings == nil || !settings.CodeIndex {
+		return nil
+	}
+	var cfg provider.Config
+	if settings.Provider.Type == provider.ProviderOllama {
+		cfg = settings.Provider
+	}
+	model := Model(settings)
+	return func(ctx context.Context, texts []string) ([][]float32, error) {
+		return provider.EmbedOllama(ctx, cfg, model, texts)
+	}
+}
+
+// Path returns the index file's path for the project at root.
+func Path(root string) string {
+	return filepath.Join(state.ForgeDir(root), Dir, "index.json")
+}
+
+// Load reads the index; a missing file is an empty index.
+func Load(root string) (*Index, error) {
+	data, err := os.ReadFile(Path(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Index{Files: map[string]string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(root), err)
+	}
+	if idx.Files == nil {
+		idx.Files = map[string]string{}
+	}
+	return &idx, nil
+}
+
+// Save writes the index, with a .gitignore that keeps the directory out of
+// commits.
+func (idx *Index) Save(root string) error {
+	dir := filepath.Dir(Path(root))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*\n"), 0644); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(root), data, 0644)
+}
+
+// Refresh loads the index of the project at root and brings it up to date
+// with every source file (scanner.SourceFiles): new and changed files are
+// embedded, deleted ones dropped. A model other than the index's rebuilds
+// it. The index is saved, and updated reports how many files were
+// (re-)embedded.
+func Refresh(ctx context.Context, root, model string, embed Embedder) (idx *Index, updated int, err error) {
+	idx, err = Load(root)
+	if err != nil || idx.Model != model {
+		idx = &Index{Model: model, Files: map[string]string{}}
+	}
+	files := scanner.SourceFiles(root)
+	listed := make(map[string]bool, len(files))
+	for _, path := range files {
+		listed[path] = true
+	}
+	for path := range idx.Files {
+		if !listed[path] {
+			files = append(files, path) // gone; Update drops it
+		}
+	}
+	updated, err = idx.Update(ctx, root, files, embed)
+	if err != nil {
+		return idx, updated, err
+	}
+	return idx, updated, idx.Save(root)
+}
+
+// Update re-indexes the given files of the project at root, e.g. those a
+// task committed. Files whose contents match the index are skipped;
+// missing, oversized and binary files are dropped. It returns how many
+// files were embedded. The index is not saved.
+func (idx *Index) Update(ctx context.Context, root string, paths []string, embed Embedder) (int, error) {
+	var pending []Chunk
+	hashes := map[string]string{}
+	for _, p := range paths {
+		p = filepath.ToSlash(p)
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil || len(data) > maxFileSize || slices.Contains(data, 0) {
+			idx.remove(p)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		if idx.Files[p] == hash {
+			continue
+		}
+		hashes[p] = hash
+		pending = append(pending, chunk(p, string(data))...)
+	}
+
+	for start := 0; start < len(pending); start += embedBatch {
+		batch := pending[start:min(start+embedBatch, len(pending))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Path + "\n" + c.Text
+		}
+		vectors, err := embed(ctx, texts)
+		if err != nil {
+			return 0, err
+		}
+		for i := range batch {
+			batch[i].Vector = vectors[i]
+		}
+	}
+
+	for p, hash := range hashes {
+		idx.remove(p)
+		idx.Files[p] = hash
+	}
+	idx.Chunks = append(idx.Chunks, pending...)
+	return len(hashes), nil
+}
+
+func (idx *Index) remove(path string) {
+	delete(idx.Files, path)
+	idx.Chunks = slices.DeleteFunc(idx.Chunks, func(c Chunk) bool { return c.Path == path })
+}
+
+// chunk splits a file into snippets of chunkLines lines, skipping blank ones.
+func chunk(path, content string) []Chunk {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var chunks []Chunk
+	for start := 0; start < len(lines); start += chunkLines {
+		end := min(start+chunkLines, len(lines))
+		text := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		chunks = append(chunks, Chunk{Path: path, StartLine: start + 1, EndLine: end, Text: text})
+	}
+	return chunks
+}
+
+// Search returns the k snippets most similar to query, best first.
+func (idx *Index) Search(ctx context.Context, query string, k int, embed Embedder) ([]Chunk, error) {
+	if len(idx.Chunks) == 0 || k <= 0 {
+		return nil, nil
+	}
+	vectors, err := embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	q := vectors[0]
+	scores := make([]float64, len(idx.Chunks))
+	order := make([]int, len(idx.Chunks))
+	for i, c := range idx.Chunks {
+		order[i] = i
+		scores[i] = cosine(q, c.Vector)
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return 0
+	})
+	var out []Chunk
+	for _, i := range order[:min(k, len(order))] {
+		out = append(out, idx.Chunks[i])
+	}
+	return out, nil
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Section formats snippets for a task prompt. Returns "" for none.
+func Section(chunks []Chunk) string {
+	if len(chunks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nRELATED CODE (snippets found by code search; may be partial):\n")
+	for _, c := range chunks {
+		fmt.Fprintf(&b, "\n--- %s:%d-%d ---\n%s\n", c.Path, c.StartLine, c.EndLine, c.Text)
+	}
+	return b.String()
+}
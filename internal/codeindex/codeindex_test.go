@@ -0,0 +1,114 @@
+package codeindex
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wordEmbedder embeds a text as counts of a few fixed words, and counts
+// how many texts it embedded.
+func wordEmbedder(calls *int) Embedder {
+	vocab := []string{"order", "user", "invoice", "email"}
+	return func(_ context.Context, texts []string) ([][]float32, error) {
+		out := make([][]float32, len(texts))
+		for i, text := range texts {
+			*calls++
+			v := make([]float32, len(vocab))
+			for j, w := range vocab {
+				v[j] = float32(strings.Count(strings.ToLower(text), w))
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefresh_Incremental(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, root, "orders.go", "package shop\n\n// order totals\nfunc OrderTotal() {}\n")
+	writeFile(t, root, "users.go", "package shop\n\n// user emails\nfunc UserEmail() {}\n")
+	writeFile(t, root, "README.md", "not indexed\n")
+
+	var calls int
+	embed := wordEmbedder(&calls)
+	idx, n, err := Refresh(context.Background(), root, "m1", embed)
+	if err != nil || n != 2 || len(idx.Chunks) != 2 || calls != 2 {
+		t.Fatalf("first refresh: %d files, %d chunks, %d embeds, err %v", n, len(idx.Chunks), calls, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".forge", Dir, ".gitignore")); err != nil {
+		t.Errorf("index directory should be git-ignored: %v", err)
+	}
+
+	calls = 0
+	if _, n, _ := Refresh(context.Background(), root, "m1", embed); n != 0 || calls != 0 {
+		t.Errorf("unchanged refresh re-embedded %d files (%d texts)", n, calls)
+	}
+
+	writeFile(t, root, "users.go", "package shop\n\n// user invoices\n")
+	if err := os.Remove(filepath.Join(root, "orders.go")); err != nil {
+		t.Fatal(err)
+	}
+	idx, n, _ = Refresh(context.Background(), root, "m1", embed)
+	if n != 1 || calls != 1 || len(idx.Chunks) != 1 || !strings.Contains(idx.Chunks[0].Text, "invoices") {
+		t.Errorf("after edit and delete: %d files, %d embeds, chunks %+v", n, calls, idx.Chunks)
+	}
+	if _, ok := idx.Files["orders.go"]; ok {
+		t.Error("deleted file should leave the index")
+	}
+
+	calls = 0
+	if _, n, _ := Refresh(context.Background(), root, "m2", embed); n != 1 || calls != 1 {
+		t.Errorf("a new model should rebuild the index: %d files, %d embeds", n, calls)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, root, "orders.go", "// order order order\n")
+	writeFile(t, root, "users.go", "// user email\n")
+	var calls int
+	embed := wordEmbedder(&calls)
+	idx, _, err := Refresh(context.Background(), root, "m", embed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := idx.Search(context.Background(), "send the user an email", 1, embed)
+	if err != nil || len(got) != 1 || got[0].Path != "users.go" || got[0].StartLine != 1 {
+		t.Fatalf("Search = %+v, %v; want users.go", got, err)
+	}
+	if s := Section(got); !strings.Contains(s, "--- users.go:1-1 ---\n// user email\n") {
+		t.Errorf("Section = %q", s)
+	}
+
+	failing := func(context.Context, []string) ([][]float32, error) { return nil, errors.New("ollama down") }
+	if _, err := idx.Search(context.Background(), "x", 1, failing); err == nil {
+		t.Error("embedding errors should be returned")
+	}
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+	content := strings.Repeat("line\n", chunkLines) + strings.Repeat("\n", chunkLines) + "last\n"
+	got := chunk("a.go", content)
+	if len(got) != 2 || got[0].EndLine != chunkLines || got[1].StartLine != 2*chunkLines+1 || got[1].Text != "last" {
+		t.Errorf("chunk = %+v", got)
+	}
+}
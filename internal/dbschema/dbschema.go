@@ -0,0 +1,174 @@
+// Package dbschema summarizes a project's database tables for the planning
+// prompt, so data-model tasks match reality. The schema comes from a dev
+// database when Settings.DatabaseURLEnv names a variable holding its URL
+// (introspected with the psql, mysql or sqlite3 CLI), and otherwise from
+// the project's SQL migration files.
+package dbschema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+const (
+	maxTables  = 80 // listed in the prompt
+	maxColumns = 40 // listed per table
+)
+
+// Column is one table column. Attrs holds "pk", "not null" and "→ table"
+// (a foreign key), whichever are known.
+type Column struct {
+	Name  string
+	Type  string
+	Attrs []string
+}
+
+// Table is a table and its columns, in definition order.
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Schema is the set of tables and where they were read from.
+type Schema struct {
+	Source string // e.g. "dev database (postgres)" or "12 migration files in db/migrations"
+	Tables []Table
+}
+
+// Context returns the planning prompt section describing the database, or
+// "" if the project has neither a configured dev database nor migrations.
+// An unreachable database falls back to the migrations, noting why.
+func Context(ctx context.Context, root string, settings *state.Settings) string {
+	var note string
+	if settings != nil && settings.DatabaseURLEnv != "" {
+		url := os.Getenv(settings.DatabaseURLEnv)
+		if url == "" {
+			url = settings.EnvVars[settings.DatabaseURLEnv]
+		}
+		if url == "" {
+			note = fmt.Sprintf("$%s is not set", settings.DatabaseURLEnv)
+		} else if s, err := Introspect(ctx, url); err != nil {
+			note = fmt.Sprintf("could not introspect the dev database: %v", err)
+		} else {
+			return s.Section()
+		}
+	}
+
+	s, err := FromMigrations(root)
+	if err != nil || s == nil {
+		return ""
+	}
+	if note != "" {
+		s.Source += " (" + note + ")"
+	}
+	return s.Section()
+}
+
+// Section renders the schema for the planning prompt.
+func (s *Schema) Section() string {
+	if s == nil || len(s.Tables) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nDATABASE SCHEMA (from %s — plan data-model changes against these tables; add migrations rather than editing existing ones):\n", s.Source)
+	b.WriteString(s.Format())
+	return b.String()
+}
+
+// Format lists one table per line: "- users: id uuid pk, email text not null".
+func (s *Schema) Format() string {
+	var b strings.Builder
+	for i, t := range s.Tables {
+		if i == maxTables {
+			fmt.Fprintf(&b, "- … and %d more tables\n", len(s.Tables)-maxTables)
+			break
+		}
+		cols := make([]string, 0, len(t.Columns))
+		for j, c := range t.Columns {
+			if j == maxColumns {
+				cols = append(cols, fmt.Sprintf("… %d more", len(t.Columns)-maxColumns))
+				break
+			}
+			cols = append(cols, strings.Join(append([]string{c.Name, c.Type}, c.Attrs...), " "))
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", t.Name, strings.Join(cols, ", "))
+	}
+	return b.String()
+}
+
+// ============================================================
+// Table set
+// ============================================================
+
+// tableSet accumulates tables in creation order; migrations alter and drop
+// them by case-insensitive name.
+type tableSet struct {
+	tables []*Table
+}
+
+func (ts *tableSet) find(name string) *Table {
+	for _, t := range ts.tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// table returns the named table, creating it if needed.
+func (ts *tableSet) table(name string) *Table {
+	if t := ts.find(name); t != nil {
+		return t
+	}
+	t := &Table{Name: name}
+	ts.tables = append(ts.tables, t)
+	return t
+}
+
+func (ts *tableSet) drop(name string) {
+	for i, t := range ts.tables {
+		if strings.EqualFold(t.Name, name) {
+			ts.tables = append(ts.tables[:i], ts.tables[i+1:]...)
+			return
+		}
+	}
+}
+
+func (ts *tableSet) schema(source string) *Schema {
+	s := &Schema{Source: source}
+	for _, t := range ts.tables {
+		s.Tables = append(s.Tables, *t)
+	}
+	return s
+}
+
+func (t *Table) column(name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// setColumn adds c, or replaces the column of the same name.
+func (t *Table) setColumn(c Column) {
+	if existing := t.column(c.Name); existing != nil {
+		*existing = c
+		return
+	}
+	t.Columns = append(t.Columns, c)
+}
+
+func (t *Table) dropColumn(name string) {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			return
+		}
+	}
+}
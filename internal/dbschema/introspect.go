@@ -0,0 +1,157 @@
+package dbschema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const introspectTimeout = 15 * time.Second
+
+const postgresQuery = `SELECT c.table_name, c.column_name, c.data_type, c.is_nullable,
+  COALESCE((SELECT 'pk' FROM information_schema.table_constraints tc
+    JOIN information_schema.key_column_usage k ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema
+    WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name AND k.column_name = c.column_name LIMIT 1), '')
+FROM information_schema.columns c
+JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+ORDER BY c.table_name, c.ordinal_position`
+
+const mysqlQuery = `SELECT table_name, column_name, column_type, is_nullable, IF(column_key = 'PRI', 'pk', '')
+FROM information_schema.columns WHERE table_schema = DATABASE()
+ORDER BY table_name, ordinal_position`
+
+const sqliteQuery = `SELECT m.name, p.name, lower(p.type), CASE p."notnull" WHEN 1 THEN 'NO' ELSE 'YES' END, CASE WHEN p.pk > 0 THEN 'pk' ELSE '' END
+FROM sqlite_master m JOIN pragma_table_info(m.name) p
+WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+ORDER BY m.name, p.cid`
+
+// command is a CLI invocation that prints one tab-separated row per
+// column: table, column, type, nullable (YES/NO), "pk" or "".
+type command struct {
+	kind string // "postgres", "mysql" or "sqlite"
+	name string
+	args []string
+	env  []string // added to the environment, e.g. MYSQL_PWD
+}
+
+// Introspect reads the tables of the database at rawURL through its
+// command-line client (psql, mysql or sqlite3), which must be installed.
+// Errors never include the URL, since it usually carries a password.
+func Introspect(ctx context.Context, rawURL string) (*Schema, error) {
+	cmd, err := introspectCommand(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(cmd.name); err != nil {
+		return nil, fmt.Errorf("%s is not installed", cmd.name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, introspectTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, cmd.name, cmd.args...)
+	c.Env = append(os.Environ(), cmd.env...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		msg := redact(strings.TrimSpace(stderr.String()), rawURL)
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s: %s", cmd.name, firstLine(msg))
+	}
+
+	s := parseRows(stdout.String())
+	s.Source = "dev database (" + cmd.kind + ")"
+	return s, nil
+}
+
+// introspectCommand picks the client for rawURL's scheme.
+func introspectCommand(rawURL string) (command, error) {
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		if strings.HasSuffix(rawURL, ".db") || strings.HasSuffix(rawURL, ".sqlite") || strings.HasSuffix(rawURL, ".sqlite3") {
+			scheme, rest = "sqlite", rawURL
+		} else {
+			return command{}, errors.New("database URL has no scheme (postgres://, mysql:// or sqlite://)")
+		}
+	}
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return command{kind: "postgres", name: "psql",
+			args: []string{"-X", "-A", "-t", "-F", "\t", "-v", "ON_ERROR_STOP=1", "-d", rawURL, "-c", postgresQuery}}, nil
+	case "mysql", "mariadb":
+		u, err := url.Parse("mysql://" + rest)
+		if err != nil {
+			return command{}, errors.New("malformed mysql URL")
+		}
+		db := strings.TrimPrefix(u.Path, "/")
+		if db == "" {
+			return command{}, errors.New("mysql URL names no database")
+		}
+		args := []string{"--batch", "--skip-column-names", "-h", u.Hostname()}
+		if port := u.Port(); port != "" {
+			args = append(args, "-P", port)
+		}
+		var env []string
+		if u.User != nil {
+			args = append(args, "-u", u.User.Username())
+			if pw, ok := u.User.Password(); ok {
+				env = append(env, "MYSQL_PWD="+pw) // keeps it out of the process list
+			}
+		}
+		args = append(args, "-e", mysqlQuery, db)
+		return command{kind: "mysql", name: "mysql", args: args, env: env}, nil
+	case "sqlite", "sqlite3", "file":
+		path, _, _ := strings.Cut(rest, "?")
+		if path == "" {
+			return command{}, errors.New("sqlite URL names no file")
+		}
+		return command{kind: "sqlite", name: "sqlite3",
+			args: []string{"-readonly", "-separator", "\t", path, sqliteQuery}}, nil
+	}
+	return command{}, fmt.Errorf("unsupported database scheme %q", scheme)
+}
+
+// parseRows builds tables from the clients' tab-separated output.
+func parseRows(out string) *Schema {
+	ts := &tableSet{}
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(f) < 4 || f[0] == "" || f[1] == "" {
+			continue
+		}
+		c := Column{Name: f[1], Type: strings.ToLower(f[2])}
+		switch {
+		case len(f) > 4 && f[4] == "pk":
+			c.Attrs = []string{"pk"}
+		case strings.EqualFold(f[3], "NO"):
+			c.Attrs = []string{"not null"}
+		}
+		t := ts.table(f[0])
+		t.Columns = append(t.Columns, c)
+	}
+	return ts.schema("")
+}
+
+// redact removes rawURL, and its password if any, from msg.
+func redact(msg, rawURL string) string {
+	msg = strings.ReplaceAll(msg, rawURL, "<database URL>")
+	if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+		if pw, ok := u.User.Password(); ok && pw != "" {
+			msg = strings.ReplaceAll(msg, pw, "****")
+		}
+	}
+	return msg
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
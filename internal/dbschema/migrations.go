@@ -0,0 +1,296 @@
+package dbschema
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/manasm11/forge/internal/scanner"
+)
+
+// migrationDirs are directory names whose .sql files are read as schema
+// history; schema.sql and structure.sql dumps are read anywhere.
+var migrationDirs = map[string]bool{
+	"migrations": true, "migration": true, "migrate": true,
+	"db": true, "sql": true, "schema": true, "schemas": true,
+}
+
+const maxMigrationDepth = 5
+
+// FromMigrations replays the project's SQL migrations, in path order, and
+// returns the resulting tables. Returns nil if there are none.
+func FromMigrations(root string) (*Schema, error) {
+	files, err := migrationFiles(root)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	ts := &tableSet{}
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		parseSQL(ts, string(data))
+	}
+	if len(ts.tables) == 0 {
+		return nil, nil
+	}
+
+	dir := path.Dir(files[0])
+	for _, f := range files[1:] {
+		if path.Dir(f) != dir {
+			dir = ""
+			break
+		}
+	}
+	source := fmt.Sprintf("%d migration files", len(files))
+	if len(files) == 1 {
+		source = files[0]
+	} else if dir != "" {
+		source += " in " + dir
+	}
+	return ts.schema(source), nil
+}
+
+// migrationFiles lists up migration files relative to root, sorted so
+// timestamp- or number-prefixed names apply in order.
+func migrationFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (scanner.SkipDir(d.Name()) || strings.Count(rel, "/") >= maxMigrationDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := strings.ToLower(d.Name())
+		if !strings.HasSuffix(name, ".sql") || isDownMigration(rel) {
+			return nil
+		}
+		if name == "schema.sql" || name == "structure.sql" || inMigrationDir(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func inMigrationDir(rel string) bool {
+	parts := strings.Split(strings.ToLower(rel), "/")
+	for _, dir := range parts[:len(parts)-1] {
+		if migrationDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// isDownMigration matches the rollback halves of paired migrations
+// (0001_init.down.sql, 0001_init_down.sql, down/0001.sql).
+func isDownMigration(rel string) bool {
+	lower := strings.ToLower(rel)
+	return strings.HasSuffix(lower, ".down.sql") || strings.HasSuffix(lower, "_down.sql") ||
+		strings.Contains(lower, "/down/") || strings.HasPrefix(lower, "down/")
+}
+
+// ============================================================
+// SQL
+// ============================================================
+
+var (
+	lineCommentRe  = regexp.MustCompile(`--[^\n]*`)
+	blockCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	spacesRe       = regexp.MustCompile(`\s+`)
+
+	createTableRe = regexp.MustCompile(`(?i)^create\s+(?:(?:global\s+|local\s+)?(?:temp|temporary)\s+|unlogged\s+)?table\s+(?:if\s+not\s+exists\s+)?(\S+?)\s*\((.*)\)`)
+	alterTableRe  = regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?(?:only\s+)?(\S+)\s+(.*)$`)
+	dropTableRe   = regexp.MustCompile(`(?i)^drop\s+table\s+(?:if\s+exists\s+)?(.+?)(?:\s+(?:cascade|restrict))?$`)
+
+	addColumnRe    = regexp.MustCompile(`(?i)^add\s+(?:column\s+)?(?:if\s+not\s+exists\s+)?(.*)$`)
+	dropColumnRe   = regexp.MustCompile(`(?i)^drop\s+(?:column\s+)?(?:if\s+exists\s+)?(\S+)`)
+	renameColumnRe = regexp.MustCompile(`(?i)^rename\s+(?:column\s+)?(\S+)\s+to\s+(\S+)$`)
+	renameTableRe  = regexp.MustCompile(`(?i)^rename\s+to\s+(\S+)$`)
+	alterTypeRe    = regexp.MustCompile(`(?i)^(?:alter|modify)\s+(?:column\s+)?(\S+)\s+(?:set\s+data\s+)?type\s+(.+)$`)
+
+	// Table-level constraints in a column list, and ALTER actions on them
+	constraintRe = regexp.MustCompile(`(?i)^(constraint|primary\s+key|foreign\s+key|unique|check|index|key|exclude|fulltext|spatial)\b`)
+	// Where a column's type ends and its constraints begin
+	typeEndRe     = regexp.MustCompile(`(?i)\s+(not\s+null|null|primary\s+key|references|default|unique|check|constraint|generated|collate|auto_increment|autoincrement|identity|on\s+update)\b`)
+	referencesRe  = regexp.MustCompile(`(?i)\breferences\s+([^\s(]+)`)
+	primaryKeyRe  = regexp.MustCompile(`(?i)\bprimary\s+key\b`)
+	notNullRe     = regexp.MustCompile(`(?i)\bnot\s+null\b`)
+	tablePKRe     = regexp.MustCompile(`(?i)^(?:constraint\s+\S+\s+)?primary\s+key\s*\(([^)]*)\)`)
+	upDownMarkers = []struct{ up, down string }{
+		{"-- +goose Up", "-- +goose Down"},
+		{"-- migrate:up", "-- migrate:down"},
+	}
+)
+
+// parseSQL applies the DDL in sql to ts: CREATE TABLE, ALTER TABLE (add,
+// drop, rename and retype columns; rename tables) and DROP TABLE. Other
+// statements are ignored. For goose and dbmate files only the up section
+// is read.
+func parseSQL(ts *tableSet, sql string) {
+	for _, m := range upDownMarkers {
+		if i := strings.Index(sql, m.up); i >= 0 {
+			sql = sql[i+len(m.up):]
+			if j := strings.Index(sql, m.down); j >= 0 {
+				sql = sql[:j]
+			}
+		}
+	}
+	sql = blockCommentRe.ReplaceAllString(lineCommentRe.ReplaceAllString(sql, ""), "")
+
+	for _, stmt := range strings.Split(sql, ";") {
+		stmt = strings.TrimSpace(spacesRe.ReplaceAllString(stmt, " "))
+		switch {
+		case createTableRe.MatchString(stmt):
+			m := createTableRe.FindStringSubmatch(stmt)
+			name := ident(m[1])
+			ts.drop(name) // a dump may recreate a table
+			t := ts.table(name)
+			for _, item := range splitTopLevel(m[2]) {
+				if pk := tablePKRe.FindStringSubmatch(item); pk != nil {
+					for _, col := range strings.Split(pk[1], ",") {
+						if c := t.column(ident(col)); c != nil {
+							c.Attrs = addAttr(c.Attrs, "pk")
+						}
+					}
+					continue
+				}
+				if constraintRe.MatchString(item) {
+					continue
+				}
+				if c, ok := parseColumn(item); ok {
+					t.setColumn(c)
+				}
+			}
+		case alterTableRe.MatchString(stmt):
+			m := alterTableRe.FindStringSubmatch(stmt)
+			alterTable(ts, ident(m[1]), m[2])
+		case dropTableRe.MatchString(stmt):
+			for _, name := range strings.Split(dropTableRe.FindStringSubmatch(stmt)[1], ",") {
+				ts.drop(ident(name))
+			}
+		}
+	}
+}
+
+func alterTable(ts *tableSet, name, actions string) {
+	t := ts.find(name)
+	if t == nil {
+		return
+	}
+	for _, action := range splitTopLevel(actions) {
+		switch {
+		case renameTableRe.MatchString(action):
+			t.Name = ident(renameTableRe.FindStringSubmatch(action)[1])
+		case renameColumnRe.MatchString(action):
+			m := renameColumnRe.FindStringSubmatch(action)
+			if c := t.column(ident(m[1])); c != nil {
+				c.Name = ident(m[2])
+			}
+		case alterTypeRe.MatchString(action):
+			m := alterTypeRe.FindStringSubmatch(action)
+			if c := t.column(ident(m[1])); c != nil {
+				c.Type = columnType(m[2])
+			}
+		case addColumnRe.MatchString(action):
+			def := addColumnRe.FindStringSubmatch(action)[1]
+			if constraintRe.MatchString(def) {
+				continue
+			}
+			if c, ok := parseColumn(def); ok {
+				t.setColumn(c)
+			}
+		case dropColumnRe.MatchString(action):
+			col := dropColumnRe.FindStringSubmatch(action)[1]
+			if !constraintRe.MatchString(col) {
+				t.dropColumn(ident(col))
+			}
+		}
+	}
+}
+
+// parseColumn parses "email text NOT NULL UNIQUE" or
+// "user_id bigint REFERENCES users(id)".
+func parseColumn(def string) (Column, bool) {
+	name, rest, ok := strings.Cut(strings.TrimSpace(def), " ")
+	if !ok || name == "" {
+		return Column{}, false
+	}
+	c := Column{Name: ident(name), Type: columnType(rest)}
+	if primaryKeyRe.MatchString(rest) {
+		c.Attrs = append(c.Attrs, "pk")
+	} else if notNullRe.MatchString(rest) {
+		c.Attrs = append(c.Attrs, "not null")
+	}
+	if m := referencesRe.FindStringSubmatch(rest); m != nil {
+		c.Attrs = append(c.Attrs, "→ "+ident(m[1]))
+	}
+	return c, true
+}
+
+// columnType is the type part of a column definition, lowercased.
+func columnType(rest string) string {
+	if loc := typeEndRe.FindStringIndex(" " + rest); loc != nil {
+		rest = (" " + rest)[:loc[0]]
+	}
+	return strings.ToLower(strings.TrimSpace(rest))
+}
+
+// ident strips identifier quoting and the default "public." schema.
+func ident(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), "\"`[]")
+	s = strings.ReplaceAll(s, `"."`, ".")
+	s = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(s)
+	return strings.TrimPrefix(s, "public.")
+}
+
+// splitTopLevel splits on commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+func addAttr(attrs []string, attr string) []string {
+	for i, a := range attrs {
+		if a == "not null" {
+			attrs[i] = attr // pk implies not null
+			return attrs
+		}
+		if a == attr {
+			return attrs
+		}
+	}
+	return append([]string{attr}, attrs...)
+}
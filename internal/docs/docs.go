@@ -0,0 +1,333 @@
+// Package docs fetches external reference documents (design docs, API
+// specs) listed in Settings.ContextURLs and condenses them into text for
+// the planning and execution prompts, caching each under .forge/cache/docs
+// so repeated runs neither refetch nor break when offline.
+package docs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxAge is how long a cached document is used without asking
+	// the server whether it changed.
+	DefaultMaxAge = 24 * time.Hour
+
+	// DefaultMaxChars bounds each document once converted to text, so one
+	// large spec can't crowd out the rest of the prompt.
+	DefaultMaxChars = 12000
+
+	maxDownload = 4 << 20
+)
+
+// Doc is a fetched document, condensed to text.
+type Doc struct {
+	URL       string    `json:"url"`
+	Title     string    `json:"title,omitempty"`
+	Text      string    `json:"text"`
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// Fetcher downloads documents through an on-disk cache.
+type Fetcher struct {
+	Client   *http.Client
+	CacheDir string        // "" disables caching
+	MaxAge   time.Duration // cached copies younger than this skip the request
+	MaxChars int           // per document; 0 = DefaultMaxChars
+
+	now func() time.Time
+}
+
+// NewFetcher returns a fetcher caching under the project's .forge directory.
+func NewFetcher(root string) *Fetcher {
+	return &Fetcher{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		CacheDir: filepath.Join(root, ".forge", "cache", "docs"),
+		MaxAge:   DefaultMaxAge,
+	}
+}
+
+// ValidURL reports whether raw is an absolute http or https URL.
+func ValidURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Fetch returns the document at rawURL. A cached copy younger than MaxAge
+// is returned as is; an older one is revalidated with its ETag, and used
+// anyway if the server can't be reached.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Doc, error) {
+	if !ValidURL(rawURL) {
+		return Doc{}, fmt.Errorf("%s: not an http(s) URL", rawURL)
+	}
+	cached, haveCache := f.load(rawURL)
+	if haveCache && f.clock().Sub(cached.FetchedAt) < f.MaxAge {
+		return cached, nil
+	}
+
+	doc, notModified, err := f.download(ctx, rawURL, cached.ETag)
+	switch {
+	case err != nil && haveCache:
+		return cached, nil // stale beats nothing
+	case err != nil:
+		return Doc{}, fmt.Errorf("%s: %w", rawURL, err)
+	case notModified:
+		doc = cached
+	}
+	doc.FetchedAt = f.clock()
+	f.store(doc)
+	return doc, nil
+}
+
+// FetchAll fetches every URL in order, skipping (and reporting) failures.
+func (f *Fetcher) FetchAll(ctx context.Context, urls []string) ([]Doc, []error) {
+	var docs []Doc
+	var errs []error
+	for _, u := range urls {
+		doc, err := f.Fetch(ctx, u)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, errs
+}
+
+// Context fetches urls through the project's cache and renders them with
+// Section. Returns "" when there are none.
+func Context(ctx context.Context, root string, urls []string) string {
+	if len(urls) == 0 {
+		return ""
+	}
+	docs, errs := NewFetcher(root).FetchAll(ctx, urls)
+	return Section(docs, errs)
+}
+
+// Section renders documents for a prompt or context.md. Documents that
+// couldn't be fetched are named so the model knows the spec exists but
+// wasn't available, rather than assuming there is none.
+func Section(docs []Doc, errs []error) string {
+	if len(docs)+len(errs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Reference Documents\n")
+	b.WriteString("External specs for this project. Build against them instead of guessing; follow them where they disagree with assumptions.\n")
+	for _, d := range docs {
+		title := d.Title
+		if title == "" {
+			title = d.URL
+		}
+		fmt.Fprintf(&b, "\n### %s\nSource: %s\n\n%s\n", title, d.URL, d.Text)
+	}
+	if len(errs) > 0 {
+		b.WriteString("\nUnavailable (could not be fetched):\n")
+		for _, err := range errs {
+			fmt.Fprintf(&b, "- %v\n", err)
+		}
+	}
+	return b.String()
+}
+
+// download fetches and condenses rawURL. notModified is true when the
+// server confirmed etag is still current.
+func (f *Fetcher) download(ctx context.Context, rawURL, etag string) (doc Doc, notModified bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Doc{}, false, err
+	}
+	req.Header.Set("Accept", "text/markdown, text/plain, application/json, application/yaml, text/html;q=0.9, */*;q=0.5")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Doc{}, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return Doc{}, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Doc{}, false, fmt.Errorf("HTTP %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return Doc{}, false, err
+	}
+	if len(body) > maxDownload {
+		return Doc{}, false, fmt.Errorf("larger than %d MB", maxDownload>>20)
+	}
+	if !utf8.Valid(body) || strings.ContainsRune(string(body), 0) {
+		return Doc{}, false, errors.New("not a text document")
+	}
+
+	title, text := Condense(resp.Header.Get("Content-Type"), string(body))
+	text, truncated := truncate(text, f.maxChars())
+	return Doc{URL: rawURL, Title: title, Text: text, ETag: resp.Header.Get("ETag"), Truncated: truncated}, false, nil
+}
+
+// ============================================================
+// Condensing
+// ============================================================
+
+var (
+	titleRe   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	commentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	headingRe = regexp.MustCompile(`(?i)<h([1-6])[^>]*>`)
+	itemRe    = regexp.MustCompile(`(?i)<li[^>]*>`)
+	breakRe   = regexp.MustCompile(`(?i)</?(p|div|br|tr|pre|ul|ol|table|section|article|/h[1-6])\b[^>]*>`)
+	tagRe     = regexp.MustCompile(`(?s)<[^>]*>`)
+	spaceRe   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankRe   = regexp.MustCompile(`\n{3,}`)
+
+	// Elements whose content is never documentation. Go's regexp has no
+	// backreferences, so each gets its own pattern.
+	noiseRes = func() []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, tag := range []string{"script", "style", "noscript", "svg", "nav", "footer", "head"} {
+			res = append(res, regexp.MustCompile(`(?is)<`+tag+`\b[^>]*>.*?</`+tag+`>`))
+		}
+		return res
+	}()
+)
+
+// Condense turns a response body into prompt-sized text: HTML pages lose
+// their markup, navigation and scripts but keep headings and list items;
+// other text (markdown, JSON and YAML specs) is kept verbatim. Either way
+// runs of blank lines and trailing spaces are collapsed.
+func Condense(contentType, body string) (title, text string) {
+	if strings.Contains(strings.ToLower(contentType), "html") || looksLikeHTML(body) {
+		title, body = htmlToText(body)
+	}
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+	}
+	text = blankRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return title, strings.TrimSpace(text)
+}
+
+func looksLikeHTML(body string) bool {
+	head := strings.ToLower(strings.TrimSpace(body))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+}
+
+func htmlToText(page string) (string, string) {
+	title := ""
+	if m := titleRe.FindStringSubmatch(page); m != nil {
+		title = strings.TrimSpace(spaceRe.ReplaceAllString(html.UnescapeString(tagRe.ReplaceAllString(m[1], "")), " "))
+	}
+
+	page = commentRe.ReplaceAllString(page, "")
+	for _, re := range noiseRes {
+		page = re.ReplaceAllString(page, "")
+	}
+	// Source newlines are layout, not content; block tags decide breaks
+	page = strings.NewReplacer("\r", " ", "\n", " ").Replace(page)
+	page = headingRe.ReplaceAllStringFunc(page, func(tag string) string {
+		return "\n\n" + strings.Repeat("#", int(tag[2]-'0')) + " "
+	})
+	page = itemRe.ReplaceAllString(page, "\n- ")
+	page = breakRe.ReplaceAllString(page, "\n")
+	page = tagRe.ReplaceAllString(page, "")
+	page = html.UnescapeString(page)
+
+	lines := strings.Split(page, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(spaceRe.ReplaceAllString(l, " "))
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// truncate cuts text to about max characters at a line boundary.
+func truncate(text string, max int) (string, bool) {
+	if len(text) <= max {
+		return text, false
+	}
+	cut := text[:max]
+	if i := strings.LastIndexByte(cut, '\n'); i > max/2 {
+		cut = cut[:i]
+	}
+	for !utf8.ValidString(cut) {
+		cut = cut[:len(cut)-1]
+	}
+	return fmt.Sprintf("%s\n[… truncated, %d more characters]", cut, len(text)-len(cut)), true
+}
+
+// ============================================================
+// Cache
+// ============================================================
+
+func (f *Fetcher) cachePath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(f.CacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func (f *Fetcher) load(rawURL string) (Doc, bool) {
+	if f.CacheDir == "" {
+		return Doc{}, false
+	}
+	data, err := os.ReadFile(f.cachePath(rawURL))
+	if err != nil {
+		return Doc{}, false
+	}
+	var doc Doc
+	if json.Unmarshal(data, &doc) != nil || doc.URL != rawURL {
+		return Doc{}, false
+	}
+	return doc, true
+}
+
+// store writes doc to the cache. Best effort: a failed write only costs
+// a download next time.
+func (f *Fetcher) store(doc Doc) {
+	if f.CacheDir == "" {
+		return
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil || os.MkdirAll(f.CacheDir, 0755) != nil {
+		return
+	}
+	_ = os.WriteFile(f.cachePath(doc.URL), data, 0644)
+}
+
+func (f *Fetcher) clock() time.Time {
+	if f.now != nil {
+		return f.now()
+	}
+	return time.Now()
+}
+
+func (f *Fetcher) maxChars() int {
+	if f.MaxChars > 0 {
+		return f.MaxChars
+	}
+	return DefaultMaxChars
+}
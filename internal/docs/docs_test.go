@@ -0,0 +1,208 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Condense
+// ============================================================
+
+func TestCondense(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantTitle   string
+		wantText    string
+	}{
+		{
+			name:        "html page",
+			contentType: "text/html; charset=utf-8",
+			body: `<!DOCTYPE html><html><head><title>Orders API &amp; Webhooks</title><style>p{}</style></head>
+<body><nav><a href="/">Home</a></nav>
+<h1>Orders</h1><p>Create an order with
+  <code>POST /orders</code>.</p>
+<ul><li>id: string</li><li>total: cents</li></ul>
+<script>track()</script><!-- hidden -->
+<footer>© 2026</footer></body></html>`,
+			wantTitle: "Orders API & Webhooks",
+			wantText:  "# Orders\n\nCreate an order with POST /orders.\n\n- id: string\n- total: cents",
+		},
+		{
+			name:        "sniffed html",
+			contentType: "",
+			body:        "<html><body><h2>Auth</h2>Use <b>Bearer</b> tokens</body></html>",
+			wantText:    "## Auth\nUse Bearer tokens",
+		},
+		{
+			name:        "yaml spec kept verbatim",
+			contentType: "application/yaml",
+			body:        "openapi: 3.0.0  \npaths:\n  /orders:\n\n\n\n    get: {}\n",
+			wantText:    "openapi: 3.0.0\npaths:\n  /orders:\n\n    get: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			title, text := Condense(tt.contentType, tt.body)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if text != tt.wantText {
+				t.Errorf("text =\n%q\nwant\n%q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+	text := strings.Repeat("line of text\n", 10)
+	got, truncated := truncate(text, 40)
+	if !truncated || !strings.HasPrefix(got, "line of text\nline of text\nline of text\n[…") {
+		t.Errorf("truncate() = %q, %v", got, truncated)
+	}
+	if got, truncated := truncate("short", 40); got != "short" || truncated {
+		t.Errorf("truncate(short) = %q, %v", got, truncated)
+	}
+}
+
+// ============================================================
+// Fetch
+// ============================================================
+
+// specServer serves a spec with an ETag and counts full and conditional
+// requests.
+func specServer(t *testing.T) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	var full, revalidated atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/markdown")
+		w.Write([]byte("# Spec\n\nGET /health returns 200\n"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &full, &revalidated
+}
+
+func TestFetch_CachesAndRevalidates(t *testing.T) {
+	t.Parallel()
+	srv, full, revalidated := specServer(t)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	f := &Fetcher{Client: srv.Client(), CacheDir: t.TempDir(), MaxAge: time.Hour, now: func() time.Time { return now }}
+
+	doc, err := f.Fetch(context.Background(), srv.URL+"/spec.md")
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+	if doc.Text != "# Spec\n\nGET /health returns 200" || doc.ETag != `"v1"` {
+		t.Errorf("doc = %+v", doc)
+	}
+
+	// Fresh copy: no request at all
+	if _, err := f.Fetch(context.Background(), srv.URL+"/spec.md"); err != nil || full.Load() != 1 || revalidated.Load() != 0 {
+		t.Errorf("fresh fetch: err %v, full %d, revalidated %d", err, full.Load(), revalidated.Load())
+	}
+
+	// Stale copy: a conditional request keeps the cached text
+	now = now.Add(2 * time.Hour)
+	doc, err = f.Fetch(context.Background(), srv.URL+"/spec.md")
+	if err != nil || full.Load() != 1 || revalidated.Load() != 1 {
+		t.Errorf("stale fetch: err %v, full %d, revalidated %d", err, full.Load(), revalidated.Load())
+	}
+	if !doc.FetchedAt.Equal(now) || !strings.Contains(doc.Text, "/health") {
+		t.Errorf("revalidated doc = %+v", doc)
+	}
+}
+
+func TestFetch_StaleCacheWhenOffline(t *testing.T) {
+	t.Parallel()
+	srv, _, _ := specServer(t)
+	dir := t.TempDir()
+	now := time.Now()
+	f := &Fetcher{Client: srv.Client(), CacheDir: dir, MaxAge: time.Hour, now: func() time.Time { return now }}
+	if _, err := f.Fetch(context.Background(), srv.URL+"/spec.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+	now = now.Add(48 * time.Hour)
+	doc, err := f.Fetch(context.Background(), srv.URL+"/spec.md")
+	if err != nil || !strings.Contains(doc.Text, "/health") {
+		t.Errorf("offline Fetch() = %+v, %v; want the cached copy", doc, err)
+	}
+}
+
+func TestFetchAll_ReportsFailures(t *testing.T) {
+	t.Parallel()
+	srv, _, _ := specServer(t)
+	f := &Fetcher{Client: srv.Client(), CacheDir: t.TempDir()}
+
+	docs, errs := f.FetchAll(context.Background(), []string{srv.URL + "/spec.md", srv.URL + "/missing", "ftp://example.com/x"})
+	if len(docs) != 1 || len(errs) != 2 {
+		t.Fatalf("FetchAll() = %d docs, errors %v", len(docs), errs)
+	}
+	if !strings.Contains(errs[0].Error(), "404") || !strings.Contains(errs[1].Error(), "not an http(s) URL") {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+// ============================================================
+// Section
+// ============================================================
+
+func TestSection(t *testing.T) {
+	t.Parallel()
+	if got := Section(nil, nil); got != "" {
+		t.Errorf("Section(nil) = %q, want empty", got)
+	}
+
+	got := Section(
+		[]Doc{{URL: "https://example.com/api", Title: "Orders API", Text: "POST /orders"}, {URL: "https://example.com/raw.yaml", Text: "openapi: 3.0.0"}},
+		[]error{errors.New("https://example.com/gone: HTTP 404 Not Found")},
+	)
+	for _, want := range []string{
+		"## Reference Documents\n",
+		"### Orders API\nSource: https://example.com/api\n\nPOST /orders\n",
+		"### https://example.com/raw.yaml\n",
+		"Unavailable (could not be fetched):\n- https://example.com/gone: HTTP 404 Not Found\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Section() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidURL(t *testing.T) {
+	t.Parallel()
+	for raw, want := range map[string]bool{
+		"https://example.com/spec.yaml": true,
+		"http://localhost:8080/docs":    true,
+		"example.com/spec":              false,
+		"file:///etc/passwd":            false,
+		"":                              false,
+	} {
+		if got := ValidURL(raw); got != want {
+			t.Errorf("ValidURL(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
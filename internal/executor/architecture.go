@@ -0,0 +1,20 @@
+package executor
+
+import (
+	"path/filepath"
+
+	"github.com/manasm11/forge/internal/planner"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// architecture returns the plan's architecture document as a task prompt
+// section, or "" when planning didn't produce one. A file that can't be
+// read is reported and left out.
+func (r *Runner) architecture(taskID string) string {
+	doc, err := planner.LoadArchitecture(filepath.Join(state.ForgeDir(r.cfg.StateRoot), planner.ArchitectureFileName))
+	if err != nil {
+		r.emit(TaskEvent{TaskID: taskID, Type: EventError, Message: "reading the architecture document: " + err.Error()})
+		return ""
+	}
+	return planner.ArchitectureSection(doc)
+}
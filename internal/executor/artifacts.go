@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// ArtifactsDir is where a task's collected artifacts are kept.
+func ArtifactsDir(root, taskID string) string {
+	return filepath.Join(state.ForgeDir(root), "artifacts", taskID)
+}
+
+// CollectArtifacts copies the files matching a task's artifact globs
+// (relative to root, filepath.Match syntax; a matched directory is copied
+// whole) into .forge/artifacts/<task-id>/, keeping their relative paths.
+// Anything collected by an earlier attempt is replaced. Returns the
+// collected paths, relative to root and sorted.
+func CollectArtifacts(root, taskID string, globs []string) ([]string, error) {
+	dest := ArtifactsDir(root, taskID)
+	if err := os.RemoveAll(dest); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var collected []string
+	for _, glob := range globs {
+		if filepath.IsAbs(glob) {
+			return collected, fmt.Errorf("artifact %q: must be relative to the project", glob)
+		}
+		matches, err := filepath.Glob(filepath.Join(root, glob))
+		if err != nil {
+			return collected, fmt.Errorf("artifact %q: %w", glob, err)
+		}
+		for _, match := range matches {
+			err := filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				if rel == ".forge" || rel == ".git" || strings.HasPrefix(rel, ".forge"+string(filepath.Separator)) || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if !d.Type().IsRegular() || seen[rel] {
+					return nil
+				}
+				seen[rel] = true
+				if err := copyFile(path, filepath.Join(dest, rel)); err != nil {
+					return err
+				}
+				collected = append(collected, filepath.ToSlash(rel))
+				return nil
+			})
+			if err != nil {
+				return collected, fmt.Errorf("artifact %q: %w", glob, err)
+			}
+		}
+	}
+	sort.Strings(collected)
+	return collected, nil
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
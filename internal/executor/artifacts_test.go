@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// CollectArtifacts
+// ============================================================
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCollectArtifacts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		globs []string
+		want  []string
+	}{
+		{name: "single file", globs: []string{"coverage.out"}, want: []string{"coverage.out"}},
+		{name: "glob", globs: []string{"bin/*"}, want: []string{"bin/app", "bin/app.sha256"}},
+		{name: "directory copied whole", globs: []string{"docs"}, want: []string{"docs/api/index.html", "docs/index.html"}},
+		{name: "overlapping globs collected once", globs: []string{"bin/app", "bin/*"}, want: []string{"bin/app", "bin/app.sha256"}},
+		{name: "no matches", globs: []string{"*.xml"}, want: nil},
+		{name: "forge dir never collected", globs: []string{".forge/*"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{
+				"coverage.out":        "mode: set",
+				"bin/app":             "binary",
+				"bin/app.sha256":      "abc",
+				"docs/index.html":     "<html>",
+				"docs/api/index.html": "<html>",
+				".forge/state.json":   "{}",
+			})
+
+			got, err := CollectArtifacts(root, "task-001", tt.globs)
+			if err != nil {
+				t.Fatalf("CollectArtifacts() error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("collected = %v, want %v", got, tt.want)
+			}
+			for _, rel := range got {
+				if _, err := os.Stat(filepath.Join(ArtifactsDir(root, "task-001"), rel)); err != nil {
+					t.Errorf("%s not copied: %v", rel, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectArtifacts_ReplacesEarlierAttempt(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"old.txt": "x", "new.txt": "y"})
+
+	if _, err := CollectArtifacts(root, "task-001", []string{"old.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CollectArtifacts(root, "task-001", []string{"new.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(ArtifactsDir(root, "task-001"), "old.txt")); !os.IsNotExist(err) {
+		t.Errorf("old.txt should be gone, stat error = %v", err)
+	}
+}
+
+func TestCollectArtifacts_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		glob    string
+		wantErr string
+	}{
+		{name: "absolute path", glob: "/etc/passwd", wantErr: "relative"},
+		{name: "bad pattern", glob: "[", wantErr: "syntax error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := CollectArtifacts(t.TempDir(), "task-001", []string{tt.glob})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CollectArtifacts() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -36,6 +36,10 @@ func SanitizeBranchName(name string) string {
 	// Remove trailing .lock
 	result = strings.TrimSuffix(result, ".lock")
 
+	// Git rejects refs ending in "/" or ".", and Windows cannot create
+	// the matching ref file for a trailing dot.
+	result = strings.TrimRight(result, "/.")
+
 	return result
 }
 
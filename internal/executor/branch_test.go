@@ -42,6 +42,8 @@ func TestSanitizeBranchName(t *testing.T) {
 		{"forge/task:001", "forge/task-001"},
 		{".forge/task-001", "forge/task-001"},
 		{"forge/task-001.lock", "forge/task-001"},
+		{"forge/task-001.", "forge/task-001"},
+		{"forge\\task-001/", "forge-task-001"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
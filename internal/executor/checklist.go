@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// SuggestedChecklist is offered as an example for Settings.Checklist.
+var SuggestedChecklist = []string{
+	"Error handling present",
+	"Tests added",
+	"Docs updated",
+	"No debug prints",
+}
+
+// ChecklistSection asks Claude to confirm each item of the project's
+// self-review checklist at the end of its reply. It is "" for an empty
+// checklist.
+func ChecklistSection(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nSELF-REVIEW CHECKLIST:\n")
+	b.WriteString("Before you finish, check your change against each item. End your reply with one line per item,\n")
+	b.WriteString("\"[x] N. item\" if it holds or \"[ ] N. item — why not\" if it doesn't:\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "[ ] %d. %s\n", i+1, item)
+	}
+	return b.String()
+}
+
+var checklistLineRe = regexp.MustCompile(`^(?:[-*]\s*)?\[([ xX✓✔])\]\s*(\d+)[.):]`)
+
+// UnconfirmedItems returns the checklist items the reply didn't tick,
+// including any it didn't mention. The last answer for an item counts.
+func UnconfirmedItems(reply string, items []string) []string {
+	confirmed := make([]bool, len(items))
+	for _, line := range strings.Split(reply, "\n") {
+		m := checklistLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		if n >= 1 && n <= len(items) {
+			confirmed[n-1] = m[1] != " "
+		}
+	}
+	var open []string
+	for i, item := range items {
+		if !confirmed[i] {
+			open = append(open, item)
+		}
+	}
+	return open
+}
+
+// BuildChecklistFollowUpPrompt sends Claude back to the items it didn't
+// confirm, before the runner moves on to tests.
+func BuildChecklistFollowUpPrompt(task state.Task, unconfirmed, items []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are finishing task %s — %s. Your reply did not confirm these self-review checklist items:\n", task.ID, task.Title)
+	for _, item := range unconfirmed {
+		b.WriteString("- " + item + "\n")
+	}
+	b.WriteString("\nReview your changes in the working tree and fix whatever these items call for. ")
+	b.WriteString("Don't undo the rest of the implementation.\n")
+	b.WriteString(ChecklistSection(items))
+	return b.String()
+}
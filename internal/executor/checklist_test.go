@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestUnconfirmedItems(t *testing.T) {
+	t.Parallel()
+	items := []string{"Error handling present", "Tests added", "No debug prints"}
+	tests := []struct {
+		name  string
+		reply string
+		want  []string
+	}{
+		{
+			name:  "all confirmed",
+			reply: "Done.\n[x] 1. Error handling present\n- [X] 2. Tests added\n[✓] 3) No debug prints\n",
+			want:  nil,
+		},
+		{
+			name:  "one left open with a reason",
+			reply: "[x] 1. Error handling present\n[ ] 2. Tests added — no test harness yet\n[x] 3. No debug prints\n",
+			want:  []string{"Tests added"},
+		},
+		{
+			name:  "unmentioned items are unconfirmed",
+			reply: "[x] 2. Tests added\n",
+			want:  []string{"Error handling present", "No debug prints"},
+		},
+		{
+			name:  "last answer wins",
+			reply: "[ ] 1. Error handling present\n[x] 1. Error handling present\n[x] 2.\n[x] 3.\n",
+			want:  nil,
+		},
+		{
+			name:  "out of range numbers are ignored",
+			reply: "[x] 1.\n[x] 2.\n[x] 3.\n[ ] 4. Something else\n",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := UnconfirmedItems(tt.reply, items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnconfirmedItems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecklistSection(t *testing.T) {
+	t.Parallel()
+	if got := ChecklistSection(nil); got != "" {
+		t.Errorf("ChecklistSection(nil) = %q, want empty", got)
+	}
+	got := ChecklistSection([]string{"Tests added", "Docs updated"})
+	if !strings.Contains(got, "[ ] 1. Tests added\n[ ] 2. Docs updated\n") {
+		t.Errorf("ChecklistSection() =\n%s", got)
+	}
+}
+
+func TestBuildChecklistFollowUpPrompt(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-002", Title: "Add search"}
+	got := BuildChecklistFollowUpPrompt(task, []string{"Docs updated"}, []string{"Tests added", "Docs updated"})
+	for _, want := range []string{"task-002 — Add search", "- Docs updated\n", "[ ] 2. Docs updated"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BuildChecklistFollowUpPrompt() missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Check run states and conclusions, as the GitHub Checks API names them.
+const (
+	CheckInProgress = "in_progress"
+	CheckCompleted  = "completed"
+	CheckSuccess    = "success"
+	CheckFailure    = "failure"
+)
+
+const (
+	maxCheckText         = 4000 // log excerpt, from the end
+	maxStatusDescription = 140  // GitHub's limit for commit status descriptions
+)
+
+// CheckRun is a result forge reports on a pushed commit, shown in the PR
+// UI next to CI.
+type CheckRun struct {
+	Name       string // "forge: task-001"
+	SHA        string
+	Status     string // CheckInProgress or CheckCompleted
+	Conclusion string // CheckSuccess or CheckFailure, once completed
+	Title      string
+	Summary    string // markdown
+	Text       string // markdown details, e.g. a log excerpt
+	Dir        string // repository to report in; empty = the publisher's
+}
+
+// CheckPublisher reports check runs on the project's forge.
+type CheckPublisher interface {
+	// PublishCheck creates the check run, or updates it when id is
+	// non-zero, and returns its ID.
+	PublishCheck(ctx context.Context, id int64, run CheckRun) (int64, error)
+}
+
+// GhCheckPublisher publishes check runs with `gh api`. Only GitHub Apps
+// may create check runs, so when GitHub refuses a user token it falls back
+// to a commit status with the same name, which shows in the same place.
+type GhCheckPublisher struct {
+	dir string
+}
+
+// NewGhCheckPublisher creates a CheckPublisher for the repository at dir.
+func NewGhCheckPublisher(dir string) *GhCheckPublisher {
+	return &GhCheckPublisher{dir: dir}
+}
+
+// PublishCheck creates or updates the check run. With the commit status
+// fallback it returns ID 0; statuses replace each other by name, so the
+// next update simply posts again.
+func (g *GhCheckPublisher) PublishCheck(ctx context.Context, id int64, run CheckRun) (int64, error) {
+	payload := map[string]any{
+		"name":   run.Name,
+		"status": run.Status,
+		"output": map[string]string{"title": run.Title, "summary": run.Summary, "text": run.Text},
+	}
+	if run.Conclusion != "" {
+		payload["conclusion"] = run.Conclusion
+	}
+	endpoint, method := "repos/{owner}/{repo}/check-runs", "POST"
+	if id != 0 {
+		endpoint, method = fmt.Sprintf("repos/{owner}/{repo}/check-runs/%d", id), "PATCH"
+	} else {
+		payload["head_sha"] = run.SHA
+	}
+	out, err := g.api(ctx, run.Dir, method, endpoint, payload)
+	if err != nil {
+		if !strings.Contains(out, "GitHub App") && !strings.Contains(out, "HTTP 403") {
+			return 0, err
+		}
+		_, err = g.api(ctx, run.Dir, "POST", "repos/{owner}/{repo}/statuses/"+run.SHA, CommitStatus(run))
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+}
+
+func (g *GhCheckPublisher) api(ctx context.Context, dir, method, endpoint string, payload any) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, "gh", "api", "-X", method, endpoint, "--input", "-", "--jq", ".id")
+	cmd.Dir = g.dir
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	cmd.Stdin = strings.NewReader(string(body))
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	if err != nil {
+		return output, fmt.Errorf("gh api %s: %s: %w", endpoint, output, err)
+	}
+	return output, nil
+}
+
+// CommitStatus is the commit status payload standing in for run.
+func CommitStatus(run CheckRun) map[string]string {
+	st := "pending"
+	switch {
+	case run.Status != CheckCompleted:
+	case run.Conclusion == CheckSuccess:
+		st = "success"
+	default:
+		st = "failure"
+	}
+	description := run.Title
+	if len(description) > maxStatusDescription {
+		description = strings.ToValidUTF8(description[:maxStatusDescription-1], "") + "…"
+	}
+	return map[string]string{"state": st, "context": run.Name, "description": description}
+}
+
+// TaskCheckName names a task's check run.
+func TaskCheckName(task state.Task) string {
+	return "forge: " + task.ID
+}
+
+// TaskCheckRun reports a task that passed its checks on attempt attempts:
+// the checks as a table, and the end of the task log.
+func TaskCheckRun(task state.Task, sha string, checks []CheckResult, attempts int, log string) CheckRun {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s — %s** passed on attempt %d.\n", task.ID, task.Title, attempts)
+	if len(checks) > 0 {
+		b.WriteString("\n| Check | Command | Result | Time |\n|---|---|---|---|\n")
+		for _, c := range checks {
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %.1fs |\n", c.Name, c.Command, passMark(c.Passed), c.Duration)
+		}
+	}
+	run := CheckRun{
+		Name: TaskCheckName(task), SHA: sha,
+		Status: CheckCompleted, Conclusion: CheckSuccess,
+		Title: task.Title, Summary: b.String(),
+	}
+	if log = strings.TrimSpace(log); log != "" {
+		if len(log) > maxCheckText {
+			log = "…\n" + tail(log, maxCheckText)
+		}
+		run.Text = "## Task log\n\n" + fence(log)
+	}
+	return run
+}
+
+// VerificationCheckRun reports the end-of-run verification of the merged
+// base branch.
+func VerificationCheckRun(name, sha string, v *state.Verification) CheckRun {
+	run := CheckRun{Name: name, SHA: sha, Status: CheckCompleted, Conclusion: CheckSuccess,
+		Title: "Integrated base branch passes"}
+	if !v.Passed {
+		run.Conclusion, run.Title = CheckFailure, "Integrated base branch fails verification"
+	}
+	var b strings.Builder
+	b.WriteString("| Step | Command | Result | Time |\n|---|---|---|---|\n")
+	for _, s := range v.Steps {
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %.1fs |\n", s.Name, s.Command, passMark(s.Passed), s.Duration)
+	}
+	run.Summary = b.String()
+	return run
+}
+
+func passMark(passed bool) string {
+	if passed {
+		return "✅"
+	}
+	return "❌"
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// mockCheckPublisher records published check runs and the IDs updated.
+type mockCheckPublisher struct {
+	runs []CheckRun
+	ids  []int64
+}
+
+func (m *mockCheckPublisher) PublishCheck(_ context.Context, id int64, run CheckRun) (int64, error) {
+	m.runs = append(m.runs, run)
+	m.ids = append(m.ids, id)
+	if id == 0 {
+		id = int64(100 + len(m.runs))
+	}
+	return id, nil
+}
+
+func TestTaskCheckRun(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-003", Title: "Add search"}
+	checks := []CheckResult{{Name: "tests", Command: "go test ./...", Passed: true, Duration: 2.5}}
+	log := strings.Repeat("noise\n", 2000) + "PASS ok search"
+
+	run := TaskCheckRun(task, "abc123", checks, 2, log)
+	if run.Name != "forge: task-003" || run.Status != CheckCompleted || run.Conclusion != CheckSuccess {
+		t.Errorf("run = %+v", run)
+	}
+	if !strings.Contains(run.Summary, "passed on attempt 2") || !strings.Contains(run.Summary, "| tests | `go test ./...` | ✅ | 2.5s |") {
+		t.Errorf("summary =\n%s", run.Summary)
+	}
+	if !strings.HasSuffix(strings.TrimSuffix(run.Text, "\n```"), "PASS ok search") || len(run.Text) > maxCheckText+100 {
+		t.Errorf("text should end with the log tail, got %d bytes", len(run.Text))
+	}
+}
+
+func TestVerificationCheckRun(t *testing.T) {
+	t.Parallel()
+	v := &state.Verification{Passed: false, Steps: []state.VerificationStep{
+		{Name: "build", Command: "go build ./...", Passed: true},
+		{Name: "test", Command: "go test ./...", Passed: false, Duration: 4},
+	}}
+	run := VerificationCheckRun("forge: verification", "def456", v)
+	if run.Conclusion != CheckFailure || !strings.Contains(run.Summary, "| test | `go test ./...` | ❌ | 4.0s |") {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestCommitStatus(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		run  CheckRun
+		want string
+	}{
+		{CheckRun{Status: CheckInProgress}, "pending"},
+		{CheckRun{Status: CheckCompleted, Conclusion: CheckSuccess}, "success"},
+		{CheckRun{Status: CheckCompleted, Conclusion: CheckFailure}, "failure"},
+	}
+	for _, tt := range tests {
+		if got := CommitStatus(tt.run)["state"]; got != tt.want {
+			t.Errorf("CommitStatus(%s/%s) state = %q, want %q", tt.run.Status, tt.run.Conclusion, got, tt.want)
+		}
+	}
+	long := CommitStatus(CheckRun{Name: "forge: task-001", Title: strings.Repeat("x", 200)})
+	if len([]rune(long["description"])) > maxStatusDescription || long["context"] != "forge: task-001" {
+		t.Errorf("status = %v", long)
+	}
+}
+
+func TestRunTask_PublishesCheckRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		publish  bool
+		remote   string
+		wantRuns int
+	}{
+		{"github remote", true, "git@github.com:acme/app.git", 2},
+		{"setting off", false, "git@github.com:acme/app.git", 0},
+		{"not github", true, "git@gitlab.com:acme/app.git", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+			s.Settings.PublishChecks = tt.publish
+			checks := &mockCheckPublisher{}
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(), RemoteURL: tt.remote,
+				Git: NewMockGitOps(), Tests: NewMockTestRunner(),
+				Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+				Checks:  checks,
+				OnEvent: func(TaskEvent) {},
+			})
+			if outcome := runner.RunTask(context.Background(), &s.Tasks[0]); outcome.Status != state.TaskDone {
+				t.Fatalf("status = %q (%s), want done", outcome.Status, outcome.Error)
+			}
+			if len(checks.runs) != tt.wantRuns {
+				t.Fatalf("published %d check runs, want %d", len(checks.runs), tt.wantRuns)
+			}
+			if tt.wantRuns == 0 {
+				return
+			}
+			if checks.runs[0].Status != CheckInProgress || checks.runs[1].Conclusion != CheckSuccess {
+				t.Errorf("statuses = %s then %s/%s", checks.runs[0].Status, checks.runs[1].Status, checks.runs[1].Conclusion)
+			}
+			if checks.ids[1] != 101 || checks.runs[1].SHA != "abc123def456" {
+				t.Errorf("completion should update the in-progress run on the commit: id %d, sha %s", checks.ids[1], checks.runs[1].SHA)
+			}
+		})
+	}
+}
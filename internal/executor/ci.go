@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ciTimeout     = 30 * time.Minute // longest forge waits for a pushed commit's CI
+	ciPollEvery   = 20 * time.Second
+	ciNoRunsGrace = 2 * time.Minute // no runs by then: the branch has no CI
+	maxCIOutput   = 8000            // failed job logs kept, per run
+)
+
+// CIRun is one workflow run for a pushed commit, as `gh run list` reports
+// it.
+type CIRun struct {
+	ID         int64  `json:"databaseId"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // queued, in_progress, completed
+	Conclusion string `json:"conclusion"` // success, failure, cancelled, skipped, ...
+	URL        string `json:"url"`
+}
+
+// Passed reports whether the run finished without failing.
+func (c CIRun) Passed() bool {
+	switch c.Conclusion {
+	case "success", "skipped", "neutral":
+		return true
+	}
+	return false
+}
+
+// CIResult is the outcome of every CI run for a commit.
+type CIResult struct {
+	Runs   []CIRun
+	Output string // failed jobs' logs, for the retry prompt
+}
+
+// Failed lists the runs that did not pass.
+func (c CIResult) Failed() []CIRun {
+	var failed []CIRun
+	for _, run := range c.Runs {
+		if !run.Passed() {
+			failed = append(failed, run)
+		}
+	}
+	return failed
+}
+
+// Passed reports whether every run passed. A commit without CI passes.
+func (c CIResult) Passed() bool {
+	return len(c.Failed()) == 0
+}
+
+// CIWatcher waits for the remote CI of a pushed commit.
+type CIWatcher interface {
+	// WaitForCI blocks until every CI run for sha on branch has completed
+	// and returns them, with the failed jobs' logs. dir is the repository.
+	WaitForCI(ctx context.Context, dir, branch, sha string) (CIResult, error)
+}
+
+// GhCIWatcher polls GitHub Actions with `gh run list`.
+type GhCIWatcher struct {
+	poll  time.Duration
+	grace time.Duration
+}
+
+// NewGhCIWatcher creates a CIWatcher for GitHub Actions.
+func NewGhCIWatcher() *GhCIWatcher {
+	return &GhCIWatcher{poll: ciPollEvery, grace: ciNoRunsGrace}
+}
+
+// WaitForCI polls until the commit's runs complete. Workflows take a few
+// seconds to be queued after a push, so no runs at all only counts as "no
+// CI" once the grace period is over.
+func (g *GhCIWatcher) WaitForCI(ctx context.Context, dir, branch, sha string) (CIResult, error) {
+	started := time.Now()
+	for {
+		out, err := runGh(ctx, dir, "run", "list", "--branch", branch, "--commit", sha,
+			"--json", "databaseId,name,status,conclusion,url")
+		if err != nil {
+			return CIResult{}, err
+		}
+		var runs []CIRun
+		if err := json.Unmarshal([]byte(out), &runs); err != nil {
+			return CIResult{}, fmt.Errorf("parsing gh run list: %w", err)
+		}
+		if ciDone(runs) && (len(runs) > 0 || time.Since(started) >= g.grace) {
+			result := CIResult{Runs: runs}
+			for _, run := range result.Failed() {
+				logs, _ := runGh(ctx, dir, "run", "view", strconv.FormatInt(run.ID, 10), "--log-failed")
+				result.Output += FormatCIRunOutput(run, logs)
+			}
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return CIResult{}, ctx.Err()
+		case <-time.After(g.poll):
+		}
+	}
+}
+
+// ciDone reports whether no run is still queued or in progress.
+func ciDone(runs []CIRun) bool {
+	for _, run := range runs {
+		if run.Status != "completed" {
+			return false
+		}
+	}
+	return true
+}
+
+func runGh(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	if err != nil {
+		return output, fmt.Errorf("gh %s %s: %s: %w", args[0], args[1], output, err)
+	}
+	return output, nil
+}
+
+// FormatCIRunOutput heads a failed run's logs with its name, conclusion
+// and URL, keeping the end of long logs where the errors are.
+func FormatCIRunOutput(run CIRun, logs string) string {
+	out := fmt.Sprintf("=== %s: %s (%s) ===\n", run.Name, run.Conclusion, run.URL)
+	if len(logs) > maxCIOutput {
+		logs = "...\n" + strings.ToValidUTF8(logs[len(logs)-maxCIOutput:], "")
+	}
+	if logs != "" {
+		out += logs + "\n"
+	}
+	return out + "\n"
+}
+
+// FormatCIFailure names the failed runs for the event log.
+func FormatCIFailure(result CIResult) string {
+	var names []string
+	for _, run := range result.Failed() {
+		names = append(names, run.Name+" ("+run.Conclusion+")")
+	}
+	return "CI failed: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// mockCIWatcher returns results in order, recording the commits it was
+// asked about.
+type mockCIWatcher struct {
+	results []CIResult
+	shas    []string
+}
+
+func (m *mockCIWatcher) WaitForCI(_ context.Context, _, _, sha string) (CIResult, error) {
+	m.shas = append(m.shas, sha)
+	if len(m.shas) > len(m.results) {
+		return CIResult{}, nil
+	}
+	return m.results[len(m.shas)-1], nil
+}
+
+func TestCIResult_Passed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		runs []CIRun
+		want bool
+	}{
+		{"no CI", nil, true},
+		{"all green", []CIRun{{Name: "test", Conclusion: "success"}, {Name: "deploy", Conclusion: "skipped"}}, true},
+		{"one failed", []CIRun{{Name: "test", Conclusion: "success"}, {Name: "lint", Conclusion: "failure"}}, false},
+		{"cancelled", []CIRun{{Name: "test", Conclusion: "cancelled"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := (CIResult{Runs: tt.runs}).Passed(); got != tt.want {
+				t.Errorf("Passed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCIRunOutput(t *testing.T) {
+	t.Parallel()
+	run := CIRun{Name: "lint", Conclusion: "failure", URL: "https://github.com/acme/app/actions/runs/7"}
+	logs := strings.Repeat("setup\n", 3000) + "main.go:3: unused import"
+	got := FormatCIRunOutput(run, logs)
+	if !strings.HasPrefix(got, "=== lint: failure (https://github.com/acme/app/actions/runs/7) ===\n...\n") {
+		t.Errorf("header missing:\n%.200s", got)
+	}
+	if !strings.Contains(got, "unused import") || len(got) > maxCIOutput+200 {
+		t.Errorf("should keep the end of the log, got %d bytes", len(got))
+	}
+	if got := FormatCIFailure(CIResult{Runs: []CIRun{run, {Name: "test", Conclusion: "success"}}}); got != "CI failed: lint (failure)" {
+		t.Errorf("FormatCIFailure() = %q", got)
+	}
+}
+
+func TestRunTask_CIFailureRetries(t *testing.T) {
+	t.Parallel()
+	s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+	s.Settings.WaitForCI = true
+	s.Settings.MaxRetries = 1
+	ci := &mockCIWatcher{results: []CIResult{
+		{Runs: []CIRun{{Name: "lint", Status: "completed", Conclusion: "failure"}}, Output: "main.go:3: unused import"},
+		{Runs: []CIRun{{Name: "lint", Status: "completed", Conclusion: "success"}}},
+	}}
+	claude := NewMockClaudeExecutor(&ExecuteResult{Text: "done"}, &ExecuteResult{Text: "fixed"})
+	var events []TaskEventType
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: t.TempDir(), RemoteURL: "https://github.com/acme/app",
+		Git: NewMockGitOps(), Tests: NewMockTestRunner(), Claude: claude, CI: ci,
+		OnEvent: func(e TaskEvent) { events = append(events, e.Type) },
+	})
+
+	outcome := runner.RunTask(context.Background(), &s.Tasks[0])
+	if outcome.Status != state.TaskDone || outcome.Retries != 1 {
+		t.Fatalf("outcome = %q after %d retries (%s), want done after 1", outcome.Status, outcome.Retries, outcome.Error)
+	}
+	if len(ci.shas) != 2 {
+		t.Errorf("waited for CI %d times, want 2", len(ci.shas))
+	}
+	if prompt := claude.Calls[1].Prompt; !strings.Contains(prompt, "CI failed") || !strings.Contains(prompt, "unused import") {
+		t.Errorf("retry prompt should carry the CI logs:\n%s", prompt)
+	}
+	if !slices.Contains(events, EventCIFailed) || !slices.Contains(events, EventCIPassed) {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestRunTask_CIExhaustedRetries(t *testing.T) {
+	t.Parallel()
+	s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+	s.Settings.WaitForCI = true
+	failed := CIResult{Runs: []CIRun{{Name: "test", Status: "completed", Conclusion: "failure"}}, Output: "FAIL"}
+	ci := &mockCIWatcher{results: []CIResult{failed, failed, failed, failed}}
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: t.TempDir(), RemoteURL: "git@github.com:acme/app.git",
+		Git: NewMockGitOps(), Tests: NewMockTestRunner(), CI: ci,
+		Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "a"}, &ExecuteResult{Text: "b"}, &ExecuteResult{Text: "c"}, &ExecuteResult{Text: "d"}),
+		OnEvent: func(TaskEvent) {},
+	})
+	outcome := runner.RunTask(context.Background(), &s.Tasks[0])
+	if outcome.Status != state.TaskFailed || !strings.HasPrefix(outcome.Error, "CI still failing") {
+		t.Errorf("outcome = %q (%s), want failed with CI still failing", outcome.Status, outcome.Error)
+	}
+}
+
+func TestRunTask_CIOffOrNotGitHub(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		wait   bool
+		remote string
+	}{
+		{"setting off", false, "https://github.com/acme/app"},
+		{"not github", true, "https://gitlab.com/acme/app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+			s.Settings.WaitForCI = tt.wait
+			ci := &mockCIWatcher{}
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(), RemoteURL: tt.remote,
+				Git: NewMockGitOps(), Tests: NewMockTestRunner(), CI: ci,
+				Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+				OnEvent: func(TaskEvent) {},
+			})
+			if outcome := runner.RunTask(context.Background(), &s.Tasks[0]); outcome.Status != state.TaskDone {
+				t.Fatalf("status = %q (%s)", outcome.Status, outcome.Error)
+			}
+			if len(ci.shas) != 0 {
+				t.Errorf("waited for CI on %v", ci.shas)
+			}
+		})
+	}
+}
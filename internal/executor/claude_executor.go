@@ -73,12 +73,16 @@ func (e *RealClaudeExecutor) Execute(ctx context.Context, opts ExecuteOpts) (*Ex
 	}
 
 	var fullText strings.Builder
+	var usage streamResult
 	scanner := bufio.NewScanner(stdout)
 	// Set a larger buffer for potentially large output lines
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		if r, ok := parseStreamResult(line); ok {
+			usage = r
+		}
 		text := parseStreamChunk(line)
 		if text != "" {
 			fullText.WriteString(text)
@@ -93,11 +97,35 @@ func (e *RealClaudeExecutor) Execute(ctx context.Context, opts ExecuteOpts) (*Ex
 	}
 
 	return &ExecuteResult{
-		Text:     fullText.String(),
-		Duration: time.Since(start).Seconds(),
+		Text:       fullText.String(),
+		TurnCount:  usage.NumTurns,
+		TokensUsed: usage.Usage.InputTokens + usage.Usage.OutputTokens,
+		CostUSD:    usage.TotalCostUSD,
+		Duration:   time.Since(start).Seconds(),
 	}, nil
 }
 
+// streamResult is the final "result" line of stream-json output.
+type streamResult struct {
+	Type         string  `json:"type"`
+	NumTurns     int     `json:"num_turns"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Usage        struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// parseStreamResult decodes the closing result line, which carries the
+// turn count, token usage and cost of the whole call.
+func parseStreamResult(line string) (streamResult, bool) {
+	var r streamResult
+	if err := json.Unmarshal([]byte(line), &r); err != nil || r.Type != "result" {
+		return streamResult{}, false
+	}
+	return r, true
+}
+
 // parseStreamChunk extracts displayable text from a single line of stream-json output.
 func parseStreamChunk(line string) string {
 	if line == "" {
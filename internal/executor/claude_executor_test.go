@@ -0,0 +1,38 @@
+package executor
+
+import "testing"
+
+func TestParseStreamResult(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		line       string
+		wantOK     bool
+		wantCost   float64
+		wantTokens int
+		wantTurns  int
+	}{
+		{
+			name:       "result line",
+			line:       `{"type":"result","num_turns":4,"total_cost_usd":0.031,"usage":{"input_tokens":1200,"output_tokens":300}}`,
+			wantOK:     true,
+			wantCost:   0.031,
+			wantTokens: 1500,
+			wantTurns:  4,
+		},
+		{name: "assistant line", line: `{"type":"assistant","message":{"content":[]}}`},
+		{name: "garbage", line: `not json`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, ok := parseStreamResult(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if r.TotalCostUSD != tt.wantCost || r.Usage.InputTokens+r.Usage.OutputTokens != tt.wantTokens || r.NumTurns != tt.wantTurns {
+				t.Errorf("parseStreamResult() = %+v", r)
+			}
+		})
+	}
+}
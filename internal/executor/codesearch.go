@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/manasm11/forge/internal/codeindex"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// maxSnippets is how many code search results go into a task prompt.
+const maxSnippets = 5
+
+// refreshIndex brings the code search index up to date with the project
+// before the first task. A failure is reported and the run goes on
+// without code search.
+func (r *Runner) refreshIndex(ctx context.Context) {
+	if r.cfg.Embed == nil {
+		return
+	}
+	idx, _, err := codeindex.Refresh(ctx, r.cfg.StateRoot, codeindex.Model(r.cfg.State.Settings), r.cfg.Embed)
+	if err != nil {
+		r.emit(TaskEvent{Type: EventError, Message: "code search disabled for this run: " + err.Error()})
+		return
+	}
+	r.index = idx
+}
+
+// relatedCode returns the prompt section with the indexed snippets closest
+// to the task. Workspace repositories are not indexed.
+func (r *Runner) relatedCode(ctx context.Context, task *state.Task, rp *repoCtx) string {
+	if r.index == nil || rp.path != "" {
+		return ""
+	}
+	chunks, err := r.index.Search(ctx, taskText(*task), maxSnippets, r.cfg.Embed)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "code search: " + err.Error()})
+		return ""
+	}
+	return codeindex.Section(chunks)
+}
+
+// reindex updates the code search index with the files a task committed,
+// so later tasks find the code it wrote.
+func (r *Runner) reindex(ctx context.Context, task *state.Task, rp *repoCtx, files []string) {
+	if r.index == nil || rp.path != "" || len(files) == 0 {
+		return
+	}
+	_, err := r.index.Update(ctx, rp.dir, files, r.cfg.Embed)
+	if err == nil {
+		err = r.index.Save(r.cfg.StateRoot)
+	}
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "updating the code search index: " + err.Error()})
+	}
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+const (
+	maxDiffFiles = 30 // changed files listed per dependency
+	maxDiffAPIs  = 40 // new public declarations listed per dependency
+)
+
+// DiffSummary condenses what a task committed, for the tasks that build
+// on it: the files it changed and the public declarations it added.
+type DiffSummary struct {
+	Files []string
+	APIs  []string // "path: declaration"
+}
+
+// publicDeclRe matches added lines that declare something other code can
+// use: exported Go identifiers, JS/TS exports, top-level Python functions
+// and classes, Rust pub items and Java/C#/Kotlin public members.
+var publicDeclRe = regexp.MustCompile(`^(?:func (?:\([^)]*\) )?[A-Z]|type [A-Z]|(?:const|var) [A-Z]|export |(?:async )?def [A-Za-z]|class [A-Za-z]|pub (?:async )?(?:fn|struct|enum|trait|type|const|mod) |\s*public )`)
+
+// SummarizeDiff reads the new public declarations out of a unified diff of
+// the given files.
+func SummarizeDiff(files []string, diff string) DiffSummary {
+	s := DiffSummary{Files: files}
+	var file string
+	for _, line := range strings.Split(diff, "\n") {
+		if name, ok := strings.CutPrefix(line, "+++ "); ok {
+			file = strings.TrimPrefix(name, "b/")
+			continue
+		}
+		added, ok := strings.CutPrefix(line, "+")
+		if !ok || file == "/dev/null" || !publicDeclRe.MatchString(added) {
+			continue
+		}
+		decl := strings.TrimSpace(strings.TrimRight(added, " {:"))
+		s.APIs = append(s.APIs, file+": "+decl)
+	}
+	return s
+}
+
+// DependencySection shows a task what its dependencies actually committed
+// in this run, so it builds on the code rather than on the plan's
+// description of it. "" when no dependency finished in this run.
+func DependencySection(task state.Task, tasks []state.Task, diffs map[string]DiffSummary) string {
+	var b strings.Builder
+	for _, id := range task.DependsOn {
+		d, ok := diffs[id]
+		if !ok {
+			continue
+		}
+		title := ""
+		if i := slices.IndexFunc(tasks, func(t state.Task) bool { return t.ID == id }); i >= 0 {
+			title = " — " + tasks[i].Title
+		}
+		fmt.Fprintf(&b, "\n%s%s\n", id, title)
+		writeCapped(&b, "Files changed:", d.Files, maxDiffFiles)
+		writeCapped(&b, "New public APIs:", d.APIs, maxDiffAPIs)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\nDEPENDENCY CHANGES (committed earlier in this run by the tasks this one builds on; read the files for details):\n" + b.String()
+}
+
+func writeCapped(b *strings.Builder, heading string, items []string, limit int) {
+	if len(items) == 0 {
+		return
+	}
+	b.WriteString(heading + "\n")
+	for i, item := range items {
+		if i == limit {
+			fmt.Fprintf(b, "- … and %d more\n", len(items)-limit)
+			break
+		}
+		b.WriteString("- " + item + "\n")
+	}
+}
+
+// summarizeStaged condenses the staged changes of a task that other
+// pending tasks depend on; nil when none does, or the diff can't be read.
+func (r *Runner) summarizeStaged(ctx context.Context, task *state.Task, rp *repoCtx, files []string) *DiffSummary {
+	if !slices.ContainsFunc(r.cfg.State.Tasks, func(t state.Task) bool {
+		return t.Status == state.TaskPending && slices.Contains(t.DependsOn, task.ID)
+	}) {
+		return nil
+	}
+	diff, err := rp.git.StagedDiff(ctx)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "summarizing the diff for dependent tasks: " + err.Error()})
+		return nil
+	}
+	s := SummarizeDiff(files, diff)
+	return &s
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestSummarizeDiff(t *testing.T) {
+	t.Parallel()
+	diff := `diff --git a/store/users.go b/store/users.go
+new file mode 100644
+--- /dev/null
++++ b/store/users.go
+@@ -0,0 +1,12 @@
++package store
++
++type User struct {
++	ID string
++}
++
++func (s *Store) CreateUser(name string) (*User, error) {
++func helper() {}
++const MaxUsers = 100
+diff --git a/web/api.ts b/web/api.ts
+--- a/web/api.ts
++++ b/web/api.ts
+@@ -1,2 +1,3 @@
+-export function old() {}
++export function listUsers(): Promise<User[]> {
++function internal() {}
+`
+	got := SummarizeDiff([]string{"store/users.go", "web/api.ts"}, diff)
+	want := []string{
+		"store/users.go: type User struct",
+		"store/users.go: func (s *Store) CreateUser(name string) (*User, error)",
+		"store/users.go: const MaxUsers = 100",
+		"web/api.ts: export function listUsers(): Promise<User[]>",
+	}
+	if !reflect.DeepEqual(got.APIs, want) {
+		t.Errorf("APIs = %q, want %q", got.APIs, want)
+	}
+}
+
+func TestDependencySection(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Store"},
+		{ID: "task-002", Title: "Docs"},
+		{ID: "task-003", Title: "API", DependsOn: []string{"task-001", "task-002"}},
+	}
+	diffs := map[string]DiffSummary{
+		"task-001": {Files: []string{"store/users.go"}, APIs: []string{"store/users.go: type User struct"}},
+	}
+	got := DependencySection(tasks[2], tasks, diffs)
+	for _, want := range []string{"DEPENDENCY CHANGES", "task-001 — Store\nFiles changed:\n- store/users.go\nNew public APIs:\n- store/users.go: type User struct\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DependencySection() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "task-002") {
+		t.Errorf("DependencySection() lists a dependency that didn't run:\n%s", got)
+	}
+	if got := DependencySection(tasks[2], tasks, nil); got != "" {
+		t.Errorf("DependencySection(no diffs) = %q, want \"\"", got)
+	}
+}
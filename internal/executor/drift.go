@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Base drift policies (Settings.BaseDrift). Empty means off.
+const (
+	DriftBranch = "branch" // fast-forward the base branch, so new task branches start from it
+	DriftRebase = "rebase" // also rebase local base commits and existing task branches onto it, rewriting them
+)
+
+// DriftPolicies lists the values accepted for Settings.BaseDrift.
+var DriftPolicies = []string{"off", DriftBranch, DriftRebase}
+
+// ValidDriftPolicy reports whether policy is empty or one of DriftPolicies.
+func ValidDriftPolicy(policy string) bool {
+	if policy == "" {
+		return true
+	}
+	for _, p := range DriftPolicies {
+		if policy == p {
+			return true
+		}
+	}
+	return false
+}
+
+const maxUpstreamFiles = 30 // listed in the task prompt
+
+// BaseDrift is what the remote base branch gained that the local one
+// lacks.
+type BaseDrift struct {
+	Upstream string   // "origin/main"
+	Behind   int      // commits
+	Ahead    int      // local commits the upstream lacks; a rebase rewrites them
+	Files    []string // changed upstream
+}
+
+// UpstreamSection tells Claude which files changed upstream since the
+// work it may remember; "" when nothing did.
+func UpstreamSection(drift *BaseDrift) string {
+	if drift == nil || len(drift.Files) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nUPSTREAM CHANGES:\n%d new commit(s) on %s were pulled in before this task. These files changed upstream; re-read them before editing:\n",
+		drift.Behind, drift.Upstream)
+	for i, f := range drift.Files {
+		if i == maxUpstreamFiles {
+			fmt.Fprintf(&b, "- … and %d more\n", len(drift.Files)-maxUpstreamFiles)
+			break
+		}
+		b.WriteString("- " + f + "\n")
+	}
+	return b.String()
+}
+
+// syncBase brings the base branch (checked out) up to date with its remote
+// before a task branches from it, per Settings.BaseDrift. It returns what
+// was pulled in, or nil. The event says when local commits were rewritten. Failing to sync is reported and the task goes on
+// from the local base.
+func (r *Runner) syncBase(ctx context.Context, task *state.Task, rp *repoCtx, baseBranch string) *BaseDrift {
+	policy := rp.settings.BaseDrift
+	if policy == "" || policy == "off" || rp.remoteURL == "" {
+		return nil
+	}
+	drift, err := rp.git.FetchBase(ctx, baseBranch)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: rp.label() + "checking the base branch for upstream changes: " + err.Error()})
+		return nil
+	}
+	if drift.Behind == 0 {
+		return nil
+	}
+	if err := rp.git.SyncBranch(ctx, drift.Upstream, policy == DriftRebase); err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: fmt.Sprintf(
+			"%s%s is %d commit(s) behind %s but could not be updated: %v", rp.label(), baseBranch, drift.Behind, drift.Upstream, err)})
+		return nil
+	}
+	msg := fmt.Sprintf("%s%s updated with %d upstream commit(s)", rp.label(), baseBranch, drift.Behind)
+	if policy == DriftRebase && drift.Ahead > 0 {
+		msg += fmt.Sprintf("; its %d local commit(s) were rebased onto %s and have new SHAs", drift.Ahead, drift.Upstream)
+	}
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventBaseDrift, Message: msg, Detail: strings.Join(drift.Files, "\n")})
+	return &drift
+}
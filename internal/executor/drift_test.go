@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestUpstreamSection(t *testing.T) {
+	t.Parallel()
+	if got := UpstreamSection(nil); got != "" {
+		t.Errorf("UpstreamSection(nil) = %q", got)
+	}
+	got := UpstreamSection(&BaseDrift{Upstream: "origin/main", Behind: 3, Files: []string{"api/routes.go", "go.mod"}})
+	for _, want := range []string{"3 new commit(s) on origin/main", "- api/routes.go\n- go.mod\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("UpstreamSection() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidDriftPolicy(t *testing.T) {
+	t.Parallel()
+	for policy, want := range map[string]bool{"": true, "off": true, "branch": true, "rebase": true, "merge": false} {
+		if got := ValidDriftPolicy(policy); got != want {
+			t.Errorf("ValidDriftPolicy(%q) = %v, want %v", policy, got, want)
+		}
+	}
+}
+
+func TestRunTask_BaseDrift(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		policy       string
+		behind       int
+		branchExists bool
+		wantSyncs    []string
+		wantContext  bool
+	}{
+		{"off", "", 2, false, nil, false},
+		{"up to date", DriftBranch, 0, false, nil, false},
+		{"fast-forward before branching", DriftBranch, 2, false, []string{"origin/main"}, true},
+		{"rebase an existing task branch", DriftRebase, 2, true, []string{"rebase:origin/main", "rebase:main"}, true},
+		{"branch policy leaves existing branches", DriftBranch, 2, true, []string{"origin/main"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+			s.Settings.BaseDrift = tt.policy
+			git := NewMockGitOps()
+			git.FetchBaseResult = BaseDrift{Behind: tt.behind, Files: []string{"api/routes.go"}}
+			git.BranchExistsResult["forge/task-001"] = tt.branchExists
+			claude := NewMockClaudeExecutor(&ExecuteResult{Text: "done"})
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(), RemoteURL: "git@github.com:acme/app.git",
+				Git: git, Tests: NewMockTestRunner(), Claude: claude,
+				OnEvent: func(TaskEvent) {},
+			})
+			if outcome := runner.RunTask(context.Background(), &s.Tasks[0]); outcome.Status != state.TaskDone {
+				t.Fatalf("status = %q (%s)", outcome.Status, outcome.Error)
+			}
+			if !reflect.DeepEqual(git.SyncBranchCalls, tt.wantSyncs) {
+				t.Errorf("SyncBranch calls = %v, want %v", git.SyncBranchCalls, tt.wantSyncs)
+			}
+			if got := strings.Contains(claude.Calls[0].Prompt, "UPSTREAM CHANGES"); got != tt.wantContext {
+				t.Errorf("prompt has upstream changes = %v, want %v", got, tt.wantContext)
+			}
+		})
+	}
+}
+
+func TestRunTask_BaseDriftRebaseReportsRewrites(t *testing.T) {
+	t.Parallel()
+	s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+	s.Settings.BaseDrift = DriftRebase
+	git := NewMockGitOps()
+	git.FetchBaseResult = BaseDrift{Behind: 2, Ahead: 1}
+	git.BranchExistsResult["forge/task-001"] = true
+	var drifts []string
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: t.TempDir(), RemoteURL: "git@github.com:acme/app.git",
+		Git: git, Tests: NewMockTestRunner(), Claude: NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+		OnEvent: func(e TaskEvent) {
+			if e.Type == EventBaseDrift {
+				drifts = append(drifts, e.Message)
+			}
+		},
+	})
+	runner.RunTask(context.Background(), &s.Tasks[0])
+	want := []string{
+		"main updated with 2 upstream commit(s); its 1 local commit(s) were rebased onto origin/main and have new SHAs",
+		"forge/task-001 rebased onto main; its commits have new SHAs",
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Errorf("base drift events = %q, want %q", drifts, want)
+	}
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// EnvRecord captures the machine a task ran on, so agent runs can be
+// compared later when results differ between machines.
+type EnvRecord struct {
+	OS    string            `json:"os"`
+	Arch  string            `json:"arch"`
+	Tools map[string]string `json:"tools"`         // tool name -> version line
+	Env   map[string]string `json:"env,omitempty"` // selected variables, secrets redacted
+}
+
+// toolVersionCommands lists the tools whose versions are recorded.
+var toolVersionCommands = map[string][]string{
+	"go":     {"go", "version"},
+	"node":   {"node", "--version"},
+	"claude": {"claude", "--version"},
+	"git":    {"git", "--version"},
+}
+
+// capturedEnvVars are recorded from the process environment when set.
+var capturedEnvVars = []string{
+	"PATH", "SHELL", "LANG", "GOPATH", "GOFLAGS", "GOTOOLCHAIN",
+	"NODE_ENV", "VIRTUAL_ENV", "CI",
+}
+
+const redacted = "[redacted]"
+
+// CaptureToolVersions runs each tool's version command. Missing tools are
+// recorded as "not found" rather than omitted, since absence matters too.
+func CaptureToolVersions(ctx context.Context) map[string]string {
+	versions := make(map[string]string, len(toolVersionCommands))
+	for name, args := range toolVersionCommands {
+		versions[name] = toolVersion(ctx, args)
+	}
+	return versions
+}
+
+func toolVersion(ctx context.Context, args []string) string {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "not found"
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
+
+// BuildEnvRecord assembles an EnvRecord from tool versions, the process
+// environment and the variables forge passes to the agent.
+func BuildEnvRecord(tools map[string]string, getenv func(string) string, agentEnv map[string]string) EnvRecord {
+	env := make(map[string]string)
+	for _, k := range capturedEnvVars {
+		if v := getenv(k); v != "" {
+			env[k] = v
+		}
+	}
+	for k, v := range agentEnv {
+		env[k] = v
+	}
+	for k := range env {
+		if IsSecretEnvName(k) {
+			env[k] = redacted
+		}
+	}
+	return EnvRecord{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Tools: tools,
+		Env:   env,
+	}
+}
+
+// IsSecretEnvName reports whether a variable name looks like a credential.
+func IsSecretEnvName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range []string{"TOKEN", "SECRET", "KEY", "PASSWORD", "PASSWD", "AUTH", "CREDENTIAL"} {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
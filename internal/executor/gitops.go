@@ -3,7 +3,11 @@ package executor
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -18,16 +22,30 @@ func NewRealGitOps(dir string) *RealGitOps {
 }
 
 func (g *RealGitOps) run(ctx context.Context, args ...string) (string, error) {
+	return g.runEnv(ctx, nil, args...)
+}
+
+// runEnv runs git with extra "KEY=value" environment variables.
+func (g *RealGitOps) runEnv(ctx context.Context, env []string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = g.dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	out, err := cmd.CombinedOutput()
-	output := strings.TrimSpace(string(out))
+	output := gitOutput(out)
 	if err != nil {
 		return output, fmt.Errorf("git %s: %s: %w", args[0], output, err)
 	}
 	return output, nil
 }
 
+// gitOutput trims git's output and normalizes Windows line endings so
+// callers can split on "\n".
+func gitOutput(out []byte) string {
+	return strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+}
+
 func (g *RealGitOps) CurrentBranch(ctx context.Context) (string, error) {
 	return g.run(ctx, "rev-parse", "--abbrev-ref", "HEAD")
 }
@@ -52,9 +70,60 @@ func (g *RealGitOps) BranchExists(ctx context.Context, name string) (bool, error
 	return true, nil
 }
 
+// StageAll stages every change except to submodules: forge never commits
+// into vendored code, so changed submodules are unstaged again and
+// reported as a *SubmoduleChangeError.
 func (g *RealGitOps) StageAll(ctx context.Context) error {
-	_, err := g.run(ctx, "add", "-A")
-	return err
+	if _, err := g.run(ctx, "add", "-A"); err != nil {
+		return err
+	}
+	subs := g.submodules(ctx)
+	if len(subs) == 0 {
+		return nil
+	}
+	// Lists both moved submodule commits and uncommitted edits inside them.
+	out, err := g.run(ctx, append([]string{"diff", "--name-only", "HEAD", "--"}, subs...)...)
+	if err != nil || out == "" {
+		return nil
+	}
+	changed := strings.Split(out, "\n")
+	if _, err := g.run(ctx, append([]string{"reset", "-q", "--"}, changed...)...); err != nil {
+		return err
+	}
+	return &SubmoduleChangeError{Paths: changed}
+}
+
+// hasCommitHooks reports whether a hook runs on commit, in .git/hooks or
+// wherever core.hooksPath points (husky).
+func (g *RealGitOps) hasCommitHooks(ctx context.Context) bool {
+	dir, err := g.run(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(g.dir, dir)
+	}
+	for _, hook := range commitHooks {
+		if info, err := os.Stat(filepath.Join(dir, hook)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// submodules lists the submodule paths declared in .gitmodules.
+func (g *RealGitOps) submodules(ctx context.Context) []string {
+	out, err := g.run(ctx, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil || out == "" {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(out, "\n") {
+		if _, path, ok := strings.Cut(line, " "); ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
 }
 
 func (g *RealGitOps) HasStagedChanges(ctx context.Context) (bool, bool, error) {
@@ -67,6 +136,18 @@ func (g *RealGitOps) HasStagedChanges(ctx context.Context) (bool, bool, error) {
 	return hasStaged, hasUnstaged, nil
 }
 
+func (g *RealGitOps) StagedFiles(ctx context.Context) ([]string, error) {
+	out, err := g.run(ctx, "diff", "--cached", "--name-only", "--diff-filter=d")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+func (g *RealGitOps) StagedDiff(ctx context.Context) (string, error) {
+	return g.run(ctx, "diff", "--cached")
+}
+
 func (g *RealGitOps) HasUnstagedChanges(ctx context.Context) (bool, error) {
 	out, err := g.run(ctx, "status", "--porcelain")
 	if err != nil {
@@ -75,8 +156,49 @@ func (g *RealGitOps) HasUnstagedChanges(ctx context.Context) (bool, error) {
 	return out != "", nil
 }
 
-func (g *RealGitOps) Commit(ctx context.Context, message string) (string, error) {
-	if _, err := g.run(ctx, "commit", "-m", message); err != nil {
+func (g *RealGitOps) DirtyFiles(ctx context.Context) ([]string, error) {
+	// Without a first commit everything tracked is staged
+	changed, err := g.run(ctx, "diff", "--name-only", "HEAD")
+	if err != nil {
+		if changed, err = g.run(ctx, "diff", "--cached", "--name-only"); err != nil {
+			return nil, err
+		}
+	}
+	untracked, err := g.run(ctx, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(changed+"\n"+untracked, "\n") {
+		if f != "" && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (g *RealGitOps) Stash(ctx context.Context, message string, paths []string) error {
+	_, err := g.run(ctx, append([]string{"stash", "push", "--include-untracked", "-m", message, "--"}, paths...)...)
+	return err
+}
+
+func (g *RealGitOps) Commit(ctx context.Context, message string, opts CommitOptions) (string, error) {
+	args := []string{"commit", "-m", message}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if opts.SignOff {
+		args = append(args, "--signoff")
+	}
+	var env []string
+	if name, email, ok := ParseIdentity(opts.Identity); ok {
+		env = []string{"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email,
+			"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email}
+	}
+	if out, err := g.runEnv(ctx, env, args...); err != nil {
+		if !opts.NoVerify && g.hasCommitHooks(ctx) {
+			return "", &HookError{Output: out, Err: err}
+		}
 		return "", err
 	}
 	sha, err := g.run(ctx, "rev-parse", "HEAD")
@@ -91,6 +213,47 @@ func (g *RealGitOps) Push(ctx context.Context) error {
 	return err
 }
 
+func (g *RealGitOps) FetchBase(ctx context.Context, branch string) (BaseDrift, error) {
+	// Track whatever branch@{u} names; a base without one follows origin
+	remote, ref := "origin", "refs/heads/"+branch
+	drift := BaseDrift{Upstream: "origin/" + branch}
+	if upstream, err := g.run(ctx, "rev-parse", "--abbrev-ref", branch+"@{u}"); err == nil {
+		drift.Upstream = upstream
+		remote, _ = g.run(ctx, "config", "--get", "branch."+branch+".remote")
+		ref, _ = g.run(ctx, "config", "--get", "branch."+branch+".merge")
+	}
+	if _, err := g.run(ctx, "fetch", remote, ref); err != nil {
+		return drift, err
+	}
+	count, err := g.run(ctx, "rev-list", "--count", branch+".."+drift.Upstream)
+	if err != nil {
+		return drift, err
+	}
+	if drift.Behind, err = strconv.Atoi(count); err != nil || drift.Behind == 0 {
+		return drift, err
+	}
+	if count, err := g.run(ctx, "rev-list", "--count", drift.Upstream+".."+branch); err == nil {
+		drift.Ahead, _ = strconv.Atoi(count)
+	}
+	files, err := g.run(ctx, "diff", "--name-only", branch+"..."+drift.Upstream)
+	if err == nil && files != "" {
+		drift.Files = strings.Split(files, "\n")
+	}
+	return drift, err
+}
+
+func (g *RealGitOps) SyncBranch(ctx context.Context, upstream string, rebase bool) error {
+	if !rebase {
+		_, err := g.run(ctx, "merge", "--ff-only", upstream)
+		return err
+	}
+	if _, err := g.run(ctx, "rebase", upstream); err != nil {
+		g.run(ctx, "rebase", "--abort")
+		return err
+	}
+	return nil
+}
+
 func (g *RealGitOps) Merge(ctx context.Context, branch string) error {
 	_, err := g.run(ctx, "merge", "--no-ff", branch)
 	return err
@@ -109,3 +272,8 @@ func (g *RealGitOps) DeleteBranch(ctx context.Context, name string) error {
 	_, err := g.run(ctx, "branch", "-D", name)
 	return err
 }
+
+func (g *RealGitOps) SparseCheckout(ctx context.Context, paths []string) error {
+	_, err := g.run(ctx, append([]string{"sparse-checkout", "set", "--cone"}, paths...)...)
+	return err
+}
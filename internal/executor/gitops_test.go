@@ -4,10 +4,16 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/manasm11/forge/internal/state"
 )
 
 // These tests use real git operations in temp directories.
@@ -37,6 +43,51 @@ func TestRealGitOps_CreateAndCheckoutBranch(t *testing.T) {
 	}
 }
 
+func TestGitOutput(t *testing.T) {
+	t.Parallel()
+	// git for Windows ends lines with CRLF
+	if got := gitOutput([]byte("a.go\r\ndocs/b c.md\r\n")); got != "a.go\ndocs/b c.md" {
+		t.Errorf("gitOutput() = %q", got)
+	}
+}
+
+func TestRealGitOps_PathsAndQuotingNeedNoShell(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+
+	// Spaces and characters cmd.exe and sh would interpret reach git as is
+	os.MkdirAll(filepath.Join(dir, "my docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "my docs", "a & b.txt"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "100%.txt"), []byte("x"), 0644)
+	files, err := g.DirtyFiles(ctx)
+	if err != nil {
+		t.Fatalf("DirtyFiles error: %v", err)
+	}
+	if want := []string{"100%.txt", "my docs/a & b.txt"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("DirtyFiles() = %q, want %q", files, want)
+	}
+
+	if err := g.Stash(ctx, `forge: "wip" ^ 50% & more`, []string{"my docs/a & b.txt"}); err != nil {
+		t.Fatalf("Stash error: %v", err)
+	}
+	if files, _ := g.DirtyFiles(ctx); !reflect.DeepEqual(files, []string{"100%.txt"}) {
+		t.Errorf("after stash, dirty = %q, want only 100%%.txt", files)
+	}
+
+	message := `Add "100%" report & fix ^ caret`
+	if err := g.StageAll(ctx); err != nil {
+		t.Fatalf("StageAll error: %v", err)
+	}
+	if _, err := g.Commit(ctx, message, CommitOptions{}); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	if got, _ := g.run(ctx, "log", "-1", "--format=%s"); got != message {
+		t.Errorf("commit subject = %q, want %q", got, message)
+	}
+}
+
 func TestRealGitOps_StageCommit(t *testing.T) {
 	t.Parallel()
 	dir := initTestRepo(t)
@@ -58,7 +109,7 @@ func TestRealGitOps_StageCommit(t *testing.T) {
 		t.Error("should have staged changes")
 	}
 
-	sha, err := g.Commit(ctx, "add hello.go")
+	sha, err := g.Commit(ctx, "add hello.go", CommitOptions{})
 	if err != nil {
 		t.Fatalf("Commit error: %v", err)
 	}
@@ -184,3 +235,248 @@ func run(t *testing.T, dir string, name string, args ...string) {
 		t.Fatalf("%s %v failed: %v\n%s", name, args, err, out)
 	}
 }
+
+func TestRealGitOps_StageAllRefusesSubmoduleChanges(t *testing.T) {
+	t.Parallel()
+	lib := initTestRepo(t)
+	dir := initTestRepo(t)
+	run(t, dir, "git", "-c", "protocol.file.allow=always", "submodule", "add", lib, "lib")
+	run(t, dir, "git", "commit", "-m", "add lib")
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+
+	os.WriteFile(filepath.Join(dir, "lib", "README.md"), []byte("# patched"), 0644)
+	os.WriteFile(filepath.Join(dir, "hello.go"), []byte("package main"), 0644)
+
+	err := g.StageAll(ctx)
+	var subErr *SubmoduleChangeError
+	if !errors.As(err, &subErr) || len(subErr.Paths) != 1 || subErr.Paths[0] != "lib" {
+		t.Fatalf("StageAll error = %v, want a SubmoduleChangeError for lib", err)
+	}
+	files, _ := g.StagedFiles(ctx)
+	if len(files) != 1 || files[0] != "hello.go" {
+		t.Errorf("staged files = %v, want only hello.go", files)
+	}
+}
+
+func TestRealGitOps_SparseCheckout(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	for _, p := range []string{"services/billing/main.go", "services/search/main.go", "libs/common/util.go"} {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0755)
+		os.WriteFile(filepath.Join(dir, p), []byte("package x"), 0644)
+	}
+	run(t, dir, "git", "add", ".")
+	run(t, dir, "git", "commit", "-m", "monorepo")
+	g := NewRealGitOps(dir)
+
+	if err := g.SparseCheckout(context.Background(), []string{"services/billing", "libs/common"}); err != nil {
+		t.Fatalf("SparseCheckout error: %v", err)
+	}
+	for p, want := range map[string]bool{
+		"README.md": true, "services/billing/main.go": true, "libs/common/util.go": true, "services/search/main.go": false,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, p)); (err == nil) != want {
+			t.Errorf("%s present = %v, want %v", p, err == nil, want)
+		}
+	}
+}
+
+func TestRealGitOps_DirtyFilesAndStash(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# edited"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip"), 0644)
+	os.MkdirAll(filepath.Join(dir, ".forge"), 0755)
+	os.WriteFile(filepath.Join(dir, ".forge", "state.json"), []byte("{}"), 0644)
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+
+	files, err := g.DirtyFiles(ctx)
+	if err != nil {
+		t.Fatalf("DirtyFiles error: %v", err)
+	}
+	user := UserChanges(files, nil)
+	if !reflect.DeepEqual(user, []string{"README.md", "notes.txt"}) {
+		t.Fatalf("UserChanges(%v) = %v", files, user)
+	}
+
+	if err := g.Stash(ctx, "forge: test", user); err != nil {
+		t.Fatalf("Stash error: %v", err)
+	}
+	files, _ = g.DirtyFiles(ctx)
+	if !reflect.DeepEqual(files, []string{".forge/state.json"}) {
+		t.Errorf("after stash, dirty = %v, want only forge's state", files)
+	}
+	if out, _ := g.run(ctx, "stash", "list"); !strings.Contains(out, "forge: test") {
+		t.Errorf("stash list = %q", out)
+	}
+}
+
+func TestRealGitOps_FetchBaseAndSync(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	remote := t.TempDir()
+	run(t, remote, "git", "init", "--bare", "-b", "main")
+	run(t, dir, "git", "remote", "add", "origin", remote)
+	run(t, dir, "git", "push", "origin", "main")
+
+	// A teammate pushes while the run is going
+	other := filepath.Join(t.TempDir(), "other")
+	run(t, dir, "git", "clone", remote, other)
+	run(t, other, "git", "config", "user.email", "mate@test.com")
+	run(t, other, "git", "config", "user.name", "Mate")
+	os.WriteFile(filepath.Join(other, "api.go"), []byte("package api"), 0644)
+	run(t, other, "git", "add", ".")
+	run(t, other, "git", "commit", "-m", "add api")
+	run(t, other, "git", "push", "origin", "main")
+
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+	drift, err := g.FetchBase(ctx, "main")
+	if err != nil {
+		t.Fatalf("FetchBase error: %v", err)
+	}
+	want := BaseDrift{Upstream: "origin/main", Behind: 1, Files: []string{"api.go"}}
+	if !reflect.DeepEqual(drift, want) {
+		t.Fatalf("FetchBase() = %+v, want %+v", drift, want)
+	}
+	if err := g.SyncBranch(ctx, drift.Upstream, false); err != nil {
+		t.Fatalf("SyncBranch error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api.go")); err != nil {
+		t.Error("base branch should have been fast-forwarded to include api.go")
+	}
+	if drift, _ := g.FetchBase(ctx, "main"); drift.Behind != 0 {
+		t.Errorf("after sync, behind = %d", drift.Behind)
+	}
+}
+
+func TestRealGitOps_FetchBaseFollowsConfiguredUpstream(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	remote := t.TempDir()
+	run(t, remote, "git", "init", "--bare", "-b", "main")
+	run(t, dir, "git", "remote", "add", "upstream", remote)
+	run(t, dir, "git", "push", "-u", "upstream", "main")
+
+	other := filepath.Join(t.TempDir(), "other")
+	run(t, dir, "git", "clone", remote, other)
+	run(t, other, "git", "config", "user.email", "mate@test.com")
+	run(t, other, "git", "config", "user.name", "Mate")
+	os.WriteFile(filepath.Join(other, "api.go"), []byte("package api"), 0644)
+	run(t, other, "git", "add", ".")
+	run(t, other, "git", "commit", "-m", "add api")
+	run(t, other, "git", "push", "origin", "main")
+
+	// A local base commit the upstream lacks
+	os.WriteFile(filepath.Join(dir, "notes.md"), []byte("local"), 0644)
+	run(t, dir, "git", "add", ".")
+	run(t, dir, "git", "commit", "-m", "local notes")
+
+	drift, err := NewRealGitOps(dir).FetchBase(context.Background(), "main")
+	if err != nil {
+		t.Fatalf("FetchBase error: %v", err)
+	}
+	want := BaseDrift{Upstream: "upstream/main", Behind: 1, Ahead: 1, Files: []string{"api.go"}}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("FetchBase() = %+v, want %+v", drift, want)
+	}
+}
+
+func TestRealGitOps_CommitHookFailure(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	hook := "#!/bin/sh\necho 'eslint: 2 problems in app.js'\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "hooks", "pre-commit"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+	os.WriteFile(filepath.Join(dir, "app.js"), []byte("var x"), 0644)
+	if err := g.StageAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := g.Commit(ctx, "add app.js", CommitOptions{})
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || !strings.Contains(hookErr.Output, "eslint: 2 problems") {
+		t.Fatalf("Commit error = %v, want a HookError with the hook output", err)
+	}
+
+	if sha, err := g.Commit(ctx, "add app.js", CommitOptions{NoVerify: true}); err != nil || sha == "" {
+		t.Errorf("Commit with NoVerify = %q, %v; want the hook skipped", sha, err)
+	}
+}
+
+func TestRealGitOps_StageAllSkipsCollectedArtifacts(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+	writeFiles(t, dir, map[string]string{"bin/app": "binary", "main.go": "package main"})
+
+	if _, err := CollectArtifacts(dir, "task-001", []string{"bin/*"}); err != nil {
+		t.Fatalf("CollectArtifacts error: %v", err)
+	}
+	if err := state.EnsureGitignore(dir); err != nil {
+		t.Fatalf("EnsureGitignore error: %v", err)
+	}
+	if err := g.StageAll(ctx); err != nil {
+		t.Fatalf("StageAll error: %v", err)
+	}
+	staged, err := g.StagedFiles(ctx)
+	if err != nil {
+		t.Fatalf("StagedFiles error: %v", err)
+	}
+	for _, f := range staged {
+		if strings.HasPrefix(f, ".forge/artifacts/") {
+			t.Errorf("staged %s; collected artifacts must stay out of commits", f)
+		}
+	}
+	if !slices.Contains(staged, "main.go") {
+		t.Errorf("staged = %v, want main.go", staged)
+	}
+}
+
+func TestRealGitOps_CommitSignOff(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0644)
+	g.StageAll(ctx)
+
+	msg := AddTrailers("add a.go", "Co-authored-by: Forge Agent <forge-agent@users.noreply.github.com>")
+	if _, err := g.Commit(ctx, msg, CommitOptions{SignOff: true}); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%(trailers:only,unfold)").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Co-authored-by: Forge Agent <forge-agent@users.noreply.github.com>\nSigned-off-by: Test <test@test.com>"
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("trailers = %q, want %q", got, want)
+	}
+}
+
+func TestRealGitOps_CommitIdentity(t *testing.T) {
+	t.Parallel()
+	dir := initTestRepo(t)
+	g := NewRealGitOps(dir)
+	ctx := context.Background()
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0644)
+	g.StageAll(ctx)
+
+	if _, err := g.Commit(ctx, "add a.go", CommitOptions{Identity: "Forge Bot <forge@team.dev>"}); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%an <%ae>|%cn <%ce>").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(out)), "Forge Bot <forge@team.dev>|Forge Bot <forge@team.dev>"; got != want {
+		t.Errorf("author|committer = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/manasm11/forge/internal/preflight"
+)
+
+// ErrPaused is returned by Run when it stopped for a human rather than
+// finishing: see ErrLowDiskSpace, ErrChangeTooLarge, ErrStateConflict and
+// ErrRunLimit.
+var ErrPaused = errors.New("run paused")
+
+var (
+	// ErrLowDiskSpace means free space fell below preflight.MinFreeDisk
+	// before a task could start.
+	ErrLowDiskSpace = fmt.Errorf("%w: low disk space", ErrPaused)
+
+	// ErrChangeTooLarge means a task staged more than Settings.MaxChangeMB.
+	// Its changes are left staged on the task branch for review.
+	ErrChangeTooLarge = fmt.Errorf("%w: change too large to commit", ErrPaused)
+
+	// ErrStateConflict means state.json changed on disk during the run
+	// (another forge, or a pulled commit). The runner stops saving rather
+	// than overwrite it.
+	ErrStateConflict = fmt.Errorf("%w: state changed on disk", ErrPaused)
+
+	// ErrRunLimit means the run lasted Settings.MaxRunDuration or reached
+	// Settings.QuietHours. The run's Checkpoint names the next task.
+	ErrRunLimit = fmt.Errorf("%w: run limit reached", ErrPaused)
+)
+
+// FileSize is one staged file and its size in bytes.
+type FileSize struct {
+	Path string
+	Size int64
+}
+
+// StagedSize sums the working-tree size of the staged files (relative to
+// root) and returns them largest first. Deleted files count as zero.
+func StagedSize(root string, files []string) (int64, []FileSize) {
+	var total int64
+	sizes := make([]FileSize, 0, len(files))
+	for _, f := range files {
+		info, err := os.Lstat(filepath.Join(root, f))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+		sizes = append(sizes, FileSize{Path: f, Size: info.Size()})
+	}
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].Size > sizes[j].Size })
+	return total, sizes
+}
+
+// FormatChangeTooLarge explains an oversized change, naming the biggest
+// files so an accidentally vendored directory stands out.
+func FormatChangeTooLarge(total int64, limitMB int, largest []FileSize) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "staged changes are %s, over the %d MB limit — review before committing", preflight.FormatBytes(uint64(total)), limitMB)
+	if len(largest) > 3 {
+		largest = largest[:3]
+	}
+	for i, f := range largest {
+		if i == 0 {
+			b.WriteString(" (largest: ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s", f.Path, preflight.FormatBytes(uint64(f.Size)))
+	}
+	if len(largest) > 0 {
+		b.WriteString(")")
+	}
+	return b.String()
+}
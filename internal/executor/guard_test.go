@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Staged change size
+// ============================================================
+
+func TestStagedSize(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"main.go":                      strings.Repeat("x", 100),
+		"node_modules/left-pad/big.js": strings.Repeat("x", 5000),
+	})
+
+	total, largest := StagedSize(root, []string{"main.go", "node_modules/left-pad/big.js", "deleted.go"})
+	if total != 5100 {
+		t.Errorf("total = %d, want 5100", total)
+	}
+	if len(largest) != 2 || largest[0].Path != "node_modules/left-pad/big.js" {
+		t.Errorf("largest = %+v, want big.js first", largest)
+	}
+}
+
+func TestFormatChangeTooLarge(t *testing.T) {
+	t.Parallel()
+	largest := []FileSize{
+		{"vendor/a.bin", 80 << 20}, {"vendor/b.bin", 30 << 20}, {"c.go", 2 << 10}, {"d.go", 10},
+	}
+	got := FormatChangeTooLarge(110<<20, 100, largest)
+	for _, want := range []string{"110 MB, over the 100 MB limit", "vendor/a.bin 80 MB", "c.go 2 KB"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatChangeTooLarge() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "d.go") {
+		t.Errorf("FormatChangeTooLarge() = %q, should list at most 3 files", got)
+	}
+}
+
+func TestStagedSize_IgnoresSymlinks(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"real.txt": "12345"})
+	if err := os.Symlink(filepath.Join(root, "real.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	if total, _ := StagedSize(root, []string{"link.txt"}); total != 0 {
+		t.Errorf("total = %d, want 0 for a symlink", total)
+	}
+}
@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// maxHandoffLen caps a stored handoff note, in bytes.
+const maxHandoffLen = 2000
+
+// HandoffInstruction asks Claude to end a task with a note for the tasks
+// that build on it.
+const HandoffInstruction = "\nWhen the task is done, end your reply with a short handoff note for the tasks that build on this one, " +
+	"inside <handoff></handoff> tags: what you built, the key files, and any gotchas. A few lines, no code.\n"
+
+var handoffRe = regexp.MustCompile(`(?s)<handoff>(.*?)</handoff>`)
+
+// ParseHandoff returns the last handoff note in a reply, or "".
+func ParseHandoff(text string) string {
+	matches := handoffRe.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	note := strings.TrimSpace(matches[len(matches)-1][1])
+	if len(note) > maxHandoffLen {
+		note = strings.ToValidUTF8(note[:maxHandoffLen], "") + "…"
+	}
+	return note
+}
+
+// HandoffSection passes on the handoff notes of the tasks a task depends
+// on; "" when none left one.
+func HandoffSection(task state.Task, tasks []state.Task) string {
+	var b strings.Builder
+	for _, id := range task.DependsOn {
+		for _, t := range tasks {
+			if t.ID == id && t.Handoff != "" {
+				fmt.Fprintf(&b, "\n%s — %s:\n%s\n", t.ID, t.Title, t.Handoff)
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\nHANDOFF NOTES (left by the tasks this one builds on):\n" + b.String()
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestParseHandoff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, text, want string
+	}{
+		{"none", "All done.", ""},
+		{"note", "Done.\n<handoff>\n Added api/users.go.\n</handoff>\n", "Added api/users.go."},
+		{"last wins", "<handoff>first</handoff> then <handoff>second</handoff>", "second"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseHandoff(tt.text); got != tt.want {
+				t.Errorf("ParseHandoff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := ParseHandoff("<handoff>" + strings.Repeat("é", maxHandoffLen) + "</handoff>")
+	if len(long) > maxHandoffLen+len("…") || !strings.HasSuffix(long, "…") {
+		t.Errorf("long note not capped: %d bytes", len(long))
+	}
+}
+
+func TestHandoffSection(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Schema", Handoff: "Users in db/users.sql"},
+		{ID: "task-002", Title: "Config"},
+		{ID: "task-003", Title: "API", DependsOn: []string{"task-001", "task-002"}},
+	}
+	got := HandoffSection(tasks[2], tasks)
+	if !strings.Contains(got, "task-001 — Schema:\nUsers in db/users.sql\n") || strings.Contains(got, "task-002") {
+		t.Errorf("HandoffSection() = %q", got)
+	}
+	if got := HandoffSection(tasks[0], tasks); got != "" {
+		t.Errorf("HandoffSection(no deps) = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// commitHooks are the client-side hooks `git commit` runs; any of them can
+// reject the commit.
+var commitHooks = []string{"pre-commit", "prepare-commit-msg", "commit-msg"}
+
+// CommitOptions adjusts how GitOps.Commit commits.
+type CommitOptions struct {
+	NoVerify bool   // skip pre-commit and commit-msg hooks (Settings.SkipHooks)
+	SignOff  bool   // add a DCO Signed-off-by trailer for the committer (Settings.SignOff)
+	Identity string // "Name <email>" to author and commit as (Settings.CommitIdentity); empty = git config
+}
+
+// commitOptions are the CommitOptions the settings ask for.
+func commitOptions(settings *state.Settings) CommitOptions {
+	return CommitOptions{NoVerify: settings.SkipHooks, SignOff: settings.SignOff, Identity: settings.CommitIdentity}
+}
+
+// HookError is a commit that failed while the repository has commit hooks
+// (husky, pre-commit, lint-staged...). Output is what git and the hooks
+// printed; it goes back to Claude so the retry can fix what they report.
+type HookError struct {
+	Output string
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("commit rejected by git hooks: %v", e.Err)
+}
+
+func (e *HookError) Unwrap() error { return e.Err }
@@ -2,7 +2,10 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/manasm11/forge/internal/codeindex"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -26,11 +29,27 @@ type GitOps interface {
 	// HasStagedChanges returns true if there are staged changes to commit.
 	HasStagedChanges(ctx context.Context) (bool, bool, error)
 
+	// StagedFiles lists the staged paths (relative to the repo root),
+	// excluding deletions.
+	StagedFiles(ctx context.Context) ([]string, error)
+
+	// StagedDiff returns the staged changes as a unified diff.
+	StagedDiff(ctx context.Context) (string, error)
+
 	// HasUnstagedChanges returns true if there are unstaged/untracked changes.
 	HasUnstagedChanges(ctx context.Context) (bool, error)
 
+	// DirtyFiles lists the paths with uncommitted changes, staged or not,
+	// including untracked files that aren't ignored.
+	DirtyFiles(ctx context.Context) ([]string, error)
+
+	// Stash stashes the changes to paths, untracked files included, under
+	// message (git stash push).
+	Stash(ctx context.Context, message string, paths []string) error
+
 	// Commit creates a commit with the given message. Returns the SHA.
-	Commit(ctx context.Context, message string) (string, error)
+	// A commit rejected by the repository's hooks returns a *HookError.
+	Commit(ctx context.Context, message string, opts CommitOptions) (string, error)
 
 	// Push pushes the current branch to origin.
 	Push(ctx context.Context) error
@@ -46,6 +65,19 @@ type GitOps interface {
 
 	// DeleteBranch deletes a local branch. Fails if it's the current branch.
 	DeleteBranch(ctx context.Context, name string) error
+
+	// FetchBase fetches branch's upstream (branch@{u}, or origin/branch
+	// when none is configured) and reports the commits and files it has
+	// that the local branch lacks.
+	FetchBase(ctx context.Context, branch string) (BaseDrift, error)
+
+	// SyncBranch brings the current branch up to upstream: a fast-forward,
+	// or a rebase onto it when rebase is set. A failed rebase is aborted.
+	SyncBranch(ctx context.Context, upstream string, rebase bool) error
+
+	// SparseCheckout limits the working tree to the given directories
+	// (cone-mode git sparse-checkout). Branches checked out later keep it.
+	SparseCheckout(ctx context.Context, paths []string) error
 }
 
 // TestRunner abstracts running test/build commands.
@@ -60,7 +92,7 @@ type TestRunner interface {
 // TestResult holds the outcome of a test or build command.
 type TestResult struct {
 	Passed   bool
-	Output   string  // stdout+stderr combined
+	Output   string // stdout+stderr combined
 	ExitCode int
 	Duration float64 // seconds
 }
@@ -89,6 +121,7 @@ type ExecuteResult struct {
 	Text       string
 	TurnCount  int
 	TokensUsed int
+	CostUSD    float64 // as reported by the CLI; 0 if unknown
 	Duration   float64 // seconds
 }
 
@@ -124,8 +157,116 @@ const (
 	EventTaskFailed
 	EventTaskSkipped
 	EventError
+	EventPlanChanged // a PlanEdit was applied between tasks
+	EventTaskReset   // a skipped task returned to pending
+	EventVerifyStart // end-of-run verification step started (Message = step name)
+	EventVerifyPassed
+	EventVerifyFailed
+	EventManualWait      // manual task waiting for a human (Message = instructions)
+	EventBudgetExhausted // run paused at the spending cap (Message = reason)
+	EventArtifacts       // artifacts collected (Message = summary, Detail = error, if any)
+	EventRunPaused       // run stopped for a human (Message = reason); see ErrPaused
+	EventPolicyViolation // staged changes broke the commit policy (Detail = violations)
+	EventStateReloaded   // state.json was changed by another process and re-read
+	EventIssueFiled      // an issue was opened for a failed task (Message = URL)
+	EventChangelog       // the completed plan's changelog entry was written (Message = path)
+	EventReview          // the reviewer judged the staged changes (Message = verdict, Detail = findings)
+	EventChecklist       // Claude left self-review checklist items unconfirmed (Detail = items)
+	EventLFSWarning      // a task commits large binaries outside Git LFS (Message = files)
+	EventHookFailed      // git hooks rejected a task's commit (Detail = hook output)
+	EventCIStart         // waiting for the remote CI of a pushed commit (Message = SHA)
+	EventCIPassed        // the pushed commit's CI passed
+	EventCIFailed        // the pushed commit's CI failed (Message = runs, Detail = logs)
+	EventPRReady         // a draft PR was marked ready for review (Message = URL)
+	EventWorktreeStashed // the user's uncommitted changes were stashed before the run (Detail = files)
+	EventBaseDrift       // the base branch was updated from its remote before a task (Detail = files)
+	EventRootCause       // a failed task was diagnosed (Message = root cause, Detail = with suggestions)
+	EventLesson          // a failure pattern was added to the knowledge base (Message = pattern → resolution)
+	EventEditRejected    // a PlanEdit could not be applied (Message = edit and reason)
 )
 
+var eventTypeNames = [...]string{
+	EventTaskStart:       "task_start",
+	EventBranchCreated:   "branch_created",
+	EventClaudeStart:     "claude_start",
+	EventClaudeChunk:     "claude_chunk",
+	EventClaudeDone:      "claude_done",
+	EventTestStart:       "test_start",
+	EventTestPassed:      "test_passed",
+	EventTestFailed:      "test_failed",
+	EventBuildStart:      "build_start",
+	EventBuildPassed:     "build_passed",
+	EventBuildFailed:     "build_failed",
+	EventRetry:           "retry",
+	EventCommit:          "commit",
+	EventPush:            "push",
+	EventPRCreated:       "pr_created",
+	EventTaskDone:        "task_done",
+	EventTaskFailed:      "task_failed",
+	EventTaskSkipped:     "task_skipped",
+	EventError:           "error",
+	EventPlanChanged:     "plan_changed",
+	EventTaskReset:       "task_reset",
+	EventVerifyStart:     "verify_start",
+	EventVerifyPassed:    "verify_passed",
+	EventVerifyFailed:    "verify_failed",
+	EventManualWait:      "manual_wait",
+	EventBudgetExhausted: "budget_exhausted",
+	EventArtifacts:       "artifacts",
+	EventRunPaused:       "run_paused",
+	EventPolicyViolation: "policy_violation",
+	EventStateReloaded:   "state_reloaded",
+	EventIssueFiled:      "issue_filed",
+	EventChangelog:       "changelog",
+	EventReview:          "review",
+	EventChecklist:       "checklist",
+	EventLFSWarning:      "lfs_warning",
+	EventHookFailed:      "hook_failed",
+	EventCIStart:         "ci_start",
+	EventCIPassed:        "ci_passed",
+	EventCIFailed:        "ci_failed",
+	EventPRReady:         "pr_ready",
+	EventWorktreeStashed: "worktree_stashed",
+	EventBaseDrift:       "base_drift",
+	EventRootCause:       "root_cause",
+	EventLesson:          "lesson",
+	EventEditRejected:    "edit_rejected",
+}
+
+// String returns the stable name used for the event type in the journal.
+func (t TaskEventType) String() string {
+	if int(t) >= 0 && int(t) < len(eventTypeNames) {
+		return eventTypeNames[t]
+	}
+	return fmt.Sprintf("event(%d)", int(t))
+}
+
+// ParseTaskEventType is the inverse of TaskEventType.String.
+func ParseTaskEventType(name string) (TaskEventType, bool) {
+	for i, n := range eventTypeNames {
+		if n == name {
+			return TaskEventType(i), true
+		}
+	}
+	return 0, false
+}
+
+// PlanEdit is a change to the task list requested while the runner is
+// executing (reorder, add, skip...). The runner applies edits between
+// tasks, so they never race with task selection; an error rejects the edit.
+type PlanEdit struct {
+	Description string
+	TaskID      string // task the edit concerns, echoed on the resulting event
+	Apply       func(s *state.State) error
+}
+
+// ManualConfirmation is a human's answer to a manual task.
+type ManualConfirmation struct {
+	TaskID string
+	Done   bool   // false marks the task failed
+	Note   string // optional, recorded in the task log
+}
+
 // EventHandler receives execution events for logging/display.
 type EventHandler func(event TaskEvent)
 
@@ -137,9 +278,24 @@ type RunnerConfig struct {
 	Tests       TestRunner
 	Claude      ClaudeExecutor
 	OnEvent     EventHandler
-	ContextFile string // contents of .forge/context.md
-	BaseBranch  string // base branch for merging
-	RemoteURL   string // remote URL (empty if no remote)
+	ContextFile string                            // contents of .forge/context.md
+	BaseBranch  string                            // base branch for merging
+	RemoteURL   string                            // remote URL (empty if no remote)
+	Journal     *Journal                          // execution journal (nil = not recorded)
+	Webhook     *Webhook                          // posts every event to Settings.WebhookURL (nil = off)
+	Edits       <-chan PlanEdit                   // plan changes from the UI (nil = none)
+	Confirm     <-chan ManualConfirmation         // answers for manual tasks (nil = manual tasks fail)
+	FreeSpace   func(path string) (uint64, error) // disk space probe (nil = platform.FreeDiskSpace)
+	Clock       func() time.Time                  // clock for run limits (nil = time.Now)
+	Issues      IssueFiler                        // opens issues for tasks that exhaust retries, if Settings.FileIssues (nil = off)
+	PRs         PRCreator                         // opens a PR per pushed task branch, if Settings.AutoPR (nil = off)
+	Checks      CheckPublisher                    // reports check runs on pushed commits, if Settings.PublishChecks (nil = off)
+	CI          CIWatcher                         // waits for pushed commits' CI, if Settings.WaitForCI (nil = off)
+	Reviewer    ClaudeExecutor                    // reviews staged changes, if Settings.CodeReview (nil = Claude)
+	Workspace   WorkspaceOps                      // git and commands for Settings.Repos (nil = root repository only)
+	StashDirty  bool                              // stash uncommitted changes at the start, as if Settings.StashDirty were on
+	Generated   []string                          // files forge wrote in the project root this session, e.g. CLAUDE.md; not the user's uncommitted work
+	Embed       codeindex.Embedder                // embeds for code search, if Settings.CodeIndex (nil = off)
 }
 
 // TaskOutcome is the result of executing a single task.
@@ -150,4 +306,8 @@ type TaskOutcome struct {
 	Error   string // error message if failed
 	Retries int    // how many retries were attempted
 	Logs    string // full execution log
+
+	// NeedsReview stops the run on the task branch, changes still staged,
+	// instead of committing them.
+	NeedsReview bool
 }
@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// FailedIssueLabel marks issues forge opens for tasks that exhausted their
+// retries.
+const FailedIssueLabel = "forge-failed"
+
+const (
+	maxIssueOutput = 6000  // failure output kept in an issue, from the end
+	maxIssuePrompt = 8000  // per prompt
+	maxIssueBody   = 60000 // GitHub rejects bodies over 65536 characters
+)
+
+// Issue is a bug report for a failed task.
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// IssueFiler opens issues in the project's tracker.
+type IssueFiler interface {
+	// FileIssue creates the issue and returns its URL.
+	FileIssue(ctx context.Context, issue Issue) (string, error)
+}
+
+// GhIssueFiler files issues with the GitHub CLI.
+type GhIssueFiler struct {
+	dir string
+}
+
+// NewGhIssueFiler creates an IssueFiler for the repository at dir.
+func NewGhIssueFiler(dir string) *GhIssueFiler {
+	return &GhIssueFiler{dir: dir}
+}
+
+// FileIssue runs `gh issue create`, first making sure each label exists
+// (gh refuses unknown labels).
+func (g *GhIssueFiler) FileIssue(ctx context.Context, issue Issue) (string, error) {
+	for _, label := range issue.Labels {
+		g.gh(ctx, "", "label", "create", label, "--force", "--color", "B60205",
+			"--description", "Task forge could not complete")
+	}
+	args := []string{"issue", "create", "--title", issue.Title, "--body-file", "-"}
+	for _, label := range issue.Labels {
+		args = append(args, "--label", label)
+	}
+	out, err := g.gh(ctx, issue.Body, args...)
+	if err != nil {
+		return "", err
+	}
+	// gh prints progress lines before the URL
+	lines := strings.Split(out, "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+func (g *GhIssueFiler) gh(ctx context.Context, stdin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = g.dir
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	if err != nil {
+		return output, fmt.Errorf("gh %s %s: %s: %w", args[0], args[1], output, err)
+	}
+	return output, nil
+}
+
+// FailureIssue describes a task that exhausted its retries: what it was
+// meant to do, where its work is, why it failed and the prompts it was
+// given, so the failure can be triaged like any other bug.
+func FailureIssue(task state.Task, remoteURL, reason, output string, prompts []string) Issue {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Forge could not complete **%s** (%s): %s.\n\n", task.Title, task.ID, reason)
+
+	b.WriteString("## Task\n\n")
+	b.WriteString(strings.TrimSpace(task.Description) + "\n")
+	if len(task.AcceptanceCriteria) > 0 {
+		b.WriteString("\n**Acceptance criteria**\n\n")
+		for _, c := range task.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- [ ] %s\n", c)
+		}
+	}
+
+	if rc := task.RootCause; rc != nil {
+		b.WriteString("\n## Root cause\n\n" + rc.Summary + "\n")
+		if len(rc.Suggestions) > 0 {
+			b.WriteString("\n**Suggested plan changes**\n\n")
+			for _, s := range rc.Suggestions {
+				fmt.Fprintf(&b, "- %s\n", s)
+			}
+		}
+	}
+
+	if task.Branch != "" {
+		b.WriteString("\n## Branch\n\n")
+		if url := BranchURL(remoteURL, task.Branch); url != "" {
+			fmt.Fprintf(&b, "[`%s`](%s)", task.Branch, url)
+		} else {
+			fmt.Fprintf(&b, "`%s`", task.Branch)
+		}
+		b.WriteString(" — failed attempts are not committed, so the branch may be local only or hold just its starting point.\n")
+	}
+
+	if output = strings.TrimSpace(output); output != "" {
+		b.WriteString("\n## Failure output\n\n")
+		if len(output) > maxIssueOutput {
+			output = "…\n" + tail(output, maxIssueOutput)
+		}
+		b.WriteString(fence(output) + "\n")
+	}
+
+	if len(prompts) > 0 {
+		b.WriteString("\n## Prompts\n")
+		for i, p := range prompts {
+			if len(p) > maxIssuePrompt {
+				p = strings.ToValidUTF8(p[:maxIssuePrompt], "") + "\n…"
+			}
+			fmt.Fprintf(&b, "\n<details><summary>Attempt %d</summary>\n\n%s\n\n</details>\n", i+1, fence(p))
+		}
+	}
+
+	body := b.String()
+	if len(body) > maxIssueBody {
+		body = strings.ToValidUTF8(body[:maxIssueBody], "") + "\n\n…(truncated)\n"
+	}
+	return Issue{
+		Title:  fmt.Sprintf("forge: %s failed — %s", task.ID, task.Title),
+		Body:   body,
+		Labels: []string{FailedIssueLabel},
+	}
+}
+
+var githubRemoteRe = regexp.MustCompile(`^(?:https?://|ssh://git@|git@)github\.com[:/]([^/]+)/(.+?)(?:\.git)?/?$`)
+
+// BranchURL links to branch on GitHub, or "" for other remotes.
+func BranchURL(remoteURL, branch string) string {
+	m := githubRemoteRe.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/tree/%s", m[1], m[2], branch)
+}
+
+// fence wraps text in a code fence longer than any backtick run inside it.
+func fence(text string) string {
+	ticks := "```"
+	for strings.Contains(text, ticks) {
+		ticks += "`"
+	}
+	return ticks + "\n" + text + "\n" + ticks
+}
+
+// tail returns about the last n bytes of s, starting at a line boundary.
+func tail(s string, n int) string {
+	s = strings.ToValidUTF8(s[len(s)-n:], "")
+	if i := strings.IndexByte(s, '\n'); i >= 0 && i < n/2 {
+		s = s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// mockIssueFiler records filed issues.
+type mockIssueFiler struct {
+	issues []Issue
+	err    error
+}
+
+func (m *mockIssueFiler) FileIssue(_ context.Context, issue Issue) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.issues = append(m.issues, issue)
+	return "https://github.com/acme/app/issues/7", nil
+}
+
+func TestFailureIssue(t *testing.T) {
+	t.Parallel()
+	task := state.Task{
+		ID: "task-003", Title: "Add login", Description: "Add a login endpoint.",
+		AcceptanceCriteria: []string{"POST /login returns a token"},
+		Branch:             "forge/task-003",
+		RootCause:          &state.RootCause{Summary: "The users table has no password column", Suggestions: []string{"Add a migration task first"}},
+	}
+	issue := FailureIssue(task, "git@github.com:acme/app.git", "tests failed after 3 attempts",
+		"--- FAIL: TestLogin\n```\nboom", []string{"Implement login", "Fix the failing test"})
+
+	if issue.Title != "forge: task-003 failed — Add login" {
+		t.Errorf("Title = %q", issue.Title)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != FailedIssueLabel {
+		t.Errorf("Labels = %q, want [%s]", issue.Labels, FailedIssueLabel)
+	}
+	for _, want := range []string{
+		"tests failed after 3 attempts",
+		"Add a login endpoint.",
+		"- [ ] POST /login returns a token",
+		"## Root cause\n\nThe users table has no password column\n\n**Suggested plan changes**\n\n- Add a migration task first\n",
+		"[`forge/task-003`](https://github.com/acme/app/tree/forge/task-003)",
+		"````\n--- FAIL: TestLogin\n```\nboom\n````", // fence outgrows the backticks inside
+		"<details><summary>Attempt 2</summary>",
+		"Fix the failing test",
+	} {
+		if !strings.Contains(issue.Body, want) {
+			t.Errorf("Body missing %q:\n%s", want, issue.Body)
+		}
+	}
+}
+
+func TestFailureIssue_TruncatesOutputFromTheStart(t *testing.T) {
+	t.Parallel()
+	output := strings.Repeat("noise line\n", 2000) + "the real error"
+	issue := FailureIssue(state.Task{ID: "task-001", Title: "X"}, "", "tests failed", output, nil)
+	if !strings.Contains(issue.Body, "the real error") || len(issue.Body) > maxIssueOutput+500 {
+		t.Errorf("body kept %d bytes, want the tail of the output", len(issue.Body))
+	}
+}
+
+func TestBranchURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ remote, want string }{
+		{"https://github.com/acme/app.git", "https://github.com/acme/app/tree/forge/task-001"},
+		{"https://github.com/acme/app", "https://github.com/acme/app/tree/forge/task-001"},
+		{"git@github.com:acme/app.git", "https://github.com/acme/app/tree/forge/task-001"},
+		{"ssh://git@github.com/acme/app.git", "https://github.com/acme/app/tree/forge/task-001"},
+		{"https://gitlab.com/acme/app.git", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := BranchURL(tt.remote, "forge/task-001"); got != tt.want {
+			t.Errorf("BranchURL(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestRunTask_FilesIssueWhenRetriesExhausted(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		fileIssues bool
+		issueURL   string // already on the task
+		filerErr   error
+		wantIssues int
+		wantEvent  TaskEventType
+	}{
+		{name: "enabled", fileIssues: true, wantIssues: 1, wantEvent: EventIssueFiled},
+		{name: "disabled", fileIssues: false, wantIssues: 0},
+		{name: "already filed", fileIssues: true, issueURL: "https://github.com/acme/app/issues/1", wantIssues: 0},
+		{name: "gh fails", fileIssues: true, filerErr: errors.New("gh: not logged in"), wantEvent: EventError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			task := mkTask("task-001", "Init", state.TaskPending, nil)
+			task.IssueURL = tt.issueURL
+			s := testState(task)
+			s.Settings = defaultSettings()
+			s.Settings.MaxRetries = 1
+			s.Settings.FileIssues = tt.fileIssues
+
+			filer := &mockIssueFiler{err: tt.filerErr}
+			var events []TaskEvent
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(),
+				Git:     NewMockGitOps(),
+				Tests:   NewMockTestRunner(&TestResult{Output: "FAIL a"}, &TestResult{Output: "FAIL b"}),
+				Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "v1"}, &ExecuteResult{Text: "v2"}),
+				Issues:  filer,
+				OnEvent: func(e TaskEvent) { events = append(events, e) },
+			})
+
+			outcome := runner.RunTask(context.Background(), &s.Tasks[0])
+			if outcome.Status != state.TaskFailed {
+				t.Fatalf("status = %q, want failed", outcome.Status)
+			}
+			if len(filer.issues) != tt.wantIssues {
+				t.Fatalf("filed %d issues, want %d", len(filer.issues), tt.wantIssues)
+			}
+			if tt.wantIssues > 0 {
+				body := filer.issues[0].Body
+				if !strings.Contains(body, "FAIL b") || !strings.Contains(body, "Attempt 2") {
+					t.Errorf("issue body lacks the last output or the retry prompt:\n%s", body)
+				}
+				if s.Tasks[0].IssueURL != "https://github.com/acme/app/issues/7" {
+					t.Errorf("IssueURL = %q", s.Tasks[0].IssueURL)
+				}
+			}
+			if tt.wantEvent != 0 {
+				last := events[len(events)-1]
+				if last.Type != tt.wantEvent {
+					t.Errorf("last event = %v, want %v", last.Type, tt.wantEvent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+const journalFileName = "journal.jsonl"
+
+// JournalPath returns the path of the execution journal (.forge/journal.jsonl).
+func JournalPath(root string) string {
+	return filepath.Join(state.ForgeDir(root), journalFileName)
+}
+
+// JournalKind distinguishes the records stored in the journal.
+type JournalKind string
+
+const (
+	JournalKindEvent    JournalKind = "event"
+	JournalKindEnv      JournalKind = "env"
+	JournalKindExchange JournalKind = "exchange"
+)
+
+// JournalEntry is one line of the execution journal.
+type JournalEntry struct {
+	Kind      JournalKind   `json:"kind"`
+	RunID     int           `json:"run_id,omitempty"` // state.RunSummary ID
+	TaskID    string        `json:"task_id,omitempty"`
+	Timestamp int64         `json:"ts"` // unix millis
+	Event     *JournalEvent `json:"event,omitempty"`
+	Env       *EnvRecord    `json:"env,omitempty"`
+	Exchange  *Exchange     `json:"exchange,omitempty"`
+}
+
+// JournalEvent is the serialized form of a TaskEvent.
+type JournalEvent struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Exchange is one prompt sent to the model and the response it returned,
+// stored verbatim so transcripts can be exported later. Planning exchanges
+// have no task ID.
+type Exchange struct {
+	Attempt  int    `json:"attempt,omitempty"` // 1-based execution attempt; 0 while planning
+	System   string `json:"system,omitempty"`  // system prompt, or the context opening a planning session
+	Prompt   string `json:"prompt"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// TaskEvent converts the entry back into the event it recorded.
+// Returns false for non-event entries or unknown event types.
+func (e JournalEntry) TaskEvent() (TaskEvent, bool) {
+	if e.Kind != JournalKindEvent || e.Event == nil {
+		return TaskEvent{}, false
+	}
+	t, ok := ParseTaskEventType(e.Event.Type)
+	if !ok {
+		return TaskEvent{}, false
+	}
+	return TaskEvent{
+		TaskID:    e.TaskID,
+		Type:      t,
+		Message:   e.Event.Message,
+		Detail:    e.Event.Detail,
+		Timestamp: e.Timestamp,
+	}, true
+}
+
+// Journal appends entries to .forge/journal.jsonl. A nil *Journal is valid
+// and discards everything, so callers don't need to check.
+type Journal struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenJournal opens (or creates) the journal for appending.
+func OpenJournal(root string) (*Journal, error) {
+	path := JournalPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating journal directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	return &Journal{f: f}, nil
+}
+
+// Append writes one entry as a JSON line.
+func (j *Journal) Append(entry JournalEntry) error {
+	if j == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling journal entry: %w", err)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	return nil
+}
+
+// AppendEvent records a task event for the given run.
+func (j *Journal) AppendEvent(runID int, e TaskEvent) error {
+	return j.Append(JournalEntry{
+		Kind:      JournalKindEvent,
+		RunID:     runID,
+		TaskID:    e.TaskID,
+		Timestamp: e.Timestamp,
+		Event:     &JournalEvent{Type: e.Type.String(), Message: e.Message, Detail: e.Detail},
+	})
+}
+
+// AppendExchange records a prompt/response pair for the given run and task.
+func (j *Journal) AppendExchange(runID int, taskID string, x Exchange) error {
+	return j.Append(JournalEntry{
+		Kind:      JournalKindExchange,
+		RunID:     runID,
+		TaskID:    taskID,
+		Timestamp: time.Now().UnixMilli(),
+		Exchange:  &x,
+	})
+}
+
+// Close closes the underlying file.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	return j.f.Close()
+}
+
+// EventsForRun returns the events recorded for one run, in order.
+func EventsForRun(entries []JournalEntry, runID int) []TaskEvent {
+	var events []TaskEvent
+	for _, entry := range entries {
+		if entry.RunID != runID {
+			continue
+		}
+		if e, ok := entry.TaskEvent(); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// LatestRunID returns the highest run ID with recorded events, or 0.
+func LatestRunID(entries []JournalEntry) int {
+	latest := 0
+	for _, entry := range entries {
+		if entry.Kind == JournalKindEvent && entry.RunID > latest {
+			latest = entry.RunID
+		}
+	}
+	return latest
+}
+
+// ReadJournal loads all entries from a journal file. Malformed lines
+// (e.g. a write cut short by a crash) are skipped.
+func ReadJournal(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var e JournalEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return entries, fmt.Errorf("reading journal: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,206 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestJournal_RoundTrip(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	j, err := OpenJournal(root)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	events := []TaskEvent{
+		{TaskID: "task-001", Type: EventTaskStart, Message: "Init", Timestamp: 1},
+		{TaskID: "task-001", Type: EventTestFailed, Detail: "--- FAIL: TestX", Timestamp: 2},
+		{TaskID: "task-001", Type: EventTaskDone, Message: "completed", Timestamp: 3},
+	}
+	for _, e := range events {
+		if err := j.AppendEvent(1, e); err != nil {
+			t.Fatalf("AppendEvent: %v", err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Simulate a torn write at the end of the file.
+	f, _ := os.OpenFile(JournalPath(root), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"kind":"event","ta`)
+	f.Close()
+
+	entries, err := ReadJournal(JournalPath(root))
+	if err != nil {
+		t.Fatalf("ReadJournal: %v", err)
+	}
+	if len(entries) != len(events) {
+		t.Fatalf("entries = %d, want %d", len(entries), len(events))
+	}
+	for i, entry := range entries {
+		got, ok := entry.TaskEvent()
+		if !ok {
+			t.Fatalf("entry %d is not an event: %+v", i, entry)
+		}
+		if got != events[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got, events[i])
+		}
+	}
+}
+
+func TestJournal_NilIsNoop(t *testing.T) {
+	t.Parallel()
+	var j *Journal
+	if err := j.AppendEvent(1, TaskEvent{Type: EventTaskStart}); err != nil {
+		t.Errorf("nil AppendEvent: %v", err)
+	}
+	if err := j.Close(); err != nil {
+		t.Errorf("nil Close: %v", err)
+	}
+}
+
+func TestTaskEventType_StringRoundTrip(t *testing.T) {
+	t.Parallel()
+	for typ := EventTaskStart; typ <= EventError; typ++ {
+		got, ok := ParseTaskEventType(typ.String())
+		if !ok || got != typ {
+			t.Errorf("ParseTaskEventType(%q) = %v, %v; want %v", typ.String(), got, ok, typ)
+		}
+	}
+	if _, ok := ParseTaskEventType("bogus"); ok {
+		t.Error("ParseTaskEventType(bogus) should fail")
+	}
+}
+
+func TestBuildEnvRecord_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+	getenv := func(k string) string {
+		return map[string]string{"PATH": "/usr/bin", "HOME": "/home/me", "CI": "true"}[k]
+	}
+	agentEnv := map[string]string{
+		"ANTHROPIC_API_KEY": "sk-live",
+		"GITHUB_TOKEN":      "ghp_x",
+		"OLLAMA_HOST":       "http://localhost:11434",
+	}
+
+	rec := BuildEnvRecord(map[string]string{"go": "go version go1.25"}, getenv, agentEnv)
+
+	if rec.OS == "" || rec.Arch == "" {
+		t.Error("OS and Arch should be set")
+	}
+	if rec.Env["PATH"] != "/usr/bin" || rec.Env["CI"] != "true" {
+		t.Errorf("captured env = %v", rec.Env)
+	}
+	if _, ok := rec.Env["HOME"]; ok {
+		t.Error("HOME is not in the captured list and should be omitted")
+	}
+	if rec.Env["OLLAMA_HOST"] != "http://localhost:11434" {
+		t.Errorf("agent env not recorded: %v", rec.Env)
+	}
+	for _, k := range []string{"ANTHROPIC_API_KEY", "GITHUB_TOKEN"} {
+		if rec.Env[k] != redacted {
+			t.Errorf("%s = %q, want redacted", k, rec.Env[k])
+		}
+	}
+}
+
+func TestRun_RecordsEnvPerTaskInJournal(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	s := testState(
+		mkTask("task-001", "Init", state.TaskPending, nil),
+		mkTask("task-002", "Auth", state.TaskPending, []string{"task-001"}),
+	)
+
+	j, err := OpenJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: root,
+		Git:     NewMockGitOps(),
+		Tests:   NewMockTestRunner(&TestResult{Passed: true}, &TestResult{Passed: true}),
+		Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "done"}, &ExecuteResult{Text: "done"}),
+		Journal: j,
+	})
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	j.Close()
+
+	entries, err := ReadJournal(filepath.Join(root, ".forge", "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envTasks := map[string]bool{}
+	prompts := map[string]string{}
+	sawEvent := false
+	for _, e := range entries {
+		switch e.Kind {
+		case JournalKindEnv:
+			if e.Env == nil || len(e.Env.Tools) == 0 {
+				t.Errorf("env entry without tools: %+v", e)
+			}
+			envTasks[e.TaskID] = true
+		case JournalKindEvent:
+			sawEvent = true
+		case JournalKindExchange:
+			if e.Exchange.Attempt != 1 || e.Exchange.System == "" || e.Exchange.Response != "done" {
+				t.Errorf("exchange = %+v, want attempt 1 with system prompt and response", e.Exchange)
+			}
+			prompts[e.TaskID] = e.Exchange.Prompt
+		}
+	}
+	if !strings.Contains(prompts["task-002"], "Auth") {
+		t.Errorf("task-002 prompt = %q, want the task's execution prompt", prompts["task-002"])
+	}
+	if !envTasks["task-001"] || !envTasks["task-002"] {
+		t.Errorf("env recorded for %v, want both tasks", envTasks)
+	}
+	if !sawEvent {
+		t.Error("journal should contain events")
+	}
+}
+
+func TestEventsForRun(t *testing.T) {
+	t.Parallel()
+	entries := []JournalEntry{
+		{Kind: JournalKindEvent, RunID: 1, TaskID: "task-001", Event: &JournalEvent{Type: "task_start"}},
+		{Kind: JournalKindEnv, RunID: 2, TaskID: "task-002", Env: &EnvRecord{}},
+		{Kind: JournalKindEvent, RunID: 2, TaskID: "task-002", Event: &JournalEvent{Type: "task_start"}},
+		{Kind: JournalKindEvent, RunID: 2, TaskID: "task-002", Event: &JournalEvent{Type: "task_done"}},
+	}
+
+	events := EventsForRun(entries, 2)
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[0].Type != EventTaskStart || events[1].Type != EventTaskDone {
+		t.Errorf("events = %+v", events)
+	}
+	if len(EventsForRun(entries, 9)) != 0 {
+		t.Error("unknown run should have no events")
+	}
+}
+
+func TestLatestRunID(t *testing.T) {
+	t.Parallel()
+	entries := []JournalEntry{
+		{Kind: JournalKindEvent, RunID: 2, Event: &JournalEvent{Type: "task_start"}},
+		{Kind: JournalKindEvent, RunID: 1, Event: &JournalEvent{Type: "task_start"}},
+		{Kind: JournalKindEnv, RunID: 5, Env: &EnvRecord{}},
+	}
+	if got := LatestRunID(entries); got != 2 {
+		t.Errorf("LatestRunID() = %d, want 2", got)
+	}
+	if got := LatestRunID(nil); got != 0 {
+		t.Errorf("LatestRunID(nil) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/manasm11/forge/internal/knowledge"
+	"github.com/manasm11/forge/internal/state"
+)
+
+const maxPitfalls = 5 // knowledge base entries per prompt
+
+// taskText is what knowledge base entries are matched against for a task.
+func taskText(task state.Task) string {
+	return task.Title + "\n" + task.Description + "\n" + strings.Join(task.AcceptanceCriteria, "\n")
+}
+
+// loadKnowledge reads the knowledge base. An unreadable file is reported
+// and treated as empty rather than failing the task.
+func (r *Runner) loadKnowledge(taskID string) *knowledge.Base {
+	kb, err := knowledge.Load(r.cfg.StateRoot)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: taskID, Type: EventError, Message: "reading the knowledge base: " + err.Error()})
+		return &knowledge.Base{}
+	}
+	return kb
+}
+
+// learn records the lessons Claude reported while recovering from failed
+// attempts of a task that then succeeded.
+func (r *Runner) learn(task *state.Task, lessons []knowledge.Entry) {
+	if len(lessons) == 0 {
+		return
+	}
+	kb := r.loadKnowledge(task.ID)
+	for _, l := range lessons {
+		l.Tasks = []string{task.ID}
+		l.UpdatedAt = r.now()
+		kb.Add(l)
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventLesson, Message: l.Pattern + " → " + l.Resolution})
+	}
+	if err := kb.Save(r.cfg.StateRoot); err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "saving the knowledge base: " + err.Error()})
+	}
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manasm11/forge/internal/preflight"
+)
+
+// lfsWarnSize is the size from which a staged binary in an LFS repository
+// is expected to go through LFS.
+const lfsWarnSize = 1 << 20
+
+// LFSCandidates returns the staged binaries (relative to root) of at least
+// lfsWarnSize that no LFS pattern covers, largest first. It returns nil
+// for repositories that don't use LFS.
+func LFSCandidates(root string, files []string, patterns []string) []FileSize {
+	if len(patterns) == 0 {
+		return nil
+	}
+	_, sizes := StagedSize(root, files)
+	var found []FileSize
+	for _, f := range sizes {
+		if f.Size < lfsWarnSize {
+			break
+		}
+		if !preflight.LFSTracked(patterns, filepath.ToSlash(f.Path)) && isBinary(filepath.Join(root, f.Path)) {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// FormatLFSWarning names the binaries a task is committing outside LFS.
+func FormatLFSWarning(files []FileSize) string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = fmt.Sprintf("%s %s", f.Path, preflight.FormatBytes(uint64(f.Size)))
+	}
+	return fmt.Sprintf("committing %d large binary file(s) outside Git LFS: %s — consider `git lfs track`",
+		len(files), strings.Join(names, ", "))
+}
+
+// isBinary reports whether the file's first 8000 bytes hold a NUL, the
+// heuristic git itself uses.
+func isBinary(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 8000)
+	n, _ := io.ReadFull(f, head)
+	return bytes.IndexByte(head[:n], 0) >= 0
+}
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLFSCandidates(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	binary := make([]byte, lfsWarnSize+1)
+	text := []byte(strings.Repeat("x", lfsWarnSize+1))
+	files := map[string][]byte{
+		"model.bin":     binary,
+		"design.psd":    binary, // tracked
+		"fixtures.json": text,
+		"icon.png":      binary[:100],
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(root, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := []string{"model.bin", "design.psd", "fixtures.json", "icon.png"}
+
+	got := LFSCandidates(root, names, []string{"*.psd"})
+	if want := []FileSize{{Path: "model.bin", Size: lfsWarnSize + 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LFSCandidates() = %+v, want %+v", got, want)
+	}
+	if got := LFSCandidates(root, names, nil); got != nil {
+		t.Errorf("LFSCandidates() without LFS = %+v, want nil", got)
+	}
+	if msg := FormatLFSWarning(got); !strings.Contains(msg, "1 large binary file(s) outside Git LFS: model.bin 1 MB") {
+		t.Errorf("FormatLFSWarning() = %q", msg)
+	}
+}
@@ -24,7 +24,13 @@ type MockGitOps struct {
 
 	HasUnstagedResult bool
 
-	CommitCalls []string // commit messages
+	StagedFilesResult []string
+	StagedFilesErr    error
+
+	StagedDiffResult string
+
+	CommitCalls     []string // commit messages
+	CommitOptsCalls []CommitOptions
 	CommitSHA   string   // SHA to return
 	CommitErr   error
 
@@ -41,6 +47,18 @@ type MockGitOps struct {
 	ResetHardErr   error
 
 	DeleteBranchCalls []string
+
+	SparseCheckoutCalls [][]string
+	SparseCheckoutErr   error
+
+	FetchBaseResult BaseDrift
+	FetchBaseErr    error
+	SyncBranchCalls []string // upstreams; "rebase:" prefix when rebasing
+	SyncBranchErr   error
+
+	DirtyFilesResult []string
+	StashCalls       [][]string
+	StashErr         error
 }
 
 var _ GitOps = (*MockGitOps)(nil)
@@ -82,6 +100,14 @@ func (m *MockGitOps) HasStagedChanges(ctx context.Context) (bool, bool, error) {
 	return m.HasStagedResult, m.HasStagedUnstaged, m.HasStagedErr
 }
 
+func (m *MockGitOps) StagedFiles(ctx context.Context) ([]string, error) {
+	return m.StagedFilesResult, m.StagedFilesErr
+}
+
+func (m *MockGitOps) StagedDiff(ctx context.Context) (string, error) {
+	return m.StagedDiffResult, nil
+}
+
 func (m *MockGitOps) HasUnstagedChanges(ctx context.Context) (bool, error) {
 	return m.HasUnstagedResult, nil
 }
@@ -91,8 +117,9 @@ func (m *MockGitOps) Merge(ctx context.Context, branch string) error {
 	return m.MergeErr
 }
 
-func (m *MockGitOps) Commit(ctx context.Context, message string) (string, error) {
+func (m *MockGitOps) Commit(ctx context.Context, message string, opts CommitOptions) (string, error) {
 	m.CommitCalls = append(m.CommitCalls, message)
+	m.CommitOptsCalls = append(m.CommitOptsCalls, opts)
 	return m.CommitSHA, m.CommitErr
 }
 
@@ -114,3 +141,33 @@ func (m *MockGitOps) DeleteBranch(ctx context.Context, name string) error {
 	m.DeleteBranchCalls = append(m.DeleteBranchCalls, name)
 	return nil
 }
+
+func (m *MockGitOps) FetchBase(ctx context.Context, branch string) (BaseDrift, error) {
+	drift := m.FetchBaseResult
+	if drift.Upstream == "" {
+		drift.Upstream = "origin/" + branch
+	}
+	return drift, m.FetchBaseErr
+}
+
+func (m *MockGitOps) SyncBranch(ctx context.Context, upstream string, rebase bool) error {
+	if rebase {
+		upstream = "rebase:" + upstream
+	}
+	m.SyncBranchCalls = append(m.SyncBranchCalls, upstream)
+	return m.SyncBranchErr
+}
+
+func (m *MockGitOps) DirtyFiles(ctx context.Context) ([]string, error) {
+	return m.DirtyFilesResult, nil
+}
+
+func (m *MockGitOps) Stash(ctx context.Context, message string, paths []string) error {
+	m.StashCalls = append(m.StashCalls, paths)
+	return m.StashErr
+}
+
+func (m *MockGitOps) SparseCheckout(ctx context.Context, paths []string) error {
+	m.SparseCheckoutCalls = append(m.SparseCheckoutCalls, paths)
+	return m.SparseCheckoutErr
+}
@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/preflight"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// SecretPatterns match well-known credential formats.
+var SecretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-(?:ant-)?[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	regexp.MustCompile(`xox[abprs]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// secretAssignment matches credential-named assignments; the value is
+// only reported when it looks random (see minSecretEntropy), so
+// placeholders like "changeme" or "${API_KEY}" pass.
+var secretAssignment = regexp.MustCompile(`(?i)(?:api[_-]?key|access[_-]?key|secret|token|password|passwd)["']?\s*[:=]\s*["']?([A-Za-z0-9_\-./+=]{16,})`)
+
+// minSecretEntropy is the Shannon entropy, in bits per character, above
+// which an assigned value is treated as a real credential.
+const minSecretEntropy = 3.5
+
+// maxScannedFile bounds the secret scan; larger files are left to the
+// size rule.
+const maxScannedFile = 1 << 20
+
+// workflowDir holds CI definitions that run with repository secrets.
+const workflowDir = ".github/workflows/"
+
+// PolicyViolation is one staged file breaking a commit policy.
+type PolicyViolation struct {
+	Path   string
+	Rule   string // "file too large", "possible secret", "workflow change" or "submodule change"
+	Detail string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Path, v.Rule, v.Detail)
+}
+
+// CheckCommitPolicy checks staged files (relative to root) against the
+// project's commit policy. Secret findings name the line and kind, never
+// the value.
+func CheckCommitPolicy(root string, files []string, p state.CommitPolicy) []PolicyViolation {
+	var violations []PolicyViolation
+	for _, f := range files {
+		slash := filepath.ToSlash(f)
+		if p.ProtectWorkflows && strings.HasPrefix(slash, workflowDir) {
+			violations = append(violations, PolicyViolation{Path: slash, Rule: "workflow change",
+				Detail: "CI workflows may not be changed by tasks"})
+		}
+
+		info, err := os.Lstat(filepath.Join(root, f))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if p.MaxFileMB > 0 && info.Size() > int64(p.MaxFileMB)<<20 {
+			violations = append(violations, PolicyViolation{Path: slash, Rule: "file too large",
+				Detail: fmt.Sprintf("%s, limit %d MB", preflight.FormatBytes(uint64(info.Size())), p.MaxFileMB)})
+			continue
+		}
+		if p.ScanSecrets && info.Size() <= maxScannedFile {
+			if data, err := os.ReadFile(filepath.Join(root, f)); err == nil {
+				for _, detail := range scanSecrets(data) {
+					violations = append(violations, PolicyViolation{Path: slash, Rule: "possible secret", Detail: detail})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// scanSecrets returns "line N: kind" for each credential-looking line.
+// Binary files are skipped.
+func scanSecrets(data []byte) []string {
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil
+	}
+	var found []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), maxScannedFile)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if kind := secretKind(line); kind != "" {
+			found = append(found, fmt.Sprintf("line %d: %s", n, kind))
+		}
+	}
+	return found
+}
+
+func secretKind(line string) string {
+	for _, p := range SecretPatterns {
+		if p.MatchString(line) {
+			return "credential format"
+		}
+	}
+	// PEM blocks span lines, so their header alone is enough
+	if strings.Contains(line, "-----BEGIN ") && strings.Contains(line, "PRIVATE KEY-----") {
+		return "private key"
+	}
+	if m := secretAssignment.FindStringSubmatch(line); m != nil && entropy(m[1]) >= minSecretEntropy {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// entropy is the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// SubmoduleChangeError reports submodules a task changed. StageAll leaves
+// them unstaged; everything else is staged as usual.
+type SubmoduleChangeError struct {
+	Paths []string
+}
+
+func (e *SubmoduleChangeError) Error() string {
+	return "changes inside submodules: " + strings.Join(e.Paths, ", ")
+}
+
+// Violations reports each changed submodule as a commit policy violation,
+// so the retry prompt asks Claude to revert it.
+func (e *SubmoduleChangeError) Violations() []PolicyViolation {
+	violations := make([]PolicyViolation, len(e.Paths))
+	for i, p := range e.Paths {
+		violations[i] = PolicyViolation{Path: p, Rule: "submodule change",
+			Detail: fmt.Sprintf("vendored code; revert with `git submodule update --force %s` and make the change upstream", p)}
+	}
+	return violations
+}
+
+// FormatPolicyViolations renders violations for the log and retry prompt.
+func FormatPolicyViolations(violations []PolicyViolation) string {
+	var b strings.Builder
+	for _, v := range violations {
+		b.WriteString("- " + v.String() + "\n")
+	}
+	return b.String()
+}
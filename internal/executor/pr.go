@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// PRTemplateFile is the optional template, under .forge/, that replaces
+// DefaultPRTemplate. It is a Go text/template executed with PRData.
+const PRTemplateFile = "pr_template.md"
+
+const maxPRFiles = 50 // listed in the body
+
+// DefaultPRTemplate renders the task, its checked acceptance criteria, the
+// files it changed, the checks it passed and the plan version it came from.
+const DefaultPRTemplate = `{{.Task.Description}}
+{{if .Task.AcceptanceCriteria}}
+## Acceptance criteria
+{{range .Task.AcceptanceCriteria}}
+- [x] {{.}}{{end}}
+{{end}}
+## Changes
+
+{{.FilesSummary}}
+{{range .Files}}
+- ` + "`{{.}}`" + `{{end}}{{if .MoreFiles}}
+- … and {{.MoreFiles}} more{{end}}
+{{if .Checks}}
+## Checks
+{{range .Checks}}
+- {{if .Passed}}✅{{else}}❌{{end}} ` + "`{{.Command}}`" + ` ({{.Name}}, {{printf "%.1f" .Duration}}s){{end}}
+{{end}}
+---
+Opened by forge for {{.Task.ID}} on attempt {{.Attempts}} · plan {{if .PlanURL}}[v{{.PlanVersion}}]({{.PlanURL}}){{else}}v{{.PlanVersion}}{{end}}{{if .PlanSummary}} — {{.PlanSummary}}{{end}}
+`
+
+// PullRequest is a pull request to open for a finished task.
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string // task branch
+	Base  string
+	Dir   string // repository to open it in; empty = the creator's
+	Draft bool
+}
+
+// PRCreator opens pull requests on the project's forge.
+type PRCreator interface {
+	// CreatePR opens the pull request and returns its URL.
+	CreatePR(ctx context.Context, pr PullRequest) (string, error)
+
+	// MarkReady marks the draft pull request at url ready for review. dir
+	// is the repository; empty = the creator's.
+	MarkReady(ctx context.Context, url, dir string) error
+}
+
+// GhPRCreator opens pull requests with the GitHub CLI.
+type GhPRCreator struct {
+	dir string
+}
+
+// NewGhPRCreator creates a PRCreator for the repository at dir.
+func NewGhPRCreator(dir string) *GhPRCreator {
+	return &GhPRCreator{dir: dir}
+}
+
+// CreatePR runs `gh pr create` and returns the URL it prints.
+func (g *GhPRCreator) CreatePR(ctx context.Context, pr PullRequest) (string, error) {
+	args := []string{"pr", "create", "--title", pr.Title, "--body-file", "-", "--head", pr.Head, "--base", pr.Base}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = g.dir
+	if pr.Dir != "" {
+		cmd.Dir = pr.Dir
+	}
+	cmd.Stdin = strings.NewReader(pr.Body)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	if err != nil {
+		return "", fmt.Errorf("gh pr create: %s: %w", output, err)
+	}
+	lines := strings.Split(output, "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// MarkReady runs `gh pr ready`.
+func (g *GhPRCreator) MarkReady(ctx context.Context, url, dir string) error {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "ready", url)
+	cmd.Dir = g.dir
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gh pr ready: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// CheckResult is a test or build command that ran on a task's final attempt.
+type CheckResult struct {
+	Name     string // "tests" or "build"
+	Command  string
+	Passed   bool
+	Duration float64 // seconds
+}
+
+// PRData is what a PR template is executed with.
+type PRData struct {
+	Task         state.Task
+	Branch       string
+	BaseBranch   string
+	Files        []string // changed files, capped at maxPRFiles
+	MoreFiles    int      // files beyond the cap
+	FilesSummary string   // "3 files changed"
+	Checks       []CheckResult
+	Attempts     int
+	PlanVersion  int
+	PlanSummary  string // the revision's summary, if recorded
+	PlanURL      string // state.json at the task's commit, on GitHub remotes
+}
+
+// NewPRData collects the template data for a finished task.
+func NewPRData(s *state.State, task state.Task, baseBranch, remoteURL, sha string, files []string, checks []CheckResult, attempts int) PRData {
+	d := PRData{
+		Task:        task,
+		Branch:      task.Branch,
+		BaseBranch:  baseBranch,
+		Checks:      checks,
+		Attempts:    attempts,
+		PlanVersion: task.PlanVersionModified,
+	}
+	for _, rev := range s.PlanHistory {
+		if rev.Version == d.PlanVersion {
+			d.PlanSummary = rev.Summary
+		}
+	}
+	if url := BranchURL(remoteURL, sha); url != "" {
+		d.PlanURL = strings.Replace(url, "/tree/", "/blob/", 1) + "/.forge/state.json"
+	}
+
+	switch len(files) {
+	case 0:
+		d.FilesSummary = "No files added or modified (deletions only)."
+	case 1:
+		d.FilesSummary = "1 file changed:"
+	default:
+		d.FilesSummary = fmt.Sprintf("%d files changed:", len(files))
+	}
+	d.Files = files
+	if len(files) > maxPRFiles {
+		d.Files, d.MoreFiles = files[:maxPRFiles], len(files)-maxPRFiles
+	}
+	return d
+}
+
+// RenderPRBody executes tmpl (DefaultPRTemplate when empty) with data.
+func RenderPRBody(tmpl string, data PRData) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultPRTemplate
+	}
+	t, err := template.New("pr").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("PR template: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("PR template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// LoadPRTemplate returns the project's .forge/pr_template.md, or "" if it
+// has none.
+func LoadPRTemplate(root string) string {
+	data, err := os.ReadFile(filepath.Join(state.ForgeDir(root), PRTemplateFile))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// mockPRCreator records opened pull requests and those marked ready.
+type mockPRCreator struct {
+	prs   []PullRequest
+	ready []string
+}
+
+func (m *mockPRCreator) CreatePR(_ context.Context, pr PullRequest) (string, error) {
+	m.prs = append(m.prs, pr)
+	return fmt.Sprintf("https://github.com/acme/app/pull/%d", len(m.prs)), nil
+}
+
+func (m *mockPRCreator) MarkReady(_ context.Context, url, _ string) error {
+	m.ready = append(m.ready, url)
+	return nil
+}
+
+func TestRenderPRBody_Default(t *testing.T) {
+	t.Parallel()
+	s := testState()
+	s.PlanHistory = []state.PlanRevision{{Version: 1, Summary: "initial plan"}, {Version: 2, Summary: "split auth tasks"}}
+	task := state.Task{
+		ID: "task-002", Title: "Add login", Description: "Add a login endpoint.",
+		AcceptanceCriteria: []string{"POST /login returns a token", "Bad passwords get 401"},
+		Branch:             "forge/task-002", PlanVersionModified: 2,
+	}
+	checks := []CheckResult{{Name: "tests", Command: "go test ./...", Passed: true, Duration: 3.25}}
+	data := NewPRData(s, task, "main", "git@github.com:acme/app.git", "abc123", []string{"auth/login.go", "auth/login_test.go"}, checks, 2)
+
+	body, err := RenderPRBody("", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Add a login endpoint.",
+		"- [x] POST /login returns a token\n- [x] Bad passwords get 401",
+		"2 files changed:\n\n- `auth/login.go`\n- `auth/login_test.go`",
+		"- ✅ `go test ./...` (tests, 3.2s)",
+		"Opened by forge for task-002 on attempt 2 · plan [v2](https://github.com/acme/app/blob/abc123/.forge/state.json) — split auth tasks",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderPRBody_CustomTemplate(t *testing.T) {
+	t.Parallel()
+	data := PRData{Task: state.Task{ID: "task-001", Title: "Init"}, PlanVersion: 3}
+	got, err := RenderPRBody("Closes {{.Task.ID}} (plan v{{.PlanVersion}})", data)
+	if err != nil || got != "Closes task-001 (plan v3)" {
+		t.Errorf("RenderPRBody() = %q, %v", got, err)
+	}
+	if _, err := RenderPRBody("{{.Nope}}", data); err == nil {
+		t.Error("unknown field should be an error")
+	}
+	if _, err := RenderPRBody("{{if}}", data); err == nil {
+		t.Error("malformed template should be an error")
+	}
+}
+
+func TestNewPRData_CapsFiles(t *testing.T) {
+	t.Parallel()
+	var files []string
+	for i := range maxPRFiles + 4 {
+		files = append(files, fmt.Sprintf("f%d.go", i))
+	}
+	d := NewPRData(testState(), state.Task{}, "main", "https://gitlab.com/acme/app.git", "abc", files, nil, 1)
+	if len(d.Files) != maxPRFiles || d.MoreFiles != 4 || d.PlanURL != "" {
+		t.Errorf("Files = %d, MoreFiles = %d, PlanURL = %q", len(d.Files), d.MoreFiles, d.PlanURL)
+	}
+}
+
+func TestRunTask_OpensPRWithTemplate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		autoPR   bool
+		remote   string
+		template string
+		wantPRs  int
+		wantBody string
+	}{
+		{name: "default template", autoPR: true, remote: "https://github.com/acme/app", wantPRs: 1, wantBody: "- [x] it works"},
+		{name: "project template", autoPR: true, remote: "https://github.com/acme/app", template: "Task {{.Task.ID}}: {{.FilesSummary}}", wantPRs: 1, wantBody: "Task task-001: 1 file changed:"},
+		{name: "auto PR off", autoPR: false, remote: "https://github.com/acme/app"},
+		{name: "no remote", autoPR: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			if tt.template != "" {
+				os.MkdirAll(filepath.Join(root, ".forge"), 0755)
+				os.WriteFile(filepath.Join(root, ".forge", PRTemplateFile), []byte(tt.template), 0644)
+			}
+			task := mkTask("task-001", "Init", state.TaskPending, nil)
+			task.AcceptanceCriteria = []string{"it works"}
+			s := testState(task)
+			s.Settings = defaultSettings()
+			s.Settings.AutoPR = tt.autoPR
+
+			git := NewMockGitOps()
+			git.CurrentBranchResult = "main"
+			git.HasStagedResult = true
+			git.StagedFilesResult = []string{"main.go"}
+			prs := &mockPRCreator{}
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: root, RemoteURL: tt.remote,
+				Git:     git,
+				Tests:   NewMockTestRunner(&TestResult{Passed: true}),
+				Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+				PRs:     prs,
+				OnEvent: func(TaskEvent) {},
+			})
+
+			outcome := runner.RunTask(context.Background(), &s.Tasks[0])
+			if outcome.Status != state.TaskDone {
+				t.Fatalf("status = %q (%s), want done", outcome.Status, outcome.Error)
+			}
+			if len(prs.prs) != tt.wantPRs {
+				t.Fatalf("opened %d PRs, want %d", len(prs.prs), tt.wantPRs)
+			}
+			if tt.wantPRs == 0 {
+				return
+			}
+			pr := prs.prs[0]
+			if pr.Head != "forge/task-001" || pr.Base != "main" || !strings.Contains(pr.Body, tt.wantBody) {
+				t.Errorf("PR = %+v, want head forge/task-001, base main, body containing %q", pr, tt.wantBody)
+			}
+			if s.Tasks[0].PRURL != "https://github.com/acme/app/pull/1" {
+				t.Errorf("PRURL = %q", s.Tasks[0].PRURL)
+			}
+		})
+	}
+}
+
+func TestRun_DraftPRsReadyAfterVerification(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		verify    bool
+		wantReady bool
+	}{
+		{"verification passes", true, true},
+		{"verification fails", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+			s.Settings.AutoPR = true
+			s.Settings.DraftPRs = true
+			prs := &mockPRCreator{}
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(), RemoteURL: "https://github.com/acme/app",
+				Git: NewMockGitOps(),
+				Tests: NewMockTestRunner(
+					&TestResult{Passed: true},      // task tests
+					&TestResult{Passed: tt.verify}, // verify test
+				),
+				Claude:  NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+				PRs:     prs,
+				OnEvent: func(TaskEvent) {},
+			})
+			if err := runner.Run(context.Background()); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if len(prs.prs) != 1 || !prs.prs[0].Draft {
+				t.Fatalf("PRs = %+v, want one draft", prs.prs)
+			}
+			task := s.Tasks[0]
+			if tt.wantReady {
+				if len(prs.ready) != 1 || prs.ready[0] != task.PRURL || task.PRDraft {
+					t.Errorf("ready = %v, PRDraft = %v; want %s marked ready", prs.ready, task.PRDraft, task.PRURL)
+				}
+			} else if len(prs.ready) != 0 || !task.PRDraft {
+				t.Errorf("ready = %v, PRDraft = %v; want the PR left a draft", prs.ready, task.PRDraft)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package executor
 
 import (
+	"cmp"
 	"fmt"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/manasm11/forge/internal/scanner"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -65,6 +70,57 @@ func BuildTaskExecutionPrompt(contextContent string, task state.Task, settings *
 	return b.String()
 }
 
+// apiWordsRe matches wording that puts a task in the API layer.
+var apiWordsRe = regexp.MustCompile(`(?i)\b(apis?|endpoints?|handlers?|routes?|routing|rest|http|grpc|rpcs?|proto|protobuf|openapi|swagger|webhooks?|clients?)\b`)
+
+// TouchesAPI reports whether a task works on the API layer: its text talks
+// about endpoints, handlers or RPCs, or names a schema file, path or
+// message from the project's contracts.
+func TouchesAPI(task state.Task, schemas []scanner.APISchema) bool {
+	text := strings.Join(append([]string{task.Title, task.Description}, task.AcceptanceCriteria...), "\n")
+	if apiWordsRe.MatchString(text) {
+		return true
+	}
+	for _, s := range schemas {
+		if strings.Contains(text, path.Base(s.Path)) {
+			return true
+		}
+		for _, e := range s.Endpoints {
+			// "GET /orders — List orders" → "/orders"
+			if f := strings.Fields(e); len(f) > 1 && strings.HasPrefix(f[1], "/") && strings.Contains(text, f[1]) {
+				return true
+			}
+		}
+		for _, m := range s.Messages {
+			// "Order { id, total }" → "Order"
+			if name, _, _ := strings.Cut(m, " "); strings.Contains(text, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// APIContext returns the project's API schema summaries for tasks that
+// touch the API layer, for appending to the context file. Returns "" for
+// other tasks or when the project has no schemas.
+func APIContext(task state.Task, snap *scanner.ProjectSnapshot) string {
+	if snap == nil || len(snap.APISchemas) == 0 || !TouchesAPI(task, snap.APISchemas) {
+		return ""
+	}
+	return "\n\nAPI SCHEMAS (the project's contracts — keep handlers, clients and messages consistent with them, and update the schema file when the task changes the API):\n" +
+		scanner.FormatAPISchemas(snap.APISchemas)
+}
+
+// TaskPrompt returns the first-attempt prompt for a task: its PromptOverride
+// when the user wrote one in review, otherwise the generated prompt.
+func TaskPrompt(contextContent string, task state.Task, settings *state.Settings) string {
+	if strings.TrimSpace(task.PromptOverride) != "" {
+		return task.PromptOverride
+	}
+	return BuildTaskExecutionPrompt(contextContent, task, settings)
+}
+
 // BuildAllowedTools returns the list of tools Claude is allowed to use during execution.
 func BuildAllowedTools(mcpServers []state.MCPServerConfig) []string {
 	tools := []string{
@@ -88,3 +144,29 @@ func MaxTurnsForTask(complexity string, config state.MaxTurnsConfig) int {
 		return config.Medium
 	}
 }
+
+// minTurnSamples is how many similar completed tasks AdaptMaxTurns needs
+// before it moves the configured limit.
+const minTurnSamples = 3
+
+// AdaptMaxTurns adapts the configured limit for a task of the given
+// complexity to the turns that completed tasks of the same complexity
+// actually needed: the 90th percentile of their Turns plus a quarter, kept
+// within half and twice limit. Returns limit unchanged, with n = 0, when
+// there are fewer than minTurnSamples such tasks; n is the number of
+// tasks the limit was based on.
+func AdaptMaxTurns(complexity string, tasks []state.Task, limit int) (adapted, n int) {
+	var samples []int
+	for _, t := range tasks {
+		if t.Status == state.TaskDone && t.Turns > 0 && strings.EqualFold(cmp.Or(t.Complexity, "medium"), cmp.Or(complexity, "medium")) {
+			samples = append(samples, t.Turns)
+		}
+	}
+	if len(samples) < minTurnSamples || limit <= 0 {
+		return limit, 0
+	}
+	slices.Sort(samples)
+	needed := samples[(len(samples)*9+9)/10-1]
+	adapted = needed + (needed+3)/4
+	return min(max(adapted, limit/2), limit*2), len(samples)
+}
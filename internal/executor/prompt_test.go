@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/manasm11/forge/internal/scanner"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -57,6 +58,60 @@ func TestBuildTaskExecutionPrompt(t *testing.T) {
 	}
 }
 
+func TestTaskPrompt(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-001", Title: "Init"}
+	if got := TaskPrompt("ctx", task, nil); got != BuildTaskExecutionPrompt("ctx", task, nil) {
+		t.Errorf("without override TaskPrompt() = %q, want the generated prompt", got)
+	}
+
+	task.PromptOverride = "Just run go mod init."
+	if got := TaskPrompt("ctx", task, nil); got != "Just run go mod init." {
+		t.Errorf("with override TaskPrompt() = %q", got)
+	}
+}
+
+func TestTouchesAPI(t *testing.T) {
+	t.Parallel()
+	schemas := []scanner.APISchema{
+		{Path: "api/openapi.yaml", Kind: "openapi", Endpoints: []string{"GET /orders — List orders"}},
+		{Path: "proto/shop.proto", Kind: "proto", Messages: []string{"Invoice { id }"}},
+	}
+	tests := []struct {
+		name string
+		task state.Task
+		want bool
+	}{
+		{name: "api wording", task: state.Task{Title: "Add REST handlers for carts"}, want: true},
+		{name: "endpoint path", task: state.Task{Title: "Paginate results", AcceptanceCriteria: []string{"/orders accepts ?page"}}, want: true},
+		{name: "message name", task: state.Task{Title: "Add a due date to Invoice"}, want: true},
+		{name: "schema file", task: state.Task{Description: "Extend shop.proto"}, want: true},
+		{name: "unrelated", task: state.Task{Title: "Set up CI", Description: "Run go test on push"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := TouchesAPI(tt.task, schemas); got != tt.want {
+			t.Errorf("%s: TouchesAPI() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAPIContext(t *testing.T) {
+	t.Parallel()
+	snap := &scanner.ProjectSnapshot{APISchemas: []scanner.APISchema{
+		{Path: "openapi.yaml", Kind: "openapi", Endpoints: []string{"GET /health"}},
+	}}
+	got := APIContext(state.Task{Title: "Add a handler for /ready"}, snap)
+	if !strings.Contains(got, "API SCHEMAS") || !strings.Contains(got, "openapi.yaml (OpenAPI)\n  GET /health\n") {
+		t.Errorf("APIContext() = %q", got)
+	}
+	if got := APIContext(state.Task{Title: "Write the README"}, snap); got != "" {
+		t.Errorf("APIContext() for a non-API task = %q, want empty", got)
+	}
+	if got := APIContext(state.Task{Title: "Add an endpoint"}, nil); got != "" {
+		t.Errorf("APIContext() without a snapshot = %q, want empty", got)
+	}
+}
+
 func TestBuildAllowedTools(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -120,3 +175,38 @@ func TestMaxTurnsForTask(t *testing.T) {
 		})
 	}
 }
+
+func TestAdaptMaxTurns(t *testing.T) {
+	t.Parallel()
+	done := func(complexity string, turns ...int) []state.Task {
+		var tasks []state.Task
+		for _, n := range turns {
+			tasks = append(tasks, state.Task{Complexity: complexity, Status: state.TaskDone, Turns: n})
+		}
+		return tasks
+	}
+	tests := []struct {
+		name        string
+		tasks       []state.Task
+		limit       int
+		wantAdapted int
+		wantN       int
+	}{
+		{"too few samples", done("small", 5, 6), 20, 20, 0},
+		{"shrinks to what was needed", done("small", 5, 6, 7, 8), 20, 10, 4},
+		{"grows after cutoffs", done("small", 20, 20, 18), 20, 25, 3},
+		{"at most twice the limit", done("small", 40, 45, 50), 20, 40, 3},
+		{"at least half the limit", done("small", 1, 1, 2), 20, 10, 3},
+		{"other complexities ignored", done("large", 5, 6, 7), 20, 20, 0},
+		{"unfinished tasks ignored", append(done("small", 5, 6), state.Task{Complexity: "small", Status: state.TaskFailed, Turns: 20}), 20, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			adapted, n := AdaptMaxTurns("small", tt.tasks, tt.limit)
+			if adapted != tt.wantAdapted || n != tt.wantN {
+				t.Errorf("AdaptMaxTurns = %d from %d, want %d from %d", adapted, n, tt.wantAdapted, tt.wantN)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+const (
+	rcaMaxTurns = 10    // the analyst only reads
+	maxRCALog   = 16000 // task log characters shown to the analyst
+)
+
+// RootCauseCategories are the kinds of failure the analyst picks from.
+var RootCauseCategories = []string{"plan", "spec", "environment", "code", "flaky"}
+
+// BuildRCASystemPrompt sets up the root cause analyst, who reads but never
+// edits.
+func BuildRCASystemPrompt() string {
+	return `You are diagnosing why an automated coding agent failed a task after several attempts.
+
+RULES:
+- Read the failure output, the agent's attempts and the code they touched; do not modify any files
+- Find the underlying cause, not the last symptom: a task too large or mis-scoped, an ambiguous spec, a missing dependency or service, a genuine code problem, a flaky test
+- Suggest concrete plan changes a planner can apply: split the task, add a prerequisite task, clarify a criterion, fix the environment
+
+Reply with exactly these lines:
+ROOT CAUSE: <one or two sentences>
+CATEGORY: <plan | spec | environment | code | flaky>
+SUGGESTION: <one plan change per line, most useful first>`
+}
+
+// BuildRCAPrompt asks for the root cause of a task that failed with reason,
+// given its accumulated log (Claude's replies and command output for every
+// attempt).
+func BuildRCAPrompt(contextContent string, task state.Task, reason, taskLog string) string {
+	var b strings.Builder
+
+	b.WriteString("PROJECT CONTEXT:\n")
+	b.WriteString(contextContent)
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "FAILED TASK: %s — %s\n", task.ID, task.Title)
+	if task.Description != "" {
+		b.WriteString(task.Description + "\n")
+	}
+	if len(task.AcceptanceCriteria) > 0 {
+		b.WriteString("\nACCEPTANCE CRITERIA:\n")
+		for _, c := range task.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+	fmt.Fprintf(&b, "\nOUTCOME: %s\n\n", reason)
+
+	b.WriteString("TASK LOG:\n")
+	b.WriteString(fence(TruncateTestOutput(taskLog, maxRCALog)))
+	b.WriteString("\n\nWhat is the root cause, and how should the plan change?\n")
+
+	return b.String()
+}
+
+var rcaLineRe = regexp.MustCompile(`(?i)^(?:[-*]\s*)?\**(ROOT CAUSE|CATEGORY|SUGGESTION)\**\s*:\s*(.+)$`)
+
+// ParseRootCause reads the analyst's reply. It is nil when the reply has
+// no ROOT CAUSE line.
+func ParseRootCause(text string) *state.RootCause {
+	var rc state.RootCause
+	for _, line := range strings.Split(text, "\n") {
+		m := rcaLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		value := strings.TrimSpace(strings.Trim(m[2], "*"))
+		switch strings.ToUpper(m[1]) {
+		case "ROOT CAUSE":
+			rc.Summary = value
+		case "CATEGORY":
+			rc.Category = strings.ToLower(strings.Trim(value, "<>"))
+		case "SUGGESTION":
+			rc.Suggestions = append(rc.Suggestions, value)
+		}
+	}
+	if rc.Summary == "" {
+		return nil
+	}
+	return &rc
+}
+
+// analyzeFailure asks Claude for the root cause of a task that exhausted
+// its retries and records it on the task, for the dashboard, the failure
+// issue and replanning. A failed analysis is reported and left out.
+func (r *Runner) analyzeFailure(ctx context.Context, task *state.Task, rp *repoCtx, env map[string]string, reason string, log *strings.Builder) {
+	if ctx.Err() != nil {
+		return
+	}
+	result, err := r.cfg.Claude.Execute(ctx, ExecuteOpts{
+		Prompt:       BuildRCAPrompt(r.cfg.ContextFile, *task, reason, log.String()),
+		SystemPrompt: BuildRCASystemPrompt(),
+		Model:        rp.settings.Provider.Model,
+		MaxTurns:     rcaMaxTurns,
+		AllowedTools: ReviewTools,
+		WorkDir:      rp.dir,
+		EnvVars:      env,
+	})
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "root cause analysis skipped: " + err.Error()})
+		return
+	}
+	r.addUsage(result)
+	log.WriteString("=== Root Cause Analysis ===\n" + result.Text + "\n\n")
+	rc := ParseRootCause(result.Text)
+	if rc == nil {
+		return
+	}
+	task.RootCause = rc
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventRootCause, Message: rc.Summary, Detail: rc.Format()})
+}
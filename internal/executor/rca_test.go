@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestParseRootCause(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		text string
+		want *state.RootCause
+	}{
+		{
+			name: "structured reply",
+			text: "I read the logs.\nROOT CAUSE: The tests need a running Postgres.\nCATEGORY: environment\nSUGGESTION: Add a manual task to start Postgres\nSUGGESTION: Mark integration tests with a build tag\n",
+			want: &state.RootCause{Summary: "The tests need a running Postgres.", Category: "environment",
+				Suggestions: []string{"Add a manual task to start Postgres", "Mark integration tests with a build tag"}},
+		},
+		{
+			name: "markdown emphasis",
+			text: "**ROOT CAUSE:** Task covers three endpoints at once\n- **Suggestion**: split it per endpoint\n**Category**: <Plan>",
+			want: &state.RootCause{Summary: "Task covers three endpoints at once", Category: "plan",
+				Suggestions: []string{"split it per endpoint"}},
+		},
+		{
+			name: "no diagnosis",
+			text: "I could not tell what went wrong.",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseRootCause(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRootCause() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRCAPrompt(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-004", Title: "Add payments", AcceptanceCriteria: []string{"POST /pay charges the card"}}
+	got := BuildRCAPrompt("Go, Gin", task, "tests failed after 3 attempts", "=== Test Output ===\nFAIL TestPay\n")
+	for _, want := range []string{"FAILED TASK: task-004 — Add payments", "- POST /pay charges the card",
+		"OUTCOME: tests failed after 3 attempts", "FAIL TestPay"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BuildRCAPrompt() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunTask_DiagnosesExhaustedTask(t *testing.T) {
+	t.Parallel()
+	s := testState(mkTask("task-001", "Payments", state.TaskPending, nil))
+	s.Settings.MaxRetries = 0
+	claude := NewMockClaudeExecutor(
+		&ExecuteResult{Text: "done"},
+		&ExecuteResult{Text: "ROOT CAUSE: STRIPE_KEY is unset\nCATEGORY: environment\nSUGGESTION: Add a manual setup task"},
+	)
+	var events []TaskEvent
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: t.TempDir(),
+		Git:    NewMockGitOps(),
+		Tests:  NewMockTestRunner(&TestResult{Passed: false, Output: "FAIL: missing STRIPE_KEY"}),
+		Claude: claude,
+		OnEvent: func(e TaskEvent) {
+			if e.Type == EventRootCause {
+				events = append(events, e)
+			}
+		},
+	})
+
+	outcome := runner.RunTask(context.Background(), &s.Tasks[0])
+	if outcome.Status != state.TaskFailed {
+		t.Fatalf("status = %q, want failed", outcome.Status)
+	}
+	analysis := claude.Calls[1]
+	if !strings.Contains(analysis.Prompt, "FAIL: missing STRIPE_KEY") || !reflect.DeepEqual(analysis.AllowedTools, ReviewTools) {
+		t.Errorf("analysis should read the task log with read-only tools: tools %v\n%s", analysis.AllowedTools, analysis.Prompt)
+	}
+	want := &state.RootCause{Summary: "STRIPE_KEY is unset", Category: "environment", Suggestions: []string{"Add a manual setup task"}}
+	if !reflect.DeepEqual(s.Tasks[0].RootCause, want) {
+		t.Errorf("RootCause = %+v, want %+v", s.Tasks[0].RootCause, want)
+	}
+	if len(events) != 1 || events[0].Message != "STRIPE_KEY is unset" || !strings.Contains(events[0].Detail, "- Add a manual setup task") {
+		t.Errorf("root cause events = %+v", events)
+	}
+	if !strings.Contains(outcome.Logs, "=== Root Cause Analysis ===") {
+		t.Error("the analysis should be in the task log")
+	}
+}
@@ -26,6 +26,68 @@ func BuildRetryPrompt(attempt, maxRetries int, testOutput string) string {
 	return prompt
 }
 
+// BuildPolicyRetryPrompt creates the retry prompt after the staged changes
+// broke the project's commit policy. Nothing was committed.
+func BuildPolicyRetryPrompt(attempt, maxRetries int, violations []PolicyViolation) string {
+	prompt := fmt.Sprintf("The previous attempt's changes were not committed because they break the project's commit policy. This is attempt %d of %d.\n",
+		attempt+1, 1+maxRetries)
+
+	prompt += "\nPOLICY VIOLATIONS:\n"
+	prompt += FormatPolicyViolations(violations)
+	prompt += "\nPlease fix them:\n"
+	prompt += "- Remove large generated or vendored files and add them to .gitignore\n"
+	prompt += "- Move credentials out of the code into environment variables; never commit real values\n"
+	prompt += "- Revert changes under .github/workflows/ — CI changes need a human\n"
+	prompt += "- Revert changes inside submodules and work around vendored code from the project itself\n"
+	prompt += "Keep the rest of the implementation and make sure tests still pass.\n"
+
+	return prompt
+}
+
+// BuildReviewRetryPrompt creates the retry prompt after the reviewer
+// blocked the staged changes. Nothing was committed.
+func BuildReviewRetryPrompt(attempt, maxRetries int, review Review) string {
+	prompt := fmt.Sprintf("The previous attempt's changes were not committed because code review found blocking problems. This is attempt %d of %d.\n",
+		attempt+1, 1+maxRetries)
+
+	prompt += "\nREVIEW FINDINGS:\n"
+	prompt += review.Format()
+	prompt += "\nPlease address every BLOCKING finding; SUGGESTIONs are optional.\n"
+	prompt += "Keep the rest of the implementation and make sure tests still pass.\n"
+
+	return prompt
+}
+
+// BuildHookRetryPrompt creates the retry prompt after the repository's git
+// hooks rejected the commit. Nothing was committed.
+func BuildHookRetryPrompt(attempt, maxRetries int, hookOutput string) string {
+	prompt := fmt.Sprintf("The previous attempt's changes were not committed because the repository's git hooks rejected the commit. This is attempt %d of %d.\n",
+		attempt+1, 1+maxRetries)
+
+	prompt += "\nHOOK OUTPUT:\n"
+	prompt += TruncateTestOutput(hookOutput, 4000)
+	prompt += "\n\nPlease fix what the hooks report (lint errors, formatting, commit checks) in the code itself; "
+	prompt += "don't edit or disable the hooks.\n"
+	prompt += "Keep the rest of the implementation and make sure tests still pass.\n"
+
+	return prompt
+}
+
+// BuildCIRetryPrompt creates the prompt for a retry after the pushed
+// commit passed local tests but failed the repository's CI.
+func BuildCIRetryPrompt(attempt, maxRetries int, ciOutput string) string {
+	prompt := fmt.Sprintf("The previous attempt passed the local tests and was pushed, but the repository's CI failed. This is attempt %d of %d.\n",
+		attempt+1, 1+maxRetries)
+
+	prompt += "\nFAILED CI JOBS:\n"
+	prompt += TruncateTestOutput(ciOutput, 6000)
+	prompt += "\n\nCI runs checks the local test command doesn't (lint, other platforms or versions, integration tests). "
+	prompt += "Fix the cause in the code; don't edit or disable the CI workflows.\n"
+	prompt += "Your previous changes are already committed; build on them.\n"
+
+	return prompt
+}
+
 // TruncateTestOutput trims test output to maxChars, keeping the
 // beginning and end (the most useful parts). Inserts a truncation
 // notice in the middle.
@@ -45,3 +107,16 @@ func TruncateTestOutput(output string, maxChars int) string {
 		fmt.Sprintf("\n\n[... %d chars truncated ...]\n\n", truncated) +
 		output[len(output)-keepEach:]
 }
+
+// FormatFailingTests renders the failing test names picked out of the
+// test output by the project's language plugin. Returns "" when there are none.
+func FormatFailingTests(failures []string) string {
+	if len(failures) == 0 {
+		return ""
+	}
+	s := "\nFAILING TESTS:\n"
+	for _, f := range failures {
+		s += "- " + f + "\n"
+	}
+	return s
+}
@@ -107,3 +107,17 @@ func longString(n int) string {
 	}
 	return string(b)
 }
+
+func TestFormatFailingTests(t *testing.T) {
+	t.Parallel()
+
+	if got := FormatFailingTests(nil); got != "" {
+		t.Errorf("FormatFailingTests(nil) = %q, want empty", got)
+	}
+
+	got := FormatFailingTests([]string{"TestA", "TestB"})
+	want := "\nFAILING TESTS:\n- TestA\n- TestB\n"
+	if got != want {
+		t.Errorf("FormatFailingTests() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+const (
+	maxReviewDiff  = 40000 // diff characters shown to the reviewer
+	reviewMaxTurns = 10    // the reviewer only reads
+)
+
+// ReviewTools are the reviewer's tools: enough to read around the diff,
+// nothing that changes the worktree.
+var ReviewTools = []string{"Read", "Grep", "Glob"}
+
+// Review is a reviewer's verdict on a task's staged changes. Blocking
+// findings send the task back for a fix before it is committed;
+// suggestions are only logged.
+type Review struct {
+	Blocking    []string
+	Suggestions []string
+}
+
+// Approved reports whether nothing blocks the commit.
+func (r Review) Approved() bool {
+	return len(r.Blocking) == 0
+}
+
+// Format lists the findings, blocking first.
+func (r Review) Format() string {
+	var b strings.Builder
+	for _, f := range r.Blocking {
+		b.WriteString("- BLOCKING: " + f + "\n")
+	}
+	for _, f := range r.Suggestions {
+		b.WriteString("- SUGGESTION: " + f + "\n")
+	}
+	return b.String()
+}
+
+// BuildReviewSystemPrompt sets up the reviewer, who reads but never edits.
+func BuildReviewSystemPrompt() string {
+	return `You are a senior engineer reviewing a colleague's change before it is committed.
+
+RULES:
+- Judge the diff against the task's acceptance criteria and the project's conventions
+- Read surrounding code when you need context; do not modify any files
+- Block only on real problems: unmet criteria, bugs, missing error handling, broken conventions, missing tests for new behavior
+- Style preferences and optional improvements are suggestions, not blockers
+
+Reply with one finding per line:
+BLOCKING: <file:line — problem and what to do instead>
+SUGGESTION: <file:line — optional improvement>
+End with "VERDICT: APPROVE" if nothing blocks, otherwise "VERDICT: REQUEST_CHANGES".`
+}
+
+// BuildReviewPrompt asks for a review of diff, the task's staged changes.
+func BuildReviewPrompt(contextContent string, task state.Task, diff string) string {
+	var b strings.Builder
+
+	b.WriteString("PROJECT CONTEXT:\n")
+	b.WriteString(contextContent)
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "TASK: %s — %s\n", task.ID, task.Title)
+	if task.Description != "" {
+		b.WriteString(task.Description + "\n")
+	}
+	b.WriteString("\n")
+
+	if len(task.AcceptanceCriteria) > 0 {
+		b.WriteString("ACCEPTANCE CRITERIA:\n")
+		for _, c := range task.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("STAGED DIFF:\n")
+	b.WriteString(fence(TruncateTestOutput(diff, maxReviewDiff)))
+	b.WriteString("\n\nReview this change.\n")
+
+	return b.String()
+}
+
+var (
+	reviewFindingRe = regexp.MustCompile(`(?i)^(?:[-*]\s*)?\**(BLOCKING|SUGGESTION)\**\s*:\s*(.+)$`)
+	reviewVerdictRe = regexp.MustCompile(`(?i)^\**VERDICT\**\s*:\s*\**\s*REQUEST[_ ]CHANGES`)
+)
+
+// ParseReview reads the reviewer's findings. A REQUEST_CHANGES verdict
+// without any blocking line still blocks, so a terse reviewer can't be
+// misread as approving.
+func ParseReview(text string) Review {
+	var r Review
+	requested := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if reviewVerdictRe.MatchString(line) {
+			requested = true
+			continue
+		}
+		m := reviewFindingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if strings.EqualFold(m[1], "BLOCKING") {
+			r.Blocking = append(r.Blocking, strings.TrimSpace(m[2]))
+		} else {
+			r.Suggestions = append(r.Suggestions, strings.TrimSpace(m[2]))
+		}
+	}
+	if requested && len(r.Blocking) == 0 {
+		r.Blocking = []string{"reviewer requested changes without listing them; re-check the acceptance criteria"}
+	}
+	return r
+}
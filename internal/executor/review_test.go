@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestParseReview(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		text string
+		want Review
+	}{
+		{
+			name: "approved with suggestions",
+			text: "Looks good.\nSUGGESTION: store.go:12 — name the constant\nVERDICT: APPROVE\n",
+			want: Review{Suggestions: []string{"store.go:12 — name the constant"}},
+		},
+		{
+			name: "blocking findings in a markdown list",
+			text: "- **BLOCKING**: api.go:40 — error from Save is ignored\n* blocking: no test for the 404 path\nVERDICT: REQUEST_CHANGES",
+			want: Review{Blocking: []string{"api.go:40 — error from Save is ignored", "no test for the 404 path"}},
+		},
+		{
+			name: "changes requested without details",
+			text: "VERDICT: REQUEST_CHANGES",
+			want: Review{Blocking: []string{"reviewer requested changes without listing them; re-check the acceptance criteria"}},
+		},
+		{
+			name: "prose mentioning blocking is not a finding",
+			text: "Nothing blocking here: the handler is fine.\nVERDICT: APPROVE",
+			want: Review{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ParseReview(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReview() = %+v, want %+v", got, tt.want)
+			}
+			if got.Approved() != (len(tt.want.Blocking) == 0) {
+				t.Errorf("Approved() = %v", got.Approved())
+			}
+		})
+	}
+}
+
+func TestBuildReviewPrompt(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-004", Title: "Add search", Description: "Full-text search",
+		AcceptanceCriteria: []string{"GET /search returns matches"}}
+	diff := "diff --git a/search.go b/search.go\n+func Search() {}\n"
+	got := BuildReviewPrompt("Go, Gin", task, diff)
+	for _, want := range []string{"PROJECT CONTEXT:\nGo, Gin", "TASK: task-004 — Add search", "- GET /search returns matches", "```\n" + diff} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BuildReviewPrompt() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuildReviewRetryPrompt(t *testing.T) {
+	t.Parallel()
+	review := Review{Blocking: []string{"api.go:40 — error ignored"}, Suggestions: []string{"rename x"}}
+	got := BuildReviewRetryPrompt(1, 2, review)
+	for _, want := range []string{"attempt 2 of 3", "- BLOCKING: api.go:40 — error ignored", "- SUGGESTION: rename x"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BuildReviewRetryPrompt() missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -1,20 +1,47 @@
 package executor
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/manasm11/forge/internal/codeindex"
+	"github.com/manasm11/forge/internal/generator"
+	"github.com/manasm11/forge/internal/knowledge"
+	"github.com/manasm11/forge/internal/platform"
+	"github.com/manasm11/forge/internal/preflight"
 	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/scanner"
+	"github.com/manasm11/forge/internal/schedule"
 	"github.com/manasm11/forge/internal/state"
 )
 
+// ErrBudgetExhausted is returned by Run when it paused at Settings.MaxBudget.
+var ErrBudgetExhausted = errors.New("budget exhausted")
+
 // Runner orchestrates task execution.
 type Runner struct {
 	cfg RunnerConfig
+
+	toolsOnce sync.Once
+	tools     map[string]string // tool versions, captured once per runner
+
+	runID int // ID of the RunSummary being recorded (0 outside Run)
+
+	stateSum string // fingerprint of state.json as the runner last saw it
+	conflict bool   // state.json changed underneath the runner; stop saving
+
+	repos map[string]*repoCtx // workspace repositories opened so far
+
+	index *codeindex.Index // code search index (nil = off)
+
+	diffs map[string]DiffSummary // task ID -> what it committed in this run
 }
 
 // NewRunner creates a new execution runner.
@@ -35,26 +62,112 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}
 
-	// Track completed task branches for merging
+	// Narrow the checkout before any task branch is created; the setting
+	// belongs to the clone, so every branch checked out afterwards keeps it
+	if s := r.cfg.State.Settings; s != nil && len(s.SparsePaths) > 0 {
+		if err := r.cfg.Git.SparseCheckout(ctx, s.SparsePaths); err != nil {
+			return fmt.Errorf("failed to configure sparse checkout: %w", err)
+		}
+	}
+
+	// Keep forge's generated files out of `git add -A`, also in projects
+	// whose .forge/.gitignore predates some of them
+	if err := state.EnsureGitignore(r.cfg.StateRoot); err != nil {
+		return fmt.Errorf("failed to update .forge/.gitignore: %w", err)
+	}
+
+	// Never start on top of the user's uncommitted work
+	if err := r.guardWorktree(ctx); err != nil {
+		return err
+	}
+	r.refreshIndex(ctx)
+
+	// Record this run in state so later sessions can see it. Everything
+	// the run writes from here on must land on top of this file.
+	r.stateSum, _ = state.Fingerprint(r.cfg.StateRoot)
+	started := r.now()
+	r.runID = r.cfg.State.StartRun(started)
+	// A task still in progress was left by a run that died mid-task
+	for i := range r.cfg.State.Tasks {
+		if r.cfg.State.Tasks[i].Status == state.TaskInProgress {
+			r.cfg.State.Tasks[i].Status = state.TaskPending
+		}
+	}
+	skippedBefore := countSkipped(r.cfg.State.Tasks)
+	r.save()
+
+	// Track completed task branches for merging, per repository
 	var completedBranches []string
+	merges := map[string][]string{} // repo path ("" = root) -> branches
+	var mergeOrder []string
+	budgetExhausted := false
+	var paused error // ErrPaused variant, if the run stopped for a human
 
 	for {
 		if ctx.Err() != nil {
+			r.finishRun(state.RunCancelled, skippedBefore)
 			return ctx.Err()
 		}
 
+		// Pick up plan changes made since the last task, from this
+		// session's TUI or from another process (e.g. `forge cancel`)
+		r.reload("")
+		r.applyEdits()
+		if r.conflict {
+			paused = ErrStateConflict
+			break
+		}
+
 		// ExecutableTasks handles skipping tasks with failed/cancelled deps
 		executable := r.cfg.State.ExecutableTasks()
 		if len(executable) == 0 {
 			break
 		}
 
+		// Plan order, unless the user asked for the riskiest task or the
+		// longest remaining dependency chain first
+		next := executable[0]
+		if s := r.cfg.State.Settings; s != nil && s.FrontLoadRisk {
+			next = state.RiskiestTask(executable, r.cfg.State.Tasks)
+		} else if s != nil && s.CriticalPathFirst {
+			next = state.CriticalTask(executable, r.cfg.State.Tasks)
+		}
+
 		// Find the actual task in state (not the copy from ExecutableTasks)
-		stateTask := r.cfg.State.FindTask(executable[0].ID)
+		stateTask := r.cfg.State.FindTask(next.ID)
 		if stateTask == nil {
 			break
 		}
 
+		// Don't start a task that could fill the disk halfway through
+		if reason := r.lowDiskSpace(); reason != "" {
+			r.emit(TaskEvent{Type: EventRunPaused, Message: reason})
+			paused = ErrLowDiskSpace
+			break
+		}
+
+		// Stop between tasks rather than overrun a limit mid-task
+		if reason, resumeAt := r.runLimit(started, stateTask.ID); reason != "" {
+			r.emit(TaskEvent{Type: EventRunPaused, Message: reason})
+			if run := r.cfg.State.FindRun(r.runID); run != nil {
+				run.Checkpoint = &state.Checkpoint{Reason: reason, NextTask: stateTask.ID, ResumeAt: resumeAt}
+			}
+			paused = ErrRunLimit
+			break
+		}
+
+		// Mark the task running on disk too, so `forge cancel` refuses it
+		// rather than have the cancel overwritten when the task finishes
+		stateTask.Status = state.TaskInProgress
+		r.saveOwning(stateTask.ID)
+		if r.conflict {
+			stateTask.Status = state.TaskPending
+			paused = ErrStateConflict
+			break
+		}
+		// The save may have reloaded state; work on the current copy
+		stateTask = r.cfg.State.FindTask(next.ID)
+
 		outcome := r.RunTask(ctx, stateTask)
 
 		// Update state
@@ -66,16 +179,45 @@ func (r *Runner) Run(ctx context.Context) error {
 			// Track branch for merging
 			if stateTask.Branch != "" {
 				completedBranches = append(completedBranches, stateTask.Branch)
+				if _, ok := merges[stateTask.Repo]; !ok {
+					mergeOrder = append(mergeOrder, stateTask.Repo)
+				}
+				merges[stateTask.Repo] = append(merges[stateTask.Repo], stateTask.Branch)
 			}
 		}
 		stateTask.Retries = outcome.Retries
 
+		if run := r.cfg.State.FindRun(r.runID); run != nil {
+			run.TaskIDs = append(run.TaskIDs, stateTask.ID)
+			run.Retries += outcome.Retries
+			switch outcome.Status {
+			case state.TaskDone:
+				run.Completed++
+			case state.TaskFailed:
+				run.Failed++
+			}
+			run.Skipped = countSkipped(r.cfg.State.Tasks) - skippedBefore
+		}
+
 		// Persist state after each task
-		state.Save(r.cfg.StateRoot, r.cfg.State)
+		r.saveOwning(stateTask.ID)
 
 		// Write log file
 		r.writeLog(stateTask.ID, outcome.Logs)
 
+		// Someone else changed the plan; let them have it
+		if r.conflict {
+			paused = ErrStateConflict
+			break
+		}
+
+		// Stay on the task branch so the staged changes can be inspected
+		if outcome.NeedsReview {
+			r.emit(TaskEvent{TaskID: stateTask.ID, Type: EventRunPaused, Message: outcome.Error})
+			paused = ErrChangeTooLarge
+			break
+		}
+
 		// Return to base branch
 		r.cfg.Git.CheckoutBranch(ctx, baseBranch)
 
@@ -83,55 +225,447 @@ func (r *Runner) Run(ctx context.Context) error {
 		if outcome.Status == state.TaskDone {
 			r.emit(TaskEvent{TaskID: stateTask.ID, Type: EventTaskDone, Message: "completed"})
 		}
+
+		// Stop spending once the cap is reached; finished work still merges below
+		if over, reason := r.budgetExceeded(); over {
+			r.emit(TaskEvent{Type: EventBudgetExhausted, Message: reason})
+			budgetExhausted = true
+			break
+		}
 	}
 
-	// After all tasks, handle merging/pushing
-	if len(completedBranches) > 0 {
-		// Merge all completed branches into base branch
-		for _, branch := range completedBranches {
-			if err := r.cfg.Git.Merge(ctx, branch); err != nil {
-				r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf("failed to merge %s: %v", branch, err)})
+	// After all tasks, handle merging/pushing. A change awaiting review
+	// leaves the worktree dirty, so merging has to wait for the next run;
+	// after a state conflict another forge may be working in this repo.
+	if len(completedBranches) > 0 && errors.Is(paused, ErrChangeTooLarge) {
+		r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf(
+			"not merged while a change awaits review: %s", strings.Join(completedBranches, ", "))})
+	} else if len(completedBranches) > 0 && errors.Is(paused, ErrStateConflict) {
+		r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf(
+			"not merged after the state conflict: %s", strings.Join(completedBranches, ", "))})
+	} else if len(completedBranches) > 0 {
+		var verification *state.Verification
+		for _, path := range mergeOrder {
+			rp, err := r.repoFor(path)
+			if err != nil {
+				r.emit(TaskEvent{Type: EventError, Message: err.Error()})
+				continue
+			}
+			base := baseBranch
+			if path != "" {
+				base = rp.settings.BaseBranch
+				rp.git.CheckoutBranch(ctx, base)
+			}
+
+			// Merge all completed branches into base branch
+			for _, branch := range merges[path] {
+				if err := rp.git.Merge(ctx, branch); err != nil {
+					r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf("%sfailed to merge %s: %v", rp.label(), branch, err)})
+				}
 			}
+
+			// Checkout base branch after merging
+			rp.git.CheckoutBranch(ctx, base)
+
+			// Re-check the integrated result before pushing it
+			v := r.verify(ctx, rp)
+			verification = mergeVerification(verification, v)
+			r.markPRsReady(ctx, rp, v)
+
+			// A finished plan gets its changelog entry before the push
+			if path == "" {
+				r.writeChangelog(ctx)
+			}
+
+			// Push if remote exists
+			if rp.remoteURL != "" {
+				if err := rp.git.Push(ctx); err != nil {
+					r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf("%sfailed to push: %v", rp.label(), err)})
+				} else if v != nil {
+					if sha, err := rp.git.LatestSHA(ctx); err == nil {
+						r.publishCheck(ctx, "", rp, 0, VerificationCheckRun("forge: verification", sha, v))
+					}
+				}
+			} else {
+				r.emit(TaskEvent{Type: EventPush, Message: rp.label() + "No remote configured - skipped push"})
+			}
+		}
+		if run := r.cfg.State.FindRun(r.runID); run != nil {
+			run.Verification = verification
 		}
+	}
 
-		// Checkout base branch after merging
-		r.cfg.Git.CheckoutBranch(ctx, baseBranch)
+	if budgetExhausted {
+		r.finishRun(state.RunBudgetExhausted, skippedBefore)
+		return ErrBudgetExhausted
+	}
+	if paused != nil {
+		r.finishRun(state.RunPaused, skippedBefore)
+		return paused
+	}
+	r.finishRun(state.RunCompleted, skippedBefore)
+	return nil
+}
 
-		// Push if remote exists
-		if r.cfg.RemoteURL != "" {
-			if err := r.cfg.Git.Push(ctx); err != nil {
-				r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf("failed to push: %v", err)})
+// budgetExceeded checks this run's usage against Settings.MaxBudget.
+func (r *Runner) budgetExceeded() (bool, string) {
+	settings := r.cfg.State.Settings
+	run := r.cfg.State.FindRun(r.runID)
+	if settings == nil || run == nil {
+		return false, ""
+	}
+	return settings.MaxBudget.Exceeded(provider.Usage{Tokens: run.TokensUsed, CostUSD: run.CostUSD})
+}
+
+// runLimit returns why task next shouldn't start under Settings.MaxRunDuration
+// or Settings.QuietHours, or "" if it may. For quiet hours it also returns
+// when they end. Invalid values are rejected in the inputs phase and
+// ignored here.
+func (r *Runner) runLimit(started time.Time, next string) (string, *time.Time) {
+	settings := r.cfg.State.Settings
+	if settings == nil {
+		return "", nil
+	}
+	now := r.now()
+	if limit, err := time.ParseDuration(settings.MaxRunDuration); err == nil && limit > 0 && now.Sub(started) >= limit {
+		return fmt.Sprintf("run reached its %s limit — run again to continue with %s", limit, next), nil
+	}
+	if settings.QuietHours == "" {
+		return "", nil
+	}
+	if w, err := schedule.ParseWindow(settings.QuietHours); err == nil && w.Contains(now) {
+		end := w.NextEnd(now)
+		return fmt.Sprintf("quiet hours (%s) — run again after %s to continue with %s", w, end.Format("15:04"), next), &end
+	}
+	return "", nil
+}
+
+func (r *Runner) now() time.Time {
+	if r.cfg.Clock != nil {
+		return r.cfg.Clock()
+	}
+	return time.Now()
+}
+
+// lowDiskSpace returns why the next task shouldn't start, or "" if there
+// is enough free space (or it can't be measured).
+func (r *Runner) lowDiskSpace() string {
+	freeSpace := r.cfg.FreeSpace
+	if freeSpace == nil {
+		freeSpace = platform.FreeDiskSpace
+	}
+	free, err := freeSpace(r.cfg.StateRoot)
+	if err != nil || free >= preflight.MinFreeDisk {
+		return ""
+	}
+	return fmt.Sprintf("only %s of disk space left (need %s) — free some up and run again",
+		preflight.FormatBytes(free), preflight.FormatBytes(preflight.MinFreeDisk))
+}
+
+// changeTooLarge returns why a task's staged changes shouldn't be
+// committed, or "" if they are within Settings.MaxChangeMB.
+func (r *Runner) changeTooLarge(ctx context.Context, rp *repoCtx) string {
+	settings := rp.settings
+	if settings.MaxChangeMB <= 0 {
+		return ""
+	}
+	files, err := rp.git.StagedFiles(ctx)
+	if err != nil {
+		return ""
+	}
+	total, largest := StagedSize(rp.dir, files)
+	if total <= int64(settings.MaxChangeMB)<<20 {
+		return ""
+	}
+	return FormatChangeTooLarge(total, settings.MaxChangeMB, largest)
+}
+
+// checkPolicy checks the staged files against Settings.CommitPolicy.
+func (r *Runner) checkPolicy(ctx context.Context, rp *repoCtx) []PolicyViolation {
+	p := rp.settings.CommitPolicy
+	if p == (state.CommitPolicy{}) {
+		return nil
+	}
+	files, err := rp.git.StagedFiles(ctx)
+	if err != nil {
+		return nil
+	}
+	return CheckCommitPolicy(rp.dir, files, p)
+}
+
+// applyEdits applies all queued plan edits without blocking. Each accepted
+// edit is persisted immediately; rejected edits are reported as errors.
+func (r *Runner) applyEdits() {
+	for {
+		select {
+		case edit, ok := <-r.cfg.Edits:
+			if !ok {
+				return
+			}
+			before := make(map[string]state.TaskStatus, len(r.cfg.State.Tasks))
+			for _, t := range r.cfg.State.Tasks {
+				before[t.ID] = t.Status
+			}
+			if err := edit.Apply(r.cfg.State); err != nil {
+				r.emit(TaskEvent{Type: EventEditRejected, TaskID: edit.TaskID, Message: fmt.Sprintf("%s: %v", edit.Description, err)})
+				continue
 			}
+			r.save()
+			r.emit(TaskEvent{Type: EventPlanChanged, TaskID: edit.TaskID, Message: edit.Description})
+
+			r.emitStatusChanges(before)
+		default:
+			return
+		}
+	}
+}
+
+// verify runs the build, test and lint commands once more on the merged
+// base branch. Every configured step runs even if an earlier one fails, so
+// the report is complete. Returns nil when no commands are configured.
+func (r *Runner) verify(ctx context.Context, rp *repoCtx) *state.Verification {
+	settings := rp.settings
+	if settings == nil {
+		return nil
+	}
+
+	steps := []struct {
+		name, command string
+		run           func(context.Context, string) *TestResult
+	}{
+		{rp.label() + "build", settings.BuildCommand, rp.tests.RunBuild},
+		{rp.label() + "test", settings.TestCommand, rp.tests.RunTests},
+		{rp.label() + "lint", settings.LintCommand, rp.tests.RunBuild},
+	}
+
+	v := &state.Verification{Passed: true}
+	var log strings.Builder
+	for _, step := range steps {
+		if step.command == "" {
+			continue
+		}
+		r.emit(TaskEvent{Type: EventVerifyStart, Message: step.name})
+		result := step.run(ctx, step.command)
+		log.WriteString(fmt.Sprintf("=== %s: %s ===\n%s\n\n", step.name, step.command, result.Output))
+
+		v.Steps = append(v.Steps, state.VerificationStep{
+			Name: step.name, Command: step.command,
+			Passed: result.Passed, Duration: result.Duration,
+		})
+		if result.Passed {
+			r.emit(TaskEvent{Type: EventVerifyPassed, Message: step.name})
 		} else {
-			r.emit(TaskEvent{Type: EventPush, Message: "No remote configured - skipped push"})
+			v.Passed = false
+			r.emit(TaskEvent{Type: EventVerifyFailed, Message: step.name, Detail: result.Output})
+		}
+	}
+	if len(v.Steps) == 0 {
+		return nil
+	}
+
+	name := fmt.Sprintf("run-%d-verification", r.runID)
+	if rp.path != "" {
+		name += "-" + strings.NewReplacer("/", "-", "\\", "-").Replace(rp.path)
+	}
+	r.writeLog(name, log.String())
+	return v
+}
+
+// mergeVerification combines the verification passes of the repositories
+// a run merged into; either may be nil.
+func mergeVerification(a, b *state.Verification) *state.Verification {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &state.Verification{Passed: a.Passed && b.Passed, Steps: append(a.Steps, b.Steps...)}
+}
+
+// emitStatusChanges reports tasks whose status a plan edit changed, so the
+// dashboard can follow skips, manual completions and unblocked tasks.
+func (r *Runner) emitStatusChanges(before map[string]state.TaskStatus) {
+	for _, t := range r.cfg.State.Tasks {
+		prev, ok := before[t.ID]
+		if !ok || prev == t.Status {
+			continue
+		}
+		switch t.Status {
+		case state.TaskSkipped:
+			r.emit(TaskEvent{Type: EventTaskSkipped, TaskID: t.ID, Message: t.SkippedReason})
+		case state.TaskDone:
+			r.emit(TaskEvent{Type: EventTaskDone, TaskID: t.ID, Message: "marked done manually at " + shortSHA(t.GitSHA)})
+		case state.TaskPending:
+			r.emit(TaskEvent{Type: EventTaskReset, TaskID: t.ID, Message: "dependencies satisfied"})
 		}
 	}
+}
 
-	return nil
+// finishRun closes the current run summary and persists it.
+func (r *Runner) finishRun(status state.RunStatus, skippedBefore int) {
+	run := r.cfg.State.FindRun(r.runID)
+	if run == nil {
+		return
+	}
+	now := time.Now()
+	run.Status = status
+	run.FinishedAt = &now
+	run.Skipped = countSkipped(r.cfg.State.Tasks) - skippedBefore
+	r.save()
+}
+
+// save persists state unless state.json changed on disk since the runner
+// last wrote it; see saveOwning.
+func (r *Runner) save() {
+	r.saveOwning("")
+}
+
+// saveOwning persists state. If state.json changed on disk since the
+// runner last wrote it, the change is merged first (see reload), keeping
+// the runner's copy of taskID. Changes that can't be merged pause the run;
+// nothing is written after that.
+func (r *Runner) saveOwning(taskID string) {
+	if r.conflict {
+		return
+	}
+	sum, err := state.SaveIfUnchanged(r.cfg.StateRoot, r.cfg.State, r.stateSum)
+	if errors.Is(err, state.ErrStateChanged) && r.reload(taskID) {
+		sum, err = state.SaveIfUnchanged(r.cfg.StateRoot, r.cfg.State, r.stateSum)
+	}
+	if errors.Is(err, state.ErrStateChanged) {
+		r.pauseForConflict()
+		return
+	}
+	if err == nil {
+		r.stateSum = sum
+	}
+}
+
+// reload adopts state.json if another process changed it since the
+// runner's last write, keeping what the runner owns: this run's summary
+// and the task it just finished (taskID, if any). Only edits of the same
+// plan are merged; a file with a different plan version or without this
+// run (e.g. a teammate's pushed plan) pauses the run instead. Reports
+// whether the in-memory state matches the file afterwards.
+func (r *Runner) reload(taskID string) bool {
+	if r.conflict {
+		return false
+	}
+	sum, err := state.Fingerprint(r.cfg.StateRoot)
+	if err != nil || sum == r.stateSum {
+		return err == nil
+	}
+	disk, err := state.Load(r.cfg.StateRoot)
+	if err != nil || disk == nil || disk.PlanVersion != r.cfg.State.PlanVersion || disk.FindRun(r.runID) == nil {
+		r.pauseForConflict()
+		return false
+	}
+
+	if run := r.cfg.State.FindRun(r.runID); run != nil {
+		*disk.FindRun(r.runID) = *run
+	}
+	if own := r.cfg.State.FindTask(taskID); own != nil {
+		if t := disk.FindTask(taskID); t != nil {
+			*t = *own
+		}
+	}
+
+	before := make(map[string]state.TaskStatus, len(r.cfg.State.Tasks))
+	for _, t := range r.cfg.State.Tasks {
+		before[t.ID] = t.Status
+	}
+	*r.cfg.State = *disk
+	r.stateSum = sum
+	r.emit(TaskEvent{Type: EventStateReloaded, Message: "picked up changes to state.json from another process"})
+	r.emitStatusChanges(before)
+	return true
+}
+
+func (r *Runner) pauseForConflict() {
+	if r.conflict {
+		return
+	}
+	r.conflict = true
+	r.emit(TaskEvent{Type: EventRunPaused, Message: "state.json was replaced by another process — not overwriting it; restart forge to load the new plan"})
+}
+
+// addUsage charges a Claude call's tokens and cost to the current run.
+func (r *Runner) addUsage(result *ExecuteResult) {
+	if run := r.cfg.State.FindRun(r.runID); run != nil {
+		run.TokensUsed += result.TokensUsed
+		run.CostUSD += result.CostUSD
+	}
+}
+
+func countSkipped(tasks []state.Task) int {
+	n := 0
+	for _, t := range tasks {
+		if t.Status == state.TaskSkipped {
+			n++
+		}
+	}
+	return n
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
 }
 
 // RunTask executes a single task.
 func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
+	switch task.EffectiveType() {
+	case state.TaskTypeVerify:
+		return r.runVerifyTask(ctx, task)
+	case state.TaskTypeManual:
+		return r.runManualTask(ctx, task)
+	}
+
 	var log strings.Builder
-	settings := r.cfg.State.Settings
+	rp, err := r.repoFor(task.Repo)
+	if err != nil {
+		return r.fail(task.ID, err.Error(), &log, 0)
+	}
+	settings := rp.settings
 	branchName := ResolveBranchName(settings.BranchPattern, task.ID)
 	branchName = SanitizeBranchName(branchName)
 	task.Branch = branchName
 
-	// Record base branch for returning later
-	baseBranch, _ := r.cfg.Git.CurrentBranch(ctx)
+	// Record base branch for returning later. A workspace repository is
+	// left wherever its last task put it, so start from its base branch.
+	if rp.path != "" {
+		if err := rp.git.CheckoutBranch(ctx, settings.BaseBranch); err != nil {
+			return r.fail(task.ID, "checkout base branch: "+err.Error(), &log, 0)
+		}
+	}
+	baseBranch, _ := rp.git.CurrentBranch(ctx)
 
 	// Emit start event
 	r.emit(TaskEvent{TaskID: task.ID, Type: EventTaskStart, Message: task.Title})
 
+	// Pick up what was pushed to the base branch since the run began
+	upstream := r.syncBase(ctx, task, rp, baseBranch)
+
 	// 1. Branch setup
-	exists, _ := r.cfg.Git.BranchExists(ctx, branchName)
+	exists, _ := rp.git.BranchExists(ctx, branchName)
 	if exists {
-		if err := r.cfg.Git.CheckoutBranch(ctx, branchName); err != nil {
+		if err := rp.git.CheckoutBranch(ctx, branchName); err != nil {
 			return r.fail(task.ID, "checkout existing branch: "+err.Error(), &log, 0)
 		}
+		if upstream != nil && settings.BaseDrift == DriftRebase {
+			if err := rp.git.SyncBranch(ctx, baseBranch, true); err != nil {
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "rebasing " + branchName + " onto " + baseBranch + ": " + err.Error()})
+			} else {
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventBaseDrift,
+					Message: rp.label() + branchName + " rebased onto " + baseBranch + "; its commits have new SHAs"})
+			}
+		}
 	} else {
-		if err := r.cfg.Git.CreateBranch(ctx, branchName, baseBranch); err != nil {
+		if err := rp.git.CreateBranch(ctx, branchName, baseBranch); err != nil {
 			return r.fail(task.ID, "create branch: "+err.Error(), &log, 0)
 		}
 	}
@@ -141,6 +675,31 @@ func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
 	maxRetries := settings.MaxRetries
 	maxAttempts := 1 + maxRetries
 	var lastTestOutput string
+	var lastViolations []PolicyViolation // set when the last attempt broke the commit policy
+	var lastReview *Review               // set when the reviewer blocked the last attempt
+	var lastHookOutput string            // set when git hooks rejected the last attempt's commit
+	var lastCIOutput string              // set when CI failed on the last attempt's pushed commit
+	var prompts []string                 // sent to Claude, for the failure issue
+	var lessons []knowledge.Entry        // reported while recovering from failed attempts
+	var handoff string                   // the latest handoff note Claude wrote
+	var committed *DiffSummary           // the last commit, for dependent tasks
+
+	// Build provider env vars
+	providerEnv := provider.EnvVarsForProvider(settings.Provider)
+
+	// Merge: settings.EnvVars + provider env vars (provider wins on collision)
+	mergedEnv := provider.MergeEnvVars(settings.EnvVars, providerEnv)
+
+	r.recordEnv(ctx, task.ID, mergedEnv)
+	kb := r.loadKnowledge(task.ID)
+
+	maxTurns := MaxTurnsForTask(task.Complexity, settings.MaxTurns)
+	if settings.AdaptiveTurns {
+		if adapted, n := AdaptMaxTurns(task.Complexity, r.cfg.State.Tasks, maxTurns); n > 0 {
+			log.WriteString(fmt.Sprintf("=== Max turns: %d (configured %d, from %d similar task(s)) ===\n\n", adapted, maxTurns, n))
+			maxTurns = adapted
+		}
+	}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		if ctx.Err() != nil {
@@ -150,47 +709,104 @@ func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
 		// Build prompt
 		var prompt string
 		if attempt == 0 {
-			prompt = BuildTaskExecutionPrompt(r.cfg.ContextFile, *task, settings)
+			prompt = TaskPrompt(r.cfg.ContextFile+APIContext(*task, r.cfg.State.Snapshot), *task, settings)
+			prompt += r.architecture(task.ID)
+			prompt += HandoffSection(*task, r.cfg.State.Tasks)
+			prompt += DependencySection(*task, r.cfg.State.Tasks, r.diffs)
+			prompt += UpstreamSection(upstream)
+			prompt += RelevantFilesSection(scanner.RelevantFiles(rp.dir, taskText(*task), settings.WarmStartFiles))
+			prompt += r.relatedCode(ctx, task, rp)
+			prompt += knowledge.Section(kb.Relevant(taskText(*task), maxPitfalls))
+			prompt += HandoffInstruction
 		} else {
 			r.emit(TaskEvent{TaskID: task.ID, Type: EventRetry,
 				Message: fmt.Sprintf("Retry %d/%d", attempt, maxRetries)})
-			prompt = BuildRetryPrompt(attempt, maxRetries, lastTestOutput)
+			if len(lastViolations) > 0 {
+				prompt = BuildPolicyRetryPrompt(attempt, maxRetries, lastViolations)
+			} else if lastReview != nil {
+				prompt = BuildReviewRetryPrompt(attempt, maxRetries, *lastReview)
+			} else if lastHookOutput != "" {
+				prompt = BuildHookRetryPrompt(attempt, maxRetries, lastHookOutput)
+			} else if lastCIOutput != "" {
+				prompt = BuildCIRetryPrompt(attempt, maxRetries, lastCIOutput)
+			} else {
+				prompt = BuildRetryPrompt(attempt, maxRetries, lastTestOutput)
+				if snap := r.cfg.State.Snapshot; snap != nil {
+					prompt += FormatFailingTests(scanner.ParseFailures(snap.Language, lastTestOutput))
+				}
+			}
+			prompt += knowledge.Section(kb.Relevant(lastTestOutput+lastHookOutput+lastCIOutput, maxPitfalls))
+			prompt += knowledge.LessonInstruction
 		}
 
-		// Build provider env vars
-		providerEnv := provider.EnvVarsForProvider(settings.Provider)
-
-		// Merge: settings.EnvVars + provider env vars (provider wins on collision)
-		mergedEnv := provider.MergeEnvVars(settings.EnvVars, providerEnv)
+		prompt += ChecklistSection(settings.Checklist)
+		prompts = append(prompts, prompt)
 
 		// Run Claude
-		r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeStart})
-		result, err := r.cfg.Claude.Execute(ctx, ExecuteOpts{
-			Prompt:       prompt,
-			SystemPrompt: BuildExecutionSystemPrompt(),
-			Model:        settings.Provider.Model, // use provider model, not settings.ClaudeModel
-			MaxTurns:     MaxTurnsForTask(task.Complexity, settings.MaxTurns),
-			AllowedTools: BuildAllowedTools(settings.MCPServers),
-			WorkDir:      r.cfg.StateRoot,
-			EnvVars:      mergedEnv,
-			OnChunk: func(text string) {
-				r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeChunk, Detail: text})
-			},
-		})
+		execute := func(prompt string) (*ExecuteResult, error) {
+			r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeStart})
+			systemPrompt := BuildExecutionSystemPrompt()
+			result, err := r.cfg.Claude.Execute(ctx, ExecuteOpts{
+				Prompt:       prompt,
+				SystemPrompt: systemPrompt,
+				Model:        cmp.Or(task.Model, settings.Provider.Model), // task override, else provider model (not settings.ClaudeModel)
+				MaxTurns:     maxTurns,
+				AllowedTools: BuildAllowedTools(settings.MCPServers),
+				WorkDir:      rp.dir,
+				EnvVars:      mergedEnv,
+				OnChunk: func(text string) {
+					r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeChunk, Detail: text})
+				},
+			})
+			r.recordExchange(task.ID, attempt+1, systemPrompt, prompt, result, err)
+			if err != nil {
+				return nil, err
+			}
+			r.addUsage(result)
+			task.Turns = max(task.Turns, result.TurnCount)
+			log.WriteString(fmt.Sprintf("=== Claude Output (attempt %d) ===\n", attempt+1))
+			log.WriteString(result.Text + "\n\n")
+			if note := ParseHandoff(result.Text); note != "" {
+				handoff = note
+			}
+			r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeDone})
+			return result, nil
+		}
+		result, err := execute(prompt)
 		if err != nil {
 			return r.fail(task.ID, "claude execution: "+err.Error(), &log, attempt)
 		}
-		log.WriteString(fmt.Sprintf("=== Claude Output (attempt %d) ===\n", attempt+1))
-		log.WriteString(result.Text + "\n\n")
-		r.emit(TaskEvent{TaskID: task.ID, Type: EventClaudeDone})
+		if attempt > 0 {
+			lessons = append(lessons, knowledge.ParseLessons(result.Text)...)
+		}
+
+		// Items the reply didn't confirm get one follow-up turn before tests
+		if open := UnconfirmedItems(result.Text, settings.Checklist); len(open) > 0 {
+			r.emit(TaskEvent{TaskID: task.ID, Type: EventChecklist,
+				Message: fmt.Sprintf("%d checklist item(s) unconfirmed", len(open)), Detail: strings.Join(open, "\n")})
+			followUp := BuildChecklistFollowUpPrompt(*task, open, settings.Checklist)
+			if result, err = execute(followUp); err != nil {
+				return r.fail(task.ID, "claude execution: "+err.Error(), &log, attempt)
+			}
+			if open = UnconfirmedItems(result.Text, settings.Checklist); len(open) > 0 {
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventChecklist,
+					Message: fmt.Sprintf("%d checklist item(s) still unconfirmed — running tests anyway", len(open)), Detail: strings.Join(open, "\n")})
+			}
+		}
 
 		// Run tests
 		allPassed := true
+		lastViolations = nil
+		lastReview = nil
+		lastHookOutput = ""
+		lastCIOutput = ""
+		var checks []CheckResult // for the PR body
 
 		if settings.TestCommand != "" {
 			r.emit(TaskEvent{TaskID: task.ID, Type: EventTestStart, Message: settings.TestCommand})
-			testResult := r.cfg.Tests.RunTests(ctx, settings.TestCommand)
+			testResult := rp.tests.RunTests(ctx, settings.TestCommand)
 			log.WriteString("=== Test Output ===\n" + testResult.Output + "\n\n")
+			checks = append(checks, CheckResult{Name: "tests", Command: settings.TestCommand, Passed: testResult.Passed, Duration: testResult.Duration})
 
 			if !testResult.Passed {
 				allPassed = false
@@ -204,8 +820,9 @@ func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
 		// Run build if configured and tests passed
 		if allPassed && settings.BuildCommand != "" {
 			r.emit(TaskEvent{TaskID: task.ID, Type: EventBuildStart, Message: settings.BuildCommand})
-			buildResult := r.cfg.Tests.RunBuild(ctx, settings.BuildCommand)
+			buildResult := rp.tests.RunBuild(ctx, settings.BuildCommand)
 			log.WriteString("=== Build Output ===\n" + buildResult.Output + "\n\n")
+			checks = append(checks, CheckResult{Name: "build", Command: settings.BuildCommand, Passed: buildResult.Passed, Duration: buildResult.Duration})
 
 			if !buildResult.Passed {
 				allPassed = false
@@ -218,39 +835,105 @@ func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
 
 		if allPassed {
 			// 3. Stage, commit, push
-			if err := r.cfg.Git.StageAll(ctx); err != nil {
-				return r.fail(task.ID, "stage: "+err.Error(), &log, attempt)
+			if err := rp.git.StageAll(ctx); err != nil {
+				var subErr *SubmoduleChangeError
+				if !errors.As(err, &subErr) {
+					return r.fail(task.ID, "stage: "+err.Error(), &log, attempt)
+				}
+				lastViolations = subErr.Violations()
+				detail := FormatPolicyViolations(lastViolations)
+				log.WriteString("=== Commit Policy Violations ===\n" + detail + "\n")
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventPolicyViolation,
+					Message: fmt.Sprintf("%d submodule(s) changed", len(lastViolations)), Detail: detail})
+				continue
 			}
 
-			hasStagedChanges, _, err := r.cfg.Git.HasStagedChanges(ctx)
+			hasStagedChanges, _, err := rp.git.HasStagedChanges(ctx)
 			if err != nil {
 				return r.fail(task.ID, "check staged changes: "+err.Error(), &log, attempt)
 			}
 			if !hasStagedChanges {
 				return r.fail(task.ID, "no code changes produced", &log, attempt)
 			}
+			if reason := r.changeTooLarge(ctx, rp); reason != "" {
+				outcome := r.fail(task.ID, reason, &log, attempt)
+				outcome.NeedsReview = true
+				return outcome
+			}
+			if lastViolations = r.checkPolicy(ctx, rp); len(lastViolations) > 0 {
+				detail := FormatPolicyViolations(lastViolations)
+				log.WriteString("=== Commit Policy Violations ===\n" + detail + "\n")
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventPolicyViolation,
+					Message: fmt.Sprintf("%d commit policy violation(s)", len(lastViolations)), Detail: detail})
+				continue
+			}
+			if review := r.review(ctx, task, rp, mergedEnv, &log); !review.Approved() {
+				lastReview = &review
+				continue
+			}
 
-			msg := CommitMessage(task.ID, task.Title)
-			sha, err := r.cfg.Git.Commit(ctx, msg)
+			files, _ := rp.git.StagedFiles(ctx)
+			if large := LFSCandidates(rp.dir, files, preflight.LFSPatterns(rp.dir)); len(large) > 0 {
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventLFSWarning, Message: FormatLFSWarning(large)})
+			}
+			committed = r.summarizeStaged(ctx, task, rp, files)
+			msg := AddTrailers(CommitMessage(task.ID, task.Title), CoAuthorTrailer(settings))
+			sha, err := rp.git.Commit(ctx, msg, commitOptions(settings))
 			if err != nil {
-				return r.fail(task.ID, "commit: "+err.Error(), &log, attempt)
+				var hookErr *HookError
+				if !errors.As(err, &hookErr) {
+					return r.fail(task.ID, "commit: "+err.Error(), &log, attempt)
+				}
+				lastHookOutput = hookErr.Output
+				log.WriteString("=== Git Hook Output ===\n" + hookErr.Output + "\n\n")
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventHookFailed,
+					Message: "commit rejected by git hooks", Detail: hookErr.Output})
+				continue
 			}
 			r.emit(TaskEvent{TaskID: task.ID, Type: EventCommit, Message: sha})
+			r.reindex(ctx, task, rp, files)
 
-			if err := r.cfg.Git.Push(ctx); err != nil {
+			if err := rp.git.Push(ctx); err != nil {
 				return r.fail(task.ID, "push: "+err.Error(), &log, attempt)
 			}
 			r.emit(TaskEvent{TaskID: task.ID, Type: EventPush})
+			if ci := r.waitForCI(ctx, task, rp, sha); ci != nil {
+				if ctx.Err() != nil {
+					return r.fail(task.ID, "cancelled", &log, attempt)
+				}
+				if !ci.Passed() {
+					lastCIOutput = ci.Output
+					log.WriteString("=== CI Output ===\n" + ci.Output + "\n")
+					r.emit(TaskEvent{TaskID: task.ID, Type: EventCIFailed, Message: FormatCIFailure(*ci), Detail: ci.Output})
+					continue
+				}
+				r.emit(TaskEvent{TaskID: task.ID, Type: EventCIPassed})
+			}
+			checkID := r.publishCheck(ctx, task.ID, rp, 0, CheckRun{Name: TaskCheckName(*task), SHA: sha,
+				Status: CheckInProgress, Title: "Opening the PR and collecting artifacts"})
+			r.openPR(ctx, task, rp, baseBranch, sha, files, checks, attempt+1)
+
+			r.collectArtifacts(task, &log)
+			r.publishCheck(ctx, task.ID, rp, checkID, TaskCheckRun(*task, sha, checks, attempt+1, log.String()))
 
 			// Update task state directly
 			task.Status = state.TaskDone
 			task.GitSHA = sha
 			task.Retries = attempt
+			task.RootCause = nil // from an earlier failed run
+			task.Handoff = handoff
+			if committed != nil {
+				if r.diffs == nil {
+					r.diffs = map[string]DiffSummary{}
+				}
+				r.diffs[task.ID] = *committed
+			}
+			r.learn(task, lessons)
 			now := time.Now()
 			task.CompletedAt = &now
 
 			// Return to base branch
-			r.cfg.Git.CheckoutBranch(ctx, baseBranch)
+			rp.git.CheckoutBranch(ctx, baseBranch)
 
 			return TaskOutcome{
 				TaskID:  task.ID,
@@ -263,27 +946,389 @@ func (r *Runner) RunTask(ctx context.Context, task *state.Task) TaskOutcome {
 	}
 
 	// Exhausted retries — return to base branch
-	r.cfg.Git.CheckoutBranch(ctx, baseBranch)
+	rp.git.CheckoutBranch(ctx, baseBranch)
 
+	reason := "tests failed"
+	output := lastTestOutput
+	if len(lastViolations) > 0 {
+		reason = "commit policy still violated"
+		output = FormatPolicyViolations(lastViolations)
+	} else if lastReview != nil {
+		reason = "code review still blocking"
+		output = lastReview.Format()
+	} else if lastHookOutput != "" {
+		reason = "git hooks still rejecting the commit"
+		output = lastHookOutput
+	} else if lastCIOutput != "" {
+		reason = "CI still failing"
+		output = lastCIOutput
+	}
+	reason = fmt.Sprintf("%s after %d attempts", reason, maxAttempts)
+	r.analyzeFailure(ctx, task, rp, mergedEnv, reason, &log)
 	r.emit(TaskEvent{TaskID: task.ID, Type: EventTaskFailed, Message: "exhausted retries"})
+	r.fileFailureIssue(ctx, task, rp.remoteURL, reason, output, prompts)
 	return TaskOutcome{
 		TaskID:  task.ID,
 		Status:  state.TaskFailed,
-		Error:   fmt.Sprintf("tests failed after %d attempts", maxAttempts),
+		Error:   reason,
 		Retries: maxRetries,
 		Logs:    log.String(),
 	}
 }
 
+// review has a second model (Settings.ReviewModel, or the execution model
+// with a reviewer prompt) critique the staged diff when Settings.CodeReview
+// is on. A reviewer that fails to run approves: review is advisory
+// tooling, and tests already passed.
+func (r *Runner) review(ctx context.Context, task *state.Task, rp *repoCtx, env map[string]string, log *strings.Builder) Review {
+	settings := rp.settings
+	if !settings.CodeReview {
+		return Review{}
+	}
+	diff, err := rp.git.StagedDiff(ctx)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "code review skipped: " + err.Error()})
+		return Review{}
+	}
+	reviewer := r.cfg.Reviewer
+	if reviewer == nil {
+		reviewer = r.cfg.Claude
+	}
+	model := settings.ReviewModel
+	if model == "" {
+		model = settings.Provider.Model
+	}
+	result, err := reviewer.Execute(ctx, ExecuteOpts{
+		Prompt:       BuildReviewPrompt(r.cfg.ContextFile, *task, diff),
+		SystemPrompt: BuildReviewSystemPrompt(),
+		Model:        model,
+		MaxTurns:     reviewMaxTurns,
+		AllowedTools: ReviewTools,
+		WorkDir:      rp.dir,
+		EnvVars:      env,
+	})
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "code review skipped: " + err.Error()})
+		return Review{}
+	}
+	r.addUsage(result)
+
+	review := ParseReview(result.Text)
+	log.WriteString("=== Code Review ===\n" + result.Text + "\n\n")
+	message := "approved"
+	if !review.Approved() {
+		message = fmt.Sprintf("%d blocking finding(s)", len(review.Blocking))
+	}
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventReview, Message: message, Detail: review.Format()})
+	return review
+}
+
+// openPR opens a pull request for a pushed task branch when Settings.AutoPR
+// is on. A failure is reported but doesn't fail the task: the work is
+// already committed and pushed.
+func (r *Runner) openPR(ctx context.Context, task *state.Task, rp *repoCtx, baseBranch, sha string, files []string, checks []CheckResult, attempts int) {
+	if r.cfg.PRs == nil || !rp.settings.AutoPR || rp.remoteURL == "" || task.PRURL != "" {
+		return
+	}
+	data := NewPRData(r.cfg.State, *task, baseBranch, rp.remoteURL, sha, files, checks, attempts)
+	body, err := RenderPRBody(LoadPRTemplate(r.cfg.StateRoot), data)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: err.Error() + " — using the default"})
+		body, _ = RenderPRBody("", data)
+	}
+	url, err := r.cfg.PRs.CreatePR(ctx, PullRequest{
+		Title: CommitMessage(task.ID, task.Title),
+		Body:  body,
+		Head:  task.Branch,
+		Base:  baseBranch,
+		Dir:   rp.dir,
+		Draft: rp.settings.DraftPRs,
+	})
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "opening PR: " + err.Error()})
+		return
+	}
+	task.PRURL = url
+	task.PRDraft = rp.settings.DraftPRs
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventPRCreated, Message: url})
+}
+
+// markPRsReady marks the repository's draft PRs ready for review once the
+// plan is complete and the run's verification of the repository (v, nil if
+// it has no verification commands) passed. Drafts opened in earlier runs
+// count too. A PR that can't be marked stays a draft for the next run.
+func (r *Runner) markPRsReady(ctx context.Context, rp *repoCtx, v *state.Verification) {
+	if r.cfg.PRs == nil || !generator.PlanComplete(r.cfg.State) {
+		return
+	}
+	var drafts []*state.Task
+	for i := range r.cfg.State.Tasks {
+		if t := &r.cfg.State.Tasks[i]; t.PRDraft && t.PRURL != "" && t.Repo == rp.path {
+			drafts = append(drafts, t)
+		}
+	}
+	if len(drafts) == 0 {
+		return
+	}
+	if v != nil && !v.Passed {
+		r.emit(TaskEvent{Type: EventError, Message: fmt.Sprintf(
+			"%sverification failed — %d PR(s) left as drafts", rp.label(), len(drafts))})
+		return
+	}
+	for _, t := range drafts {
+		if err := r.cfg.PRs.MarkReady(ctx, t.PRURL, rp.dir); err != nil {
+			r.emit(TaskEvent{TaskID: t.ID, Type: EventError, Message: "marking PR ready: " + err.Error()})
+			continue
+		}
+		t.PRDraft = false
+		r.emit(TaskEvent{TaskID: t.ID, Type: EventPRReady, Message: t.PRURL})
+	}
+}
+
+// publishCheck reports run on GitHub when Settings.PublishChecks is on and
+// the repository has a GitHub remote, updating check run id if non-zero.
+// It returns the ID to update next; failures are reported, never fatal.
+func (r *Runner) publishCheck(ctx context.Context, taskID string, rp *repoCtx, id int64, run CheckRun) int64 {
+	if r.cfg.Checks == nil || !rp.settings.PublishChecks || !githubRemoteRe.MatchString(strings.TrimSpace(rp.remoteURL)) {
+		return 0
+	}
+	run.Dir = rp.dir
+	newID, err := r.cfg.Checks.PublishCheck(ctx, id, run)
+	if err != nil {
+		r.emit(TaskEvent{TaskID: taskID, Type: EventError, Message: "publishing check run: " + err.Error()})
+		return id
+	}
+	return newID
+}
+
+// waitForCI waits for the remote CI of a task's pushed commit when
+// Settings.WaitForCI is on and the repository has a GitHub remote. It
+// returns nil when it didn't wait, or couldn't tell: an error watching CI is
+// reported but doesn't hold the task back.
+func (r *Runner) waitForCI(ctx context.Context, task *state.Task, rp *repoCtx, sha string) *CIResult {
+	if r.cfg.CI == nil || !rp.settings.WaitForCI || !githubRemoteRe.MatchString(strings.TrimSpace(rp.remoteURL)) {
+		return nil
+	}
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventCIStart, Message: sha})
+	waitCtx, cancel := context.WithTimeout(ctx, ciTimeout)
+	defer cancel()
+	result, err := r.cfg.CI.WaitForCI(waitCtx, rp.dir, task.Branch, sha)
+	if err != nil {
+		if ctx.Err() != nil {
+			return &CIResult{}
+		}
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "waiting for CI: " + err.Error()})
+		return nil
+	}
+	return &result
+}
+
+// writeChangelog summarizes the plan once every task is finished, when
+// Settings.Changelog asks for it: as a draft under .forge/, or prepended to
+// CHANGELOG.md and committed on the base branch as a final docs commit.
+func (r *Runner) writeChangelog(ctx context.Context) {
+	mode := r.cfg.State.Settings.Changelog
+	if mode == "" || !generator.PlanComplete(r.cfg.State) {
+		return
+	}
+	now := r.now()
+	entry := generator.ChangelogEntry(r.cfg.State, now)
+	if entry == "" {
+		return
+	}
+	path, err := generator.WriteChangelog(r.cfg.StateRoot, mode, entry)
+	if err != nil {
+		r.emit(TaskEvent{Type: EventError, Message: "writing changelog: " + err.Error()})
+		return
+	}
+	if mode == generator.ChangelogCommit {
+		if err := r.cfg.Git.StageAll(ctx); err != nil {
+			r.emit(TaskEvent{Type: EventError, Message: "staging changelog: " + err.Error()})
+			return
+		}
+		settings := r.cfg.State.Settings
+		if _, err := r.cfg.Git.Commit(ctx, AddTrailers("docs: update changelog", CoAuthorTrailer(settings)), commitOptions(settings)); err != nil {
+			r.emit(TaskEvent{Type: EventError, Message: "committing changelog: " + err.Error()})
+			return
+		}
+	}
+	r.cfg.State.ChangelogAt = &now
+	r.emit(TaskEvent{Type: EventChangelog, Message: path})
+}
+
+// fileFailureIssue opens an issue for a task that exhausted its retries,
+// when the project asked for that. A task keeps the first issue opened for
+// it, so rerunning a failing task doesn't file duplicates.
+func (r *Runner) fileFailureIssue(ctx context.Context, task *state.Task, remoteURL, reason, output string, prompts []string) {
+	settings := r.cfg.State.Settings
+	if r.cfg.Issues == nil || !settings.FileIssues || task.IssueURL != "" || ctx.Err() != nil {
+		return
+	}
+	url, err := r.cfg.Issues.FileIssue(ctx, FailureIssue(*task, remoteURL, reason, output, prompts))
+	if err != nil {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventError, Message: "filing issue: " + err.Error()})
+		return
+	}
+	task.IssueURL = url
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventIssueFiled, Message: url})
+}
+
+// runVerifyTask runs the task's commands (or the project's build, test and
+// lint commands) on the current branch without invoking Claude. There is
+// nothing to retry: a failing command fails the task.
+func (r *Runner) runVerifyTask(ctx context.Context, task *state.Task) TaskOutcome {
+	var log strings.Builder
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventTaskStart, Message: task.Title})
+	rp, err := r.repoFor(task.Repo)
+	if err != nil {
+		return r.fail(task.ID, err.Error(), &log, 0)
+	}
+
+	commands := task.Commands
+	if len(commands) == 0 {
+		if s := rp.settings; s != nil {
+			for _, c := range []string{s.BuildCommand, s.TestCommand, s.LintCommand} {
+				if c != "" {
+					commands = append(commands, c)
+				}
+			}
+		}
+	}
+	if len(commands) == 0 {
+		return r.fail(task.ID, "verify task has no commands to run", &log, 0)
+	}
+
+	for _, command := range commands {
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventTestStart, Message: command})
+		result := rp.tests.RunTests(ctx, command)
+		log.WriteString(fmt.Sprintf("=== %s ===\n%s\n\n", command, result.Output))
+		if !result.Passed {
+			r.emit(TaskEvent{TaskID: task.ID, Type: EventTestFailed, Detail: result.Output})
+			return r.fail(task.ID, "command failed: "+command, &log, 0)
+		}
+		r.emit(TaskEvent{TaskID: task.ID, Type: EventTestPassed})
+	}
+
+	r.collectArtifacts(task, &log)
+
+	sha, _ := rp.git.LatestSHA(ctx)
+	return TaskOutcome{TaskID: task.ID, Status: state.TaskDone, SHA: sha, Logs: log.String()}
+}
+
+// collectArtifacts gathers the artifacts a successful task declared.
+// Missing or uncopyable artifacts are reported but never fail the task.
+func (r *Runner) collectArtifacts(task *state.Task, log *strings.Builder) {
+	if len(task.Artifacts) == 0 {
+		return
+	}
+	globs := task.Artifacts
+	if task.Repo != "" {
+		// relative to the task's repository, collected under the project
+		globs = make([]string, len(task.Artifacts))
+		for i, g := range task.Artifacts {
+			globs[i] = filepath.Join(task.Repo, g)
+		}
+	}
+	collected, err := CollectArtifacts(r.cfg.StateRoot, task.ID, globs)
+	task.CollectedArtifacts = collected
+
+	event := TaskEvent{TaskID: task.ID, Type: EventArtifacts,
+		Message: fmt.Sprintf("%d artifact(s) collected", len(collected))}
+	if err != nil {
+		event.Detail = err.Error()
+	}
+	fmt.Fprintf(log, "=== Artifacts ===\n%s\n", strings.Join(collected, "\n"))
+	if err != nil {
+		fmt.Fprintf(log, "error: %v\n", err)
+	}
+	log.WriteString("\n")
+	r.emit(event)
+}
+
+// runManualTask shows the task's instructions and blocks until a human
+// confirms or rejects it. Without a confirmation channel the task fails,
+// since nobody could ever answer.
+func (r *Runner) runManualTask(ctx context.Context, task *state.Task) TaskOutcome {
+	var log strings.Builder
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventTaskStart, Message: task.Title})
+
+	if r.cfg.Confirm == nil {
+		return r.fail(task.ID, "manual task needs interactive confirmation", &log, 0)
+	}
+	rp, err := r.repoFor(task.Repo)
+	if err != nil {
+		return r.fail(task.ID, err.Error(), &log, 0)
+	}
+
+	instructions := task.Description
+	for _, c := range task.AcceptanceCriteria {
+		instructions += "\n- " + c
+	}
+	log.WriteString("=== Manual step ===\n" + instructions + "\n\n")
+	r.emit(TaskEvent{TaskID: task.ID, Type: EventManualWait, Message: instructions})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return r.fail(task.ID, "cancelled while waiting for confirmation", &log, 0)
+		case c := <-r.cfg.Confirm:
+			if c.TaskID != task.ID {
+				continue // stale answer for an earlier task
+			}
+			if c.Note != "" {
+				log.WriteString("Note: " + c.Note + "\n")
+			}
+			if !c.Done {
+				return r.fail(task.ID, "marked failed by operator", &log, 0)
+			}
+			sha, _ := rp.git.LatestSHA(ctx)
+			return TaskOutcome{TaskID: task.ID, Status: state.TaskDone, SHA: sha, Logs: log.String()}
+		}
+	}
+}
+
 func (r *Runner) emit(event TaskEvent) {
 	if event.Timestamp == 0 {
 		event.Timestamp = time.Now().UnixMilli()
 	}
+	r.cfg.Journal.AppendEvent(r.runID, event)
+	r.cfg.Webhook.Send(r.runID, event)
 	if r.cfg.OnEvent != nil {
 		r.cfg.OnEvent(event)
 	}
 }
 
+// recordEnv writes the task's environment to the journal. Tool versions
+// are probed once per runner since they don't change mid-run.
+func (r *Runner) recordEnv(ctx context.Context, taskID string, agentEnv map[string]string) {
+	if r.cfg.Journal == nil {
+		return
+	}
+	r.toolsOnce.Do(func() {
+		r.tools = CaptureToolVersions(ctx)
+	})
+	rec := BuildEnvRecord(r.tools, os.Getenv, agentEnv)
+	r.cfg.Journal.Append(JournalEntry{
+		Kind:      JournalKindEnv,
+		RunID:     r.runID,
+		TaskID:    taskID,
+		Timestamp: time.Now().UnixMilli(),
+		Env:       &rec,
+	})
+}
+
+// recordExchange journals the exact prompt and response of one attempt.
+func (r *Runner) recordExchange(taskID string, attempt int, system, prompt string, result *ExecuteResult, err error) {
+	x := Exchange{Attempt: attempt, System: system, Prompt: prompt}
+	if result != nil {
+		x.Response = result.Text
+	}
+	if err != nil {
+		x.Error = err.Error()
+	}
+	r.cfg.Journal.AppendExchange(r.runID, taskID, x)
+}
+
 func (r *Runner) fail(taskID, message string, log *strings.Builder, retries int) TaskOutcome {
 	r.emit(TaskEvent{TaskID: taskID, Type: EventTaskFailed, Message: message})
 	log.WriteString("=== FAILED: " + message + " ===\n")
@@ -5,11 +5,14 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/manasm11/forge/internal/platform"
 )
 
 // RealTestRunner implements TestRunner using real command execution.
 type RealTestRunner struct {
-	dir string
+	dir   string
+	shell string
 }
 
 // NewRealTestRunner creates a TestRunner rooted at dir.
@@ -17,15 +20,20 @@ func NewRealTestRunner(dir string) *RealTestRunner {
 	return &RealTestRunner{dir: dir}
 }
 
+// WithShell sets the shell commands run through ("" = platform default).
+func (r *RealTestRunner) WithShell(shell string) *RealTestRunner {
+	r.shell = shell
+	return r
+}
+
 func (r *RealTestRunner) runCommand(ctx context.Context, command string) *TestResult {
 	start := time.Now()
 
-	parts := strings.Fields(command)
-	if len(parts) == 0 {
+	if strings.TrimSpace(command) == "" {
 		return &TestResult{Passed: true, Output: "no command"}
 	}
 
-	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd := platform.ShellCommand(ctx, r.shell, command)
 	cmd.Dir = r.dir
 	out, err := cmd.CombinedOutput()
 
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Settings.CoAuthor values with a built-in identity; anything else is a
+// literal "Name <email>".
+const (
+	CoAuthorForge = "forge" // Forge Agent
+	CoAuthorModel = "model" // the execution model, e.g. "sonnet"
+)
+
+// forgeAgentEmail is the address credited in generated co-author trailers.
+const forgeAgentEmail = "forge-agent@users.noreply.github.com"
+
+var identityRe = regexp.MustCompile(`^([^<>]*[^<>\s])\s*<([^<>\s@]+@[^<>\s]+)>$`)
+
+// ParseIdentity splits a git identity "Name <email>".
+func ParseIdentity(v string) (name, email string, ok bool) {
+	m := identityRe.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// ValidCoAuthor reports whether v is a Settings.CoAuthor value: empty or
+// "off", "forge", "model", or "Name <email>".
+func ValidCoAuthor(v string) bool {
+	switch v {
+	case "", "off", CoAuthorForge, CoAuthorModel:
+		return true
+	}
+	_, _, ok := ParseIdentity(v)
+	return ok
+}
+
+// CoAuthorTrailer returns the Co-authored-by trailer Settings.CoAuthor asks
+// for, or "" when it is off.
+func CoAuthorTrailer(settings *state.Settings) string {
+	identity := settings.CoAuthor
+	switch identity {
+	case "", "off":
+		return ""
+	case CoAuthorForge:
+		identity = "Forge Agent <" + forgeAgentEmail + ">"
+	case CoAuthorModel:
+		model := provider.FormatModelName(settings.Provider.Model)
+		if model == "" {
+			model = "Forge Agent"
+		}
+		identity = model + " <" + forgeAgentEmail + ">"
+	}
+	return "Co-authored-by: " + identity
+}
+
+// AddTrailers appends trailers ("Key: value") to a commit message as its
+// closing paragraph, where git expects them. Empty trailers are dropped.
+func AddTrailers(message string, trailers ...string) string {
+	var lines []string
+	for _, t := range trailers {
+		if t != "" {
+			lines = append(lines, t)
+		}
+	}
+	if len(lines) == 0 {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + strings.Join(lines, "\n")
+}
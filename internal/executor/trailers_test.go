@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestCoAuthorTrailer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		coAuthor string
+		want     string
+	}{
+		{"", ""},
+		{"off", ""},
+		{"forge", "Co-authored-by: Forge Agent <forge-agent@users.noreply.github.com>"},
+		{"model", "Co-authored-by: qwen2.5-coder <forge-agent@users.noreply.github.com>"},
+		{"Build Bot <bot@example.com>", "Co-authored-by: Build Bot <bot@example.com>"},
+	}
+	for _, tt := range tests {
+		s := &state.Settings{CoAuthor: tt.coAuthor, Provider: provider.Config{Model: "qwen2.5-coder:latest"}}
+		if got := CoAuthorTrailer(s); got != tt.want {
+			t.Errorf("CoAuthorTrailer(%q) = %q, want %q", tt.coAuthor, got, tt.want)
+		}
+	}
+}
+
+func TestValidCoAuthor(t *testing.T) {
+	t.Parallel()
+	for v, want := range map[string]bool{
+		"": true, "forge": true, "model": true, "Jo Doe <jo@example.com>": true,
+		"Jo Doe": false, "<jo@example.com>": false, "Jo <not an email>": false,
+	} {
+		if got := ValidCoAuthor(v); got != want {
+			t.Errorf("ValidCoAuthor(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestParseIdentity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in          string
+		name, email string
+		ok          bool
+	}{
+		{"Forge Bot <forge@team.dev>", "Forge Bot", "forge@team.dev", true},
+		{"  bot<bot@ci.example.com> ", "bot", "bot@ci.example.com", true},
+		{"Forge Bot", "", "", false},
+		{"<forge@team.dev>", "", "", false},
+		{"Forge Bot <forge>", "", "", false},
+	}
+	for _, tt := range tests {
+		name, email, ok := ParseIdentity(tt.in)
+		if name != tt.name || email != tt.email || ok != tt.ok {
+			t.Errorf("ParseIdentity(%q) = %q, %q, %v; want %q, %q, %v", tt.in, name, email, ok, tt.name, tt.email, tt.ok)
+		}
+	}
+}
+
+func TestAddTrailers(t *testing.T) {
+	t.Parallel()
+	if got := AddTrailers("forge: task-001 — Add auth", "", "Co-authored-by: A <a@b.c>"); got != "forge: task-001 — Add auth\n\nCo-authored-by: A <a@b.c>" {
+		t.Errorf("AddTrailers() = %q", got)
+	}
+	if got := AddTrailers("msg", ""); got != "msg" {
+		t.Errorf("AddTrailers() without trailers = %q, want the message unchanged", got)
+	}
+}
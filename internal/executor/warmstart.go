@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manasm11/forge/internal/scanner"
+)
+
+// RelevantFilesSection inlines the files RelevantFiles picked for a task,
+// so the model can start on them instead of spending turns reading.
+// Returns "" when there are none.
+func RelevantFilesSection(files []scanner.RelevantFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nRELEVANT FILES (current contents, already read for you; read others as needed):\n")
+	for _, f := range files {
+		if f.Summary {
+			fmt.Fprintf(&b, "\n--- %s (declarations only; read the file for the bodies) ---\n", f.Path)
+		} else {
+			fmt.Fprintf(&b, "\n--- %s ---\n", f.Path)
+		}
+		b.WriteString(strings.TrimRight(f.Content, "\n") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	webhookQueueSize    = 256              // events buffered while the endpoint is slow
+	webhookTimeout      = 5 * time.Second  // per POST
+	webhookDrainTimeout = 10 * time.Second // how long Close waits for queued events
+)
+
+// Webhook POSTs every task event to Settings.WebhookURL as JSON — the same
+// shape as a journal line — so dashboards, chat bots and CI annotations can
+// follow a run live. Events are delivered in order from a background
+// goroutine; when the endpoint falls behind they are dropped rather than
+// slowing the run. A nil *Webhook is valid and sends nothing.
+type Webhook struct {
+	url     string
+	client  *http.Client
+	queue   chan JournalEntry
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+// NewWebhook starts delivering to url. It returns nil for an empty url.
+// A nil client uses one with a short timeout.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if url == "" {
+		return nil
+	}
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	w := &Webhook{url: url, client: client,
+		queue: make(chan JournalEntry, webhookQueueSize), done: make(chan struct{})}
+	go w.deliver()
+	return w
+}
+
+// Send queues an event of the given run for delivery.
+func (w *Webhook) Send(runID int, e TaskEvent) {
+	if w == nil {
+		return
+	}
+	entry := JournalEntry{
+		Kind:      JournalKindEvent,
+		RunID:     runID,
+		TaskID:    e.TaskID,
+		Timestamp: e.Timestamp,
+		Event:     &JournalEvent{Type: e.Type.String(), Message: e.Message, Detail: e.Detail},
+	}
+	select {
+	case w.queue <- entry:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Dropped is the number of events discarded because the queue was full.
+func (w *Webhook) Dropped() int64 {
+	if w == nil {
+		return 0
+	}
+	return w.dropped.Load()
+}
+
+// Close stops accepting events and waits, up to webhookDrainTimeout, for
+// the queued ones to go out.
+func (w *Webhook) Close() {
+	if w == nil {
+		return
+	}
+	close(w.queue)
+	select {
+	case <-w.done:
+	case <-time.After(webhookDrainTimeout):
+	}
+}
+
+func (w *Webhook) deliver() {
+	defer close(w.done)
+	for entry := range w.queue {
+		body, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forge-Event", entry.Event.Type)
+			// Observers are best effort: a failed POST never stops the run
+			if resp, err := w.client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestWebhook_PostsEventsInOrder(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var got []JournalEntry
+	var headers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry JournalEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		mu.Lock()
+		got = append(got, entry)
+		headers = append(headers, r.Header.Get("X-Forge-Event"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, srv.Client())
+	w.Send(3, TaskEvent{TaskID: "task-001", Type: EventTaskStart, Timestamp: 1})
+	w.Send(3, TaskEvent{TaskID: "task-001", Type: EventTaskDone, Message: "ok", Timestamp: 2})
+	w.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 {
+		t.Fatalf("received %d events, want 2", len(got))
+	}
+	if got[0].RunID != 3 || got[0].Event.Type != "task_start" || got[1].Event.Message != "ok" {
+		t.Errorf("events = %+v, %+v", *got[0].Event, *got[1].Event)
+	}
+	if headers[0] != "task_start" || headers[1] != "task_done" {
+		t.Errorf("X-Forge-Event headers = %v", headers)
+	}
+}
+
+func TestWebhook_NilIsNoop(t *testing.T) {
+	t.Parallel()
+	w := NewWebhook("", nil)
+	if w != nil {
+		t.Fatal("NewWebhook(\"\") should return nil")
+	}
+	w.Send(1, TaskEvent{Type: EventTaskStart})
+	w.Close()
+	if w.Dropped() != 0 {
+		t.Error("nil webhook should drop nothing")
+	}
+}
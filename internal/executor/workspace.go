@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// WorkspaceOps opens git and command runners for a workspace repository
+// directory (see state.Settings.Repos).
+type WorkspaceOps func(dir string) (GitOps, TestRunner)
+
+// RealWorkspace returns WorkspaceOps backed by real git and shell commands.
+func RealWorkspace(shell string) WorkspaceOps {
+	return func(dir string) (GitOps, TestRunner) {
+		return NewRealGitOps(dir), NewRealTestRunner(dir).WithShell(shell)
+	}
+}
+
+// repoCtx is where a task's git and command work happens: the project root
+// or one of the workspace repositories.
+type repoCtx struct {
+	path      string // state.WorkspaceRepo.Path; "" for the project root
+	dir       string
+	git       GitOps
+	tests     TestRunner
+	remoteURL string
+	settings  *state.Settings // Settings.ForRepo(path)
+}
+
+// label prefixes messages about a workspace repository; it is empty for
+// the project root.
+func (rc *repoCtx) label() string {
+	if rc.path == "" {
+		return ""
+	}
+	return rc.path + ": "
+}
+
+// repoFor resolves a task's repository. Git and test runners are opened
+// once per runner; settings are re-read each time since plan edits can
+// change them between tasks.
+func (r *Runner) repoFor(path string) (*repoCtx, error) {
+	settings := r.cfg.State.Settings
+	if path == "" {
+		return &repoCtx{dir: r.cfg.StateRoot, git: r.cfg.Git, tests: r.cfg.Tests,
+			remoteURL: r.cfg.RemoteURL, settings: settings}, nil
+	}
+	repo := settings.FindRepo(path)
+	if repo == nil {
+		return nil, fmt.Errorf("repo %q is not one of the workspace repositories", path)
+	}
+	if r.cfg.Workspace == nil {
+		return nil, fmt.Errorf("repo %q: this runner has no workspace support", path)
+	}
+	if r.repos == nil {
+		r.repos = map[string]*repoCtx{}
+	}
+	rc, ok := r.repos[path]
+	if !ok {
+		dir := repo.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(r.cfg.StateRoot, dir)
+		}
+		rc = &repoCtx{path: path, dir: dir}
+		rc.git, rc.tests = r.cfg.Workspace(dir)
+		r.repos[path] = rc
+	}
+	rc.settings = settings.ForRepo(path)
+	rc.remoteURL = rc.settings.RemoteURL
+	return rc, nil
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxDirtyFilesShown = 5 // in DirtyWorktreeError's message
+
+// DirtyWorktreeError stops a run that would start on top of the user's
+// uncommitted changes: `git add -A` would fold them into the first task's
+// commit.
+type DirtyWorktreeError struct {
+	Files []string
+}
+
+func (e *DirtyWorktreeError) Error() string {
+	shown := e.Files
+	if len(shown) > maxDirtyFilesShown {
+		shown = shown[:maxDirtyFilesShown]
+	}
+	msg := fmt.Sprintf("working tree has uncommitted changes: %s", strings.Join(shown, ", "))
+	if more := len(e.Files) - len(shown); more > 0 {
+		msg += fmt.Sprintf(" (+%d more)", more)
+	}
+	return msg + "; commit or stash them, or turn on Stash Uncommitted Changes"
+}
+
+// UserChanges drops forge's own files from a list of changed paths:
+// .forge/, .claude/, and generated, the files forge wrote this session
+// (RunnerConfig.Generated).
+func UserChanges(files, generated []string) []string {
+	var user []string
+	for _, f := range files {
+		p := path.Clean(strings.ReplaceAll(f, "\\", "/"))
+		if strings.HasPrefix(p, ".forge/") || strings.HasPrefix(p, ".claude/") || slices.Contains(generated, p) {
+			continue
+		}
+		user = append(user, f)
+	}
+	return user
+}
+
+// guardWorktree keeps uncommitted work out of task commits, in the project
+// root and every workspace repository: it refuses to start with a
+// *DirtyWorktreeError, or stashes the changes when Settings.StashDirty or
+// RunnerConfig.StashDirty says so.
+func (r *Runner) guardWorktree(ctx context.Context) error {
+	repos := []string{""}
+	if s := r.cfg.State.Settings; s != nil && r.cfg.Workspace != nil {
+		for _, wr := range s.Repos {
+			repos = append(repos, wr.Path)
+		}
+	}
+
+	dirty := map[*repoCtx][]string{}
+	var order []*repoCtx
+	var all []string
+	for _, repo := range repos {
+		rc, err := r.repoFor(repo)
+		if err != nil {
+			return err
+		}
+		files, err := rc.git.DirtyFiles(ctx)
+		if err != nil {
+			return fmt.Errorf("%sfailed to check the working tree: %w", rc.label(), err)
+		}
+		var generated []string
+		if repo == "" {
+			generated = r.cfg.Generated
+		}
+		files = UserChanges(files, generated)
+		if len(files) == 0 {
+			continue
+		}
+		dirty[rc] = files
+		order = append(order, rc)
+		for _, f := range files {
+			all = append(all, rc.label()+f)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	if s := r.cfg.State.Settings; !r.cfg.StashDirty && (s == nil || !s.StashDirty) {
+		return &DirtyWorktreeError{Files: all}
+	}
+	message := "forge: uncommitted changes before the run at " + r.now().Format(time.DateTime)
+	for _, rc := range order {
+		if err := rc.git.Stash(ctx, message, dirty[rc]); err != nil {
+			return fmt.Errorf("%sfailed to stash uncommitted changes: %w", rc.label(), err)
+		}
+	}
+	r.emit(TaskEvent{Type: EventWorktreeStashed,
+		Message: fmt.Sprintf("Stashed %d uncommitted file(s) — `git stash pop` restores them", len(all)),
+		Detail:  strings.Join(all, "\n")})
+	return nil
+}
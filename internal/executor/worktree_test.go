@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestUserChanges(t *testing.T) {
+	t.Parallel()
+	files := []string{".forge/state.json", "CLAUDE.md", ".claude/settings.json", "main.go", "docs/CLAUDE.md", ".forgery", "AGENTS.md"}
+	want := []string{"main.go", "docs/CLAUDE.md", ".forgery", "AGENTS.md"}
+	if got := UserChanges(files, []string{"CLAUDE.md"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDirtyWorktreeError(t *testing.T) {
+	t.Parallel()
+	err := &DirtyWorktreeError{Files: []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go"}}
+	msg := err.Error()
+	if !strings.Contains(msg, "a.go, b.go, c.go, d.go, e.go (+2 more)") || strings.Contains(msg, "f.go") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestRun_DirtyWorktree(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		settingStash bool
+		runnerStash  bool
+		wantRefused  bool
+	}{
+		{"refuses by default", false, false, true},
+		{"stashes with the setting", true, false, false},
+		{"stashes when the runner is told to", false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+			s.Settings.StashDirty = tt.settingStash
+			git := NewMockGitOps()
+			git.DirtyFilesResult = []string{".forge/state.json", "notes.txt"}
+			var stashed []string
+			runner := NewRunner(RunnerConfig{
+				State: s, StateRoot: t.TempDir(), StashDirty: tt.runnerStash,
+				Git: git, Tests: NewMockTestRunner(),
+				Claude: NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+				OnEvent: func(e TaskEvent) {
+					if e.Type == EventWorktreeStashed {
+						stashed = append(stashed, e.Detail)
+					}
+				},
+			})
+
+			err := runner.Run(context.Background())
+			var dirty *DirtyWorktreeError
+			if tt.wantRefused {
+				if !errors.As(err, &dirty) || !reflect.DeepEqual(dirty.Files, []string{"notes.txt"}) {
+					t.Fatalf("Run() error = %v, want DirtyWorktreeError for notes.txt", err)
+				}
+				if len(s.Runs) != 0 || len(git.CreateBranchCalls) != 0 {
+					t.Error("a refused run should not be recorded or create branches")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(git.StashCalls, [][]string{{"notes.txt"}}) || len(stashed) != 1 {
+				t.Errorf("stash calls = %v, events = %v; want notes.txt stashed", git.StashCalls, stashed)
+			}
+			if s.Tasks[0].Status != state.TaskDone {
+				t.Errorf("task status = %q, want done", s.Tasks[0].Status)
+			}
+		})
+	}
+}
+
+func TestRun_DirtyWorkspaceRepo(t *testing.T) {
+	t.Parallel()
+	s := testState(mkTask("task-001", "Init", state.TaskPending, nil))
+	s.Settings.Repos = []state.WorkspaceRepo{{Path: "web"}}
+	rootGit, webGit := NewMockGitOps(), NewMockGitOps()
+	rootGit.DirtyFilesResult = []string{"CLAUDE.md"}
+	webGit.DirtyFilesResult = []string{"src/cart.ts"}
+	runner := NewRunner(RunnerConfig{
+		State: s, StateRoot: t.TempDir(),
+		Git: rootGit, Tests: NewMockTestRunner(),
+		Claude: NewMockClaudeExecutor(&ExecuteResult{Text: "done"}),
+		Workspace: func(dir string) (GitOps, TestRunner) {
+			return webGit, NewMockTestRunner()
+		},
+	})
+
+	err := runner.Run(context.Background())
+	var dirty *DirtyWorktreeError
+	if !errors.As(err, &dirty) || !reflect.DeepEqual(dirty.Files, []string{"CLAUDE.md", "web: src/cart.ts"}) {
+		t.Fatalf("Run() error = %v, want the user's CLAUDE.md and the web repo's change", err)
+	}
+}
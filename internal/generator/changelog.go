@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Changelog modes (Settings.Changelog). Empty means off.
+const (
+	ChangelogDraft  = "draft"  // write .forge/release-notes.md for a human to edit
+	ChangelogCommit = "commit" // prepend the entry to CHANGELOG.md and commit it
+)
+
+// ChangelogModes lists the values accepted for Settings.Changelog.
+var ChangelogModes = []string{"off", ChangelogDraft, ChangelogCommit}
+
+// ValidChangelogMode reports whether mode is empty or one of ChangelogModes.
+func ValidChangelogMode(mode string) bool {
+	if mode == "" {
+		return true
+	}
+	for _, m := range ChangelogModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	ChangelogFile    = "CHANGELOG.md"
+	ReleaseNotesFile = "release-notes.md" // under .forge/
+)
+
+// Keep a Changelog sections, in the order they are written.
+var changelogKinds = []string{"Added", "Changed", "Fixed", "Removed"}
+
+// kindVerbs maps a task title's leading verb to its changelog section;
+// anything else counts as Added.
+var kindVerbs = map[string]string{
+	"fix": "Fixed", "resolve": "Fixed", "correct": "Fixed", "repair": "Fixed",
+	"remove": "Removed", "delete": "Removed", "drop": "Removed", "deprecate": "Removed",
+	"update": "Changed", "change": "Changed", "refactor": "Changed", "rename": "Changed",
+	"improve": "Changed", "migrate": "Changed", "upgrade": "Changed", "replace": "Changed",
+	"move": "Changed", "optimize": "Changed", "simplify": "Changed", "extract": "Changed",
+}
+
+// PlanComplete reports whether every task in the plan is finished: at least
+// one is done and none is pending, running or failed.
+func PlanComplete(s *state.State) bool {
+	done := false
+	for _, t := range s.Tasks {
+		switch t.Status {
+		case state.TaskDone:
+			done = true
+		case state.TaskPending, state.TaskInProgress, state.TaskFailed:
+			return false
+		}
+	}
+	return done
+}
+
+// ChangelogEntry summarizes the tasks finished since the last entry
+// (State.ChangelogAt) as a Keep a Changelog section dated date. Tasks are
+// grouped by the plan revision that last shaped them — forge's milestones —
+// then by kind, inferred from the title's leading verb. Verify tasks change
+// no code and are left out. It returns "" when there is nothing to report.
+func ChangelogEntry(s *state.State, date time.Time) string {
+	byVersion := map[int]map[string][]string{}
+	var versions []int
+	for _, t := range s.Tasks {
+		if t.Status != state.TaskDone || t.Type == state.TaskTypeVerify {
+			continue
+		}
+		if s.ChangelogAt != nil && (t.CompletedAt == nil || !t.CompletedAt.After(*s.ChangelogAt)) {
+			continue
+		}
+		v := t.PlanVersionModified
+		if byVersion[v] == nil {
+			byVersion[v] = map[string][]string{}
+			versions = append(versions, v)
+		}
+		kind := changelogKind(t.Title)
+		byVersion[v][kind] = append(byVersion[v][kind], changelogLine(t))
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	sort.Ints(versions)
+
+	summaries := map[int]string{}
+	for _, rev := range s.PlanHistory {
+		summaries[rev.Version] = rev.Summary
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", date.Format("2006-01-02"))
+	for _, v := range versions {
+		if len(versions) > 1 {
+			fmt.Fprintf(&b, "\n### Plan v%d", v)
+			if summaries[v] != "" {
+				fmt.Fprintf(&b, " — %s", summaries[v])
+			}
+			b.WriteString("\n")
+		}
+		heading := "###"
+		if len(versions) > 1 {
+			heading = "####"
+		}
+		for _, kind := range changelogKinds {
+			lines := byVersion[v][kind]
+			if len(lines) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n%s %s\n\n", heading, kind)
+			for _, line := range lines {
+				b.WriteString("- " + line + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func changelogKind(title string) string {
+	verb, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(title)), " ")
+	verb = strings.TrimRight(verb, ":,")
+	// "Fix", "Fixes", "Fixed", "Remove", "Removed"
+	for _, suffix := range []string{"", "s", "es", "d", "ed"} {
+		if kind, ok := kindVerbs[strings.TrimSuffix(verb, suffix)]; ok {
+			return kind
+		}
+	}
+	return "Added"
+}
+
+func changelogLine(t state.Task) string {
+	line := fmt.Sprintf("%s (%s)", strings.TrimSpace(t.Title), t.ID)
+	if t.PRURL != "" {
+		line += fmt.Sprintf(" — [PR](%s)", t.PRURL)
+	}
+	return line
+}
+
+// PrependChangelog inserts entry above the newest release in an existing
+// CHANGELOG.md, keeping its title and preamble on top. An empty existing
+// file gets a "# Changelog" title.
+func PrependChangelog(existing, entry string) string {
+	if strings.TrimSpace(existing) == "" {
+		return "# Changelog\n\n" + entry
+	}
+	lines := strings.SplitAfter(existing, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			return strings.Join(lines[:i], "") + entry + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+	if !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+	return existing + "\n" + entry
+}
+
+// WriteChangelog writes entry where mode says: .forge/release-notes.md for
+// a draft (replacing the previous draft), or the top of CHANGELOG.md in the
+// project root. It returns the path written.
+func WriteChangelog(root, mode, entry string) (string, error) {
+	if mode == ChangelogDraft {
+		path := filepath.Join(state.ForgeDir(root), ReleaseNotesFile)
+		return path, os.WriteFile(path, []byte("# Release notes (draft)\n\n"+entry), 0644)
+	}
+	path := filepath.Join(root, ChangelogFile)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return path, err
+	}
+	return path, os.WriteFile(path, []byte(PrependChangelog(string(existing), entry)), 0644)
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestPlanComplete(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		statuses []state.TaskStatus
+		want     bool
+	}{
+		{"all done", []state.TaskStatus{state.TaskDone, state.TaskDone}, true},
+		{"done and cancelled", []state.TaskStatus{state.TaskDone, state.TaskCancelled, state.TaskSkipped}, true},
+		{"pending left", []state.TaskStatus{state.TaskDone, state.TaskPending}, false},
+		{"failed", []state.TaskStatus{state.TaskDone, state.TaskFailed}, false},
+		{"nothing done", []state.TaskStatus{state.TaskCancelled}, false},
+		{"no tasks", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &state.State{}
+			for i, st := range tt.statuses {
+				s.Tasks = append(s.Tasks, state.Task{ID: string(rune('a' + i)), Status: st})
+			}
+			if got := PlanComplete(s); got != tt.want {
+				t.Errorf("PlanComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelogKind(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Add JWT auth":               "Added",
+		"Implement inventory API":    "Added",
+		"Fix race in cache":          "Fixed",
+		"Fixes login redirect":       "Fixed",
+		"Removed legacy endpoints":   "Removed",
+		"Refactor: split handlers":   "Changed",
+		"Update dependencies":        "Changed",
+		"Migrates config to TOML":    "Changed",
+		"Fixture data for e2e tests": "Added",
+	}
+	for title, want := range tests {
+		if got := changelogKind(title); got != want {
+			t.Errorf("changelogKind(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestChangelogEntry_SinglePlanVersion(t *testing.T) {
+	t.Parallel()
+	s := &state.State{Tasks: []state.Task{
+		{ID: "task-001", Title: "Add auth", Status: state.TaskDone, PlanVersionModified: 1, PRURL: "https://github.com/o/r/pull/3"},
+		{ID: "task-002", Title: "Fix login redirect", Status: state.TaskDone, PlanVersionModified: 1},
+		{ID: "task-003", Title: "Run e2e suite", Status: state.TaskDone, Type: state.TaskTypeVerify, PlanVersionModified: 1},
+		{ID: "task-004", Title: "Add admin UI", Status: state.TaskCancelled, PlanVersionModified: 1},
+	}}
+	got := ChangelogEntry(s, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))
+	want := "## 2026-03-09\n" +
+		"\n### Added\n\n- Add auth (task-001) — [PR](https://github.com/o/r/pull/3)\n" +
+		"\n### Fixed\n\n- Fix login redirect (task-002)\n"
+	if got != want {
+		t.Errorf("ChangelogEntry() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestChangelogEntry_GroupsByPlanVersion(t *testing.T) {
+	t.Parallel()
+	s := &state.State{
+		PlanHistory: []state.PlanRevision{{Version: 1, Summary: "Initial plan"}, {Version: 2, Summary: "Add billing"}},
+		Tasks: []state.Task{
+			{ID: "task-003", Title: "Add invoices", Status: state.TaskDone, PlanVersionModified: 2},
+			{ID: "task-001", Title: "Add auth", Status: state.TaskDone, PlanVersionModified: 1},
+		},
+	}
+	got := ChangelogEntry(s, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))
+	for _, want := range []string{"### Plan v1 — Initial plan\n\n#### Added\n\n- Add auth", "### Plan v2 — Add billing\n\n#### Added\n\n- Add invoices"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ChangelogEntry() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "Plan v1") > strings.Index(got, "Plan v2") {
+		t.Errorf("plan versions out of order:\n%s", got)
+	}
+}
+
+func TestChangelogEntry_SkipsTasksInPreviousEntry(t *testing.T) {
+	t.Parallel()
+	last := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before, after := last.Add(-time.Hour), last.Add(time.Hour)
+	s := &state.State{
+		ChangelogAt: &last,
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Add auth", Status: state.TaskDone, CompletedAt: &before},
+			{ID: "task-002", Title: "Add search", Status: state.TaskDone, CompletedAt: &after},
+		},
+	}
+	got := ChangelogEntry(s, after)
+	if strings.Contains(got, "task-001") || !strings.Contains(got, "task-002") {
+		t.Errorf("ChangelogEntry() =\n%s\nwant only task-002", got)
+	}
+
+	s.Tasks = s.Tasks[:1]
+	if got := ChangelogEntry(s, after); got != "" {
+		t.Errorf("ChangelogEntry() = %q, want empty when nothing is new", got)
+	}
+}
+
+func TestPrependChangelog(t *testing.T) {
+	t.Parallel()
+	entry := "## 2026-03-09\n\n### Added\n\n- New\n"
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{"new file", "", "# Changelog\n\n" + entry},
+		{
+			"above newest release",
+			"# Changelog\n\nAll notable changes.\n\n## 1.0.0\n\n- Old\n",
+			"# Changelog\n\nAll notable changes.\n\n" + entry + "\n## 1.0.0\n\n- Old\n",
+		},
+		{"no releases yet", "# Changelog", "# Changelog\n\n" + entry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := PrependChangelog(tt.existing, entry); got != tt.want {
+				t.Errorf("PrependChangelog() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteChangelog(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	if err := os.MkdirAll(state.ForgeDir(root), 0755); err != nil {
+		t.Fatal(err)
+	}
+	entry := "## 2026-03-09\n"
+
+	path, err := WriteChangelog(root, ChangelogDraft, entry)
+	if err != nil || path != filepath.Join(state.ForgeDir(root), ReleaseNotesFile) {
+		t.Fatalf("WriteChangelog(draft) = %q, %v", path, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ChangelogFile)); !os.IsNotExist(err) {
+		t.Error("a draft should not touch CHANGELOG.md")
+	}
+
+	path, err = WriteChangelog(root, ChangelogCommit, entry)
+	if err != nil || path != filepath.Join(root, ChangelogFile) {
+		t.Fatalf("WriteChangelog(commit) = %q, %v", path, err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "# Changelog\n\n"+entry {
+		t.Errorf("CHANGELOG.md = %q", data)
+	}
+}
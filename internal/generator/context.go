@@ -86,6 +86,16 @@ func GenerateContextFile(s *state.State) string {
 		b.WriteString("- Run the test command after making changes\n")
 	}
 	b.WriteString("- Follow existing code patterns and conventions\n")
+	if s.Settings != nil && len(s.Settings.SparsePaths) > 0 {
+		fmt.Fprintf(&b, "- Only these directories are checked out (sparse checkout): %s; don't recreate files outside them\n",
+			strings.Join(s.Settings.SparsePaths, ", "))
+	}
+	if s.Snapshot != nil {
+		if readOnly := s.Snapshot.ReadOnlyPaths(); len(readOnly) > 0 {
+			fmt.Fprintf(&b, "- Do not edit submodules or vendored code (%s); forge will not commit changes to submodules\n",
+				strings.Join(readOnly, ", "))
+		}
+	}
 
 	return b.String()
 }
@@ -93,19 +103,67 @@ func GenerateContextFile(s *state.State) string {
 // GenerateClaudeMD produces the contents of CLAUDE.md for the project.
 func GenerateClaudeMD(s *state.State) string {
 	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", projectName(s))
+	writeProjectGuide(&b, s)
+	return b.String()
+}
 
-	name := s.ProjectName
-	if name == "" {
-		name = "Project"
+// GenerateAgentsMD produces the contents of AGENTS.md, the tool-agnostic
+// counterpart of CLAUDE.md read by other agentic coding tools.
+func GenerateAgentsMD(s *state.State) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", projectName(s))
+	b.WriteString("Guidance for AI coding agents working in this repository.\n\n")
+	writeProjectGuide(&b, s)
+	return b.String()
+}
+
+// GenerateCursorRules produces the contents of .cursorrules for the project.
+func GenerateCursorRules(s *state.State) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Cursor rules for %s\n\n", projectName(s))
+	writeProjectGuide(&b, s)
+	return b.String()
+}
+
+// AgentFile is a project guidance file for an agentic coding tool.
+type AgentFile struct {
+	Name    string // path relative to the project root
+	Content string
+}
+
+// GenerateAgentFiles returns every guidance file forge should write for the
+// project: CLAUDE.md always, plus AGENTS.md and .cursorrules when enabled.
+func GenerateAgentFiles(s *state.State) []AgentFile {
+	files := []AgentFile{{Name: "CLAUDE.md", Content: GenerateClaudeMD(s)}}
+	if s.Settings == nil {
+		return files
+	}
+	if s.Settings.GenerateAgentsMD {
+		files = append(files, AgentFile{Name: "AGENTS.md", Content: GenerateAgentsMD(s)})
+	}
+	if s.Settings.GenerateCursorRules {
+		files = append(files, AgentFile{Name: ".cursorrules", Content: GenerateCursorRules(s)})
+	}
+	return files
+}
+
+func projectName(s *state.State) string {
+	if s.ProjectName == "" {
+		return "Project"
 	}
-	fmt.Fprintf(&b, "# %s\n\n", name)
+	return s.ProjectName
+}
 
+// writeProjectGuide writes the shared body of the agent guidance files:
+// tech stack, project structure, commands and conventions.
+func writeProjectGuide(b *strings.Builder, s *state.State) {
 	// Tech stack
 	if s.Snapshot != nil && s.Snapshot.Language != "" {
 		b.WriteString("## Tech Stack\n")
-		fmt.Fprintf(&b, "- %s\n", s.Snapshot.Language)
+		fmt.Fprintf(b, "- %s\n", s.Snapshot.Language)
 		for _, fw := range s.Snapshot.Frameworks {
-			fmt.Fprintf(&b, "- %s\n", fw)
+			fmt.Fprintf(b, "- %s\n", fw)
 		}
 		b.WriteString("\n")
 	}
@@ -121,10 +179,10 @@ func GenerateClaudeMD(s *state.State) string {
 	if s.Settings != nil {
 		b.WriteString("## Testing\n")
 		if s.Settings.TestCommand != "" {
-			fmt.Fprintf(&b, "Run tests: `%s`\n", s.Settings.TestCommand)
+			fmt.Fprintf(b, "Run tests: `%s`\n", s.Settings.TestCommand)
 		}
 		if s.Settings.BuildCommand != "" {
-			fmt.Fprintf(&b, "Build: `%s`\n", s.Settings.BuildCommand)
+			fmt.Fprintf(b, "Build: `%s`\n", s.Settings.BuildCommand)
 		}
 		b.WriteString("\n")
 	}
@@ -134,6 +192,4 @@ func GenerateClaudeMD(s *state.State) string {
 	b.WriteString("- Follow existing code patterns in the project\n")
 	b.WriteString("- Handle errors explicitly\n")
 	b.WriteString("- Write tests for new functionality\n")
-
-	return b.String()
 }
@@ -78,6 +78,22 @@ func TestGenerateContextFile_MinimalState(t *testing.T) {
 	}
 }
 
+func TestGenerateContextFile_CheckoutLimits(t *testing.T) {
+	t.Parallel()
+	s := &state.State{
+		ProjectName: "test",
+		Snapshot:    &state.ProjectSnapshot{Submodules: []string{"libs/proto"}, Vendored: []string{"vendor"}},
+		Settings:    &state.Settings{SparsePaths: []string{"services/api", "libs"}},
+	}
+	content := GenerateContextFile(s)
+	if !strings.Contains(content, "Do not edit submodules or vendored code (libs/proto, vendor)") {
+		t.Errorf("context should list read-only paths:\n%s", content)
+	}
+	if !strings.Contains(content, "checked out (sparse checkout): services/api, libs;") {
+		t.Errorf("context should list the sparse checkout:\n%s", content)
+	}
+}
+
 func TestGenerateContextFile_NoSettings(t *testing.T) {
 	t.Parallel()
 	s := &state.State{
@@ -148,6 +164,61 @@ func TestGenerateClaudeMD_NoSnapshot(t *testing.T) {
 	}
 }
 
+func TestGenerateAgentsMDAndCursorRules(t *testing.T) {
+	t.Parallel()
+	s := &state.State{
+		ProjectName: "inventory-api",
+		Snapshot: &state.ProjectSnapshot{
+			Language:   "Go",
+			Frameworks: []string{"Gin"},
+		},
+		Settings: &state.Settings{TestCommand: "go test ./..."},
+	}
+
+	for name, content := range map[string]string{
+		"AGENTS.md":    GenerateAgentsMD(s),
+		".cursorrules": GenerateCursorRules(s),
+	} {
+		for _, want := range []string{"inventory-api", "Gin", "go test ./...", "## Conventions"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q", name, want)
+			}
+		}
+	}
+}
+
+func TestGenerateAgentFiles(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		settings *state.Settings
+		want     []string
+	}{
+		{"no settings", nil, []string{"CLAUDE.md"}},
+		{"defaults", &state.Settings{}, []string{"CLAUDE.md"}},
+		{"agents only", &state.Settings{GenerateAgentsMD: true}, []string{"CLAUDE.md", "AGENTS.md"}},
+		{"all", &state.Settings{GenerateAgentsMD: true, GenerateCursorRules: true},
+			[]string{"CLAUDE.md", "AGENTS.md", ".cursorrules"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			files := GenerateAgentFiles(&state.State{ProjectName: "p", Settings: tt.settings})
+			if len(files) != len(tt.want) {
+				t.Fatalf("got %d files, want %d", len(files), len(tt.want))
+			}
+			for i, f := range files {
+				if f.Name != tt.want[i] {
+					t.Errorf("files[%d] = %q, want %q", i, f.Name, tt.want[i])
+				}
+				if f.Content == "" {
+					t.Errorf("%s has empty content", f.Name)
+				}
+			}
+		})
+	}
+}
+
 // ============================================================
 // GenerateMCPConfig
 // ============================================================
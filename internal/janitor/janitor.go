@@ -0,0 +1,148 @@
+// Package janitor tracks the temporary files and directories forge creates
+// outside the project, so they can be removed on exit, swept on the next
+// startup after a crash, or cleaned up by hand with `forge cleanup`.
+package janitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/manasm11/forge/internal/platform"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// LedgerFile lists the tracked resources, relative to the .forge directory.
+const LedgerFile = "resources.json"
+
+// Resource is one tracked file or directory.
+type Resource struct {
+	Path      string    `json:"path"`
+	Purpose   string    `json:"purpose"` // shown by `forge cleanup`, e.g. "edit task-003"
+	PID       int       `json:"pid"`     // forge process that created it
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Selector picks the resources Clean removes.
+type Selector func(Resource) bool
+
+// All selects every tracked resource, including ones a running forge
+// still uses.
+func All(Resource) bool { return true }
+
+// Owned selects resources created by this process.
+func Owned(r Resource) bool { return r.PID == os.Getpid() }
+
+// Orphaned selects resources whose creating process has exited.
+func Orphaned(r Resource) bool { return !Owned(r) && !platform.ProcessAlive(r.PID) }
+
+// Load reads the ledger. A missing ledger is empty.
+func Load(root string) ([]Resource, error) {
+	data, err := os.ReadFile(ledgerPath(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading resource ledger: %w", err)
+	}
+	var resources []Resource
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing resource ledger: %w", err)
+	}
+	return resources, nil
+}
+
+func save(root string, resources []Resource) error {
+	path := ledgerPath(root)
+	if len(resources) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating .forge directory: %w", err)
+	}
+	data, err := json.MarshalIndent(resources, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func ledgerPath(root string) string {
+	return filepath.Join(state.ForgeDir(root), LedgerFile)
+}
+
+// Track records a resource owned by this process.
+func Track(root, path, purpose string) error {
+	resources, err := Load(root)
+	if err != nil {
+		return err
+	}
+	resources = append(resources, Resource{Path: path, Purpose: purpose, PID: os.Getpid(), CreatedAt: time.Now()})
+	return save(root, resources)
+}
+
+// CreateTemp writes content to a new temp file named after pattern (see
+// os.CreateTemp) and tracks it.
+func CreateTemp(root, pattern, purpose string, content []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	_, err = f.Write(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = Track(root, path, purpose)
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// Release removes a tracked resource and drops it from the ledger.
+func Release(root, path string) error {
+	_, err := Clean(root, func(r Resource) bool { return r.Path == path })
+	return err
+}
+
+// Clean removes the selected resources and drops them from the ledger.
+// Resources that are already gone count as removed; ones that cannot be
+// removed stay tracked.
+func Clean(root string, which Selector) ([]Resource, error) {
+	resources, err := Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed, kept []Resource
+	var errs []error
+	for _, r := range resources {
+		if !which(r) {
+			kept = append(kept, r)
+			continue
+		}
+		if err := os.RemoveAll(r.Path); err != nil {
+			errs = append(errs, err)
+			kept = append(kept, r)
+			continue
+		}
+		removed = append(removed, r)
+	}
+
+	if len(removed) > 0 {
+		if err := save(root, kept); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package janitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deadPID is assumed not to belong to a running process.
+const deadPID = 1 << 30
+
+func seed(t *testing.T, root string, resources ...Resource) {
+	t.Helper()
+	if err := save(root, resources); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tempFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "forge-edit.txt")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ============================================================
+// CreateTemp / Release
+// ============================================================
+
+func TestCreateTempAndRelease(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	path, err := CreateTemp(root, "forge-test-*.txt", "edit task-001", []byte("hello"))
+	if err != nil {
+		t.Fatalf("CreateTemp() error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "hello" {
+		t.Errorf("content = %q", data)
+	}
+	resources, _ := Load(root)
+	if len(resources) != 1 || resources[0].Path != path || resources[0].PID != os.Getpid() {
+		t.Fatalf("ledger = %+v", resources)
+	}
+
+	if err := Release(root, path); err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if exists(path) {
+		t.Error("Release() left the file behind")
+	}
+	if exists(filepath.Join(root, ".forge", LedgerFile)) {
+		t.Error("empty ledger should be removed")
+	}
+}
+
+// ============================================================
+// Clean
+// ============================================================
+
+func TestClean(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		which       Selector
+		wantRemoved []string
+	}{
+		{name: "orphaned", which: Orphaned, wantRemoved: []string{"crashed"}},
+		{name: "owned", which: Owned, wantRemoved: []string{"mine"}},
+		{name: "all", which: All, wantRemoved: []string{"mine", "crashed", "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			paths := map[string]string{"mine": tempFile(t), "crashed": tempFile(t), "other": tempFile(t)}
+			now := time.Now()
+			seed(t, root,
+				Resource{Path: paths["mine"], Purpose: "mine", PID: os.Getpid(), CreatedAt: now},
+				Resource{Path: paths["crashed"], Purpose: "crashed", PID: deadPID, CreatedAt: now},
+				// the parent process (go test) is alive but is not us
+				Resource{Path: paths["other"], Purpose: "other", PID: os.Getppid(), CreatedAt: now},
+			)
+
+			removed, err := Clean(root, tt.which)
+			if err != nil {
+				t.Fatalf("Clean() error: %v", err)
+			}
+			if len(removed) != len(tt.wantRemoved) {
+				t.Fatalf("removed = %+v, want %v", removed, tt.wantRemoved)
+			}
+			for i, purpose := range tt.wantRemoved {
+				if removed[i].Purpose != purpose || exists(paths[purpose]) {
+					t.Errorf("removed[%d] = %+v, want %s deleted", i, removed[i], purpose)
+				}
+			}
+			left, _ := Load(root)
+			if len(left)+len(removed) != 3 {
+				t.Errorf("ledger keeps %d entries, want %d", len(left), 3-len(removed))
+			}
+		})
+	}
+}
+
+func TestClean_AlreadyGone(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	seed(t, root, Resource{Path: filepath.Join(t.TempDir(), "missing"), PID: deadPID})
+
+	removed, err := Clean(root, Orphaned)
+	if err != nil || len(removed) != 1 {
+		t.Errorf("Clean() = %+v, %v; want the missing file dropped from the ledger", removed, err)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+	resources, err := Load(t.TempDir())
+	if resources != nil || err != nil {
+		t.Errorf("Load() = %v, %v; want nil, nil", resources, err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package knowledge keeps the repository's recurring failure patterns and
+// their resolutions ("tests need docker compose up first") in
+// .forge/knowledge.json. Entries are learned from tasks that recovered from
+// a failure and injected into later task prompts that look related, so the
+// agent stops repeating repo-specific mistakes across tasks and sessions.
+// The file is committed with state.json, so teammates share it.
+package knowledge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// File is the knowledge base's name under .forge/.
+const File = "knowledge.json"
+
+// Entry is a failure pattern and how to avoid it.
+type Entry struct {
+	Pattern    string    `json:"pattern"`            // what goes wrong
+	Resolution string    `json:"resolution"`         // what to do instead
+	Keywords   []string  `json:"keywords,omitempty"` // matched against task text; empty = the pattern's words
+	Seen       int       `json:"seen"`               // times the pattern was hit
+	Tasks      []string  `json:"tasks,omitempty"`    // tasks it was learned from
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Base is the contents of knowledge.json.
+type Base struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Path returns the knowledge base's path for the project at root.
+func Path(root string) string {
+	return filepath.Join(state.ForgeDir(root), File)
+}
+
+// Load reads the knowledge base; a missing file is an empty base.
+func Load(root string) (*Base, error) {
+	data, err := os.ReadFile(Path(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Base{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Base
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", File, err)
+	}
+	return &b, nil
+}
+
+// Save writes the knowledge base.
+func (b *Base) Save(root string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(state.ForgeDir(root), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(Path(root), append(data, '\n'), 0644)
+}
+
+// Add records e. An entry with the same pattern (ignoring case and
+// spacing) is counted again instead, taking the newer resolution. It
+// reports whether the pattern is new.
+func (b *Base) Add(e Entry) bool {
+	key := normalize(e.Pattern)
+	for i := range b.Entries {
+		old := &b.Entries[i]
+		if normalize(old.Pattern) != key {
+			continue
+		}
+		old.Seen++
+		if e.Resolution != "" {
+			old.Resolution = e.Resolution
+		}
+		for _, k := range e.Keywords {
+			if !slices.Contains(old.Keywords, k) {
+				old.Keywords = append(old.Keywords, k)
+			}
+		}
+		for _, t := range e.Tasks {
+			if !slices.Contains(old.Tasks, t) {
+				old.Tasks = append(old.Tasks, t)
+			}
+		}
+		old.UpdatedAt = e.UpdatedAt
+		return false
+	}
+	if e.Seen == 0 {
+		e.Seen = 1
+	}
+	b.Entries = append(b.Entries, e)
+	return true
+}
+
+var wordRe = regexp.MustCompile(`[a-z0-9][a-z0-9_.]*[a-z0-9]`)
+
+// stopWords are too common to tie an entry to a task.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "when": true, "before": true, "after": true, "into": true, "are": true,
+	"not": true, "fails": true, "failed": true, "error": true, "test": true, "tests": true,
+	"run": true, "runs": true, "need": true, "needs": true, "first": true, "file": true,
+}
+
+func words(text string) []string {
+	var out []string
+	for _, w := range wordRe.FindAllString(strings.ToLower(text), -1) {
+		if len(w) >= 3 && !stopWords[w] && !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func (e Entry) terms() []string {
+	if len(e.Keywords) > 0 {
+		return words(strings.Join(e.Keywords, " "))
+	}
+	return words(e.Pattern)
+}
+
+// Relevant returns up to limit entries whose terms appear in text (a task's
+// title, description and criteria, or a failure's output), best match
+// first. An entry needs two of its terms to match, or its only one.
+func (b *Base) Relevant(text string, limit int) []Entry {
+	present := map[string]bool{}
+	for _, w := range words(text) {
+		present[w] = true
+	}
+	type scored struct {
+		entry Entry
+		score int
+	}
+	var matches []scored
+	for _, e := range b.Entries {
+		terms := e.terms()
+		score := 0
+		for _, t := range terms {
+			if present[t] {
+				score++
+			}
+		}
+		if score > 0 && score >= min(2, len(terms)) {
+			matches = append(matches, scored{e, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.Seen > matches[j].entry.Seen
+	})
+	var out []Entry
+	for i := 0; i < len(matches) && i < limit; i++ {
+		out = append(out, matches[i].entry)
+	}
+	return out
+}
+
+// Section formats entries for a task prompt; "" when there are none.
+func Section(entries []Entry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nKNOWN PITFALLS IN THIS REPOSITORY (learned from earlier tasks):\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- %s → %s\n", e.Pattern, e.Resolution)
+	}
+	return b.String()
+}
+
+// LessonInstruction asks Claude to report what it learned when it fixes a
+// failure, so the fix can be remembered.
+const LessonInstruction = "\nIf the failure came from something specific to this repository that a later task could hit too " +
+	"(a service to start, a generated file, an unusual command or flag), end your reply with one line:\n" +
+	"LESSON: <what goes wrong> => <how to avoid it>\n"
+
+var lessonRe = regexp.MustCompile(`(?i)^(?:[-*]\s*)?\**LESSON\**\s*:\s*\**\s*(.+?)\s*(?:=>|->|→)\s*(.+)$`)
+
+// ParseLessons reads the LESSON lines from a reply.
+func ParseLessons(text string) []Entry {
+	var lessons []Entry
+	for _, line := range strings.Split(text, "\n") {
+		m := lessonRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		pattern, resolution := strings.TrimSpace(m[1]), strings.TrimSpace(strings.TrimRight(m[2], "*"))
+		if pattern == "" || resolution == "" || strings.HasPrefix(pattern, "<") {
+			continue
+		}
+		lessons = append(lessons, Entry{Pattern: pattern, Resolution: resolution})
+	}
+	return lessons
+}
@@ -0,0 +1,135 @@
+package knowledge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSave(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	b, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load() on a new project: %v", err)
+	}
+	if len(b.Entries) != 0 {
+		t.Fatalf("Load() on a new project = %+v, want empty", b)
+	}
+
+	when := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b.Add(Entry{Pattern: "integration tests need postgres", Resolution: "run docker compose up -d db first",
+		Tasks: []string{"task-002"}, UpdatedAt: when})
+	if err := b.Save(root); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	got, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("Load() = %+v, want %+v", got, b)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+	var b Base
+	if !b.Add(Entry{Pattern: "Mocks are stale", Resolution: "run make mocks", Tasks: []string{"task-001"}}) {
+		t.Error("Add() of a new pattern = false")
+	}
+	if b.Add(Entry{Pattern: "  mocks ARE stale ", Resolution: "run go generate ./...", Tasks: []string{"task-004"}}) {
+		t.Error("Add() of a known pattern = true")
+	}
+	want := []Entry{{Pattern: "Mocks are stale", Resolution: "run go generate ./...", Seen: 2, Tasks: []string{"task-001", "task-004"}}}
+	if !reflect.DeepEqual(b.Entries, want) {
+		t.Errorf("Entries = %+v, want %+v", b.Entries, want)
+	}
+}
+
+func TestRelevant(t *testing.T) {
+	t.Parallel()
+	b := Base{Entries: []Entry{
+		{Pattern: "integration tests need postgres running", Resolution: "docker compose up -d db", Seen: 1},
+		{Pattern: "generated mocks are stale", Resolution: "run make mocks", Seen: 3},
+		{Pattern: "protobuf", Keywords: []string{"proto", "grpc"}, Resolution: "run buf generate", Seen: 1},
+	}}
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string // patterns
+	}{
+		{name: "two terms match", text: "Add a postgres-backed integration test for orders", limit: 5,
+			want: []string{"integration tests need postgres running"}},
+		{name: "one term of several is not enough", text: "Move the config to postgres", limit: 5},
+		{name: "keywords replace the pattern's words", text: "Expose orders over gRPC", limit: 5},
+		{name: "stop words don't match", text: "the tests need to run first", limit: 5},
+		{name: "best match first", text: "grpc proto changes break the integration suite unless postgres is running", limit: 5,
+			want: []string{"integration tests need postgres running", "protobuf"}},
+		{name: "ties go to the most seen", text: "stale generated mocks; grpc proto", limit: 5,
+			want: []string{"generated mocks are stale", "protobuf"}},
+		{name: "limit", text: "stale generated mocks; grpc proto", limit: 1, want: []string{"generated mocks are stale"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, e := range b.Relevant(tt.text, tt.limit) {
+				got = append(got, e.Pattern)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Relevant(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSection(t *testing.T) {
+	t.Parallel()
+	if got := Section(nil); got != "" {
+		t.Errorf("Section(nil) = %q, want empty", got)
+	}
+	got := Section([]Entry{{Pattern: "mocks are stale", Resolution: "run make mocks"}})
+	for _, want := range []string{"KNOWN PITFALLS", "- mocks are stale → run make mocks\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Section() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseLessons(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		text string
+		want []Entry
+	}{
+		{
+			name: "one lesson",
+			text: "Fixed the test.\nLESSON: tests fail without postgres => run docker compose up -d db first",
+			want: []Entry{{Pattern: "tests fail without postgres", Resolution: "run docker compose up -d db first"}},
+		},
+		{
+			name: "markdown and arrows",
+			text: "- **LESSON:** mocks are stale → run make mocks\nlesson: lint needs golangci v1.59 -> use make lint",
+			want: []Entry{
+				{Pattern: "mocks are stale", Resolution: "run make mocks"},
+				{Pattern: "lint needs golangci v1.59", Resolution: "use make lint"},
+			},
+		},
+		{name: "echoed template", text: "LESSON: <what goes wrong> => <how to avoid it>"},
+		{name: "no resolution", text: "LESSON: the build is flaky"},
+		{name: "none", text: "All tests pass now."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ParseLessons(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLessons() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
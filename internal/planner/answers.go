@@ -0,0 +1,206 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AnswerFileName is the answer file picked up automatically by the TUI,
+// relative to the .forge directory.
+const AnswerFileName = "answers.yaml"
+
+// Decision is one predetermined choice, e.g. database: PostgreSQL.
+type Decision struct {
+	Key   string
+	Value string
+}
+
+// AnswerFile holds decisions settled before planning starts, so repeated
+// planning runs for the same spec converge on the same plan:
+//
+//	stack:
+//	  language: Go
+//	  database: PostgreSQL
+//	must:
+//	  - Ship a single static binary
+//	must_not:
+//	  - Add a web frontend
+//	answers:
+//	  "Do you need auth?": No, single user
+//
+// Entries keep their file order so the injected prompt is stable.
+type AnswerFile struct {
+	Stack   []Decision
+	Must    []string
+	MustNot []string
+	Answers []Decision
+}
+
+// LoadAnswerFile reads and parses an answer file. A missing file is not an
+// error and returns nil.
+func LoadAnswerFile(path string) (*AnswerFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	af, err := ParseAnswerFile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return af, nil
+}
+
+// ParseAnswerFile parses the small YAML subset answer files use: top-level
+// sections holding either a list ("- item") or a mapping ("key: value"),
+// with # comments and optionally quoted strings.
+func ParseAnswerFile(text string) (*AnswerFile, error) {
+	af := &AnswerFile{}
+	section := ""
+	for n, raw := range strings.Split(text, "\n") {
+		line := stripComment(strings.TrimRight(raw, " \t\r"))
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineNo := n + 1
+
+		// Section header: unindented "name:"
+		if line[0] != ' ' && line[0] != '\t' {
+			name, rest, ok := strings.Cut(line, ":")
+			if !ok || strings.TrimSpace(rest) != "" {
+				return nil, fmt.Errorf("line %d: expected a section header like \"must:\"", lineNo)
+			}
+			section = strings.TrimSpace(name)
+			switch section {
+			case "stack", "must", "must_not", "answers":
+			default:
+				return nil, fmt.Errorf("line %d: unknown section %q (want stack, must, must_not or answers)", lineNo, section)
+			}
+			continue
+		}
+
+		item := strings.TrimSpace(line)
+		switch section {
+		case "":
+			return nil, fmt.Errorf("line %d: entry outside of a section", lineNo)
+		case "must", "must_not":
+			value, ok := strings.CutPrefix(item, "-")
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s entries are list items (\"- ...\")", lineNo, section)
+			}
+			value = unquote(strings.TrimSpace(value))
+			if value == "" {
+				continue
+			}
+			if section == "must" {
+				af.Must = append(af.Must, value)
+			} else {
+				af.MustNot = append(af.MustNot, value)
+			}
+		case "stack", "answers":
+			key, value, err := splitMapping(item)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			d := Decision{Key: key, Value: value}
+			if section == "stack" {
+				af.Stack = append(af.Stack, d)
+			} else {
+				af.Answers = append(af.Answers, d)
+			}
+		}
+	}
+	return af, nil
+}
+
+// PromptSection renders the decisions for injection into the planning
+// prompt. Returns "" for an empty or nil file.
+func (af *AnswerFile) PromptSection() string {
+	if af == nil || len(af.Stack)+len(af.Must)+len(af.MustNot)+len(af.Answers) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nPREDETERMINED DECISIONS (from the project's answer file — treat these as settled, do not ask about them, and make the plan follow them):\n")
+	if len(af.Stack) > 0 {
+		b.WriteString("Stack:\n")
+		for _, d := range af.Stack {
+			fmt.Fprintf(&b, "- %s: %s\n", d.Key, d.Value)
+		}
+	}
+	if len(af.Must) > 0 {
+		b.WriteString("Must:\n")
+		for _, m := range af.Must {
+			fmt.Fprintf(&b, "- %s\n", m)
+		}
+	}
+	if len(af.MustNot) > 0 {
+		b.WriteString("Must not:\n")
+		for _, m := range af.MustNot {
+			fmt.Fprintf(&b, "- %s\n", m)
+		}
+	}
+	if len(af.Answers) > 0 {
+		b.WriteString("Answers to questions you might ask:\n")
+		for _, d := range af.Answers {
+			fmt.Fprintf(&b, "- %s → %s\n", d.Key, d.Value)
+		}
+	}
+	return b.String()
+}
+
+// splitMapping splits "key: value", where either side may be quoted.
+func splitMapping(item string) (string, string, error) {
+	var key, rest string
+	if q := item[0]; q == '"' || q == '\'' {
+		end := strings.IndexByte(item[1:], q)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quote")
+		}
+		key, rest = item[1:end+1], strings.TrimSpace(item[end+2:])
+		var ok bool
+		if rest, ok = strings.CutPrefix(rest, ":"); !ok {
+			return "", "", fmt.Errorf("expected \"key: value\"")
+		}
+	} else {
+		var ok bool
+		if key, rest, ok = strings.Cut(item, ":"); !ok {
+			return "", "", fmt.Errorf("expected \"key: value\"")
+		}
+		key = strings.TrimSpace(key)
+	}
+	value := unquote(strings.TrimSpace(rest))
+	if key == "" || value == "" {
+		return "", "", fmt.Errorf("expected \"key: value\"")
+	}
+	return key, value, nil
+}
+
+// stripComment drops a trailing # comment that is outside quotes.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t-:", line[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
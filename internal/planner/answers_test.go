@@ -0,0 +1,124 @@
+package planner
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+)
+
+// ============================================================
+// ParseAnswerFile
+// ============================================================
+
+func TestParseAnswerFile(t *testing.T) {
+	t.Parallel()
+	text := `# settled before planning
+stack:
+  language: Go
+  database: PostgreSQL   # not SQLite
+must:
+  - Ship a single static binary
+  - "Support #hashtags in titles"
+must_not:
+  - Add a web frontend
+
+answers:
+  "Do you need auth?": No, single user
+  'Where is it deployed?': Fly.io's free tier
+`
+	got, err := ParseAnswerFile(text)
+	if err != nil {
+		t.Fatalf("ParseAnswerFile() error: %v", err)
+	}
+	want := &AnswerFile{
+		Stack:   []Decision{{"language", "Go"}, {"database", "PostgreSQL"}},
+		Must:    []string{"Ship a single static binary", "Support #hashtags in titles"},
+		MustNot: []string{"Add a web frontend"},
+		Answers: []Decision{
+			{"Do you need auth?", "No, single user"},
+			{"Where is it deployed?", "Fly.io's free tier"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAnswerFile() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseAnswerFile_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "unknown section", text: "stack:\n  a: b\nextras:\n", wantErr: "line 3: unknown section"},
+		{name: "entry outside section", text: "  - orphan\n", wantErr: "line 1: entry outside"},
+		{name: "list item without dash", text: "must:\n  tests pass\n", wantErr: "line 2: must entries are list items"},
+		{name: "mapping without value", text: "stack:\n  language:\n", wantErr: "line 2: expected \"key: value\""},
+		{name: "unterminated quote", text: "answers:\n  \"Auth?: no\n", wantErr: "unterminated quote"},
+		{name: "inline section value", text: "must: tests pass\n", wantErr: "section header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseAnswerFile(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseAnswerFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ============================================================
+// PromptSection
+// ============================================================
+
+func TestPromptSection(t *testing.T) {
+	t.Parallel()
+	af := &AnswerFile{
+		Stack:   []Decision{{"language", "Go"}},
+		MustNot: []string{"Add a web frontend"},
+		Answers: []Decision{{"Auth?", "No"}},
+	}
+	got := af.PromptSection()
+	for _, want := range []string{"PREDETERMINED DECISIONS", "Stack:\n- language: Go\n", "Must not:\n- Add a web frontend\n", "- Auth? → No\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("PromptSection() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Must:") {
+		t.Errorf("PromptSection() rendered an empty Must section:\n%s", got)
+	}
+
+	var none *AnswerFile
+	if none.PromptSection() != "" || (&AnswerFile{}).PromptSection() != "" {
+		t.Error("PromptSection() should be empty for a nil or empty file")
+	}
+}
+
+// ============================================================
+// LoadAnswerFile
+// ============================================================
+
+func TestLoadAnswerFile_Missing(t *testing.T) {
+	t.Parallel()
+	af, err := LoadAnswerFile(filepath.Join(t.TempDir(), AnswerFileName))
+	if af != nil || err != nil {
+		t.Errorf("LoadAnswerFile() = %v, %v; want nil, nil", af, err)
+	}
+}
+
+func TestRun_InjectsDecisions(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{Text: planResponse})
+	decided := &AnswerFile{Must: []string{"Use SQLite"}}
+
+	if _, err := Run(context.Background(), mock, Options{Prompt: "Build a todo CLI", Decided: decided}); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mock.AssertCall(t, 0, "Send", "Must:\n- Use SQLite\n")
+}
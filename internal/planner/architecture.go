@@ -0,0 +1,49 @@
+package planner
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// ArchitectureFileName is the plan's architecture document, relative to
+// the .forge directory. The planner writes it with the plan (components,
+// data flow, major decisions) and replaces it when a revision changes
+// them; every task prompt includes it.
+const ArchitectureFileName = "architecture.md"
+
+// LoadArchitecture reads the architecture document. A missing file is not
+// an error and returns "".
+func LoadArchitecture(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SaveArchitecture writes the architecture document, replacing the old
+// one. An empty doc leaves the file as it is and reports false.
+func SaveArchitecture(path, doc string) (bool, error) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return false, nil
+	}
+	if !strings.HasPrefix(doc, "# ") {
+		doc = "# Architecture\n\n" + doc
+	}
+	return true, os.WriteFile(path, []byte(doc+"\n"), 0644)
+}
+
+// ArchitectureSection renders the document for injection into a planning
+// or task prompt. Returns "" for an empty document.
+func ArchitectureSection(doc string) string {
+	if strings.TrimSpace(doc) == "" {
+		return ""
+	}
+	return "\n\nARCHITECTURE (the plan's components, data flow and major decisions — stay consistent with it):\n" +
+		strings.TrimSpace(doc) + "\n"
+}
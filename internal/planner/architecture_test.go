@@ -0,0 +1,37 @@
+package planner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveArchitecture(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ArchitectureFileName)
+
+	if doc, err := LoadArchitecture(path); doc != "" || err != nil {
+		t.Fatalf("LoadArchitecture(missing) = %q, %v; want \"\", nil", doc, err)
+	}
+	if saved, err := SaveArchitecture(path, "## Components\n- store: SQLite access\n"); !saved || err != nil {
+		t.Fatalf("SaveArchitecture() = %v, %v", saved, err)
+	}
+	// An update without a document keeps the current one
+	if saved, err := SaveArchitecture(path, "  "); saved || err != nil {
+		t.Fatalf("SaveArchitecture(empty) = %v, %v; want false, nil", saved, err)
+	}
+
+	doc, err := LoadArchitecture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Architecture\n\n## Components\n- store: SQLite access"; doc != want {
+		t.Errorf("LoadArchitecture() = %q, want %q", doc, want)
+	}
+	if section := ArchitectureSection(doc); !strings.Contains(section, "ARCHITECTURE") || !strings.Contains(section, "- store: SQLite access") {
+		t.Errorf("ArchitectureSection() = %q", section)
+	}
+	if ArchitectureSection("") != "" {
+		t.Error("ArchitectureSection(\"\") should be empty")
+	}
+}
@@ -0,0 +1,67 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// CriteriaEdit is a proposed rewrite of one task's acceptance criteria.
+type CriteriaEdit struct {
+	TaskID string
+	Title  string
+	Old    []string
+	New    []string
+}
+
+// SuggestCriteria asks the model to tighten the vague acceptance criteria
+// of the pending tasks into verifiable statements. It returns one edit per
+// task whose criteria would change, in plan order; suggestions for other
+// tasks are dropped.
+//
+// Like Critique, the request starts a session of its own.
+func SuggestCriteria(ctx context.Context, c claude.Claude, tasks []state.Task) ([]CriteriaEdit, error) {
+	var pending []CritiqueTask
+	for _, t := range CritiqueTasks(tasks) {
+		if t.Status == string(state.TaskPending) {
+			pending = append(pending, t)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("no pending tasks")
+	}
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding tasks: %w", err)
+	}
+
+	resp, err := c.Send(ctx, fmt.Sprintf(claude.CriteriaPrompt, data))
+	if err != nil {
+		return nil, fmt.Errorf("asking for criteria: %w", err)
+	}
+	reply, err := claude.ExtractCriteria(resp.Text)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("the model did not return <criteria>")
+	}
+
+	proposed := make(map[string][]string, len(reply.Tasks))
+	for _, t := range reply.Tasks {
+		proposed[t.ID] = t.AcceptanceCriteria
+	}
+	var edits []CriteriaEdit
+	for _, t := range pending {
+		criteria, ok := proposed[t.ID]
+		if !ok || slices.Equal(criteria, t.AcceptanceCriteria) {
+			continue
+		}
+		edits = append(edits, CriteriaEdit{TaskID: t.ID, Title: t.Title, Old: t.AcceptanceCriteria, New: criteria})
+	}
+	return edits, nil
+}
@@ -0,0 +1,73 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestSuggestCriteria(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{
+		Text: `<criteria>{"tasks": [
+			{"id": "task-002", "acceptance_criteria": ["GET /users returns 200 with a JSON list", "go test ./api/... passes"]},
+			{"id": "task-003", "acceptance_criteria": ["make lint passes"]},
+			{"id": "task-001", "acceptance_criteria": ["rewritten done task"]},
+			{"id": "task-009", "acceptance_criteria": ["unknown task"]}]}</criteria>`,
+	})
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Setup", Status: state.TaskDone, AcceptanceCriteria: []string{"it builds"}},
+		{ID: "task-002", Title: "Users API", Status: state.TaskPending, AcceptanceCriteria: []string{"API works well"}},
+		{ID: "task-003", Title: "Lint", Status: state.TaskPending, AcceptanceCriteria: []string{"make lint passes"}},
+	}
+
+	edits, err := SuggestCriteria(context.Background(), mock, tasks)
+	if err != nil {
+		t.Fatalf("SuggestCriteria() error: %v", err)
+	}
+	want := []CriteriaEdit{{
+		TaskID: "task-002",
+		Title:  "Users API",
+		Old:    []string{"API works well"},
+		New:    []string{"GET /users returns 200 with a JSON list", "go test ./api/... passes"},
+	}}
+	if !reflect.DeepEqual(edits, want) {
+		t.Errorf("edits = %+v, want %+v", edits, want)
+	}
+	mock.AssertCallCount(t, 1)
+	mock.AssertCall(t, 0, "Send", `"title": "Users API"`)
+	if strings.Contains(mock.Calls[0].Prompt, "Setup") {
+		t.Error("done tasks should not be sent")
+	}
+}
+
+func TestSuggestCriteria_Errors(t *testing.T) {
+	t.Parallel()
+	pending := []state.Task{{ID: "task-001", Title: "API", Status: state.TaskPending}}
+	tests := []struct {
+		name    string
+		tasks   []state.Task
+		resp    claude.MockResponse
+		wantErr string
+	}{
+		{name: "nothing pending", tasks: []state.Task{{ID: "task-001", Status: state.TaskDone}}, wantErr: "no pending tasks"},
+		{name: "send fails", tasks: pending, resp: claude.MockResponse{Err: fmt.Errorf("boom")}, wantErr: "boom"},
+		{name: "no tags", tasks: pending, resp: claude.MockResponse{Text: "They look fine."}, wantErr: "did not return"},
+		{name: "empty list", tasks: pending, resp: claude.MockResponse{Text: `<criteria>{"tasks": [{"id": "task-001", "acceptance_criteria": []}]}</criteria>`},
+			wantErr: "needs at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := SuggestCriteria(context.Background(), claude.NewMockClaude(tt.resp), tt.tasks)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SuggestCriteria() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// CritiqueTask is the view of a task the critic reviews.
+type CritiqueTask struct {
+	ID                 string   `json:"id"`
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
+	DependsOn          []string `json:"depends_on,omitempty"`
+	Type               string   `json:"type,omitempty"`
+	Status             string   `json:"status"`
+}
+
+// CritiqueTasks reduces state tasks to what the critic needs, in plan
+// order. Cancelled and skipped tasks are left out.
+func CritiqueTasks(tasks []state.Task) []CritiqueTask {
+	var out []CritiqueTask
+	for _, t := range tasks {
+		if t.Status == state.TaskCancelled || t.Status == state.TaskSkipped {
+			continue
+		}
+		out = append(out, CritiqueTask{
+			ID:                 t.ID,
+			Title:              t.Title,
+			Description:        t.Description,
+			AcceptanceCriteria: t.AcceptanceCriteria,
+			DependsOn:          t.DependsOn,
+			Type:               string(t.Type),
+			Status:             string(t.Status),
+		})
+	}
+	return out
+}
+
+// Critique asks a second model to review a finished plan (any value that
+// marshals to the plan's JSON) for missing steps, risky orderings and
+// absent testing. It returns one finding per problem; none means the
+// critic found nothing worth raising.
+//
+// c should be a separate client from the planning conversation, since
+// the critique starts a session of its own.
+func Critique(ctx context.Context, c claude.Claude, plan any) ([]string, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding plan: %w", err)
+	}
+
+	resp, err := c.Send(ctx, fmt.Sprintf(claude.CritiquePrompt, data))
+	if err != nil {
+		return nil, fmt.Errorf("running critic: %w", err)
+	}
+	findings, found := claude.ExtractCritique(resp.Text)
+	if !found {
+		return nil, fmt.Errorf("the critic did not return a <critique>")
+	}
+	return findings, nil
+}
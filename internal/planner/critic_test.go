@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// ============================================================
+// Critique
+// ============================================================
+
+func TestCritique(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{
+		Text: "<critique>\n- No task covers tests for the API.\n- \"Deploy\" depends on nothing.\n</critique>",
+	})
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Build API", Status: state.TaskPending},
+		{ID: "task-002", Title: "Old idea", Status: state.TaskCancelled},
+	}
+
+	findings, err := Critique(context.Background(), mock, CritiqueTasks(tasks))
+	if err != nil {
+		t.Fatalf("Critique() error: %v", err)
+	}
+	if len(findings) != 2 || findings[0] != "No task covers tests for the API." {
+		t.Errorf("findings = %q", findings)
+	}
+	mock.AssertCallCount(t, 1)
+	mock.AssertCall(t, 0, "Send", `"title": "Build API"`)
+	if strings.Contains(mock.Calls[0].Prompt, "Old idea") {
+		t.Error("cancelled tasks should not be sent to the critic")
+	}
+}
+
+func TestCritique_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		resp    claude.MockResponse
+		wantErr string
+	}{
+		{name: "send fails", resp: claude.MockResponse{Err: fmt.Errorf("boom")}, wantErr: "boom"},
+		{name: "no critique tags", resp: claude.MockResponse{Text: "Looks good to me."}, wantErr: "did not return"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Critique(context.Background(), claude.NewMockClaude(tt.resp), []CritiqueTask{{ID: "task-001"}})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Critique() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
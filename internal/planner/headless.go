@@ -0,0 +1,103 @@
+// Package planner runs the planning conversation without the TUI, for
+// scripts and bulk plan generation (`forge plan`).
+package planner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/scanner"
+)
+
+// Options configures a headless planning session.
+type Options struct {
+	Prompt   string                   // project description, sent as the first message
+	Answers  []string                 // scripted replies to follow-up questions, in order
+	Snapshot *scanner.ProjectSnapshot // existing project context (nil = new project)
+	Decided  *AnswerFile              // predetermined decisions (nil = none)
+	Memory   *Memory                  // project memory from earlier plans (nil = none)
+	Docs     string                   // reference documents section (docs.Context), if any
+	Schema   string                   // database schema section (dbschema.Context), if any
+	Analysis string                   // static analysis and coverage section (analysis.Section), if any
+}
+
+// Run sends the prompt, then each scripted answer, until the model
+// produces a final plan. Once the answers run out it asks for the plan
+// outright, as /done does in the TUI.
+func Run(ctx context.Context, c claude.Claude, opts Options) (*claude.PlanJSON, error) {
+	if strings.TrimSpace(opts.Prompt) == "" {
+		return nil, fmt.Errorf("a prompt describing the project is required")
+	}
+
+	first := claude.InitialPlanningPrompt + opts.Decided.PromptSection() + opts.Memory.PromptSection() + opts.Docs + opts.Schema + opts.Analysis +
+		claude.ProjectContext(opts.Snapshot) + "\n\nUser: " + opts.Prompt
+	resp, err := c.Send(ctx, first)
+	if err != nil {
+		return nil, fmt.Errorf("sending prompt: %w", err)
+	}
+
+	for _, answer := range opts.Answers {
+		if plan, err := extractPlan(ctx, c, resp.Text); err != nil || plan != nil {
+			return plan, err
+		}
+		if resp, err = c.Continue(ctx, answer); err != nil {
+			return nil, fmt.Errorf("sending answer: %w", err)
+		}
+	}
+
+	if plan, err := extractPlan(ctx, c, resp.Text); err != nil || plan != nil {
+		return plan, err
+	}
+	if resp, err = c.Continue(ctx, claude.FinalPlanInstruction); err != nil {
+		return nil, fmt.Errorf("requesting final plan: %w", err)
+	}
+	plan, err := extractPlan(ctx, c, resp.Text)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("the model did not return a final plan")
+	}
+	return plan, nil
+}
+
+// extractPlan returns the final plan in a reply, if any. When its JSON
+// can't be used, the model is asked once to fix it; the original error is
+// returned if the fix doesn't carry a plan either.
+func extractPlan(ctx context.Context, c claude.Claude, text string) (*claude.PlanJSON, error) {
+	plan, err := claude.ExtractFinalPlan(text)
+	if err == nil {
+		return plan, nil
+	}
+	resp, sendErr := c.Continue(ctx, claude.JSONFixInstruction("final_plan", err))
+	if sendErr != nil {
+		return nil, fmt.Errorf("%w (asking for a fix: %v)", err, sendErr)
+	}
+	fixed, fixErr := claude.ExtractFinalPlan(resp.Text)
+	switch {
+	case fixErr != nil:
+		return nil, fixErr
+	case fixed == nil:
+		return nil, err
+	}
+	return fixed, nil
+}
+
+// ReadAnswers reads scripted answers, one per non-blank line. Lines
+// starting with # are comments.
+func ReadAnswers(r io.Reader) ([]string, error) {
+	var answers []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		answers = append(answers, line)
+	}
+	return answers, sc.Err()
+}
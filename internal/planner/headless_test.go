@@ -0,0 +1,142 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/scanner"
+)
+
+const planResponse = `Here you go.
+<final_plan>
+{"project_name": "todo-cli", "description": "A todo CLI", "tech_stack": ["Go"],
+ "tasks": [{"title": "Init", "description": "Set up module", "acceptance_criteria": ["builds"], "complexity": "small", "depends_on": []}]}
+</final_plan>`
+
+// ============================================================
+// Run
+// ============================================================
+
+func TestRun_SingleShot(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(
+		claude.MockResponse{Text: "What storage should it use?"},
+		claude.MockResponse{Text: planResponse},
+	)
+
+	plan, err := Run(context.Background(), mock, Options{Prompt: "Build a todo CLI"})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if plan.ProjectName != "todo-cli" || len(plan.Tasks) != 1 {
+		t.Errorf("plan = %+v", plan)
+	}
+	mock.AssertCallCount(t, 2)
+	mock.AssertCall(t, 0, "Send", "User: Build a todo CLI")
+	mock.AssertCall(t, 1, "Continue", "<final_plan>")
+}
+
+func TestRun_ScriptedAnswers(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(
+		claude.MockResponse{Text: "What storage?"},
+		claude.MockResponse{Text: "Any auth?"},
+		claude.MockResponse{Text: planResponse},
+	)
+
+	_, err := Run(context.Background(), mock, Options{
+		Prompt:  "Build a todo CLI",
+		Answers: []string{"SQLite", "No auth"},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mock.AssertCallCount(t, 3)
+	mock.AssertCall(t, 1, "Continue", "SQLite")
+	mock.AssertCall(t, 2, "Continue", "No auth")
+}
+
+func TestRun_StopsAsSoonAsPlanArrives(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{Text: planResponse})
+
+	if _, err := Run(context.Background(), mock, Options{Prompt: "Build it", Answers: []string{"unused"}}); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mock.AssertCallCount(t, 1)
+}
+
+func TestRun_IncludesProjectContext(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{Text: planResponse})
+	snap := &scanner.ProjectSnapshot{IsExisting: true, Language: "Go"}
+
+	if _, err := Run(context.Background(), mock, Options{Prompt: "Add a web UI", Snapshot: snap}); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mock.AssertCall(t, 0, "Send", "EXISTING PROJECT CONTEXT:\nLanguage: Go")
+}
+
+func TestRun_AsksOnceToFixJSON(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(
+		claude.MockResponse{Text: `<final_plan>{"project_name": "todo-cli" "tasks": []}</final_plan>`},
+		claude.MockResponse{Text: planResponse},
+	)
+
+	plan, err := Run(context.Background(), mock, Options{Prompt: "Build a todo CLI"})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if plan.ProjectName != "todo-cli" {
+		t.Errorf("plan = %+v", plan)
+	}
+	mock.AssertCallCount(t, 2)
+	mock.AssertCall(t, 1, "Continue", "could not be used")
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prompt    string
+		responses []claude.MockResponse
+		wantErr   string
+	}{
+		{name: "empty prompt", prompt: " ", wantErr: "prompt"},
+		{name: "send fails", prompt: "x", responses: []claude.MockResponse{{Err: fmt.Errorf("boom")}}, wantErr: "boom"},
+		{name: "never plans", prompt: "x", responses: []claude.MockResponse{{Text: "hmm"}, {Text: "still thinking"}}, wantErr: "did not return a final plan"},
+		{name: "malformed plan", prompt: "x", responses: []claude.MockResponse{{Text: "<final_plan>{</final_plan>"}}, wantErr: "invalid JSON"},
+		{name: "fix is malformed too", prompt: "x", responses: []claude.MockResponse{
+			{Text: "<final_plan>{</final_plan>"}, {Text: `<final_plan>{"project_name": "p", "tasks": []}</final_plan>`}}, wantErr: "tasks needs at least 1"},
+		{name: "fix has no plan", prompt: "x", responses: []claude.MockResponse{
+			{Text: "<final_plan>{</final_plan>"}, {Text: "Sorry."}}, wantErr: "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Run(context.Background(), claude.NewMockClaude(tt.responses...), Options{Prompt: tt.prompt})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ============================================================
+// ReadAnswers
+// ============================================================
+
+func TestReadAnswers(t *testing.T) {
+	t.Parallel()
+	got, err := ReadAnswers(strings.NewReader("# storage\nSQLite\n\n  No auth  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "SQLite" || got[1] != "No auth" {
+		t.Errorf("ReadAnswers() = %q", got)
+	}
+}
@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/manasm11/forge/internal/claude"
+)
+
+// MemoryFileName is the project memory, relative to the .forge directory.
+// Unlike state.json it outlives any one plan: every planning session reads
+// it and every accepted plan adds to it.
+const MemoryFileName = "memory.md"
+
+// Memory is what planning sessions agreed that later plans in the same
+// repository should know. The file is markdown, so it can be edited by
+// hand:
+//
+//	# Project memory
+//
+//	## Decisions
+//	- PostgreSQL over SQLite for concurrent writers
+//
+//	## Constraints
+//	- Handlers never touch the database directly
+//
+//	## Conventions
+//	- Table names are plural snake_case
+type Memory struct {
+	Decisions   []string
+	Constraints []string
+	Conventions []string
+}
+
+// memorySections are the file's headings, in order.
+var memorySections = []string{"Decisions", "Constraints", "Conventions"}
+
+func (m *Memory) section(name string) *[]string {
+	switch strings.ToLower(name) {
+	case "decisions":
+		return &m.Decisions
+	case "constraints":
+		return &m.Constraints
+	case "conventions":
+		return &m.Conventions
+	}
+	return nil
+}
+
+// LoadMemory reads the project memory. A missing file is not an error and
+// returns nil.
+func LoadMemory(path string) (*Memory, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseMemory(string(data)), nil
+}
+
+// ParseMemory reads the "- " items under each "## " heading. Other text,
+// including items under unknown headings, is ignored.
+func ParseMemory(text string) *Memory {
+	m := &Memory{}
+	var items *[]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			items = m.section(strings.TrimSpace(heading))
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok || items == nil {
+			continue
+		}
+		if item = strings.TrimSpace(item); item != "" {
+			*items = append(*items, item)
+		}
+	}
+	return m
+}
+
+// Format renders the memory file.
+func (m *Memory) Format() string {
+	var b strings.Builder
+	b.WriteString("# Project memory\n\n")
+	b.WriteString("Kept by forge across plans. Edit freely; each planning session reads it.\n")
+	for _, name := range memorySections {
+		items := *m.section(name)
+		if len(items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", name)
+		for _, item := range items {
+			b.WriteString("- " + item + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Save writes the memory file.
+func (m *Memory) Save(path string) error {
+	return os.WriteFile(path, []byte(m.Format()), 0644)
+}
+
+// Remember adds a plan's memory entries, skipping ones already known
+// (ignoring case). It returns how many were added.
+func (m *Memory) Remember(j *claude.MemoryJSON) int {
+	if j == nil {
+		return 0
+	}
+	added := 0
+	for _, s := range []struct {
+		name  string
+		items []string
+	}{{"decisions", j.Decisions}, {"constraints", j.Constraints}, {"conventions", j.Conventions}} {
+		known := m.section(s.name)
+		for _, item := range s.items {
+			item = strings.TrimSpace(item)
+			if item == "" || slices.ContainsFunc(*known, func(k string) bool { return strings.EqualFold(k, item) }) {
+				continue
+			}
+			*known = append(*known, item)
+			added++
+		}
+	}
+	return added
+}
+
+// PromptSection renders the memory for injection into the planning
+// prompt. Returns "" for an empty or nil memory.
+func (m *Memory) PromptSection() string {
+	if m == nil || len(m.Decisions)+len(m.Constraints)+len(m.Conventions) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nPROJECT MEMORY (agreed while planning earlier work in this repository — keep the plan consistent with it unless the user changes it):\n")
+	for _, name := range memorySections {
+		items := *m.section(name)
+		if len(items) == 0 {
+			continue
+		}
+		b.WriteString(name + ":\n")
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// RememberPlan adds a plan's memory entries to the memory file at path,
+// creating it if needed. It returns how many entries were added.
+func RememberPlan(path string, j *claude.MemoryJSON) (int, error) {
+	m, err := LoadMemory(path)
+	if err != nil {
+		return 0, err
+	}
+	if m == nil {
+		m = &Memory{}
+	}
+	added := m.Remember(j)
+	if added == 0 {
+		return 0, nil
+	}
+	return added, m.Save(path)
+}
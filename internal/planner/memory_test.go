@@ -0,0 +1,131 @@
+package planner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+)
+
+// ============================================================
+// ParseMemory / Format
+// ============================================================
+
+func TestParseMemory(t *testing.T) {
+	t.Parallel()
+	text := `# Project memory
+
+Some notes the user wrote.
+
+## Decisions
+- PostgreSQL over SQLite for concurrent writers
+
+## Constraints
+-   Handlers never touch the database directly
+- 
+
+## Ideas
+- not a section forge knows
+
+## conventions
+- Table names are plural snake_case
+`
+	want := &Memory{
+		Decisions:   []string{"PostgreSQL over SQLite for concurrent writers"},
+		Constraints: []string{"Handlers never touch the database directly"},
+		Conventions: []string{"Table names are plural snake_case"},
+	}
+	got := ParseMemory(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseMemory() = %+v, want %+v", got, want)
+	}
+	if again := ParseMemory(got.Format()); !reflect.DeepEqual(again, want) {
+		t.Errorf("ParseMemory(Format()) = %+v, want %+v", again, want)
+	}
+}
+
+func TestMemory_Remember(t *testing.T) {
+	t.Parallel()
+	m := &Memory{Decisions: []string{"Use PostgreSQL"}}
+	added := m.Remember(&claude.MemoryJSON{
+		Decisions:   []string{"use postgresql", "Deploy to Fly.io"},
+		Conventions: []string{" Errors wrap with %w ", ""},
+	})
+	if added != 2 {
+		t.Errorf("Remember() = %d, want 2", added)
+	}
+	want := &Memory{Decisions: []string{"Use PostgreSQL", "Deploy to Fly.io"}, Conventions: []string{"Errors wrap with %w"}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("memory = %+v, want %+v", m, want)
+	}
+	if got := m.Remember(nil); got != 0 {
+		t.Errorf("Remember(nil) = %d, want 0", got)
+	}
+}
+
+func TestMemory_PromptSection(t *testing.T) {
+	t.Parallel()
+	var empty *Memory
+	if got := empty.PromptSection(); got != "" {
+		t.Errorf("nil PromptSection() = %q, want empty", got)
+	}
+	got := (&Memory{Constraints: []string{"No CGO"}}).PromptSection()
+	if !strings.Contains(got, "PROJECT MEMORY") || !strings.Contains(got, "Constraints:\n- No CGO\n") || strings.Contains(got, "Decisions:") {
+		t.Errorf("PromptSection() =\n%s", got)
+	}
+}
+
+// ============================================================
+// RememberPlan
+// ============================================================
+
+func TestRememberPlan(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), MemoryFileName)
+
+	if added, err := RememberPlan(path, nil); added != 0 || err != nil {
+		t.Fatalf("RememberPlan(nil) = %d, %v", added, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("RememberPlan(nil) should not create the file")
+	}
+
+	if _, err := RememberPlan(path, &claude.MemoryJSON{Decisions: []string{"Use PostgreSQL"}}); err != nil {
+		t.Fatal(err)
+	}
+	added, err := RememberPlan(path, &claude.MemoryJSON{Decisions: []string{"Use PostgreSQL"}, Constraints: []string{"No CGO"}})
+	if added != 1 || err != nil {
+		t.Fatalf("second RememberPlan() = %d, %v; want 1, nil", added, err)
+	}
+	m, err := LoadMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Memory{Decisions: []string{"Use PostgreSQL"}, Constraints: []string{"No CGO"}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("memory file = %+v, want %+v", m, want)
+	}
+}
+
+func TestLoadMemory_Missing(t *testing.T) {
+	t.Parallel()
+	m, err := LoadMemory(filepath.Join(t.TempDir(), MemoryFileName))
+	if m != nil || err != nil {
+		t.Errorf("LoadMemory() = %v, %v; want nil, nil", m, err)
+	}
+}
+
+func TestRun_InjectsMemory(t *testing.T) {
+	t.Parallel()
+	mock := claude.NewMockClaude(claude.MockResponse{Text: planResponse})
+	memory := &Memory{Conventions: []string{"Commands live under cmd/"}}
+
+	if _, err := Run(context.Background(), mock, Options{Prompt: "Build a todo CLI", Memory: memory}); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mock.AssertCall(t, 0, "Send", "Conventions:\n- Commands live under cmd/\n")
+}
@@ -0,0 +1,140 @@
+package planner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// UnresolvedRefsError lists the references in a plan update that match no
+// task, each naming the task it appears in.
+type UnresolvedRefsError struct {
+	Problems []string
+}
+
+func (e *UnresolvedRefsError) Error() string {
+	return "unresolved task references: " + strings.Join(e.Problems, "; ")
+}
+
+// ResolvePlanUpdateRefs rewrites a plan update's task references as real
+// task IDs before it is validated and applied. Added tasks get the IDs
+// AddTask will give them, in update order; an id the model gave an added
+// task is a temporary name other tasks can depend on. A depends_on entry
+// is read, first match wins, as an added task's temporary ID, an existing
+// ID, an index into the update's task list ("1" or "#1"), or a loosely
+// written existing ID ("task-7" for task-007). Entries that match no task
+// are returned together as an *UnresolvedRefsError and nothing is changed.
+func ResolvePlanUpdateRefs(s *state.State, update *claude.PlanUpdateJSON) error {
+	existing := make(map[string]*state.Task, len(s.Tasks))
+	for i := range s.Tasks {
+		existing[s.Tasks[i].ID] = &s.Tasks[i]
+	}
+	var problems []string
+
+	// The ID each entry of the update ends up with
+	ids := make([]string, len(update.Tasks))
+	temp := make(map[string]int)
+	next := taskNumber(s.NextTaskID())
+	for i, t := range update.Tasks {
+		if t.Action != "add" {
+			ids[i] = t.ID
+			if _, ok := existing[t.ID]; !ok {
+				if id, ok := looseTaskID(t.ID); ok && existing[id] != nil {
+					ids[i] = id
+				}
+			}
+			continue
+		}
+		ids[i] = fmt.Sprintf("task-%03d", next)
+		next++
+		switch _, clash := existing[t.ID]; {
+		case t.ID == "":
+		case clash:
+			problems = append(problems, fmt.Sprintf("added task %q uses the ID of existing task %s; give it a temporary id such as \"new-1\"", t.Title, t.ID))
+		case temp[t.ID] > 0:
+			problems = append(problems, fmt.Sprintf("temporary id %q is given to more than one added task", t.ID))
+		default:
+			temp[t.ID] = i + 1 // 0 = none
+		}
+	}
+
+	resolved := make([][]string, len(update.Tasks))
+	for i, t := range update.Tasks {
+		for _, ref := range t.DependsOn {
+			id, ok := resolveTaskRef(ref, existing, temp, ids)
+			var problem string
+			switch {
+			case !ok:
+				problem = fmt.Sprintf("depends on %q, which is not a task ID, an index into the update or the temporary id of an added task", ref)
+			case id == ids[i]:
+				problem = fmt.Sprintf("depends on %q, which is itself", ref)
+			default:
+				resolved[i] = append(resolved[i], id)
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s %s", describeUpdateTask(t, ids[i]), problem))
+		}
+	}
+	if len(problems) > 0 {
+		return &UnresolvedRefsError{Problems: problems}
+	}
+
+	for i := range update.Tasks {
+		update.Tasks[i].ID = ids[i]
+		if update.Tasks[i].DependsOn != nil {
+			update.Tasks[i].DependsOn = resolved[i]
+		}
+	}
+	return nil
+}
+
+// resolveTaskRef returns the task ID a depends_on entry means, in the
+// order ResolvePlanUpdateRefs documents.
+func resolveTaskRef(ref string, existing map[string]*state.Task, temp map[string]int, ids []string) (string, bool) {
+	if i := temp[ref]; i > 0 {
+		return ids[i-1], true
+	}
+	if _, ok := existing[ref]; ok {
+		return ref, true
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		if n < 0 || n >= len(ids) || ids[n] == "" {
+			return "", false
+		}
+		return ids[n], true
+	}
+	if id, ok := looseTaskID(ref); ok && existing[id] != nil {
+		return id, true
+	}
+	return "", false
+}
+
+// looseTaskID reads "task-7" or "Task-007" as task-007.
+func looseTaskID(ref string) (string, bool) {
+	num, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(ref)), "task-")
+	if !ok {
+		return "", false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return "", false
+	}
+	return fmt.Sprintf("task-%03d", n), true
+}
+
+// taskNumber is the number in a "task-NNN" ID.
+func taskNumber(id string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(id, "task-"))
+	return n
+}
+
+// describeUpdateTask names an entry of a plan update in a problem.
+func describeUpdateTask(t claude.PlanUpdateTaskJSON, id string) string {
+	if t.Action == "add" {
+		return fmt.Sprintf("added task %q", t.Title)
+	}
+	return id
+}
@@ -0,0 +1,129 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestResolvePlanUpdateRefs(t *testing.T) {
+	t.Parallel()
+
+	baseTasks := []state.Task{
+		{ID: "task-001", Title: "Setup", Status: state.TaskDone},
+		{ID: "task-002", Title: "Auth", Status: state.TaskPending},
+		{ID: "task-007", Title: "API", Status: state.TaskPending},
+	}
+	type entry struct{ ID, DependsOn string } // DependsOn comma-separated
+
+	tests := []struct {
+		name     string
+		tasks    []claude.PlanUpdateTaskJSON
+		want     []entry
+		problems []string
+	}{
+		{
+			name: "temporary IDs get the next IDs in order",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "task-001", Action: "keep"},
+				{ID: "new-2", Action: "add", Title: "Cache", DependsOn: []string{"new-1"}},
+				{ID: "new-1", Action: "add", Title: "Redis", DependsOn: []string{"task-002"}},
+				{Action: "add", Title: "Docs", DependsOn: []string{"new-2", "new-1"}},
+			},
+			want: []entry{{"task-001", ""}, {"task-008", "task-009"}, {"task-009", "task-002"}, {"task-010", "task-008,task-009"}},
+		},
+		{
+			name: "loosely written IDs",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "task-7", Action: "modify", DependsOn: []string{"Task-2"}},
+				{Action: "add", Title: "Docs", DependsOn: []string{"task-7", "task-0001"}},
+			},
+			want: []entry{{"task-007", "task-002"}, {"task-008", "task-007,task-001"}},
+		},
+		{
+			name: "a plain number is an index, not a task ID",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "task-002", Action: "keep"},
+				{Action: "add", Title: "Cache"},
+				{Action: "add", Title: "Docs", DependsOn: []string{"0", "1", "task-1"}},
+			},
+			// "1" is entry 1 of the update; "task-1" is task-001
+			want: []entry{{"task-002", ""}, {"task-008", ""}, {"task-009", "task-002,task-008,task-001"}},
+		},
+		{
+			name: "a temporary ID wins over an index",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "1", Action: "add", Title: "Cache"},
+				{ID: "task-002", Action: "keep"},
+				{Action: "add", Title: "Docs", DependsOn: []string{"1", "#1"}},
+			},
+			want: []entry{{"task-008", ""}, {"task-002", ""}, {"task-009", "task-008,task-002"}},
+		},
+		{
+			name: "unknown, out of range and self references",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "task-001", Action: "keep"},
+				{ID: "task-002", Action: "modify", DependsOn: []string{"Setup", "5", "task-9"}},
+				{ID: "new-1", Action: "add", Title: "Cache", DependsOn: []string{"new-1"}},
+			},
+			problems: []string{
+				`task-002 depends on "Setup", which is not a task ID, an index into the update or the temporary id of an added task`,
+				`task-002 depends on "5", which is not a task ID, an index into the update or the temporary id of an added task`,
+				`task-002 depends on "task-9", which is not a task ID, an index into the update or the temporary id of an added task`,
+				`added task "Cache" depends on "new-1", which is itself`,
+			},
+		},
+		{
+			name: "temporary IDs that clash",
+			tasks: []claude.PlanUpdateTaskJSON{
+				{ID: "task-002", Action: "add", Title: "Auth v2"},
+				{ID: "x", Action: "add", Title: "A"},
+				{ID: "x", Action: "add", Title: "B"},
+			},
+			problems: []string{
+				`added task "Auth v2" uses the ID of existing task task-002; give it a temporary id such as "new-1"`,
+				`temporary id "x" is given to more than one added task`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &state.State{Tasks: append([]state.Task(nil), baseTasks...)}
+			update := &claude.PlanUpdateJSON{Tasks: tt.tasks}
+			err := ResolvePlanUpdateRefs(s, update)
+			if tt.problems != nil {
+				refErr, ok := err.(*UnresolvedRefsError)
+				if !ok {
+					t.Fatalf("error = %v, want *UnresolvedRefsError", err)
+				}
+				if strings.Join(refErr.Problems, "\n") != strings.Join(tt.problems, "\n") {
+					t.Errorf("problems:\n got  %q\n want %q", refErr.Problems, tt.problems)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, w := range tt.want {
+				got := update.Tasks[i]
+				if got.ID != w.ID || strings.Join(got.DependsOn, ",") != w.DependsOn {
+					t.Errorf("task %d = %s after %v, want %s after %s", i, got.ID, got.DependsOn, w.ID, w.DependsOn)
+				}
+			}
+
+			// The resolved IDs are the ones the tasks are created with
+			for _, u := range update.Tasks {
+				if u.Action != "add" {
+					continue
+				}
+				if task := s.AddTask(u.Title, u.Description, u.Complexity, u.AcceptanceCriteria, u.DependsOn); task.ID != u.ID {
+					t.Errorf("added task %q created as %s, resolved as %s", u.Title, task.ID, u.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package platform isolates OS-specific behavior: how user commands are
+// launched through a shell, which editor is opened for long-form input and
+// how a notification sound is played, and what the terminal can draw.
+package platform
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// IsWindows reports whether forge is running on Windows.
+func IsWindows() bool {
+	return runtime.GOOS == "windows"
+}
+
+// Shells lists the shells that can run test and build commands.
+var Shells = []string{"sh", "bash", "zsh", "pwsh", "cmd"}
+
+// ValidShell reports whether shell is one of Shells.
+func ValidShell(shell string) bool {
+	for _, s := range Shells {
+		if s == shell {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultShell is the shell used when none is configured.
+func DefaultShell() string {
+	if IsWindows() {
+		return "cmd"
+	}
+	return "sh"
+}
+
+// ShellCommand builds a command that runs line through shell, so pipes,
+// quotes and && behave as they would in the user's terminal. An empty
+// shell means DefaultShell. The line is passed as a single argument and
+// never re-split by forge.
+func ShellCommand(ctx context.Context, shell, line string) *exec.Cmd {
+	if shell == "" {
+		shell = DefaultShell()
+	}
+	args := shellArgs(shell, line)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	prepareShellCommand(cmd, shell, line)
+	return cmd
+}
+
+func shellArgs(shell, line string) []string {
+	switch shell {
+	case "cmd":
+		return []string{"cmd", "/S", "/C", line}
+	case "pwsh":
+		return []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", line}
+	default:
+		return []string{shell, "-c", line}
+	}
+}
+
+// EditorCommand returns a command that opens path in the user's editor.
+// $EDITOR and $VISUAL may include arguments (e.g. "code --wait").
+func EditorCommand(path string) *exec.Cmd {
+	args := append(editorArgs(runtime.GOOS, os.Getenv, exec.LookPath), path)
+	return exec.Command(args[0], args[1:]...)
+}
+
+// editorArgs resolves the editor command line: $EDITOR, then $VISUAL, then
+// the first installed fallback for the platform (notepad always exists on
+// Windows).
+func editorArgs(goos string, getenv func(string) string, lookPath func(string) (string, error)) []string {
+	for _, env := range []string{"EDITOR", "VISUAL"} {
+		editor := strings.TrimSpace(getenv(env))
+		if editor == "" {
+			continue
+		}
+		// A bare path may contain spaces ("C:\Program Files\...\code.exe").
+		if _, err := lookPath(editor); err == nil {
+			return []string{editor}
+		}
+		return strings.Fields(editor)
+	}
+
+	fallbacks := []string{"nano", "vi"}
+	if goos == "windows" {
+		fallbacks = []string{"notepad"}
+	}
+	for _, name := range fallbacks {
+		if _, err := lookPath(name); err == nil {
+			return []string{name}
+		}
+	}
+	return []string{fallbacks[len(fallbacks)-1]}
+}
+
+// Alerts lists the ways forge can get attention when a run waits for a
+// human: nothing, the terminal bell, or a notification sound.
+var Alerts = []string{"off", "bell", "sound"}
+
+// ValidAlert reports whether alert is one of Alerts (empty means off).
+func ValidAlert(alert string) bool {
+	return alert == "" || slices.Contains(Alerts, alert)
+}
+
+// SoundCommand returns a command that plays a short notification sound,
+// or nil when no player forge knows is installed.
+func SoundCommand() *exec.Cmd {
+	args := soundArgs(runtime.GOOS, exec.LookPath, fileExists)
+	if args == nil {
+		return nil
+	}
+	return exec.Command(args[0], args[1:]...)
+}
+
+// soundArgs picks the platform's sound player: afplay on macOS, a console
+// beep on Windows, and paplay or canberra-gtk-play elsewhere.
+func soundArgs(goos string, lookPath func(string) (string, error), exists func(string) bool) []string {
+	switch goos {
+	case "darwin":
+		return []string{"afplay", "/System/Library/Sounds/Glass.aiff"}
+	case "windows":
+		return []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "[console]::beep(880,300)"}
+	}
+	const freedesktop = "/usr/share/sounds/freedesktop/stereo/complete.oga"
+	if _, err := lookPath("paplay"); err == nil && exists(freedesktop) {
+		return []string{"paplay", freedesktop}
+	}
+	if _, err := lookPath("canberra-gtk-play"); err == nil {
+		return []string{"canberra-gtk-play", "--id=bell"}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// UTF8Terminal reports whether the terminal can be expected to draw UTF-8
+// block characters and emoji. The first of LC_ALL, LC_CTYPE and LANG that
+// is set must name a UTF-8 charset; the C/POSIX locale is plain ASCII
+// when there is a terminal (TERM is set). With no locale at all nothing
+// is known and UTF-8 is assumed. The Linux console and vt100-style
+// terminals never qualify: their fonts lack the glyphs.
+func UTF8Terminal() bool {
+	return utf8Terminal(os.Getenv)
+}
+
+func utf8Terminal(getenv func(string) string) bool {
+	term := getenv("TERM")
+	if term == "linux" || strings.HasPrefix(term, "vt1") || strings.HasPrefix(term, "vt2") {
+		return false
+	}
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := getenv(env)
+		if locale == "" {
+			continue
+		}
+		if locale == "C" || locale == "POSIX" {
+			return term == ""
+		}
+		charset := strings.ToLower(locale)
+		return strings.Contains(charset, "utf-8") || strings.Contains(charset, "utf8")
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellArgs(t *testing.T) {
+	t.Parallel()
+	line := `go test ./... | grep -v "no test files"`
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"sh", []string{"sh", "-c", line}},
+		{"bash", []string{"bash", "-c", line}},
+		{"zsh", []string{"zsh", "-c", line}},
+		{"pwsh", []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", line}},
+		{"cmd", []string{"cmd", "/S", "/C", line}},
+	}
+	for _, tt := range tests {
+		if got := shellArgs(tt.shell, line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("shellArgs(%q) = %v, want %v", tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestValidShell(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"sh", "bash", "zsh", "pwsh", "cmd"} {
+		if !ValidShell(s) {
+			t.Errorf("ValidShell(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"", "fish", "Bash", "/bin/bash"} {
+		if ValidShell(s) {
+			t.Errorf("ValidShell(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestShellCommand_RunsThroughShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	t.Parallel()
+
+	out, err := ShellCommand(context.Background(), "", `echo "a  b" | tr a-z A-Z`).CombinedOutput()
+	if err != nil {
+		t.Fatalf("ShellCommand: %v\n%s", err, out)
+	}
+	if strings.TrimSpace(string(out)) != "A  B" {
+		t.Errorf("output = %q, want %q", out, "A  B")
+	}
+}
+
+// installed fakes exec.LookPath for a machine with only names on PATH.
+func installed(names ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, n := range names {
+			if n == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestEditorArgs(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+
+	tests := []struct {
+		name     string
+		goos     string
+		vars     map[string]string
+		lookPath func(string) (string, error)
+		want     []string
+	}{
+		{"EDITOR wins", "linux", map[string]string{"EDITOR": "vim", "VISUAL": "emacs"}, installed("vim"), []string{"vim"}},
+		{"VISUAL fallback", "linux", map[string]string{"VISUAL": "emacs"}, installed("emacs"), []string{"emacs"}},
+		{"EDITOR with args", "linux", map[string]string{"EDITOR": "code --wait"}, installed("code"), []string{"code", "--wait"}},
+		{"path with spaces", "windows", map[string]string{"EDITOR": `C:\Program Files\Editor\ed.exe`}, installed(`C:\Program Files\Editor\ed.exe`), []string{`C:\Program Files\Editor\ed.exe`}},
+		{"unix prefers nano", "linux", nil, installed("nano", "vi"), []string{"nano"}},
+		{"unix falls back to vi", "linux", nil, installed("vi"), []string{"vi"}},
+		{"windows uses notepad", "windows", nil, installed("nano"), []string{"notepad"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := editorArgs(tt.goos, env(tt.vars), tt.lookPath)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("editorArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSoundArgs(t *testing.T) {
+	t.Parallel()
+	const oga = "/usr/share/sounds/freedesktop/stereo/complete.oga"
+	hasOga := func(p string) bool { return p == oga }
+	none := func(string) bool { return false }
+	tests := []struct {
+		name     string
+		goos     string
+		lookPath func(string) (string, error)
+		exists   func(string) bool
+		want     []string
+	}{
+		{"macOS", "darwin", installed(), none, []string{"afplay", "/System/Library/Sounds/Glass.aiff"}},
+		{"windows", "windows", installed(), none, []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "[console]::beep(880,300)"}},
+		{"paplay with theme", "linux", installed("paplay", "canberra-gtk-play"), hasOga, []string{"paplay", oga}},
+		{"paplay without theme", "linux", installed("paplay", "canberra-gtk-play"), none, []string{"canberra-gtk-play", "--id=bell"}},
+		{"no player", "linux", installed(), hasOga, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := soundArgs(tt.goos, tt.lookPath, tt.exists); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("soundArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidAlert(t *testing.T) {
+	t.Parallel()
+	for _, a := range []string{"", "off", "bell", "sound"} {
+		if !ValidAlert(a) {
+			t.Errorf("ValidAlert(%q) = false, want true", a)
+		}
+	}
+	if ValidAlert("siren") {
+		t.Error(`ValidAlert("siren") = true, want false`)
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	t.Parallel()
+	if !ProcessAlive(os.Getpid()) {
+		t.Error("ProcessAlive(self) = false")
+	}
+	if ProcessAlive(0) || ProcessAlive(-1) {
+		t.Error("ProcessAlive should be false for non-positive PIDs")
+	}
+}
+
+func TestUTF8Terminal(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"nothing set", nil, true},
+		{"utf-8 lang", map[string]string{"LANG": "en_US.UTF-8", "TERM": "xterm-256color"}, true},
+		{"utf8 spelling", map[string]string{"LANG": "de_DE.utf8"}, true},
+		{"C locale in a terminal", map[string]string{"LANG": "C", "TERM": "xterm"}, false},
+		{"POSIX locale without a terminal", map[string]string{"LC_ALL": "POSIX"}, true},
+		{"latin-1 lang", map[string]string{"LANG": "en_US.ISO-8859-1"}, false},
+		{"LC_ALL wins", map[string]string{"LC_ALL": "en_US.ISO-8859-1", "LANG": "en_US.UTF-8"}, false},
+		{"LC_CTYPE before LANG", map[string]string{"LC_CTYPE": "en_US.UTF-8", "LANG": "en_US"}, true},
+		{"linux console", map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"}, false},
+		{"vt100", map[string]string{"TERM": "vt100"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := utf8Terminal(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("utf8Terminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
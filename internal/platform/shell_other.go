@@ -0,0 +1,9 @@
+//go:build !windows
+
+package platform
+
+import "os/exec"
+
+// prepareShellCommand is a no-op outside Windows: argv is passed to the
+// shell verbatim.
+func prepareShellCommand(cmd *exec.Cmd, shell, line string) {}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package platform
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// prepareShellCommand hands cmd.exe the raw command line. Go's default
+// argument quoting escapes embedded quotes with backslashes, which cmd
+// does not understand, so "echo "a b"" would otherwise be mangled.
+func prepareShellCommand(cmd *exec.Cmd, shell, line string) {
+	if shell == "cmd" {
+		cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + line + `"`}
+	}
+}
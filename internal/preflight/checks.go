@@ -2,9 +2,12 @@ package preflight
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/manasm11/forge/internal/platform"
 )
 
 type CheckResult struct {
@@ -16,6 +19,37 @@ type CheckResult struct {
 
 var requiredTools = []string{"claude", "gh", "git"}
 
+// MinFreeDisk is the free space forge wants before starting, and before
+// each task, so a task can't fill the disk halfway through a build.
+const MinFreeDisk uint64 = 1 << 30
+
+// CheckDiskSpace reports whether the filesystem holding path has at least
+// min bytes free. Found is false when it doesn't or can't be checked.
+func CheckDiskSpace(path string, min uint64) CheckResult {
+	free, err := platform.FreeDiskSpace(path)
+	if err != nil {
+		return CheckResult{Name: "disk space", Error: err.Error()}
+	}
+	r := CheckResult{Name: "disk space", Found: free >= min, Version: FormatBytes(free) + " free"}
+	if !r.Found {
+		r.Error = fmt.Sprintf("only %s free, need %s", FormatBytes(free), FormatBytes(min))
+	}
+	return r
+}
+
+// FormatBytes renders a size as e.g. "512 MB" or "1.5 GB".
+func FormatBytes(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
 // RunAll checks for required external tools and returns results.
 // Required: claude, gh, git
 func RunAll() []CheckResult {
@@ -1,6 +1,7 @@
 package preflight
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -96,3 +97,36 @@ func TestCheckResult_FieldsPopulated(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckDiskSpace(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	if r := CheckDiskSpace(dir, 0); !r.Found || !strings.HasSuffix(r.Version, " free") {
+		t.Errorf("CheckDiskSpace(0) = %+v, want found with free space", r)
+	}
+	if r := CheckDiskSpace(dir, 1<<62); r.Found || !strings.Contains(r.Error, "need") {
+		t.Errorf("CheckDiskSpace(4 EB) = %+v, want not found", r)
+	}
+	if r := CheckDiskSpace(dir+"/missing", 0); r.Found || r.Error == "" {
+		t.Errorf("CheckDiskSpace(missing dir) = %+v, want an error", r)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{512, "512 B"},
+		{2 << 10, "2 KB"},
+		{300 << 20, "300 MB"},
+		{3 << 29, "1.5 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
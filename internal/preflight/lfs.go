@@ -0,0 +1,107 @@
+package preflight
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LFSPatterns returns the .gitattributes patterns in root that route files
+// through Git LFS (filter=lfs).
+func LFSPatterns(root string) []string {
+	f, err := os.Open(filepath.Join(root, ".gitattributes"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+// UsesLFS reports whether root tracks any files with Git LFS.
+func UsesLFS(root string) bool {
+	return len(LFSPatterns(root)) > 0
+}
+
+// LFSTracked reports whether file (slash-separated, relative to the
+// repository root) matches one of the LFS patterns. It covers the forms
+// `git lfs track` writes: "*.psd", "assets/*.bin", "media/**".
+func LFSTracked(patterns []string, file string) bool {
+	for _, p := range patterns {
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "/"), "**/")
+		if dir, ok := strings.CutSuffix(p, "/**"); ok {
+			if strings.HasPrefix(file, dir+"/") {
+				return true
+			}
+			continue
+		}
+		target := file
+		if !strings.Contains(p, "/") {
+			target = path.Base(file)
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureLFS checks, for a repository that uses Git LFS, that git-lfs is
+// installed and that the repository's LFS hooks are in place, installing
+// them when they aren't: without the pre-push hook a push uploads pointer
+// files but not the objects they point to.
+func EnsureLFS(root string) CheckResult {
+	r := check("git-lfs")
+	r.Name = "git-lfs"
+	if !r.Found {
+		r.Error = "the repository tracks files with Git LFS but git-lfs is not installed: " + r.Error
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := git(ctx, root, "rev-parse", "--git-path", "hooks/pre-push")
+	if err != nil {
+		r.Found, r.Error = false, "locating git hooks: "+err.Error()
+		return r
+	}
+	hook := strings.TrimSpace(out)
+	if !filepath.IsAbs(hook) {
+		hook = filepath.Join(root, hook)
+	}
+	if data, err := os.ReadFile(hook); err == nil && strings.Contains(string(data), "git lfs") {
+		return r
+	}
+	if out, err := git(ctx, root, "lfs", "install", "--local"); err != nil {
+		r.Found, r.Error = false, "installing LFS hooks: "+strings.TrimSpace(out)
+		return r
+	}
+	r.Version += ", hooks installed"
+	return r
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
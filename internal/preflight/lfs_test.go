@@ -0,0 +1,42 @@
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLFSPatterns(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	attrs := "# binaries\n*.psd filter=lfs diff=lfs merge=lfs -text\n*.go text eol=lf\nmedia/** filter=lfs diff=lfs merge=lfs -text\n"
+	if err := os.WriteFile(filepath.Join(root, ".gitattributes"), []byte(attrs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := LFSPatterns(root), []string{"*.psd", "media/**"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LFSPatterns() = %v, want %v", got, want)
+	}
+	if !UsesLFS(root) || UsesLFS(t.TempDir()) {
+		t.Error("UsesLFS should follow the LFS patterns")
+	}
+}
+
+func TestLFSTracked(t *testing.T) {
+	t.Parallel()
+	patterns := []string{"*.psd", "/assets/*.bin", "media/**", "**/*.mp4"}
+	tests := map[string]bool{
+		"design/logo.psd":       true,
+		"assets/model.bin":      true,
+		"assets/nested/x.bin":   false,
+		"media/video/intro.mov": true,
+		"clips/demo.mp4":        true,
+		"mediafile.png":         false,
+		"main.go":               false,
+	}
+	for file, want := range tests {
+		if got := LFSTracked(patterns, file); got != want {
+			t.Errorf("LFSTracked(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
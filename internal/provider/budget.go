@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Budget caps what a single execution run may spend. Zero fields mean no
+// limit on that dimension. Persisted in state.Settings.
+type Budget struct {
+	MaxUSD    float64 `json:"max_usd,omitempty"`
+	MaxTokens int     `json:"max_tokens,omitempty"`
+}
+
+// Usage is the spend accumulated against a Budget.
+type Usage struct {
+	Tokens  int
+	CostUSD float64
+}
+
+// Unlimited reports whether no cap is set.
+func (b Budget) Unlimited() bool {
+	return b.MaxUSD <= 0 && b.MaxTokens <= 0
+}
+
+// Exceeded reports whether usage has reached either cap, with a
+// human-readable reason.
+func (b Budget) Exceeded(u Usage) (bool, string) {
+	if b.MaxUSD > 0 && u.CostUSD >= b.MaxUSD {
+		return true, fmt.Sprintf("spent $%.2f of $%.2f budget", u.CostUSD, b.MaxUSD)
+	}
+	if b.MaxTokens > 0 && u.Tokens >= b.MaxTokens {
+		return true, fmt.Sprintf("used %d of %d token budget", u.Tokens, b.MaxTokens)
+	}
+	return false, ""
+}
+
+// String formats the budget in the syntax ParseBudget accepts.
+func (b Budget) String() string {
+	var parts []string
+	if b.MaxUSD > 0 {
+		parts = append(parts, "$"+strconv.FormatFloat(b.MaxUSD, 'f', -1, 64))
+	}
+	if b.MaxTokens > 0 {
+		parts = append(parts, strconv.Itoa(b.MaxTokens)+" tokens")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseBudget parses a comma-separated list of caps: dollar amounts
+// ("$5", "5 usd") and token counts ("200000 tokens", "200k", "2m tokens").
+// An empty string is an unlimited budget.
+func ParseBudget(value string) (Budget, error) {
+	var b Budget
+	for _, part := range strings.Split(value, ",") {
+		p := strings.ToLower(strings.TrimSpace(part))
+		if p == "" {
+			continue
+		}
+
+		if usd, ok := strings.CutPrefix(p, "$"); ok || strings.HasSuffix(p, "usd") {
+			if !ok {
+				usd = strings.TrimSpace(strings.TrimSuffix(p, "usd"))
+			}
+			n, err := strconv.ParseFloat(usd, 64)
+			if err != nil || n <= 0 {
+				return Budget{}, fmt.Errorf("invalid dollar budget %q", part)
+			}
+			b.MaxUSD = n
+			continue
+		}
+
+		p, hasUnit := strings.CutSuffix(p, "tokens")
+		p = strings.TrimSpace(p)
+		mult := 0
+		switch {
+		case strings.HasSuffix(p, "k"):
+			mult, p = 1_000, strings.TrimSuffix(p, "k")
+		case strings.HasSuffix(p, "m"):
+			mult, p = 1_000_000, strings.TrimSuffix(p, "m")
+		case hasUnit:
+			mult = 1
+		default:
+			return Budget{}, fmt.Errorf("budget %q needs a unit: $ for dollars or tokens", part)
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || n <= 0 {
+			return Budget{}, fmt.Errorf("invalid token budget %q", part)
+		}
+		b.MaxTokens = int(n * float64(mult))
+	}
+	return b, nil
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// ParseBudget
+// ============================================================
+
+func TestParseBudget(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    Budget
+		wantErr bool
+	}{
+		{name: "empty is unlimited", input: "", want: Budget{}},
+		{name: "dollars", input: "$5", want: Budget{MaxUSD: 5}},
+		{name: "dollars with fraction", input: "$2.50", want: Budget{MaxUSD: 2.5}},
+		{name: "usd suffix", input: "10 USD", want: Budget{MaxUSD: 10}},
+		{name: "tokens", input: "5000 tokens", want: Budget{MaxTokens: 5000}},
+		{name: "thousands", input: "200k", want: Budget{MaxTokens: 200_000}},
+		{name: "millions with unit", input: "2m tokens", want: Budget{MaxTokens: 2_000_000}},
+		{name: "both caps", input: "$5, 1.5m tokens", want: Budget{MaxUSD: 5, MaxTokens: 1_500_000}},
+		{name: "bare number", input: "5", wantErr: true},
+		{name: "negative dollars", input: "$-1", wantErr: true},
+		{name: "garbage tokens", input: "lots tokens", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseBudget(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBudget(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBudget(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBudget_StringRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, b := range []Budget{{}, {MaxUSD: 2.5}, {MaxTokens: 300_000}, {MaxUSD: 5, MaxTokens: 1_000}} {
+		got, err := ParseBudget(b.String())
+		if err != nil {
+			t.Fatalf("ParseBudget(%q) error: %v", b.String(), err)
+		}
+		if got != b {
+			t.Errorf("round trip of %+v = %+v", b, got)
+		}
+	}
+}
+
+// ============================================================
+// Exceeded
+// ============================================================
+
+func TestBudget_Exceeded(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		budget     Budget
+		usage      Usage
+		want       bool
+		wantReason string
+	}{
+		{name: "unlimited", budget: Budget{}, usage: Usage{Tokens: 1e9, CostUSD: 1e3}, want: false},
+		{name: "under dollar cap", budget: Budget{MaxUSD: 5}, usage: Usage{CostUSD: 4.99}, want: false},
+		{name: "at dollar cap", budget: Budget{MaxUSD: 5}, usage: Usage{CostUSD: 5}, want: true, wantReason: "$5.00 of $5.00"},
+		{name: "over token cap", budget: Budget{MaxTokens: 1000}, usage: Usage{Tokens: 1200}, want: true, wantReason: "1200 of 1000 token"},
+		{name: "tokens ignored without token cap", budget: Budget{MaxUSD: 5}, usage: Usage{Tokens: 1e9}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, reason := tt.budget.Exceeded(tt.usage)
+			if got != tt.want {
+				t.Errorf("Exceeded() = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
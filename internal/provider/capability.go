@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capabilities is what a model supports, as far as forge can tell.
+type Capabilities struct {
+	Known         bool // false when the provider could not say
+	Tools         bool // tool use, which the claude CLI needs to edit files and run commands
+	JSON          bool // JSON output, which planning and the critic rely on
+	ContextTokens int  // context window; 0 = unknown
+}
+
+// Requirements is what a run needs from its model.
+type Requirements struct {
+	Tools        bool
+	JSON         bool
+	PromptTokens int // the largest prompt forge will send, estimated
+}
+
+// CLIOverheadTokens approximates the claude CLI's own system prompt and tool
+// definitions, which go with every prompt.
+const CLIOverheadTokens = 16_000
+
+// anthropicContextTokens is the context window of the Claude models.
+const anthropicContextTokens = 200_000
+
+// maxSuggestionChecks bounds how many installed models SuggestModel asks
+// Ollama about.
+const maxSuggestionChecks = 10
+
+// Capability features a CapabilityProblem can be about.
+const (
+	FeatureTools   = "tools"
+	FeatureJSON    = "json"
+	FeatureContext = "context"
+	FeatureUnknown = "unknown"
+)
+
+// CapabilityProblem is one way a model falls short of the requirements.
+type CapabilityProblem struct {
+	Feature  string
+	Message  string
+	Blocking bool // the run would fail, not just go badly
+}
+
+// ModelCapabilities looks up what cfg.Model supports. Claude models are
+// known; Ollama is asked with /api/show.
+func ModelCapabilities(ctx context.Context, cfg Config) (Capabilities, error) {
+	switch cfg.Type {
+	case ProviderAnthropic:
+		return Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: anthropicContextTokens}, nil
+	case ProviderOllama:
+		cfg = cfg.WithEnv()
+		if cfg.OllamaURL == "" {
+			cfg.OllamaURL = DefaultOllamaURL()
+		}
+		return showOllamaModel(ctx, cfg, cfg.Model)
+	}
+	return Capabilities{}, fmt.Errorf("unknown provider type: %q", cfg.Type)
+}
+
+// ollamaShowResponse is the part of /api/show forge reads. Older Ollama
+// servers don't report capabilities.
+type ollamaShowResponse struct {
+	Capabilities []string       `json:"capabilities"`
+	ModelInfo    map[string]any `json:"model_info"`
+	Parameters   string         `json:"parameters"`
+}
+
+func showOllamaModel(ctx context.Context, cfg Config, model string) (Capabilities, error) {
+	client, err := cfg.HTTPClient(5 * time.Second)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	body, _ := json.Marshal(map[string]string{"model": model})
+	req, err := cfg.newOllamaRequest(ctx, http.MethodPost, "/api/show", bytes.NewReader(body))
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Capabilities{}, fmt.Errorf("model %q is not installed", model)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Capabilities{}, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	var show ollamaShowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&show); err != nil {
+		return Capabilities{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return show.capabilities(), nil
+}
+
+// capabilities reads the response. Every model that completes text can be
+// asked for JSON through Ollama's format option, so JSON follows
+// "completion". A num_ctx parameter in the Modelfile limits the context
+// below what the model was trained for.
+func (s ollamaShowResponse) capabilities() Capabilities {
+	caps := Capabilities{
+		Known: s.Capabilities != nil,
+		Tools: slices.Contains(s.Capabilities, "tools"),
+		JSON:  slices.Contains(s.Capabilities, "completion"),
+	}
+	for key, v := range s.ModelInfo {
+		if n, ok := v.(float64); ok && strings.HasSuffix(key, ".context_length") {
+			caps.ContextTokens = int(n)
+		}
+	}
+	for _, line := range strings.Split(s.Parameters, "\n") {
+		if f := strings.Fields(line); len(f) == 2 && f[0] == "num_ctx" {
+			if n, err := strconv.Atoi(f[1]); err == nil && n > 0 {
+				caps.ContextTokens = n
+			}
+		}
+	}
+	return caps
+}
+
+// CheckCapabilities compares what a model supports with what the run
+// needs. A prompt that doesn't fit, or no tool use, blocks the run; a
+// prompt that leaves little room for the files Claude reads only warns.
+func CheckCapabilities(model string, caps Capabilities, req Requirements) []CapabilityProblem {
+	if !caps.Known {
+		return []CapabilityProblem{{Feature: FeatureUnknown,
+			Message: fmt.Sprintf("could not tell what %s supports — check it handles tool use and JSON", model)}}
+	}
+	var problems []CapabilityProblem
+	if req.Tools && !caps.Tools {
+		problems = append(problems, CapabilityProblem{Feature: FeatureTools, Blocking: true,
+			Message: fmt.Sprintf("%s does not support tool use, so it can't edit files or run commands", model)})
+	}
+	if req.JSON && !caps.JSON {
+		problems = append(problems, CapabilityProblem{Feature: FeatureJSON,
+			Message: fmt.Sprintf("%s can't be asked for JSON output, which planning and review rely on", model)})
+	}
+	if need := req.PromptTokens + CLIOverheadTokens; caps.ContextTokens > 0 {
+		switch {
+		case need > caps.ContextTokens:
+			problems = append(problems, CapabilityProblem{Feature: FeatureContext, Blocking: true,
+				Message: fmt.Sprintf("%s has a %d-token context but the largest task prompt needs ~%d", model, caps.ContextTokens, need)})
+		case need > caps.ContextTokens/2:
+			problems = append(problems, CapabilityProblem{Feature: FeatureContext,
+				Message: fmt.Sprintf("the largest task prompt (~%d tokens) fills over half of %s's %d-token context", need, model, caps.ContextTokens)})
+		}
+	}
+	return problems
+}
+
+// SuggestModel returns an installed Ollama model that meets the
+// requirements, trying the recommended ones first, or "" when none does.
+func SuggestModel(ctx context.Context, cfg Config, req Requirements) string {
+	if cfg.Type != ProviderOllama {
+		return ""
+	}
+	cfg = cfg.WithEnv()
+	if cfg.OllamaURL == "" {
+		cfg.OllamaURL = DefaultOllamaURL()
+	}
+	models, err := listOllamaModels(ctx, cfg)
+	if err != nil {
+		return ""
+	}
+	recommended := RecommendedModels(ProviderOllama)
+	rank := func(m OllamaModel) int {
+		for i, name := range recommended {
+			if ModelInList(name, []OllamaModel{m}) {
+				return i
+			}
+		}
+		return len(recommended)
+	}
+	slices.SortStableFunc(models, func(a, b OllamaModel) int { return rank(a) - rank(b) })
+	for i, m := range models {
+		if i == maxSuggestionChecks {
+			break
+		}
+		name := FormatModelName(m.Name)
+		if name == FormatModelName(cfg.Model) {
+			continue
+		}
+		caps, err := showOllamaModel(ctx, cfg, m.Name)
+		if err == nil && caps.Known && len(CheckCapabilities(name, caps, req)) == 0 {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ollamaShowServer serves /api/tags and /api/show for the given models.
+func ollamaShowServer(t *testing.T, models map[string]ollamaShowResponse) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			var list []map[string]any
+			for name := range models {
+				list = append(list, map[string]any{"name": name})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"models": list})
+		case "/api/show":
+			var req struct{ Model string }
+			if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			show, ok := models[req.Model]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(show)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestModelCapabilities(t *testing.T) {
+	t.Parallel()
+	srv := ollamaShowServer(t, map[string]ollamaShowResponse{
+		"qwen3-coder:latest": {Capabilities: []string{"completion", "tools"},
+			ModelInfo: map[string]any{"general.architecture": "qwen3", "qwen3.context_length": 262144.0}},
+		"tiny:latest": {Capabilities: []string{"completion"},
+			ModelInfo: map[string]any{"llama.context_length": 131072.0}, Parameters: "stop \"<eos>\"\nnum_ctx 4096"},
+		"old:latest": {ModelInfo: map[string]any{"llama.context_length": 8192.0}},
+	})
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    Capabilities
+		wantErr string
+	}{
+		{name: "anthropic", cfg: Config{Type: ProviderAnthropic, Model: "sonnet"},
+			want: Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: anthropicContextTokens}},
+		{name: "ollama with tools", cfg: Config{Type: ProviderOllama, Model: "qwen3-coder:latest", OllamaURL: srv.URL},
+			want: Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: 262144}},
+		{name: "num_ctx limits the context", cfg: Config{Type: ProviderOllama, Model: "tiny:latest", OllamaURL: srv.URL},
+			want: Capabilities{Known: true, JSON: true, ContextTokens: 4096}},
+		{name: "server without capabilities", cfg: Config{Type: ProviderOllama, Model: "old:latest", OllamaURL: srv.URL},
+			want: Capabilities{ContextTokens: 8192}},
+		{name: "model not installed", cfg: Config{Type: ProviderOllama, Model: "missing", OllamaURL: srv.URL},
+			wantErr: "not installed"},
+		{name: "unknown provider", cfg: Config{Type: "other"}, wantErr: "unknown provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ModelCapabilities(context.Background(), tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	t.Parallel()
+	all := Requirements{Tools: true, JSON: true, PromptTokens: 4_000}
+	tests := []struct {
+		name string
+		caps Capabilities
+		req  Requirements
+		want []string // features, blocking ones suffixed with "!"
+	}{
+		{name: "claude", caps: Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: 200_000}, req: all},
+		{name: "unknown context is not a problem", caps: Capabilities{Known: true, Tools: true, JSON: true}, req: all},
+		{name: "unknown capabilities warn", caps: Capabilities{}, req: all, want: []string{FeatureUnknown}},
+		{name: "no tools blocks, no JSON warns", caps: Capabilities{Known: true, ContextTokens: 128_000}, req: all,
+			want: []string{FeatureTools + "!", FeatureJSON}},
+		{name: "prompt doesn't fit", caps: Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: 8192}, req: all,
+			want: []string{FeatureContext + "!"}},
+		{name: "prompt fills over half", caps: Capabilities{Known: true, Tools: true, JSON: true, ContextTokens: 32_768}, req: all,
+			want: []string{FeatureContext}},
+		{name: "tools not needed", caps: Capabilities{Known: true, JSON: true}, req: Requirements{JSON: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, p := range CheckCapabilities("m", tt.caps, tt.req) {
+				f := p.Feature
+				if p.Blocking {
+					f += "!"
+				}
+				got = append(got, f)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestModel(t *testing.T) {
+	t.Parallel()
+	capable := ollamaShowResponse{Capabilities: []string{"completion", "tools"},
+		ModelInfo: map[string]any{"llama.context_length": 131072.0}}
+	srv := ollamaShowServer(t, map[string]ollamaShowResponse{
+		"tiny:latest":        {Capabilities: []string{"completion"}},
+		"mistral:latest":     capable,
+		"qwen3-coder:latest": capable,
+	})
+	req := Requirements{Tools: true, JSON: true, PromptTokens: 2_000}
+
+	cfg := Config{Type: ProviderOllama, Model: "tiny", OllamaURL: srv.URL}
+	if got := SuggestModel(context.Background(), cfg, req); got != "qwen3-coder" {
+		t.Errorf("SuggestModel = %q, want the recommended qwen3-coder", got)
+	}
+	cfg.Model = "qwen3-coder"
+	if got := SuggestModel(context.Background(), cfg, req); got != "mistral" {
+		t.Errorf("SuggestModel = %q, want mistral (not the current model)", got)
+	}
+	if got := SuggestModel(context.Background(), Config{Type: ProviderAnthropic, Model: "sonnet"}, req); got != "" {
+		t.Errorf("SuggestModel(anthropic) = %q, want none", got)
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultEmbeddingModel is the Ollama model the code search index uses
+// unless Settings.EmbeddingModel names another.
+const DefaultEmbeddingModel = "nomic-embed-text"
+
+// EmbedOllama returns one embedding per input from the Ollama server in
+// cfg (POST /api/embed), computed with model.
+func EmbedOllama(ctx context.Context, cfg Config, model string, inputs []string) ([][]float32, error) {
+	cfg = cfg.WithEnv()
+	if cfg.OllamaURL == "" {
+		cfg.OllamaURL = DefaultOllamaURL()
+	}
+	client, err := cfg.HTTPClient(2 * time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]any{"model": model, "input": inputs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := cfg.newOllamaRequest(ctx, http.MethodPost, "/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding with %s: unexpected status: %d", model, resp.StatusCode)
+	}
+
+	var out struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(out.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("embedding with %s: got %d embeddings for %d inputs", model, len(out.Embeddings), len(inputs))
+	}
+	return out.Embeddings, nil
+}
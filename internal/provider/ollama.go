@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -26,16 +28,28 @@ type ollamaModelJSON struct {
 // If url is empty, DefaultOllamaURL() is used.
 // The context controls the overall timeout.
 func DetectOllama(ctx context.Context, url string) OllamaStatus {
-	if url == "" {
-		url = DefaultOllamaURL()
+	return CheckOllama(ctx, Config{Type: ProviderOllama, OllamaURL: url})
+}
+
+// CheckOllama is DetectOllama for a configured server, with its auth and
+// TLS options.
+func CheckOllama(ctx context.Context, cfg Config) OllamaStatus {
+	cfg = cfg.WithEnv()
+	if cfg.OllamaURL == "" {
+		cfg.OllamaURL = DefaultOllamaURL()
 	}
+	url := cfg.OllamaURL
 
 	status := OllamaStatus{URL: url}
 	start := time.Now()
 
 	// 1. Health check
-	client := &http.Client{Timeout: 3 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/api/version", nil)
+	client, err := cfg.HTTPClient(3 * time.Second)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	req, err := cfg.newOllamaRequest(ctx, http.MethodGet, "/api/version", nil)
 	if err != nil {
 		status.Error = fmt.Sprintf("failed to create request: %v", err)
 		return status
@@ -49,6 +63,7 @@ func DetectOllama(ctx context.Context, url string) OllamaStatus {
 	}
 	defer resp.Body.Close()
 
+	status.HTTPCode = resp.StatusCode
 	if resp.StatusCode != http.StatusOK {
 		status.Error = fmt.Sprintf("unhealthy response: HTTP %d", resp.StatusCode)
 		return status
@@ -63,7 +78,7 @@ func DetectOllama(ctx context.Context, url string) OllamaStatus {
 	status.Available = true
 
 	// 2. List models (best-effort — don't fail the overall detection)
-	models, err := ListOllamaModels(ctx, url)
+	models, err := listOllamaModels(ctx, cfg)
 	if err == nil {
 		status.Models = models
 	}
@@ -72,14 +87,100 @@ func DetectOllama(ctx context.Context, url string) OllamaStatus {
 	return status
 }
 
+// OllamaProblem classifies why requests to Ollama fail.
+type OllamaProblem string
+
+const (
+	OllamaOK            OllamaProblem = ""
+	OllamaBadConfig     OllamaProblem = "bad_config"      // auth or TLS settings can't be used
+	OllamaUnreachable   OllamaProblem = "unreachable"     // no HTTP response: daemon down or wrong URL
+	OllamaBadStatus     OllamaProblem = "bad_status"      // the server answered, but not with 200
+	OllamaNoModelList   OllamaProblem = "no_model_list"   // healthy, but /api/tags failed
+	OllamaModelNotFound OllamaProblem = "model_not_found" // healthy, but the model isn't installed
+)
+
+// OllamaDiagnosis says what is wrong with an Ollama setup.
+type OllamaDiagnosis struct {
+	URL      string
+	Model    string
+	Problem  OllamaProblem
+	HTTPCode int    // 0 = no response
+	Detail   string // the underlying error
+	Models   []OllamaModel
+}
+
+// DiagnoseOllama checks the server at cfg.OllamaURL and that cfg.Model is
+// installed on it, to explain a failed request.
+func DiagnoseOllama(ctx context.Context, cfg Config) OllamaDiagnosis {
+	cfg = cfg.WithEnv()
+	status := CheckOllama(ctx, cfg)
+	d := OllamaDiagnosis{URL: status.URL, Model: cfg.Model, HTTPCode: status.HTTPCode, Detail: status.Error, Models: status.Models}
+	_, authErr := cfg.AuthHeader()
+	_, tlsErr := cfg.HTTPClient(0)
+	switch {
+	case authErr != nil || tlsErr != nil:
+		d.Problem, d.Detail = OllamaBadConfig, errors.Join(authErr, tlsErr).Error()
+	case !status.Available && status.HTTPCode == 0:
+		d.Problem = OllamaUnreachable
+	case !status.Available:
+		d.Problem = OllamaBadStatus
+	case ModelInList(cfg.Model, status.Models):
+		// healthy
+	case len(status.Models) == 0:
+		// DetectOllama drops a failed listing; find out why
+		if _, err := listOllamaModels(ctx, cfg); err != nil {
+			d.Problem, d.Detail = OllamaNoModelList, err.Error()
+		} else {
+			d.Problem = OllamaModelNotFound
+		}
+	default:
+		d.Problem = OllamaModelNotFound
+	}
+	return d
+}
+
+// Hint says what to do about the problem.
+func (d OllamaDiagnosis) Hint() string {
+	switch d.Problem {
+	case OllamaBadConfig:
+		return "Fix the Ollama auth or TLS settings in the inputs screen, or the environment variables they read."
+	case OllamaUnreachable:
+		return fmt.Sprintf("Nothing answered at %s. Start Ollama with `ollama serve`, or fix the URL.", d.URL)
+	case OllamaBadStatus:
+		if d.HTTPCode == http.StatusUnauthorized || d.HTTPCode == http.StatusForbidden {
+			return fmt.Sprintf("%s refused the request (HTTP %d). Check the credentials or the proxy in front of Ollama.", d.URL, d.HTTPCode)
+		}
+		return fmt.Sprintf("%s answered HTTP %d. Check that the URL points at an Ollama server.", d.URL, d.HTTPCode)
+	case OllamaNoModelList:
+		return fmt.Sprintf("Ollama at %s is up but could not list its models. Check its logs.", d.URL)
+	case OllamaModelNotFound:
+		hint := fmt.Sprintf("Model %q is not installed. Pull it with `ollama pull %s`", d.Model, d.Model)
+		if len(d.Models) == 0 {
+			return hint + "."
+		}
+		names := make([]string, len(d.Models))
+		for i, m := range d.Models {
+			names[i] = FormatModelName(m.Name)
+		}
+		return hint + ", or switch to one of: " + strings.Join(names, ", ") + "."
+	}
+	return fmt.Sprintf("Ollama at %s is up and has %s; the failure came from elsewhere.", d.URL, d.Model)
+}
+
 // ListOllamaModels fetches available models from the Ollama API.
 func ListOllamaModels(ctx context.Context, url string) ([]OllamaModel, error) {
 	if url == "" {
 		url = DefaultOllamaURL()
 	}
+	return listOllamaModels(ctx, Config{Type: ProviderOllama, OllamaURL: url}.WithEnv())
+}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/api/tags", nil)
+func listOllamaModels(ctx context.Context, cfg Config) ([]OllamaModel, error) {
+	client, err := cfg.HTTPClient(5 * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	req, err := cfg.newOllamaRequest(ctx, http.MethodGet, "/api/tags", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
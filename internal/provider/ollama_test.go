@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -219,4 +220,101 @@ func TestListOllamaModels_MalformedJSON(t *testing.T) {
 	if len(models) != 0 {
 		t.Errorf("models should be empty, got %d", len(models))
 	}
-}
\ No newline at end of file
+}
+// ============================================================
+// DiagnoseOllama
+// ============================================================
+
+func TestDiagnoseOllama(t *testing.T) {
+	t.Parallel()
+	// fakeOllama answers the health check with health and lists models
+	// with tags (nil = /api/tags fails).
+	fakeOllama := func(health int, tags []string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/version":
+				w.WriteHeader(health)
+				json.NewEncoder(w).Encode(map[string]string{"version": "0.14.3"})
+			case "/api/tags":
+				if tags == nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				var models []map[string]any
+				for _, name := range tags {
+					models = append(models, map[string]any{"name": name})
+				}
+				json.NewEncoder(w).Encode(map[string]any{"models": models})
+			}
+		}))
+	}
+
+	tests := []struct {
+		name     string
+		srv      *httptest.Server // nil = nothing listening
+		model    string
+		want     OllamaProblem
+		wantHint string
+	}{
+		{name: "healthy", srv: fakeOllama(http.StatusOK, []string{"qwen3-coder:latest"}), model: "qwen3-coder", want: OllamaOK},
+		{name: "daemon down", model: "qwen3-coder", want: OllamaUnreachable, wantHint: "ollama serve"},
+		{name: "unauthorized", srv: fakeOllama(http.StatusUnauthorized, nil), model: "x", want: OllamaBadStatus, wantHint: "credentials"},
+		{name: "not ollama", srv: fakeOllama(http.StatusNotFound, nil), model: "x", want: OllamaBadStatus, wantHint: "HTTP 404"},
+		{name: "model list fails", srv: fakeOllama(http.StatusOK, nil), model: "x", want: OllamaNoModelList},
+		{name: "model missing", srv: fakeOllama(http.StatusOK, []string{"llama3:latest", "gpt-oss:20b"}), model: "qwen3-coder",
+			want: OllamaModelNotFound, wantHint: "ollama pull qwen3-coder`, or switch to one of: llama3, gpt-oss:20b"},
+		{name: "no models installed", srv: fakeOllama(http.StatusOK, []string{}), model: "qwen3-coder", want: OllamaModelNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			url := "http://127.0.0.1:19999"
+			if tt.srv != nil {
+				defer tt.srv.Close()
+				url = tt.srv.URL
+			}
+			d := DiagnoseOllama(context.Background(), Config{Type: ProviderOllama, Model: tt.model, OllamaURL: url})
+			if d.Problem != tt.want {
+				t.Fatalf("Problem = %q, want %q (%+v)", d.Problem, tt.want, d)
+			}
+			if d.URL != url {
+				t.Errorf("URL = %q, want %q", d.URL, url)
+			}
+			if !strings.Contains(d.Hint(), tt.wantHint) {
+				t.Errorf("Hint() = %q, want containing %q", d.Hint(), tt.wantHint)
+			}
+		})
+	}
+}
+
+// ============================================================
+// EmbedOllama
+// ============================================================
+
+func TestEmbedOllama(t *testing.T) {
+	t.Parallel()
+	var got struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": [][]float32{{1, 0}, {0, 1}}})
+	}))
+	defer srv.Close()
+
+	vectors, err := EmbedOllama(context.Background(), Config{OllamaURL: srv.URL}, "nomic-embed-text", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EmbedOllama: %v", err)
+	}
+	if got.Model != "nomic-embed-text" || len(got.Input) != 2 || len(vectors) != 2 || vectors[1][1] != 1 {
+		t.Errorf("request %+v, vectors %v", got, vectors)
+	}
+	if _, err := EmbedOllama(context.Background(), Config{OllamaURL: srv.URL}, "m", []string{"only one"}); err == nil {
+		t.Error("a count mismatch should be an error")
+	}
+}
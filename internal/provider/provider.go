@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 )
@@ -18,7 +19,14 @@ const (
 type Config struct {
 	Type      ProviderType `json:"type"`
 	Model     string       `json:"model"`
-	OllamaURL string      `json:"ollama_url,omitempty"`
+	OllamaURL string       `json:"ollama_url,omitempty"`
+
+	// Remote Ollama servers (see remote.go). The secret stays in the
+	// environment variable named by OllamaTokenEnv.
+	OllamaAuth     string `json:"ollama_auth,omitempty"`      // "", "bearer" or "basic"
+	OllamaTokenEnv string `json:"ollama_token_env,omitempty"` // "" = FORGE_OLLAMA_TOKEN
+	OllamaCACert   string `json:"ollama_ca_cert,omitempty"`   // PEM file trusted for the server
+	OllamaInsecure bool   `json:"ollama_insecure,omitempty"`  // skip certificate verification
 }
 
 // OllamaStatus represents the result of a DetectOllama call.
@@ -28,6 +36,7 @@ type OllamaStatus struct {
 	Version   string        // Ollama server version if available
 	Models    []OllamaModel // populated only if Available is true
 	Error     string        // non-empty if detection failed
+	HTTPCode  int           // status of the health check; 0 = no response
 	Latency   time.Duration // round-trip time of health check
 }
 
@@ -39,8 +48,12 @@ type OllamaModel struct {
 	ModifiedAt time.Time // last modified
 }
 
-// DefaultOllamaURL returns the standard local Ollama endpoint.
+// DefaultOllamaURL returns the Ollama endpoint set by FORGE_OLLAMA_URL or
+// OLLAMA_HOST, or the standard local one.
 func DefaultOllamaURL() string {
+	if url := ollamaURLFromEnv(os.Getenv); url != "" {
+		return url
+	}
 	return "http://localhost:11434"
 }
 
@@ -56,21 +69,40 @@ func DefaultConfig() Config {
 // to connect to the selected provider.
 //   - Anthropic: empty map (claude uses its default behavior).
 //   - Ollama: ANTHROPIC_BASE_URL, ANTHROPIC_AUTH_TOKEN, ANTHROPIC_API_KEY.
+//     A bearer token replaces the placeholder auth token, basic auth goes
+//     in ANTHROPIC_CUSTOM_HEADERS, and the TLS options become
+//     NODE_EXTRA_CA_CERTS and NODE_TLS_REJECT_UNAUTHORIZED.
 func EnvVarsForProvider(cfg Config) map[string]string {
 	if cfg.Type == ProviderAnthropic {
 		return map[string]string{}
 	}
 
+	cfg = cfg.WithEnv()
 	url := cfg.OllamaURL
 	if url == "" {
 		url = DefaultOllamaURL()
 	}
 
-	return map[string]string{
+	env := map[string]string{
 		"ANTHROPIC_BASE_URL":   url,
 		"ANTHROPIC_AUTH_TOKEN": "ollama",
 		"ANTHROPIC_API_KEY":    "ollama",
 	}
+	// A missing secret shows up as a 401 in DiagnoseOllama
+	if auth, err := cfg.AuthHeader(); err == nil && auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			env["ANTHROPIC_AUTH_TOKEN"] = token
+		} else {
+			env["ANTHROPIC_CUSTOM_HEADERS"] = "Authorization: " + auth
+		}
+	}
+	if cfg.OllamaCACert != "" {
+		env["NODE_EXTRA_CA_CERTS"] = cfg.OllamaCACert
+	}
+	if cfg.OllamaInsecure {
+		env["NODE_TLS_REJECT_UNAUTHORIZED"] = "0"
+	}
+	return env
 }
 
 // ValidateConfig checks that a provider config is valid.
@@ -94,6 +126,12 @@ func ValidateConfig(cfg Config) []string {
 		}
 	}
 
+	switch cfg.OllamaAuth {
+	case OllamaAuthNone, OllamaAuthBearer, OllamaAuthBasic:
+	default:
+		errs = append(errs, fmt.Sprintf("unknown Ollama auth: %q (want bearer or basic)", cfg.OllamaAuth))
+	}
+
 	return errs
 }
 
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables that configure a remote Ollama server. They fill
+// in whatever the saved config leaves empty.
+const (
+	EnvOllamaURL      = "FORGE_OLLAMA_URL"      // server URL; OLLAMA_HOST is used when unset
+	EnvOllamaAuth     = "FORGE_OLLAMA_AUTH"     // "bearer" or "basic"
+	EnvOllamaToken    = "FORGE_OLLAMA_TOKEN"    // default secret: the token, or user:password for basic
+	EnvOllamaCACert   = "FORGE_OLLAMA_CA_CERT"  // PEM file trusted for the server's certificate
+	EnvOllamaInsecure = "FORGE_OLLAMA_INSECURE" // "1" or "true" skips certificate verification
+)
+
+// Ollama auth schemes.
+const (
+	OllamaAuthNone   = ""
+	OllamaAuthBearer = "bearer"
+	OllamaAuthBasic  = "basic"
+)
+
+// ollamaURLFromEnv returns the Ollama URL set in the environment, or "".
+// OLLAMA_HOST may be a bare host:port, as the ollama CLI accepts.
+func ollamaURLFromEnv(getenv func(string) string) string {
+	if url := getenv(EnvOllamaURL); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	host := getenv("OLLAMA_HOST")
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+// WithEnv returns the config with its empty Ollama connection settings
+// filled in from the environment.
+func (c Config) WithEnv() Config {
+	return c.withEnv(os.Getenv)
+}
+
+func (c Config) withEnv(getenv func(string) string) Config {
+	if c.OllamaURL == "" {
+		c.OllamaURL = ollamaURLFromEnv(getenv)
+	}
+	if c.OllamaAuth == "" {
+		c.OllamaAuth = strings.ToLower(getenv(EnvOllamaAuth))
+	}
+	if c.OllamaCACert == "" {
+		c.OllamaCACert = getenv(EnvOllamaCACert)
+	}
+	if v := strings.ToLower(getenv(EnvOllamaInsecure)); v == "1" || v == "true" {
+		c.OllamaInsecure = true
+	}
+	return c
+}
+
+// TokenEnv returns the name of the environment variable holding the
+// Ollama secret. The secret itself is never saved in state.
+func (c Config) TokenEnv() string {
+	if c.OllamaTokenEnv != "" {
+		return c.OllamaTokenEnv
+	}
+	return EnvOllamaToken
+}
+
+// AuthHeader returns the Authorization header value for the Ollama server,
+// or "" when it needs none.
+func (c Config) AuthHeader() (string, error) {
+	return c.authHeader(os.Getenv)
+}
+
+func (c Config) authHeader(getenv func(string) string) (string, error) {
+	if c.OllamaAuth == OllamaAuthNone {
+		return "", nil
+	}
+	secret := getenv(c.TokenEnv())
+	if secret == "" {
+		return "", fmt.Errorf("Ollama %s auth is on but $%s is empty", c.OllamaAuth, c.TokenEnv())
+	}
+	switch c.OllamaAuth {
+	case OllamaAuthBearer:
+		return "Bearer " + secret, nil
+	case OllamaAuthBasic:
+		if !strings.Contains(secret, ":") {
+			return "", fmt.Errorf("$%s must be user:password for basic auth", c.TokenEnv())
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(secret)), nil
+	}
+	return "", fmt.Errorf("unknown Ollama auth %q (want bearer or basic)", c.OllamaAuth)
+}
+
+// HTTPClient returns a client for the Ollama server that applies the TLS
+// options.
+func (c Config) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	if c.OllamaCACert == "" && !c.OllamaInsecure {
+		return &http.Client{Timeout: timeout}, nil
+	}
+	tlsCfg := &tls.Config{InsecureSkipVerify: c.OllamaInsecure}
+	if c.OllamaCACert != "" {
+		pem, err := os.ReadFile(c.OllamaCACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading Ollama CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", c.OllamaCACert)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// newOllamaRequest builds a request to the server with its auth header. A
+// body is sent as JSON.
+func (c Config) newOllamaRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.OllamaURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	auth, err := c.AuthHeader()
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return req, nil
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeEnv(vars map[string]string) func(string) string {
+	return func(k string) string { return vars[k] }
+}
+
+func TestConfig_WithEnv(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "env fills empty settings",
+			env: map[string]string{EnvOllamaURL: "https://gpu.example:11434/", EnvOllamaAuth: "Bearer",
+				EnvOllamaCACert: "/etc/ca.pem", EnvOllamaInsecure: "true"},
+			want: Config{OllamaURL: "https://gpu.example:11434", OllamaAuth: "bearer", OllamaCACert: "/etc/ca.pem", OllamaInsecure: true},
+		},
+		{
+			name: "saved settings win",
+			cfg:  Config{OllamaURL: "http://saved:11434", OllamaAuth: "basic"},
+			env:  map[string]string{EnvOllamaURL: "http://env:11434", EnvOllamaAuth: "bearer"},
+			want: Config{OllamaURL: "http://saved:11434", OllamaAuth: "basic"},
+		},
+		{
+			name: "bare OLLAMA_HOST",
+			env:  map[string]string{"OLLAMA_HOST": "10.0.0.5:11434"},
+			want: Config{OllamaURL: "http://10.0.0.5:11434"},
+		},
+		{
+			name: "FORGE_OLLAMA_URL before OLLAMA_HOST",
+			env:  map[string]string{"OLLAMA_HOST": "10.0.0.5:11434", EnvOllamaURL: "https://forge:1"},
+			want: Config{OllamaURL: "https://forge:1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.cfg.withEnv(fakeEnv(tt.env)); got != tt.want {
+				t.Errorf("withEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_AuthHeader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     Config
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{name: "none", cfg: Config{}, want: ""},
+		{name: "bearer", cfg: Config{OllamaAuth: OllamaAuthBearer}, env: map[string]string{EnvOllamaToken: "s3cret"}, want: "Bearer s3cret"},
+		{name: "custom env var", cfg: Config{OllamaAuth: OllamaAuthBearer, OllamaTokenEnv: "MY_TOKEN"},
+			env: map[string]string{"MY_TOKEN": "abc"}, want: "Bearer abc"},
+		{name: "basic", cfg: Config{OllamaAuth: OllamaAuthBasic}, env: map[string]string{EnvOllamaToken: "user:pass"},
+			want: "Basic dXNlcjpwYXNz"},
+		{name: "basic without colon", cfg: Config{OllamaAuth: OllamaAuthBasic}, env: map[string]string{EnvOllamaToken: "token"},
+			wantErr: "user:password"},
+		{name: "missing secret", cfg: Config{OllamaAuth: OllamaAuthBearer}, wantErr: "$FORGE_OLLAMA_TOKEN is empty"},
+		{name: "unknown scheme", cfg: Config{OllamaAuth: "digest"}, env: map[string]string{EnvOllamaToken: "x"}, wantErr: "unknown Ollama auth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.cfg.authHeader(fakeEnv(tt.env))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("authHeader() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckOllama_RemoteTLSWithAuth runs against an HTTPS server with its
+// own certificate that requires a bearer token.
+func TestCheckOllama_RemoteTLSWithAuth(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/version":
+			json.NewEncoder(w).Encode(map[string]string{"version": "0.14.3"})
+		case "/api/tags":
+			json.NewEncoder(w).Encode(map[string]any{"models": []map[string]any{{"name": "qwen3-coder:latest"}}})
+		}
+	}))
+	defer srv.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caPath, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FORGE_TEST_OLLAMA_TOKEN", "s3cret")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantOK   bool
+		wantCode int
+	}{
+		{name: "CA and token", cfg: Config{OllamaAuth: OllamaAuthBearer, OllamaTokenEnv: "FORGE_TEST_OLLAMA_TOKEN", OllamaCACert: caPath}, wantOK: true},
+		{name: "insecure and token", cfg: Config{OllamaAuth: OllamaAuthBearer, OllamaTokenEnv: "FORGE_TEST_OLLAMA_TOKEN", OllamaInsecure: true}, wantOK: true},
+		{name: "untrusted certificate", cfg: Config{OllamaAuth: OllamaAuthBearer, OllamaTokenEnv: "FORGE_TEST_OLLAMA_TOKEN"}},
+		{name: "no token", cfg: Config{OllamaCACert: caPath}, wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Type, tt.cfg.OllamaURL = ProviderOllama, srv.URL
+			status := CheckOllama(ctx, tt.cfg)
+			if status.Available != tt.wantOK || (!tt.wantOK && status.HTTPCode != tt.wantCode) {
+				t.Fatalf("status = %+v, want available=%v code=%d", status, tt.wantOK, tt.wantCode)
+			}
+			if tt.wantOK && len(status.Models) != 1 {
+				t.Errorf("models = %v, want the listing to use the same auth and TLS", status.Models)
+			}
+		})
+	}
+}
+
+func TestEnvVarsForProvider_RemoteOllama(t *testing.T) {
+	t.Setenv("FORGE_TEST_OLLAMA_TOKEN", "user:pass")
+	env := EnvVarsForProvider(Config{Type: ProviderOllama, OllamaURL: "https://gpu:11434", OllamaAuth: OllamaAuthBasic,
+		OllamaTokenEnv: "FORGE_TEST_OLLAMA_TOKEN", OllamaCACert: "/etc/ca.pem", OllamaInsecure: true})
+	want := map[string]string{
+		"ANTHROPIC_BASE_URL":           "https://gpu:11434",
+		"ANTHROPIC_AUTH_TOKEN":         "ollama",
+		"ANTHROPIC_API_KEY":            "ollama",
+		"ANTHROPIC_CUSTOM_HEADERS":     "Authorization: Basic dXNlcjpwYXNz",
+		"NODE_EXTRA_CA_CERTS":          "/etc/ca.pem",
+		"NODE_TLS_REJECT_UNAUTHORIZED": "0",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+
+	t.Setenv("FORGE_TEST_OLLAMA_TOKEN", "s3cret")
+	env = EnvVarsForProvider(Config{Type: ProviderOllama, OllamaAuth: OllamaAuthBearer, OllamaTokenEnv: "FORGE_TEST_OLLAMA_TOKEN"})
+	if env["ANTHROPIC_AUTH_TOKEN"] != "s3cret" {
+		t.Errorf("bearer token = %q, want it as ANTHROPIC_AUTH_TOKEN", env["ANTHROPIC_AUTH_TOKEN"])
+	}
+}
@@ -0,0 +1,358 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	maxAPISchemaFiles = 10 // schema files summarized per project
+	maxAPIEntries     = 60 // endpoints or messages listed per file
+)
+
+// APISchema summarizes an OpenAPI document or a protobuf file found in the
+// project, so plans and task prompts use the real contract.
+type APISchema struct {
+	Path      string   `json:"path"` // relative to the project root
+	Kind      string   `json:"kind"` // "openapi" or "proto"
+	Title     string   `json:"title,omitempty"`
+	Endpoints []string `json:"endpoints,omitempty"` // "GET /orders/{id} — Get an order", or RPCs
+	Messages  []string `json:"messages,omitempty"`  // protobuf messages with their fields
+}
+
+// isAPISchemaFile reports whether a file name looks like an API contract:
+// openapi/swagger documents (optionally prefixed, e.g. orders.openapi.yaml)
+// and .proto files.
+func isAPISchemaFile(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, ".proto") {
+		return true
+	}
+	ext := path.Ext(lower)
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return false
+	}
+	base := strings.TrimSuffix(lower, ext)
+	return base == "openapi" || base == "swagger" ||
+		strings.HasSuffix(base, ".openapi") || strings.HasSuffix(base, ".swagger")
+}
+
+// scanAPISchemas parses the schema files found by the walk. Files that
+// turn out not to be API documents are dropped.
+func scanAPISchemas(root string, files []string) []APISchema {
+	sort.Strings(files)
+	var schemas []APISchema
+	for _, rel := range files {
+		if len(schemas) == maxAPISchemaFiles {
+			break
+		}
+		content := readFileFull(root, filepath.FromSlash(rel))
+		if content == "" {
+			continue
+		}
+		var s APISchema
+		if strings.HasSuffix(strings.ToLower(rel), ".proto") {
+			s = ParseProto(content)
+		} else {
+			s = ParseOpenAPI(content)
+		}
+		if s.Kind == "" || len(s.Endpoints)+len(s.Messages) == 0 {
+			continue
+		}
+		s.Path = rel
+		schemas = append(schemas, s)
+	}
+	return schemas
+}
+
+// FormatAPISchemas renders schema summaries for a prompt, one indented
+// line per endpoint, RPC or message.
+func FormatAPISchemas(schemas []APISchema) string {
+	var b strings.Builder
+	for _, s := range schemas {
+		kind := "OpenAPI"
+		if s.Kind == "proto" {
+			kind = "protobuf"
+		}
+		if s.Title != "" {
+			fmt.Fprintf(&b, "%s (%s: %s)\n", s.Path, kind, s.Title)
+		} else {
+			fmt.Fprintf(&b, "%s (%s)\n", s.Path, kind)
+		}
+		for _, e := range s.Endpoints {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+		for _, m := range s.Messages {
+			fmt.Fprintf(&b, "  message %s\n", m)
+		}
+	}
+	return b.String()
+}
+
+// ============================================================
+// OpenAPI
+// ============================================================
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// ParseOpenAPI extracts the title and operations of an OpenAPI 3 or
+// Swagger 2 document in JSON or YAML. Kind is empty if the content isn't
+// one.
+func ParseOpenAPI(content string) APISchema {
+	if strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return parseOpenAPIJSON(content)
+	}
+	return parseOpenAPIYAML(content)
+}
+
+func parseOpenAPIJSON(content string) APISchema {
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Swagger string `json:"swagger"`
+		Info    struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if json.Unmarshal([]byte(content), &doc) != nil || doc.OpenAPI == "" && doc.Swagger == "" {
+		return APISchema{}
+	}
+
+	s := APISchema{Kind: "openapi", Title: doc.Info.Title}
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		methods := make([]string, 0, len(doc.Paths[p]))
+		for m := range doc.Paths[p] {
+			if httpMethods[strings.ToLower(m)] {
+				methods = append(methods, m)
+			}
+		}
+		sort.Slice(methods, func(i, j int) bool { return methodOrder(methods[i]) < methodOrder(methods[j]) })
+		for _, m := range methods {
+			var op struct {
+				Summary     string `json:"summary"`
+				OperationID string `json:"operationId"`
+			}
+			json.Unmarshal(doc.Paths[p][m], &op)
+			s.Endpoints = append(s.Endpoints, formatOperation(m, p, op.Summary, op.OperationID))
+		}
+	}
+	s.Endpoints = capEntries(s.Endpoints)
+	return s
+}
+
+// parseOpenAPIYAML reads just enough YAML to list operations: the
+// top-level openapi/swagger marker, info.title, and under paths the path
+// keys, their method keys, and each method's summary or operationId.
+func parseOpenAPIYAML(content string) APISchema {
+	type operation struct{ method, path, summary, operationID string }
+	var (
+		s                        APISchema
+		isAPI                    bool
+		section                  string
+		pathIndent, methodIndent = -1, -1
+		curPath                  string
+		ops                      []operation
+		cur                      *operation
+	)
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key, value = unquoteYAML(key), unquoteYAML(value)
+
+		if indent == 0 {
+			section = key
+			if key == "openapi" || key == "swagger" {
+				isAPI = true
+			}
+			continue
+		}
+		switch section {
+		case "info":
+			if key == "title" && s.Title == "" {
+				s.Title = value
+			}
+		case "paths":
+			if pathIndent < 0 {
+				pathIndent = indent
+			}
+			switch {
+			case indent == pathIndent:
+				curPath, cur, methodIndent = key, nil, -1
+			case indent > pathIndent && (methodIndent < 0 || indent == methodIndent):
+				methodIndent = indent
+				cur = nil
+				if httpMethods[strings.ToLower(key)] {
+					ops = append(ops, operation{method: key, path: curPath})
+					cur = &ops[len(ops)-1]
+				}
+			case cur != nil && indent > methodIndent:
+				if key == "summary" && cur.summary == "" {
+					cur.summary = value
+				} else if key == "operationId" && cur.operationID == "" {
+					cur.operationID = value
+				}
+			}
+		}
+	}
+	if !isAPI {
+		return APISchema{}
+	}
+	s.Kind = "openapi"
+	for _, op := range ops {
+		s.Endpoints = append(s.Endpoints, formatOperation(op.method, op.path, op.summary, op.operationID))
+	}
+	s.Endpoints = capEntries(s.Endpoints)
+	return s
+}
+
+func formatOperation(method, path, summary, operationID string) string {
+	line := strings.ToUpper(method) + " " + path
+	if summary == "" {
+		summary = operationID
+	}
+	if summary != "" {
+		line += " — " + summary
+	}
+	return line
+}
+
+func methodOrder(m string) int {
+	order := []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+	for i, o := range order {
+		if strings.EqualFold(m, o) {
+			return i
+		}
+	}
+	return len(order)
+}
+
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ============================================================
+// Protobuf
+// ============================================================
+
+var (
+	protoPackageRe = regexp.MustCompile(`^package\s+([\w.]+)\s*;`)
+	protoBlockRe   = regexp.MustCompile(`^(message|enum|service|oneof)\s+(\w+)\s*\{`)
+	protoRPCRe     = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	protoFieldRe   = regexp.MustCompile(`^(?:repeated\s+|optional\s+|required\s+)?(?:map<[^>]+>|[\w.]+)\s+(\w+)\s*=\s*\d+`)
+)
+
+// ParseProto lists the services' RPCs and the messages (with field names)
+// of a .proto file. Nested messages are named Outer.Inner.
+func ParseProto(content string) APISchema {
+	type block struct {
+		kind, name string
+		msg        int // index into messages for message blocks, else -1
+	}
+	type message struct {
+		name   string
+		fields []string
+	}
+	var (
+		s        = APISchema{Kind: "proto"}
+		stack    []block
+		messages []message
+	)
+	// nearestMessage is the message that fields at this point belong to
+	nearestMessage := func() int {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind == "message" {
+				return stack[i].msg
+			}
+		}
+		return -1
+	}
+
+	for _, raw := range strings.Split(content, "\n") {
+		line, _, _ := strings.Cut(raw, "//")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := protoPackageRe.FindStringSubmatch(line); m != nil {
+			s.Title = "package " + m[1]
+			continue
+		}
+		if m := protoBlockRe.FindStringSubmatch(line); m != nil {
+			b := block{kind: m[1], name: m[2], msg: -1}
+			if b.kind == "message" {
+				name := m[2]
+				if outer := nearestMessage(); outer >= 0 {
+					name = messages[outer].name + "." + name
+				}
+				messages = append(messages, message{name: name})
+				b.msg = len(messages) - 1
+			}
+			stack = append(stack, b)
+			if strings.HasSuffix(line, "}") { // one-line block, e.g. "message Empty {}"
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		if m := protoRPCRe.FindStringSubmatch(line); m != nil && len(stack) > 0 && stack[len(stack)-1].kind == "service" {
+			s.Endpoints = append(s.Endpoints, fmt.Sprintf("rpc %s.%s(%s%s) returns (%s%s)",
+				stack[len(stack)-1].name, m[1], m[2], m[3], m[4], m[5]))
+		} else if m := protoFieldRe.FindStringSubmatch(line); m != nil {
+			if i := nearestMessage(); i >= 0 && stack[len(stack)-1].kind != "enum" {
+				messages[i].fields = append(messages[i].fields, m[1])
+			}
+		}
+		// Other braces (rpc options, option literals) only need balancing
+		for range strings.Count(line, "{") {
+			stack = append(stack, block{kind: "other", msg: -1})
+		}
+		for range strings.Count(line, "}") {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	for _, m := range messages {
+		if len(m.fields) == 0 {
+			s.Messages = append(s.Messages, m.name+" {}")
+			continue
+		}
+		s.Messages = append(s.Messages, fmt.Sprintf("%s { %s }", m.name, strings.Join(m.fields, ", ")))
+	}
+	s.Endpoints = capEntries(s.Endpoints)
+	s.Messages = capEntries(s.Messages)
+	return s
+}
+
+// capEntries keeps the first maxAPIEntries entries and notes the rest.
+func capEntries(entries []string) []string {
+	if len(entries) <= maxAPIEntries {
+		return entries
+	}
+	return append(entries[:maxAPIEntries:maxAPIEntries], fmt.Sprintf("… and %d more", len(entries)-maxAPIEntries))
+}
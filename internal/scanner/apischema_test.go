@@ -0,0 +1,185 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsAPISchemaFile(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]bool{
+		"openapi.yaml":         true,
+		"swagger.json":         true,
+		"orders.openapi.yml":   true,
+		"orders.proto":         true,
+		"openapi-generator.sh": false,
+		"config.yaml":          false,
+		"package.json":         false,
+	} {
+		if got := isAPISchemaFile(name); got != want {
+			t.Errorf("isAPISchemaFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// ============================================================
+// ParseOpenAPI
+// ============================================================
+
+func TestParseOpenAPI_YAML(t *testing.T) {
+	t.Parallel()
+	doc := `openapi: 3.0.3
+info:
+  title: "Orders API"
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      summary: List orders
+      parameters:
+        - name: limit
+          in: query
+    post:
+      operationId: createOrder
+      responses:
+        '201':
+          description: Created
+  '/orders/{id}':
+    parameters:
+      - name: id
+    delete:
+      summary: Cancel an order
+components:
+  schemas:
+    Order:
+      type: object
+`
+	got := ParseOpenAPI(doc)
+	want := APISchema{Kind: "openapi", Title: "Orders API", Endpoints: []string{
+		"GET /orders — List orders",
+		"POST /orders — createOrder",
+		"DELETE /orders/{id} — Cancel an order",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOpenAPI() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseOpenAPI_JSON(t *testing.T) {
+	t.Parallel()
+	doc := `{"swagger": "2.0", "info": {"title": "Pets"}, "paths": {
+		"/pets/{id}": {"delete": {}, "get": {"summary": "Get a pet"}, "parameters": []},
+		"/pets": {"post": {"operationId": "addPet"}}}}`
+	got := ParseOpenAPI(doc)
+	want := []string{"POST /pets — addPet", "GET /pets/{id} — Get a pet", "DELETE /pets/{id}"}
+	if got.Kind != "openapi" || got.Title != "Pets" || !reflect.DeepEqual(got.Endpoints, want) {
+		t.Errorf("ParseOpenAPI() = %+v, want endpoints %q", got, want)
+	}
+}
+
+func TestParseOpenAPI_NotAnAPI(t *testing.T) {
+	t.Parallel()
+	for _, doc := range []string{"name: app\npaths:\n  /x:\n    get: {}\n", `{"name": "app"}`, "not: [valid"} {
+		if got := ParseOpenAPI(doc); got.Kind != "" {
+			t.Errorf("ParseOpenAPI(%q) = %+v, want no schema", doc, got)
+		}
+	}
+}
+
+// ============================================================
+// ParseProto
+// ============================================================
+
+func TestParseProto(t *testing.T) {
+	t.Parallel()
+	doc := `syntax = "proto3";
+package shop.v1; // the shop
+
+option go_package = "example.com/shop/v1";
+
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order);
+  rpc WatchOrders(WatchRequest) returns (stream Order) {
+    option (google.api.http) = { get: "/v1/orders:watch" };
+  }
+}
+
+message Order {
+  string id = 1;
+  repeated Item items = 2;
+  map<string, string> labels = 3;
+  oneof payment {
+    string card = 4;
+    string invoice = 5;
+  }
+  message Item {
+    string sku = 1;
+    int32 quantity = 2;
+  }
+  Status status = 6;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+
+message GetOrderRequest { }
+message WatchRequest {}
+`
+	got := ParseProto(doc)
+	want := APISchema{
+		Kind:  "proto",
+		Title: "package shop.v1",
+		Endpoints: []string{
+			"rpc OrderService.GetOrder(GetOrderRequest) returns (Order)",
+			"rpc OrderService.WatchOrders(WatchRequest) returns (stream Order)",
+		},
+		Messages: []string{
+			"Order { id, items, labels, card, invoice, status }",
+			"Order.Item { sku, quantity }",
+			"GetOrderRequest {}",
+			"WatchRequest {}",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProto() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// ============================================================
+// Scan / FormatAPISchemas
+// ============================================================
+
+func TestScan_FindsAPISchemas(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.go", "package main")
+	writeTestFile(t, dir, "api/openapi.yaml", "openapi: 3.1.0\npaths:\n  /health:\n    get:\n      summary: Liveness\n")
+	writeTestFile(t, dir, "proto/ping.proto", "service Ping {\n  rpc Ping(PingRequest) returns (PingReply);\n}\n")
+	writeTestFile(t, dir, "deploy/swagger.yaml", "kind: Deployment\n") // name matches, content doesn't
+
+	snap := Scan(dir)
+	if len(snap.APISchemas) != 2 {
+		t.Fatalf("APISchemas = %+v, want 2", snap.APISchemas)
+	}
+	got := FormatAPISchemas(snap.APISchemas)
+	want := "api/openapi.yaml (OpenAPI)\n  GET /health — Liveness\n" +
+		"proto/ping.proto (protobuf)\n  rpc Ping.Ping(PingRequest) returns (PingReply)\n"
+	if got != want {
+		t.Errorf("FormatAPISchemas() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCapEntries(t *testing.T) {
+	t.Parallel()
+	var entries []string
+	for i := range maxAPIEntries + 5 {
+		entries = append(entries, fmt.Sprint(i))
+	}
+	got := capEntries(entries)
+	if len(got) != maxAPIEntries+1 || !strings.Contains(got[maxAPIEntries], "5 more") {
+		t.Errorf("capEntries() kept %d, last %q", len(got), got[len(got)-1])
+	}
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// snapshotVersion is part of every cache key; bump it when Scan learns to
+// fill new fields so entries written by older versions are rescanned.
+const snapshotVersion = "5" // 2: API schemas, 3: TODO comments, 4: workspace repos, 5: submodules
+
+// snapshotCacheEntry is the on-disk form of a cached snapshot.
+type snapshotCacheEntry struct {
+	Key      string          `json:"key"`
+	Snapshot ProjectSnapshot `json:"snapshot"`
+}
+
+// ScanCached behaves like Scan but reuses the previous result when the
+// git HEAD and worktree status are unchanged. Non-git directories and
+// cache errors fall back to a fresh scan.
+func ScanCached(root string) ProjectSnapshot {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return Scan(root)
+	}
+	return scanCached(root, filepath.Join(dir, "forge", "snapshots"))
+}
+
+func scanCached(root, cacheDir string) ProjectSnapshot {
+	key := snapshotCacheKey(root)
+	if key == "" {
+		return Scan(root)
+	}
+
+	path := filepath.Join(cacheDir, snapshotCacheFile(root))
+	if data, err := os.ReadFile(path); err == nil {
+		var entry snapshotCacheEntry
+		if json.Unmarshal(data, &entry) == nil && entry.Key == key {
+			return entry.Snapshot
+		}
+	}
+
+	snap := Scan(root)
+
+	// Best effort: a failed write only costs a rescan next time.
+	if data, err := json.Marshal(snapshotCacheEntry{Key: key, Snapshot: snap}); err == nil {
+		if os.MkdirAll(cacheDir, 0755) == nil {
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+
+	return snap
+}
+
+// snapshotCacheKey combines HEAD with the porcelain status so uncommitted
+// changes invalidate the cache. Returns "" outside a git repo or before
+// the first commit.
+func snapshotCacheKey(root string) string {
+	head := runGit(root, "rev-parse", "HEAD")
+	if head == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(snapshotVersion + "\n" + head + "\n" + runGit(root, "status", "--porcelain")))
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotCacheFile names the cache file after the absolute project path.
+func snapshotCacheFile(root string) string {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return hex.EncodeToString(sum[:8]) + ".json"
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// parseGoFailures picks test names from "--- FAIL: TestName (0.00s)" lines.
+func parseGoFailures(output string) []string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		rest, ok := strings.CutPrefix(trimmed, "--- FAIL: ")
+		if !ok {
+			continue
+		}
+		if name := strings.Fields(rest); len(name) > 0 {
+			failures = append(failures, name[0])
+		}
+	}
+	return dedup(failures)
+}
+
+// parseJSFailures picks test files from jest/vitest "FAIL path" lines.
+func parseJSFailures(output string) []string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		rest, ok := strings.CutPrefix(trimmed, "FAIL ")
+		if !ok {
+			continue
+		}
+		if name := strings.Fields(rest); len(name) > 0 {
+			failures = append(failures, name[0])
+		}
+	}
+	return dedup(failures)
+}
+
+// parsePytestFailures picks node IDs from "FAILED path::test - reason" lines.
+func parsePytestFailures(output string) []string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "FAILED ")
+		if !ok {
+			continue
+		}
+		if name := strings.Fields(rest); len(name) > 0 {
+			failures = append(failures, name[0])
+		}
+	}
+	return dedup(failures)
+}
+
+// parseRustFailures picks test paths from "test foo::bar ... FAILED" lines.
+func parseRustFailures(output string) []string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "test ") || !strings.HasSuffix(trimmed, "... FAILED") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(trimmed, "test "), "... FAILED")
+		failures = append(failures, strings.TrimSpace(name))
+	}
+	return dedup(failures)
+}
+
+// parseRSpecFailures picks examples from "rspec ./spec/x_spec.rb:12 # desc" lines.
+func parseRSpecFailures(output string) []string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "rspec ")
+		if !ok {
+			continue
+		}
+		if name := strings.Fields(rest); len(name) > 0 {
+			failures = append(failures, name[0])
+		}
+	}
+	return dedup(failures)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -76,6 +77,12 @@ func branchExists(root, branch string) bool {
 	return cmd.Run() == nil
 }
 
+// RemoteURL returns the origin remote URL of the repository at root, or
+// "" if it has none.
+func RemoteURL(root string) string {
+	return getRemoteURL(root)
+}
+
 // getRemoteURL returns the origin remote URL if set.
 func getRemoteURL(root string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
@@ -171,6 +178,6 @@ func AddRemote(root, name, url string) error {
 
 // GitInitialized returns true if the directory has a .git folder.
 func GitInitialized(root string) bool {
-	_, err := os.Stat(root + "/.git")
+	_, err := os.Stat(filepath.Join(root, ".git"))
 	return err == nil
 }
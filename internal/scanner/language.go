@@ -15,51 +15,206 @@ var rsFrameworks = []string{"actix", "axum", "tokio", "rocket", "serde"}
 var dartFrameworks = []string{"flutter", "riverpod", "bloc", "dio"}
 
 // detectLanguage examines manifest files to determine the primary language,
-// frameworks, and dependencies.
+// frameworks, and dependencies. The first registered language that detects
+// the project wins.
 func detectLanguage(root string) (language string, frameworks []string, dependencies []string) {
-	// Detection priority: first match wins for primary language
-	type detector struct {
-		file     string
-		language string
-		detect   func(path string) (string, []string, []string)
+	for _, l := range Languages() {
+		name := l.Detect(root)
+		if name == "" {
+			continue
+		}
+		frameworks, dependencies = l.Frameworks(root)
+		return name, frameworks, dependencies
 	}
 
-	detectors := []detector{
-		{"go.mod", "Go", detectGo},
-		{"package.json", "", detectJS}, // language determined by tsconfig presence
-		{"requirements.txt", "Python", detectPythonReqs},
-		{"pyproject.toml", "Python", detectPythonPyproject},
-		{"setup.py", "Python", nil},
-		{"Pipfile", "Python", nil},
-		{"Cargo.toml", "Rust", detectRust},
-		{"pom.xml", "Java", nil},
-		{"build.gradle", "Java", nil},
-		{"build.gradle.kts", "Kotlin", nil},
-		{"Gemfile", "Ruby", nil},
-		{"composer.json", "PHP", nil},
-		{"Package.swift", "Swift", nil},
-		{"pubspec.yaml", "Dart/Flutter", detectDart},
-		{"mix.exs", "Elixir", nil},
+	return "", nil, nil
+}
+
+// manifest is a file whose presence identifies a language.
+type manifest struct {
+	file     string
+	language string // reported language; "" means parse decides
+	parse    func(path string) (string, []string, []string)
+}
+
+// builtinLanguage implements Language for the languages forge ships with.
+type builtinLanguage struct {
+	name      string
+	aliases   []string
+	manifests []manifest
+	testCmd   func(snap *ProjectSnapshot) string
+	buildCmd  func(snap *ProjectSnapshot) string
+	failures  FailureParser
+}
+
+// builtinLanguages is the single table of languages forge knows about.
+// Order is detection priority: first match wins for the primary language.
+func builtinLanguages() []Language {
+	return []Language{
+		&builtinLanguage{
+			name:      "Go",
+			manifests: []manifest{{"go.mod", "Go", detectGo}},
+			testCmd:   fixedCmd("go test ./..."),
+			buildCmd:  fixedCmd("go build ./..."),
+			failures:  parseGoFailures,
+		},
+		&builtinLanguage{
+			name:      "JavaScript",
+			aliases:   []string{"TypeScript"},
+			manifests: []manifest{{"package.json", "", detectJS}}, // language determined by tsconfig presence
+			testCmd: func(snap *ProjectSnapshot) string {
+				switch {
+				case hasName(snap.TestFrameworks, "vitest"):
+					return "npx vitest run"
+				case hasName(snap.TestFrameworks, "jest"):
+					return "npx jest"
+				}
+				return "npm test"
+			},
+			buildCmd: fixedCmd("npm run build"),
+			failures: parseJSFailures,
+		},
+		&builtinLanguage{
+			name: "Python",
+			manifests: []manifest{
+				{"requirements.txt", "Python", detectPythonReqs},
+				{"pyproject.toml", "Python", detectPythonPyproject},
+				{"setup.py", "Python", nil},
+				{"Pipfile", "Python", nil},
+			},
+			testCmd: func(snap *ProjectSnapshot) string {
+				if hasName(snap.Frameworks, "django") {
+					return "python manage.py test"
+				}
+				return "pytest"
+			},
+			failures: parsePytestFailures,
+		},
+		&builtinLanguage{
+			name:      "Rust",
+			manifests: []manifest{{"Cargo.toml", "Rust", detectRust}},
+			testCmd:   fixedCmd("cargo test"),
+			buildCmd:  fixedCmd("cargo build"),
+			failures:  parseRustFailures,
+		},
+		&builtinLanguage{
+			name:    "Java",
+			aliases: []string{"Kotlin"},
+			manifests: []manifest{
+				{"pom.xml", "Java", nil},
+				{"build.gradle", "Java", nil},
+				{"build.gradle.kts", "Kotlin", nil},
+			},
+			testCmd:  fixedCmd("mvn test"),
+			buildCmd: fixedCmd("mvn package"),
+		},
+		&builtinLanguage{
+			name:      "Ruby",
+			manifests: []manifest{{"Gemfile", "Ruby", nil}},
+			testCmd: func(snap *ProjectSnapshot) string {
+				if hasName(snap.TestFrameworks, "minitest") && !hasName(snap.TestFrameworks, "rspec") {
+					return "bundle exec rake test"
+				}
+				return "bundle exec rspec"
+			},
+			failures: parseRSpecFailures,
+		},
+		&builtinLanguage{
+			name:      "PHP",
+			manifests: []manifest{{"composer.json", "PHP", nil}},
+		},
+		&builtinLanguage{
+			name:      "Swift",
+			manifests: []manifest{{"Package.swift", "Swift", nil}},
+		},
+		&builtinLanguage{
+			name:      "Dart/Flutter",
+			aliases:   []string{"Dart", "Flutter"},
+			manifests: []manifest{{"pubspec.yaml", "Dart/Flutter", detectDart}},
+			testCmd: func(snap *ProjectSnapshot) string {
+				if hasName(snap.Frameworks, "flutter") {
+					return "flutter test"
+				}
+				return "dart test"
+			},
+			buildCmd: func(snap *ProjectSnapshot) string {
+				if hasName(snap.Frameworks, "flutter") {
+					return "flutter build apk"
+				}
+				return ""
+			},
+		},
+		&builtinLanguage{
+			name:      "Elixir",
+			manifests: []manifest{{"mix.exs", "Elixir", nil}},
+			testCmd:   fixedCmd("mix test"),
+		},
 	}
+}
 
-	for _, d := range detectors {
-		path := filepath.Join(root, d.file)
-		if _, err := os.Stat(path); err != nil {
-			continue
-		}
+func (l *builtinLanguage) Name() string      { return l.name }
+func (l *builtinLanguage) Aliases() []string { return l.aliases }
 
-		if d.detect != nil {
-			lang, fw, deps := d.detect(path)
-			if lang == "" {
-				lang = d.language
-			}
-			return lang, fw, deps
-		}
+func (l *builtinLanguage) Detect(root string) string {
+	m, path := l.findManifest(root)
+	if m == nil {
+		return ""
+	}
+	if m.language != "" {
+		return m.language
+	}
+	lang, _, _ := m.parse(path)
+	return lang
+}
 
-		return d.language, nil, nil
+func (l *builtinLanguage) Frameworks(root string) ([]string, []string) {
+	m, path := l.findManifest(root)
+	if m == nil || m.parse == nil {
+		return nil, nil
 	}
+	_, fw, deps := m.parse(path)
+	return fw, deps
+}
 
-	return "", nil, nil
+func (l *builtinLanguage) TestCmd(snap *ProjectSnapshot) string {
+	if l.testCmd == nil || snap == nil {
+		return ""
+	}
+	return l.testCmd(snap)
+}
+
+func (l *builtinLanguage) BuildCmd(snap *ProjectSnapshot) string {
+	if l.buildCmd == nil || snap == nil {
+		return ""
+	}
+	return l.buildCmd(snap)
+}
+
+func (l *builtinLanguage) FailureParser() FailureParser { return l.failures }
+
+// findManifest returns the first of the language's manifests present in root.
+func (l *builtinLanguage) findManifest(root string) (*manifest, string) {
+	for i := range l.manifests {
+		path := filepath.Join(root, l.manifests[i].file)
+		if _, err := os.Stat(path); err == nil {
+			return &l.manifests[i], path
+		}
+	}
+	return nil, ""
+}
+
+func fixedCmd(cmd string) func(*ProjectSnapshot) string {
+	return func(*ProjectSnapshot) string { return cmd }
+}
+
+// hasName reports whether names contains want, ignoring case.
+func hasName(names []string, want string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, want) {
+			return true
+		}
+	}
+	return false
 }
 
 func detectGo(path string) (string, []string, []string) {
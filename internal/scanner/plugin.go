@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"strings"
+	"sync"
+)
+
+// Language describes how forge recognises and works with one language
+// ecosystem. Built-in languages live in language.go; third parties can
+// add their own with RegisterLanguage.
+type Language interface {
+	// Name is the canonical language name, as reported in ProjectSnapshot.Language.
+	Name() string
+	// Detect returns the language name if root looks like a project in
+	// this language, or "" otherwise.
+	Detect(root string) string
+	// Frameworks returns the frameworks and dependencies declared in root.
+	Frameworks(root string) (frameworks []string, dependencies []string)
+	// TestCmd returns the default test command for the snapshot, or "".
+	TestCmd(snap *ProjectSnapshot) string
+	// BuildCmd returns the default build command for the snapshot, or "".
+	BuildCmd(snap *ProjectSnapshot) string
+	// FailureParser returns the parser for this language's test output.
+	FailureParser() FailureParser
+}
+
+// FailureParser extracts the names of failing tests from test output.
+type FailureParser func(output string) []string
+
+// aliased is implemented by languages that are reported under more than
+// one name (e.g. TypeScript is handled by the JavaScript plugin).
+type aliased interface {
+	Aliases() []string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = builtinLanguages()
+)
+
+// RegisterLanguage adds a language plugin. Plugins are tried in
+// registration order, after the built-in ones.
+func RegisterLanguage(l Language) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, l)
+}
+
+// Languages returns the registered language plugins in detection order.
+func Languages() []Language {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Language(nil), registry...)
+}
+
+// LanguageFor returns the plugin handling the named language, or nil.
+// Matching is case-insensitive and includes aliases.
+func LanguageFor(name string) Language {
+	if name == "" {
+		return nil
+	}
+	for _, l := range Languages() {
+		if strings.EqualFold(l.Name(), name) {
+			return l
+		}
+		if a, ok := l.(aliased); ok {
+			for _, alias := range a.Aliases() {
+				if strings.EqualFold(alias, name) {
+					return l
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseFailures runs the named language's failure parser over output.
+// Returns nil when the language is unknown or has no parser.
+func ParseFailures(language, output string) []string {
+	l := LanguageFor(language)
+	if l == nil {
+		return nil
+	}
+	parse := l.FailureParser()
+	if parse == nil {
+		return nil
+	}
+	return parse(output)
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLanguageFor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Go", "Go"},
+		{"go", "Go"},
+		{"TypeScript", "JavaScript"},
+		{"Kotlin", "Java"},
+		{"Dart", "Dart/Flutter"},
+		{"Brainfuck", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		l := LanguageFor(tt.name)
+		got := ""
+		if l != nil {
+			got = l.Name()
+		}
+		if got != tt.want {
+			t.Errorf("LanguageFor(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuiltinCommands(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		snap      ProjectSnapshot
+		wantTest  string
+		wantBuild string
+	}{
+		{"go", ProjectSnapshot{Language: "Go"}, "go test ./...", "go build ./..."},
+		{"vitest", ProjectSnapshot{Language: "TypeScript", TestFrameworks: []string{"vitest"}}, "npx vitest run", "npm run build"},
+		{"django lowercase", ProjectSnapshot{Language: "Python", Frameworks: []string{"django"}}, "python manage.py test", ""},
+		{"flutter as scanned", ProjectSnapshot{Language: "Dart/Flutter", Frameworks: []string{"flutter"}}, "flutter test", "flutter build apk"},
+		{"minitest", ProjectSnapshot{Language: "Ruby", TestFrameworks: []string{"minitest"}}, "bundle exec rake test", ""},
+		{"php has no defaults", ProjectSnapshot{Language: "PHP"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := LanguageFor(tt.snap.Language)
+			if l == nil {
+				t.Fatalf("no plugin for %q", tt.snap.Language)
+			}
+			if got := l.TestCmd(&tt.snap); got != tt.wantTest {
+				t.Errorf("TestCmd() = %q, want %q", got, tt.wantTest)
+			}
+			if got := l.BuildCmd(&tt.snap); got != tt.wantBuild {
+				t.Errorf("BuildCmd() = %q, want %q", got, tt.wantBuild)
+			}
+		})
+	}
+}
+
+func TestParseFailures(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		language string
+		output   string
+		want     []string
+	}{
+		{"Go", "=== RUN   TestA\n--- FAIL: TestA (0.00s)\n    --- FAIL: TestA/sub (0.00s)\nFAIL\n", []string{"TestA", "TestA/sub"}},
+		{"TypeScript", " FAIL  src/app.test.ts > renders\n PASS  src/ok.test.ts\n", []string{"src/app.test.ts"}},
+		{"Python", "FAILED tests/test_api.py::test_get - AssertionError\n", []string{"tests/test_api.py::test_get"}},
+		{"Rust", "test parser::tests::empty ... FAILED\ntest ok ... ok\n", []string{"parser::tests::empty"}},
+		{"Ruby", "rspec ./spec/user_spec.rb:12 # User is valid\n", []string{"./spec/user_spec.rb:12"}},
+		{"Java", "anything", nil},
+		{"Unknown", "--- FAIL: TestA", nil},
+	}
+	for _, tt := range tests {
+		got := ParseFailures(tt.language, tt.output)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFailures(%q) = %v, want %v", tt.language, got, tt.want)
+		}
+	}
+}
+
+type fakeLanguage struct{}
+
+func (fakeLanguage) Name() string { return "Zig" }
+func (fakeLanguage) Detect(root string) string {
+	if _, err := os.Stat(filepath.Join(root, "build.zig")); err == nil {
+		return "Zig"
+	}
+	return ""
+}
+func (fakeLanguage) Frameworks(string) ([]string, []string) { return nil, nil }
+func (fakeLanguage) TestCmd(*ProjectSnapshot) string        { return "zig build test" }
+func (fakeLanguage) BuildCmd(*ProjectSnapshot) string       { return "zig build" }
+func (fakeLanguage) FailureParser() FailureParser           { return nil }
+
+// Not parallel: mutates the global registry.
+func TestRegisterLanguage(t *testing.T) {
+	registryMu.Lock()
+	saved := registry
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	RegisterLanguage(fakeLanguage{})
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "build.zig", "const std = @import(\"std\");\n")
+
+	lang, _, _ := detectLanguage(dir)
+	if lang != "Zig" {
+		t.Errorf("detectLanguage() = %q, want Zig", lang)
+	}
+	if l := LanguageFor("zig"); l == nil || l.TestCmd(nil) != "zig build test" {
+		t.Error("LanguageFor(zig) should return the registered plugin")
+	}
+}
@@ -0,0 +1,241 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RelevantFile is a project file picked for a task's prompt.
+type RelevantFile struct {
+	Path    string // slash-separated, relative to the project root
+	Content string // the whole file, or its declarations if it is long
+	Summary bool   // Content holds only the declarations
+}
+
+const (
+	maxRelevantCandidates = 5000    // files listed per project
+	maxRelevantFileSize   = 1 << 16 // larger files are never inlined
+	maxInlineLines        = 200     // longer files are summarized
+	maxSummaryLines       = 60
+)
+
+// relevantStopWords are task words that say nothing about which files to read.
+var relevantStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "into": true, "add": true, "new": true, "use": true, "make": true,
+	"should": true, "must": true, "all": true, "are": true, "can": true, "when": true,
+	"implement": true, "create": true, "update": true, "support": true, "test": true,
+	"tests": true, "file": true, "files": true, "code": true, "work": true, "works": true,
+}
+
+var (
+	wordRe   = regexp.MustCompile(`[A-Za-z][A-Za-z0-9]*`)
+	camelRe  = regexp.MustCompile(`[A-Z]?[a-z0-9]+|[A-Z]+(?:$|[^a-z])`)
+	importRe = regexp.MustCompile(`(?m)^\s*(?:import|from|require|use|#include)\b.*$|require\(\s*['"][^'"]+['"]\s*\)`)
+	goImport = regexp.MustCompile(`(?s)import\s*\(([^)]*)\)`)
+	quoteRe  = regexp.MustCompile(`["'<]([^"'<>\s]+)["'>]`)
+	fromRe   = regexp.MustCompile(`^\s*from\s+(\.*[\w.]*)\s+import`)
+	declRe   = regexp.MustCompile(`^(?:func|type|class|def|interface|struct|enum|trait|impl|module|export|public|pub|async def|const|var)\b`)
+	methodRe = regexp.MustCompile(`^(?:def|async def|fn|pub fn|public|private|protected|func)\b`)
+)
+
+// SourceFiles lists the source files under root that forge reads for
+// task context, slash-separated and relative to root: files with a code
+// extension other than Markdown, outside skipped and hidden directories.
+// At most maxRelevantCandidates are listed.
+func SourceFiles(root string) []string {
+	var files []string
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if len(files) >= maxRelevantCandidates {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			if p != root && SkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		if strings.HasPrefix(d.Name(), ".") || !codeExtensions[ext] || ext == ".md" {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+// keywords splits text into lowercase words of three letters or more,
+// breaking camelCase and dropping stop words.
+func keywords(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range wordRe.FindAllString(text, -1) {
+		for _, part := range append(camelRe.FindAllString(w, -1), w) {
+			part = strings.ToLower(part)
+			if len(part) >= 3 && !relevantStopWords[part] {
+				words[strings.TrimSuffix(part, "s")] = true
+			}
+		}
+	}
+	return words
+}
+
+// RelevantFiles picks up to n source files under root that a task is
+// likely to read, so their contents can go into its prompt. A file scores
+// a point for each task word in its path and five if the task names it;
+// files imported by a scoring file score a point for each such import.
+// Files that score nothing are never picked. Long files are summarized by
+// their declarations.
+func RelevantFiles(root, taskText string, n int) []RelevantFile {
+	if n <= 0 {
+		return nil
+	}
+	words := keywords(taskText)
+	lowerText := strings.ToLower(taskText)
+
+	candidates := SourceFiles(root)
+
+	scores := map[string]int{}
+	for _, c := range candidates {
+		for k := range keywords(c) {
+			if words[k] {
+				scores[c]++
+			}
+		}
+		if strings.Contains(lowerText, strings.ToLower(c)) || strings.Contains(lowerText, strings.ToLower(path.Base(c))) {
+			scores[c] += 5
+		}
+	}
+
+	// Follow the imports of the files the task points at
+	imported := map[string]int{}
+	for c, score := range scores {
+		if score == 0 {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(c)))
+		if err != nil || len(data) > maxRelevantFileSize {
+			continue
+		}
+		for _, ref := range importRefs(c, string(data)) {
+			for _, other := range candidates {
+				if other != c && importMatches(other, ref) {
+					imported[other]++
+				}
+			}
+		}
+	}
+	for c, n := range imported {
+		scores[c] += n
+	}
+
+	var ranked []string
+	for c, score := range scores {
+		if score > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	slices.SortFunc(ranked, func(a, b string) int {
+		if scores[a] != scores[b] {
+			return scores[b] - scores[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	var files []RelevantFile
+	for _, c := range ranked {
+		if len(files) == n {
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(c)))
+		if err != nil || len(data) > maxRelevantFileSize {
+			continue
+		}
+		f := RelevantFile{Path: c, Content: string(data)}
+		if strings.Count(f.Content, "\n") > maxInlineLines {
+			f.Content, f.Summary = summarize(f.Content), true
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// importRefs returns the paths a file imports, as written: module paths,
+// relative paths resolved against the file's directory, and dotted Python
+// modules turned into paths.
+func importRefs(file, content string) []string {
+	var lines []string
+	lines = append(lines, importRe.FindAllString(content, -1)...)
+	for _, block := range goImport.FindAllStringSubmatch(content, -1) {
+		lines = append(lines, block[1])
+	}
+	dir := path.Dir(file)
+	var refs []string
+	for _, line := range lines {
+		var raw []string
+		for _, m := range quoteRe.FindAllStringSubmatch(line, -1) {
+			raw = append(raw, m[1])
+		}
+		if m := fromRe.FindStringSubmatch(line); m != nil {
+			// from .models import User → ./models
+			mod := strings.TrimLeft(m[1], ".")
+			prefix := strings.Repeat("../", max(len(m[1])-len(mod)-1, 0))
+			if len(mod) < len(m[1]) {
+				prefix = "./" + prefix
+			}
+			raw = append(raw, prefix+strings.ReplaceAll(mod, ".", "/"))
+		}
+		for _, r := range raw {
+			if strings.HasPrefix(r, ".") {
+				r = path.Join(dir, r)
+			}
+			if r != "" && r != "." {
+				refs = append(refs, r)
+			}
+		}
+	}
+	return refs
+}
+
+// importMatches reports whether the project file at rel is what ref
+// imports: the file itself, with or without its extension, or a file in
+// the imported package directory. Module paths match on their suffix.
+func importMatches(rel, ref string) bool {
+	stem := strings.TrimSuffix(rel, path.Ext(rel))
+	dir := path.Dir(rel)
+	for _, p := range []string{rel, stem, dir} {
+		if p == ref || strings.HasSuffix(ref, "/"+p) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarize keeps the declaration lines of a long file: top-level
+// functions, types, classes and exports, and the methods indented one
+// level under them, so the model knows what is there without the bodies.
+func summarize(content string) string {
+	var decls []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		if (indent == 0 && declRe.MatchString(trimmed)) || (indent > 0 && indent <= 4 && methodRe.MatchString(trimmed)) {
+			decls = append(decls, strings.TrimRight(line, " {"))
+			if len(decls) == maxSummaryLines {
+				decls = append(decls, "...")
+				break
+			}
+		}
+	}
+	return strings.Join(decls, "\n")
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRelevantFiles(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                       "module example.com/shop\n",
+		"internal/orders/handler.go":   "package orders\n\nimport (\n\t\"net/http\"\n\n\t\"example.com/shop/internal/store\"\n)\n",
+		"internal/orders/orders.go":    "package orders\n",
+		"internal/store/store.go":      "package store\n\ntype Store struct{}\n",
+		"internal/users/users.go":      "package users\n",
+		"web/src/cart.ts":              "import { api } from './api'\n",
+		"web/src/api.ts":               "export const api = {}\n",
+		"README.md":                    "orders\n",
+		"node_modules/orders/index.js": "orders\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := func(fs []RelevantFile) []string {
+		var out []string
+		for _, f := range fs {
+			out = append(out, f.Path)
+		}
+		return out
+	}
+
+	got := RelevantFiles(root, "Add an order handler for listing orders", 3)
+	want := []string{"internal/orders/handler.go", "internal/orders/orders.go", "internal/store/store.go"}
+	if !reflect.DeepEqual(paths(got), want) {
+		t.Errorf("RelevantFiles = %v, want %v", paths(got), want)
+	}
+	if len(got) > 0 && got[0].Content != files["internal/orders/handler.go"] {
+		t.Errorf("content = %q", got[0].Content)
+	}
+
+	if got := paths(RelevantFiles(root, "Show totals in cart.ts", 5)); !reflect.DeepEqual(got, []string{"web/src/cart.ts", "web/src/api.ts"}) {
+		t.Errorf("named file and its import = %v", got)
+	}
+	if got := RelevantFiles(root, "Write a haiku", 5); len(got) != 0 {
+		t.Errorf("unrelated task picked %v", paths(got))
+	}
+	if got := RelevantFiles(root, "orders", 0); got != nil {
+		t.Errorf("n = 0 picked %v", paths(got))
+	}
+}
+
+func TestRelevantFiles_SummarizesLongFiles(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	body := "package billing\n\ntype Invoice struct {\n" + strings.Repeat("\tField int\n", maxInlineLines) + "}\n\nfunc (i *Invoice) Total() int {\n\tvar sum int\n\treturn sum\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "billing.go"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := RelevantFiles(root, "Fix billing totals", 1)
+	if len(got) != 1 || !got[0].Summary {
+		t.Fatalf("RelevantFiles = %+v, want one summary", got)
+	}
+	if want := "type Invoice struct\nfunc (i *Invoice) Total() int"; got[0].Content != want {
+		t.Errorf("summary = %q, want %q", got[0].Content, want)
+	}
+}
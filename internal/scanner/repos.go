@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// maxRepoDepth is how deep below the project root nested repositories are
+// looked for: "backend" and "services/billing", but nothing deeper.
+const maxRepoDepth = 2
+
+// FindRepos lists the git repositories checked out inside root, as
+// slash-separated paths relative to it — the candidates for a workspace
+// plan spanning several repos. Only clones with their own .git directory
+// count; submodules and worktrees (a .git file) belong to their parent.
+func FindRepos(root string) []string {
+	var repos []string
+	var walk func(dir, rel string, depth int)
+	walk = func(dir, rel string, depth int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() || SkipDir(e.Name()) {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			childRel := e.Name()
+			if rel != "" {
+				childRel = rel + "/" + e.Name()
+			}
+			if info, err := os.Stat(filepath.Join(path, ".git")); err == nil && info.IsDir() {
+				repos = append(repos, childRel)
+				continue
+			}
+			if depth < maxRepoDepth {
+				walk(path, childRel, depth+1)
+			}
+		}
+	}
+	walk(root, "", 1)
+	sort.Strings(repos)
+	return repos
+}
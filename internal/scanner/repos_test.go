@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindRepos(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, dir := range []string{
+		"backend/.git",
+		"services/billing/.git",
+		"services/billing/vendor/lib/.git", // inside a repo: not looked at
+		"a/b/c/.git",                       // too deep
+		"node_modules/pkg/.git",            // skipped directory
+		"docs",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a submodule's .git is a file pointing into the parent repository
+	if err := os.MkdirAll(filepath.Join(root, "third_party"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "third_party", ".git"), []byte("gitdir: ../.git/modules/third_party\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"backend", "services/billing"}
+	if got := FindRepos(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindRepos() = %q, want %q", got, want)
+	}
+}
@@ -21,6 +21,16 @@ type ProjectSnapshot struct {
 	GitDirty      bool     `json:"git_dirty"`
 	RecentCommits []string `json:"recent_commits,omitempty"`
 	KeyFiles      []string `json:"key_files,omitempty"`
+
+	TestFrameworks []string `json:"test_frameworks,omitempty"`
+	CISystems      []string `json:"ci_systems,omitempty"`
+	Services       []string `json:"services,omitempty"`
+
+	APISchemas []APISchema `json:"api_schemas,omitempty"` // OpenAPI and protobuf contracts
+	Todos      []Todo      `json:"todos,omitempty"`       // TODO/FIXME/HACK comments, offered as candidate tasks
+	Repos      []string    `json:"repos,omitempty"`       // nested git repositories a workspace plan can span
+	Submodules []string    `json:"submodules,omitempty"`  // paths from .gitmodules; never edited in place
+	Vendored   []string    `json:"vendored,omitempty"`    // vendor/, third_party/ and similar copied-in code
 }
 
 // Scan analyzes the project directory and returns a snapshot.
@@ -36,11 +46,20 @@ func Scan(root string) ProjectSnapshot {
 	}
 
 	// Scan structure
-	snap.FileCount, snap.LOC, snap.Structure, snap.KeyFiles = scanStructure(root)
+	var apiFiles, todoFiles []string
+	snap.FileCount, snap.LOC, snap.Structure, snap.KeyFiles, apiFiles, todoFiles = scanStructure(root)
+	snap.APISchemas = scanAPISchemas(root, apiFiles)
+	snap.Todos = scanTodos(root, todoFiles)
+	snap.Repos = FindRepos(root)
+	snap.Submodules = Submodules(root)
+	snap.Vendored = VendoredDirs(root)
 
 	// Detect language and frameworks
 	snap.Language, snap.Frameworks, snap.Dependencies = detectLanguage(root)
 
+	// Detect test frameworks, CI and docker-compose services
+	snap.TestFrameworks, snap.CISystems, snap.Services = detectTooling(root)
+
 	// Scan git info
 	snap.GitBranch, snap.GitDirty, snap.RecentCommits = scanGit(root)
 
@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -40,7 +41,7 @@ func TestScanStructure(t *testing.T) {
 		}
 	}
 
-	fileCount, loc, structure, keyFiles := scanStructure(root)
+	fileCount, loc, structure, keyFiles, _, _ := scanStructure(root)
 
 	// File count should match (7 files, not counting directories)
 	if fileCount != 7 {
@@ -96,7 +97,7 @@ func TestScanStructureSkipsDirs(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	fileCount, _, structure, _ := scanStructure(root)
+	fileCount, _, structure, _, _, _ := scanStructure(root)
 
 	if fileCount != 1 {
 		t.Errorf("fileCount = %d, want 1 (node_modules should be skipped)", fileCount)
@@ -459,7 +460,7 @@ func TestGitHubActionsDetection(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, _, _, keyFiles := scanStructure(root)
+	_, _, _, keyFiles, _, _ := scanStructure(root)
 
 	found := false
 	for _, kf := range keyFiles {
@@ -692,3 +693,180 @@ func runTestGit(t *testing.T, dir string, args ...string) {
 		t.Fatalf("git %v failed: %v\n%s", args, err, out)
 	}
 }
+
+func TestDetectTooling(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, "go.mod", "module example.com/test\n\nrequire github.com/stretchr/testify v1.9.0\n")
+	writeTestFile(t, dir, "package.json", `{"devDependencies": {"vitest": "^1.0.0"}}`)
+	writeTestFile(t, dir, ".gitlab-ci.yml", "stages: [test]\n")
+	os.MkdirAll(filepath.Join(dir, ".circleci"), 0755)
+	writeTestFile(t, dir, ".circleci/config.yml", "version: 2.1\n")
+	writeTestFile(t, dir, "docker-compose.yml", `version: "3"
+services:
+  db:
+    image: postgres
+    environment:
+      POSTGRES_PASSWORD: secret
+  # cache layer
+  redis:
+    image: redis
+volumes:
+  data:
+`)
+
+	testFrameworks, ciSystems, services := detectTooling(dir)
+
+	for _, want := range []string{"testify", "vitest"} {
+		if !containsStr(testFrameworks, want) {
+			t.Errorf("testFrameworks = %v, should contain %s", testFrameworks, want)
+		}
+	}
+	for _, want := range []string{"GitLab CI", "CircleCI"} {
+		if !containsStr(ciSystems, want) {
+			t.Errorf("ciSystems = %v, should contain %s", ciSystems, want)
+		}
+	}
+	if len(services) != 2 || services[0] != "db" || services[1] != "redis" {
+		t.Errorf("services = %v, want [db redis]", services)
+	}
+}
+
+func TestDetectTooling_ConfigFilesAndEmpty(t *testing.T) {
+	t.Parallel()
+
+	empty := t.TempDir()
+	tf, ci, svc := detectTooling(empty)
+	if len(tf) != 0 || len(ci) != 0 || len(svc) != 0 {
+		t.Errorf("empty dir: got %v %v %v, want nothing", tf, ci, svc)
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pytest.ini", "[pytest]\n")
+	writeTestFile(t, dir, "requirements.txt", "pytest==8.0\n")
+	os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755)
+
+	tf, ci, _ = detectTooling(dir)
+	if len(tf) != 1 || tf[0] != "pytest" {
+		t.Errorf("testFrameworks = %v, want [pytest]", tf)
+	}
+	if !containsStr(ci, "GitHub Actions") {
+		t.Errorf("ciSystems = %v, should contain GitHub Actions", ci)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	buf := make([]byte, 8) // small buffer to exercise chunk boundaries
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"empty", "", 0},
+		{"single line no newline", "hello", 1},
+		{"trailing newline", "a\nb\n", 2},
+		{"no trailing newline", "a\nb\nc", 3},
+		{"long line", strings.Repeat("x", 200000) + "\nend\n", 2},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("f%d.txt", i))
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := countLines(path, buf); got != tt.want {
+			t.Errorf("%s: countLines = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWalkProject_ManyDirs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	for i := 0; i < 20; i++ {
+		writeTestFile(t, dir, fmt.Sprintf("pkg%02d/sub/file.go", i), "package sub\n\nfunc F() {}\n")
+	}
+	writeTestFile(t, dir, "deploy/Dockerfile", "FROM scratch\n")
+	writeTestFile(t, dir, "node_modules/lib/index.js", "module.exports = {}\n")
+
+	fileCount, loc, keyFiles, _, _ := walkProject(dir)
+
+	if fileCount != 21 {
+		t.Errorf("fileCount = %d, want 21", fileCount)
+	}
+	if loc != 60 {
+		t.Errorf("loc = %d, want 60", loc)
+	}
+	if len(keyFiles) != 1 || keyFiles[0] != "deploy/Dockerfile" {
+		t.Errorf("keyFiles = %v, want [deploy/Dockerfile]", keyFiles)
+	}
+}
+
+func TestScanCached(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Parallel()
+
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeTestFile(t, root, "main.go", "package main\n")
+	for _, c := range [][]string{
+		{"git", "init"},
+		{"git", "config", "user.email", "test@test.com"},
+		{"git", "config", "user.name", "Test User"},
+		{"git", "add", "."},
+		{"git", "commit", "-m", "initial commit"},
+	} {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("command %v failed: %v\n%s", c, err, out)
+		}
+	}
+
+	first := scanCached(root, cacheDir)
+	if first.Language != "" || first.FileCount != 1 {
+		t.Fatalf("unexpected first scan: %+v", first)
+	}
+
+	// Tamper with the cache entry: an unchanged HEAD must return it verbatim.
+	path := filepath.Join(cacheDir, snapshotCacheFile(root))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+	data = []byte(strings.Replace(string(data), `"file_count":1`, `"file_count":99`, 1))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := scanCached(root, cacheDir); got.FileCount != 99 {
+		t.Errorf("FileCount = %d, want cached value 99", got.FileCount)
+	}
+
+	// A worktree change invalidates the key.
+	writeTestFile(t, root, "extra.go", "package main\n")
+	if got := scanCached(root, cacheDir); got.FileCount != 2 {
+		t.Errorf("FileCount after change = %d, want 2", got.FileCount)
+	}
+}
+
+func TestScanCached_NonGitFallsBack(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	cacheDir := t.TempDir()
+	writeTestFile(t, root, "main.go", "package main\n")
+
+	snap := scanCached(root, cacheDir)
+	if !snap.IsExisting {
+		t.Error("should still scan non-git directories")
+	}
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 0 {
+		t.Errorf("cache dir should stay empty outside git, got %d entries", len(entries))
+	}
+}
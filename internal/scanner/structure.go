@@ -1,7 +1,6 @@
 package scanner
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +16,12 @@ var skipDirs = map[string]bool{
 	".next": true, ".nuxt": true, "coverage": true,
 }
 
+// SkipDir reports whether the project walk ignores a directory: VCS,
+// dependency and build output directories, and hidden ones except .github.
+func SkipDir(name string) bool {
+	return skipDirs[name] || (strings.HasPrefix(name, ".") && name != ".github")
+}
+
 // File extensions counted for LOC estimation.
 var codeExtensions = map[string]bool{
 	".go": true, ".py": true, ".js": true, ".ts": true, ".jsx": true, ".tsx": true,
@@ -43,8 +48,9 @@ const showEntriesPerDir = 10
 const maxTreeLines = 100
 
 // scanStructure walks the directory tree and produces file counts, LOC estimate,
-// a tree string (depth 3), and key files found.
-func scanStructure(root string) (fileCount int, loc int, structure string, keyFiles []string) {
+// a tree string (depth 3), key files, API schema files and files with
+// TODO-style markers found.
+func scanStructure(root string) (fileCount int, loc int, structure string, keyFiles, apiFiles, todoFiles []string) {
 	type entry struct {
 		name  string
 		isDir bool
@@ -113,68 +119,8 @@ func scanStructure(root string) (fileCount int, loc int, structure string, keyFi
 		return lines
 	}
 
-	// Walk for file count, LOC, and key files
-	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil // skip errors
-		}
-
-		name := d.Name()
-
-		if d.IsDir() {
-			if path != root && (skipDirs[name] || (strings.HasPrefix(name, ".") && name != ".github")) {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip hidden files
-		if strings.HasPrefix(name, ".") {
-			return nil
-		}
-
-		fileCount++
-
-		// Key files detection
-		if keyFileNames[name] {
-			rel, _ := filepath.Rel(root, path)
-			keyFiles = append(keyFiles, filepath.ToSlash(rel))
-		}
-
-		// Check for GitHub Actions
-		rel, _ := filepath.Rel(root, path)
-		relSlash := filepath.ToSlash(rel)
-		if strings.HasPrefix(relSlash, ".github/workflows/") && strings.HasSuffix(name, ".yml") {
-			keyFiles = append(keyFiles, "GitHub Actions CI found")
-		}
-
-		// LOC counting
-		ext := strings.ToLower(filepath.Ext(name))
-		if !codeExtensions[ext] {
-			return nil
-		}
-
-		info, err := d.Info()
-		if err != nil || info.Size() > maxLOCFileSize {
-			return nil
-		}
-
-		f, err := os.Open(path)
-		if err != nil {
-			return nil
-		}
-		defer f.Close()
-
-		scanner := bufio.NewScanner(f)
-		for scanner.Scan() {
-			loc++
-		}
-
-		return nil
-	})
-
-	// Deduplicate key files (GitHub Actions may appear multiple times)
-	keyFiles = dedup(keyFiles)
+	// Walk for file count, LOC, key files, API schemas and TODO markers
+	fileCount, loc, keyFiles, apiFiles, todoFiles = walkProject(root)
 
 	// Build tree
 	treeLines := buildTree(root, 0)
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	maxTodos     = 200 // collected per project
+	maxTodosShow = 40  // listed in the planning prompt
+	maxTodoText  = 120
+)
+
+// Todo is a TODO, FIXME or HACK comment left in the code, offered to the
+// planner as a candidate task.
+type Todo struct {
+	Path string `json:"path"` // relative to the project root
+	Line int    `json:"line"`
+	Kind string `json:"kind"` // "TODO", "FIXME" or "HACK"
+	Text string `json:"text,omitempty"`
+}
+
+// String renders "internal/x.go:42 FIXME: handle timeouts".
+func (t Todo) String() string {
+	if t.Text == "" {
+		return fmt.Sprintf("%s:%d %s", t.Path, t.Line, t.Kind)
+	}
+	return fmt.Sprintf("%s:%d %s: %s", t.Path, t.Line, t.Kind, t.Text)
+}
+
+var (
+	todoMarkers = [][]byte{[]byte("TODO"), []byte("FIXME"), []byte("HACK")}
+
+	// A marker must follow a comment opener on the same line, so
+	// identifiers like todoList or a "TODO" string literal don't count.
+	todoRe = regexp.MustCompile(`(?://|#|/\*|^\s*\*|--|<!--|;)\s*(TODO|FIXME|HACK)\b(?:\([^)]*\))?[:\s-]*(.*)$`)
+	// Trailing comment closers
+	todoCloserRe = regexp.MustCompile(`\s*(\*/|-->)\s*$`)
+)
+
+// hasTodoMarker is the cheap pre-check countLines runs on each chunk.
+func hasTodoMarker(b []byte) bool {
+	for _, m := range todoMarkers {
+		if bytes.Contains(b, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanTodos reads the marked files found by the walk and returns their
+// TODO comments, ordered by path and line. FIXMEs are kept ahead of TODOs
+// and HACKs when the project has more than maxTodos.
+func scanTodos(root string, files []string) []Todo {
+	sort.Strings(files)
+	var todos []Todo
+	for _, rel := range files {
+		todos = append(todos, fileTodos(filepath.Join(root, filepath.FromSlash(rel)), rel)...)
+	}
+	if len(todos) > maxTodos {
+		sort.SliceStable(todos, func(i, j int) bool {
+			return todos[i].Kind == "FIXME" && todos[j].Kind != "FIXME"
+		})
+		todos = todos[:maxTodos]
+		sort.SliceStable(todos, func(i, j int) bool {
+			if todos[i].Path != todos[j].Path {
+				return todos[i].Path < todos[j].Path
+			}
+			return todos[i].Line < todos[j].Line
+		})
+	}
+	return todos
+}
+
+func fileTodos(path, rel string) []Todo {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var todos []Todo
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), maxLOCFileSize)
+	for n := 1; sc.Scan(); n++ {
+		if t, ok := ParseTodo(sc.Text()); ok {
+			t.Path, t.Line = rel, n
+			todos = append(todos, t)
+		}
+	}
+	return todos
+}
+
+// ParseTodo extracts the marker and text from a source line, if it holds
+// a TODO, FIXME or HACK comment.
+func ParseTodo(line string) (Todo, bool) {
+	m := todoRe.FindStringSubmatch(line)
+	if m == nil {
+		return Todo{}, false
+	}
+	text := strings.TrimSpace(todoCloserRe.ReplaceAllString(m[2], ""))
+	if r := []rune(text); len(r) > maxTodoText {
+		text = strings.TrimSpace(string(r[:maxTodoText])) + "…"
+	}
+	return Todo{Kind: m[1], Text: text}, true
+}
+
+// FormatTodos lists up to maxTodosShow comments, one per line, noting how
+// many were left out.
+func FormatTodos(todos []Todo) string {
+	var b strings.Builder
+	for i, t := range todos {
+		if i == maxTodosShow {
+			fmt.Fprintf(&b, "  … and %d more\n", len(todos)-maxTodosShow)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", t)
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTodo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line   string
+		want   Todo
+		wantOK bool
+	}{
+		{line: "\t// TODO: handle timeouts", want: Todo{Kind: "TODO", Text: "handle timeouts"}, wantOK: true},
+		{line: "x := f() // FIXME(alice) leaks on error", want: Todo{Kind: "FIXME", Text: "leaks on error"}, wantOK: true},
+		{line: "# HACK - pin until upstream fix", want: Todo{Kind: "HACK", Text: "pin until upstream fix"}, wantOK: true},
+		{line: "/* TODO remove */", want: Todo{Kind: "TODO", Text: "remove"}, wantOK: true},
+		{line: " * TODO: document", want: Todo{Kind: "TODO", Text: "document"}, wantOK: true},
+		{line: "<!-- FIXME: alt text -->", want: Todo{Kind: "FIXME", Text: "alt text"}, wantOK: true},
+		{line: "-- TODO", want: Todo{Kind: "TODO"}, wantOK: true},
+		{line: `todos := []string{"TODO"}`},
+		{line: "func TODOList() {}"},
+		{line: "// TODOS are tracked elsewhere"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseTodo(tt.line)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseTodo(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseTodo_TruncatesLongText(t *testing.T) {
+	t.Parallel()
+	got, _ := ParseTodo("// TODO: " + strings.Repeat("é", 300))
+	if n := len([]rune(got.Text)); n != maxTodoText+1 || !strings.HasSuffix(got.Text, "…") {
+		t.Errorf("Text has %d runes, want %d ending in …", n, maxTodoText+1)
+	}
+}
+
+func TestCountLines_DetectsMarkersAcrossReads(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	buf := make([]byte, 8)
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{content: "package x\n// nothing here\n", want: false},
+		{content: "1234567FIXME\n", want: true}, // split across the first two reads
+		{content: "abcdefTODO\n", want: true},
+		{content: "abcdefgHAC\nK\n", want: false},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(dir, string(rune('a'+i))+".go")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, got := countLines(path, buf); got != tt.want {
+			t.Errorf("countLines(%q) marked = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestScan_FindsTodos(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.go", "package main\n\n// TODO: read config from env\nfunc main() {}\n")
+	writeTestFile(t, dir, "lib/db.py", "def connect():\n    pass  # FIXME: retry on failure\n")
+	writeTestFile(t, dir, "notes.txt", "TODO: not code\n")
+	writeTestFile(t, dir, "node_modules/x/index.js", "// TODO: vendored\n")
+
+	snap := Scan(dir)
+	want := []Todo{
+		{Path: "lib/db.py", Line: 2, Kind: "FIXME", Text: "retry on failure"},
+		{Path: "main.go", Line: 3, Kind: "TODO", Text: "read config from env"},
+	}
+	if !reflect.DeepEqual(snap.Todos, want) {
+		t.Errorf("Todos = %+v, want %+v", snap.Todos, want)
+	}
+	if got := FormatTodos(snap.Todos); got != "  lib/db.py:2 FIXME: retry on failure\n  main.go:3 TODO: read config from env\n" {
+		t.Errorf("FormatTodos() = %q", got)
+	}
+}
+
+func TestScanTodos_KeepsFixmesWhenCapped(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	var b strings.Builder
+	for range maxTodos {
+		b.WriteString("// TODO: later\n")
+	}
+	b.WriteString("// FIXME: broken\n")
+	writeTestFile(t, dir, "a.go", b.String())
+
+	todos := scanTodos(dir, []string{"a.go"})
+	if len(todos) != maxTodos {
+		t.Fatalf("got %d todos, want %d", len(todos), maxTodos)
+	}
+	if last := todos[len(todos)-1]; last.Kind != "FIXME" || last.Line != maxTodos+1 {
+		t.Errorf("last todo = %+v, want the FIXME on line %d", last, maxTodos+1)
+	}
+}
@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Test frameworks detected by substring match in manifest files.
+var testFrameworkMarkers = []struct {
+	file      string
+	marker    string
+	framework string
+}{
+	{"go.mod", "github.com/stretchr/testify", "testify"},
+	{"go.mod", "github.com/onsi/ginkgo", "ginkgo"},
+	{"package.json", `"vitest"`, "vitest"},
+	{"package.json", `"jest"`, "jest"},
+	{"package.json", `"mocha"`, "mocha"},
+	{"package.json", `"@playwright/test"`, "playwright"},
+	{"package.json", `"cypress"`, "cypress"},
+	{"requirements.txt", "pytest", "pytest"},
+	{"requirements-dev.txt", "pytest", "pytest"},
+	{"pyproject.toml", "pytest", "pytest"},
+	{"Gemfile", "rspec", "rspec"},
+	{"Gemfile", "minitest", "minitest"},
+}
+
+// Config files that imply a test framework on their own.
+var testFrameworkFiles = map[string]string{
+	"pytest.ini":           "pytest",
+	"conftest.py":          "pytest",
+	"jest.config.js":       "jest",
+	"jest.config.ts":       "jest",
+	"vitest.config.ts":     "vitest",
+	"vitest.config.js":     "vitest",
+	".rspec":               "rspec",
+	"playwright.config.ts": "playwright",
+}
+
+// CI systems keyed by the path that identifies them.
+var ciMarkers = []struct {
+	path   string
+	system string
+}{
+	{".github/workflows", "GitHub Actions"},
+	{".gitlab-ci.yml", "GitLab CI"},
+	{".circleci/config.yml", "CircleCI"},
+	{"Jenkinsfile", "Jenkins"},
+	{"azure-pipelines.yml", "Azure Pipelines"},
+	{".travis.yml", "Travis CI"},
+	{"bitbucket-pipelines.yml", "Bitbucket Pipelines"},
+}
+
+var composeFiles = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// detectTooling identifies test frameworks, CI systems and docker-compose
+// services in the project root.
+func detectTooling(root string) (testFrameworks, ciSystems, services []string) {
+	manifests := make(map[string]string)
+	for _, m := range testFrameworkMarkers {
+		content, ok := manifests[m.file]
+		if !ok {
+			content = readFileFull(root, m.file)
+			manifests[m.file] = content
+		}
+		if content != "" && strings.Contains(content, m.marker) {
+			testFrameworks = append(testFrameworks, m.framework)
+		}
+	}
+	for file, fw := range testFrameworkFiles {
+		if _, err := os.Stat(filepath.Join(root, file)); err == nil {
+			testFrameworks = append(testFrameworks, fw)
+		}
+	}
+
+	for _, c := range ciMarkers {
+		if _, err := os.Stat(filepath.Join(root, c.path)); err == nil {
+			ciSystems = append(ciSystems, c.system)
+		}
+	}
+
+	for _, name := range composeFiles {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err == nil {
+			services = append(services, parseComposeServices(path)...)
+		}
+	}
+
+	return sortedDedup(testFrameworks), ciSystems, dedup(services)
+}
+
+// parseComposeServices extracts the service names from a docker-compose file.
+// Only the keys directly under the top-level "services:" block are returned.
+func parseComposeServices(path string) []string {
+	var services []string
+	inServices := false
+	indent := ""
+
+	for _, line := range readLines(path, 500) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		topLevel := !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t")
+		if topLevel {
+			inServices = trimmed == "services:"
+			indent = ""
+			continue
+		}
+		if !inServices {
+			continue
+		}
+
+		lineIndent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent == "" {
+			indent = lineIndent
+		}
+		if lineIndent != indent || !strings.HasSuffix(trimmed, ":") {
+			continue
+		}
+		services = append(services, strings.TrimSuffix(trimmed, ":"))
+	}
+
+	return services
+}
+
+// sortedDedup removes duplicates and sorts, since map iteration order is random.
+func sortedDedup(items []string) []string {
+	result := dedup(items)
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// vendorDirNames are directories that conventionally hold copied-in
+// third-party code.
+var vendorDirNames = []string{"vendor", "third_party", "third-party", "external"}
+
+// Submodules lists the submodule paths declared in root's .gitmodules,
+// slash-separated and relative to root.
+func Submodules(root string) []string {
+	f, err := os.Open(filepath.Join(root, ".gitmodules"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var paths []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(sc.Text()), "=")
+		if !ok || strings.TrimSpace(key) != "path" {
+			continue
+		}
+		if p := strings.Trim(strings.TrimSpace(value), `"`); p != "" {
+			paths = append(paths, strings.TrimSuffix(filepath.ToSlash(p), "/"))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// VendoredDirs lists the top-level vendored dependency directories in root
+// (vendor/, third_party/, ...) that hold anything.
+func VendoredDirs(root string) []string {
+	var dirs []string
+	for _, name := range vendorDirNames {
+		entries, err := os.ReadDir(filepath.Join(root, name))
+		if err == nil && len(entries) > 0 {
+			dirs = append(dirs, name)
+		}
+	}
+	return dirs
+}
+
+// ReadOnlyPaths is every submodule and vendored directory in the snapshot:
+// code that tasks must not edit in place.
+func (s ProjectSnapshot) ReadOnlyPaths() []string {
+	paths := append([]string(nil), s.Submodules...)
+	for _, dir := range s.Vendored {
+		if !slices.Contains(paths, dir) {
+			paths = append(paths, dir)
+		}
+	}
+	return paths
+}
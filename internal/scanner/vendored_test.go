@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSubmodules(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	gitmodules := `[submodule "proto"]
+	path = api/proto
+	url = git@github.com:o/proto.git
+[submodule "libfoo"]
+	path = "third_party/libfoo/"
+	url = https://github.com/o/libfoo
+`
+	if err := os.WriteFile(filepath.Join(root, ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"api/proto", "third_party/libfoo"}
+	if got := Submodules(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("Submodules() = %v, want %v", got, want)
+	}
+	if got := Submodules(t.TempDir()); got != nil {
+		t.Errorf("Submodules() without .gitmodules = %v, want nil", got)
+	}
+}
+
+func TestVendoredDirs(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "vendor", "github.com"), 0755)
+	os.MkdirAll(filepath.Join(root, "external"), 0755) // empty: not vendored code
+	os.MkdirAll(filepath.Join(root, "internal"), 0755)
+
+	if got, want := VendoredDirs(root), []string{"vendor"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VendoredDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestReadOnlyPaths(t *testing.T) {
+	t.Parallel()
+	snap := ProjectSnapshot{Submodules: []string{"vendor", "libs/proto"}, Vendored: []string{"vendor", "third_party"}}
+	if got, want := snap.ReadOnlyPaths(), []string{"vendor", "libs/proto", "third_party"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadOnlyPaths() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxWalkEntriesPerDir bounds how many entries are read from a single
+// directory, so generated or vendored dirs with huge fan-out stay cheap.
+const maxWalkEntriesPerDir = 5000
+
+// scanWorkers is the number of directories processed concurrently.
+var scanWorkers = max(runtime.NumCPU(), 4)
+
+var locBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 32*1024)
+		return &buf
+	},
+}
+
+// walkProject counts files, estimates LOC and collects key files, API
+// schema files and source files containing TODO-style markers using a
+// bounded pool of workers, one directory at a time per worker.
+func walkProject(root string) (fileCount int, loc int, keyFiles, apiFiles, todoFiles []string) {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, scanWorkers)
+	)
+
+	var visit func(dir string)
+	visit = func(dir string) {
+		defer wg.Done()
+
+		sem <- struct{}{}
+		files, lines, keys, apis, todos, subdirs := scanDir(root, dir)
+		<-sem
+
+		mu.Lock()
+		fileCount += files
+		loc += lines
+		keyFiles = append(keyFiles, keys...)
+		apiFiles = append(apiFiles, apis...)
+		todoFiles = append(todoFiles, todos...)
+		mu.Unlock()
+
+		for _, sub := range subdirs {
+			wg.Add(1)
+			go visit(sub)
+		}
+	}
+
+	wg.Add(1)
+	visit(root)
+	wg.Wait()
+
+	// Walk order is nondeterministic; sort before deduplicating
+	// (GitHub Actions may appear multiple times).
+	sort.Strings(keyFiles)
+	keyFiles = dedup(keyFiles)
+	return
+}
+
+// scanDir processes the files directly inside dir and returns the
+// subdirectories that should be visited next.
+func scanDir(root, dir string) (fileCount int, loc int, keyFiles, apiFiles, todoFiles, subdirs []string) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	entries, _ := f.ReadDir(maxWalkEntriesPerDir)
+	f.Close()
+
+	bufp := locBufPool.Get().(*[]byte)
+	defer locBufPool.Put(bufp)
+
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(dir, name)
+
+		if e.IsDir() {
+			if SkipDir(name) {
+				continue
+			}
+			subdirs = append(subdirs, path)
+			continue
+		}
+
+		// Skip hidden files
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		fileCount++
+
+		// Key files detection
+		if keyFileNames[name] {
+			rel, _ := filepath.Rel(root, path)
+			keyFiles = append(keyFiles, filepath.ToSlash(rel))
+		}
+
+		// Check for GitHub Actions
+		rel, _ := filepath.Rel(root, path)
+		relSlash := filepath.ToSlash(rel)
+		if isAPISchemaFile(name) {
+			apiFiles = append(apiFiles, relSlash)
+		}
+		if strings.HasPrefix(relSlash, ".github/workflows/") && strings.HasSuffix(name, ".yml") {
+			keyFiles = append(keyFiles, "GitHub Actions CI found")
+		}
+
+		// LOC counting
+		ext := strings.ToLower(filepath.Ext(name))
+		if !codeExtensions[ext] {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil || info.Size() > maxLOCFileSize {
+			continue
+		}
+
+		lines, marked := countLines(path, *bufp)
+		loc += lines
+		if marked {
+			todoFiles = append(todoFiles, relSlash)
+		}
+	}
+
+	return
+}
+
+// countLines streams the file through buf and counts lines, including a
+// final line without a trailing newline. Unlike bufio.Scanner it does not
+// give up on very long lines. marked reports whether the file contains a
+// TODO, FIXME or HACK marker, so only those files are read again for them.
+func countLines(path string, buf []byte) (lines int, marked bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	var last byte
+	var carry []byte // tail of the previous chunk, for markers split across reads
+	seen := false
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			lines += bytes.Count(chunk, []byte{'\n'})
+			if !marked {
+				marked = hasTodoMarker(chunk) || hasTodoMarker(append(carry, chunk[:min(n, 4)]...))
+				carry = append(carry[:0], chunk[max(n-4, 0):]...)
+			}
+			last = buf[n-1]
+			seen = true
+		}
+		if err != nil {
+			break
+		}
+	}
+	if seen && last != '\n' {
+		lines++
+	}
+	return lines, marked
+}
@@ -0,0 +1,106 @@
+// Package schedule resolves and waits for delayed execution start times,
+// so token-expensive runs can start unattended (e.g. overnight), and
+// describes the daily quiet hours in which no task may start.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Parse resolves a start time. It accepts "HH:MM" (the next occurrence in
+// now's location, today or tomorrow), "+<duration>" relative to now
+// (e.g. "+90m"), or an RFC 3339 timestamp.
+func Parse(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty start time")
+	}
+
+	if strings.HasPrefix(value, "+") {
+		d, err := time.ParseDuration(value[1:])
+		if err != nil || d < 0 {
+			return time.Time{}, fmt.Errorf("invalid delay %q (use e.g. +2h30m)", value)
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q (use HH:MM, +duration or RFC 3339)", value)
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// Wait blocks until t or until ctx is done, returning ctx.Err() in the
+// latter case. A time in the past returns immediately.
+func Wait(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Window is a daily time range, such as quiet hours. End before Start
+// wraps past midnight ("22:00-07:00").
+type Window struct {
+	Start, End int // minutes after midnight
+}
+
+// ParseWindow parses "HH:MM-HH:MM".
+func ParseWindow(value string) (Window, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid window %q (use HH:MM-HH:MM, e.g. 22:00-07:00)", value)
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(from))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(to))
+	if err1 != nil || err2 != nil {
+		return Window{}, fmt.Errorf("invalid window %q (use HH:MM-HH:MM, e.g. 22:00-07:00)", value)
+	}
+	w := Window{Start: start.Hour()*60 + start.Minute(), End: end.Hour()*60 + end.Minute()}
+	if w.Start == w.End {
+		return Window{}, fmt.Errorf("window %q is empty", value)
+	}
+	return w, nil
+}
+
+// Contains reports whether t's wall-clock time falls inside the window.
+func (w Window) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+// NextEnd returns the first time after t at which the window ends.
+func (w Window) NextEnd(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), w.End/60, w.End%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
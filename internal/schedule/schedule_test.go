@@ -0,0 +1,119 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("test", 2*3600)
+	now := time.Date(2026, 3, 10, 22, 15, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"later today", "23:30", time.Date(2026, 3, 10, 23, 30, 0, 0, loc), false},
+		{"tomorrow when already past", "02:00", time.Date(2026, 3, 11, 2, 0, 0, 0, loc), false},
+		{"same minute rolls over", "22:15", time.Date(2026, 3, 11, 22, 15, 0, 0, loc), false},
+		{"relative delay", "+90m", now.Add(90 * time.Minute), false},
+		{"rfc3339", "2026-03-12T01:00:00Z", time.Date(2026, 3, 12, 1, 0, 0, 0, time.UTC), false},
+		{"trims spaces", " 23:30 ", time.Date(2026, 3, 10, 23, 30, 0, 0, loc), false},
+		{"bad clock", "25:00", time.Time{}, true},
+		{"bad delay", "+soon", time.Time{}, true},
+		{"negative delay", "+-1h", time.Time{}, true},
+		{"empty", "", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	if err := Wait(context.Background(), time.Now().Add(-time.Hour)); err != nil {
+		t.Errorf("past time: err = %v, want nil", err)
+	}
+	if err := Wait(context.Background(), time.Now().Add(10*time.Millisecond)); err != nil {
+		t.Errorf("short wait: err = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Wait(ctx, time.Now().Add(time.Hour)); err != context.Canceled {
+		t.Errorf("cancelled wait: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value   string
+		want    Window
+		wantErr bool
+	}{
+		{value: "09:00-18:00", want: Window{Start: 540, End: 1080}},
+		{value: " 22:00 - 07:30 ", want: Window{Start: 1320, End: 450}},
+		{value: "22:00", wantErr: true},
+		{value: "22:00-25:00", wantErr: true},
+		{value: "08:00-08:00", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWindow(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseWindow(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+	at := func(day, h, m int) time.Time { return time.Date(2026, 3, day, h, m, 0, 0, time.UTC) }
+	overnight := Window{Start: 22 * 60, End: 7 * 60}
+	office := Window{Start: 9 * 60, End: 18 * 60}
+
+	tests := []struct {
+		name     string
+		w        Window
+		t        time.Time
+		contains bool
+		nextEnd  time.Time
+	}{
+		{"overnight before midnight", overnight, at(10, 23, 0), true, at(11, 7, 0)},
+		{"overnight after midnight", overnight, at(11, 3, 0), true, at(11, 7, 0)},
+		{"overnight end is outside", overnight, at(11, 7, 0), false, at(12, 7, 0)},
+		{"overnight daytime", overnight, at(10, 12, 0), false, at(11, 7, 0)},
+		{"office start is inside", office, at(10, 9, 0), true, at(10, 18, 0)},
+		{"office evening", office, at(10, 20, 0), false, at(11, 18, 0)},
+	}
+	for _, tt := range tests {
+		if got := tt.w.Contains(tt.t); got != tt.contains {
+			t.Errorf("%s: Contains() = %v, want %v", tt.name, got, tt.contains)
+		}
+		if got := tt.w.NextEnd(tt.t); !got.Equal(tt.nextEnd) {
+			t.Errorf("%s: NextEnd() = %v, want %v", tt.name, got, tt.nextEnd)
+		}
+	}
+	if got := overnight.String(); got != "22:00-07:00" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,586 @@
+// Package server exposes forge's plan and execution operations over a local
+// JSON-RPC 2.0 socket (`forge serve`), so editor extensions can show task
+// status inline, start and stop runs and stream their events without
+// scraping the TUI. Messages are framed like LSP, with a Content-Length
+// header, so existing JSON-RPC client libraries work unchanged.
+//
+// The plan is always read from and written to .forge/state.json; a run
+// started here, or by a forge TUI, picks up edits before its next task.
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/taskcmd"
+)
+
+// SocketFile is the default socket, relative to the .forge directory.
+const SocketFile = "forge.sock"
+
+// SocketPath returns the default socket path for a project.
+func SocketPath(root string) string {
+	return filepath.Join(state.ForgeDir(root), SocketFile)
+}
+
+// ExecuteFunc runs the plan in s until it finishes, fails or ctx is
+// cancelled, reporting events through onEvent and reading answers to
+// manual tasks from confirm. forge serve backs it with the real runner.
+type ExecuteFunc func(ctx context.Context, s *state.State, onEvent func(executor.TaskEvent), confirm <-chan executor.ManualConfirmation) error
+
+// Server answers JSON-RPC calls on any number of connections and
+// broadcasts run notifications to all of them.
+type Server struct {
+	root    string
+	execute ExecuteFunc
+
+	mu      sync.Mutex
+	conns   map[*conn]struct{}
+	cancel  context.CancelFunc // non-nil while a run is in progress
+	done    chan struct{}      // closed when the current run ends
+	current string             // task the run is working on
+	waiting string             // manual task waiting for task/confirm
+	confirm chan executor.ManualConfirmation
+}
+
+// New creates a server for the project in root.
+func New(root string, execute ExecuteFunc) *Server {
+	return &Server{root: root, execute: execute, conns: map[*conn]struct{}{}}
+}
+
+// Listen opens a Unix socket at path. A leftover socket nobody answers on
+// is replaced; a live one means another forge serve owns it.
+func Listen(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			c.Close()
+			return nil, fmt.Errorf("another forge serve is listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// Serve accepts connections until ctx is cancelled, then stops any run it
+// started and waits for it to wind down.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	defer s.stopRun()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.ServeConn(ctx, c)
+	}
+}
+
+// ServeConn answers calls on one connection until it closes.
+func (s *Server) ServeConn(ctx context.Context, rwc io.ReadWriteCloser) {
+	c := newConn(rwc)
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	r := bufio.NewReader(rwc)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				c.send(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			}
+			return
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			c.send(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+		result, err := s.call(req)
+		if len(req.ID) == 0 {
+			continue // notifications get no reply
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result}
+		if err != nil {
+			var rerr *rpcError
+			if !errors.As(err, &rerr) {
+				rerr = &rpcError{Code: codeFailed, Message: err.Error()}
+			}
+			resp.Result, resp.Error = nil, rerr
+		} else if result == nil {
+			resp.Result = struct{}{}
+		}
+		c.send(resp)
+	}
+}
+
+const (
+	// outboxSize bounds the messages waiting to be written to one client.
+	// A client that falls this far behind is disconnected, so one that
+	// stops reading can't stall the run whose events it is sent.
+	outboxSize = 256
+
+	// closeTimeout bounds how long a closing connection waits for its
+	// queued messages to be written.
+	closeTimeout = 5 * time.Second
+)
+
+// conn queues messages for one client; a single writer goroutine sends
+// them in order.
+type conn struct {
+	rwc  io.ReadWriteCloser
+	done chan struct{} // closed when the writer has finished
+
+	mu  sync.Mutex
+	out chan any // nil once the connection stops taking messages
+}
+
+func newConn(rwc io.ReadWriteCloser) *conn {
+	c := &conn{rwc: rwc, done: make(chan struct{}), out: make(chan any, outboxSize)}
+	go c.write(c.out)
+	return c
+}
+
+// write sends queued messages until out is closed. After a failed write
+// the connection is closed, which ends ServeConn, and the rest of the
+// queue is discarded.
+func (c *conn) write(out <-chan any) {
+	defer close(c.done)
+	failed := false
+	for v := range out {
+		if failed {
+			continue
+		}
+		if err := writeMessage(c.rwc, v); err != nil {
+			log.Printf("forge serve: writing to a client: %v", err)
+			failed = true
+			c.rwc.Close()
+		}
+	}
+}
+
+// send queues v for the client without blocking. A client whose queue is
+// full is disconnected.
+func (c *conn) send(v any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.out == nil {
+		return
+	}
+	select {
+	case c.out <- v:
+	default:
+		log.Printf("forge serve: a client fell %d messages behind; disconnecting it", outboxSize)
+		close(c.out)
+		c.out = nil
+		c.rwc.Close()
+	}
+}
+
+// close stops taking messages, gives the queued ones closeTimeout to be
+// written and closes the connection.
+func (c *conn) close() {
+	c.mu.Lock()
+	if c.out != nil {
+		close(c.out)
+		c.out = nil
+	}
+	c.mu.Unlock()
+	select {
+	case <-c.done:
+	case <-time.After(closeTimeout):
+	}
+	c.rwc.Close()
+}
+
+// notify sends a notification to every connected client.
+func (s *Server) notify(method string, params any) {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+	for _, c := range conns {
+		c.send(notification{JSONRPC: "2.0", Method: method, Params: params})
+	}
+}
+
+// ============================================================
+// Methods
+// ============================================================
+
+// Status is the result of forge/status.
+type Status struct {
+	Phase       state.Phase    `json:"phase"`
+	PlanVersion int            `json:"plan_version"`
+	Tasks       map[string]int `json:"tasks"` // count per status
+	Running     bool           `json:"running"`
+	CurrentTask string         `json:"current_task,omitempty"`
+	WaitingFor  string         `json:"waiting_for,omitempty"` // manual task to answer with task/confirm
+}
+
+// taskFields are the task fields task/add and task/edit accept. Absent
+// fields are left alone.
+type taskFields struct {
+	Title              *string          `json:"title"`
+	Description        *string          `json:"description"`
+	Complexity         *string          `json:"complexity"`
+	Type               *state.TaskType  `json:"type"`
+	Owner              *state.TaskOwner `json:"owner"`
+	Assignee           *string          `json:"assignee"`
+	AcceptanceCriteria *[]string        `json:"acceptance_criteria"`
+	DependsOn          *[]string        `json:"depends_on"`
+	Commands           *[]string        `json:"commands"`
+	Artifacts          *[]string        `json:"artifacts"`
+}
+
+func (f taskFields) apply(t *state.Task) {
+	setIf(&t.Title, f.Title)
+	setIf(&t.Description, f.Description)
+	setIf(&t.Complexity, f.Complexity)
+	setIf(&t.Type, f.Type)
+	setIf(&t.Owner, f.Owner)
+	setIf(&t.Assignee, f.Assignee)
+	setIf(&t.AcceptanceCriteria, f.AcceptanceCriteria)
+	setIf(&t.DependsOn, f.DependsOn)
+	setIf(&t.Commands, f.Commands)
+	setIf(&t.Artifacts, f.Artifacts)
+	if t.Owner == state.OwnerAgent {
+		t.Owner = ""
+	}
+}
+
+func setIf[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+type idParams struct {
+	ID string `json:"id"`
+}
+
+func (s *Server) call(req request) (any, error) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "expected a JSON-RPC 2.0 request"}
+	}
+
+	switch req.Method {
+	case "forge/status":
+		st, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		status := Status{Phase: st.Phase, PlanVersion: st.PlanVersion, Tasks: map[string]int{}}
+		for _, t := range st.Tasks {
+			status.Tasks[string(t.Status)]++
+		}
+		s.mu.Lock()
+		status.Running, status.CurrentTask, status.WaitingFor = s.cancel != nil, s.current, s.waiting
+		s.mu.Unlock()
+		return status, nil
+
+	case "plan/tasks":
+		st, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		return append([]state.Task{}, st.Tasks...), nil
+
+	case "task/get":
+		var p idParams
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		st, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		t := st.FindTask(p.ID)
+		if t == nil {
+			return nil, fmt.Errorf("task %q not found", p.ID)
+		}
+		return t, nil
+
+	case "task/add":
+		var f taskFields
+		if err := decode(req.Params, &f); err != nil {
+			return nil, err
+		}
+		var added state.Task
+		err := s.update(func(st *state.State) error {
+			t := state.Task{Complexity: "medium"}
+			f.apply(&t)
+			var err error
+			added, err = taskcmd.Add(st, t)
+			return err
+		})
+		return added, err
+
+	case "task/edit":
+		var p struct {
+			ID string `json:"id"`
+			taskFields
+		}
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		var edited state.Task
+		err := s.update(func(st *state.State) error {
+			existing := st.FindTask(p.ID)
+			if existing == nil {
+				return fmt.Errorf("task %q not found", p.ID)
+			}
+			edited = *existing
+			p.apply(&edited)
+			return taskcmd.Edit(st, edited)
+		})
+		return edited, err
+
+	case "task/cancel":
+		var p struct {
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
+		}
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.Reason == "" {
+			p.Reason = "cancelled from the editor"
+		}
+		return nil, s.update(func(st *state.State) error { return st.CancelTask(p.ID, p.Reason) })
+
+	case "task/confirm":
+		var p struct {
+			ID   string `json:"id"`
+			Done bool   `json:"done"`
+			Note string `json:"note"`
+		}
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.confirmManual(executor.ManualConfirmation{TaskID: p.ID, Done: p.Done, Note: p.Note})
+
+	case "task/log":
+		var p idParams
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.ID == "" || filepath.Base(p.ID) != p.ID {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid task ID %q", p.ID)}
+		}
+		data, err := os.ReadFile(filepath.Join(state.ForgeDir(s.root), "logs", p.ID+".log"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no log for %s yet", p.ID)
+		}
+		return map[string]string{"log": string(data)}, err
+
+	case "run/start":
+		var p struct {
+			From string `json:"from"`
+		}
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.startRun(p.From)
+
+	case "run/stop":
+		if !s.stopRun() {
+			return nil, fmt.Errorf("no run in progress")
+		}
+		return nil, nil
+
+	case "forge/methods":
+		return Methods, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+}
+
+// Methods lists the supported calls, for forge/methods.
+var Methods = []string{
+	"forge/status", "forge/methods", "plan/tasks",
+	"task/get", "task/add", "task/edit", "task/cancel", "task/confirm", "task/log",
+	"run/start", "run/stop",
+}
+
+func decode(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) load() (*state.State, error) {
+	st, err := state.Load(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("loading state: %w", err)
+	}
+	if st == nil {
+		return nil, fmt.Errorf("no forge session here")
+	}
+	return st, nil
+}
+
+// update applies fn to the state on disk and saves it, unless the file
+// changed in between.
+func (s *Server) update(fn func(*state.State) error) error {
+	fingerprint, err := state.Fingerprint(s.root)
+	if err != nil {
+		return err
+	}
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(st); err != nil {
+		return err
+	}
+	if _, err := state.SaveIfUnchanged(s.root, st, fingerprint); errors.Is(err, state.ErrStateChanged) {
+		return fmt.Errorf("state.json changed while saving; nothing was saved, try again")
+	} else if err != nil {
+		return err
+	}
+	s.notify("plan/changed", nil)
+	return nil
+}
+
+// ============================================================
+// Runs
+// ============================================================
+
+// startRun moves the plan to execution, like `forge run --from`, and runs
+// it in the background. Events go out as run/event notifications and the
+// end as run/finished.
+func (s *Server) startRun(from string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("a run is already in progress")
+	}
+
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if st.Settings == nil {
+		return fmt.Errorf("execution settings are not configured — finish the inputs phase first")
+	}
+	if from != "" {
+		if _, err := st.StartFrom(from); err != nil {
+			return err
+		}
+	}
+	st.Phase = state.PhaseExecution
+	st.ScheduledStart = nil
+	if err := state.Save(s.root, st); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel, s.done = cancel, make(chan struct{})
+	s.current, s.waiting = "", ""
+	s.confirm = make(chan executor.ManualConfirmation, 1)
+	confirm, done := s.confirm, s.done
+
+	go func() {
+		defer close(done)
+		err := s.execute(ctx, st, s.onEvent, confirm)
+		s.mu.Lock()
+		s.cancel, s.current, s.waiting = nil, "", ""
+		s.mu.Unlock()
+		cancel()
+
+		finished := map[string]string{}
+		if err != nil {
+			finished["error"] = err.Error()
+		}
+		s.notify("run/finished", finished)
+	}()
+	return nil
+}
+
+// stopRun cancels the current run and waits for it to end. Reports
+// whether there was one.
+func (s *Server) stopRun() bool {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	<-done
+	return true
+}
+
+func (s *Server) onEvent(e executor.TaskEvent) {
+	s.mu.Lock()
+	switch e.Type {
+	case executor.EventTaskStart:
+		s.current = e.TaskID
+	case executor.EventManualWait:
+		s.waiting = e.TaskID
+	case executor.EventTaskDone, executor.EventTaskFailed, executor.EventTaskSkipped:
+		if s.waiting == e.TaskID {
+			s.waiting = ""
+		}
+	}
+	s.mu.Unlock()
+
+	// Same shape as a journal line, so clients can share one decoder
+	s.notify("run/event", executor.JournalEntry{
+		Kind:      executor.JournalKindEvent,
+		TaskID:    e.TaskID,
+		Timestamp: e.Timestamp,
+		Event:     &executor.JournalEvent{Type: e.Type.String(), Message: e.Message, Detail: e.Detail},
+	})
+}
+
+func (s *Server) confirmManual(c executor.ManualConfirmation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting == "" || s.waiting != c.TaskID {
+		return fmt.Errorf("task %q is not waiting for confirmation", c.TaskID)
+	}
+	select {
+	case s.confirm <- c:
+		s.waiting = ""
+		return nil
+	default:
+		return fmt.Errorf("a confirmation for %s is already pending", c.TaskID)
+	}
+}
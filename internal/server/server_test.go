@@ -0,0 +1,412 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// message is any server message: a response or a notification.
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	r      *bufio.Reader
+	nextID int
+	notes  []message // notifications received so far
+}
+
+// newClient serves one end of a pipe and returns a client on the other.
+func newClient(t *testing.T, srv *Server) *client {
+	t.Helper()
+	serverEnd, clientEnd := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	go srv.ServeConn(ctx, serverEnd)
+	t.Cleanup(func() {
+		cancel()
+		clientEnd.Close()
+		srv.stopRun()
+	})
+	return &client{t: t, conn: clientEnd, r: bufio.NewReader(clientEnd)}
+}
+
+// call sends a request and returns its response, keeping notifications
+// that arrive first.
+func (c *client) call(method string, params any) message {
+	c.t.Helper()
+	c.nextID++
+	id, _ := json.Marshal(c.nextID)
+	if err := writeMessage(c.conn, map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params}); err != nil {
+		c.t.Fatalf("writing %s: %v", method, err)
+	}
+	for {
+		m := c.read()
+		if m.Method != "" {
+			c.notes = append(c.notes, m)
+			continue
+		}
+		if !bytes.Equal(m.ID, id) {
+			c.t.Fatalf("response id = %s, want %s", m.ID, id)
+		}
+		return m
+	}
+}
+
+// waitFor returns the first notification with the given method; see
+// waitUntil.
+func (c *client) waitFor(method string) message {
+	c.t.Helper()
+	return c.waitUntil(func(m message) bool { return m.Method == method })
+}
+
+// waitUntil returns the first notification that matches, from those
+// received so far or read now. It is consumed, so each notification is
+// matched once.
+func (c *client) waitUntil(match func(message) bool) message {
+	c.t.Helper()
+	for i, m := range c.notes {
+		if match(m) {
+			c.notes = slices.Delete(c.notes, i, i+1)
+			return m
+		}
+	}
+	for {
+		m := c.read()
+		if match(m) {
+			return m
+		}
+		c.notes = append(c.notes, m)
+	}
+}
+
+func (c *client) read() message {
+	c.t.Helper()
+	body, err := readMessage(c.r)
+	if err != nil {
+		c.t.Fatalf("reading message: %v", err)
+	}
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		c.t.Fatalf("decoding %s: %v", body, err)
+	}
+	return m
+}
+
+func decodeResult[T any](t *testing.T, m message) T {
+	t.Helper()
+	if m.Error != nil {
+		t.Fatalf("call failed: %d %s", m.Error.Code, m.Error.Message)
+	}
+	var v T
+	if err := json.Unmarshal(m.Result, &v); err != nil {
+		t.Fatalf("decoding result %s: %v", m.Result, err)
+	}
+	return v
+}
+
+// newRoot saves a configured plan with a done task-001 and a pending task-002.
+func newRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	s := &state.State{
+		Phase:       state.PhaseInputs,
+		PlanVersion: 1,
+		Settings:    &state.Settings{MaxRetries: 1},
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskDone},
+			{ID: "task-002", Title: "API", Complexity: "medium", Status: state.TaskPending, DependsOn: []string{"task-001"}},
+		},
+	}
+	if err := state.Save(root, s); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// ============================================================
+// Framing
+// ============================================================
+
+func TestReadMessage(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := writeMessage(&buf, map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Content-Length: 7\r\n\r\n") {
+		t.Errorf("framed message = %q", buf.String())
+	}
+	body, err := readMessage(bufio.NewReader(&buf))
+	if err != nil || string(body) != `{"a":1}` {
+		t.Errorf("readMessage() = %q, %v", body, err)
+	}
+
+	tests := []struct {
+		name, input, wantErr string
+	}{
+		{name: "missing length", input: "Content-Type: x\r\n\r\n{}", wantErr: "missing Content-Length"},
+		{name: "bad length", input: "Content-Length: ten\r\n\r\n", wantErr: "invalid Content-Length"},
+		{name: "too large", input: "Content-Length: 999999999\r\n\r\n", wantErr: "exceeds"},
+	}
+	for _, tt := range tests {
+		if _, err := readMessage(bufio.NewReader(strings.NewReader(tt.input))); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: readMessage() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// ============================================================
+// Plan methods
+// ============================================================
+
+func TestServer_StatusAndTasks(t *testing.T) {
+	t.Parallel()
+	c := newClient(t, New(newRoot(t), nil))
+
+	status := decodeResult[Status](t, c.call("forge/status", nil))
+	if status.Phase != state.PhaseInputs || status.Tasks["done"] != 1 || status.Tasks["pending"] != 1 || status.Running {
+		t.Errorf("status = %+v", status)
+	}
+
+	tasks := decodeResult[[]state.Task](t, c.call("plan/tasks", nil))
+	if len(tasks) != 2 || tasks[1].ID != "task-002" {
+		t.Errorf("plan/tasks = %+v", tasks)
+	}
+
+	task := decodeResult[state.Task](t, c.call("task/get", map[string]string{"id": "task-002"}))
+	if task.Title != "API" {
+		t.Errorf("task/get = %+v", task)
+	}
+}
+
+func TestServer_EditsPlan(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+	c := newClient(t, New(root, nil))
+
+	added := decodeResult[state.Task](t, c.call("task/add", map[string]any{"title": "Docs", "depends_on": []string{"task-002"}}))
+	if added.ID != "task-003" || added.Complexity != "medium" {
+		t.Errorf("task/add = %+v", added)
+	}
+	c.waitFor("plan/changed")
+
+	edited := decodeResult[state.Task](t, c.call("task/edit", map[string]any{"id": "task-002", "complexity": "large"}))
+	if edited.Complexity != "large" || edited.Title != "API" {
+		t.Errorf("task/edit = %+v", edited)
+	}
+
+	decodeResult[struct{}](t, c.call("task/cancel", map[string]string{"id": "task-003"}))
+	s, _ := state.Load(root)
+	if got := s.FindTask("task-003"); got.Status != state.TaskCancelled || got.CancelledReason != "cancelled from the editor" {
+		t.Errorf("cancelled task = %+v", got)
+	}
+	if s.FindTask("task-002").Complexity != "large" {
+		t.Error("task/edit was not saved")
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		method   string
+		params   any
+		wantCode int
+		wantMsg  string
+	}{
+		{method: "plan/delete", wantCode: codeMethodNotFound, wantMsg: "unknown method"},
+		{method: "task/get", params: []int{1}, wantCode: codeInvalidParams},
+		{method: "task/get", params: map[string]string{"id": "task-404"}, wantCode: codeFailed, wantMsg: "not found"},
+		{method: "task/add", params: map[string]string{"complexity": "huge", "title": "x"}, wantCode: codeFailed, wantMsg: "complexity must be"},
+		{method: "task/edit", params: map[string]string{"id": "task-001", "title": "x"}, wantCode: codeFailed, wantMsg: "it is done"},
+		{method: "task/log", params: map[string]string{"id": "../state"}, wantCode: codeInvalidParams, wantMsg: "invalid task ID"},
+		{method: "task/confirm", params: map[string]any{"id": "task-002", "done": true}, wantCode: codeFailed, wantMsg: "not waiting"},
+		{method: "run/stop", wantCode: codeFailed, wantMsg: "no run in progress"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			t.Parallel()
+			c := newClient(t, New(newRoot(t), nil))
+			m := c.call(tt.method, tt.params)
+			if m.Error == nil || m.Error.Code != tt.wantCode || !strings.Contains(m.Error.Message, tt.wantMsg) {
+				t.Errorf("%s error = %+v, want code %d containing %q", tt.method, m.Error, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestServer_TaskLog(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+	logs := filepath.Join(state.ForgeDir(root), "logs")
+	if err := os.MkdirAll(logs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(logs, "task-001.log"), []byte("all good\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newClient(t, New(root, nil))
+	got := decodeResult[map[string]string](t, c.call("task/log", map[string]string{"id": "task-001"}))
+	if got["log"] != "all good\n" {
+		t.Errorf("task/log = %q", got)
+	}
+}
+
+// ============================================================
+// Runs
+// ============================================================
+
+func TestServer_RunStreamsEventsAndTakesConfirmations(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+	var gotState *state.State
+	execute := func(ctx context.Context, s *state.State, onEvent func(executor.TaskEvent), confirm <-chan executor.ManualConfirmation) error {
+		gotState = s
+		onEvent(executor.TaskEvent{TaskID: "task-002", Type: executor.EventTaskStart, Message: "API"})
+		onEvent(executor.TaskEvent{TaskID: "task-002", Type: executor.EventManualWait, Message: "deploy it"})
+		select {
+		case c := <-confirm:
+			if !c.Done {
+				return executor.ErrPaused
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		onEvent(executor.TaskEvent{TaskID: "task-002", Type: executor.EventTaskDone})
+		return nil
+	}
+	c := newClient(t, New(root, execute))
+
+	decodeResult[struct{}](t, c.call("run/start", nil))
+	if m := c.call("run/start", nil); m.Error == nil || !strings.Contains(m.Error.Message, "already in progress") {
+		t.Errorf("second run/start error = %+v", m.Error)
+	}
+
+	start := c.waitFor("run/event")
+	var entry executor.JournalEntry
+	if err := json.Unmarshal(start.Params, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if ev, ok := entry.TaskEvent(); !ok || ev.Type != executor.EventTaskStart || ev.TaskID != "task-002" {
+		t.Errorf("first run/event = %s", start.Params)
+	}
+
+	// Wait until the manual step is announced before answering it; the
+	// announcement may have arrived during an earlier call
+	c.waitUntil(func(m message) bool {
+		return m.Method == "run/event" && strings.Contains(string(m.Params), "manual_wait")
+	})
+	status := decodeResult[Status](t, c.call("forge/status", nil))
+	if !status.Running || status.CurrentTask != "task-002" || status.WaitingFor != "task-002" {
+		t.Errorf("status during run = %+v", status)
+	}
+	decodeResult[struct{}](t, c.call("task/confirm", map[string]any{"id": "task-002", "done": true}))
+
+	finished := c.waitFor("run/finished")
+	if string(finished.Params) != "{}" {
+		t.Errorf("run/finished = %s, want no error", finished.Params)
+	}
+	if gotState == nil || gotState.Phase != state.PhaseExecution {
+		t.Errorf("run started with state %+v, want the execution phase", gotState)
+	}
+	if s, _ := state.Load(root); s.Phase != state.PhaseExecution {
+		t.Errorf("saved phase = %s, want execution", s.Phase)
+	}
+}
+
+func TestServer_DropsClientThatStopsReading(t *testing.T) {
+	t.Parallel()
+	srv := New(newRoot(t), nil)
+	serverEnd, clientEnd := net.Pipe()
+	defer clientEnd.Close()
+	go srv.ServeConn(context.Background(), serverEnd)
+	for {
+		srv.mu.Lock()
+		n := len(srv.conns)
+		srv.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Nothing reads clientEnd, so the writer blocks on the first message
+	notified := make(chan struct{})
+	go func() {
+		for range outboxSize + 2 {
+			srv.notify("run/event", nil)
+		}
+		close(notified)
+	}()
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify blocked on a client that doesn't read")
+	}
+
+	// The client was disconnected: reads fail once the queue is discarded
+	clientEnd.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.Copy(io.Discard, clientEnd); err != nil {
+		t.Errorf("reading from the dropped client: %v, want EOF", err)
+	}
+}
+
+func TestServer_RunNeedsSettings(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	if err := state.Save(root, &state.State{Phase: state.PhaseReview}); err != nil {
+		t.Fatal(err)
+	}
+	c := newClient(t, New(root, nil))
+	if m := c.call("run/start", nil); m.Error == nil || !strings.Contains(m.Error.Message, "settings are not configured") {
+		t.Errorf("run/start error = %+v", m.Error)
+	}
+}
+
+// ============================================================
+// Listen
+// ============================================================
+
+func TestListen(t *testing.T) {
+	t.Parallel()
+	dir, err := os.MkdirTemp("", "forge-sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, SocketFile)
+
+	// A stale file in the way is replaced
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket: %v", err)
+	}
+	defer l.Close()
+
+	// A live socket is not
+	if _, err := Listen(path); err == nil || !strings.Contains(err.Error(), "another forge serve") {
+		t.Errorf("second Listen() error = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package state
+
+// taskWeight estimates how long a task takes relative to the others, from
+// its complexity. Unknown complexities count as medium.
+func taskWeight(t Task) int {
+	switch t.Complexity {
+	case "small":
+		return 1
+	case "large":
+		return 3
+	}
+	return 2
+}
+
+// CriticalPaths returns, for each task still to run, the weight of the
+// longest chain of remaining work that starts with it: its own weight plus
+// that of its heaviest chain of pending dependents. Tasks that already
+// finished are left out and add nothing to a chain.
+func CriticalPaths(tasks []Task) map[string]int {
+	dependents := map[string][]string{}
+	byID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		for _, dep := range t.DependsOn {
+			dependents[dep] = append(dependents[dep], t.ID)
+		}
+	}
+
+	lengths := map[string]int{}
+	visiting := map[string]bool{}
+	var walk func(id string) int
+	walk = func(id string) int {
+		if n, ok := lengths[id]; ok {
+			return n
+		}
+		t, ok := byID[id]
+		if !ok || visiting[id] || (t.Status != TaskPending && t.Status != TaskInProgress) {
+			return 0
+		}
+		visiting[id] = true
+		longest := 0
+		for _, d := range dependents[id] {
+			longest = max(longest, walk(d))
+		}
+		visiting[id] = false
+		lengths[id] = taskWeight(t) + longest
+		return lengths[id]
+	}
+	for _, t := range tasks {
+		walk(t.ID)
+	}
+	return lengths
+}
+
+// CriticalTask returns the ready task that heads the longest chain of
+// remaining work, the earliest in plan order on a tie. Starting the long
+// chains first keeps total wall-clock time down once tasks run side by
+// side. ready must not be empty.
+func CriticalTask(ready, tasks []Task) Task {
+	paths := CriticalPaths(tasks)
+	best := ready[0]
+	for _, t := range ready[1:] {
+		if paths[t.ID] > paths[best.ID] {
+			best = t
+		}
+	}
+	return best
+}
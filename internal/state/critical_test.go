@@ -0,0 +1,60 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCriticalPaths(t *testing.T) {
+	t.Parallel()
+	tasks := []Task{
+		{ID: "task-001", Complexity: "small", Status: TaskPending},
+		{ID: "task-002", Complexity: "large", Status: TaskPending},
+		{ID: "task-003", Complexity: "medium", Status: TaskPending, DependsOn: []string{"task-001"}},
+		{ID: "task-004", Complexity: "large", Status: TaskPending, DependsOn: []string{"task-003"}},
+		{ID: "task-005", Complexity: "small", Status: TaskPending, DependsOn: []string{"task-001", "task-002"}},
+		{ID: "task-006", Complexity: "large", Status: TaskDone},
+		{ID: "task-007", Status: TaskPending, DependsOn: []string{"task-006"}},
+	}
+	want := map[string]int{
+		"task-001": 6, // 1 + 2 + 3 through task-003 and task-004
+		"task-002": 4,
+		"task-003": 5,
+		"task-004": 3,
+		"task-005": 1,
+		"task-007": 2,
+	}
+	if got := CriticalPaths(tasks); !reflect.DeepEqual(got, want) {
+		t.Errorf("CriticalPaths =\n %v\nwant\n %v", got, want)
+	}
+}
+
+func TestCriticalPaths_Cycle(t *testing.T) {
+	t.Parallel()
+	tasks := []Task{
+		{ID: "task-001", Status: TaskPending, DependsOn: []string{"task-002"}},
+		{ID: "task-002", Status: TaskPending, DependsOn: []string{"task-001"}},
+	}
+	got := CriticalPaths(tasks)
+	if len(got) != 2 {
+		t.Errorf("CriticalPaths = %v, want an entry per task", got)
+	}
+}
+
+func TestCriticalTask(t *testing.T) {
+	t.Parallel()
+	tasks := []Task{
+		{ID: "task-001", Complexity: "large", Status: TaskPending},
+		{ID: "task-002", Complexity: "small", Status: TaskPending},
+		{ID: "task-003", Complexity: "large", Status: TaskPending, DependsOn: []string{"task-002"}},
+		{ID: "task-004", Complexity: "medium", Status: TaskPending},
+	}
+	ready := []Task{tasks[0], tasks[1], tasks[3]}
+	if got := CriticalTask(ready, tasks); got.ID != "task-002" {
+		t.Errorf("CriticalTask = %s, want task-002 (heads the longest chain)", got.ID)
+	}
+	ready = []Task{tasks[3], tasks[0]}
+	if got := CriticalTask(ready, tasks[:1]); got.ID != "task-001" {
+		t.Errorf("CriticalTask = %s, want task-001", got.ID)
+	}
+}
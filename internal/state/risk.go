@@ -0,0 +1,127 @@
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Risk levels, by score: below RiskMedium is low, RiskHigh and above is high.
+const (
+	RiskMedium = 4
+	RiskHigh   = 7
+)
+
+// Risk is how likely a task is to go wrong, or to be expensive when it
+// does, with the reasons behind the score.
+type Risk struct {
+	Score   int
+	Reasons []string
+}
+
+// Level names the risk band: "low", "medium" or "high".
+func (r Risk) Level() string {
+	switch {
+	case r.Score >= RiskHigh:
+		return "high"
+	case r.Score >= RiskMedium:
+		return "medium"
+	}
+	return "low"
+}
+
+// protectedPaths are files and directories where a mistake outlives the
+// task: CI runs with repository secrets, migrations change live data,
+// build and dependency manifests affect every other task.
+var protectedPaths = []string{
+	".github/workflows", ".gitlab-ci", "migrations/", "Dockerfile", "docker-compose",
+	".env", "terraform", "go.mod", "package.json", "Cargo.toml", "requirements.txt",
+}
+
+// integrationPattern matches mentions of systems outside the repository,
+// which tests usually can't exercise for real.
+var integrationPattern = regexp.MustCompile(`(?i)\b(?:external api|third[- ]party|webhooks?|oauth|stripe|payments?|s3|smtp|email provider|twilio|slack api|graphql api|grpc)\b`)
+
+// TaskRisk scores a task from its complexity, how many tasks depend on
+// it, whether it touches protected paths and whether it integrates with
+// external systems. tasks is the whole plan.
+func TaskRisk(t Task, tasks []Task) Risk {
+	var r Risk
+	switch t.Complexity {
+	case "large":
+		r.Score += 3
+		r.Reasons = append(r.Reasons, "large complexity (+3)")
+	case "small":
+		r.Score++
+		r.Reasons = append(r.Reasons, "small complexity (+1)")
+	default:
+		r.Score += 2
+		r.Reasons = append(r.Reasons, "medium complexity (+2)")
+	}
+
+	if n := len(Dependents(tasks, t.ID)); n > 0 {
+		points := min(n, 3)
+		r.Score += points
+		r.Reasons = append(r.Reasons, fmt.Sprintf("%d dependent task(s) (+%d)", n, points))
+	}
+
+	text := strings.Join(append(append([]string{t.Title, t.Description}, t.AcceptanceCriteria...), t.Commands...), "\n")
+	var touched []string
+	for _, p := range protectedPaths {
+		if strings.Contains(text, p) {
+			touched = append(touched, p)
+		}
+	}
+	if len(touched) > 0 {
+		r.Score += 3
+		r.Reasons = append(r.Reasons, fmt.Sprintf("touches protected paths: %s (+3)", strings.Join(touched, ", ")))
+	}
+
+	var external []string
+	for _, m := range integrationPattern.FindAllString(text, -1) {
+		if m = strings.ToLower(m); !slices.Contains(external, m) {
+			external = append(external, m)
+		}
+	}
+	if len(external) > 0 {
+		r.Score += 2
+		r.Reasons = append(r.Reasons, fmt.Sprintf("external integration: %s (+2)", strings.Join(external, ", ")))
+	}
+	return r
+}
+
+// RiskiestTask returns the ready task with the highest risk score, the
+// earliest in plan order on a tie. ready must not be empty.
+func RiskiestTask(ready, tasks []Task) Task {
+	best, bestScore := ready[0], TaskRisk(ready[0], tasks).Score
+	for _, t := range ready[1:] {
+		if score := TaskRisk(t, tasks).Score; score > bestScore {
+			best, bestScore = t, score
+		}
+	}
+	return best
+}
+
+// Dependents returns the tasks that depend on taskID, directly or
+// transitively. None of them can become one of its dependencies without
+// creating a cycle.
+func Dependents(tasks []Task, taskID string) map[string]bool {
+	dependents := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, t := range tasks {
+			if dependents[t.ID] || t.ID == taskID {
+				continue
+			}
+			for _, dep := range t.DependsOn {
+				if dep == taskID || dependents[dep] {
+					dependents[t.ID] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return dependents
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaskRisk(t *testing.T) {
+	t.Parallel()
+	tasks := []Task{
+		{ID: "task-001", Complexity: "small", Title: "Set up module"},
+		{ID: "task-002", Complexity: "large", Title: "Add Stripe payments",
+			Description: "Charge cards through the Stripe API and handle its webhooks.", DependsOn: []string{"task-001"}},
+		{ID: "task-003", Complexity: "medium", Title: "Add a CI workflow",
+			Description: "Create .github/workflows/ci.yml running go test.", DependsOn: []string{"task-002"}},
+		{ID: "task-004", Title: "Docs", DependsOn: []string{"task-003"}},
+	}
+	tests := []struct {
+		id          string
+		wantScore   int
+		wantLevel   string
+		wantReasons []string
+	}{
+		{id: "task-001", wantScore: 4, wantLevel: "medium", wantReasons: []string{"small complexity (+1)", "3 dependent task(s) (+3)"}},
+		{id: "task-002", wantScore: 7, wantLevel: "high", wantReasons: []string{
+			"large complexity (+3)", "2 dependent task(s) (+2)", "external integration: stripe, payments, webhooks (+2)"}},
+		{id: "task-003", wantScore: 6, wantLevel: "medium", wantReasons: []string{
+			"medium complexity (+2)", "1 dependent task(s) (+1)", "touches protected paths: .github/workflows (+3)"}},
+		{id: "task-004", wantScore: 2, wantLevel: "low", wantReasons: []string{"medium complexity (+2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			var task Task
+			for _, candidate := range tasks {
+				if candidate.ID == tt.id {
+					task = candidate
+				}
+			}
+			got := TaskRisk(task, tasks)
+			if got.Score != tt.wantScore || got.Level() != tt.wantLevel {
+				t.Errorf("score = %d (%s), want %d (%s)", got.Score, got.Level(), tt.wantScore, tt.wantLevel)
+			}
+			if !reflect.DeepEqual(got.Reasons, tt.wantReasons) {
+				t.Errorf("reasons:\n got  %q\n want %q", got.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestRiskiestTask(t *testing.T) {
+	t.Parallel()
+	ready := []Task{
+		{ID: "task-001", Complexity: "small"},
+		{ID: "task-002", Complexity: "large"},
+		{ID: "task-003", Complexity: "large"},
+	}
+	if got := RiskiestTask(ready, ready); got.ID != "task-002" {
+		t.Errorf("RiskiestTask = %s, want task-002 (the earlier of the tie)", got.ID)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	t.Parallel()
+	tasks := []Task{
+		{ID: "task-001"},
+		{ID: "task-002", DependsOn: []string{"task-001"}},
+		{ID: "task-003", DependsOn: []string{"task-002"}},
+		{ID: "task-004", DependsOn: []string{"task-003"}},
+		{ID: "task-005"},
+	}
+	got := Dependents(tasks, "task-002")
+	want := map[string]bool{"task-003": true, "task-004": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents(task-002) = %v, want %v", got, want)
+	}
+}
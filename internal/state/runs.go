@@ -0,0 +1,123 @@
+package state
+
+import "time"
+
+// RunStatus describes how an execution run ended.
+type RunStatus string
+
+const (
+	RunInProgress      RunStatus = "in_progress"
+	RunCompleted       RunStatus = "completed"
+	RunCancelled       RunStatus = "cancelled"
+	RunBudgetExhausted RunStatus = "budget_exhausted" // paused after hitting Settings.MaxBudget
+	RunPaused          RunStatus = "paused"           // stopped for a human: low disk space, an oversized change or a run limit
+)
+
+// RunSummary records one execution run so later sessions can show what
+// happened before and reports can aggregate across runs.
+type RunSummary struct {
+	ID          int        `json:"id"`
+	PlanVersion int        `json:"plan_version"` // plan the run executed, for comparing replans
+	Status      RunStatus  `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	TaskIDs     []string   `json:"task_ids,omitempty"` // tasks attempted, in order
+	Completed   int        `json:"completed"`
+	Failed      int        `json:"failed"`
+	Skipped     int        `json:"skipped"`
+	Retries     int        `json:"retries"`
+	TokensUsed  int        `json:"tokens_used,omitempty"`
+	CostUSD     float64    `json:"cost_usd,omitempty"`
+
+	// Final build/test/lint pass on the integrated base branch (nil if not run)
+	Verification *Verification `json:"verification,omitempty"`
+
+	// Where a run stopped at a run limit (nil otherwise)
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// Checkpoint records where a paused run stopped. Nothing of the next task
+// has run yet, so the next run simply starts with it.
+type Checkpoint struct {
+	Reason   string     `json:"reason"`
+	NextTask string     `json:"next_task"`
+	ResumeAt *time.Time `json:"resume_at,omitempty"` // earliest useful restart, e.g. the end of quiet hours
+}
+
+// Verification is the result of re-running the project checks after all
+// task branches were merged, since per-task runs can miss integration breakage.
+type Verification struct {
+	Passed bool               `json:"passed"`
+	Steps  []VerificationStep `json:"steps"`
+}
+
+// VerificationStep is one command of the verification pass.
+type VerificationStep struct {
+	Name     string  `json:"name"` // build, test or lint
+	Command  string  `json:"command"`
+	Passed   bool    `json:"passed"`
+	Duration float64 `json:"duration_s"`
+}
+
+// Duration returns how long the run took, or zero while it is in progress.
+func (r RunSummary) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// StartRun appends a new in-progress run and returns its ID.
+func (s *State) StartRun(now time.Time) int {
+	id := 1
+	if n := len(s.Runs); n > 0 {
+		id = s.Runs[n-1].ID + 1
+	}
+	s.Runs = append(s.Runs, RunSummary{ID: id, PlanVersion: s.PlanVersion, Status: RunInProgress, StartedAt: now})
+	return id
+}
+
+// FindRun returns a pointer to the run with the given ID, or nil.
+func (s *State) FindRun(id int) *RunSummary {
+	for i := range s.Runs {
+		if s.Runs[i].ID == id {
+			return &s.Runs[i]
+		}
+	}
+	return nil
+}
+
+// LastRun returns the most recent run, or nil if there have been none.
+func (s *State) LastRun() *RunSummary {
+	if len(s.Runs) == 0 {
+		return nil
+	}
+	return &s.Runs[len(s.Runs)-1]
+}
+
+// RunTotals aggregates a set of runs.
+type RunTotals struct {
+	Runs       int
+	Completed  int
+	Failed     int
+	Skipped    int
+	Retries    int
+	TokensUsed int
+	CostUSD    float64
+	Duration   time.Duration
+}
+
+// AggregateRuns sums the counters of all runs.
+func AggregateRuns(runs []RunSummary) RunTotals {
+	t := RunTotals{Runs: len(runs)}
+	for _, r := range runs {
+		t.Completed += r.Completed
+		t.Failed += r.Failed
+		t.Skipped += r.Skipped
+		t.Retries += r.Retries
+		t.TokensUsed += r.TokensUsed
+		t.CostUSD += r.CostUSD
+		t.Duration += r.Duration()
+	}
+	return t
+}
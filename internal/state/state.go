@@ -1,11 +1,14 @@
 package state
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -38,17 +41,66 @@ const (
 	TaskCancelled  TaskStatus = "cancelled"
 )
 
+// TaskType says how a task is executed. Empty means TaskTypeCode.
+type TaskType string
+
+const (
+	TaskTypeCode   TaskType = "code"   // Claude implements the task
+	TaskTypeVerify TaskType = "verify" // only commands run, no Claude
+	TaskTypeManual TaskType = "manual" // a human does it; execution waits for confirmation
+)
+
+// ValidTaskType reports whether t is a known task type (empty counts as code).
+func ValidTaskType(t TaskType) bool {
+	switch t {
+	case "", TaskTypeCode, TaskTypeVerify, TaskTypeManual:
+		return true
+	}
+	return false
+}
+
+// TaskOwner says who works on a task. Empty means OwnerAgent.
+type TaskOwner string
+
+const (
+	OwnerAgent TaskOwner = "agent" // the runner executes the task
+	OwnerHuman TaskOwner = "human" // a person does it outside forge and marks it done
+)
+
+// BranchDisposition records what became of the branch of a task removed
+// in a replan while it had work on it (Branch or GitSHA set).
+type BranchDisposition string
+
+const (
+	BranchKept    BranchDisposition = "kept"    // left in place for the user
+	BranchDeleted BranchDisposition = "deleted" // deleted locally by forge
+)
+
+// ValidTaskOwner reports whether o is a known owner (empty counts as agent).
+func ValidTaskOwner(o TaskOwner) bool {
+	switch o {
+	case "", OwnerAgent, OwnerHuman:
+		return true
+	}
+	return false
+}
+
 type State struct {
-	ProjectName         string            `json:"project_name,omitempty"`
-	Phase               Phase             `json:"phase"`
-	PlanVersion         int               `json:"plan_version"`
-	PlanHistory         []PlanRevision    `json:"plan_history,omitempty"`
-	ConversationHistory []ConversationMsg `json:"conversation_history,omitempty"`
-	Tasks               []Task            `json:"tasks,omitempty"`
-	Settings            *Settings         `json:"settings,omitempty"`
-	Snapshot            *ProjectSnapshot  `json:"snapshot,omitempty"`
-	CreatedAt           time.Time         `json:"created_at"`
-	UpdatedAt           time.Time         `json:"updated_at"`
+	ProjectName         string             `json:"project_name,omitempty"`
+	Phase               Phase              `json:"phase"`
+	PlanVersion         int                `json:"plan_version"`
+	PlanHistory         []PlanRevision     `json:"plan_history,omitempty"`
+	ConversationHistory []ConversationMsg  `json:"conversation_history,omitempty"`
+	ConversationBranch  string             `json:"conversation_branch,omitempty"` // the branch ConversationHistory belongs to; "" = main
+	ConversationForks   []ConversationFork `json:"conversation_forks,omitempty"`  // the other branches
+	Tasks               []Task             `json:"tasks,omitempty"`
+	Settings            *Settings          `json:"settings,omitempty"`
+	Snapshot            *ProjectSnapshot   `json:"snapshot,omitempty"`
+	Runs                []RunSummary       `json:"runs,omitempty"`
+	ScheduledStart      *time.Time         `json:"scheduled_start,omitempty"` // delayed start of the next run; cleared when it begins
+	ChangelogAt         *time.Time         `json:"changelog_at,omitempty"`    // last changelog entry; earlier tasks are not repeated
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
 }
 
 // PlanRevision records metadata each time the plan changes.
@@ -65,6 +117,17 @@ type ConversationMsg struct {
 	Content string `json:"content"`
 }
 
+// MainConversation is the name of the planning conversation's first
+// branch.
+const MainConversation = "main"
+
+// ConversationFork is a planning conversation branch that is not the
+// current one, e.g. a microservices variant explored with /fork.
+type ConversationFork struct {
+	Name    string            `json:"name"`
+	History []ConversationMsg `json:"history,omitempty"`
+}
+
 type Task struct {
 	ID                  string     `json:"id"`
 	Title               string     `json:"title"`
@@ -72,31 +135,263 @@ type Task struct {
 	AcceptanceCriteria  []string   `json:"acceptance_criteria"`
 	DependsOn           []string   `json:"depends_on,omitempty"`
 	Complexity          string     `json:"complexity"`
+	Labels              []string   `json:"labels,omitempty"` // free-form tags set in review, e.g. "frontend"
+	Type                TaskType   `json:"type,omitempty"`
+	Commands            []string   `json:"commands,omitempty"`        // verify tasks; empty = project build/test/lint
+	PromptOverride      string     `json:"prompt_override,omitempty"` // replaces the generated execution prompt
+	Artifacts           []string   `json:"artifacts,omitempty"`       // globs collected into .forge/artifacts/<id>/ on success
+	Model               string     `json:"model,omitempty"`           // runs the task on this model instead of the provider's
+	CollectedArtifacts  []string   `json:"collected_artifacts,omitempty"`
 	Status              TaskStatus `json:"status"`
 	PlanVersionCreated  int        `json:"plan_version_created"`
 	PlanVersionModified int        `json:"plan_version_modified"`
 	Branch              string     `json:"branch,omitempty"`
 	GitSHA              string     `json:"git_sha,omitempty"`
 	CancelledReason     string     `json:"cancelled_reason,omitempty"`
+	SkippedReason       string     `json:"skipped_reason,omitempty"`
+	Deferred            bool       `json:"deferred,omitempty"` // skipped for now by StartFrom; satisfies dependents
+	Owner               TaskOwner  `json:"owner,omitempty"`    // human tasks never enter the runner queue
+	Assignee            string     `json:"assignee,omitempty"` // who is on it, e.g. a name or handle
 	Retries             int        `json:"retries"`
+	Turns               int        `json:"turns,omitempty"` // most turns one Claude call of the task took (Settings.AdaptiveTurns)
+	Handoff             string     `json:"handoff,omitempty"` // the model's note for dependent tasks: what was built, key files, gotchas
+	IssueURL            string     `json:"issue_url,omitempty"` // issue opened when the task exhausted its retries
+	PRURL               string     `json:"pr_url,omitempty"`    // pull request opened for the task branch (Settings.AutoPR)
+	PRDraft             bool       `json:"pr_draft,omitempty"`  // PRURL is still a draft (Settings.DraftPRs)
+	Repo                string     `json:"repo,omitempty"`      // workspace repository (WorkspaceRepo.Path) the task works in; empty = project root
+	RootCause           *RootCause `json:"root_cause,omitempty"` // diagnosis once the task exhausted its retries
 	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+
+	// What became of Branch when a replan removed the task with work on it
+	BranchDisposition BranchDisposition `json:"branch_disposition,omitempty"`
+}
+
+// RootCause is the diagnosis of a task that exhausted its retries, with the
+// plan changes it suggests.
+type RootCause struct {
+	Summary     string   `json:"summary"`
+	Category    string   `json:"category,omitempty"` // plan, spec, environment, code or flaky
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// Format renders the diagnosis as plain text.
+func (rc RootCause) Format() string {
+	var b strings.Builder
+	b.WriteString("Root cause: " + rc.Summary)
+	if rc.Category != "" {
+		b.WriteString(" [" + rc.Category + "]")
+	}
+	b.WriteString("\n")
+	for _, s := range rc.Suggestions {
+		b.WriteString("- " + s + "\n")
+	}
+	return b.String()
 }
 
 type Settings struct {
 	TestCommand    string            `json:"test_command,omitempty"`
 	BuildCommand   string            `json:"build_command,omitempty"`
+	LintCommand    string            `json:"lint_command,omitempty"` // only run in the end-of-run verification pass
 	BranchPattern  string            `json:"branch_pattern"`
 	BaseBranch    string            `json:"base_branch"`
 	MaxRetries    int               `json:"max_retries"`
 	AutoPR        bool              `json:"auto_pr"`
+	FileIssues    bool              `json:"file_issues,omitempty"` // open a forge-failed GitHub issue when a task exhausts retries
 	ClaudeModel   string            `json:"claude_model,omitempty"`
 	MaxTurns      MaxTurnsConfig   `json:"max_turns"`
 	MCPServers    []MCPServerConfig `json:"mcp_servers,omitempty"`
 	EnvVars       map[string]string `json:"env_vars,omitempty"`
 	ExtraContext  string            `json:"extra_context,omitempty"`
+	ContextURLs   []string          `json:"context_urls,omitempty"` // design docs and API specs fetched into prompts (internal/docs)
+	DatabaseURLEnv string           `json:"database_url_env,omitempty"` // env var holding the dev database URL, introspected for planning (internal/dbschema)
 	Provider      provider.Config    `json:"provider"`
 	GitInitialized bool             `json:"git_initialized,omitempty"`
 	RemoteURL     string            `json:"remote_url,omitempty"`
+
+	// Extra agent guidance files written alongside CLAUDE.md.
+	GenerateAgentsMD    bool `json:"generate_agents_md,omitempty"`
+	GenerateCursorRules bool `json:"generate_cursor_rules,omitempty"`
+
+	// Spending cap per execution run; the runner pauses once it is reached.
+	MaxBudget provider.Budget `json:"max_budget,omitempty"`
+
+	// Checks on every task commit; violations are sent back to Claude as a retry.
+	CommitPolicy CommitPolicy `json:"commit_policy"`
+
+	// A task staging more than this many MB pauses the run for review
+	// instead of committing (e.g. vendored node_modules). 0 disables.
+	MaxChangeMB int `json:"max_change_mb,omitempty"`
+
+	// Shell used to run test/build commands: sh, bash, zsh, pwsh or cmd.
+	// Empty means the platform default (sh, or cmd on Windows).
+	Shell string `json:"shell,omitempty"`
+
+	// How to get attention when a run waits on a human: "bell" rings the
+	// terminal bell, "sound" plays a system sound. Empty means off.
+	Alert string `json:"alert,omitempty"`
+
+	// How task statuses are drawn: "emoji" (default), "badges" ([DONE],
+	// [FAIL], ...) or "symbols" (✓, ✗, ...), for terminals and readers
+	// that can't tell the emoji or colors apart.
+	StatusStyle string `json:"status_style,omitempty"`
+
+	// Screen-reader friendly mode: a linear dashboard without box drawing,
+	// with every status change printed as a labeled line of an append-only
+	// log. forge --accessible turns it on for one session.
+	Accessible bool `json:"accessible,omitempty"`
+
+	// Draw with ASCII only (#/- progress bars, [x] statuses) for terminals
+	// that show block characters and emoji as garbage. forge also falls
+	// back on its own when the locale isn't UTF-8 (platform.UTF8Terminal).
+	ASCIIMode bool `json:"ascii_mode,omitempty"`
+
+	// Run limits, checked before each task: a run pauses once it has
+	// lasted MaxRunDuration (e.g. "6h"), and no task starts during
+	// QuietHours ("HH:MM-HH:MM", local time). Empty disables either.
+	MaxRunDuration string `json:"max_run_duration,omitempty"`
+	QuietHours     string `json:"quiet_hours,omitempty"`
+
+	// What to do with a changelog entry once the plan completes: "draft"
+	// writes .forge/release-notes.md, "commit" prepends CHANGELOG.md and
+	// commits it on the base branch. Empty means off.
+	Changelog string `json:"changelog,omitempty"`
+
+	// Have a second model review each task's staged diff against its
+	// acceptance criteria before commit; blocking findings trigger a fix
+	// attempt. ReviewModel empty means the execution model.
+	CodeReview  bool   `json:"code_review,omitempty"`
+	ReviewModel string `json:"review_model,omitempty"`
+
+	// Commit with --no-verify, skipping the repository's pre-commit and
+	// commit-msg hooks (husky, pre-commit). Off by default: hook failures
+	// go back to Claude as a retry instead.
+	SkipHooks bool `json:"skip_hooks,omitempty"`
+
+	// Commit trailers: SignOff adds a DCO Signed-off-by for the committer
+	// (git commit --signoff); CoAuthor adds Co-authored-by for "forge"
+	// (Forge Agent), "model" (the execution model) or a "Name <email>".
+	// Empty CoAuthor means no trailer.
+	SignOff  bool   `json:"sign_off,omitempty"`
+	CoAuthor string `json:"co_author,omitempty"`
+
+	// Author and committer of forge's commits, "Name <email>" (e.g.
+	// "Forge Bot <forge@team.dev>"), so git blame tells agent-written code
+	// apart. Empty commits as the git config identity.
+	CommitIdentity string `json:"commit_identity,omitempty"`
+
+	// Every execution event is POSTed here as JSON (a journal line) for
+	// dashboards, chat bots and CI annotations. Empty disables.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Report each pushed task, and the end-of-run verification, as a
+	// GitHub check run on its commit (commit statuses when the token may
+	// not create check runs). GitHub remotes only.
+	PublishChecks bool `json:"publish_checks,omitempty"`
+
+	// Wait for the repository's CI after pushing a task (GitHub Actions,
+	// GitHub remotes only) and retry the task when CI fails, as for a
+	// failed local test.
+	WaitForCI bool `json:"wait_for_ci,omitempty"`
+
+	// Open AutoPR pull requests as drafts and mark them ready for review
+	// once the plan is complete and its end-of-run verification passes.
+	DraftPRs bool `json:"draft_prs,omitempty"`
+
+	// Stash uncommitted changes found when a run starts instead of
+	// refusing to run, so they can't end up in a task's commit.
+	StashDirty bool `json:"stash_dirty,omitempty"`
+
+	// What to do when the base branch has new commits on the remote
+	// before a task: "branch" fast-forwards it, "rebase" also rebases
+	// local commits and existing task branches onto it. Empty = off.
+	BaseDrift string `json:"base_drift,omitempty"`
+
+	// Self-review items Claude must confirm at the end of each task
+	// ("Tests added"); unconfirmed items get a follow-up turn before tests.
+	Checklist []string `json:"checklist,omitempty"`
+
+	// Other git repositories a workspace plan spans (e.g. backend and
+	// frontend checked out side by side). Tasks bind to one with Task.Repo.
+	Repos []WorkspaceRepo `json:"repos,omitempty"`
+
+	// Directories to check out in a large monorepo, relative to the
+	// project root (git sparse-checkout in cone mode; top-level files are
+	// always present). Empty checks out everything.
+	SparsePaths []string `json:"sparse_paths,omitempty"`
+
+	// Run the riskiest ready task first (see TaskRisk) instead of the
+	// earliest in plan order, so likely failures surface early.
+	FrontLoadRisk bool `json:"front_load_risk,omitempty"`
+
+	// Run the ready task that heads the longest chain of remaining work
+	// first (see CriticalTask) instead of the earliest in plan order.
+	CriticalPathFirst bool `json:"critical_path_first,omitempty"`
+
+	// Base each task's max turns on the turns similar completed tasks
+	// needed, within half and twice the MaxTurns limit.
+	AdaptiveTurns bool `json:"adaptive_turns,omitempty"`
+
+	// Inline up to this many project files related to the task into its
+	// first prompt (scanner.RelevantFiles). 0 disables.
+	WarmStartFiles int `json:"warm_start_files,omitempty"`
+
+	// Keep an embedding index of the code in .forge/index/ and add the
+	// snippets closest to each task to its prompt (internal/codeindex).
+	CodeIndex      bool   `json:"code_index,omitempty"`
+	EmbeddingModel string `json:"embedding_model,omitempty"` // Ollama model; "" = provider.DefaultEmbeddingModel
+}
+
+// WorkspaceRepo is a repository, other than the project root, that tasks
+// can work in. Empty commands and base branch fall back to Settings.
+type WorkspaceRepo struct {
+	Path         string `json:"path"` // relative to the project root
+	BaseBranch   string `json:"base_branch,omitempty"`
+	RemoteURL    string `json:"remote_url,omitempty"`
+	TestCommand  string `json:"test_command,omitempty"`
+	BuildCommand string `json:"build_command,omitempty"`
+}
+
+// FindRepo returns the workspace repository at path, or nil.
+func (s *Settings) FindRepo(path string) *WorkspaceRepo {
+	for i := range s.Repos {
+		if s.Repos[i].Path == path {
+			return &s.Repos[i]
+		}
+	}
+	return nil
+}
+
+// ForRepo returns the settings a task in repo runs with: the repository's
+// base branch, remote and commands in place of the project's, and no lint
+// command. An empty or
+// unknown repo returns s itself.
+func (s *Settings) ForRepo(repo string) *Settings {
+	r := s.FindRepo(repo)
+	if repo == "" || r == nil {
+		return s
+	}
+	c := *s
+	c.RemoteURL = r.RemoteURL
+	c.LintCommand = "" // written for the root project's stack
+	c.SparsePaths = nil
+	if r.BaseBranch != "" {
+		c.BaseBranch = r.BaseBranch
+	}
+	if r.TestCommand != "" {
+		c.TestCommand = r.TestCommand
+	}
+	if r.BuildCommand != "" {
+		c.BuildCommand = r.BuildCommand
+	}
+	return &c
+}
+
+// CommitPolicy limits what a task may commit. The zero value allows
+// everything.
+type CommitPolicy struct {
+	MaxFileMB        int  `json:"max_file_mb,omitempty"`       // largest single file; 0 = no limit
+	ScanSecrets      bool `json:"scan_secrets,omitempty"`      // reject credential-looking content
+	ProtectWorkflows bool `json:"protect_workflows,omitempty"` // reject changes under .github/workflows/
 }
 
 // MaxTurnsConfig maps task complexity to max claude turns.
@@ -165,6 +460,41 @@ func Save(root string, s *State) error {
 	return nil
 }
 
+// ErrStateChanged is returned by SaveIfUnchanged when state.json was
+// rewritten by someone else since it was last read or saved.
+var ErrStateChanged = errors.New("state.json was changed by another process")
+
+// Fingerprint returns a hash of .forge/state.json as it is on disk, or ""
+// if it doesn't exist.
+func Fingerprint(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(ForgeDir(root), stateFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading state file: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SaveIfUnchanged saves s only if state.json still has the given
+// fingerprint, so a concurrent edit from another terminal (or a pulled
+// commit) is never overwritten. Returns the fingerprint of the saved file.
+func SaveIfUnchanged(root string, s *State, fingerprint string) (string, error) {
+	current, err := Fingerprint(root)
+	if err != nil {
+		return fingerprint, err
+	}
+	if current != fingerprint {
+		return fingerprint, ErrStateChanged
+	}
+	if err := Save(root, s); err != nil {
+		return fingerprint, err
+	}
+	return Fingerprint(root)
+}
+
 // Init creates a new default state and saves it. Errors if state already exists.
 func Init(root string) (*State, error) {
 	path := filepath.Join(ForgeDir(root), stateFileName)
@@ -302,6 +632,178 @@ func (s *State) CancelTask(id string, reason string) error {
 	return nil
 }
 
+// EffectiveType returns the task's type, defaulting to TaskTypeCode.
+func (t Task) EffectiveType() TaskType {
+	if t.Type == "" {
+		return TaskTypeCode
+	}
+	return t.Type
+}
+
+// ForHuman reports whether a person, not the runner, does the task.
+func (t Task) ForHuman() bool {
+	return t.Owner == OwnerHuman
+}
+
+// SkipTask marks a pending task as skipped with the given reason. Pending
+// tasks that depend on it, directly or transitively, are skipped as well.
+// Returns the IDs of all skipped tasks, starting with id.
+func (s *State) SkipTask(id string, reason string) ([]string, error) {
+	t := s.FindTask(id)
+	if t == nil {
+		return nil, fmt.Errorf("task %q not found", id)
+	}
+	if t.Status != TaskPending {
+		return nil, fmt.Errorf("cannot skip task %q: status is %s", id, t.Status)
+	}
+	t.Status = TaskSkipped
+	t.SkippedReason = reason
+	skipped := []string{id}
+
+	changed := true
+	for changed {
+		changed = false
+		for i := range s.Tasks {
+			if s.Tasks[i].Status != TaskPending {
+				continue
+			}
+			for _, dep := range s.Tasks[i].DependsOn {
+				if containsID(skipped, dep) {
+					s.Tasks[i].Status = TaskSkipped
+					s.Tasks[i].SkippedReason = fmt.Sprintf("depends on skipped %s", dep)
+					skipped = append(skipped, s.Tasks[i].ID)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return skipped, nil
+}
+
+// StartFrom skips, for now, every pending task ordered before id and any
+// pending task id depends on, so execution starts at id — for when the
+// early tasks were completed outside forge. Unlike other skips, deferred
+// tasks satisfy the dependencies of later tasks. Tasks deferred by an
+// earlier call are restored first, so starting from the first task undoes
+// it. Returns the IDs of the deferred tasks.
+func (s *State) StartFrom(id string) ([]string, error) {
+	t := s.FindTask(id)
+	if t == nil {
+		return nil, fmt.Errorf("task %q not found", id)
+	}
+	if t.Status != TaskPending && !t.Deferred {
+		return nil, fmt.Errorf("cannot start from task %q: status is %s", id, t.Status)
+	}
+
+	for i := range s.Tasks {
+		if s.Tasks[i].Deferred {
+			s.Tasks[i].Status = TaskPending
+			s.Tasks[i].SkippedReason = ""
+			s.Tasks[i].Deferred = false
+		}
+	}
+
+	// Everything id needs, directly or transitively
+	needed := make(map[string]bool)
+	var walk func(id string)
+	walk = func(id string) {
+		if t := s.FindTask(id); t != nil {
+			for _, dep := range t.DependsOn {
+				if !needed[dep] {
+					needed[dep] = true
+					walk(dep)
+				}
+			}
+		}
+	}
+	walk(id)
+
+	var deferred []string
+	before := true
+	for i := range s.Tasks {
+		task := &s.Tasks[i]
+		if task.ID == id {
+			before = false
+			continue
+		}
+		if task.Status != TaskPending || !(before || needed[task.ID]) {
+			continue
+		}
+		task.Status = TaskSkipped
+		task.SkippedReason = fmt.Sprintf("skipped for now: starting from %s", id)
+		task.Deferred = true
+		deferred = append(deferred, task.ID)
+	}
+	return deferred, nil
+}
+
+// MarkTaskDone records a failed task, or a pending human-owned task, as
+// completed by hand at the given commit. Tasks that were skipped only because of the failure (directly or
+// transitively) and whose dependencies are no longer blocked revert to
+// pending; deferred tasks (see StartFrom) count as done. Manually skipped
+// tasks keep their status. Returns the IDs of the unblocked tasks.
+func (s *State) MarkTaskDone(id, sha string, now time.Time) ([]string, error) {
+	t := s.FindTask(id)
+	if t == nil {
+		return nil, fmt.Errorf("task %q not found", id)
+	}
+	if t.Status != TaskFailed && !(t.Status == TaskPending && t.ForHuman()) {
+		return nil, fmt.Errorf("cannot mark task %q done: status is %s", id, t.Status)
+	}
+	t.Status = TaskDone
+	t.GitSHA = sha
+	t.CompletedAt = &now
+
+	statusMap := make(map[string]TaskStatus, len(s.Tasks))
+	for _, t := range s.Tasks {
+		statusMap[t.ID] = t.Status
+		if t.Deferred {
+			statusMap[t.ID] = TaskDone
+		}
+	}
+
+	var unblocked []string
+	cleared := []string{id}
+	changed := true
+	for changed {
+		changed = false
+		for i := range s.Tasks {
+			task := &s.Tasks[i]
+			if task.Status != TaskSkipped || task.SkippedReason != "" {
+				continue
+			}
+			downstream, blocked := false, false
+			for _, dep := range task.DependsOn {
+				if containsID(cleared, dep) {
+					downstream = true
+				}
+				switch statusMap[dep] {
+				case TaskFailed, TaskCancelled, TaskSkipped:
+					blocked = true
+				}
+			}
+			if downstream && !blocked {
+				task.Status = TaskPending
+				statusMap[task.ID] = TaskPending
+				cleared = append(cleared, task.ID)
+				unblocked = append(unblocked, task.ID)
+				changed = true
+			}
+		}
+	}
+	return unblocked, nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
 // BumpPlanVersion increments PlanVersion, records a PlanRevision, and returns the new version.
 func (s *State) BumpPlanVersion(summary string) int {
 	s.PlanVersion++
@@ -325,6 +827,70 @@ func (s *State) AddConversationMessage(role, content string) {
 	}
 }
 
+// CurrentConversation returns the name of the current conversation branch.
+func (s *State) CurrentConversation() string {
+	if s.ConversationBranch == "" {
+		return MainConversation
+	}
+	return s.ConversationBranch
+}
+
+// ConversationBranches lists every conversation branch, the current one
+// included: main first, then the rest by name.
+func (s *State) ConversationBranches() []string {
+	names := []string{s.CurrentConversation()}
+	for _, f := range s.ConversationForks {
+		names = append(names, f.Name)
+	}
+	slices.Sort(names)
+	if i := slices.Index(names, MainConversation); i > 0 {
+		names = slices.Insert(slices.Delete(names, i, i+1), 0, MainConversation)
+	}
+	return names
+}
+
+func (s *State) hasConversation(name string) bool {
+	return slices.Contains(s.ConversationBranches(), name)
+}
+
+// ForkConversation starts a new conversation branch with a copy of the
+// current history and makes it current.
+func (s *State) ForkConversation(name string) error {
+	if name == "" {
+		return fmt.Errorf("a branch name is required")
+	}
+	if s.hasConversation(name) {
+		return fmt.Errorf("conversation branch %q already exists", name)
+	}
+	s.ConversationForks = append(s.ConversationForks, ConversationFork{
+		Name:    s.CurrentConversation(),
+		History: s.ConversationHistory,
+	})
+	s.ConversationHistory = slices.Clone(s.ConversationHistory)
+	s.ConversationBranch = name
+	return nil
+}
+
+// SwitchConversation makes the named branch current, setting the current
+// one aside.
+func (s *State) SwitchConversation(name string) error {
+	if name == s.CurrentConversation() {
+		return nil
+	}
+	i := slices.IndexFunc(s.ConversationForks, func(f ConversationFork) bool { return f.Name == name })
+	if i < 0 {
+		return fmt.Errorf("no conversation branch %q (have %s)", name, strings.Join(s.ConversationBranches(), ", "))
+	}
+	target := s.ConversationForks[i]
+	s.ConversationForks[i] = ConversationFork{Name: s.CurrentConversation(), History: s.ConversationHistory}
+	s.ConversationHistory = target.History
+	s.ConversationBranch = target.Name
+	if target.Name == MainConversation {
+		s.ConversationBranch = ""
+	}
+	return nil
+}
+
 // TrimConversationHistory keeps the last maxMessages messages.
 // Older messages are summarized into a single system message at the start.
 func (s *State) TrimConversationHistory(maxMessages int) {
@@ -345,11 +911,16 @@ func (s *State) TrimConversationHistory(maxMessages int) {
 // ExecutableTasks returns pending tasks whose dependencies are all done.
 // Tasks whose dependencies include a failed, cancelled, or skipped task are automatically skipped.
 // This cascades: if A fails, B (depends on A) is skipped, and C (depends on B) is also skipped.
+// Deferred tasks (see StartFrom) count as done. Human-owned tasks are never
+// returned; their dependents wait until they are marked done.
 func (s *State) ExecutableTasks() []Task {
 	// Build a status map for quick lookup
 	statusMap := make(map[string]TaskStatus, len(s.Tasks))
 	for _, t := range s.Tasks {
 		statusMap[t.ID] = t.Status
+		if t.Deferred {
+			statusMap[t.ID] = TaskDone
+		}
 	}
 
 	// Skip tasks with blocked dependencies. Loop until stable since skips cascade.
@@ -374,7 +945,7 @@ func (s *State) ExecutableTasks() []Task {
 
 	var result []Task
 	for _, t := range s.Tasks {
-		if t.Status != TaskPending {
+		if t.Status != TaskPending || t.ForHuman() {
 			continue
 		}
 		allDepsDone := true
@@ -391,8 +962,46 @@ func (s *State) ExecutableTasks() []Task {
 	return result
 }
 
+// gitignoreEntries are the .forge/ paths that are machine-specific or
+// generated and must never be committed.
+var gitignoreEntries = []string{"logs/", "journal.jsonl", "resources.json", "forge.sock", "cache/", "artifacts/"}
+
+// EnsureGitignore writes .forge/.gitignore, or appends the entries of
+// gitignoreEntries an existing one lacks, so projects set up by an older
+// forge pick up new entries. Lines the user added are kept.
+func EnsureGitignore(root string) error {
+	if err := os.MkdirAll(ForgeDir(root), 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(ForgeDir(root), ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	content := string(data)
+	missing := false
+	for _, entry := range gitignoreEntries {
+		if present[entry] {
+			continue
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += entry + "\n"
+		missing = true
+	}
+	if !missing {
+		return nil
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
 // InitForgeDir creates the .forge directory structure and its .gitignore.
-// Creates: .forge/, .forge/.gitignore (ignoring logs/), .forge/logs/, .forge/state.json
+// Creates: .forge/, .forge/.gitignore (see EnsureGitignore), .forge/logs/, .forge/state.json
 func InitForgeDir(root string, providerCfg *provider.Config, gitInitialized bool, remoteURL string) (*State, error) {
 	dir := ForgeDir(root)
 
@@ -403,8 +1012,7 @@ func InitForgeDir(root string, providerCfg *provider.Config, gitInitialized bool
 	}
 
 	// Create .forge/.gitignore
-	gitignorePath := filepath.Join(dir, ".gitignore")
-	if err := os.WriteFile(gitignorePath, []byte("logs/\n"), 0644); err != nil {
+	if err := EnsureGitignore(root); err != nil {
 		return nil, fmt.Errorf("creating .forge/.gitignore: %w", err)
 	}
 
@@ -452,6 +1060,13 @@ func (s *State) GenerateReplanContext() string {
 	if s.ProjectName != "" {
 		fmt.Fprintf(&b, "Project: %s\n", s.ProjectName)
 	}
+	if s.Settings != nil && len(s.Settings.Repos) > 0 {
+		paths := make([]string, len(s.Settings.Repos))
+		for i, r := range s.Settings.Repos {
+			paths[i] = r.Path
+		}
+		fmt.Fprintf(&b, "Workspace repositories (task \"repo\"): %s\n", strings.Join(paths, ", "))
+	}
 
 	completed := s.CompletedTasks()
 	if len(completed) > 0 {
@@ -478,6 +1093,12 @@ func (s *State) GenerateReplanContext() string {
 				detail += fmt.Sprintf(" (failed after %d retries)", t.Retries)
 			}
 			fmt.Fprintf(&b, "  %s: %s\n", t.ID, detail)
+			if rc := t.RootCause; rc != nil {
+				fmt.Fprintf(&b, "    Root cause: %s\n", rc.Summary)
+				for _, s := range rc.Suggestions {
+					fmt.Fprintf(&b, "    Suggested change: %s\n", s)
+				}
+			}
 		}
 	}
 
@@ -2,6 +2,7 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -199,6 +200,35 @@ func TestSave(t *testing.T) {
 	})
 }
 
+func TestSaveIfUnchanged(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	s := &State{Phase: PhaseExecution}
+
+	sum, err := Fingerprint(root)
+	if sum != "" || err != nil {
+		t.Fatalf("Fingerprint() of a missing file = %q, %v", sum, err)
+	}
+	if sum, err = SaveIfUnchanged(root, s, sum); err != nil || sum == "" {
+		t.Fatalf("first SaveIfUnchanged() = %q, %v", sum, err)
+	}
+	if sum, err = SaveIfUnchanged(root, s, sum); err != nil {
+		t.Fatalf("SaveIfUnchanged() on our own save: %v", err)
+	}
+
+	// Another terminal rewrites the plan
+	other := &State{Phase: PhaseReview, ProjectName: "theirs"}
+	if err := Save(root, other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveIfUnchanged(root, s, sum); !errors.Is(err, ErrStateChanged) {
+		t.Fatalf("SaveIfUnchanged() error = %v, want ErrStateChanged", err)
+	}
+	if got, _ := Load(root); got.ProjectName != "theirs" {
+		t.Errorf("state.json was overwritten: %+v", got)
+	}
+}
+
 func TestNextTaskID(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -392,6 +422,199 @@ func TestFindTask(t *testing.T) {
 	})
 }
 
+func TestTaskType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ       TaskType
+		valid     bool
+		effective TaskType
+	}{
+		{"", true, TaskTypeCode},
+		{TaskTypeCode, true, TaskTypeCode},
+		{TaskTypeVerify, true, TaskTypeVerify},
+		{TaskTypeManual, true, TaskTypeManual},
+		{"script", false, "script"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			t.Parallel()
+			if got := ValidTaskType(tt.typ); got != tt.valid {
+				t.Errorf("ValidTaskType(%q) = %v, want %v", tt.typ, got, tt.valid)
+			}
+			if got := (Task{Type: tt.typ}).EffectiveType(); got != tt.effective {
+				t.Errorf("EffectiveType() = %q, want %q", got, tt.effective)
+			}
+		})
+	}
+}
+
+func TestSkipTask(t *testing.T) {
+	t.Parallel()
+	newState := func() *State {
+		return &State{Tasks: []Task{
+			{ID: "task-001", Status: TaskDone},
+			{ID: "task-002", Status: TaskPending, DependsOn: []string{"task-001"}},
+			{ID: "task-003", Status: TaskPending, DependsOn: []string{"task-002"}},
+			{ID: "task-004", Status: TaskPending, DependsOn: []string{"task-003"}},
+			{ID: "task-005", Status: TaskPending, DependsOn: []string{"task-001"}},
+			{ID: "task-006", Status: TaskInProgress},
+		}}
+	}
+
+	t.Run("cascades to transitive dependents", func(t *testing.T) {
+		t.Parallel()
+		s := newState()
+		skipped, err := s.SkipTask("task-002", "not needed")
+		if err != nil {
+			t.Fatalf("SkipTask() error: %v", err)
+		}
+		if strings.Join(skipped, ",") != "task-002,task-003,task-004" {
+			t.Errorf("skipped = %v", skipped)
+		}
+		if got := s.FindTask("task-002").SkippedReason; got != "not needed" {
+			t.Errorf("reason = %q, want %q", got, "not needed")
+		}
+		if got := s.FindTask("task-004").SkippedReason; got != "depends on skipped task-003" {
+			t.Errorf("cascaded reason = %q", got)
+		}
+		if s.FindTask("task-005").Status != TaskPending {
+			t.Error("unrelated task-005 should stay pending")
+		}
+	})
+
+	for _, id := range []string{"task-001", "task-006", "task-404"} {
+		t.Run("rejects "+id, func(t *testing.T) {
+			t.Parallel()
+			if _, err := newState().SkipTask(id, "x"); err == nil {
+				t.Errorf("SkipTask(%s) should fail", id)
+			}
+		})
+	}
+}
+
+func TestStartFrom(t *testing.T) {
+	t.Parallel()
+	newState := func() *State {
+		return &State{Tasks: []Task{
+			{ID: "task-001", Status: TaskDone},
+			{ID: "task-002", Status: TaskPending, DependsOn: []string{"task-001"}},
+			{ID: "task-003", Status: TaskCancelled},
+			{ID: "task-004", Status: TaskPending, DependsOn: []string{"task-002", "task-006"}},
+			{ID: "task-005", Status: TaskPending},
+			{ID: "task-006", Status: TaskPending},
+			{ID: "task-007", Status: TaskInProgress},
+		}}
+	}
+
+	t.Run("defers earlier tasks and dependencies", func(t *testing.T) {
+		t.Parallel()
+		s := newState()
+		deferred, err := s.StartFrom("task-004")
+		if err != nil {
+			t.Fatalf("StartFrom() error: %v", err)
+		}
+		if strings.Join(deferred, ",") != "task-002,task-006" {
+			t.Errorf("deferred = %v, want [task-002 task-006]", deferred)
+		}
+		if got := s.FindTask("task-002"); got.Status != TaskSkipped || !got.Deferred || got.SkippedReason != "skipped for now: starting from task-004" {
+			t.Errorf("task-002 = %+v", got)
+		}
+		if s.FindTask("task-005").Status != TaskPending {
+			t.Error("later unrelated task-005 should stay pending")
+		}
+
+		var ids []string
+		for _, task := range s.ExecutableTasks() {
+			ids = append(ids, task.ID)
+		}
+		if strings.Join(ids, ",") != "task-004,task-005" {
+			t.Errorf("ExecutableTasks() = %v, want deferred dependencies to count as done", ids)
+		}
+	})
+
+	t.Run("starting from the first task restores deferred tasks", func(t *testing.T) {
+		t.Parallel()
+		s := newState()
+		if _, err := s.StartFrom("task-005"); err != nil {
+			t.Fatal(err)
+		}
+		deferred, err := s.StartFrom("task-002")
+		if err != nil {
+			t.Fatalf("StartFrom() error: %v", err)
+		}
+		if len(deferred) != 0 {
+			t.Errorf("deferred = %v, want none", deferred)
+		}
+		for _, id := range []string{"task-002", "task-004"} {
+			if got := s.FindTask(id); got.Status != TaskPending || got.Deferred || got.SkippedReason != "" {
+				t.Errorf("%s = %+v, want restored to pending", id, got)
+			}
+		}
+	})
+
+	for _, id := range []string{"task-001", "task-003", "task-007", "task-404"} {
+		t.Run("rejects "+id, func(t *testing.T) {
+			t.Parallel()
+			if _, err := newState().StartFrom(id); err == nil {
+				t.Errorf("StartFrom(%s) should fail", id)
+			}
+		})
+	}
+}
+
+func TestMarkTaskDone(t *testing.T) {
+	t.Parallel()
+	newState := func() *State {
+		return &State{Tasks: []Task{
+			{ID: "task-001", Status: TaskFailed},
+			{ID: "task-002", Status: TaskSkipped, DependsOn: []string{"task-001"}},
+			{ID: "task-003", Status: TaskSkipped, DependsOn: []string{"task-002"}},
+			{ID: "task-004", Status: TaskSkipped, DependsOn: []string{"task-001", "task-009"}},
+			{ID: "task-005", Status: TaskSkipped, DependsOn: []string{"task-001"}, SkippedReason: "not needed"},
+			{ID: "task-006", Status: TaskSkipped, DependsOn: []string{"task-005"}, SkippedReason: "depends on skipped task-005"},
+			{ID: "task-007", Status: TaskSkipped, DependsOn: []string{"task-001", "task-010"}},
+			{ID: "task-009", Status: TaskFailed},
+			{ID: "task-010", Status: TaskSkipped, Deferred: true, SkippedReason: "skipped for now: starting from task-001"},
+		}}
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("unblocks failure-cascaded dependents", func(t *testing.T) {
+		t.Parallel()
+		s := newState()
+		unblocked, err := s.MarkTaskDone("task-001", "abc1234", now)
+		if err != nil {
+			t.Fatalf("MarkTaskDone() error: %v", err)
+		}
+		if strings.Join(unblocked, ",") != "task-002,task-003,task-007" {
+			t.Errorf("unblocked = %v, want [task-002 task-003 task-007]", unblocked)
+		}
+		task := s.FindTask("task-001")
+		if task.Status != TaskDone || task.GitSHA != "abc1234" || task.CompletedAt == nil || !task.CompletedAt.Equal(now) {
+			t.Errorf("task-001 = %+v, want done at abc1234", task)
+		}
+		for id, want := range map[string]TaskStatus{
+			"task-004": TaskSkipped, // still blocked by task-009
+			"task-005": TaskSkipped, // skipped by hand
+			"task-006": TaskSkipped,
+			"task-010": TaskSkipped, // deferred, which satisfies task-007
+		} {
+			if got := s.FindTask(id).Status; got != want {
+				t.Errorf("%s status = %s, want %s", id, got, want)
+			}
+		}
+	})
+
+	for _, id := range []string{"task-002", "task-404"} {
+		t.Run("rejects "+id, func(t *testing.T) {
+			t.Parallel()
+			if _, err := newState().MarkTaskDone(id, "abc", now); err == nil {
+				t.Errorf("MarkTaskDone(%s) should fail", id)
+			}
+		})
+	}
+}
+
 func TestCancelTask(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -528,6 +751,51 @@ func TestAddConversationMessage(t *testing.T) {
 	})
 }
 
+func TestConversationBranches(t *testing.T) {
+	t.Parallel()
+	s := &State{}
+	s.AddConversationMessage("user", "Build an order service")
+	s.AddConversationMessage("assistant", "Monolith or services?")
+
+	if err := s.ForkConversation("microservices"); err != nil {
+		t.Fatalf("ForkConversation() error: %v", err)
+	}
+	s.AddConversationMessage("user", "Split it into services")
+	if got := s.CurrentConversation(); got != "microservices" {
+		t.Errorf("CurrentConversation() = %q, want microservices", got)
+	}
+	if err := s.ForkConversation("main"); err == nil {
+		t.Error("forking onto an existing branch name should fail")
+	}
+	if err := s.ForkConversation(""); err == nil {
+		t.Error("forking without a name should fail")
+	}
+
+	if err := s.SwitchConversation("main"); err != nil {
+		t.Fatalf("SwitchConversation(main) error: %v", err)
+	}
+	if s.ConversationBranch != "" || len(s.ConversationHistory) != 2 {
+		t.Errorf("main branch = %q with %d messages, want \"\" with 2", s.ConversationBranch, len(s.ConversationHistory))
+	}
+	s.AddConversationMessage("user", "Keep it one service")
+
+	if err := s.SwitchConversation("microservices"); err != nil {
+		t.Fatalf("SwitchConversation(microservices) error: %v", err)
+	}
+	if n := len(s.ConversationHistory); n != 3 || s.ConversationHistory[2].Content != "Split it into services" {
+		t.Errorf("microservices history = %+v", s.ConversationHistory)
+	}
+	if err := s.SwitchConversation("serverless"); err == nil {
+		t.Error("switching to an unknown branch should fail")
+	}
+	if got, want := strings.Join(s.ConversationBranches(), ","), "main,microservices"; got != want {
+		t.Errorf("ConversationBranches() = %s, want %s", got, want)
+	}
+	if len(s.ConversationForks) != 1 || len(s.ConversationForks[0].History) != 3 {
+		t.Errorf("main should be set aside with its 3 messages: %+v", s.ConversationForks)
+	}
+}
+
 func TestTrimConversationHistory(t *testing.T) {
 	t.Parallel()
 	t.Run("no-op when under limit", func(t *testing.T) {
@@ -602,6 +870,31 @@ func TestExecutableTasks(t *testing.T) {
 		}
 	})
 
+	t.Run("human-owned tasks never run and hold back dependents", func(t *testing.T) {
+		s := &State{
+			Tasks: []Task{
+				{ID: "task-001", Status: TaskPending, Owner: OwnerHuman, Assignee: "alice"},
+				{ID: "task-002", Status: TaskPending, DependsOn: []string{"task-001"}},
+				{ID: "task-003", Status: TaskPending, Owner: OwnerAgent},
+			},
+		}
+
+		exec := s.ExecutableTasks()
+		if len(exec) != 1 || exec[0].ID != "task-003" {
+			t.Fatalf("ExecutableTasks() = %v, want only task-003", exec)
+		}
+		if s.FindTask("task-002").Status != TaskPending {
+			t.Error("dependent of a human task should wait, not be skipped")
+		}
+
+		if _, err := s.MarkTaskDone("task-001", "abc1234", time.Now()); err != nil {
+			t.Fatalf("MarkTaskDone(human task) error: %v", err)
+		}
+		if exec := s.ExecutableTasks(); len(exec) != 2 || exec[0].ID != "task-002" {
+			t.Errorf("after marking done, ExecutableTasks() = %v", exec)
+		}
+	})
+
 	t.Run("respects dependency order", func(t *testing.T) {
 		s := &State{
 			Tasks: []Task{
@@ -685,7 +978,8 @@ func TestGenerateReplanContext(t *testing.T) {
 			{ID: "task-001", Title: "Initialize Go project", Status: TaskDone},
 			{ID: "task-002", Title: "Add user authentication with JWT", Status: TaskDone},
 			{ID: "task-003", Title: "Add GraphQL endpoint", Status: TaskCancelled, CancelledReason: "Replaced by REST in plan v2"},
-			{ID: "task-004", Title: "Add payment integration", Status: TaskFailed, Retries: 3},
+			{ID: "task-004", Title: "Add payment integration", Status: TaskFailed, Retries: 3,
+				RootCause: &RootCause{Summary: "Stripe keys are not configured", Suggestions: []string{"Add a manual task to set STRIPE_KEY"}}},
 			{ID: "task-005", Title: "Add order management endpoints", Status: TaskPending},
 			{ID: "task-006", Title: "Add WebSocket notifications", Status: TaskPending},
 		},
@@ -708,6 +1002,7 @@ func TestGenerateReplanContext(t *testing.T) {
 		{"pending task-006", "task-006: Add WebSocket notifications"},
 		{"failed header", "FAILED TASKS"},
 		{"failed task-004", "task-004: Add payment integration (failed after 3 retries)"},
+		{"root cause", "    Root cause: Stripe keys are not configured\n    Suggested change: Add a manual task to set STRIPE_KEY\n"},
 		{"cancelled header", "CANCELLED TASKS"},
 		{"cancelled task-003", "task-003: Add GraphQL endpoint (Replaced by REST in plan v2)"},
 		{"instruction keep", "Keep all completed tasks"},
@@ -905,8 +1200,8 @@ func TestInitForgeDir(t *testing.T) {
 	if err != nil {
 		t.Fatalf(".gitignore not created: %v", err)
 	}
-	if string(data) != "logs/\n" {
-		t.Errorf(".gitignore content = %q, want %q", string(data), "logs/\n")
+	if string(data) != "logs/\njournal.jsonl\nresources.json\nforge.sock\ncache/\nartifacts/\n" {
+		t.Errorf(".gitignore content = %q, want %q", string(data), "logs/\njournal.jsonl\nresources.json\nforge.sock\ncache/\nartifacts/\n")
 	}
 
 	// Verify .forge/logs/ was created
@@ -920,6 +1215,36 @@ func TestInitForgeDir(t *testing.T) {
 	}
 }
 
+func TestEnsureGitignore_AppendsMissingEntries(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	path := filepath.Join(ForgeDir(root), ".gitignore")
+	if err := os.MkdirAll(ForgeDir(root), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Written by an older forge, plus a line of the user's
+	if err := os.WriteFile(path, []byte("logs/\njournal.jsonl\nmy-notes.txt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureGitignore(root); err != nil {
+		t.Fatalf("EnsureGitignore() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "logs/\njournal.jsonl\nmy-notes.txt\nresources.json\nforge.sock\ncache/\nartifacts/\n"
+	if string(data) != want {
+		t.Errorf(".gitignore = %q, want %q", data, want)
+	}
+
+	// Nothing to add the second time
+	if err := EnsureGitignore(root); err != nil {
+		t.Fatalf("EnsureGitignore() error: %v", err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != want {
+		t.Errorf(".gitignore after a second call = %q, want it unchanged", again)
+	}
+}
+
 func TestProjectSnapshotRoundTrip(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -1004,3 +1329,70 @@ func TestLogDir(t *testing.T) {
 		t.Error("logs should be a directory")
 	}
 }
+
+func TestStartRunAndAggregate(t *testing.T) {
+	t.Parallel()
+	s := &State{}
+	if s.LastRun() != nil {
+		t.Error("LastRun on empty state should be nil")
+	}
+
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	id1 := s.StartRun(t0)
+	id2 := s.StartRun(t0.Add(time.Hour))
+	if id1 != 1 || id2 != 2 {
+		t.Fatalf("run IDs = %d, %d; want 1, 2", id1, id2)
+	}
+	if s.LastRun().ID != 2 || s.LastRun().Status != RunInProgress {
+		t.Errorf("LastRun = %+v, want in-progress run 2", s.LastRun())
+	}
+
+	r1 := s.FindRun(1)
+	end := t0.Add(10 * time.Minute)
+	r1.FinishedAt = &end
+	r1.Completed, r1.Failed, r1.Retries, r1.CostUSD, r1.TokensUsed = 2, 1, 3, 0.5, 1000
+	r2 := s.FindRun(2)
+	r2.Completed, r2.Skipped, r2.CostUSD = 1, 2, 0.25
+
+	if s.FindRun(99) != nil {
+		t.Error("FindRun for unknown ID should be nil")
+	}
+
+	totals := AggregateRuns(s.Runs)
+	want := RunTotals{Runs: 2, Completed: 3, Failed: 1, Skipped: 2, Retries: 3,
+		TokensUsed: 1000, CostUSD: 0.75, Duration: 10 * time.Minute}
+	if totals != want {
+		t.Errorf("AggregateRuns = %+v, want %+v", totals, want)
+	}
+}
+
+func TestSettingsForRepo(t *testing.T) {
+	t.Parallel()
+	s := &Settings{
+		BaseBranch: "main", RemoteURL: "git@github.com:o/api.git",
+		TestCommand: "go test ./...", BuildCommand: "go build ./...", LintCommand: "go vet ./...",
+		Repos: []WorkspaceRepo{{Path: "web", BaseBranch: "develop", RemoteURL: "git@github.com:o/web.git", TestCommand: "npm test"}},
+	}
+
+	if got := s.ForRepo(""); got != s {
+		t.Error("ForRepo(\"\") should return the project settings")
+	}
+	if got := s.ForRepo("mobile"); got != s {
+		t.Error("ForRepo of an unknown repo should return the project settings")
+	}
+
+	got := s.ForRepo("web")
+	if got.BaseBranch != "develop" || got.RemoteURL != "git@github.com:o/web.git" || got.TestCommand != "npm test" {
+		t.Errorf("ForRepo(web) = base %q, remote %q, test %q", got.BaseBranch, got.RemoteURL, got.TestCommand)
+	}
+	if got.BuildCommand != "go build ./..." || got.LintCommand != "" {
+		t.Errorf("ForRepo(web) build %q, lint %q; want the project build and no lint", got.BuildCommand, got.LintCommand)
+	}
+	if s.TestCommand != "go test ./..." {
+		t.Error("ForRepo modified the project settings")
+	}
+	s.SparsePaths = []string{"services/api"}
+	if got := s.ForRepo("web"); got.SparsePaths != nil {
+		t.Errorf("ForRepo(web).SparsePaths = %v; sparse paths belong to the project root", got.SparsePaths)
+	}
+}
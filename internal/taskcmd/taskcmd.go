@@ -0,0 +1,376 @@
+// Package taskcmd implements `forge task`, which edits the plan in
+// .forge/state.json without the TUI so scripts and editors can manage it.
+// Changes go through the same validation as the review screen, and a
+// running forge picks them up before its next task.
+package taskcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/manasm11/forge/internal/cli"
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui"
+)
+
+// Run executes `forge task <action> ...` against the state in root and
+// writes the result to w, as JSON when --json is given.
+func Run(root string, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("task: expected an action (see `forge help task`)")
+	}
+	action, args := args[0], args[1:]
+
+	fingerprint, err := state.Fingerprint(root)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	s, err := state.Load(root)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if s == nil {
+		return fmt.Errorf("no forge session here")
+	}
+
+	var (
+		task    state.Task
+		message string
+		changed bool
+	)
+	if action == "add" {
+		task.Complexity = "medium"
+	}
+	fs, opts := newFlagSet(action, &task)
+	switch action {
+	case "add":
+		if err := parseNone(fs, args); err != nil {
+			return err
+		}
+		if task, err = Add(s, task); err != nil {
+			return err
+		}
+		message, changed = fmt.Sprintf("Added %s: %s", task.ID, task.Title), true
+	case "edit":
+		// A first pass finds the task, so the real one can start from its
+		// current fields and only change what the flags give.
+		var probe state.Task
+		probeFS, _ := newFlagSet(action, &probe)
+		id, err := parseID(probeFS, args)
+		if err != nil {
+			return err
+		}
+		existing := s.FindTask(id)
+		if existing == nil {
+			return fmt.Errorf("task %q not found", id)
+		}
+		task = cloneTask(*existing)
+		fs, opts = newFlagSet(action, &task)
+		if _, err := parseID(fs, args); err != nil {
+			return err
+		}
+		if err := Edit(s, task); err != nil {
+			return err
+		}
+		message, changed = fmt.Sprintf("Updated %s: %s", task.ID, task.Title), true
+	case "cancel":
+		id, err := parseID(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := s.CancelTask(id, opts.reason); err != nil {
+			return err
+		}
+		task = *s.FindTask(id)
+		message, changed = fmt.Sprintf("Cancelled %s", id), true
+	case "show":
+		id, err := parseID(fs, args)
+		if err != nil {
+			return err
+		}
+		t := s.FindTask(id)
+		if t == nil {
+			return fmt.Errorf("task %q not found", id)
+		}
+		task, message = *t, FormatShow(*t, s.Tasks)
+	case "list":
+		if err := parseNone(fs, args); err != nil {
+			return err
+		}
+		if opts.json {
+			return writeJSON(w, append([]state.Task{}, s.Tasks...))
+		}
+		if opts.ids {
+			for _, t := range s.Tasks {
+				fmt.Fprintln(w, t.ID)
+			}
+			return nil
+		}
+		_, err := io.WriteString(w, FormatList(s.Tasks))
+		return err
+	default:
+		return fmt.Errorf("task: unknown action %q (see `forge help task`)", action)
+	}
+
+	if changed {
+		if _, err := state.SaveIfUnchanged(root, s, fingerprint); err != nil {
+			if errors.Is(err, state.ErrStateChanged) {
+				return fmt.Errorf("state.json changed while this command ran; nothing was saved, try again")
+			}
+			return err
+		}
+		message += "; a running forge picks this up before its next task"
+	}
+	if opts.json {
+		return writeJSON(w, task)
+	}
+	_, err = fmt.Fprintln(w, strings.TrimRight(message, "\n"))
+	return err
+}
+
+// Add validates t as a new pending task and appends it to the plan with the
+// next free ID.
+func Add(s *state.State, t state.Task) (state.Task, error) {
+	t.ID = s.NextTaskID()
+	t.Status = state.TaskPending
+	t.PlanVersionCreated = s.PlanVersion
+	t.PlanVersionModified = s.PlanVersion
+	if err := validate(s.Tasks, t); err != nil {
+		return state.Task{}, err
+	}
+	s.Tasks = append(s.Tasks, t)
+	return t, nil
+}
+
+// Edit replaces the pending task with t's ID by t, after validation. Like
+// the review screen, only pending tasks can be edited.
+func Edit(s *state.State, t state.Task) error {
+	existing := s.FindTask(t.ID)
+	if existing == nil {
+		return fmt.Errorf("task %q not found", t.ID)
+	}
+	if existing.Status != state.TaskPending {
+		return fmt.Errorf("cannot edit task %q: it is %s", t.ID, existing.Status)
+	}
+	t.Status = existing.Status
+	t.PlanVersionModified = s.PlanVersion
+	if err := validate(s.Tasks, t); err != nil {
+		return err
+	}
+	*existing = t
+	return nil
+}
+
+// validate applies the review screen's checks to t, as if it replaced (or
+// was added to) tasks, and rejects dependency cycles.
+func validate(tasks []state.Task, t state.Task) error {
+	var others []state.Task
+	for _, o := range tasks {
+		if o.ID != t.ID {
+			others = append(others, o)
+		}
+	}
+	for _, dep := range t.DependsOn {
+		if dep == t.ID {
+			return fmt.Errorf("task %s cannot depend on itself", t.ID)
+		}
+	}
+	if err := tui.ValidateNewTask(others, t.Title, t.Description, t.Complexity, t.AcceptanceCriteria, t.DependsOn); err != nil {
+		return err
+	}
+	if !state.ValidTaskType(t.Type) {
+		return fmt.Errorf("type must be code, verify or manual (got %q)", t.Type)
+	}
+	if !state.ValidTaskOwner(t.Owner) {
+		return fmt.Errorf("owner must be agent or human (got %q)", t.Owner)
+	}
+	if cycle := tui.DetectCircularDependencies(append(others, t)); len(cycle) > 0 {
+		return fmt.Errorf("circular dependency detected: %s", strings.Join(cycle, " → "))
+	}
+	return nil
+}
+
+// FormatShow renders one task for `forge task show`: its status followed
+// by the same detail the review screen shows.
+func FormatShow(t state.Task, all []state.Task) string {
+	detail := tui.FormatTaskDetail(t, all)
+	id, rest, _ := strings.Cut(detail, "\n")
+	status := string(t.Status)
+	if t.Status == state.TaskCancelled && t.CancelledReason != "" {
+		status += " (" + t.CancelledReason + ")"
+	}
+	return fmt.Sprintf("%s\nStatus: %s\n%s", id, status, rest)
+}
+
+// FormatList renders one line per task: ID, status, complexity and title.
+func FormatList(tasks []state.Task) string {
+	if len(tasks) == 0 {
+		return "No tasks in the plan\n"
+	}
+	var b strings.Builder
+	for _, t := range tasks {
+		who := ""
+		if t.ForHuman() {
+			who = " (human" + tui.FormatAssignee(t.Assignee) + ")"
+		}
+		fmt.Fprintf(&b, "%-9s %-12s %-7s %s%s\n", t.ID, t.Status, t.Complexity, t.Title, who)
+	}
+	return b.String()
+}
+
+// ============================================================
+// Flags
+// ============================================================
+
+// Commands describes the task actions for help and shell completion. Run
+// does the parsing, so they are Raw.
+func Commands() []*cli.Command {
+	flags := func(action string) func(*flag.FlagSet) {
+		return func(fs *flag.FlagSet) { addFlags(fs, action, &state.Task{}) }
+	}
+	return []*cli.Command{
+		{Name: "add", Synopsis: "--title TEXT [flags]", Summary: "Add a pending task", Flags: flags("add"), Raw: true},
+		{Name: "edit", Synopsis: "TASK-ID [flags]", Summary: "Change the given fields of a pending task", Flags: flags("edit"), Args: cli.ArgTaskID, Raw: true},
+		{Name: "cancel", Synopsis: "[--reason TEXT] TASK-ID", Summary: "Cancel a pending task", Flags: flags("cancel"), Args: cli.ArgTaskID, Raw: true},
+		{Name: "show", Synopsis: "TASK-ID", Summary: "Show a task's details", Flags: flags("show"), Args: cli.ArgTaskID, Raw: true},
+		{Name: "list", Synopsis: "[--json | --ids]", Summary: "List the tasks in the plan", Flags: flags("list"), Raw: true},
+	}
+}
+
+// options holds the flags that are not task fields.
+type options struct {
+	json   bool
+	reason string // cancel
+	ids    bool   // list
+}
+
+// newFlagSet builds the flag set of one action.
+func newFlagSet(action string, t *state.Task) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet("task "+action, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs, addFlags(fs, action, t)
+}
+
+// addFlags registers the flags of one action. add and edit bind the task
+// fields into t, with t's current values as defaults so edit only changes
+// what is given.
+func addFlags(fs *flag.FlagSet, action string, t *state.Task) *options {
+	opts := &options{}
+	fs.BoolVar(&opts.json, "json", false, "print the result as JSON")
+	switch action {
+	case "cancel":
+		fs.StringVar(&opts.reason, "reason", "cancelled from the command line", "why the task is cancelled")
+	case "list":
+		fs.BoolVar(&opts.ids, "ids", false, "print only task IDs, one per line")
+	}
+	if action != "add" && action != "edit" {
+		return opts
+	}
+
+	fs.StringVar(&t.Title, "title", t.Title, "task title")
+	fs.StringVar(&t.Description, "description", t.Description, "task description")
+	fs.StringVar(&t.Complexity, "complexity", t.Complexity, "small, medium or large")
+	fs.Func("type", "code, verify or manual", func(v string) error { t.Type = state.TaskType(v); return nil })
+	fs.Func("owner", "agent or human", func(v string) error {
+		if t.Owner = state.TaskOwner(v); t.Owner == state.OwnerAgent {
+			t.Owner = ""
+		}
+		return nil
+	})
+	fs.StringVar(&t.Assignee, "assignee", t.Assignee, "who is on the task")
+	fs.StringVar(&t.Model, "model", t.Model, "model to run the task on (\"\" = the provider's)")
+	fs.Var(&listFlag{dst: &t.AcceptanceCriteria}, "criterion", "acceptance criterion (repeatable; replaces the list)")
+	fs.Var(&listFlag{dst: &t.DependsOn, commas: true}, "depends-on", "task IDs this depends on, comma-separated or repeated (\"\" clears)")
+	fs.Var(&listFlag{dst: &t.Commands}, "command", "verify command (repeatable; replaces the list)")
+	fs.Var(&listFlag{dst: &t.Artifacts}, "artifact", "artifact glob (repeatable; replaces the list)")
+	return opts
+}
+
+// listFlag is a repeatable flag whose first use replaces the existing list.
+type listFlag struct {
+	dst    *[]string
+	commas bool // split each value on commas
+	set    bool
+}
+
+func (l *listFlag) String() string {
+	if l.dst == nil {
+		return ""
+	}
+	return strings.Join(*l.dst, ", ")
+}
+
+func (l *listFlag) Set(v string) error {
+	if !l.set {
+		*l.dst, l.set = nil, true
+	}
+	values := []string{v}
+	if l.commas {
+		values = strings.Split(v, ",")
+	}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			*l.dst = append(*l.dst, v)
+		}
+	}
+	return nil
+}
+
+// parseArgs parses flags that may come before or after positional
+// arguments: `edit task-003 --title X` and `edit --title X task-003` both work.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%s: %w", fs.Name(), err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// parseID parses flags around exactly one task ID.
+func parseID(fs *flag.FlagSet, args []string) (string, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(positional) != 1 {
+		return "", fmt.Errorf("%s: expected exactly one task ID", fs.Name())
+	}
+	return positional[0], nil
+}
+
+// parseNone parses flags and rejects positional arguments.
+func parseNone(fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%s: unexpected argument %q", fs.Name(), positional[0])
+	}
+	return nil
+}
+
+// cloneTask copies t so flag edits don't alias the state's slices.
+func cloneTask(t state.Task) state.Task {
+	t.AcceptanceCriteria = append([]string(nil), t.AcceptanceCriteria...)
+	t.DependsOn = append([]string(nil), t.DependsOn...)
+	t.Commands = append([]string(nil), t.Commands...)
+	t.Artifacts = append([]string(nil), t.Artifacts...)
+	return t
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,237 @@
+package taskcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// newRoot saves a plan with a done task-001 and a pending task-002 that
+// depends on it.
+func newRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	s := &state.State{
+		Phase:       state.PhaseExecution,
+		PlanVersion: 1,
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskDone},
+			{ID: "task-002", Title: "API", Complexity: "medium", Status: state.TaskPending, DependsOn: []string{"task-001"}, AcceptanceCriteria: []string{"serves /health"}},
+		},
+	}
+	if err := state.Save(root, s); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func run(t *testing.T, root string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := Run(root, args, &out)
+	return out.String(), err
+}
+
+func load(t *testing.T, root string) *state.State {
+	t.Helper()
+	s, err := state.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// ============================================================
+// Run
+// ============================================================
+
+func TestRun_Add(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+
+	out, err := run(t, root, "add", "--title", "Docs", "--criterion", "README exists", "--depends-on", "task-001, task-002", "--owner", "human", "--json")
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	var got state.Task
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not a task: %v\n%s", err, out)
+	}
+	if got.ID != "task-003" || got.Complexity != "medium" || got.Owner != state.OwnerHuman || len(got.DependsOn) != 2 {
+		t.Errorf("added task = %+v", got)
+	}
+
+	saved := load(t, root).FindTask("task-003")
+	if saved == nil || saved.Status != state.TaskPending || saved.PlanVersionCreated != 1 {
+		t.Errorf("saved task = %+v", saved)
+	}
+}
+
+func TestRun_Edit(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+
+	out, err := run(t, root, "edit", "--title", "HTTP API", "task-002", "--complexity", "large")
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !strings.Contains(out, "Updated task-002: HTTP API") {
+		t.Errorf("output = %q", out)
+	}
+
+	got := load(t, root).FindTask("task-002")
+	if got.Title != "HTTP API" || got.Complexity != "large" {
+		t.Errorf("edited task = %+v", got)
+	}
+	// Fields without a flag keep their values
+	if len(got.DependsOn) != 1 || len(got.AcceptanceCriteria) != 1 {
+		t.Errorf("edit dropped untouched fields: %+v", got)
+	}
+}
+
+func TestRun_EditClearsDependencies(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+
+	if _, err := run(t, root, "edit", "task-002", "--depends-on", ""); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if got := load(t, root).FindTask("task-002"); len(got.DependsOn) != 0 {
+		t.Errorf("DependsOn = %v, want none", got.DependsOn)
+	}
+}
+
+func TestRun_CancelShowList(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+
+	if _, err := run(t, root, "cancel", "--reason", "out of scope", "task-002"); err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+
+	out, err := run(t, root, "show", "task-002")
+	if err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	for _, want := range []string{"task-002: API", "Status: cancelled (out of scope)", "• serves /health"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("show output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run(t, root, "list", "--json")
+	if err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	var tasks []state.Task
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil || len(tasks) != 2 {
+		t.Fatalf("list --json = %s (err %v)", out, err)
+	}
+	if tasks[1].Status != state.TaskCancelled {
+		t.Errorf("task-002 status = %s, want cancelled", tasks[1].Status)
+	}
+
+	if out, err = run(t, root, "list", "--ids"); err != nil || out != "task-001\ntask-002\n" {
+		t.Errorf("list --ids = %q (err %v)", out, err)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "no action", args: nil, wantErr: "expected an action"},
+		{name: "unknown action", args: []string{"rename"}, wantErr: "unknown action"},
+		{name: "add without title", args: []string{"add"}, wantErr: "title must not be empty"},
+		{name: "bad complexity", args: []string{"add", "--title", "x", "--complexity", "huge"}, wantErr: "complexity must be"},
+		{name: "bad type", args: []string{"add", "--title", "x", "--type", "deploy"}, wantErr: "type must be"},
+		{name: "bad owner", args: []string{"add", "--title", "x", "--owner", "robot"}, wantErr: "owner must be"},
+		{name: "missing dependency", args: []string{"add", "--title", "x", "--depends-on", "task-009"}, wantErr: "does not exist"},
+		{name: "self dependency", args: []string{"edit", "task-002", "--depends-on", "task-002"}, wantErr: "cannot depend on itself"},
+		{name: "edit done task", args: []string{"edit", "task-001", "--title", "x"}, wantErr: "it is done"},
+		{name: "edit unknown task", args: []string{"edit", "task-404", "--title", "x"}, wantErr: "not found"},
+		{name: "edit without ID", args: []string{"edit", "--title", "x"}, wantErr: "exactly one task ID"},
+		{name: "cancel done task", args: []string{"cancel", "task-001"}, wantErr: "already done"},
+		{name: "list with argument", args: []string{"list", "extra"}, wantErr: "unexpected argument"},
+		{name: "reason outside cancel", args: []string{"show", "--reason", "x", "task-001"}, wantErr: "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := newRoot(t)
+			before, _ := state.Fingerprint(root)
+			_, err := run(t, root, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run(%q) error = %v, want it to contain %q", tt.args, err, tt.wantErr)
+			}
+			if after, _ := state.Fingerprint(root); after != before {
+				t.Error("a failed command changed state.json")
+			}
+		})
+	}
+}
+
+func TestRun_NoSession(t *testing.T) {
+	t.Parallel()
+	if _, err := run(t, t.TempDir(), "list"); err == nil || !strings.Contains(err.Error(), "no forge session") {
+		t.Errorf("Run() error = %v, want no forge session", err)
+	}
+}
+
+// ============================================================
+// Add / Edit
+// ============================================================
+
+func TestEdit_RejectsCycles(t *testing.T) {
+	t.Parallel()
+	s := &state.State{Tasks: []state.Task{
+		{ID: "task-001", Title: "A", Complexity: "small", Status: state.TaskPending},
+		{ID: "task-002", Title: "B", Complexity: "small", Status: state.TaskPending, DependsOn: []string{"task-001"}},
+	}}
+
+	edited := s.Tasks[0]
+	edited.DependsOn = []string{"task-002"}
+	if err := Edit(s, edited); err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Errorf("Edit() error = %v, want a circular dependency", err)
+	}
+	if len(s.Tasks[0].DependsOn) != 0 {
+		t.Error("a rejected edit changed the task")
+	}
+}
+
+func TestAdd_AgentOwnerStoredAsDefault(t *testing.T) {
+	t.Parallel()
+	root := newRoot(t)
+	if _, err := run(t, root, "add", "--title", "x", "--owner", "agent"); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if got := load(t, root).FindTask("task-003"); got.Owner != "" {
+		t.Errorf("Owner = %q, want empty (agent)", got.Owner)
+	}
+}
+
+// ============================================================
+// FormatList
+// ============================================================
+
+func TestFormatList(t *testing.T) {
+	t.Parallel()
+	got := FormatList([]state.Task{
+		{ID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskDone},
+		{ID: "task-002", Title: "Buy domain", Complexity: "small", Status: state.TaskPending, Owner: state.OwnerHuman, Assignee: "alice"},
+	})
+	want := "task-001  done         small   Init\n" +
+		"task-002  pending      small   Buy domain (human · @alice)\n"
+	if got != want {
+		t.Errorf("FormatList() =\n%s\nwant\n%s", got, want)
+	}
+	if got := FormatList(nil); got != "No tasks in the plan\n" {
+		t.Errorf("FormatList(nil) = %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package transcript
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/manasm11/forge/internal/executor"
+)
+
+const redacted = "[redacted]"
+
+// minSecretLen keeps short values like "1" or "true" from being scrubbed
+// everywhere they happen to appear.
+const minSecretLen = 8
+
+// assignmentPattern matches "api_key: value" style assignments; the name
+// is kept so the transcript still reads sensibly.
+var assignmentPattern = regexp.MustCompile(`(?i)((?:api[_-]?key|access[_-]?key|token|secret|password|passwd)["']?\s*[:=]\s*["']?|bearer\s+)([A-Za-z0-9_\-./+=]{8,})`)
+
+// Redactor scrubs credentials from transcript text.
+type Redactor struct {
+	secrets []string // literal values, longest first
+}
+
+// NewRedactor collects the values of credential-like variables (see
+// executor.IsSecretEnvName) from the given environments.
+func NewRedactor(envs ...map[string]string) *Redactor {
+	seen := make(map[string]bool)
+	r := &Redactor{}
+	for _, env := range envs {
+		for k, v := range env {
+			if len(v) >= minSecretLen && executor.IsSecretEnvName(k) && !seen[v] {
+				seen[v] = true
+				r.secrets = append(r.secrets, v)
+			}
+		}
+	}
+	// Longest first, so a secret containing another is replaced whole
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r
+}
+
+// Redact replaces known secret values and credential-shaped strings.
+func (r *Redactor) Redact(text string) string {
+	for _, s := range r.secrets {
+		text = strings.ReplaceAll(text, s, redacted)
+	}
+	// Well-known credential formats, regardless of where they came from
+	for _, p := range executor.SecretPatterns {
+		text = p.ReplaceAllString(text, redacted)
+	}
+	return assignmentPattern.ReplaceAllString(text, "${1}"+redacted)
+}
+
+// EnvMap converts os.Environ-style "KEY=value" pairs into a map.
+func EnvMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
@@ -0,0 +1,140 @@
+// Package transcript exports the prompts forge sent and the responses it
+// received, for a task or the latest planning session, as markdown.
+package transcript
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Task renders every recorded exchange for one task, across all runs.
+func Task(s *state.State, entries []executor.JournalEntry, taskID string, r *Redactor) (string, error) {
+	task := s.FindTask(taskID)
+	if task == nil {
+		return "", fmt.Errorf("unknown task %q", taskID)
+	}
+
+	var exchanges []executor.JournalEntry
+	for _, e := range entries {
+		if e.Kind == executor.JournalKindExchange && e.Exchange != nil && e.TaskID == taskID {
+			exchanges = append(exchanges, e)
+		}
+	}
+	if len(exchanges) == 0 {
+		return "", fmt.Errorf("no prompts recorded for %s", taskID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Transcript: %s — %s\n\n", task.ID, r.Redact(task.Title))
+	writeIntro(&b, len(exchanges))
+	for _, e := range exchanges {
+		fmt.Fprintf(&b, "## Run %d · attempt %d — %s\n\n", e.RunID, e.Exchange.Attempt, formatTime(e.Timestamp))
+		writeExchange(&b, *e.Exchange, "System prompt", r)
+	}
+	return b.String(), nil
+}
+
+// Planning renders the most recent planning session. Sessions recorded
+// before exchanges were journaled fall back to the saved conversation,
+// which lacks the planning context.
+func Planning(s *state.State, entries []executor.JournalEntry, r *Redactor) (string, error) {
+	var session []executor.JournalEntry
+	for _, e := range entries {
+		if e.Kind != executor.JournalKindExchange || e.Exchange == nil || e.TaskID != "" {
+			continue
+		}
+		// The context is only sent on the first request of a session
+		if e.Exchange.System != "" {
+			session = nil
+		}
+		session = append(session, e)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Transcript: planning session\n\n")
+
+	if len(session) == 0 {
+		if len(s.ConversationHistory) == 0 {
+			return "", fmt.Errorf("no planning conversation recorded")
+		}
+		b.WriteString("_Reconstructed from the saved conversation; the exact planning context was not recorded. Secrets redacted._\n\n")
+		for _, msg := range s.ConversationHistory {
+			fmt.Fprintf(&b, "## %s\n\n", roleHeading(msg.Role))
+			writeBlock(&b, r.Redact(msg.Content))
+		}
+		return b.String(), nil
+	}
+
+	writeIntro(&b, len(session))
+	for i, e := range session {
+		fmt.Fprintf(&b, "## Request %d — %s\n\n", i+1, formatTime(e.Timestamp))
+		writeExchange(&b, *e.Exchange, "Planning context", r)
+	}
+	return b.String(), nil
+}
+
+func writeIntro(b *strings.Builder, n int) {
+	noun := "exchanges"
+	if n == 1 {
+		noun = "exchange"
+	}
+	fmt.Fprintf(b, "_%d %s. Secrets redacted._\n\n", n, noun)
+}
+
+func writeExchange(b *strings.Builder, x executor.Exchange, systemLabel string, r *Redactor) {
+	if x.System != "" {
+		fmt.Fprintf(b, "### %s\n\n", systemLabel)
+		writeBlock(b, r.Redact(x.System))
+	}
+	b.WriteString("### Prompt\n\n")
+	writeBlock(b, r.Redact(x.Prompt))
+	if x.Response != "" {
+		b.WriteString("### Response\n\n")
+		writeBlock(b, r.Redact(x.Response))
+	}
+	if x.Error != "" {
+		fmt.Fprintf(b, "**Error:** %s\n\n", r.Redact(x.Error))
+	}
+}
+
+// writeBlock fences text verbatim, using a fence longer than any backtick
+// run inside it so embedded code blocks survive.
+func writeBlock(b *strings.Builder, text string) {
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+	fmt.Fprintf(b, "%stext\n%s\n%s\n\n", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+func longestRun(s string, c rune) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+func roleHeading(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	default:
+		return "System"
+	}
+}
+
+func formatTime(millis int64) string {
+	if millis == 0 {
+		return "unknown time"
+	}
+	return time.UnixMilli(millis).Format("2006-01-02 15:04:05")
+}
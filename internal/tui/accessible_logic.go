@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// Accessible reports whether forge should draw for screen readers and
+// dumb terminals: Settings.Accessible is on, or TERM is "dumb". The
+// interactive session also takes --accessible.
+func Accessible(settings *state.Settings) bool {
+	return (settings != nil && settings.Accessible) || os.Getenv("TERM") == "dumb"
+}
+
+// StripBoxDrawing replaces box-drawing characters (borders, separators)
+// with spaces, so layouts keep their shape but nothing is read out.
+func StripBoxDrawing(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 0x2500 && r <= 0x257F {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// statusChanges are the events that move a task to another status, and
+// the status they move it to.
+var statusChanges = map[executor.TaskEventType]string{
+	executor.EventTaskStart:   "in progress",
+	executor.EventTaskDone:    "done",
+	executor.EventTaskFailed:  "failed",
+	executor.EventTaskSkipped: "skipped",
+	executor.EventManualWait:  "waiting for you",
+}
+
+// logLabels name what a log line's color says.
+var logLabels = map[LogLineType]string{
+	LogSuccess: "OK: ",
+	LogError:   "Error: ",
+	LogWarning: "Warning: ",
+}
+
+// AnnounceEvent describes an event in one self-contained entry for the
+// accessible mode's append-only log: which task, what happened, and for
+// status changes the new status in words. Claude's streamed output is
+// left out (""); the full log stays available with l.
+func AnnounceEvent(event executor.TaskEvent, title string) string {
+	if event.Type == executor.EventClaudeChunk {
+		return ""
+	}
+	line := EventToLogLine(event)
+	if line == nil {
+		return ""
+	}
+	subject := "Run"
+	if event.TaskID != "" {
+		subject = "Task " + event.TaskID
+		if title != "" {
+			subject += " (" + title + ")"
+		}
+	}
+	text := logLabels[line.Type] + strings.ReplaceAll(strings.TrimSpace(line.Text), "\n", "\n  ")
+	if status, ok := statusChanges[event.Type]; ok {
+		if event.Type == executor.EventTaskStart {
+			return fmt.Sprintf("%s: status %s.", subject, status)
+		}
+		return fmt.Sprintf("%s: status %s. %s", subject, status, text)
+	}
+	return subject + ": " + text
+}
+
+// FormatAccessibleStatus sums up the run and the selected task in plain
+// sentences, for the accessible dashboard that replaces the task list,
+// separators and progress bar.
+func FormatAccessibleStatus(progress []TaskProgress, status ExecutionStatus, cursor int) string {
+	done := 0
+	for _, tp := range progress {
+		if tp.Status == state.TaskDone {
+			done++
+		}
+	}
+	var run string
+	switch status {
+	case ExecComplete:
+		run = "Execution complete."
+	case ExecStopped:
+		run = "Execution stopped."
+	case ExecCancelled:
+		run = "Execution cancelled."
+	case ExecPaused:
+		run = "Execution paused."
+	default:
+		run = "Executing."
+	}
+	lines := []string{fmt.Sprintf("%s %d of %d tasks done.", run, done, len(progress))}
+	if cursor >= 0 && cursor < len(progress) {
+		tp := progress[cursor]
+		task := fmt.Sprintf("Selected task %d of %d: %s, %s, status %s", cursor+1, len(progress), tp.TaskID, tp.Title, strings.ReplaceAll(string(tp.Status), "-", " "))
+		if tp.Human && tp.Status == state.TaskPending {
+			task += ", for a human"
+		}
+		if tp.Status == state.TaskInProgress && tp.Attempt > 0 {
+			task += fmt.Sprintf(", attempt %d of %d", tp.Attempt, tp.MaxAttempts)
+		}
+		if tp.Status == state.TaskFailed && tp.RootCause != "" {
+			task += ". Root cause: " + tp.RootCause
+		}
+		lines = append(lines, task+".")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestAnnounceEvent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		event executor.TaskEvent
+		title string
+		want  string
+	}{
+		{
+			name:  "start names the new status",
+			event: executor.TaskEvent{TaskID: "task-002", Type: executor.EventTaskStart, Message: "Auth"},
+			title: "Auth",
+			want:  "Task task-002 (Auth): status in progress.",
+		},
+		{
+			name:  "failure is labeled, not colored",
+			event: executor.TaskEvent{TaskID: "task-002", Type: executor.EventTaskFailed, Message: "x"},
+			title: "Auth",
+			want:  "Task task-002 (Auth): status failed. Error: Task failed: x",
+		},
+		{
+			name:  "unknown title",
+			event: executor.TaskEvent{TaskID: "task-003", Type: executor.EventTaskFailed, Message: "x"},
+			want:  "Task task-003: status failed. Error: Task failed: x",
+		},
+		{
+			name:  "claude output is left out",
+			event: executor.TaskEvent{TaskID: "task-002", Type: executor.EventClaudeChunk, Message: "thinking"},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := AnnounceEvent(tt.event, tt.title); got != tt.want {
+				t.Errorf("AnnounceEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripBoxDrawing(t *testing.T) {
+	t.Parallel()
+	in := "╭──╮\n│ok│\n╰──╯ ✓ █"
+	want := "    \n ok \n     ✓ █"
+	if got := StripBoxDrawing(in); got != want {
+		t.Errorf("StripBoxDrawing() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatAccessibleStatus(t *testing.T) {
+	t.Parallel()
+	progress := []TaskProgress{
+		{TaskID: "task-001", Title: "Init", Status: state.TaskDone},
+		{TaskID: "task-002", Title: "Auth", Status: state.TaskInProgress, Attempt: 2, MaxAttempts: 3},
+		{TaskID: "task-003", Title: "Docs", Status: state.TaskFailed, RootCause: "missing dependency"},
+	}
+
+	got := FormatAccessibleStatus(progress, ExecRunning, 1)
+	want := "Executing. 1 of 3 tasks done.\nSelected task 2 of 3: task-002, Auth, status in progress, attempt 2 of 3."
+	if got != want {
+		t.Errorf("FormatAccessibleStatus() =\n%s\nwant\n%s", got, want)
+	}
+
+	got = FormatAccessibleStatus(progress, ExecStopped, 2)
+	if !strings.HasPrefix(got, "Execution stopped.") || !strings.Contains(got, "Root cause: missing dependency.") {
+		t.Errorf("FormatAccessibleStatus() = %q, want the stop and the root cause", got)
+	}
+	if strings.ContainsAny(got, "─│█░") {
+		t.Errorf("FormatAccessibleStatus() = %q, has box drawing or bars", got)
+	}
+}
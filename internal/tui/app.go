@@ -1,13 +1,18 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/manasm11/forge/internal/claude"
 	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/planner"
+	"github.com/manasm11/forge/internal/provider"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // TransitionMsg signals a phase transition.
@@ -15,12 +20,20 @@ type TransitionMsg struct {
 	To state.Phase
 }
 
+// ProviderChangedMsg is sent when the inputs phase saves a different
+// provider or model, so the planning client is re-created for it.
+type ProviderChangedMsg struct {
+	Config provider.Config
+}
+
 // AppModel is the root bubbletea model managing phase transitions.
 type AppModel struct {
 	state      *state.State
 	stateRoot  string // project root directory
 	claude     claude.Claude
 	claudeExec executor.ClaudeExecutor
+	critic     claude.Claude // reviews each new plan version; nil = off
+	accessible bool          // --accessible; Settings.Accessible also turns it on
 	program    *tea.Program
 	phase      state.Phase
 	planning   PlanningModel
@@ -31,6 +44,16 @@ type AppModel struct {
 	height     int
 	err        error
 	quitting   bool
+
+	critiquedVersion int // last plan version sent to the critic
+
+	generated []string // agent files the inputs phase wrote this session
+
+	// A plan is streaming in and the review list shows it as it grows; the
+	// phase stays planning until the plan is applied. generationHidden is
+	// set when esc went back to the chat for the rest of the reply.
+	generating       bool
+	generationHidden bool
 }
 
 // NewAppModel creates a new root model with the given state.
@@ -47,6 +70,17 @@ func NewAppModel(s *state.State, root string, claudeClient claude.Claude, claude
 	}
 }
 
+// SetAccessible turns on the screen-reader friendly mode (see Accessible)
+// whatever the settings say.
+func (m *AppModel) SetAccessible(on bool) {
+	m.accessible = on
+	m.execution.SetAccessible(m.isAccessible())
+}
+
+func (m *AppModel) isAccessible() bool {
+	return m.accessible || Accessible(m.state.Settings)
+}
+
 // SetProgram sets the tea.Program reference for streaming operations.
 // Must be called after tea.NewProgram() and before p.Run().
 func (m *AppModel) SetProgram(p *tea.Program) {
@@ -55,12 +89,34 @@ func (m *AppModel) SetProgram(p *tea.Program) {
 	m.execution.SetProgram(p)
 }
 
+// withProvider re-creates a CLI client for a provider config. Other
+// implementations (test mocks) are returned as they are.
+func withProvider(c claude.Claude, cfg provider.Config, useModel bool) claude.Claude {
+	client, ok := c.(*claude.Client)
+	if !ok {
+		return c
+	}
+	if useModel && cfg.Model != "" {
+		client = client.WithModel(cfg.Model)
+	}
+	return client.WithEnvVars(provider.EnvVarsForProvider(cfg))
+}
+
+// SetCritic enables a second-model review of every new plan version. The
+// findings are shown in the review phase and kept in the conversation so a
+// replan can address them. c must not share a session with the planner.
+func (m *AppModel) SetCritic(c claude.Claude) {
+	m.critic = c
+}
+
 func (m *AppModel) Init() tea.Cmd {
+	applyASCII(m.state.Settings)
 	switch m.phase {
 	case state.PhaseInputs:
 		return m.inputs.Init()
 	case state.PhaseExecution:
 		m.execution = NewExecutionModel(m.state, m.stateRoot, m.claudeExec)
+		m.execution.SetAccessible(m.isAccessible())
 		m.execution.SetProgram(m.program)
 		return tea.Batch(m.execution.Init(), m.execution.StartExecution())
 	default:
@@ -88,6 +144,19 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.generating && msg.String() != "ctrl+c" {
+			switch msg.String() {
+			case "esc":
+				m.generating, m.generationHidden = false, true
+			case "ctrl+p", "ctrl+n":
+				// no phase changes until the plan is complete
+			default:
+				var cmd tea.Cmd
+				m.review, cmd = m.review.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			m.quitting = true
@@ -100,10 +169,55 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.transitionToNextPhase()
 		}
 
+	case critiqueMsg:
+		if msg.Err == nil {
+			m.state.AddConversationMessage("system", FormatCritique(msg.Findings))
+			if err := state.Save(m.stateRoot, m.state); err != nil {
+				m.err = err
+			}
+		}
+		var cmd tea.Cmd
+		m.review, cmd = m.review.Update(msg)
+		return m, cmd
+
+	case PlanProgressMsg:
+		if m.phase != state.PhasePlanning || m.generationHidden {
+			return m, nil
+		}
+		if !m.generating {
+			m.generating = true
+			m.review = NewReviewModel(m.state, m.stateRoot)
+			m.review.SetSize(m.width, m.height-4)
+		}
+		m.review.ShowPartialPlan(msg.Tasks)
+		return m, nil
+
+	case components.StreamStartMsg, components.StreamDoneMsg:
+		// The reply has ended (or a new one begins): back to the chat, where
+		// a complete plan moves on to review and a broken one is explained
+		m.generating, m.generationHidden = false, false
+
+	case suggestCriteriaMsg:
+		return m, m.suggestCriteria()
+
+	case ProviderChangedMsg:
+		m.claude = withProvider(m.claude, msg.Config, true)
+		if m.critic != nil {
+			m.critic = withProvider(m.critic, msg.Config, false) // keeps its own model
+		}
+		return m, nil
+
 	case TransitionMsg:
+		from := m.phase
 		m.phase = msg.To
 		m.state.Phase = msg.To
-		if err := state.Save(m.stateRoot, m.state); err != nil {
+		applyASCII(m.state.Settings) // the inputs phase may have changed it
+		if from == state.PhaseInputs {
+			m.generated = append(m.generated, m.inputs.generated...)
+		}
+		if m.StateConflict() {
+			// keep the newer state.json someone else wrote
+		} else if err := state.Save(m.stateRoot, m.state); err != nil {
 			m.err = err
 		}
 
@@ -114,10 +228,17 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.planning = NewPlanningModel(m.state, m.stateRoot, m.claude, m.program)
 		case state.PhaseReview:
 			m.review = NewReviewModel(m.state, m.stateRoot)
+			if from == state.PhasePlanning && m.critic != nil && m.state.PlanVersion != m.critiquedVersion {
+				m.critiquedVersion = m.state.PlanVersion
+				m.review.critiquing = true
+				initCmd = m.critiquePlan()
+			}
 		case state.PhaseInputs:
 			m.inputs = NewInputsModel(m.state, m.stateRoot)
 		case state.PhaseExecution:
 			m.execution = NewExecutionModel(m.state, m.stateRoot, m.claudeExec)
+			m.execution.SetAccessible(m.isAccessible())
+			m.execution.SetGenerated(m.generated)
 			m.execution.SetProgram(m.program)
 			m.execution.SetSize(m.width, m.height-4)
 			initCmd = tea.Batch(m.execution.Init(), m.execution.StartExecution())
@@ -155,6 +276,9 @@ func (m *AppModel) View() string {
 	switch m.phase {
 	case state.PhasePlanning:
 		content = m.planning.View()
+		if m.generating {
+			content = m.review.View()
+		}
 	case state.PhaseReview:
 		content = m.review.View()
 	case state.PhaseInputs:
@@ -174,11 +298,39 @@ func (m *AppModel) View() string {
 	// Status bar
 	statusBar := m.renderStatusBar()
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, content, statusBar)
+	view := lipgloss.JoinVertical(lipgloss.Left, header, content, statusBar)
+	if m.isAccessible() {
+		view = StripBoxDrawing(view)
+	}
+	return view
+}
+
+// critiquePlan runs the critic over the current tasks in the background.
+func (m *AppModel) critiquePlan() tea.Cmd {
+	critic := m.critic
+	tasks := planner.CritiqueTasks(m.state.Tasks)
+	return func() tea.Msg {
+		findings, err := planner.Critique(context.Background(), critic, tasks)
+		return critiqueMsg{Findings: findings, Err: err}
+	}
+}
+
+// suggestCriteria has the planning model tighten the pending tasks'
+// acceptance criteria in the background.
+func (m *AppModel) suggestCriteria() tea.Cmd {
+	c := m.claude
+	tasks := slices.Clone(m.state.Tasks)
+	return func() tea.Msg {
+		if c == nil {
+			return criteriaSuggestionsMsg{Err: fmt.Errorf("Claude CLI not available")}
+		}
+		edits, err := planner.SuggestCriteria(context.Background(), c, tasks)
+		return criteriaSuggestionsMsg{Edits: edits, Err: err}
+	}
 }
 
 func (m *AppModel) renderHeader() string {
-	title := TitleStyle.Render("⚒ forge")
+	title := TitleStyle.Render(logo() + " forge")
 
 	phases := []struct {
 		name  string
@@ -218,6 +370,12 @@ func (m *AppModel) State() *state.State {
 	return m.state
 }
 
+// StateConflict reports whether state.json was changed by another process
+// during execution, in which case State() is stale and must not be saved.
+func (m *AppModel) StateConflict() bool {
+	return m.execution.stateConflict
+}
+
 func (m *AppModel) renderStatusBar() string {
 	help := "ctrl+c: quit"
 	if m.phase != state.PhasePlanning {
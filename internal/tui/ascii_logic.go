@@ -0,0 +1,20 @@
+package tui
+
+import (
+	"github.com/manasm11/forge/internal/platform"
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
+)
+
+// ASCII reports whether forge should draw with ASCII only: Settings.ASCIIMode
+// is on, or the terminal doesn't look like it can draw UTF-8
+// (platform.UTF8Terminal).
+func ASCII(settings *state.Settings) bool {
+	return (settings != nil && settings.ASCIIMode) || !platform.UTF8Terminal()
+}
+
+// applyASCII makes the components draw as ASCII(settings) says. Called
+// when a program starts and whenever the settings may have changed.
+func applyASCII(settings *state.Settings) {
+	components.SetASCII(ASCII(settings))
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
+)
+
+// Tests here switch package-level rendering state, so they must not call
+// t.Parallel.
+
+func TestASCII(t *testing.T) {
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+	t.Setenv("TERM", "xterm-256color")
+	if ASCII(&state.Settings{}) {
+		t.Error("ASCII() = true on a UTF-8 terminal")
+	}
+	if !ASCII(&state.Settings{ASCIIMode: true}) {
+		t.Error("ASCII() = false with ASCIIMode on")
+	}
+	t.Setenv("LC_ALL", "en_US.ISO-8859-1")
+	if !ASCII(nil) {
+		t.Error("ASCII(nil) = false on a latin-1 terminal")
+	}
+}
+
+func TestASCIIMode_KeepsUserText(t *testing.T) {
+	defer components.SetASCII(true)()
+
+	tp := TaskProgress{TaskID: "task-001", Title: "Parse a → b · café — and so on…", Status: state.TaskDone}
+	line := FormatTaskStatusLine(tp, false, 80, components.StatusEmoji)
+	if !strings.Contains(line, "[x]") || !strings.Contains(line, tp.Title) {
+		t.Errorf("status line = %q, want the ascii icon and the title unchanged", line)
+	}
+
+	v := state.Verification{Passed: false, Steps: []state.VerificationStep{{Name: "build", Passed: true}, {Name: "test"}}}
+	if got := FormatVerificationRow(v); got != "x verification  build + · test x" {
+		t.Errorf("FormatVerificationRow() = %q", got)
+	}
+	if got := FormatProgressBar(1, 2, 4); got != "##-- 1/2 (50%)" {
+		t.Errorf("FormatProgressBar() = %q", got)
+	}
+}
@@ -73,6 +73,11 @@ type ChatModel struct {
 	spinner         spinner.Model
 	sender          MessageSender
 	slashHandler    SlashHandler
+	commands        *Commands // nil = slashHandler only, no completion
+	refs            *References
+	selectedRef     string // mention picked with Tab
+	refExpanded     bool   // selectedRef's details are shown
+	lastSent        string // last regular message, for Retry
 	waiting         bool
 	streaming       bool // true while receiving stream chunks
 	streamingMsgIdx int  // index of the message being streamed into
@@ -120,10 +125,15 @@ var (
 
 	spinnerStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#06B6D4"))
+
+	hintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			PaddingLeft(2)
 )
 
 const inputAreaHeight = 3 // border top + input line + border bottom
 const msgPadding = 2      // horizontal padding for message content
+const maxHints = 5        // command completions listed above the input
 
 // NewChatModel creates a new chat component.
 func NewChatModel(sender MessageSender, slashHandler SlashHandler) ChatModel {
@@ -147,6 +157,15 @@ func NewChatModel(sender MessageSender, slashHandler SlashHandler) ChatModel {
 	}
 }
 
+// SetCommands makes the chat offer a phase's slash commands: they run on
+// enter, complete with Tab and are listed by /help.
+func (m *ChatModel) SetCommands(c *Commands) {
+	m.commands = c
+	m.slashHandler = c.handle
+	m.textInput.ShowSuggestions = true
+	m.textInput.SetSuggestions(c.suggestions())
+}
+
 // Init returns the initial commands for the chat component.
 func (m ChatModel) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, m.spinner.Tick)
@@ -220,9 +239,14 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
+		if msg.String() == "tab" && !m.waiting && m.textInput.Value() == "" {
+			m.cycleReference()
+			return m, nil
+		}
 		if msg.String() == "enter" && !m.waiting {
 			text := strings.TrimSpace(m.textInput.Value())
 			if text == "" {
+				m.toggleReference()
 				return m, nil
 			}
 
@@ -230,6 +254,19 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 
 			// Check for slash command
 			if cmd, ok := ParseSlashCommand(text); ok {
+				if m.commands != nil {
+					if _, known := m.commands.Lookup(cmd.Name); !known {
+						// An unambiguous prefix runs the command it completes to
+						if matches := m.commands.Complete("/" + cmd.Name); len(matches) == 1 {
+							cmd.Name = matches[0].Name
+						}
+					}
+					if cmd.Name == "help" {
+						m.addMessage(RoleSystem, m.commands.Help())
+						m.refreshViewport()
+						return m, nil
+					}
+				}
 				if m.slashHandler != nil {
 					asyncCmd, handled := m.slashHandler(cmd)
 					if handled {
@@ -241,13 +278,18 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 					}
 				}
 				// Unhandled slash command
-				m.addMessage(RoleSystem, fmt.Sprintf("Unknown command: /%s", cmd.Name))
+				unknown := fmt.Sprintf("Unknown command: /%s", cmd.Name)
+				if m.commands != nil {
+					unknown += " (/help lists the commands)"
+				}
+				m.addMessage(RoleSystem, unknown)
 				m.refreshViewport()
 				return m, nil
 			}
 
 			// Regular message
 			m.addMessage(RoleUser, text)
+			m.lastSent = text
 			m.waiting = true
 			cmds = append(cmds, m.sender(text), m.spinner.Tick)
 			m.refreshViewport()
@@ -280,13 +322,40 @@ func (m ChatModel) View() string {
 	var inputView string
 	if m.waiting {
 		spinnerText := fmt.Sprintf("%s Claude is thinking...", m.spinner.View())
-		inputView = inputBorderStyle.Width(m.width - 4).Render(spinnerText)
+		inputView = Bordered(inputBorderStyle).Width(m.width - 4).Render(spinnerText)
 	} else {
 		m.textInput.Width = m.width - 6 // account for border + padding
-		inputView = inputBorderStyle.Width(m.width - 4).Render(m.textInput.View())
+		inputView = Bordered(inputBorderStyle).Width(m.width - 4).Render(m.textInput.View())
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), inputView)
+	hints := m.commandHints()
+	if len(hints) == 0 {
+		hints = m.referencePanel()
+	}
+	if len(hints) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), inputView)
+	}
+	// Hints and expanded references take the bottom of the viewport
+	vp := m.viewport
+	vp.Height = max(0, vp.Height-len(hints))
+	return lipgloss.JoinVertical(lipgloss.Left, vp.View(), strings.Join(hints, "\n"), inputView)
+}
+
+// commandHints lists the commands completing a partly typed one.
+func (m ChatModel) commandHints() []string {
+	if m.commands == nil || m.waiting {
+		return nil
+	}
+	matches := m.commands.Complete(m.textInput.Value())
+	var hints []string
+	for i, cmd := range matches {
+		if i == maxHints {
+			hints = append(hints, hintStyle.Render(fmt.Sprintf("… %d more (/help)", len(matches)-maxHints)))
+			break
+		}
+		hints = append(hints, hintStyle.Render(fmt.Sprintf("%-18s %s", cmd.Usage(), cmd.Description)))
+	}
+	return hints
 }
 
 // AddMessage adds a message to the chat from outside.
@@ -333,10 +402,40 @@ func (m *ChatModel) ReceiveResponse(content string) tea.Cmd {
 	return nil
 }
 
+// ReplaceLast replaces the content of the newest message, e.g. to show a
+// streamed reply in a more readable form once it is complete.
+func (m *ChatModel) ReplaceLast(content string) {
+	if len(m.messages) == 0 {
+		return
+	}
+	m.messages[len(m.messages)-1].Content = content
+	m.refreshViewport()
+}
+
+// Retry sends the last message again, e.g. after its request failed. It
+// returns nil when there is nothing to resend or a reply is pending.
+func (m *ChatModel) Retry() tea.Cmd {
+	if m.lastSent == "" || m.waiting {
+		return nil
+	}
+	m.waiting = true
+	m.refreshViewport()
+	return tea.Batch(m.sender(m.lastSent), m.spinner.Tick)
+}
+
+// Notify adds a system message and clears the waiting state, for slash
+// commands that are answered without the model.
+func (m *ChatModel) Notify(content string) {
+	m.waiting = false
+	m.addMessage(RoleSystem, content)
+	m.refreshViewport()
+}
+
 // ClearMessages removes all messages.
 func (m *ChatModel) ClearMessages() {
 	m.messages = nil
 	m.streaming = false
+	m.selectedRef, m.refExpanded = "", false
 	m.refreshViewport()
 }
 
@@ -344,7 +443,7 @@ func (m *ChatModel) addMessage(role Role, content string) {
 	m.messages = append(m.messages, Message{
 		Role:    role,
 		Content: content,
-		Time:    time.Now(),
+		Time:    clock(),
 	})
 }
 
@@ -389,7 +488,7 @@ func (m ChatModel) renderMessages() string {
 
 func (m ChatModel) renderMessage(msg Message, width int) string {
 	timestamp := msg.Time.Format("3:04 PM")
-	wrapped := wordwrap.String(msg.Content, width-4) // account for border + padding
+	wrapped := m.highlightReferences(wordwrap.String(msg.Content, width-4)) // account for border + padding
 
 	switch msg.Role {
 	case RoleUser:
@@ -397,7 +496,7 @@ func (m ChatModel) renderMessage(msg Message, width int) string {
 			userNameStyle.Render("You"),
 			timeStyle.Render(timestamp),
 		)
-		body := userMsgStyle.Width(width).Render(wrapped)
+		body := Bordered(userMsgStyle).Width(width).Render(wrapped)
 		return fmt.Sprintf("%s\n%s", header, body)
 
 	case RoleAssistant:
@@ -405,7 +504,7 @@ func (m ChatModel) renderMessage(msg Message, width int) string {
 			assistantNameStyle.Render("Claude"),
 			timeStyle.Render(timestamp),
 		)
-		body := assistantMsgStyle.Width(width).Render(wrapped)
+		body := Bordered(assistantMsgStyle).Width(width).Render(wrapped)
 		return fmt.Sprintf("%s\n%s", header, body)
 
 	case RoleSystem:
@@ -259,6 +259,43 @@ func TestStreamDone_WithError(t *testing.T) {
 	}
 }
 
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	var sent []string
+	sender := func(text string) tea.Cmd {
+		sent = append(sent, text)
+		return nil
+	}
+	m := NewChatModel(sender, nil)
+	m.SetSize(80, 24)
+
+	if cmd := m.Retry(); cmd != nil || len(sent) != 0 {
+		t.Fatal("Retry() before any message should do nothing")
+	}
+
+	for _, r := range "hello" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Retry() != nil {
+		t.Error("Retry() while waiting for a reply should do nothing")
+	}
+	m, _ = m.Update(StreamDoneMsg{Err: fmt.Errorf("connection failed")})
+
+	if cmd := m.Retry(); cmd == nil {
+		t.Fatal("Retry() after a failure = nil")
+	}
+	if want := []string{"hello", "hello"}; fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	if !m.IsWaiting() {
+		t.Error("Retry() should wait for the reply")
+	}
+	if n := len(m.Messages()); n != 2 {
+		t.Errorf("messages = %d, want the user message and the error only", n)
+	}
+}
+
 func TestSetSize(t *testing.T) {
 	t.Parallel()
 	sender := func(text string) tea.Cmd { return nil }
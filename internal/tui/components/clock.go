@@ -0,0 +1,14 @@
+package components
+
+import "time"
+
+// clock supplies message timestamps; SetClock pins it for golden tests.
+var clock = time.Now
+
+// SetClock replaces the clock behind rendered timestamps and returns a
+// function that restores the previous one.
+func SetClock(fn func() time.Time) (restore func()) {
+	prev := clock
+	clock = fn
+	return func() { clock = prev }
+}
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Command is a slash command a phase registers with its chat.
+type Command struct {
+	Name        string // without the slash
+	Args        string // argument usage, e.g. "<name>"; "" = none
+	Description string
+	// Run handles the command. The chat waits (showing the spinner) until
+	// the returned command answers with a StreamDoneMsg, a ResponseMsg or
+	// a Notify; nil means the command finished synchronously.
+	Run func(args string) tea.Cmd
+}
+
+// Usage returns how the command is typed, e.g. "/fork <name>".
+func (c Command) Usage() string {
+	if c.Args == "" {
+		return "/" + c.Name
+	}
+	return "/" + c.Name + " " + c.Args
+}
+
+// Commands is the set of slash commands a chat offers. Phases register
+// their own; /help is built in.
+type Commands struct {
+	byName map[string]Command
+}
+
+// NewCommands creates an empty command set.
+func NewCommands() *Commands {
+	return &Commands{byName: map[string]Command{}}
+}
+
+// Register adds a command, replacing any with the same name.
+func (c *Commands) Register(cmd Command) *Commands {
+	c.byName[cmd.Name] = cmd
+	return c
+}
+
+// Lookup finds a command by name.
+func (c *Commands) Lookup(name string) (Command, bool) {
+	cmd, ok := c.byName[name]
+	return cmd, ok
+}
+
+// All returns the commands sorted by name, /help included.
+func (c *Commands) All() []Command {
+	all := []Command{{Name: "help", Description: "list the commands"}}
+	for _, cmd := range c.byName {
+		if cmd.Name != "help" {
+			all = append(all, cmd)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Complete returns the commands whose "/name" starts with input. Input
+// that isn't a bare slash command (no slash, or arguments already typed)
+// completes to nothing.
+func (c *Commands) Complete(input string) []Command {
+	if !strings.HasPrefix(input, "/") || strings.ContainsAny(input, " \t") {
+		return nil
+	}
+	var matches []Command
+	for _, cmd := range c.All() {
+		if strings.HasPrefix("/"+cmd.Name, strings.ToLower(input)) {
+			matches = append(matches, cmd)
+		}
+	}
+	return matches
+}
+
+// Help lists the commands for /help.
+func (c *Commands) Help() string {
+	all := c.All()
+	width := 0
+	for _, cmd := range all {
+		width = max(width, len(cmd.Usage()))
+	}
+	var b strings.Builder
+	b.WriteString("Commands (Tab completes):\n")
+	for _, cmd := range all {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, cmd.Usage(), cmd.Description)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// suggestions are the completions offered to the text input.
+func (c *Commands) suggestions() []string {
+	var s []string
+	for _, cmd := range c.All() {
+		name := "/" + cmd.Name
+		if cmd.Args != "" {
+			name += " "
+		}
+		s = append(s, name)
+	}
+	return s
+}
+
+// handle runs a registered command, as a SlashHandler.
+func (c *Commands) handle(sc SlashCommand) (tea.Cmd, bool) {
+	cmd, ok := c.Lookup(sc.Name)
+	if !ok || cmd.Run == nil {
+		return nil, false
+	}
+	return cmd.Run(sc.Args), true
+}
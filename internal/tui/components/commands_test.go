@@ -0,0 +1,111 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testCommands(ran *[]string) *Commands {
+	record := func(name string) func(string) tea.Cmd {
+		return func(args string) tea.Cmd {
+			*ran = append(*ran, strings.TrimSpace(name+" "+args))
+			return nil
+		}
+	}
+	return NewCommands().
+		Register(Command{Name: "done", Description: "finish", Run: record("done")}).
+		Register(Command{Name: "debug", Description: "toggle stats", Run: record("debug")}).
+		Register(Command{Name: "fork", Args: "<name>", Description: "branch off", Run: record("fork")})
+}
+
+func TestCommands_Complete(t *testing.T) {
+	t.Parallel()
+	cmds := testCommands(new([]string))
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "/", want: []string{"debug", "done", "fork", "help"}},
+		{input: "/d", want: []string{"debug", "done"}},
+		{input: "/DO", want: []string{"done"}},
+		{input: "/fork x", want: nil},
+		{input: "fork", want: nil},
+		{input: "/zzz", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, c := range cmds.Complete(tt.input) {
+				got = append(got, c.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommands_Help(t *testing.T) {
+	t.Parallel()
+	got := testCommands(new([]string)).Help()
+	for _, want := range []string{"  /fork <name>  branch off\n", "  /help         list the commands"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Help() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestChat_RunsRegisteredCommands(t *testing.T) {
+	t.Parallel()
+	var ran []string
+	m := NewChatModel(func(string) tea.Cmd { return nil }, nil)
+	m.SetSize(80, 24)
+	m.SetCommands(testCommands(&ran))
+
+	enter := func(text string) {
+		m.textInput.SetValue(text)
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	enter("/fork microservices")
+	enter("/fo") // unambiguous prefix
+	enter("/d")  // ambiguous: unknown
+	enter("/help")
+
+	if want := []string{"fork microservices", "fork"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran = %q, want %q", ran, want)
+	}
+	msgs := m.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("messages = %+v, want the unknown-command note and the help", msgs)
+	}
+	if !strings.Contains(msgs[0].Content, "Unknown command: /d (/help") {
+		t.Errorf("messages[0] = %q", msgs[0].Content)
+	}
+	if !strings.HasPrefix(msgs[1].Content, "Commands (Tab completes):") {
+		t.Errorf("messages[1] = %q", msgs[1].Content)
+	}
+}
+
+func TestChat_CommandHints(t *testing.T) {
+	t.Parallel()
+	m := NewChatModel(func(string) tea.Cmd { return nil }, nil)
+	m.SetSize(80, 24)
+	m.SetCommands(testCommands(new([]string)))
+
+	m.textInput.SetValue("/d")
+	if hints := m.commandHints(); len(hints) != 2 || !strings.Contains(hints[0], "/debug") {
+		t.Errorf("commandHints(/d) = %q", hints)
+	}
+	withHints := strings.Count(m.View(), "\n")
+	m.textInput.SetValue("hello")
+	if hints := m.commandHints(); hints != nil {
+		t.Errorf("commandHints(hello) = %q, want none", hints)
+	}
+	if without := strings.Count(m.View(), "\n"); withHints != without {
+		t.Errorf("hints change the view height: %d lines, %d without", withHints+1, without+1)
+	}
+}
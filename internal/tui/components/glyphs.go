@@ -0,0 +1,54 @@
+package components
+
+import "github.com/charmbracelet/lipgloss"
+
+// asciiOnly makes components draw without block characters, box drawing
+// and emoji; see SetASCII.
+var asciiOnly bool
+
+// SetASCII switches drawing to ASCII only, for terminals that show block
+// characters and emoji as garbage: #/- progress bars, -|+ borders and
+// [x] task statuses. It returns a function that restores the previous
+// setting. Text the user or the model wrote is never changed.
+func SetASCII(on bool) (restore func()) {
+	prev := asciiOnly
+	asciiOnly = on
+	return func() { asciiOnly = prev }
+}
+
+// ASCII reports whether components draw with ASCII only.
+func ASCII() bool {
+	return asciiOnly
+}
+
+// Glyph returns unicode, or ascii when drawing with ASCII only.
+func Glyph(unicode, ascii string) string {
+	if asciiOnly {
+		return ascii
+	}
+	return unicode
+}
+
+// asciiBorder replaces every lipgloss border when drawing with ASCII only.
+var asciiBorder = lipgloss.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+	MiddleLeft: "+", MiddleRight: "+", Middle: "+", MiddleTop: "+", MiddleBottom: "+",
+}
+
+// BoxBorder returns b, or an ASCII border when drawing with ASCII only.
+func BoxBorder(b lipgloss.Border) lipgloss.Border {
+	if asciiOnly {
+		return asciiBorder
+	}
+	return b
+}
+
+// Bordered returns s, with its border drawn in ASCII when drawing with
+// ASCII only, for styles built before SetASCII. The sides s draws are kept.
+func Bordered(s lipgloss.Style) lipgloss.Style {
+	if asciiOnly {
+		return s.BorderStyle(asciiBorder)
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SetASCII switches package-level state, so these tests must not call
+// t.Parallel.
+
+func TestSetASCII(t *testing.T) {
+	restore := SetASCII(true)
+
+	if got := Glyph("✓", "+"); got != "+" {
+		t.Errorf("Glyph() = %q, want +", got)
+	}
+	if got := StatusIcon(StatusEmoji, StatusDone, false); got != "[x]" {
+		t.Errorf("StatusIcon() = %q, want [x] whatever the style", got)
+	}
+	m := NewProgressBarModel(2, 26)
+	m.SetDone(1)
+	if view := m.View(); !strings.Contains(view, "###---") || strings.ContainsAny(view, "█░") {
+		t.Errorf("progress bar = %q, want # and -", view)
+	}
+	box := Bordered(lipgloss.NewStyle().Border(lipgloss.RoundedBorder())).Render("hi")
+	if box != "+--+\n|hi|\n+--+" {
+		t.Errorf("Bordered box = %q", box)
+	}
+
+	restore()
+	if got := Glyph("✓", "+"); got != "✓" {
+		t.Errorf("Glyph() after restore = %q, want ✓", got)
+	}
+	if BoxBorder(lipgloss.NormalBorder()) != lipgloss.NormalBorder() {
+		t.Error("BoxBorder() after restore changed the border")
+	}
+}
@@ -76,6 +76,12 @@ func (m *LogStreamModel) SetLines(lines []LogLine) {
 	m.scrollToBottom()
 }
 
+// ScrollTo shows the given line at the top and stops following new ones.
+func (m *LogStreamModel) ScrollTo(line int) {
+	m.follow = false
+	m.offset = max(min(line, len(m.lines)-1), 0)
+}
+
 // Clear removes all lines.
 func (m *LogStreamModel) Clear() {
 	m.lines = nil
@@ -0,0 +1,136 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PickerItem is one checkbox of a PickerModel.
+type PickerItem struct {
+	ID      string
+	Label   string
+	Checked bool
+	// Disabled explains why the item can't be checked, e.g. because it
+	// would create a dependency cycle; "" = selectable.
+	Disabled string
+}
+
+// PickerDoneMsg is emitted when the picker is confirmed or cancelled.
+type PickerDoneMsg struct {
+	Selected  []string // checked IDs, in list order
+	Cancelled bool
+}
+
+// PickerModel is a checkbox list: j/k move, space toggles, enter confirms
+// and esc cancels.
+type PickerModel struct {
+	title     string
+	items     []PickerItem
+	cursor    int
+	scrollOff int
+	width     int
+	height    int
+}
+
+var pickerTitleStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#7C3AED")).
+	Bold(true)
+
+// NewPickerModel creates a picker.
+func NewPickerModel(title string, items []PickerItem) PickerModel {
+	return PickerModel{title: title, items: items}
+}
+
+// SetSize updates the component dimensions.
+func (m *PickerModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Selected returns the checked IDs, in list order.
+func (m PickerModel) Selected() []string {
+	var ids []string
+	for _, item := range m.items {
+		if item.Checked {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// Update handles messages for the picker.
+func (m PickerModel) Update(msg tea.Msg) (PickerModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "j", "down":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case " ", "x":
+		if m.cursor >= len(m.items) {
+			break
+		}
+		// A disabled item that is checked can still be unchecked.
+		if item := &m.items[m.cursor]; item.Disabled == "" || item.Checked {
+			item.Checked = !item.Checked
+		}
+	case "enter":
+		selected := m.Selected()
+		return m, func() tea.Msg { return PickerDoneMsg{Selected: selected} }
+	case "esc", "q":
+		return m, func() tea.Msg { return PickerDoneMsg{Cancelled: true} }
+	}
+	m.ensureVisible()
+	return m, nil
+}
+
+func (m *PickerModel) ensureVisible() {
+	rows := max(1, m.height-1) // title line
+	if m.cursor < m.scrollOff {
+		m.scrollOff = m.cursor
+	}
+	if m.cursor >= m.scrollOff+rows {
+		m.scrollOff = m.cursor - rows + 1
+	}
+}
+
+// View renders the picker.
+func (m PickerModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	lines := []string{pickerTitleStyle.Render(m.title)}
+	if len(m.items) == 0 {
+		lines = append(lines, dimStyle.Render("  Nothing to choose from"))
+	}
+	end := min(len(m.items), m.scrollOff+max(1, m.height-1))
+	for i := m.scrollOff; i < end; i++ {
+		item := m.items[i]
+		prefix := "  "
+		if i == m.cursor {
+			prefix = selectedPrefix.Render("→ ")
+		}
+		box, label := "[ ]", item.Label
+		switch {
+		case item.Disabled != "":
+			box, label = "[-]", dimStyle.Render(label+" — "+item.Disabled)
+		case i == m.cursor:
+			label = selectedStyle.Render(label)
+		}
+		if item.Checked {
+			box = "[x]"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", prefix, box, label))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pickerKey(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestPicker_ToggleAndConfirm(t *testing.T) {
+	t.Parallel()
+	m := NewPickerModel("Dependencies", []PickerItem{
+		{ID: "task-001", Label: "task-001 Init", Checked: true},
+		{ID: "task-002", Label: "task-002 API"},
+		{ID: "task-003", Label: "task-003 Docs", Disabled: "depends on task-004"},
+	})
+	m.SetSize(80, 10)
+
+	for _, k := range []string{" ", "j", " ", "j", " "} {
+		m, _ = m.Update(pickerKey(k))
+	}
+	if got := m.Selected(); !reflect.DeepEqual(got, []string{"task-002"}) {
+		t.Errorf("Selected() = %v, want [task-002] (task-001 unchecked, task-003 disabled)", got)
+	}
+	if view := m.View(); !strings.Contains(view, "[-]") || !strings.Contains(view, "depends on task-004") {
+		t.Errorf("view should show why task-003 is disabled:\n%s", view)
+	}
+
+	_, cmd := m.Update(pickerKey("enter"))
+	if cmd == nil {
+		t.Fatal("enter should emit PickerDoneMsg")
+	}
+	if msg := cmd().(PickerDoneMsg); msg.Cancelled || !reflect.DeepEqual(msg.Selected, []string{"task-002"}) {
+		t.Errorf("PickerDoneMsg = %+v", msg)
+	}
+}
+
+func TestPicker_Cancel(t *testing.T) {
+	t.Parallel()
+	m := NewPickerModel("Dependencies", []PickerItem{{ID: "a", Label: "a"}})
+	_, cmd := m.Update(pickerKey("esc"))
+	if cmd == nil || !cmd().(PickerDoneMsg).Cancelled {
+		t.Error("esc should cancel")
+	}
+}
+
+func TestPicker_UncheckDisabled(t *testing.T) {
+	t.Parallel()
+	m := NewPickerModel("Dependencies", []PickerItem{{ID: "a", Label: "a", Checked: true, Disabled: "cycle"}})
+	m, _ = m.Update(pickerKey(" "))
+	if len(m.Selected()) != 0 {
+		t.Error("a checked disabled item should still be uncheckable")
+	}
+	m, _ = m.Update(pickerKey(" "))
+	if len(m.Selected()) != 0 {
+		t.Error("a disabled item should not be checkable")
+	}
+}
+
+func TestPicker_Empty(t *testing.T) {
+	t.Parallel()
+	m := NewPickerModel("Dependencies", nil)
+	m.SetSize(80, 10)
+	m, _ = m.Update(pickerKey(" "))
+	if !strings.Contains(m.View(), "Nothing to choose from") {
+		t.Error("empty picker should say so")
+	}
+}
@@ -53,7 +53,7 @@ func (m ProgressBarModel) View() string {
 		barWidth = 5
 	}
 	if m.total == 0 {
-		empty := strings.Repeat("░", barWidth)
+		empty := strings.Repeat(Glyph("░", "-"), barWidth)
 		return fmt.Sprintf("  %s 0/0 (0%%)", progressBarEmpty.Render(empty))
 	}
 
@@ -61,8 +61,8 @@ func (m ProgressBarModel) View() string {
 	filled := m.done * barWidth / m.total
 	empty := barWidth - filled
 
-	bar := progressBarFilled.Render(strings.Repeat("█", filled)) +
-		progressBarEmpty.Render(strings.Repeat("░", empty))
+	bar := progressBarFilled.Render(strings.Repeat(Glyph("█", "#"), filled)) +
+		progressBarEmpty.Render(strings.Repeat(Glyph("░", "-"), empty))
 
 	label := progressBarText.Render(fmt.Sprintf(" %d/%d (%d%%)", m.done, m.total, pct))
 
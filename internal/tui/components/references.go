@@ -0,0 +1,133 @@
+package components
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// References ties chat text to the items it mentions, e.g. task IDs in a
+// planning conversation. Mentions are highlighted; with an empty input,
+// Tab selects one and Enter expands it to its current details.
+type References struct {
+	Pattern *regexp.Regexp
+	// Resolve returns an item's details; false leaves the mention plain
+	// (e.g. a task ID that isn't in the plan).
+	Resolve func(ref string) (string, bool)
+}
+
+const maxRefLines = 10 // expanded details shown above the input
+
+var (
+	refStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#F59E0B")).
+			Bold(true)
+
+	selectedRefStyle = refStyle.
+				Underline(true)
+
+	refPanelStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), true, false, false, false).
+			BorderForeground(lipgloss.Color("#F59E0B")).
+			PaddingLeft(2)
+)
+
+// SetReferences enables reference highlighting and expansion.
+func (m *ChatModel) SetReferences(r References) {
+	m.refs = &r
+	m.refreshViewport()
+}
+
+// mentions lists the resolvable references in the conversation, oldest
+// first, each once at its latest mention.
+func (m ChatModel) mentions() []string {
+	if m.refs == nil {
+		return nil
+	}
+	var refs []string
+	for _, msg := range m.messages {
+		for _, ref := range m.refs.Pattern.FindAllString(msg.Content, -1) {
+			if _, ok := m.refs.Resolve(ref); !ok {
+				continue
+			}
+			if i := indexOf(refs, ref); i >= 0 {
+				refs = append(refs[:i], refs[i+1:]...)
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// cycleReference selects the previous mention, wrapping to the latest.
+func (m *ChatModel) cycleReference() {
+	refs := m.mentions()
+	if len(refs) == 0 {
+		return
+	}
+	i := indexOf(refs, m.selectedRef)
+	if i <= 0 {
+		i = len(refs)
+	}
+	m.selectedRef = refs[i-1]
+	m.refExpanded = false
+	m.refreshViewport()
+}
+
+// toggleReference expands or collapses the selected mention, selecting
+// the latest one if none is.
+func (m *ChatModel) toggleReference() {
+	refs := m.mentions()
+	if len(refs) == 0 {
+		return
+	}
+	if indexOf(refs, m.selectedRef) < 0 {
+		m.selectedRef = refs[len(refs)-1]
+		m.refExpanded = false
+	}
+	m.refExpanded = !m.refExpanded
+	m.refreshViewport()
+}
+
+// highlightReferences styles the mentions in rendered message text.
+func (m ChatModel) highlightReferences(text string) string {
+	if m.refs == nil {
+		return text
+	}
+	return m.refs.Pattern.ReplaceAllStringFunc(text, func(ref string) string {
+		if _, ok := m.refs.Resolve(ref); !ok {
+			return ref
+		}
+		if ref == m.selectedRef {
+			return selectedRefStyle.Render(ref)
+		}
+		return refStyle.Render(ref)
+	})
+}
+
+// referencePanel renders the expanded reference's details, or nothing.
+func (m ChatModel) referencePanel() []string {
+	if m.refs == nil || !m.refExpanded || m.selectedRef == "" {
+		return nil
+	}
+	details, ok := m.refs.Resolve(m.selectedRef)
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(details, "\n"), "\n")
+	if len(lines) > maxRefLines {
+		lines = append(lines[:maxRefLines-1], "…")
+	}
+	lines = append(lines, "(Enter closes · Tab selects another)")
+	return strings.Split(Bordered(refPanelStyle).Width(max(0, m.width-2)).Render(strings.Join(lines, "\n")), "\n")
+}
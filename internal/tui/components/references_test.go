@@ -0,0 +1,68 @@
+package components
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func refChat() ChatModel {
+	m := NewChatModel(func(string) tea.Cmd { return nil }, nil)
+	m.SetSize(80, 24)
+	m.SetReferences(References{
+		Pattern: regexp.MustCompile(`\btask-\d{3}\b`),
+		Resolve: func(ref string) (string, bool) {
+			if ref == "task-009" {
+				return "", false
+			}
+			return ref + " details", true
+		},
+	})
+	return m
+}
+
+func TestChat_Mentions(t *testing.T) {
+	t.Parallel()
+	m := refChat()
+	m.AddMessage(RoleUser, "Split task-001 and task-002")
+	m.AddMessage(RoleAssistant, "task-001 becomes task-003; task-009 does not exist")
+
+	want := []string{"task-002", "task-001", "task-003"}
+	if got := m.mentions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mentions() = %v, want %v", got, want)
+	}
+}
+
+func TestChat_ExpandReference(t *testing.T) {
+	t.Parallel()
+	m := refChat()
+	m.AddMessage(RoleAssistant, "task-001 then task-002")
+
+	key := func(k tea.KeyType) { m, _ = m.Update(tea.KeyMsg{Type: k}) }
+
+	key(tea.KeyEnter) // expands the latest mention
+	if m.selectedRef != "task-002" || !m.refExpanded {
+		t.Fatalf("after Enter: selected %q, expanded %v", m.selectedRef, m.refExpanded)
+	}
+	if panel := strings.Join(m.referencePanel(), "\n"); !strings.Contains(panel, "task-002 details") {
+		t.Errorf("referencePanel() = %q", panel)
+	}
+
+	key(tea.KeyTab) // earlier mention, collapsed
+	if m.selectedRef != "task-001" || m.refExpanded {
+		t.Errorf("after Tab: selected %q, expanded %v", m.selectedRef, m.refExpanded)
+	}
+	key(tea.KeyTab) // wraps around
+	if m.selectedRef != "task-002" {
+		t.Errorf("after second Tab: selected %q, want task-002", m.selectedRef)
+	}
+
+	key(tea.KeyEnter)
+	key(tea.KeyEnter) // closes
+	if m.refExpanded || m.referencePanel() != nil {
+		t.Error("second Enter should collapse the reference")
+	}
+}
@@ -0,0 +1,72 @@
+package components
+
+import "fmt"
+
+// StatusStyle picks how task statuses are drawn, so they stay apart
+// without relying on color or emoji support.
+type StatusStyle string
+
+const (
+	StatusEmoji   StatusStyle = "emoji"   // ✅ ❌ 🔄 ⏭ (default)
+	StatusBadges  StatusStyle = "badges"  // [DONE] [FAIL] [RUN] [SKIP]
+	StatusSymbols StatusStyle = "symbols" // ✓ ✗ ▶ » — one shape per status
+	StatusASCII   StatusStyle = "ascii"   // [x] [!] [>] [-] — for terminals without UTF-8
+)
+
+// StatusStyles lists the styles Settings.StatusStyle accepts.
+var StatusStyles = []string{string(StatusEmoji), string(StatusBadges), string(StatusSymbols), string(StatusASCII)}
+
+// ValidStatusStyle reports whether s names a style; "" is the default.
+func ValidStatusStyle(s string) bool {
+	switch StatusStyle(s) {
+	case "", StatusEmoji, StatusBadges, StatusSymbols, StatusASCII:
+		return true
+	}
+	return false
+}
+
+var statusIcons = map[StatusStyle]map[TaskStatus]string{
+	StatusEmoji: {
+		StatusDone: "✅", StatusFailed: "❌", StatusInProgress: "🔄", StatusSkipped: "⏭",
+		StatusPending: "  ", StatusCancelled: "  ",
+	},
+	StatusBadges: {
+		StatusDone: "[DONE]", StatusFailed: "[FAIL]", StatusInProgress: "[RUN]", StatusSkipped: "[SKIP]",
+		StatusPending: "[TODO]", StatusCancelled: "[CANCEL]",
+	},
+	StatusSymbols: {
+		StatusDone: "✓", StatusFailed: "✗", StatusInProgress: "▶", StatusSkipped: "»",
+		StatusPending: "·", StatusCancelled: "⊘",
+	},
+	StatusASCII: {
+		StatusDone: "[x]", StatusFailed: "[!]", StatusInProgress: "[>]", StatusSkipped: "[-]",
+		StatusPending: "[ ]", StatusCancelled: "[/]",
+	},
+}
+
+// humanIcons mark pending tasks a person has to do.
+var humanIcons = map[StatusStyle]string{StatusEmoji: "👤", StatusBadges: "[HUMAN]", StatusSymbols: "@", StatusASCII: "[@]"}
+
+// statusWidths pad every icon of a style to the same width, so titles
+// line up.
+var statusWidths = map[StatusStyle]int{StatusEmoji: 0, StatusBadges: 8, StatusSymbols: 2, StatusASCII: 3}
+
+// StatusIcon returns the indicator for a task status in a style; human
+// marks a pending task owned by a person. Unknown styles draw emoji, and
+// every style draws ascii when drawing with ASCII only (see SetASCII).
+func StatusIcon(style StatusStyle, status TaskStatus, human bool) string {
+	if _, ok := statusIcons[style]; !ok {
+		style = StatusEmoji
+	}
+	if asciiOnly {
+		style = StatusASCII
+	}
+	icon, ok := statusIcons[style][status]
+	if !ok {
+		icon = statusIcons[style][StatusPending]
+	}
+	if human && status == StatusPending {
+		icon = humanIcons[style]
+	}
+	return fmt.Sprintf("%-*s", statusWidths[style], icon)
+}
@@ -0,0 +1,40 @@
+package components
+
+import "testing"
+
+func TestStatusIcon(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		style  StatusStyle
+		status TaskStatus
+		human  bool
+		want   string
+	}{
+		{StatusEmoji, StatusDone, false, "✅"},
+		{StatusEmoji, StatusPending, true, "👤"},
+		{"", StatusFailed, false, "❌"},
+		{StatusBadges, StatusDone, false, "[DONE]  "},
+		{StatusBadges, StatusInProgress, false, "[RUN]   "},
+		{StatusBadges, StatusSkipped, false, "[SKIP]  "},
+		{StatusBadges, StatusPending, true, "[HUMAN] "},
+		{StatusBadges, StatusCancelled, false, "[CANCEL]"},
+		{StatusSymbols, StatusFailed, false, "✗ "},
+		{StatusSymbols, StatusDone, true, "✓ "}, // human only marks pending tasks
+		{StatusASCII, StatusDone, false, "[x]"},
+		{StatusASCII, StatusPending, true, "[@]"},
+	}
+	for _, tt := range tests {
+		if got := StatusIcon(tt.style, tt.status, tt.human); got != tt.want {
+			t.Errorf("StatusIcon(%q, %q, %v) = %q, want %q", tt.style, tt.status, tt.human, got, tt.want)
+		}
+	}
+
+	for _, s := range append(StatusStyles, "") {
+		if !ValidStatusStyle(s) {
+			t.Errorf("ValidStatusStyle(%q) = false", s)
+		}
+	}
+	if ValidStatusStyle("colors") {
+		t.Error("ValidStatusStyle(colors) = true")
+	}
+}
@@ -0,0 +1,138 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusLevel says how a status message is shown.
+type StatusLevel int
+
+const (
+	StatusInfo StatusLevel = iota
+	StatusWarning
+	StatusError
+)
+
+// StatusEntry is one message shown on a StatusLine.
+type StatusEntry struct {
+	Level StatusLevel
+	Text  string
+	At    time.Time
+}
+
+// maxStatusEntries bounds the message log.
+const maxStatusEntries = 100
+
+// StatusLine shows the latest message until it is dismissed, instead of a
+// flash that disappears before it can be read, and keeps every message in
+// a log the user can open.
+type StatusLine struct {
+	entries   []StatusEntry
+	dismissed bool // the latest entry was dismissed
+	logOpen   bool
+}
+
+var (
+	statusInfoStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#10B981"))
+
+	statusWarningStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#F59E0B")).
+				Bold(true)
+
+	statusErrorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#EF4444")).
+				Bold(true)
+)
+
+func (l StatusLevel) style() lipgloss.Style {
+	switch l {
+	case StatusWarning:
+		return statusWarningStyle
+	case StatusError:
+		return statusErrorStyle
+	}
+	return statusInfoStyle
+}
+
+func (l StatusLevel) icon() string {
+	switch l {
+	case StatusWarning:
+		return Glyph("⚠", "!")
+	case StatusError:
+		return Glyph("✗", "x")
+	}
+	return Glyph("✓", "+")
+}
+
+// Push shows a new message.
+func (s *StatusLine) Push(level StatusLevel, text string) {
+	s.entries = append(s.entries, StatusEntry{Level: level, Text: text, At: time.Now()})
+	if len(s.entries) > maxStatusEntries {
+		s.entries = s.entries[len(s.entries)-maxStatusEntries:]
+	}
+	s.dismissed = false
+}
+
+// Dismiss hides the current message; it stays in the log. It reports
+// whether there was one to hide.
+func (s *StatusLine) Dismiss() bool {
+	if _, ok := s.Current(); !ok {
+		return false
+	}
+	s.dismissed = true
+	return true
+}
+
+// Current returns the message being shown.
+func (s StatusLine) Current() (StatusEntry, bool) {
+	if s.dismissed || len(s.entries) == 0 {
+		return StatusEntry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+// Entries returns the message log, oldest first.
+func (s StatusLine) Entries() []StatusEntry {
+	return s.entries
+}
+
+// ToggleLog opens or closes the message log.
+func (s *StatusLine) ToggleLog() {
+	s.logOpen = !s.logOpen
+}
+
+// LogOpen reports whether the message log is open.
+func (s StatusLine) LogOpen() bool {
+	return s.logOpen
+}
+
+// View renders the current message with how to dismiss it, or "" when
+// there is none.
+func (s StatusLine) View(width int) string {
+	e, ok := s.Current()
+	if !ok {
+		return ""
+	}
+	hint := dimStyle.Render("  esc dismiss · m messages")
+	text := e.Level.style().Render(e.Level.icon() + " " + e.Text)
+	return lipgloss.NewStyle().Width(width).PaddingLeft(1).Render(text + hint)
+}
+
+// LogView renders the message log, newest first, in at most height lines.
+func (s StatusLine) LogView(width, height int) string {
+	lines := []string{selectedStyle.Render(fmt.Sprintf("Messages (%d) — m or esc closes", len(s.entries)))}
+	if len(s.entries) == 0 {
+		lines = append(lines, dimStyle.Render("  No messages yet"))
+	}
+	for i := len(s.entries) - 1; i >= 0 && len(lines) < max(2, height); i-- {
+		e := s.entries[i]
+		lines = append(lines, fmt.Sprintf("  %s %s", dimStyle.Render(e.At.Format("15:04:05")),
+			e.Level.style().Render(e.Level.icon()+" "+e.Text)))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,110 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		run         func(s *StatusLine)
+		wantCurrent string // "" = nothing shown
+		wantEntries int
+	}{
+		{name: "empty", run: func(*StatusLine) {}},
+		{
+			name: "latest message is shown",
+			run: func(s *StatusLine) {
+				s.Push(StatusInfo, "saved")
+				s.Push(StatusError, "boom")
+			},
+			wantCurrent: "boom",
+			wantEntries: 2,
+		},
+		{
+			name: "dismiss hides but keeps the log",
+			run: func(s *StatusLine) {
+				s.Push(StatusError, "boom")
+				s.Dismiss()
+			},
+			wantEntries: 1,
+		},
+		{
+			name: "a new message after dismiss is shown",
+			run: func(s *StatusLine) {
+				s.Push(StatusError, "boom")
+				s.Dismiss()
+				s.Push(StatusWarning, "careful")
+			},
+			wantCurrent: "careful",
+			wantEntries: 2,
+		},
+		{
+			name: "log is bounded",
+			run: func(s *StatusLine) {
+				for i := range maxStatusEntries + 5 {
+					s.Push(StatusInfo, fmt.Sprintf("msg %d", i))
+				}
+			},
+			wantCurrent: fmt.Sprintf("msg %d", maxStatusEntries+4),
+			wantEntries: maxStatusEntries,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var s StatusLine
+			tt.run(&s)
+			e, ok := s.Current()
+			if got := e.Text; ok != (tt.wantCurrent != "") || got != tt.wantCurrent {
+				t.Errorf("Current() = %q, %v; want %q", got, ok, tt.wantCurrent)
+			}
+			if len(s.Entries()) != tt.wantEntries {
+				t.Errorf("entries = %d, want %d", len(s.Entries()), tt.wantEntries)
+			}
+			if view := s.View(80); (view != "") != ok {
+				t.Errorf("View() = %q with current shown = %v", view, ok)
+			}
+		})
+	}
+}
+
+func TestStatusLine_Dismiss(t *testing.T) {
+	t.Parallel()
+	var s StatusLine
+	if s.Dismiss() {
+		t.Error("Dismiss() with nothing shown = true")
+	}
+	s.Push(StatusError, "boom")
+	if !s.Dismiss() {
+		t.Error("Dismiss() = false, want true")
+	}
+	if s.Dismiss() {
+		t.Error("second Dismiss() = true, want false so esc falls through")
+	}
+}
+
+func TestStatusLine_LogView(t *testing.T) {
+	t.Parallel()
+	var s StatusLine
+	s.Push(StatusInfo, "first")
+	s.Push(StatusError, "second")
+	s.Dismiss()
+	s.ToggleLog()
+	if !s.LogOpen() {
+		t.Fatal("LogOpen() = false after ToggleLog")
+	}
+
+	view := s.LogView(80, 10)
+	first, second := strings.Index(view, "first"), strings.Index(view, "second")
+	if first < 0 || second < 0 || second > first {
+		t.Errorf("log should list dismissed messages newest first:\n%s", view)
+	}
+
+	if short := s.LogView(80, 2); strings.Contains(short, "first") {
+		t.Errorf("log should fit its height:\n%s", short)
+	}
+}
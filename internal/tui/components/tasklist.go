@@ -37,18 +37,20 @@ type TaskSelectedMsg struct {
 
 // TaskActionMsg is emitted when the user triggers an action on a task.
 type TaskActionMsg struct {
-	Action string // "edit", "delete", "new", "reorder_up", "reorder_down"
+	Action string // "edit", "delete", "new", "duplicate", "reorder_up", "reorder_down", "prompt"
 	TaskID string
 }
 
 // TaskListModel is a reusable list component for displaying tasks.
 type TaskListModel struct {
-	items      []TaskListItem
-	cursor     int  // currently highlighted item
-	scrollOff  int  // first visible item index
-	detailView bool // whether to show expanded detail panel
-	width      int
-	height     int
+	items       []TaskListItem
+	cursor      int             // currently highlighted item
+	scrollOff   int             // first visible item index
+	detailView  bool            // whether to show expanded detail panel
+	marked      map[string]bool // IDs marked with space for bulk actions
+	statusStyle StatusStyle     // how statuses are drawn; "" = emoji
+	width       int
+	height      int
 }
 
 // Styles for task list rendering.
@@ -57,21 +59,12 @@ var (
 			Foreground(lipgloss.Color("#7C3AED")).
 			Bold(true)
 
-	doneIcon = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
-			Render("✅")
-
-	failedIcon = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
-			Render("❌")
-
-	progressIcon = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#06B6D4")).
-			Render("🔄")
-
-	skippedIcon = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
-			Render("⏭")
+	statusColors = map[TaskStatus]lipgloss.Style{
+		StatusDone:       lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981")),
+		StatusFailed:     lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")),
+		StatusInProgress: lipgloss.NewStyle().Foreground(lipgloss.Color("#06B6D4")),
+		StatusSkipped:    lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")),
+	}
 
 	complexityStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#F59E0B"))
@@ -102,9 +95,19 @@ func NewTaskListModel(items []TaskListItem) TaskListModel {
 	}
 }
 
+// SetStatusStyle sets how task statuses are drawn.
+func (m *TaskListModel) SetStatusStyle(style StatusStyle) {
+	m.statusStyle = style
+}
+
 // SetItems replaces the items (e.g., after delete/reorder).
 func (m *TaskListModel) SetItems(items []TaskListItem) {
 	m.items = items
+	for id := range m.marked {
+		if i := m.indexOf(id); i < 0 || !items[i].Editable {
+			delete(m.marked, id)
+		}
+	}
 	if m.cursor >= len(items) {
 		m.cursor = len(items) - 1
 	}
@@ -147,6 +150,36 @@ func (m *TaskListModel) SetCursorByID(id string) {
 	}
 }
 
+// Marked returns the IDs of the marked tasks, in list order.
+func (m TaskListModel) Marked() []string {
+	var ids []string
+	for _, item := range m.items {
+		if m.marked[item.ID] {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// ClearMarks unmarks every task.
+func (m *TaskListModel) ClearMarks() {
+	m.marked = nil
+}
+
+func (m TaskListModel) indexOf(id string) int {
+	for i, item := range m.items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// DetailVisible reports whether the detail panel is open.
+func (m TaskListModel) DetailVisible() bool {
+	return m.detailView
+}
+
 // ToggleDetail toggles the expanded detail panel.
 func (m *TaskListModel) ToggleDetail() {
 	m.detailView = !m.detailView
@@ -180,6 +213,23 @@ func (m TaskListModel) Update(msg tea.Msg) (TaskListModel, tea.Cmd) {
 			m.detailView = !m.detailView
 			return m, nil
 
+		case " ": // mark for bulk actions, then move on
+			if item := m.SelectedItem(); item != nil && item.Editable {
+				if m.marked == nil {
+					m.marked = map[string]bool{}
+				}
+				if m.marked[item.ID] {
+					delete(m.marked, item.ID)
+				} else {
+					m.marked[item.ID] = true
+				}
+				if m.cursor < len(m.items)-1 {
+					m.cursor++
+					m.ensureVisible()
+				}
+			}
+			return m, nil
+
 		case "e":
 			if item := m.SelectedItem(); item != nil && item.Editable {
 				return m, func() tea.Msg {
@@ -196,11 +246,35 @@ func (m TaskListModel) Update(msg tea.Msg) (TaskListModel, tea.Cmd) {
 			}
 			return m, nil
 
+		case "p":
+			if item := m.SelectedItem(); item != nil && item.Editable {
+				return m, func() tea.Msg {
+					return TaskActionMsg{Action: "prompt", TaskID: item.ID}
+				}
+			}
+			return m, nil
+
 		case "n":
 			return m, func() tea.Msg {
 				return TaskActionMsg{Action: "new"}
 			}
 
+		case "y": // any task, done ones included, can be copied
+			if item := m.SelectedItem(); item != nil {
+				return m, func() tea.Msg {
+					return TaskActionMsg{Action: "duplicate", TaskID: item.ID}
+				}
+			}
+			return m, nil
+
+		case "f": // start execution from here; the task list owner validates
+			if item := m.SelectedItem(); item != nil {
+				return m, func() tea.Msg {
+					return TaskActionMsg{Action: "start_from", TaskID: item.ID}
+				}
+			}
+			return m, nil
+
 		case "J": // shift+j = reorder down
 			if item := m.SelectedItem(); item != nil && item.Editable {
 				return m, func() tea.Msg {
@@ -259,7 +333,7 @@ func (m TaskListModel) View() string {
 	}
 
 	// Render detail panel
-	separator := detailBorderStyle.Render(strings.Repeat("─", m.width))
+	separator := detailBorderStyle.Render(strings.Repeat(Glyph("─", "-"), m.width))
 	detailView := m.renderDetail(detailHeight)
 
 	return lipgloss.JoinVertical(lipgloss.Left, listView, separator, detailView)
@@ -291,18 +365,9 @@ func (m TaskListModel) renderItem(idx int) string {
 	isSelected := idx == m.cursor
 
 	// Status icon
-	var icon string
-	switch item.Status {
-	case StatusDone:
-		icon = doneIcon
-	case StatusFailed:
-		icon = failedIcon
-	case StatusInProgress:
-		icon = progressIcon
-	case StatusSkipped:
-		icon = skippedIcon
-	default:
-		icon = "  " // blank for pending
+	icon := StatusIcon(m.statusStyle, item.Status, false)
+	if color, ok := statusColors[item.Status]; ok {
+		icon = color.Render(icon)
 	}
 
 	// Complexity badge
@@ -318,6 +383,15 @@ func (m TaskListModel) renderItem(idx int) string {
 		prefix = "  "
 	}
 
+	// Mark column, only while something is marked
+	if len(m.marked) > 0 {
+		if m.marked[item.ID] {
+			prefix += selectedPrefix.Render("● ")
+		} else {
+			prefix += "  "
+		}
+	}
+
 	title := style.Render(item.Title)
 	if !item.Editable && !isSelected {
 		title = dimStyle.Render(item.Title)
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -97,6 +98,36 @@ func TestTaskList_EditAction(t *testing.T) {
 	}
 }
 
+func TestTaskList_PromptAction(t *testing.T) {
+	t.Parallel()
+	m := NewTaskListModel(sampleItems())
+	m.SetSize(80, 24)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if cmd == nil {
+		t.Fatal("'p' on editable item should produce a command")
+	}
+	action, ok := cmd().(TaskActionMsg)
+	if !ok || action.Action != "prompt" || action.TaskID != "task-001" {
+		t.Errorf("got %+v, want prompt action for task-001", action)
+	}
+}
+
+func TestTaskList_StartFromAction(t *testing.T) {
+	t.Parallel()
+	m := NewTaskListModel(sampleItems())
+	m.SetSize(80, 24)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if cmd == nil {
+		t.Fatal("'f' should produce a command")
+	}
+	action, ok := cmd().(TaskActionMsg)
+	if !ok || action.Action != "start_from" || action.TaskID != "task-001" {
+		t.Errorf("got %+v, want start_from action for task-001", action)
+	}
+}
+
 func TestTaskList_DeleteAction(t *testing.T) {
 	t.Parallel()
 	m := NewTaskListModel(sampleItems())
@@ -135,6 +166,22 @@ func TestTaskList_NewAction(t *testing.T) {
 	}
 }
 
+func TestTaskList_DuplicateAction(t *testing.T) {
+	t.Parallel()
+	m := NewTaskListModel(sampleItems())
+	m.SetSize(80, 24)
+	m.SetCursorByID("task-003") // done tasks can be copied too
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("'y' should produce a command")
+	}
+	action, ok := cmd().(TaskActionMsg)
+	if !ok || action.Action != "duplicate" || action.TaskID != "task-003" {
+		t.Errorf("got %+v, want duplicate task-003", action)
+	}
+}
+
 func TestTaskList_ReorderDownAction(t *testing.T) {
 	t.Parallel()
 	m := NewTaskListModel(sampleItems())
@@ -188,6 +235,40 @@ func TestTaskList_EditOnNonEditable(t *testing.T) {
 	}
 }
 
+func TestTaskList_SpaceMarks(t *testing.T) {
+	t.Parallel()
+	m := NewTaskListModel(sampleItems())
+	m.SetSize(80, 24)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	m, _ = m.Update(space) // marks task-001, moves to task-002
+	m, _ = m.Update(space) // marks task-002, moves to the done task-003
+	m, _ = m.Update(space) // not editable: no mark
+	if got := m.Marked(); len(got) != 2 || got[0] != "task-001" || got[1] != "task-002" {
+		t.Fatalf("Marked() = %v, want [task-001 task-002]", got)
+	}
+	if !strings.Contains(m.View(), "●") {
+		t.Error("view should show the marks")
+	}
+
+	m.SetCursorByID("task-001")
+	m, _ = m.Update(space)
+	if got := m.Marked(); len(got) != 1 || got[0] != "task-002" {
+		t.Errorf("after unmarking task-001 Marked() = %v", got)
+	}
+
+	m.SetItems(sampleItems()[:1])
+	if got := m.Marked(); len(got) != 0 {
+		t.Errorf("marks on removed items kept: %v", got)
+	}
+
+	m, _ = m.Update(space)
+	m.ClearMarks()
+	if got := m.Marked(); len(got) != 0 {
+		t.Errorf("after ClearMarks Marked() = %v", got)
+	}
+}
+
 func TestTaskList_SelectedItem(t *testing.T) {
 	t.Parallel()
 	m := NewTaskListModel(sampleItems())
@@ -2,16 +2,23 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/codeindex"
+	"github.com/manasm11/forge/internal/docs"
 	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/janitor"
+	"github.com/manasm11/forge/internal/platform"
+	"github.com/manasm11/forge/internal/schedule"
 	"github.com/manasm11/forge/internal/state"
 	"github.com/manasm11/forge/internal/tui/components"
 )
@@ -35,6 +42,22 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// scheduleReachedMsg signals that a delayed run has started.
+type scheduleReachedMsg struct{}
+
+// markDoneMsg carries the HEAD SHA for a task being marked done by hand.
+type markDoneMsg struct {
+	taskID string
+	sha    string
+	err    error
+}
+
+// execNewTaskFinishedMsg is sent when $EDITOR closes on a task added mid-run.
+type execNewTaskFinishedMsg struct {
+	err     error
+	tmpPath string
+}
+
 // ExecutionModel is the TUI model for the execution dashboard.
 type ExecutionModel struct {
 	state       *state.State
@@ -51,10 +74,68 @@ type ExecutionModel struct {
 	height      int
 	startedAt   time.Time
 
+	// Runs recorded before this session, shown alongside the summary
+	previousRuns []state.RunSummary
+
+	// Runs tab (toggled with tab)
+	runsView RunsModel
+	showRuns bool
+
+	// Plan edits sent to the runner, applied between tasks
+	edits   chan executor.PlanEdit
+	taskIDs []string        // every task ID in the plan, for allocating new ones
+	added   map[string]bool // tasks added here that the runner has not accepted yet
+
+	flashMsg string
+	flashErr bool
+
+	// Skip prompt: task ID awaiting a reason (empty when not prompting)
+	skipTaskID string
+	skipInput  textinput.Model
+
+	// Transcript search: / reads a query, then the matching log lines of
+	// every task are listed until esc; enter jumps to one
+	searching    bool // typing the query
+	searchInput  textinput.Model
+	searchOpen   bool // showing the results
+	searchQuery  string
+	searchGroups []LogSearchGroup
+	searchCursor int // selected match, counted across groups
+
+	// Failed task awaiting confirmation to be marked done by hand
+	markTaskID string
+
+	// Manual task the runner is waiting on, answered with y/x
+	confirm      chan executor.ManualConfirmation
+	manualTaskID string
+
+	// Delayed start: the runner waits until scheduledAt unless startNow fires
+	scheduledAt *time.Time
+	startNow    chan struct{}
+
+	// Why the runner paused early (e.g. budget exhausted); shown in the summary
+	pauseReason string
+
+	// state.json changed on disk during the run; our copy must not be saved
+	stateConflict bool
+
+	// The run refused to start on these uncommitted files; s restarts it
+	// with stashDirty
+	dirtyFiles []string
+	stashDirty bool
+
+	// Agent files forge wrote this session; they don't block the run
+	generated []string
+
 	// Execution control
 	cancelFunc context.CancelFunc
 	started    bool // whether execution has been started
 	userMoved  bool // user manually navigated away from running task
+	replay     bool // events come from a recorded journal, not a runner
+
+	// Plain linear view for screen readers and dumb terminals; events are
+	// printed above it as an append-only log (see Accessible)
+	accessible bool
 }
 
 // NewExecutionModel creates a new execution dashboard.
@@ -85,7 +166,18 @@ func NewExecutionModel(s *state.State, root string, claude executor.ClaudeExecut
 		logStream:   components.NewLogStreamModel(),
 		progressBar: components.NewProgressBarModel(total, 30),
 		status:      ExecRunning,
-		startedAt:   time.Now(),
+		startedAt:   clock(),
+
+		previousRuns: append([]state.RunSummary(nil), s.Runs...),
+		runsView:     NewRunsModel(root),
+		edits:        make(chan executor.PlanEdit, 32),
+		added:        make(map[string]bool),
+		confirm:      make(chan executor.ManualConfirmation, 1),
+		scheduledAt:  s.ScheduledStart,
+		startNow:     make(chan struct{}, 1),
+	}
+	for _, t := range s.Tasks {
+		m.taskIDs = append(m.taskIDs, t.ID)
 	}
 	m.progressBar.SetDone(done)
 
@@ -122,28 +214,71 @@ func (m *ExecutionModel) StartExecution() tea.Cmd {
 	s := m.state
 	root := m.stateRoot
 	claude := m.claude
+	edits := m.edits
+	confirm := m.confirm
+	startNow := m.startNow
+	stashDirty := m.stashDirty
+	generated := m.generated
 
 	return func() tea.Msg {
 		ctx, cancel := context.WithCancel(context.Background())
 		// Send cancel func back via a message so the model can store it
 		p.Send(executionCancelFuncMsg{cancel: cancel})
 
+		// Hold off until the scheduled start; enter in the dashboard starts early
+		if at := s.ScheduledStart; at != nil {
+			waitCtx, stopWaiting := context.WithCancel(ctx)
+			go func() {
+				select {
+				case <-startNow:
+					stopWaiting()
+				case <-waitCtx.Done():
+				}
+			}()
+			schedule.Wait(waitCtx, *at)
+			stopWaiting()
+			if ctx.Err() != nil {
+				return ExecutionDoneMsg{Err: ctx.Err()}
+			}
+			s.ScheduledStart = nil
+			p.Send(scheduleReachedMsg{})
+		}
+
 		// Read context file
 		contextContent := ""
 		data, err := os.ReadFile(filepath.Join(root, ".forge", "context.md"))
 		if err == nil {
 			contextContent = string(data)
 		}
+		contextContent += docs.Context(ctx, root, s.Settings.ContextURLs)
+
+		// Journal failures shouldn't block execution; a nil journal records nothing.
+		journal, _ := executor.OpenJournal(root)
+		defer journal.Close()
+		webhook := executor.NewWebhook(s.Settings.WebhookURL, nil)
+		defer webhook.Close()
 
 		runner := executor.NewRunner(executor.RunnerConfig{
 			State:       s,
 			StateRoot:   root,
 			Git:         executor.NewRealGitOps(root),
-			Tests:       executor.NewRealTestRunner(root),
+			Tests:       executor.NewRealTestRunner(root).WithShell(s.Settings.Shell),
 			Claude:      claude,
 			ContextFile: contextContent,
 			BaseBranch:  s.Settings.BaseBranch,
 			RemoteURL:   s.Settings.RemoteURL,
+			Issues:      executor.NewGhIssueFiler(root),
+			PRs:         executor.NewGhPRCreator(root),
+			Checks:      executor.NewGhCheckPublisher(root),
+			CI:          executor.NewGhCIWatcher(),
+			Workspace:   executor.RealWorkspace(s.Settings.Shell),
+			Embed:       codeindex.OllamaEmbedder(s.Settings),
+			Journal:     journal,
+			Webhook:     webhook,
+			Edits:       edits,
+			Confirm:     confirm,
+			StashDirty:  stashDirty,
+			Generated:   generated,
 			OnEvent: func(e executor.TaskEvent) {
 				p.Send(ExecutionEventMsg{Event: e})
 			},
@@ -154,21 +289,153 @@ func (m *ExecutionModel) StartExecution() tea.Cmd {
 	}
 }
 
+// StartReplay feeds recorded events to the dashboard instead of running
+// tasks, waiting the recorded gaps divided by speed. Must be called after
+// SetProgram, on a model built from BuildReplayState.
+func (m *ExecutionModel) StartReplay(events []executor.TaskEvent, speed float64) tea.Cmd {
+	if m.started || m.program == nil {
+		return nil
+	}
+	m.started = true
+	m.replay = true
+	m.scheduledAt = nil
+
+	p := m.program
+	delays := ReplayDelays(events, speed, maxReplayGap)
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.Send(executionCancelFuncMsg{cancel: cancel})
+
+		var runErr error
+		for i, e := range events {
+			select {
+			case <-time.After(delays[i]):
+			case <-ctx.Done():
+				return ExecutionDoneMsg{Err: ctx.Err()}
+			}
+			switch e.Type {
+			case executor.EventBudgetExhausted:
+				runErr = executor.ErrBudgetExhausted
+			case executor.EventRunPaused:
+				runErr = executor.ErrPaused
+			}
+			p.Send(ExecutionEventMsg{Event: e})
+		}
+		return ExecutionDoneMsg{Err: runErr}
+	}
+}
+
 // executionCancelFuncMsg carries the cancel function from the runner goroutine.
 type executionCancelFuncMsg struct {
 	cancel context.CancelFunc
 }
 
-// Update handles messages for the execution dashboard.
+// SetGenerated names the files forge wrote in the project root this
+// session (see executor.RunnerConfig.Generated).
+func (m *ExecutionModel) SetGenerated(files []string) {
+	m.generated = files
+}
+
+// SetAccessible switches the dashboard to its plain linear view, with
+// every event printed as a labeled line of an append-only log.
+func (m *ExecutionModel) SetAccessible(on bool) {
+	m.accessible = on
+}
+
+// Update handles messages for the execution dashboard. In accessible mode
+// events and the final summary are also printed above the view.
 func (m ExecutionModel) Update(msg tea.Msg) (ExecutionModel, tea.Cmd) {
+	m, cmd := m.update(msg)
+	if !m.accessible {
+		return m, cmd
+	}
+	var announce string
+	switch msg := msg.(type) {
+	case ExecutionEventMsg:
+		announce = AnnounceEvent(msg.Event, m.taskTitle(msg.Event.TaskID))
+	case ExecutionDoneMsg:
+		if m.summary != nil {
+			announce = FormatSummaryText(*m.summary)
+		}
+	}
+	if announce == "" {
+		return m, cmd
+	}
+	return m, tea.Batch(tea.Println(announce), cmd)
+}
+
+func (m ExecutionModel) taskTitle(taskID string) string {
+	for _, tp := range m.progress {
+		if tp.TaskID == taskID {
+			return tp.Title
+		}
+	}
+	return ""
+}
+
+func (m ExecutionModel) update(msg tea.Msg) (ExecutionModel, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case executionCancelFuncMsg:
 		m.cancelFunc = msg.cancel
 		return m, nil
 
+	case clearFlashMsg:
+		m.flashMsg = ""
+		m.flashErr = false
+		return m, nil
+
+	case execNewTaskFinishedMsg:
+		return m.handleNewTaskFinished(msg)
+
+	case markDoneMsg:
+		return m.handleMarkDone(msg)
+
+	case scheduleReachedMsg:
+		m.scheduledAt = nil
+		m.startedAt = clock()
+		return m, nil
+
 	case ExecutionEventMsg:
 		ApplyEventToProgress(m.progress, msg.Event)
+		if m.replay {
+			ApplyEventToTasks(m.state.Tasks, msg.Event)
+		}
+
+		switch msg.Event.Type {
+		case executor.EventManualWait:
+			m.manualTaskID = msg.Event.TaskID
+		case executor.EventTaskDone, executor.EventTaskFailed:
+			if msg.Event.TaskID == m.manualTaskID {
+				m.manualTaskID = ""
+			}
+		case executor.EventPlanChanged:
+			delete(m.added, msg.Event.TaskID)
+		case executor.EventStateReloaded:
+			if !m.replay {
+				m.reloadProgress()
+				return m, m.flash("Picked up changes made to state.json by another process", false)
+			}
+		case executor.EventBudgetExhausted:
+			m.pauseReason = "budget exhausted — " + msg.Event.Message
+			return m, tea.Batch(m.flash(m.pauseReason, true), m.alert(msg.Event))
+		case executor.EventRunPaused:
+			m.pauseReason = msg.Event.Message
+			return m, tea.Batch(m.flash(m.pauseReason, true), m.alert(msg.Event))
+		case executor.EventEditRejected:
+			if m.added[msg.Event.TaskID] {
+				delete(m.added, msg.Event.TaskID)
+				m.removeProgress(msg.Event.TaskID)
+			}
+			return m, m.flash(msg.Event.Message, true)
+		case executor.EventError:
+			// Run-level errors (merge, push) have no task log to go to;
+			// task errors (code review, CI, base sync) go to the task's
+			if msg.Event.TaskID == "" {
+				return m, m.flash(msg.Event.Message, true)
+			}
+		}
 
 		// Update log stream for current task
 		if line := EventToLogLine(msg.Event); line != nil {
@@ -207,11 +474,30 @@ func (m ExecutionModel) Update(msg tea.Msg) (ExecutionModel, tea.Cmd) {
 			}
 		}
 
-		return m, nil
+		return m, m.alert(msg.Event)
 
 	case ExecutionDoneMsg:
-		m.status = ComputeExecutionStatus(m.state.Tasks)
+		var dirty *executor.DirtyWorktreeError
+		if errors.As(msg.Err, &dirty) {
+			m.dirtyFiles = dirty.Files
+			m.status = ExecStopped
+			return m, nil
+		}
+		if m.status != ExecCancelled {
+			m.status = ComputeExecutionStatus(m.state.Tasks)
+			if errors.Is(msg.Err, executor.ErrBudgetExhausted) || errors.Is(msg.Err, executor.ErrPaused) {
+				m.status = ExecPaused
+			}
+			m.stateConflict = errors.Is(msg.Err, executor.ErrStateConflict)
+		}
 		s := ComputeExecutionSummary(m.progress)
+		if run := m.state.LastRun(); run != nil {
+			s.CostUSD = run.CostUSD
+			s.Verification = run.Verification
+		}
+		if m.status == ExecPaused {
+			s.PauseReason = m.pauseReason
+		}
 		m.summary = &s
 		return m, nil
 
@@ -220,7 +506,7 @@ func (m ExecutionModel) Update(msg tea.Msg) (ExecutionModel, tea.Cmd) {
 			return m, nil // stop ticking
 		}
 		// Update elapsed for in-progress tasks
-		now := time.Now()
+		now := clock()
 		for i := range m.progress {
 			if m.progress[i].Status == state.TaskInProgress && m.progress[i].StartedAt != nil {
 				m.progress[i].Elapsed = now.Sub(*m.progress[i].StartedAt)
@@ -236,6 +522,47 @@ func (m ExecutionModel) Update(msg tea.Msg) (ExecutionModel, tea.Cmd) {
 }
 
 func (m ExecutionModel) handleKey(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
+	if m.skipTaskID != "" {
+		return m.handleSkipInput(msg)
+	}
+	if m.dirtyFiles != nil {
+		return m.handleDirtyWorktree(msg)
+	}
+	if m.markTaskID != "" {
+		taskID := m.markTaskID
+		m.markTaskID = ""
+		if msg.String() == "y" {
+			return m, m.fetchHeadSHA(taskID)
+		}
+		return m, nil
+	}
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+	if m.searchOpen {
+		return m.handleSearchResults(msg)
+	}
+	if msg.String() == "tab" {
+		m.showRuns = !m.showRuns
+		if m.showRuns {
+			m.runsView.SetRuns(m.state.Runs)
+		}
+		return m, nil
+	}
+	if m.showRuns && msg.String() != "q" && msg.String() != "ctrl+p" {
+		var cmd tea.Cmd
+		m.runsView, cmd = m.runsView.Update(msg)
+		return m, cmd
+	}
+
+	// A replay only shows what happened; nothing can be changed
+	if m.replay {
+		switch msg.String() {
+		case "n", "s", "m", "y", "x", "enter", "J", "K", "r", "ctrl+p":
+			return m, nil
+		}
+	}
+
 	switch msg.String() {
 	case "j", "down":
 		if m.cursor < len(m.progress)-1 {
@@ -251,6 +578,67 @@ func (m ExecutionModel) handleKey(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
 			m.logStream.SetLines(toComponentLogLines(m.progress[m.cursor].LogLines))
 		}
 
+	case "n":
+		if m.status == ExecRunning {
+			return m.startNewTask()
+		}
+
+	case "s":
+		if m.status == ExecRunning && m.cursor >= 0 && m.cursor < len(m.progress) {
+			tp := m.progress[m.cursor]
+			if tp.Status != state.TaskPending {
+				return m, m.flash("Only pending tasks can be skipped", true)
+			}
+			m.skipTaskID = tp.TaskID
+			m.skipInput = textinput.New()
+			m.skipInput.Placeholder = "no longer needed"
+			m.skipInput.CharLimit = 256
+			m.skipInput.Focus()
+			return m, textinput.Blink
+		}
+
+	case "enter":
+		if m.scheduledAt != nil {
+			select {
+			case m.startNow <- struct{}{}:
+			default:
+			}
+		}
+
+	case "y", "x":
+		if m.manualTaskID != "" {
+			answer := executor.ManualConfirmation{TaskID: m.manualTaskID, Done: msg.String() == "y"}
+			select {
+			case m.confirm <- answer:
+				m.manualTaskID = ""
+			default:
+			}
+		}
+
+	case "m":
+		if m.cursor >= 0 && m.cursor < len(m.progress) {
+			tp := m.progress[m.cursor]
+			if tp.Status != state.TaskFailed && !(tp.Status == state.TaskPending && tp.Human) {
+				return m, m.flash("Only failed or human-owned tasks can be marked done", true)
+			}
+			m.markTaskID = tp.TaskID
+		}
+
+	case "K":
+		return m.reorder(-1)
+
+	case "J":
+		return m.reorder(+1)
+
+	case "/":
+		m.searching = true
+		m.searchInput = textinput.New()
+		m.searchInput.Placeholder = "error text"
+		m.searchInput.SetValue(m.searchQuery)
+		m.searchInput.CharLimit = 256
+		m.searchInput.Focus()
+		return m, textinput.Blink
+
 	case "f": // follow running task again
 		m.userMoved = false
 		for i, tp := range m.progress {
@@ -267,8 +655,7 @@ func (m ExecutionModel) handleKey(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
 			taskID := m.progress[m.cursor].TaskID
 			logPath := filepath.Join(m.stateRoot, ".forge", "logs", taskID+".log")
 			if _, err := os.Stat(logPath); err == nil {
-				editor := getEditor()
-				c := exec.Command(editor, logPath)
+				c := platform.EditorCommand(logPath)
 				return m, tea.ExecProcess(c, func(err error) tea.Msg {
 					return nil
 				})
@@ -307,12 +694,368 @@ func (m ExecutionModel) handleKey(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
 	return m, nil
 }
 
+// handleDirtyWorktree answers the uncommitted-changes dialog: s stashes
+// them and starts the run again.
+func (m ExecutionModel) handleDirtyWorktree(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		m.dirtyFiles = nil
+		m.stashDirty = true
+		m.started = false
+		m.status = ExecRunning
+		cmd := m.StartExecution()
+		return m, tea.Batch(cmd, tickCmd())
+	case "ctrl+p":
+		return m, func() tea.Msg {
+			return TransitionMsg{To: state.PhaseInputs}
+		}
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// reorder moves the selected pending task and queues the same change for
+// the runner, which picks it up before choosing its next task.
+func (m ExecutionModel) reorder(direction int) (ExecutionModel, tea.Cmd) {
+	if m.status != ExecRunning || m.cursor < 0 || m.cursor >= len(m.progress) {
+		return m, nil
+	}
+	taskID := m.progress[m.cursor].TaskID
+
+	progress, err := ReorderProgress(m.progress, taskID, direction)
+	if err != nil {
+		return m, m.flash(err.Error(), true)
+	}
+
+	dir := "down"
+	if direction < 0 {
+		dir = "up"
+	}
+	edit := executor.PlanEdit{
+		Description: fmt.Sprintf("moved %s %s", taskID, dir),
+		Apply: func(s *state.State) error {
+			tasks, err := ReorderTask(s.Tasks, taskID, direction)
+			if err != nil {
+				return err
+			}
+			s.Tasks = tasks
+			return nil
+		},
+	}
+	select {
+	case m.edits <- edit:
+	default:
+		return m, m.flash("Too many pending changes — wait for the current task to finish", true)
+	}
+
+	m.progress = progress
+	m.userMoved = true
+	for i, tp := range m.progress {
+		if tp.TaskID == taskID {
+			m.cursor = i
+			break
+		}
+	}
+	return m, nil
+}
+
+// handleSkipInput reads the skip reason; enter queues the skip, esc aborts.
+func (m ExecutionModel) handleSkipInput(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.skipTaskID = ""
+		return m, nil
+
+	case "enter":
+		taskID := m.skipTaskID
+		reason := strings.TrimSpace(m.skipInput.Value())
+		if reason == "" {
+			reason = m.skipInput.Placeholder
+		}
+		m.skipTaskID = ""
+
+		edit := executor.PlanEdit{
+			Description: fmt.Sprintf("skipped %s: %s", taskID, reason),
+			TaskID:      taskID,
+			Apply: func(s *state.State) error {
+				_, err := s.SkipTask(taskID, reason)
+				return err
+			},
+		}
+		select {
+		case m.edits <- edit:
+		default:
+			return m, m.flash("Too many pending changes — wait for the current task to finish", true)
+		}
+		return m, m.flash(fmt.Sprintf("Skip of %s queued", taskID), false)
+	}
+
+	var cmd tea.Cmd
+	m.skipInput, cmd = m.skipInput.Update(msg)
+	return m, cmd
+}
+
+// handleSearchInput reads the search query; enter lists the matches, esc
+// aborts.
+func (m ExecutionModel) handleSearchInput(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		return m, nil
+
+	case "enter":
+		m.searching = false
+		m.searchQuery = strings.TrimSpace(m.searchInput.Value())
+		if m.searchQuery == "" {
+			return m, nil
+		}
+		m.searchGroups = SearchLogs(m.progress, m.searchQuery)
+		m.searchCursor = 0
+		m.searchOpen = true
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+// handleSearchResults moves through the search results; enter jumps to
+// the selected line in its task's log.
+func (m ExecutionModel) handleSearchResults(msg tea.KeyMsg) (ExecutionModel, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.searchCursor < CountLogMatches(m.searchGroups)-1 {
+			m.searchCursor++
+		}
+	case "k", "up":
+		if m.searchCursor > 0 {
+			m.searchCursor--
+		}
+	case "/":
+		m.searchOpen = false
+		return m.handleKey(msg)
+	case "esc", "q":
+		m.searchOpen = false
+	case "enter":
+		m.searchOpen = false
+		group, match, ok := LogMatchAt(m.searchGroups, m.searchCursor)
+		if !ok {
+			return m, nil
+		}
+		for i, tp := range m.progress {
+			if tp.TaskID == group.TaskID {
+				m.cursor = i
+				m.userMoved = true
+				m.logStream.SetLines(toComponentLogLines(tp.LogLines))
+				m.logStream.ScrollTo(match.Line)
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// fetchHeadSHA reads the commit the hand-made fix lives at.
+func (m ExecutionModel) fetchHeadSHA(taskID string) tea.Cmd {
+	git := executor.NewRealGitOps(m.stateRoot)
+	return func() tea.Msg {
+		sha, err := git.LatestSHA(context.Background())
+		return markDoneMsg{taskID: taskID, sha: sha, err: err}
+	}
+}
+
+// handleMarkDone marks a failed task done at HEAD. While the runner is
+// active the change goes through it; afterwards it is applied directly.
+func (m ExecutionModel) handleMarkDone(msg markDoneMsg) (ExecutionModel, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.flash(fmt.Sprintf("Cannot read HEAD: %v", msg.err), true)
+	}
+
+	if m.status == ExecRunning {
+		edit := executor.PlanEdit{
+			Description: fmt.Sprintf("marked %s done at %s", msg.taskID, msg.sha),
+			TaskID:      msg.taskID,
+			Apply: func(s *state.State) error {
+				_, err := s.MarkTaskDone(msg.taskID, msg.sha, time.Now())
+				return err
+			},
+		}
+		select {
+		case m.edits <- edit:
+		default:
+			return m, m.flash("Too many pending changes — wait for the current task to finish", true)
+		}
+		return m, m.flash(fmt.Sprintf("Marking %s done", msg.taskID), false)
+	}
+
+	unblocked, err := m.state.MarkTaskDone(msg.taskID, msg.sha, time.Now())
+	if err != nil {
+		return m, m.flash(err.Error(), true)
+	}
+	_ = state.Save(m.stateRoot, m.state)
+
+	ApplyEventToProgress(m.progress, executor.TaskEvent{TaskID: msg.taskID, Type: executor.EventTaskDone})
+	for _, id := range unblocked {
+		ApplyEventToProgress(m.progress, executor.TaskEvent{TaskID: id, Type: executor.EventTaskReset, Message: "dependencies satisfied"})
+	}
+	// No runner is active, so unblocked tasks wait for the next run
+	if status := ComputeExecutionStatus(m.state.Tasks); status != ExecRunning {
+		m.status = status
+	}
+	if m.summary != nil {
+		s := ComputeExecutionSummary(m.progress)
+		s.CostUSD = m.summary.CostUSD
+		s.Verification = m.summary.Verification
+		s.PauseReason = m.summary.PauseReason
+		m.summary = &s
+	}
+
+	done := 0
+	for _, tp := range m.progress {
+		if tp.Status == state.TaskDone {
+			done++
+		}
+	}
+	m.progressBar.SetDone(done)
+
+	return m, m.flash(fmt.Sprintf("%s marked done; %d task(s) unblocked", msg.taskID, len(unblocked)), false)
+}
+
+// startNewTask opens the review phase's new-task template in $EDITOR.
+func (m ExecutionModel) startNewTask() (ExecutionModel, tea.Cmd) {
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, "forge-new-task-*.txt", "new task", []byte(formatNewTemplate()))
+	if err != nil {
+		return m, m.flash(fmt.Sprintf("Failed to create temp file: %v", err), true)
+	}
+
+	c := platform.EditorCommand(tmpPath)
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return execNewTaskFinishedMsg{err: err, tmpPath: tmpPath}
+	})
+}
+
+// handleNewTaskFinished queues the edited task for the runner and shows it
+// as pending straight away.
+func (m ExecutionModel) handleNewTaskFinished(msg execNewTaskFinishedMsg) (ExecutionModel, tea.Cmd) {
+	defer janitor.Release(m.stateRoot, msg.tmpPath)
+
+	if msg.err != nil {
+		return m, m.flash(fmt.Sprintf("Editor error: %v", msg.err), true)
+	}
+	data, err := os.ReadFile(msg.tmpPath)
+	if err != nil {
+		return m, m.flash(fmt.Sprintf("Failed to read temp file: %v", err), true)
+	}
+
+	task, err := NewExecutionTask(m.taskIDs, parseEditTemplate(string(data)), m.state.PlanVersion)
+	if err != nil {
+		return m, m.flash(fmt.Sprintf("Invalid task: %v", err), true)
+	}
+
+	edit := executor.PlanEdit{
+		Description: fmt.Sprintf("added %s: %s", task.ID, task.Title),
+		TaskID:      task.ID,
+		Apply: func(s *state.State) error {
+			return AppendPendingTask(s, task)
+		},
+	}
+	select {
+	case m.edits <- edit:
+	default:
+		return m, m.flash("Too many pending changes — wait for the current task to finish", true)
+	}
+
+	m.taskIDs = append(m.taskIDs, task.ID)
+	m.added[task.ID] = true
+	m.progress = append(m.progress, BuildTaskProgressList([]state.Task{task}, m.state.Settings)...)
+	m.progressBar.SetTotal(len(m.progress))
+	return m, m.flash(fmt.Sprintf("Queued %s", task.ID), false)
+}
+
+// reloadProgress rebuilds the task rows after the runner re-read state.json.
+func (m *ExecutionModel) reloadProgress() {
+	m.progress = ReloadProgress(m.progress, m.state.Tasks, m.state.Settings)
+	// Add new IDs, keeping those of tasks still queued for the runner
+	for _, t := range m.state.Tasks {
+		if !slices.Contains(m.taskIDs, t.ID) {
+			m.taskIDs = append(m.taskIDs, t.ID)
+		}
+	}
+	done := 0
+	for _, tp := range m.progress {
+		if tp.Status == state.TaskDone {
+			done++
+		}
+	}
+	m.progressBar.SetTotal(len(m.progress))
+	m.progressBar.SetDone(done)
+	if m.cursor >= len(m.progress) {
+		m.cursor = len(m.progress) - 1
+	}
+}
+
+// removeProgress drops a task row, e.g. when the runner rejected its addition.
+func (m *ExecutionModel) removeProgress(taskID string) {
+	for i, tp := range m.progress {
+		if tp.TaskID == taskID {
+			m.progress = append(m.progress[:i], m.progress[i+1:]...)
+			break
+		}
+	}
+	if m.cursor >= len(m.progress) {
+		m.cursor = len(m.progress) - 1
+	}
+	m.progressBar.SetTotal(len(m.progress))
+}
+
+// flash shows a transient message in the footer.
+func (m *ExecutionModel) flash(msg string, isErr bool) tea.Cmd {
+	m.flashMsg = msg
+	m.flashErr = isErr
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return clearFlashMsg{}
+	})
+}
+
+// alert rings the bell or plays a sound, per Settings.Alert, when an event
+// leaves the run waiting on the user. Replays stay silent.
+func (m ExecutionModel) alert(e executor.TaskEvent) tea.Cmd {
+	if m.replay || m.state.Settings == nil || !NeedsAttention(e.Type) {
+		return nil
+	}
+	switch m.state.Settings.Alert {
+	case "bell":
+		return ringBell
+	case "sound":
+		return func() tea.Msg {
+			if cmd := platform.SoundCommand(); cmd == nil || cmd.Run() != nil {
+				return ringBell()
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// ringBell writes BEL to the terminal, which most terminals turn into a
+// beep, a flash, or an urgency hint on the window.
+func ringBell() tea.Msg {
+	fmt.Fprint(os.Stdout, "\a")
+	return nil
+}
+
 // View renders the execution dashboard.
 func (m ExecutionModel) View() string {
 	if m.width == 0 || m.height == 0 {
 		return ""
 	}
 
+	if m.accessible {
+		return m.viewAccessible()
+	}
+
 	var sections []string
 
 	// Header line
@@ -321,6 +1064,17 @@ func (m ExecutionModel) View() string {
 	// Separator
 	sections = append(sections, m.renderSeparator())
 
+	if m.showRuns {
+		// Header + 2 separators + progress bar + footer
+		m.runsView.SetSize(m.width, max(m.height-5, 3))
+		sections = append(sections, m.runsView.View())
+		sections = append(sections, m.renderSeparator())
+		m.progressBar.SetWidth(m.width - 4)
+		sections = append(sections, m.progressBar.View())
+		sections = append(sections, HelpStyle.Render(m.runsView.HelpText()))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
 	// Task list
 	taskListHeight := m.taskListHeight()
 	sections = append(sections, m.renderTaskList(taskListHeight))
@@ -328,7 +1082,11 @@ func (m ExecutionModel) View() string {
 	// Separator
 	sections = append(sections, m.renderSeparator())
 
-	if m.summary != nil {
+	if m.dirtyFiles != nil {
+		sections = append(sections, m.renderDirtyWorktree())
+	} else if m.searchOpen {
+		sections = append(sections, m.renderSearchResults(m.logStreamHeight()+1))
+	} else if m.summary != nil {
 		// Show summary when done
 		sections = append(sections, m.renderSummary())
 	} else {
@@ -352,6 +1110,28 @@ func (m ExecutionModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// viewAccessible renders the dashboard as plain sentences: the run and
+// selected task, whatever needs an answer, and the keys. The log itself
+// is printed above as it happens, so nothing here redraws it.
+func (m ExecutionModel) viewAccessible() string {
+	sections := []string{FormatAccessibleStatus(m.progress, m.status, m.cursor)}
+	switch {
+	case m.showRuns:
+		m.runsView.SetSize(m.width, max(m.height-4, 3))
+		sections = append(sections, m.runsView.View(), m.runsView.HelpText())
+		return strings.Join(sections, "\n\n")
+	case m.dirtyFiles != nil:
+		sections = append(sections, FormatDirtyWorktree(m.dirtyFiles, 10))
+	case m.searchOpen:
+		lines, _ := FormatLogSearch(m.searchQuery, m.searchGroups, m.searchCursor, 0)
+		sections = append(sections, strings.Join(lines, "\n"))
+	case m.summary != nil:
+		sections = append(sections, FormatSummaryText(*m.summary))
+	}
+	sections = append(sections, strings.TrimSpace(m.renderFooter()))
+	return strings.Join(sections, "\n\n")
+}
+
 // SetSize updates the component dimensions.
 func (m *ExecutionModel) SetSize(w, h int) {
 	m.width = w
@@ -391,6 +1171,9 @@ func (m ExecutionModel) renderExecHeader() string {
 	right := lipgloss.NewStyle().
 		Foreground(Text).
 		Render(fmt.Sprintf("Plan v%d · %d/%d tasks done", m.state.PlanVersion, done, total))
+	if repos := FormatRepoProgress(m.progress); repos != "" {
+		right = lipgloss.NewStyle().Foreground(Muted).Render(repos+" · ") + right
+	}
 
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(right) - 2
 	if gap < 1 {
@@ -403,7 +1186,7 @@ func (m ExecutionModel) renderExecHeader() string {
 func (m ExecutionModel) renderSeparator() string {
 	return lipgloss.NewStyle().
 		Foreground(Muted).
-		Render("  " + strings.Repeat("─", m.width-4))
+		Render("  " + strings.Repeat(components.Glyph("─", "-"), m.width-4))
 }
 
 func (m ExecutionModel) renderTaskList(height int) string {
@@ -424,7 +1207,7 @@ func (m ExecutionModel) renderTaskList(height int) string {
 
 	for i := start; i < end; i++ {
 		selected := i == m.cursor
-		line := FormatTaskStatusLine(m.progress[i], selected, m.width-2)
+		line := FormatTaskStatusLine(m.progress[i], selected, m.width-2, StatusStyleOf(m.state.Settings))
 		lines = append(lines, line)
 	}
 
@@ -453,6 +1236,12 @@ func (m ExecutionModel) renderTaskDetailHeader() string {
 			Foreground(Warning).
 			Render(fmt.Sprintf("  Attempt %d/%d", tp.Attempt, tp.MaxAttempts))
 	}
+	if tp.Status == state.TaskFailed && tp.RootCause != "" {
+		extra = lipgloss.NewStyle().
+			Foreground(Danger).
+			MaxWidth(max(m.width-lipgloss.Width(title)-2, 0)).
+			Render("  Root cause: " + tp.RootCause)
+	}
 
 	if extra != "" {
 		return title + extra
@@ -466,6 +1255,9 @@ func (m ExecutionModel) renderSummary() string {
 	}
 
 	text := FormatSummaryText(*m.summary)
+	if len(m.previousRuns) > 0 {
+		text += "\n\nPrevious runs:\n" + FormatRunHistory(m.previousRuns, 3)
+	}
 	lines := strings.Split(text, "\n")
 	var styled []string
 	for _, line := range lines {
@@ -476,16 +1268,92 @@ func (m ExecutionModel) renderSummary() string {
 		Render(strings.Join(styled, "\n"))
 }
 
+// renderSearchResults shows the transcript search results in the log
+// area, scrolled to keep the selected match in view.
+func (m ExecutionModel) renderSearchResults(height int) string {
+	lines, at := FormatLogSearch(m.searchQuery, m.searchGroups, m.searchCursor, m.width-4)
+	start := 0
+	if at >= height {
+		start = at - height + 1
+	}
+	lines = lines[start:min(start+height, len(lines))]
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	var styled []string
+	for _, line := range lines {
+		styled = append(styled, "  "+line)
+	}
+	return lipgloss.NewStyle().
+		Foreground(Text).
+		Render(strings.Join(styled, "\n"))
+}
+
+func (m ExecutionModel) renderDirtyWorktree() string {
+	var styled []string
+	for _, line := range strings.Split(FormatDirtyWorktree(m.dirtyFiles, 10), "\n") {
+		styled = append(styled, "  "+line)
+	}
+	return lipgloss.NewStyle().
+		Foreground(Text).
+		Render(strings.Join(styled, "\n"))
+}
+
 func (m ExecutionModel) renderFooter() string {
 	var help string
-	if m.status == ExecRunning {
-		help = "  j/k navigate · f follow · l logs · q cancel"
+	if m.replay {
+		help = "  replay · j/k navigate · f follow · l logs · / search · tab runs · q stop/quit"
+	} else if m.status == ExecRunning {
+		help = "  j/k navigate · J/K reorder · n new task · s skip · m mark done · f follow · l logs · / search · tab runs · q cancel"
 	} else if m.status == ExecComplete {
-		help = "  j/k navigate · l logs · r replan · ctrl+p back · q quit"
+		help = "  j/k navigate · l logs · / search · tab runs · r replan · ctrl+p back · q quit"
+	} else if m.status == ExecPaused {
+		help = "  j/k navigate · l logs · / search · tab runs · ctrl+p back to raise the budget · q quit"
 	} else if m.status == ExecStopped {
-		help = "  j/k navigate · l logs · enter retry · r replan · ctrl+p back · q quit"
+		help = "  j/k navigate · l logs · / search · m mark done · tab runs · enter retry · r replan · ctrl+p back · q quit"
 	} else {
-		help = "  j/k navigate · l logs · r replan · ctrl+p back · q quit"
+		help = "  j/k navigate · l logs · / search · tab runs · r replan · ctrl+p back · q quit"
+	}
+
+	if m.dirtyFiles != nil {
+		return lipgloss.NewStyle().Foreground(Warning).Render(
+			"  Uncommitted changes — s stash them and start · ctrl+p back · q quit")
+	}
+
+	if m.markTaskID != "" {
+		return fmt.Sprintf("  Mark %s done at current HEAD? Dependents will be unblocked. %s",
+			m.markTaskID, HelpStyle.Render("y confirm · any other key cancels"))
+	}
+
+	if m.searching {
+		return fmt.Sprintf("  Search logs: %s  %s", m.searchInput.View(), HelpStyle.Render("enter search · esc cancel"))
+	}
+
+	if m.searchOpen {
+		return HelpStyle.Render("  j/k select · enter jump to line · / new search · esc close")
+	}
+
+	if m.skipTaskID != "" {
+		return fmt.Sprintf("  Skip %s (and its dependents)? Reason: %s  %s",
+			m.skipTaskID, m.skipInput.View(), HelpStyle.Render("enter confirm · esc cancel"))
+	}
+
+	if m.scheduledAt != nil && m.status == ExecRunning {
+		return lipgloss.NewStyle().Foreground(Warning).Render(
+			"  " + FormatScheduledStart(*m.scheduledAt, clock()) + " — enter start now · q cancel")
+	}
+
+	if m.manualTaskID != "" {
+		return lipgloss.NewStyle().Foreground(Warning).Render(
+			fmt.Sprintf("  Manual step %s is waiting for you — y done · x failed", m.manualTaskID))
+	}
+
+	if m.flashMsg != "" {
+		style := lipgloss.NewStyle().Foreground(Success)
+		if m.flashErr {
+			style = lipgloss.NewStyle().Foreground(Danger)
+		}
+		return style.Render("  " + m.flashMsg)
 	}
 
 	return HelpStyle.Render(help)
@@ -1,12 +1,15 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/manasm11/forge/internal/executor"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // ExecutionStatus represents the overall execution state.
@@ -33,6 +36,11 @@ type TaskProgress struct {
 	MaxAttempts int
 	LogLines    []LogLine // streaming log entries
 	RetryCount  int       // total retries used
+	Human       bool      // owned by a person; the runner never starts it
+	Assignee    string
+	Repo        string // workspace repository; "" for the project root
+	RootCause   string // diagnosis of a task that exhausted its retries
+	DependsOn   []string
 }
 
 // LogLine is a single line in the task's live log.
@@ -62,6 +70,9 @@ type ExecutionSummary struct {
 	TotalRetries  int
 	TotalDuration time.Duration
 	Branches      []string
+	CostUSD       float64             // reported agent cost for this run (0 if unknown)
+	Verification  *state.Verification // end-of-run check on the base branch (nil if not run)
+	PauseReason   string              // why the runner paused early (empty if it did not)
 }
 
 const maxLogLines = 100
@@ -86,6 +97,13 @@ func BuildTaskProgressList(tasks []state.Task, settings *state.Settings) []TaskP
 			Status:      t.Status,
 			MaxAttempts: 1 + maxRetries,
 			RetryCount:  t.Retries,
+			Human:       t.ForHuman(),
+			Assignee:    t.Assignee,
+			Repo:        t.Repo,
+			DependsOn:   t.DependsOn,
+		}
+		if t.RootCause != nil {
+			tp.RootCause = t.RootCause.Summary
 		}
 		if t.Status == state.TaskDone && t.CompletedAt != nil {
 			fin := *t.CompletedAt
@@ -96,6 +114,30 @@ func BuildTaskProgressList(tasks []state.Task, settings *state.Settings) []TaskP
 	return result
 }
 
+// ReloadProgress rebuilds the dashboard rows from tasks after the runner
+// re-read state.json, keeping the live fields (timings, attempts, logs) of
+// rows that still exist. Cancelled tasks drop out and new ones are added.
+func ReloadProgress(progress []TaskProgress, tasks []state.Task, settings *state.Settings) []TaskProgress {
+	old := make(map[string]TaskProgress, len(progress))
+	for _, tp := range progress {
+		old[tp.TaskID] = tp
+	}
+	rebuilt := BuildTaskProgressList(tasks, settings)
+	for i, tp := range rebuilt {
+		if prev, ok := old[tp.TaskID]; ok {
+			prev.Title = tp.Title
+			prev.Complexity = tp.Complexity
+			prev.Status = tp.Status
+			prev.Human = tp.Human
+			prev.Assignee = tp.Assignee
+			prev.Repo = tp.Repo
+			prev.DependsOn = tp.DependsOn
+			rebuilt[i] = prev
+		}
+	}
+	return rebuilt
+}
+
 // ComputeExecutionStatus determines overall status from task states.
 func ComputeExecutionStatus(tasks []state.Task) ExecutionStatus {
 	hasPending := false
@@ -103,11 +145,16 @@ func ComputeExecutionStatus(tasks []state.Task) ExecutionStatus {
 	hasFailed := false
 	hasSkipped := false
 	hasDone := false
+	hasHuman := false
 
 	for _, t := range tasks {
 		switch t.Status {
 		case state.TaskPending:
-			hasPending = true
+			if t.ForHuman() {
+				hasHuman = true // waits for a person, not the runner
+			} else {
+				hasPending = true
+			}
 		case state.TaskInProgress:
 			hasInProgress = true
 		case state.TaskFailed:
@@ -124,7 +171,7 @@ func ComputeExecutionStatus(tasks []state.Task) ExecutionStatus {
 	if hasInProgress || hasPending {
 		return ExecRunning
 	}
-	if hasFailed || hasSkipped {
+	if hasFailed || hasSkipped || hasHuman {
 		if !hasDone && !hasFailed && !hasSkipped {
 			return ExecComplete
 		}
@@ -177,12 +224,12 @@ func ComputeExecutionSummary(progress []TaskProgress) ExecutionSummary {
 // FormatProgressBar produces a text progress bar: ████████░░░░░░ 3/7 (43%)
 func FormatProgressBar(done, total, width int) string {
 	if total == 0 {
-		return "░ 0/0 (0%)"
+		return components.Glyph("░", "-") + " 0/0 (0%)"
 	}
 	pct := done * 100 / total
 	filled := done * width / total
 	empty := width - filled
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", empty)
+	bar := strings.Repeat(components.Glyph("█", "#"), filled) + strings.Repeat(components.Glyph("░", "-"), empty)
 	return fmt.Sprintf("%s %d/%d (%d%%)", bar, done, total, pct)
 }
 
@@ -211,16 +258,84 @@ func FormatSummaryText(summary ExecutionSummary) string {
 		fmt.Fprintf(&b, "\n%d retries across all tasks", summary.TotalRetries)
 	}
 
+	if summary.CostUSD > 0 {
+		fmt.Fprintf(&b, "\nCost: $%.2f", summary.CostUSD)
+	}
+	if summary.PauseReason != "" {
+		fmt.Fprintf(&b, "\nPaused: %s", summary.PauseReason)
+	}
+
 	if len(summary.Branches) > 0 {
 		fmt.Fprintf(&b, "\nBranches: %s", strings.Join(summary.Branches, ", "))
 	}
 
+	if summary.Verification != nil {
+		b.WriteString("\n" + FormatVerificationRow(*summary.Verification))
+	}
+
 	return b.String()
 }
 
+// FormatVerificationRow renders the end-of-run verification as a synthetic
+// task row: "✓ verification  build ✓ · test ✗ · lint ✓".
+func FormatVerificationRow(v state.Verification) string {
+	icon := components.Glyph("✓", "+")
+	if !v.Passed {
+		icon = components.Glyph("✗", "x")
+	}
+	steps := make([]string, len(v.Steps))
+	for i, step := range v.Steps {
+		mark := components.Glyph("✓", "+")
+		if !step.Passed {
+			mark = components.Glyph("✗", "x")
+		}
+		steps[i] = step.Name + " " + mark
+	}
+	return fmt.Sprintf("%s verification  %s", icon, strings.Join(steps, " · "))
+}
+
+// FormatRunLine renders a one-line summary of a persisted run:
+// "Run #2 · completed · 3 done, 1 failed · 4:12 · $0.42".
+func FormatRunLine(run state.RunSummary) string {
+	parts := []string{fmt.Sprintf("Run #%d", run.ID), string(run.Status)}
+
+	counts := []string{fmt.Sprintf("%d done", run.Completed)}
+	if run.Failed > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", run.Failed))
+	}
+	if run.Skipped > 0 {
+		counts = append(counts, fmt.Sprintf("%d skipped", run.Skipped))
+	}
+	parts = append(parts, strings.Join(counts, ", "))
+
+	if run.FinishedAt != nil {
+		parts = append(parts, FormatElapsed(run.Duration()))
+	}
+	if run.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", run.CostUSD))
+	}
+	if cp := run.Checkpoint; cp != nil {
+		next := "next: " + cp.NextTask
+		if cp.ResumeAt != nil {
+			next += " after " + cp.ResumeAt.Format("15:04")
+		}
+		parts = append(parts, next)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatRunHistory renders the most recent runs (newest first), at most limit.
+func FormatRunHistory(runs []state.RunSummary, limit int) string {
+	var lines []string
+	for i := len(runs) - 1; i >= 0 && len(lines) < limit; i-- {
+		lines = append(lines, FormatRunLine(runs[i]))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // EventToLogLine converts an executor.TaskEvent into a displayable LogLine.
 func EventToLogLine(event executor.TaskEvent) *LogLine {
-	ts := time.Now()
+	ts := clock()
 	if event.Timestamp > 0 {
 		ts = time.UnixMilli(event.Timestamp)
 	}
@@ -280,6 +395,37 @@ func EventToLogLine(event executor.TaskEvent) *LogLine {
 			text += ": " + event.Message
 		}
 		return &LogLine{Text: text, Type: LogError, Timestamp: ts}
+	case executor.EventPRCreated:
+		return &LogLine{Text: "Opened PR: " + event.Message, Type: LogSuccess, Timestamp: ts}
+	case executor.EventWorktreeStashed:
+		return &LogLine{Text: event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventBaseDrift:
+		return &LogLine{Text: event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventLesson:
+		return &LogLine{Text: "Learned: " + event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventRootCause:
+		return &LogLine{Text: strings.TrimSpace(event.Detail), Type: LogWarning, Timestamp: ts}
+	case executor.EventPRReady:
+		return &LogLine{Text: "PR ready for review: " + event.Message, Type: LogSuccess, Timestamp: ts}
+	case executor.EventIssueFiled:
+		return &LogLine{Text: "Issue filed: " + event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventReview:
+		if event.Message != "approved" {
+			return &LogLine{Text: "Review: " + event.Message + " — retrying", Type: LogWarning, Timestamp: ts}
+		}
+		return &LogLine{Text: "Review: approved", Type: LogSuccess, Timestamp: ts}
+	case executor.EventChecklist, executor.EventLFSWarning:
+		return &LogLine{Text: event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventHookFailed:
+		return &LogLine{Text: "Git hooks rejected the commit — retrying", Type: LogWarning, Timestamp: ts}
+	case executor.EventCIStart:
+		return &LogLine{Text: "Waiting for CI on " + event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventCIPassed:
+		return &LogLine{Text: "CI passed", Type: LogSuccess, Timestamp: ts}
+	case executor.EventCIFailed:
+		return &LogLine{Text: event.Message + " — retrying", Type: LogWarning, Timestamp: ts}
+	case executor.EventChangelog:
+		return &LogLine{Text: "Changelog written: " + event.Message, Type: LogSuccess, Timestamp: ts}
 	case executor.EventTaskSkipped:
 		text := "Task skipped"
 		if event.Message != "" {
@@ -288,6 +434,33 @@ func EventToLogLine(event executor.TaskEvent) *LogLine {
 		return &LogLine{Text: text, Type: LogWarning, Timestamp: ts}
 	case executor.EventError:
 		return &LogLine{Text: "Error: " + event.Message, Type: LogError, Timestamp: ts}
+	case executor.EventEditRejected:
+		return &LogLine{Text: "Edit rejected: " + event.Message, Type: LogError, Timestamp: ts}
+	case executor.EventPlanChanged:
+		return &LogLine{Text: "Plan updated: " + event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventStateReloaded:
+		return &LogLine{Text: "State reloaded: " + event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventTaskReset:
+		return &LogLine{Text: "Task unblocked: " + event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventManualWait:
+		return &LogLine{Text: "Manual step — do this, then press y (or x if it failed):\n" + event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventArtifacts:
+		if event.Detail != "" {
+			return &LogLine{Text: event.Message + " (" + event.Detail + ")", Type: LogWarning, Timestamp: ts}
+		}
+		return &LogLine{Text: event.Message, Type: LogInfo, Timestamp: ts}
+	case executor.EventPolicyViolation:
+		return &LogLine{Text: event.Message + " — retrying", Type: LogWarning, Timestamp: ts}
+	case executor.EventRunPaused:
+		return &LogLine{Text: "Paused: " + event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventBudgetExhausted:
+		return &LogLine{Text: "Budget exhausted: " + event.Message, Type: LogWarning, Timestamp: ts}
+	case executor.EventVerifyStart:
+		return &LogLine{Text: "Verifying " + event.Message + "...", Type: LogInfo, Timestamp: ts}
+	case executor.EventVerifyPassed:
+		return &LogLine{Text: "Verification " + event.Message + " passed", Type: LogSuccess, Timestamp: ts}
+	case executor.EventVerifyFailed:
+		return &LogLine{Text: "Verification " + event.Message + " failed", Type: LogError, Timestamp: ts}
 	case executor.EventTaskStart:
 		return &LogLine{Text: "Starting task: " + event.Message, Type: LogInfo, Timestamp: ts}
 	default:
@@ -295,6 +468,133 @@ func EventToLogLine(event executor.TaskEvent) *LogLine {
 	}
 }
 
+// ReorderProgress moves a pending task up (-1) or down (+1) past the
+// nearest pending task, mirroring ReorderTask in the review phase and the
+// runner: a task can't move ahead of one it depends on.
+// Returns a new slice; the input is not mutated.
+func ReorderProgress(progress []TaskProgress, taskID string, direction int) ([]TaskProgress, error) {
+	idx := -1
+	for i, tp := range progress {
+		if tp.TaskID == taskID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("task %q not found", taskID)
+	}
+	if progress[idx].Status != state.TaskPending {
+		return nil, fmt.Errorf("only pending tasks can be reordered")
+	}
+
+	swap := -1
+	for i := idx + direction; i >= 0 && i < len(progress); i += direction {
+		if progress[i].Status == state.TaskPending {
+			swap = i
+			break
+		}
+	}
+	if swap == -1 {
+		return nil, fmt.Errorf("cannot move task further in that direction")
+	}
+	earlier, later := progress[min(idx, swap)], progress[max(idx, swap)]
+	if err := checkReorderDeps(earlier.TaskID, later.TaskID, later.DependsOn); err != nil {
+		return nil, err
+	}
+
+	result := make([]TaskProgress, len(progress))
+	copy(result, progress)
+	result[idx], result[swap] = result[swap], result[idx]
+	return result, nil
+}
+
+// NewExecutionTask builds a task added from the execution dashboard. ids
+// must hold every task ID in the plan, including cancelled ones, so the new
+// ID cannot collide with a task the runner already knows about.
+func NewExecutionTask(ids []string, parsed parsedTemplate, planVersion int) (state.Task, error) {
+	known := make([]state.Task, len(ids))
+	for i, id := range ids {
+		known[i] = state.Task{ID: id}
+	}
+	if err := ValidateNewTask(known, parsed.title, parsed.description, parsed.complexity, parsed.criteria, parsed.dependsOn); err != nil {
+		return state.Task{}, err
+	}
+	if !state.ValidTaskType(parsed.taskType) {
+		return state.Task{}, fmt.Errorf("type must be code, verify or manual (got %q)", parsed.taskType)
+	}
+	if !state.ValidTaskOwner(parsed.owner) {
+		return state.Task{}, fmt.Errorf("owner must be agent or human (got %q)", parsed.owner)
+	}
+
+	return state.Task{
+		ID:                  (&state.State{Tasks: known}).NextTaskID(),
+		Title:               parsed.title,
+		Description:         parsed.description,
+		Complexity:          parsed.complexity,
+		Type:                parsed.taskType,
+		Commands:            parsed.commands,
+		Model:               parsed.model,
+		Owner:               parsed.owner,
+		Assignee:            parsed.assignee,
+		AcceptanceCriteria:  parsed.criteria,
+		DependsOn:           parsed.dependsOn,
+		Status:              state.TaskPending,
+		PlanVersionCreated:  planVersion,
+		PlanVersionModified: planVersion,
+	}, nil
+}
+
+// AppendPendingTask adds a task built by NewExecutionTask to the live plan.
+// It re-checks the ID and dependencies against the runner's state.
+func AppendPendingTask(s *state.State, task state.Task) error {
+	if s.FindTask(task.ID) != nil {
+		return fmt.Errorf("task %s already exists", task.ID)
+	}
+	if err := ValidateNewTask(s.Tasks, task.Title, task.Description, task.Complexity, task.AcceptanceCriteria, task.DependsOn); err != nil {
+		return err
+	}
+	s.Tasks = append(s.Tasks, task)
+	return nil
+}
+
+// FormatDirtyWorktree explains why a run refused to start on top of
+// uncommitted changes, listing up to limit of the files.
+func FormatDirtyWorktree(files []string, limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The working tree has %d uncommitted file(s). forge commits with `git add -A`,\n", len(files))
+	b.WriteString("so they would end up in the first task's commit:\n\n")
+	for i, f := range files {
+		if i == limit {
+			fmt.Fprintf(&b, "  … and %d more\n", len(files)-limit)
+			break
+		}
+		b.WriteString("  " + f + "\n")
+	}
+	b.WriteString("\nCommit them yourself, or stash them (git stash pop restores them after the run).")
+	return b.String()
+}
+
+// FormatScheduledStart describes a pending delayed start:
+// "Scheduled to start at 02:00 (in 3h 45m)". Starts on another day include
+// the weekday.
+func FormatScheduledStart(at, now time.Time) string {
+	when := at.Format("15:04")
+	if at.Format("2006-01-02") != now.Format("2006-01-02") {
+		when = at.Format("Mon 15:04")
+	}
+
+	left := at.Sub(now).Round(time.Minute)
+	if left < time.Minute {
+		return fmt.Sprintf("Scheduled to start at %s (any moment)", when)
+	}
+	h, m := int(left.Hours()), int(left.Minutes())%60
+	in := fmt.Sprintf("%dm", m)
+	if h > 0 {
+		in = fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("Scheduled to start at %s (in %s)", when, in)
+}
+
 // TasksRemaining returns the count of tasks not yet done/failed/skipped/cancelled.
 func TasksRemaining(tasks []state.Task) int {
 	count := 0
@@ -307,21 +607,20 @@ func TasksRemaining(tasks []state.Task) int {
 	return count
 }
 
-// FormatTaskStatusLine renders a single task line for the list.
-func FormatTaskStatusLine(tp TaskProgress, selected bool, width int) string {
-	var icon string
-	switch tp.Status {
-	case state.TaskDone:
-		icon = "✅"
-	case state.TaskInProgress:
-		icon = "🔄"
-	case state.TaskFailed:
-		icon = "❌"
-	case state.TaskSkipped:
-		icon = "⏭"
-	default:
-		icon = "  "
+// NeedsAttention reports whether an event leaves the run waiting on a
+// human: a manual task, the spending cap, or a pause for review.
+func NeedsAttention(t executor.TaskEventType) bool {
+	switch t {
+	case executor.EventManualWait, executor.EventBudgetExhausted, executor.EventRunPaused:
+		return true
 	}
+	return false
+}
+
+// FormatTaskStatusLine renders a single task line for the list, drawing
+// the status in the given style (Settings.StatusStyle).
+func FormatTaskStatusLine(tp TaskProgress, selected bool, width int, style components.StatusStyle) string {
+	icon := components.StatusIcon(style, components.TaskStatus(tp.Status), tp.Human)
 
 	prefix := "  "
 	if selected {
@@ -340,8 +639,71 @@ func FormatTaskStatusLine(tp TaskProgress, selected bool, width int) string {
 	if tp.Status == state.TaskSkipped {
 		suffix = " skipped"
 	}
+	if tp.Status == state.TaskPending && tp.Human {
+		suffix = " (human" + FormatAssignee(tp.Assignee) + ")"
+	}
+
+	title := tp.Title
+	if tp.Repo != "" {
+		title = tp.Repo + ": " + title
+	}
+
+	return fmt.Sprintf("%s%s %s %s %s%s", prefix, icon, tp.TaskID, complexity, title, suffix)
+}
+
+// FormatRepoProgress summarizes done/total tasks per repository for a
+// workspace plan, root project first ("root 2/3 · frontend 1/4"). It is ""
+// when every task is in the project root.
+func FormatRepoProgress(progress []TaskProgress) string {
+	type count struct{ done, total int }
+	counts := map[string]*count{}
+	var order []string
+	workspace := false
+	for _, tp := range progress {
+		if tp.Repo != "" {
+			workspace = true
+		}
+		c, ok := counts[tp.Repo]
+		if !ok {
+			c = &count{}
+			counts[tp.Repo] = c
+			order = append(order, tp.Repo)
+		}
+		c.total++
+		if tp.Status == state.TaskDone {
+			c.done++
+		}
+	}
+	if !workspace {
+		return ""
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i] == "" && order[j] != "" })
+	parts := make([]string, len(order))
+	for i, repo := range order {
+		name := repo
+		if name == "" {
+			name = "root"
+		}
+		parts[i] = fmt.Sprintf("%s %d/%d", name, counts[repo].done, counts[repo].total)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// StatusStyleOf returns the status style the settings ask for; nil
+// settings draw emoji.
+func StatusStyleOf(settings *state.Settings) components.StatusStyle {
+	if settings == nil {
+		return components.StatusEmoji
+	}
+	return cmp.Or(components.StatusStyle(settings.StatusStyle), components.StatusEmoji)
+}
 
-	return fmt.Sprintf("%s%s %s %s %s%s", prefix, icon, tp.TaskID, complexity, tp.Title, suffix)
+// FormatAssignee renders an assignee as " · @name", or "" when unassigned.
+func FormatAssignee(assignee string) string {
+	if assignee == "" {
+		return ""
+	}
+	return " · @" + strings.TrimPrefix(assignee, "@")
 }
 
 // FormatCompletionMessage returns the header message based on execution status.
@@ -362,6 +724,12 @@ func FormatCompletionMessage(status ExecutionStatus, summary ExecutionSummary) s
 		return msg
 	case ExecCancelled:
 		return fmt.Sprintf("Execution Cancelled — %s tasks completed", done)
+	case ExecPaused:
+		msg := fmt.Sprintf("Execution Paused — %s tasks done", done)
+		if summary.PauseReason != "" {
+			msg += ", " + summary.PauseReason
+		}
+		return msg
 	default:
 		return fmt.Sprintf("Executing — %s tasks done", done)
 	}
@@ -385,7 +753,7 @@ func ApplyEventToProgress(progress []TaskProgress, event executor.TaskEvent) {
 	switch event.Type {
 	case executor.EventTaskStart:
 		tp.Status = state.TaskInProgress
-		now := time.Now()
+		now := clock()
 		tp.StartedAt = &now
 		tp.Attempt = 1
 	case executor.EventRetry:
@@ -393,20 +761,28 @@ func ApplyEventToProgress(progress []TaskProgress, event executor.TaskEvent) {
 		tp.RetryCount++
 	case executor.EventTaskDone:
 		tp.Status = state.TaskDone
-		now := time.Now()
+		now := clock()
 		tp.FinishedAt = &now
 		if tp.StartedAt != nil {
 			tp.Elapsed = now.Sub(*tp.StartedAt)
 		}
 	case executor.EventTaskFailed:
 		tp.Status = state.TaskFailed
-		now := time.Now()
+		now := clock()
 		tp.FinishedAt = &now
 		if tp.StartedAt != nil {
 			tp.Elapsed = now.Sub(*tp.StartedAt)
 		}
 	case executor.EventTaskSkipped:
 		tp.Status = state.TaskSkipped
+	case executor.EventRootCause:
+		tp.RootCause = event.Message
+	case executor.EventTaskReset:
+		tp.Status = state.TaskPending
+		tp.RootCause = ""
+		tp.StartedAt = nil
+		tp.FinishedAt = nil
+		tp.Elapsed = 0
 	}
 
 	// Append log line
@@ -7,6 +7,7 @@ import (
 
 	"github.com/manasm11/forge/internal/executor"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // ============================================================
@@ -55,6 +56,30 @@ func TestBuildTaskProgressList(t *testing.T) {
 	}
 }
 
+func TestReloadProgress(t *testing.T) {
+	t.Parallel()
+	progress := []TaskProgress{
+		{TaskID: "task-001", Title: "Init", Status: state.TaskDone, Elapsed: 42 * time.Second, LogLines: []LogLine{{Text: "ok"}}},
+		{TaskID: "task-002", Title: "Auth", Status: state.TaskPending},
+	}
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Init", Status: state.TaskDone},
+		{ID: "task-002", Title: "Auth", Status: state.TaskCancelled},
+		{ID: "task-003", Title: "Docs", Status: state.TaskPending, Owner: state.OwnerHuman},
+	}
+
+	got := ReloadProgress(progress, tasks, &state.Settings{MaxRetries: 1})
+	if len(got) != 2 || got[0].TaskID != "task-001" || got[1].TaskID != "task-003" {
+		t.Fatalf("ReloadProgress() = %+v, want task-001 and task-003", got)
+	}
+	if got[0].Elapsed != 42*time.Second || len(got[0].LogLines) != 1 {
+		t.Errorf("task-001 lost its live fields: %+v", got[0])
+	}
+	if !got[1].Human || got[1].MaxAttempts != 2 {
+		t.Errorf("task-003 = %+v, want a new human row", got[1])
+	}
+}
+
 func TestBuildTaskProgressList_MaxAttempts(t *testing.T) {
 	t.Parallel()
 	tasks := []state.Task{
@@ -146,6 +171,14 @@ func TestComputeExecutionStatus(t *testing.T) {
 			},
 			want: ExecRunning,
 		},
+		{
+			name: "only human tasks left — stopped",
+			tasks: []state.Task{
+				{Status: state.TaskDone},
+				{Status: state.TaskPending, Owner: state.OwnerHuman},
+			},
+			want: ExecStopped,
+		},
 		{
 			name:  "empty tasks",
 			tasks: nil,
@@ -440,16 +473,204 @@ func TestTasksRemaining(t *testing.T) {
 	}
 }
 
+func TestNeedsAttention(t *testing.T) {
+	t.Parallel()
+	for _, typ := range []executor.TaskEventType{executor.EventManualWait, executor.EventBudgetExhausted, executor.EventRunPaused} {
+		if !NeedsAttention(typ) {
+			t.Errorf("NeedsAttention(%s) = false, want true", typ)
+		}
+	}
+	for _, typ := range []executor.TaskEventType{executor.EventTaskStart, executor.EventTaskFailed, executor.EventTestPassed} {
+		if NeedsAttention(typ) {
+			t.Errorf("NeedsAttention(%s) = true, want false", typ)
+		}
+	}
+}
+
+func TestReorderProgress(t *testing.T) {
+	t.Parallel()
+	progress := []TaskProgress{
+		{TaskID: "task-001", Status: state.TaskDone},
+		{TaskID: "task-002", Status: state.TaskInProgress},
+		{TaskID: "task-003", Status: state.TaskPending},
+		{TaskID: "task-004", Status: state.TaskFailed},
+		{TaskID: "task-005", Status: state.TaskPending},
+		{TaskID: "task-006", Status: state.TaskPending, DependsOn: []string{"task-005"}},
+	}
+	ids := func(p []TaskProgress) []string {
+		var out []string
+		for _, tp := range p {
+			out = append(out, tp.TaskID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		taskID    string
+		direction int
+		want      []string
+		wantErr   bool
+	}{
+		{"down skips non-pending", "task-003", 1, []string{"task-001", "task-002", "task-005", "task-004", "task-003", "task-006"}, false},
+		{"up skips non-pending", "task-005", -1, []string{"task-001", "task-002", "task-005", "task-004", "task-003", "task-006"}, false},
+		{"no pending task above", "task-003", -1, nil, true},
+		{"no pending task below", "task-006", 1, nil, true},
+		{"dependent cannot move above its dependency", "task-006", -1, nil, true},
+		{"dependency cannot move below its dependent", "task-005", 1, nil, true},
+		{"running task cannot move", "task-002", 1, nil, true},
+		{"unknown task", "task-999", 1, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ReorderProgress(progress, tt.taskID, tt.direction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if strings.Join(ids(got), ",") != strings.Join(tt.want, ",") {
+				t.Errorf("order = %v, want %v", ids(got), tt.want)
+			}
+			if progress[2].TaskID != "task-003" {
+				t.Error("input slice was mutated")
+			}
+		})
+	}
+}
+
+func TestNewExecutionTask(t *testing.T) {
+	t.Parallel()
+	ids := []string{"task-001", "task-002", "task-005"} // task-005 may be cancelled
+
+	tests := []struct {
+		name    string
+		parsed  parsedTemplate
+		wantID  string
+		wantErr bool
+	}{
+		{
+			name:   "allocates next ID past all known tasks",
+			parsed: parsedTemplate{title: "Hotfix", complexity: "small", dependsOn: []string{"task-002"}},
+			wantID: "task-006",
+		},
+		{
+			name:    "missing title",
+			parsed:  parsedTemplate{complexity: "small"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown dependency",
+			parsed:  parsedTemplate{title: "Hotfix", complexity: "small", dependsOn: []string{"task-009"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			parsed:  parsedTemplate{title: "Hotfix", complexity: "small", taskType: "script"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown owner",
+			parsed:  parsedTemplate{title: "Hotfix", complexity: "small", owner: "robot"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			task, err := NewExecutionTask(ids, tt.parsed, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if task.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", task.ID, tt.wantID)
+			}
+			if task.Status != state.TaskPending || task.PlanVersionCreated != 3 {
+				t.Errorf("task = %+v, want pending task created in plan v3", task)
+			}
+		})
+	}
+}
+
+func TestAppendPendingTask(t *testing.T) {
+	t.Parallel()
+	s := &state.State{Tasks: []state.Task{{ID: "task-001", Status: state.TaskDone}}}
+	task := state.Task{ID: "task-002", Title: "Hotfix", Complexity: "small", Status: state.TaskPending, DependsOn: []string{"task-001"}}
+
+	if err := AppendPendingTask(s, task); err != nil {
+		t.Fatalf("AppendPendingTask() error: %v", err)
+	}
+	if len(s.Tasks) != 2 || s.Tasks[1].ID != "task-002" {
+		t.Fatalf("tasks = %+v, want task-002 appended", s.Tasks)
+	}
+	if err := AppendPendingTask(s, task); err == nil {
+		t.Error("expected error for duplicate task ID")
+	}
+
+	orphan := state.Task{ID: "task-003", Title: "Orphan", Complexity: "small", DependsOn: []string{"task-404"}}
+	if err := AppendPendingTask(s, orphan); err == nil {
+		t.Error("expected error for missing dependency")
+	}
+}
+
+func TestFormatScheduledStart(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 10, 22, 15, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{"later today", now.Add(45 * time.Minute), "Scheduled to start at 23:00 (in 45m)"},
+		{"tomorrow", time.Date(2026, 3, 11, 2, 0, 0, 0, time.UTC), "Scheduled to start at Wed 02:00 (in 3h 45m)"},
+		{"imminent", now.Add(20 * time.Second), "Scheduled to start at 22:15 (any moment)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatScheduledStart(tt.at, now); got != tt.want {
+				t.Errorf("FormatScheduledStart() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // ============================================================
 // FormatTaskStatusLine
 // ============================================================
 
+func TestFormatRepoProgress(t *testing.T) {
+	t.Parallel()
+	progress := []TaskProgress{
+		{TaskID: "task-001", Repo: "frontend", Status: state.TaskDone},
+		{TaskID: "task-002", Status: state.TaskDone},
+		{TaskID: "task-003", Repo: "frontend", Status: state.TaskPending},
+		{TaskID: "task-004", Status: state.TaskFailed},
+	}
+	if got, want := FormatRepoProgress(progress), "root 1/2 · frontend 1/2"; got != want {
+		t.Errorf("FormatRepoProgress() = %q, want %q", got, want)
+	}
+	if got := FormatRepoProgress(progress[1:2]); got != "" {
+		t.Errorf("FormatRepoProgress() = %q, want empty without workspace repos", got)
+	}
+	line := FormatTaskStatusLine(progress[0], false, 80, components.StatusEmoji)
+	if !strings.Contains(line, "frontend: ") {
+		t.Errorf("status line %q should name the repo", line)
+	}
+}
+
 func TestFormatTaskStatusLine(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name        string
 		tp          TaskProgress
 		selected    bool
+		style       components.StatusStyle // "" = emoji
 		mustContain []string
 	}{
 		{
@@ -492,6 +713,14 @@ func TestFormatTaskStatusLine(t *testing.T) {
 			},
 			mustContain: []string{"task-005", "Upcoming"},
 		},
+		{
+			name: "human task",
+			tp: TaskProgress{
+				TaskID: "task-006", Title: "Sign DPA", Complexity: "small",
+				Status: state.TaskPending, Human: true, Assignee: "alice",
+			},
+			mustContain: []string{"👤", "task-006", "(human · @alice)"},
+		},
 		{
 			name: "selected task has arrow",
 			tp: TaskProgress{
@@ -501,11 +730,29 @@ func TestFormatTaskStatusLine(t *testing.T) {
 			selected:    true,
 			mustContain: []string{"→"},
 		},
+		{
+			name: "failed task as a text badge",
+			tp: TaskProgress{
+				TaskID: "task-003", Title: "Payment", Complexity: "large",
+				Status: state.TaskFailed,
+			},
+			style:       components.StatusBadges,
+			mustContain: []string{"[FAIL]   task-003"},
+		},
+		{
+			name: "human task as a symbol",
+			tp: TaskProgress{
+				TaskID: "task-006", Title: "Sign DPA", Complexity: "small",
+				Status: state.TaskPending, Human: true,
+			},
+			style:       components.StatusSymbols,
+			mustContain: []string{"@  task-006"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			line := FormatTaskStatusLine(tt.tp, tt.selected, 80)
+			line := FormatTaskStatusLine(tt.tp, tt.selected, 80, tt.style)
 			for _, s := range tt.mustContain {
 				if !strings.Contains(line, s) {
 					t.Errorf("line missing %q\ngot: %q", s, line)
@@ -552,6 +799,14 @@ func TestFormatCompletionMessage(t *testing.T) {
 			},
 			mustContain: []string{"Cancelled", "2/5"},
 		},
+		{
+			name:   "paused on budget",
+			status: ExecPaused,
+			summary: ExecutionSummary{
+				TotalTasks: 5, Completed: 2, PauseReason: "budget exhausted",
+			},
+			mustContain: []string{"Paused", "2/5", "budget exhausted"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -602,7 +857,25 @@ func TestFormatSummaryText(t *testing.T) {
 				TotalTasks: 2, Completed: 2, TotalRetries: 0,
 				TotalDuration: 2 * time.Minute,
 			},
-			mustNotContain: []string{"retries"},
+			mustNotContain: []string{"retries", "Cost"},
+		},
+		{
+			name: "with cost",
+			summary: ExecutionSummary{
+				TotalTasks: 1, Completed: 1, CostUSD: 1.234,
+			},
+			mustContain: []string{"Cost: $1.23"},
+		},
+		{
+			name: "with failed verification",
+			summary: ExecutionSummary{
+				TotalTasks: 2, Completed: 2,
+				Verification: &state.Verification{Passed: false, Steps: []state.VerificationStep{
+					{Name: "build", Passed: true},
+					{Name: "test", Passed: false},
+				}},
+			},
+			mustContain: []string{"✗ verification  build ✓ · test ✗"},
 		},
 	}
 	for _, tt := range tests {
@@ -742,3 +1015,109 @@ func TestApplyEventToProgress_LimitsLogLines(t *testing.T) {
 		t.Errorf("log lines = %d, should be capped", len(progress[0].LogLines))
 	}
 }
+
+func TestApplyEventToProgress_TaskReset(t *testing.T) {
+	t.Parallel()
+	fin := time.Now()
+	progress := []TaskProgress{
+		{TaskID: "task-002", Status: state.TaskSkipped, FinishedAt: &fin, Elapsed: time.Second},
+	}
+
+	ApplyEventToProgress(progress, executor.TaskEvent{
+		TaskID: "task-002", Type: executor.EventTaskReset, Message: "dependencies satisfied",
+	})
+
+	tp := progress[0]
+	if tp.Status != state.TaskPending || tp.FinishedAt != nil || tp.Elapsed != 0 {
+		t.Errorf("progress = %+v, want reset to pending", tp)
+	}
+	if len(tp.LogLines) != 1 || !strings.Contains(tp.LogLines[0].Text, "unblocked") {
+		t.Errorf("log lines = %+v", tp.LogLines)
+	}
+}
+
+func TestApplyEventToProgress_RootCause(t *testing.T) {
+	t.Parallel()
+	progress := []TaskProgress{{TaskID: "task-004", Status: state.TaskInProgress}}
+
+	ApplyEventToProgress(progress, executor.TaskEvent{
+		TaskID: "task-004", Type: executor.EventRootCause, Message: "Stripe keys are not configured",
+		Detail: "Root cause: Stripe keys are not configured [environment]\n- Add a manual task to set STRIPE_KEY\n",
+	})
+
+	tp := progress[0]
+	if tp.RootCause != "Stripe keys are not configured" {
+		t.Errorf("RootCause = %q", tp.RootCause)
+	}
+	if len(tp.LogLines) != 1 || !strings.HasSuffix(tp.LogLines[0].Text, "- Add a manual task to set STRIPE_KEY") {
+		t.Errorf("log lines = %+v", tp.LogLines)
+	}
+}
+
+// ============================================================
+// FormatRunLine / FormatRunHistory
+// ============================================================
+
+func TestFormatRunLine(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(4*time.Minute + 12*time.Second)
+
+	tests := []struct {
+		name string
+		run  state.RunSummary
+		want string
+	}{
+		{
+			name: "finished with cost",
+			run: state.RunSummary{ID: 2, Status: state.RunCompleted, StartedAt: start, FinishedAt: &end,
+				Completed: 3, Failed: 1, CostUSD: 0.42},
+			want: "Run #2 · completed · 3 done, 1 failed · 4:12 · $0.42",
+		},
+		{
+			name: "in progress",
+			run:  state.RunSummary{ID: 1, Status: state.RunInProgress, StartedAt: start, Completed: 1, Skipped: 2},
+			want: "Run #1 · in_progress · 1 done, 2 skipped",
+		},
+		{
+			name: "paused at quiet hours",
+			run: state.RunSummary{ID: 3, Status: state.RunPaused, StartedAt: start, FinishedAt: &end, Completed: 2,
+				Checkpoint: &state.Checkpoint{NextTask: "task-004", ResumeAt: &end}},
+			want: "Run #3 · paused · 2 done · 4:12 · next: task-004 after 10:04",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatRunLine(tt.run); got != tt.want {
+				t.Errorf("FormatRunLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRunHistory_NewestFirstAndLimited(t *testing.T) {
+	t.Parallel()
+	runs := []state.RunSummary{
+		{ID: 1, Status: state.RunCompleted},
+		{ID: 2, Status: state.RunCancelled},
+		{ID: 3, Status: state.RunCompleted},
+	}
+	lines := strings.Split(FormatRunHistory(runs, 2), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "Run #3") || !strings.HasPrefix(lines[1], "Run #2") {
+		t.Errorf("history = %v, want runs 3 then 2", lines)
+	}
+}
+
+func TestFormatDirtyWorktree(t *testing.T) {
+	t.Parallel()
+	got := FormatDirtyWorktree([]string{"a.go", "b.go", "c.go"}, 2)
+	for _, want := range []string{"3 uncommitted file(s)", "  a.go\n  b.go\n  … and 1 more\n", "git stash pop"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatDirtyWorktree() missing %q:\n%s", want, got)
+		}
+	}
+}
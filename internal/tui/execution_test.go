@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestExecutionModel_ErrorEvents(t *testing.T) {
+	t.Parallel()
+	s := &state.State{
+		Settings: &state.Settings{MaxRetries: 1},
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskPending},
+		},
+	}
+	m := NewExecutionModel(s, t.TempDir(), nil)
+
+	// A task's error lands in its log, not in a flash
+	m, _ = m.Update(ExecutionEventMsg{Event: executor.TaskEvent{TaskID: "task-001", Type: executor.EventError, Message: "waiting for CI: timeout"}})
+	if m.flashMsg != "" {
+		t.Errorf("flash = %q, want none for a task error", m.flashMsg)
+	}
+	lines := m.progress[0].LogLines
+	if len(lines) != 1 || lines[0].Text != "Error: waiting for CI: timeout" {
+		t.Errorf("task log = %+v, want the error", lines)
+	}
+
+	// Run-level errors and rejected edits have no task log to go to
+	m, _ = m.Update(ExecutionEventMsg{Event: executor.TaskEvent{Type: executor.EventError, Message: "failed to push"}})
+	if m.flashMsg != "failed to push" {
+		t.Errorf("flash = %q, want the run error", m.flashMsg)
+	}
+	m, _ = m.Update(ExecutionEventMsg{Event: executor.TaskEvent{TaskID: "task-001", Type: executor.EventEditRejected, Message: "skip task-001: already done"}})
+	if m.flashMsg != "skip task-001: already done" {
+		t.Errorf("flash = %q, want the rejected edit", m.flashMsg)
+	}
+}
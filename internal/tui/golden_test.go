@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden/*.golden with current output")
+
+// goldenTime is the pinned clock for every golden test.
+var goldenTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// assertGolden compares got with testdata/golden/<name>.golden. Run
+// `go test ./internal/tui -run Golden -update` to accept new output.
+//
+// Golden tests pin package-level rendering state, so they must not call
+// t.Parallel.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s does not match golden file %s\n--- got ---\n%s\n--- want ---\n%s", name, path, got, want)
+	}
+}
+
+// ============================================================
+// Golden views
+// ============================================================
+
+func TestGolden_TaskStatusLines(t *testing.T) {
+	defer DeterministicRendering(goldenTime)()
+
+	rows := []TaskProgress{
+		{TaskID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskDone, Elapsed: 12 * time.Second},
+		{TaskID: "task-002", Title: "Auth", Complexity: "medium", Status: state.TaskInProgress, Elapsed: 83 * time.Second},
+		{TaskID: "task-003", Title: "Payment", Complexity: "large", Status: state.TaskFailed, RetryCount: 3},
+		{TaskID: "task-004", Title: "Depends on failed", Complexity: "small", Status: state.TaskSkipped},
+		{TaskID: "task-005", Title: "Docs", Complexity: "small", Status: state.TaskPending},
+	}
+	var lines []string
+	for i, tp := range rows {
+		lines = append(lines, FormatTaskStatusLine(tp, i == 1, 80, components.StatusEmoji))
+	}
+	assertGolden(t, "task_status_lines", strings.Join(lines, "\n"))
+}
+
+func TestGolden_SummaryText(t *testing.T) {
+	defer DeterministicRendering(goldenTime)()
+
+	summary := ExecutionSummary{
+		TotalTasks: 5, Completed: 3, Failed: 1, Skipped: 1, TotalRetries: 2,
+		TotalDuration: 11*time.Minute + 6*time.Second,
+		Branches:      []string{"forge/task-001", "forge/task-002"},
+		CostUSD:       1.25,
+		Verification: &state.Verification{Steps: []state.VerificationStep{
+			{Name: "build", Passed: true}, {Name: "test", Passed: false},
+		}},
+	}
+	assertGolden(t, "summary_text", FormatSummaryText(summary)+"\n---\n"+FormatCompletionMessage(ExecStopped, summary))
+}
+
+func TestGolden_ExecutionView(t *testing.T) {
+	defer DeterministicRendering(goldenTime)()
+
+	s := &state.State{
+		ProjectName: "demo",
+		PlanVersion: 2,
+		Settings:    &state.Settings{MaxRetries: 2},
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Init project", Complexity: "small", Status: state.TaskPending},
+			{ID: "task-002", Title: "Add auth", Complexity: "medium", Status: state.TaskPending, DependsOn: []string{"task-001"}},
+			{ID: "task-003", Title: "Write docs", Complexity: "small", Status: state.TaskPending},
+		},
+	}
+	m := NewExecutionModel(s, t.TempDir(), nil)
+	m.SetSize(80, 24)
+
+	ts := goldenTime.UnixMilli()
+	for _, e := range []executor.TaskEvent{
+		{TaskID: "task-001", Type: executor.EventTaskStart, Message: "Init project", Timestamp: ts},
+		{TaskID: "task-001", Type: executor.EventBranchCreated, Message: "forge/task-001", Timestamp: ts + 1000},
+		{TaskID: "task-001", Type: executor.EventTestPassed, Timestamp: ts + 5000},
+		{TaskID: "task-001", Type: executor.EventTaskDone, Message: "completed", Timestamp: ts + 6000},
+		{TaskID: "task-002", Type: executor.EventTaskStart, Message: "Add auth", Timestamp: ts + 7000},
+	} {
+		m, _ = m.Update(ExecutionEventMsg{Event: e})
+	}
+
+	assertGolden(t, "execution_view", m.View())
+}
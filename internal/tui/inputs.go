@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,9 +14,13 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/manasm11/forge/internal/generator"
+	"github.com/manasm11/forge/internal/janitor"
+	"github.com/manasm11/forge/internal/platform"
 	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/schedule"
 	"github.com/manasm11/forge/internal/scanner"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // ollamaDetectionDoneMsg is sent when Ollama detection completes.
@@ -25,13 +29,19 @@ type ollamaDetectionDoneMsg struct {
 	err    string
 }
 
+// modelCheckDoneMsg carries the capability problems of the model in cfg.
+type modelCheckDoneMsg struct {
+	cfg  provider.Config
+	errs FieldErrors
+}
+
 // editorDoneMsg is sent when $EDITOR closes for the extra context field.
 type editorDoneMsg struct {
 	err     error
 	tmpPath string
 }
 
-// clearFlashMsg clears the flash message after a timeout.
+// clearFlashMsg clears the execution screen's flash message after a timeout.
 type clearFlashMsg struct{}
 
 // InputsModel manages the input collection phase.
@@ -39,28 +49,33 @@ type InputsModel struct {
 	fields        []InputField
 	mcpServers    []MCPServer
 	maxTurns      MaxTurnsConfig
+	turnsInputs   []textinput.Model // small, medium, large max turns
 	cursor        int // index into the combined navigation items
 	textInputs    []textinput.Model
 	state         *state.State
 	stateRoot     string
 	width         int
 	height        int
-	flashMsg      string
-	flashErr      bool // true if flashMsg is an error
+	status        components.StatusLine // messages stay until dismissed
+	fieldErrs     FieldErrors           // problems from the last confirm, shown under their fields
+	warned        bool                  // warnings were shown; the next confirm goes ahead
 	providerType  provider.ProviderType // currently selected provider
-	ollamaURL     string                // Ollama URL if using Ollama
 	ollamaModels  []string              // available Ollama models
 	ollamaChecked bool                  // whether Ollama detection has completed
 	ollamaError   string                // error from Ollama detection if any
+	modelChecked  provider.Config       // config whose model passed (or only warned) the capability check
+	checkingModel bool                  // a capability check is running
+	generated     []string              // agent files written here, e.g. CLAUDE.md
 }
 
-// Navigation sections: fields (0..len(fields)-1), then MCP servers, then max turns fields.
+// Navigation sections: provider selection, fields, then MCP servers, then max turns fields.
 // We track which "zone" the cursor is in.
 
 const (
-	zoneFields     = 0
-	zoneMCP        = 1
-	zoneMaxTurns   = 2
+	zoneProvider = 0
+	zoneFields   = 1
+	zoneMCP      = 2
+	zoneMaxTurns = 3
 )
 
 func NewInputsModel(s *state.State, root string) InputsModel {
@@ -70,7 +85,6 @@ func NewInputsModel(s *state.State, root string) InputsModel {
 
 	// Initialize provider fields
 	providerType := provider.ProviderAnthropic
-	ollamaURL := provider.DefaultOllamaURL()
 	var ollamaModels []string
 
 	// Pre-populate from existing settings if available
@@ -91,10 +105,7 @@ func NewInputsModel(s *state.State, root string) InputsModel {
 		if s.Settings.Provider.Type != "" {
 			providerType = s.Settings.Provider.Type
 		}
-		if s.Settings.Provider.OllamaURL != "" {
-			ollamaURL = s.Settings.Provider.OllamaURL
-		}
-		// Model will be populated from the ClaudeModel field
+		// Model and Ollama connection are populated from their fields
 	}
 
 	// Create text inputs for text/number fields
@@ -126,16 +137,24 @@ func NewInputsModel(s *state.State, root string) InputsModel {
 		textInputs[0].Focus()
 	}
 
+	var turnsInputs []textinput.Model
+	for _, n := range maxTurns.Values() {
+		ti := textinput.New()
+		ti.CharLimit = 4
+		ti.SetValue(strconv.Itoa(n))
+		turnsInputs = append(turnsInputs, ti)
+	}
+
 	m := InputsModel{
 		fields:       fields,
 		mcpServers:   mcpServers,
 		maxTurns:     maxTurns,
+		turnsInputs:  turnsInputs,
 		cursor:       0,
 		textInputs:   textInputs,
 		state:        s,
 		stateRoot:    root,
 		providerType: providerType,
-		ollamaURL:    ollamaURL,
 		ollamaModels: ollamaModels,
 	}
 
@@ -167,16 +186,126 @@ func populateFromSettings(fields []InputField, settings *state.Settings) {
 			if settings.RemoteURL != "" {
 				fields[i].Value = settings.RemoteURL
 			}
+		case "lint_command":
+			fields[i].Value = settings.LintCommand
+		case "max_run_duration":
+			fields[i].Value = settings.MaxRunDuration
+		case "quiet_hours":
+			fields[i].Value = settings.QuietHours
+		case "shell":
+			fields[i].Value = settings.Shell
+		case "alert":
+			if settings.Alert != "" {
+				fields[i].Value = settings.Alert
+			}
+		case "status_style":
+			if settings.StatusStyle != "" {
+				fields[i].Value = settings.StatusStyle
+			}
+		case "accessible":
+			fields[i].Value = fmt.Sprintf("%t", settings.Accessible)
+		case "ascii_mode":
+			fields[i].Value = fmt.Sprintf("%t", settings.ASCIIMode)
+		case "max_budget":
+			fields[i].Value = settings.MaxBudget.String()
+		case "max_change_mb":
+			fields[i].Value = fmt.Sprintf("%d", settings.MaxChangeMB)
+		case "code_index":
+			fields[i].Value = fmt.Sprintf("%t", settings.CodeIndex)
+		case "embedding_model":
+			fields[i].Value = settings.EmbeddingModel
+		case "warm_start_files":
+			fields[i].Value = fmt.Sprintf("%d", settings.WarmStartFiles)
+		case "max_file_mb":
+			fields[i].Value = fmt.Sprintf("%d", settings.CommitPolicy.MaxFileMB)
+		case "scan_secrets":
+			fields[i].Value = fmt.Sprintf("%t", settings.CommitPolicy.ScanSecrets)
+		case "allow_workflow_changes":
+			fields[i].Value = fmt.Sprintf("%t", !settings.CommitPolicy.ProtectWorkflows)
 		case "auto_pr":
 			if settings.AutoPR {
 				fields[i].Value = "true"
 			} else {
 				fields[i].Value = "false"
 			}
+		case "draft_prs":
+			fields[i].Value = fmt.Sprintf("%t", settings.DraftPRs)
+		case "file_issues":
+			fields[i].Value = fmt.Sprintf("%t", settings.FileIssues)
+		case "changelog":
+			if settings.Changelog != "" {
+				fields[i].Value = settings.Changelog
+			}
+		case "publish_checks":
+			fields[i].Value = fmt.Sprintf("%t", settings.PublishChecks)
+		case "wait_for_ci":
+			fields[i].Value = fmt.Sprintf("%t", settings.WaitForCI)
+		case "skip_hooks":
+			fields[i].Value = fmt.Sprintf("%t", settings.SkipHooks)
+		case "stash_dirty":
+			fields[i].Value = fmt.Sprintf("%t", settings.StashDirty)
+		case "front_load_risk":
+			fields[i].Value = fmt.Sprintf("%t", settings.FrontLoadRisk)
+		case "critical_path_first":
+			fields[i].Value = fmt.Sprintf("%t", settings.CriticalPathFirst)
+		case "adaptive_turns":
+			fields[i].Value = fmt.Sprintf("%t", settings.AdaptiveTurns)
+		case "base_drift":
+			if settings.BaseDrift != "" {
+				fields[i].Value = settings.BaseDrift
+			}
+		case "commit_identity":
+			fields[i].Value = settings.CommitIdentity
+		case "sign_off":
+			fields[i].Value = fmt.Sprintf("%t", settings.SignOff)
+		case "co_author":
+			if settings.CoAuthor != "" {
+				fields[i].Value = settings.CoAuthor
+			}
+		case "code_review":
+			fields[i].Value = fmt.Sprintf("%t", settings.CodeReview)
+		case "review_model":
+			fields[i].Value = settings.ReviewModel
+		case "checklist":
+			fields[i].Value = strings.Join(settings.Checklist, "; ")
+		case "workspace_repos":
+			paths := make([]string, len(settings.Repos))
+			for j, r := range settings.Repos {
+				paths[j] = r.Path
+			}
+			fields[i].Value = strings.Join(paths, ", ")
+		case "sparse_paths":
+			fields[i].Value = strings.Join(settings.SparsePaths, ", ")
+		case "agents_md":
+			fields[i].Value = fmt.Sprintf("%t", settings.GenerateAgentsMD)
+		case "cursor_rules":
+			fields[i].Value = fmt.Sprintf("%t", settings.GenerateCursorRules)
 		case "claude_model":
 			if settings.ClaudeModel != "" {
 				fields[i].Value = settings.ClaudeModel
+			} else if settings.Provider.Model != "" {
+				fields[i].Value = settings.Provider.Model // chosen at startup
+			}
+		case "ollama_url":
+			if settings.Provider.OllamaURL != "" {
+				fields[i].Value = settings.Provider.OllamaURL
 			}
+		case "ollama_auth":
+			if settings.Provider.OllamaAuth != "" {
+				fields[i].Value = settings.Provider.OllamaAuth
+			}
+		case "ollama_token_env":
+			fields[i].Value = settings.Provider.OllamaTokenEnv
+		case "ollama_ca_cert":
+			fields[i].Value = settings.Provider.OllamaCACert
+		case "ollama_insecure":
+			fields[i].Value = fmt.Sprintf("%t", settings.Provider.OllamaInsecure)
+		case "context_urls":
+			fields[i].Value = strings.Join(settings.ContextURLs, ", ")
+		case "webhook_url":
+			fields[i].Value = settings.WebhookURL
+		case "database_url_env":
+			fields[i].Value = settings.DatabaseURLEnv
 		case "extra_context":
 			if settings.ExtraContext != "" {
 				fields[i].Value = settings.ExtraContext
@@ -199,100 +328,120 @@ func populateMCPFromSettings(servers []MCPServer, settings *state.Settings) {
 
 func (m InputsModel) Init() tea.Cmd {
 	// Start Ollama detection in the background
-	return tea.Batch(
-		textinput.Blink,
-		func() tea.Msg {
-			// Run Ollama detection
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			defer cancel()
-
-			status := provider.DetectOllama(ctx, "")
-
-			if !status.Available {
-				return ollamaDetectionDoneMsg{
-					models: nil,
-					err:    status.Error,
-				}
-			}
+	return tea.Batch(textinput.Blink, m.detectOllama())
+}
 
-			// Extract model names
-			models := make([]string, len(status.Models))
-			for i, model := range status.Models {
-				models[i] = provider.FormatModelName(model.Name)
-			}
+// detectOllama fetches the model list from the Ollama server in the form,
+// with its auth and TLS options.
+func (m InputsModel) detectOllama() tea.Cmd {
+	cfg := m.providerConfig(provider.ProviderOllama)
+	return func() tea.Msg {
+		// Run Ollama detection
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
 
+		status := provider.CheckOllama(ctx, cfg)
+
+		if !status.Available {
 			return ollamaDetectionDoneMsg{
-				models: models,
-				err:    "",
+				models: nil,
+				err:    status.Error,
 			}
-		},
-	)
+		}
+
+		// Extract model names
+		models := make([]string, len(status.Models))
+		for i, model := range status.Models {
+			models[i] = provider.FormatModelName(model.Name)
+		}
+
+		return ollamaDetectionDoneMsg{
+			models: models,
+			err:    "",
+		}
+	}
 }
 
 // totalItems returns the total number of navigable items.
 func (m InputsModel) totalItems() int {
 	// Provider selection takes 2 positions (Anthropic and Ollama)
-	return 2 + len(m.fields) + len(m.mcpServers)
+	return 2 + len(m.fields) + len(m.mcpServers) + len(m.turnsInputs)
+}
+
+// editingText reports whether the cursor is on a text or number field, whose
+// input gets the letter keys.
+func (m InputsModel) editingText() bool {
+	zone, localIdx := m.cursorZone()
+	if zone == zoneMaxTurns {
+		return true
+	}
+	if zone != zoneFields || localIdx >= len(m.fields) {
+		return false
+	}
+	f := m.fields[localIdx]
+	return f.FieldType == FieldText || f.FieldType == FieldNumber
 }
 
 // cursorZone returns which zone the cursor is in and the local index.
-// Zones: 0=provider selection, 1=fields, 2=MCP servers
+// Zones: 0=provider selection, 1=fields, 2=MCP servers, 3=max turns
 func (m InputsModel) cursorZone() (zone int, localIdx int) {
 	if m.cursor < 2 {
-		return 0, m.cursor // provider selection
+		return zoneProvider, m.cursor // provider selection
 	}
 	cursorAdjusted := m.cursor - 2
 	if cursorAdjusted < len(m.fields) {
-		return 1, cursorAdjusted // fields
+		return zoneFields, cursorAdjusted // fields
 	}
-	return 2, cursorAdjusted - len(m.fields) // MCP servers
+	cursorAdjusted -= len(m.fields)
+	if cursorAdjusted < len(m.mcpServers) {
+		return zoneMCP, cursorAdjusted // MCP servers
+	}
+	return zoneMaxTurns, cursorAdjusted - len(m.mcpServers)
 }
 
 func (m InputsModel) Update(msg tea.Msg) (InputsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.status.LogOpen() {
+			if s := msg.String(); s == "m" || s == "esc" || s == "q" {
+				m.status.ToggleLog()
+			}
+			return m, nil
+		}
 		switch msg.String() {
+		case "esc":
+			if m.status.Dismiss() {
+				return m, nil
+			}
+		case "m":
+			if !m.editingText() {
+				m.status.ToggleLog()
+				return m, nil
+			}
+		case "ctrl+r":
+			// Re-check Ollama, e.g. after changing its URL or auth
+			m.ollamaChecked = false
+			return m, m.detectOllama()
 		case "tab", "down":
 			return m.moveCursor(1), nil
 		case "shift+tab", "up":
 			return m.moveCursor(-1), nil
 		case "c":
 			// Don't capture 'c' if typing in a text input
-			zone, _ := m.cursorZone()
-			if zone == 1 { // fields zone
-				_, localIdx := m.cursorZone()
-				if localIdx < len(m.fields) {
-					f := m.fields[localIdx]
-					if f.FieldType == FieldText || f.FieldType == FieldNumber {
-						break // let text input handle it
-					}
-				}
+			if m.editingText() {
+				break // let text input handle it
 			}
 			return m.confirm()
 		case "b":
-			zone, _ := m.cursorZone()
-			if zone == 1 { // fields zone
-				_, localIdx := m.cursorZone()
-				if localIdx < len(m.fields) {
-					f := m.fields[localIdx]
-					if f.FieldType == FieldText || f.FieldType == FieldNumber {
-						break // let text input handle it
-					}
-				}
+			if m.editingText() {
+				break // let text input handle it
 			}
 			return m, func() tea.Msg {
 				return TransitionMsg{To: state.PhaseReview}
 			}
 		case "q":
-			zone, _ := m.cursorZone()
-			if zone == 1 { // fields zone
-				_, localIdx := m.cursorZone()
-				if localIdx < len(m.fields) {
-					f := m.fields[localIdx]
-					if f.FieldType == FieldText || f.FieldType == FieldNumber {
-						break // let text input handle it
-					}
-				}
+			if m.editingText() {
+				break // let text input handle it
 			}
 			return m, tea.Quit
 		case " ":
@@ -302,21 +451,15 @@ func (m InputsModel) Update(msg tea.Msg) (InputsModel, tea.Cmd) {
 		}
 
 	case editorDoneMsg:
-		defer os.Remove(msg.tmpPath)
+		defer janitor.Release(m.stateRoot, msg.tmpPath)
 		if msg.err != nil {
-			m.flashMsg = fmt.Sprintf("Editor error: %v", msg.err)
-			m.flashErr = true
-			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-				return clearFlashMsg{}
-			})
+			m.status.Push(components.StatusError, fmt.Sprintf("Editor error: %v", msg.err))
+			return m, nil
 		}
 		data, err := os.ReadFile(msg.tmpPath)
 		if err != nil {
-			m.flashMsg = fmt.Sprintf("Could not read temp file: %v", err)
-			m.flashErr = true
-			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-				return clearFlashMsg{}
-			})
+			m.status.Push(components.StatusError, fmt.Sprintf("Could not read temp file: %v", err))
+			return m, nil
 		}
 		// Find the extra_context field and set its value
 		for i := range m.fields {
@@ -328,9 +471,23 @@ func (m InputsModel) Update(msg tea.Msg) (InputsModel, tea.Cmd) {
 		}
 		return m, nil
 
-	case clearFlashMsg:
-		m.flashMsg = ""
-		m.flashErr = false
+	case modelCheckDoneMsg:
+		m.checkingModel = false
+		if msg.cfg != m.providerConfig(m.providerType) {
+			return m, nil // the form changed while checking
+		}
+		if len(msg.errs) == 0 {
+			m.modelChecked = msg.cfg
+			return m.confirm()
+		}
+		m.fieldErrs = append(m.fieldErrs, msg.errs...)
+		if msg.errs.Blocking() {
+			m.status.Push(components.StatusError, fmt.Sprintf("%s can't run these tasks — see the model field", msg.cfg.Model))
+		} else {
+			m.modelChecked = msg.cfg // the next confirm goes ahead
+			m.status.Push(components.StatusWarning, fmt.Sprintf("%s may struggle with these tasks — press c again to confirm anyway", msg.cfg.Model))
+		}
+		m = m.focusField("claude_model")
 		return m, nil
 
 	case ollamaDetectionDoneMsg:
@@ -352,13 +509,78 @@ func (m InputsModel) Update(msg tea.Msg) (InputsModel, tea.Cmd) {
 			var cmd tea.Cmd
 			m.textInputs[localIdx], cmd = m.textInputs[localIdx].Update(msg)
 			m.fields[localIdx].Value = m.textInputs[localIdx].Value()
+			if m.fieldErrs != nil { // keep the highlights current while fixing
+				_, m.fieldErrs = m.validate()
+			}
 			return m, cmd
 		}
 	}
+	if zone == zoneMaxTurns && localIdx < len(m.turnsInputs) {
+		var cmd tea.Cmd
+		m.turnsInputs[localIdx], cmd = m.turnsInputs[localIdx].Update(msg)
+		if m.fieldErrs != nil {
+			_, m.fieldErrs = m.validate()
+		}
+		return m, cmd
+	}
 
 	return m, nil
 }
 
+// validate checks the fields and the max turns, returning the max turns
+// as entered.
+func (m InputsModel) validate() (MaxTurnsConfig, FieldErrors) {
+	var values [3]string
+	for i := range min(len(values), len(m.turnsInputs)) {
+		values[i] = m.turnsInputs[i].Value()
+	}
+	maxTurns, turnsErrs := ParseMaxTurns(values)
+	errs := append(ValidateSettings(m.fields), turnsErrs...)
+	if m.providerType == provider.ProviderOllama {
+		errs = append(errs, ValidateOllamaAccess(m.providerConfig(m.providerType))...)
+	}
+	return maxTurns, errs
+}
+
+// checkModel checks that the model in cfg supports what the tasks need,
+// before execution starts, and looks for an alternative if it doesn't.
+func (m InputsModel) checkModel(cfg provider.Config, settings *state.Settings) (InputsModel, tea.Cmd) {
+	if m.checkingModel {
+		return m, nil
+	}
+	m.checkingModel = true
+	m.status.Push(components.StatusInfo, fmt.Sprintf("Checking that %s can run the tasks…", cfg.Model))
+
+	next := *m.state
+	next.Settings = settings
+	req := ExecutionRequirements(generator.GenerateContextFile(&next), m.state.Tasks, settings)
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		caps, err := provider.ModelCapabilities(ctx, cfg)
+		if err != nil {
+			return modelCheckDoneMsg{cfg: cfg, errs: FieldErrors{{Field: "claude_model", Severity: SeverityWarning,
+				Message: fmt.Sprintf("Model: could not check what %s supports: %v", cfg.Model, err)}}}
+		}
+		problems := provider.CheckCapabilities(cfg.Model, caps, req)
+		var suggestion string
+		if len(problems) > 0 {
+			suggestion = provider.SuggestModel(ctx, cfg, req)
+		}
+		return modelCheckDoneMsg{cfg: cfg, errs: CapabilityFieldErrors(problems, suggestion, cfg.Type)}
+	}
+}
+
+// providerConfig builds the provider config the form describes for pt.
+func (m InputsModel) providerConfig(pt provider.ProviderType) provider.Config {
+	values := map[string]string{"provider_type": string(pt)}
+	for i, f := range m.fields {
+		values[f.Key] = m.resolveValue(i)
+	}
+	return BuildProviderConfigFromFields(values)
+}
+
 func (m InputsModel) moveCursor(delta int) InputsModel {
 	total := m.totalItems()
 	if total == 0 {
@@ -370,6 +592,9 @@ func (m InputsModel) moveCursor(delta int) InputsModel {
 	if zone == 1 && localIdx < len(m.textInputs) { // zoneFields is now 1
 		m.textInputs[localIdx].Blur()
 	}
+	if zone == zoneMaxTurns {
+		m.turnsInputs[localIdx].Blur()
+	}
 
 	m.cursor += delta
 	if m.cursor < 0 {
@@ -387,19 +612,47 @@ func (m InputsModel) moveCursor(delta int) InputsModel {
 			m.textInputs[localIdx].Focus()
 		}
 	}
+	if zone == zoneMaxTurns {
+		m.turnsInputs[localIdx].Focus()
+	}
 
 	return m
 }
 
+// focusField moves the cursor to the field with the given key.
+func (m InputsModel) focusField(key string) InputsModel {
+	for i, f := range m.fields {
+		if f.Key == key {
+			return m.moveCursor(2 + i - m.cursor) // provider selection comes first
+		}
+	}
+	for i, k := range MaxTurnsKeys {
+		if k == key {
+			return m.moveCursor(2 + len(m.fields) + len(m.mcpServers) + i - m.cursor)
+		}
+	}
+	return m
+}
+
 func (m InputsModel) handleSpace() (InputsModel, tea.Cmd) {
 	zone, localIdx := m.cursorZone()
 
 	switch zone {
 	case 0: // provider selection
-		if localIdx == 0 { // Anthropic
-			m.providerType = provider.ProviderAnthropic
-		} else if localIdx == 1 { // Ollama
-			m.providerType = provider.ProviderOllama
+		chosen := provider.ProviderAnthropic
+		if localIdx == 1 { // Ollama
+			chosen = provider.ProviderOllama
+		}
+		if chosen == m.providerType {
+			return m, nil
+		}
+		m.providerType = chosen
+		if i := m.fieldIndex("claude_model"); i >= 0 {
+			m.setField(i, ModelForProvider(m.resolveValue(i), chosen, ModelChoices(chosen, m.ollamaModels)))
+		}
+		if chosen == provider.ProviderOllama {
+			m.ollamaChecked = false // fetch the current model list
+			return m, m.detectOllama()
 		}
 		return m, nil
 	case 1: // fields zone
@@ -439,28 +692,41 @@ func (m InputsModel) handleEnter() (InputsModel, tea.Cmd) {
 		if f.FieldType == FieldEditor {
 			return m.openEditor(localIdx)
 		}
+		if f.Key == "claude_model" {
+			m.setField(localIdx, NextModel(m.textInputs[localIdx].Value(), ModelChoices(m.providerType, m.ollamaModels)))
+		}
 	}
 	return m, nil
 }
 
-func (m InputsModel) openEditor(fieldIdx int) (InputsModel, tea.Cmd) {
-	tmpDir := os.TempDir()
-	tmpPath := filepath.Join(tmpDir, "forge-extra-context.txt")
+// fieldIndex returns the index of the field with the given key, or -1.
+func (m InputsModel) fieldIndex(key string) int {
+	for i, f := range m.fields {
+		if f.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// setField sets a field's value and its text input.
+func (m *InputsModel) setField(i int, value string) {
+	m.fields[i].Value = value
+	m.textInputs[i].SetValue(value)
+}
 
+func (m InputsModel) openEditor(fieldIdx int) (InputsModel, tea.Cmd) {
 	content := m.fields[fieldIdx].Value
 	if content == "" {
 		content = m.fields[fieldIdx].Default
 	}
-	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
-		m.flashMsg = fmt.Sprintf("Failed to create temp file: %v", err)
-		m.flashErr = true
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearFlashMsg{}
-		})
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, "forge-extra-context-*.txt", "extra context", []byte(content))
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to create temp file: %v", err))
+		return m, nil
 	}
 
-	editor := getEditor()
-	c := exec.Command(editor, tmpPath)
+	c := platform.EditorCommand(tmpPath)
 
 	return m, tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorDoneMsg{err: err, tmpPath: tmpPath}
@@ -483,14 +749,19 @@ func (m InputsModel) confirm() (InputsModel, tea.Cmd) {
 		}
 	}
 
-	// Validate
-	errs := ValidateSettings(m.fields)
-	if len(errs) > 0 {
-		m.flashMsg = strings.Join(errs, "; ")
-		m.flashErr = true
-		return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
-			return clearFlashMsg{}
-		})
+	// Validate; problems are shown under their fields. Warnings alone only
+	// stop the first confirm.
+	maxTurns, errs := m.validate()
+	m.fieldErrs = errs
+	if errs.Blocking() || (len(errs) > 0 && !m.warned) {
+		m.warned = true
+		if errs.Blocking() {
+			m.status.Push(components.StatusError, fmt.Sprintf("Check the highlighted fields (%s)", errs.Summary()))
+		} else {
+			m.status.Push(components.StatusWarning, fmt.Sprintf("Check the highlighted fields (%s) — press c again to confirm anyway", errs.Summary()))
+		}
+		m = m.focusField(errs[0].Field)
+		return m, nil
 	}
 
 	// Build provider config
@@ -503,23 +774,35 @@ func (m InputsModel) confirm() (InputsModel, tea.Cmd) {
 		fieldMap[f.Key] = val
 	}
 
-	providerCfg := provider.Config{
-		Type:  m.providerType,
-		Model: fieldMap["claude_model"],
-	}
-	if m.providerType == provider.ProviderOllama {
-		providerCfg.OllamaURL = m.ollamaURL
-	}
+	providerCfg := m.providerConfig(m.providerType)
 
 	// Build settings
+	m.maxTurns = maxTurns
 	settings := BuildSettingsFromFieldsWithProvider(m.fields, m.mcpServers, m.maxTurns, providerCfg)
+	if providerCfg != m.modelChecked {
+		return m.checkModel(providerCfg, settings)
+	}
+	var previousRepos []state.WorkspaceRepo
+	var previousProvider provider.Config
+	if m.state.Settings != nil {
+		previousRepos = m.state.Settings.Repos
+		previousProvider = m.state.Settings.Provider
+	}
+	settings.Repos = ResolveRepos(m.stateRoot, settings.Repos, previousRepos)
 	m.state.Settings = settings
 
+	// A start time is a one-off for the next run, not a setting
+	m.state.ScheduledStart = nil
+	if at := fieldMap["start_at"]; at != "" {
+		if t, err := schedule.Parse(at, time.Now()); err == nil {
+			m.state.ScheduledStart = &t
+		}
+	}
+
 	// If user provided a remote URL, add it to git
 	if settings.RemoteURL != "" {
 		if err := scanner.AddRemote(m.stateRoot, "origin", settings.RemoteURL); err != nil {
-			m.flashMsg = fmt.Sprintf("Warning: Failed to add remote: %v", err)
-			m.flashErr = true
+			m.status.Push(components.StatusWarning, fmt.Sprintf("Warning: Failed to add remote: %v", err))
 			// Don't fail - continue anyway
 		}
 	}
@@ -529,57 +812,55 @@ func (m InputsModel) confirm() (InputsModel, tea.Cmd) {
 		// Check current remote
 		currentRemote := scanner.GitInitialized(m.stateRoot)
 		if !currentRemote {
-			m.flashMsg = "Warning: No remote configured. PR creation disabled."
-			m.flashErr = true
+			m.status.Push(components.StatusWarning, "Warning: No remote configured. PR creation disabled.")
 			settings.AutoPR = false
 		}
 	}
+	if settings.FileIssues && settings.RemoteURL == "" && !scanner.GitInitialized(m.stateRoot) {
+		m.status.Push(components.StatusWarning, "Warning: No remote configured. Issue filing disabled.")
+		settings.FileIssues = false
+	}
 
 	// Write .forge/context.md
 	contextContent := generator.GenerateContextFile(m.state)
 	contextPath := filepath.Join(m.stateRoot, ".forge", "context.md")
 	if err := os.WriteFile(contextPath, []byte(contextContent), 0644); err != nil {
-		m.flashMsg = fmt.Sprintf("Failed to write context.md: %v", err)
-		m.flashErr = true
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearFlashMsg{}
-		})
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to write context.md: %v", err))
+		return m, nil
 	}
 
-	// Write CLAUDE.md only if it doesn't exist
-	claudeMDPath := filepath.Join(m.stateRoot, "CLAUDE.md")
-	if _, err := os.Stat(claudeMDPath); os.IsNotExist(err) {
-		content := generator.GenerateClaudeMD(m.state)
-		if writeErr := os.WriteFile(claudeMDPath, []byte(content), 0644); writeErr != nil {
-			m.flashMsg = fmt.Sprintf("Failed to write CLAUDE.md: %v", writeErr)
-			m.flashErr = true
-			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-				return clearFlashMsg{}
-			})
+	// Write CLAUDE.md (and AGENTS.md/.cursorrules if enabled) only if they don't exist
+	for _, f := range generator.GenerateAgentFiles(m.state) {
+		path := filepath.Join(m.stateRoot, f.Name)
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			continue
 		}
+		if writeErr := os.WriteFile(path, []byte(f.Content), 0644); writeErr != nil {
+			m.status.Push(components.StatusError, fmt.Sprintf("Failed to write %s: %v", f.Name, writeErr))
+			return m, nil
+		}
+		m.generated = append(m.generated, f.Name)
 	}
 
 	// Write .claude/settings.json (merge with existing)
 	if err := m.writeMCPConfig(); err != nil {
-		m.flashMsg = fmt.Sprintf("Failed to write MCP config: %v", err)
-		m.flashErr = true
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearFlashMsg{}
-		})
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to write MCP config: %v", err))
+		return m, nil
 	}
 
 	// Save state
 	if err := state.Save(m.stateRoot, m.state); err != nil {
-		m.flashMsg = fmt.Sprintf("Failed to save state: %v", err)
-		m.flashErr = true
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearFlashMsg{}
-		})
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to save state: %v", err))
+		return m, nil
 	}
 
-	return m, func() tea.Msg {
+	transition := func() tea.Msg {
 		return TransitionMsg{To: state.PhaseExecution}
 	}
+	if settings.Provider != previousProvider {
+		return m, tea.Batch(func() tea.Msg { return ProviderChangedMsg{Config: settings.Provider} }, transition)
+	}
+	return m, transition
 }
 
 func (m InputsModel) writeMCPConfig() error {
@@ -675,7 +956,7 @@ func (m InputsModel) View() string {
 		sections = append(sections, m.renderMCPServer(srv, active))
 	}
 
-	// Max Turns display (read-only info)
+	// Max Turns fields
 	sections = append(sections, "")
 	turnsLabel := lipgloss.NewStyle().
 		Bold(true).
@@ -683,32 +964,28 @@ func (m InputsModel) View() string {
 		PaddingLeft(2).
 		Render("Max Turns per Task Complexity")
 	sections = append(sections, turnsLabel)
-	turnsInfo := lipgloss.NewStyle().
-		Foreground(Muted).
-		PaddingLeft(4).
-		Render(fmt.Sprintf("Small: %d    Medium: %d    Large: %d",
-			m.maxTurns.Small, m.maxTurns.Medium, m.maxTurns.Large))
-	sections = append(sections, turnsInfo)
-
-	// Flash message
-	if m.flashMsg != "" {
-		sections = append(sections, "")
-		color := Success
-		if m.flashErr {
-			color = Danger
-		}
-		flash := lipgloss.NewStyle().
-			Foreground(color).
-			Bold(true).
-			PaddingLeft(2).
-			Render(m.flashMsg)
-		sections = append(sections, flash)
+	zone, localIdx := m.cursorZone()
+	for i := range m.turnsInputs {
+		sections = append(sections, m.renderMaxTurns(i, zone == zoneMaxTurns && localIdx == i))
+	}
+
+	// Message log, in place of the form
+	if m.status.LogOpen() {
+		sections = append(sections[:2], m.status.LogView(m.width, m.height-4))
+	}
+
+	// Latest message, until dismissed
+	if status := m.status.View(m.width); status != "" {
+		sections = append(sections, "", status)
 	}
 
 	// Footer help
 	sections = append(sections, "")
-	help := HelpStyle.Render(
-		"Tab/Shift+Tab navigate · Enter edit · Space toggle · c confirm · b back · q quit")
+	keys := "Tab/Shift+Tab navigate · Enter edit · Space toggle · m messages · c confirm · b back · q quit"
+	if m.providerType == provider.ProviderOllama {
+		keys = "Tab/Shift+Tab navigate · Enter edit · Space toggle · Ctrl+R recheck Ollama · m messages · c confirm · b back · q quit"
+	}
+	help := HelpStyle.Render(keys)
 	sections = append(sections, help)
 
 	content := strings.Join(sections, "\n")
@@ -731,6 +1008,13 @@ func (m InputsModel) renderField(idx int, f InputField, active bool) string {
 	if active {
 		labelStyle = labelStyle.Foreground(Secondary)
 	}
+	problems := m.fieldErrs.For(f.Key)
+	if len(problems) > 0 {
+		labelStyle = labelStyle.Foreground(Warning)
+		if problems.Blocking() {
+			labelStyle = labelStyle.Foreground(Danger)
+		}
+	}
 	lines = append(lines, labelStyle.Render(f.Label))
 
 	// Value display
@@ -767,15 +1051,45 @@ func (m InputsModel) renderField(idx int, f InputField, active bool) string {
 		lines = append(lines, editorStyle.Render(display))
 	}
 
+	// Validation problems, in place of the help text
+	for _, p := range problems {
+		style, mark := lipgloss.NewStyle().Foreground(Danger).PaddingLeft(4), components.Glyph("✗", "x")+" "
+		if p.Severity == SeverityWarning {
+			style, mark = style.Foreground(Warning), components.Glyph("⚠", "!")+" "
+		}
+		lines = append(lines, style.Render(mark+p.Message))
+	}
+
 	// Help text
-	if f.HelpText != "" {
+	helpText := f.HelpText
+	if f.Key == "claude_model" {
+		helpText = "Enter cycles: " + strings.Join(ModelChoices(m.providerType, m.ollamaModels), " · ") + " — or type a name"
+	}
+	if helpText != "" && len(problems) == 0 {
 		helpStyle := lipgloss.NewStyle().Foreground(Muted).PaddingLeft(4)
-		lines = append(lines, helpStyle.Render(f.HelpText))
+		lines = append(lines, helpStyle.Render(helpText))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// renderMaxTurns renders one max turns field as a single labelled line.
+func (m InputsModel) renderMaxTurns(i int, active bool) string {
+	labelStyle := lipgloss.NewStyle().Foreground(Muted).PaddingLeft(4).Width(12)
+	if active {
+		labelStyle = labelStyle.Foreground(Secondary)
+	}
+	problems := m.fieldErrs.For(MaxTurnsKeys[i])
+	if len(problems) > 0 {
+		labelStyle = labelStyle.Foreground(Danger)
+	}
+	lines := []string{labelStyle.Render(MaxTurnsLabels[i]+":") + m.turnsInputs[i].View()}
+	for _, p := range problems {
+		lines = append(lines, lipgloss.NewStyle().Foreground(Danger).PaddingLeft(6).Render(components.Glyph("✗", "x")+" "+p.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m InputsModel) renderMCPServer(srv MCPServer, active bool) string {
 	checkbox := "[ ]"
 	if srv.Enabled {
@@ -813,9 +1127,9 @@ func (m InputsModel) renderProviderSelection() string {
 		ollamaIndicator = "●"
 	}
 
-	selection := fmt.Sprintf("%s Anthropic (cloud)   %s Ollama (local)", anthropicIndicator, ollamaIndicator)
+	selection := fmt.Sprintf("%s Anthropic (cloud)   %s Ollama (local or remote)", anthropicIndicator, ollamaIndicator)
 	box := lipgloss.NewStyle().
-		BorderStyle(lipgloss.NormalBorder()).
+		BorderStyle(components.BoxBorder(lipgloss.NormalBorder())).
 		BorderForeground(Border).
 		Padding(0, 1).
 		Render(selection)
@@ -823,25 +1137,25 @@ func (m InputsModel) renderProviderSelection() string {
 
 	// Ollama Status
 	if !m.ollamaChecked {
-		status := lipgloss.NewStyle().Foreground(Muted).PaddingLeft(2).Render("⏳ Checking for Ollama...")
+		status := lipgloss.NewStyle().Foreground(Muted).PaddingLeft(2).Render(components.Glyph("⏳", "..") + " Checking for Ollama...")
 		lines = append(lines, status)
 	} else if m.ollamaError != "" {
-		status := lipgloss.NewStyle().Foreground(Danger).PaddingLeft(2).Render(fmt.Sprintf("⚠ Ollama not detected: %s", m.ollamaError))
+		status := lipgloss.NewStyle().Foreground(Danger).PaddingLeft(2).Render(fmt.Sprintf("%s Ollama not detected: %s", components.Glyph("⚠", "!"), m.ollamaError))
 		lines = append(lines, status)
 	} else {
 		modelCount := len(m.ollamaModels)
 		status := lipgloss.NewStyle().Foreground(Success).PaddingLeft(2).Render(
-			fmt.Sprintf("✅ Ollama detected (%d model%s available)", modelCount, pluralize(modelCount)))
+			fmt.Sprintf("%s Ollama detected (%d model%s available)", components.Glyph("✅", "[x]"), modelCount, pluralize(modelCount)))
 		lines = append(lines, status)
 
 		// Show Ollama URL and model selection when Ollama is selected
 		if m.providerType == provider.ProviderOllama {
-			urlLine := lipgloss.NewStyle().PaddingLeft(4).Render(fmt.Sprintf("URL: %s", m.ollamaURL))
+			urlLine := lipgloss.NewStyle().PaddingLeft(4).Render(fmt.Sprintf("URL: %s", m.providerConfig(provider.ProviderOllama).WithEnv().OllamaURL))
 			lines = append(lines, urlLine)
 
 			if len(m.ollamaModels) > 0 {
 				modelsLine := lipgloss.NewStyle().Foreground(Muted).PaddingLeft(4).Render(
-					fmt.Sprintf("💡 Recommended: 64K+ context for best results"))
+					components.Glyph("💡", "*") + " Recommended: 64K+ context for best results")
 				lines = append(lines, modelsLine)
 			}
 		}
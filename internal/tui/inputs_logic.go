@@ -2,11 +2,24 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/manasm11/forge/internal/docs"
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/generator"
+	"github.com/manasm11/forge/internal/platform"
 	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/schedule"
+	"github.com/manasm11/forge/internal/scanner"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // InputField represents a single form field in the inputs phase.
@@ -63,10 +76,38 @@ func BuildProviderConfigFromFields(fields map[string]string) provider.Config {
 		if cfg.OllamaURL == "" {
 			cfg.OllamaURL = provider.DefaultOllamaURL()
 		}
+		if auth := strings.ToLower(fields["ollama_auth"]); auth != "none" {
+			cfg.OllamaAuth = auth
+		}
+		if env := strings.TrimPrefix(fields["ollama_token_env"], "$"); env != provider.EnvOllamaToken {
+			cfg.OllamaTokenEnv = env
+		}
+		cfg.OllamaCACert = fields["ollama_ca_cert"]
+		cfg.OllamaInsecure = fields["ollama_insecure"] == "true"
 	}
 	return cfg
 }
 
+// ollamaAuthChoices are the values of the ollama_auth field.
+var ollamaAuthChoices = []string{"none", provider.OllamaAuthBearer, provider.OllamaAuthBasic}
+
+// ValidateOllamaAccess checks the parts of an Ollama config that depend on
+// the machine rather than the form: the CA file and the secret's variable.
+// A missing secret only warns, since it may be exported before running.
+func ValidateOllamaAccess(cfg provider.Config) FieldErrors {
+	var errs FieldErrors
+	if cfg.OllamaCACert != "" {
+		if _, err := os.Stat(cfg.OllamaCACert); err != nil {
+			errs = append(errs, fieldErr("ollama_ca_cert", "Ollama CA Certificate: %v", err))
+		}
+	}
+	if cfg.OllamaAuth != provider.OllamaAuthNone && os.Getenv(cfg.TokenEnv()) == "" {
+		errs = append(errs, FieldError{Field: "ollama_token_env", Severity: SeverityWarning,
+			Message: fmt.Sprintf("$%s is empty — Ollama %s auth will fail until it is set", cfg.TokenEnv(), cfg.OllamaAuth)})
+	}
+	return errs
+}
+
 // OllamaModelNames extracts display-formatted model names from an OllamaStatus.
 func OllamaModelNames(status *provider.OllamaStatus) []string {
 	if status == nil || !status.Available {
@@ -79,6 +120,80 @@ func OllamaModelNames(status *provider.OllamaStatus) []string {
 	return names
 }
 
+// ModelChoices returns the models the model field cycles through for a
+// provider: the models the Ollama server reported, or the recommended ones
+// until it has.
+func ModelChoices(pt provider.ProviderType, ollamaModels []string) []string {
+	if pt == provider.ProviderOllama && len(ollamaModels) > 0 {
+		return ollamaModels
+	}
+	return provider.RecommendedModels(pt)
+}
+
+// NextModel returns the choice after current, wrapping around, or the
+// first choice when current is not one of them.
+func NextModel(current string, choices []string) string {
+	if len(choices) == 0 {
+		return current
+	}
+	i := slices.Index(choices, current)
+	return choices[(i+1)%len(choices)]
+}
+
+// ModelForProvider returns the model to use after switching to provider
+// pt: current if it is one of pt's choices (or a full claude-* name for
+// Anthropic), otherwise pt's first choice, so a switch never leaves e.g.
+// "sonnet" configured for Ollama.
+func ModelForProvider(current string, pt provider.ProviderType, choices []string) string {
+	if slices.Contains(choices, current) || len(choices) == 0 {
+		return current
+	}
+	if pt == provider.ProviderAnthropic && strings.HasPrefix(current, "claude-") {
+		return current
+	}
+	return choices[0]
+}
+
+// ExecutionRequirements returns what running the tasks needs from the
+// model: tool use, JSON output and room for the largest first-attempt
+// prompt among the tasks still to run.
+func ExecutionRequirements(contextContent string, tasks []state.Task, settings *state.Settings) provider.Requirements {
+	largest := 0
+	for _, t := range tasks {
+		if t.Status == state.TaskDone || t.Status == state.TaskCancelled || t.Status == state.TaskSkipped {
+			continue
+		}
+		largest = max(largest, len(executor.TaskPrompt(contextContent, t, settings)))
+	}
+	return provider.Requirements{
+		Tools:        true,
+		JSON:         true,
+		PromptTokens: EstimateTokens(len(executor.BuildExecutionSystemPrompt()) + largest),
+	}
+}
+
+// CapabilityFieldErrors reports a model's capability problems on the model
+// field. The first one names the suggested alternative, or for Ollama the
+// recommended models to pull when no installed one will do.
+func CapabilityFieldErrors(problems []provider.CapabilityProblem, suggestion string, pt provider.ProviderType) FieldErrors {
+	var errs FieldErrors
+	for _, p := range problems {
+		sev := SeverityWarning
+		if p.Blocking {
+			sev = SeverityError
+		}
+		errs = append(errs, FieldError{Field: "claude_model", Message: "Model: " + p.Message, Severity: sev})
+	}
+	switch {
+	case len(errs) == 0:
+	case suggestion != "":
+		errs[0].Message += fmt.Sprintf(" — try %s", suggestion)
+	case pt == provider.ProviderOllama:
+		errs[0].Message += " — pull one of: " + strings.Join(provider.RecommendedModels(pt), ", ")
+	}
+	return errs
+}
+
 // BuildSettingsFromFieldsWithProvider is the updated version of
 // BuildSettingsFromFields that also includes the provider config.
 // The existing BuildSettingsFromFields should call this internally
@@ -96,64 +211,67 @@ func BuildSettingsFromFieldsWithProvider(
 	return settings
 }
 
-// InferTestCommand guesses the test command from the project snapshot.
+// InferTestCommand guesses the test command from the project snapshot
+// using the language plugin registered for snapshot.Language.
 func InferTestCommand(snapshot *state.ProjectSnapshot) string {
 	if snapshot == nil {
 		return ""
 	}
-	// Check frameworks first for more specific commands
-	for _, fw := range snapshot.Frameworks {
-		switch fw {
-		case "Django":
-			return "python manage.py test"
-		case "Flutter":
-			return "flutter test"
-		}
-	}
-	switch snapshot.Language {
-	case "Go":
-		return "go test ./..."
-	case "JavaScript", "TypeScript":
-		return "npm test"
-	case "Python":
-		return "pytest"
-	case "Rust":
-		return "cargo test"
-	case "Java", "Kotlin":
-		return "mvn test"
-	case "Ruby":
-		return "bundle exec rspec"
-	case "Dart":
-		return "dart test"
-	case "Elixir":
-		return "mix test"
-	default:
-		return ""
+	if l := scanner.LanguageFor(snapshot.Language); l != nil {
+		return l.TestCmd(snapshot)
 	}
+	return ""
 }
 
-// InferBuildCommand guesses the build command from the project snapshot.
+// InferBuildCommand guesses the build command from the project snapshot
+// using the language plugin registered for snapshot.Language.
 func InferBuildCommand(snapshot *state.ProjectSnapshot) string {
 	if snapshot == nil {
 		return ""
 	}
-	for _, fw := range snapshot.Frameworks {
-		if fw == "Flutter" {
-			return "flutter build apk"
-		}
-	}
-	switch snapshot.Language {
-	case "Go":
-		return "go build ./..."
-	case "JavaScript", "TypeScript":
-		return "npm run build"
-	case "Rust":
-		return "cargo build"
-	case "Java", "Kotlin":
-		return "mvn package"
-	default:
-		return ""
+	if l := scanner.LanguageFor(snapshot.Language); l != nil {
+		return l.BuildCmd(snapshot)
+	}
+	return ""
+}
+
+func snapshotRepos(snapshot *state.ProjectSnapshot) []string {
+	if snapshot == nil {
+		return nil
 	}
+	return snapshot.Repos
+}
+
+// ResolveRepos fills in what can be detected about each workspace
+// repository under root — base branch, origin remote, test and build
+// commands — keeping whatever was already configured for the same path.
+func ResolveRepos(root string, repos, previous []state.WorkspaceRepo) []state.WorkspaceRepo {
+	out := make([]state.WorkspaceRepo, len(repos))
+	for i, r := range repos {
+		for _, p := range previous {
+			if p.Path == r.Path {
+				r = p
+			}
+		}
+		dir := filepath.Join(root, filepath.FromSlash(r.Path))
+		if r.BaseBranch == "" {
+			r.BaseBranch = scanner.DetectBaseBranch(dir)
+		}
+		if r.RemoteURL == "" {
+			r.RemoteURL = scanner.RemoteURL(dir)
+		}
+		if r.TestCommand == "" || r.BuildCommand == "" {
+			snap := scanner.ScanCached(dir)
+			if r.TestCommand == "" {
+				r.TestCommand = InferTestCommand(&snap)
+			}
+			if r.BuildCommand == "" {
+				r.BuildCommand = InferBuildCommand(&snap)
+			}
+		}
+		out[i] = r
+	}
+	return out
 }
 
 // DefaultInputFields returns the initial form fields with smart defaults.
@@ -175,6 +293,158 @@ func DefaultInputFields(snapshot *state.ProjectSnapshot) []InputField {
 			FieldType: FieldText,
 			HelpText:  "Command to verify build succeeds",
 		},
+		{
+			Key:       "lint_command",
+			Label:     "Lint Command (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Run with build and tests in the final verification pass",
+		},
+		{
+			Key:       "start_at",
+			Label:     "Start At (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Delay execution, e.g. 02:00 or +3h — empty starts immediately",
+		},
+		{
+			Key:       "max_budget",
+			Label:     "Max Budget per Run (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Pause once reached, e.g. $5, 2m tokens, or both — empty is unlimited",
+		},
+		{
+			Key:       "max_run_duration",
+			Label:     "Max Run Duration (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Pause between tasks once a run has lasted this long, e.g. 6h — empty is unlimited",
+		},
+		{
+			Key:       "quiet_hours",
+			Label:     "Quiet Hours (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "No task starts in this daily window, e.g. 09:00-18:00 or 23:00-07:00",
+		},
+		{
+			Key:       "max_change_mb",
+			Label:     "Max Change Size (MB)",
+			Default:   "100",
+			Required:  false,
+			FieldType: FieldNumber,
+			HelpText:  "Pause for review instead of committing a larger task change — 0 disables",
+		},
+		{
+			Key:       "warm_start_files",
+			Label:     "Warm-Start Files",
+			Default:   "5",
+			Required:  false,
+			FieldType: FieldNumber,
+			HelpText:  "Inline this many files related to each task into its prompt (by path and imports) — 0 disables",
+		},
+		{
+			Key:       "code_index",
+			Label:     "Code Search Index",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Embed the code with a local Ollama model into .forge/index/ and add the closest snippets to each task",
+		},
+		{
+			Key:       "embedding_model",
+			Label:     "Embedding Model (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Ollama model for the code search index — empty uses " + provider.DefaultEmbeddingModel,
+		},
+		{
+			Key:       "max_file_mb",
+			Label:     "Max File Size (MB)",
+			Default:   "10",
+			Required:  false,
+			FieldType: FieldNumber,
+			HelpText:  "Tasks committing a larger single file are retried — 0 disables",
+		},
+		{
+			Key:       "scan_secrets",
+			Label:     "Block Committed Secrets",
+			Default:   "true",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Retry tasks whose changes contain credential-looking values",
+		},
+		{
+			Key:       "allow_workflow_changes",
+			Label:     "Allow CI Workflow Changes",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Let tasks modify files under .github/workflows/",
+		},
+		{
+			Key:       "shell",
+			Label:     "Shell (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "sh, bash, zsh, pwsh or cmd — empty uses sh (cmd on Windows)",
+		},
+		{
+			Key:       "alert",
+			Label:     "Alert",
+			Default:   "off",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "off, bell or sound — get your attention when a run waits for you",
+		},
+		{
+			Key:       "status_style",
+			Label:     "Status Style",
+			Default:   string(components.StatusEmoji),
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "emoji, badges ([DONE], [FAIL]), symbols (✓, ✗) or ascii ([x], [!]) — how task statuses are drawn",
+		},
+		{
+			Key:       "accessible",
+			Label:     "Accessible Mode",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Linear screen-reader friendly output: no box drawing, every status change logged as a sentence",
+		},
+		{
+			Key:       "ascii_mode",
+			Label:     "ASCII Mode",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Draw with plain ASCII (#, -, [x]) when block characters and emoji show as garbage",
+		},
+		{
+			Key:       "workspace_repos",
+			Label:     "Workspace Repos (optional)",
+			Default:   strings.Join(snapshotRepos(snapshot), ", "),
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Nested git repos tasks can work in, comma separated — e.g. backend, frontend",
+		},
+		{
+			Key:       "sparse_paths",
+			Label:     "Sparse Checkout Paths (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Only check out these directories in a large monorepo, comma separated — e.g. services/billing, libs/common",
+		},
 		{
 			Key:       "branch_pattern",
 			Label:     "Branch Pattern",
@@ -215,14 +485,230 @@ func DefaultInputFields(snapshot *state.ProjectSnapshot) []InputField {
 			FieldType: FieldToggle,
 			HelpText:  "Create PRs automatically after pushing",
 		},
+		{
+			Key:       "draft_prs",
+			Label:     "Open PRs as Drafts",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Open PRs as drafts; mark them ready once the plan is done and verification passes",
+		},
+		{
+			Key:       "file_issues",
+			Label:     "File Issues for Failed Tasks",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Open a forge-failed GitHub issue when a task exhausts its retries",
+		},
+		{
+			Key:       "publish_checks",
+			Label:     "Publish GitHub Checks",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Report each task's result as a check run on its pushed commit, shown in the PR",
+		},
+		{
+			Key:       "wait_for_ci",
+			Label:     "Wait for CI",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "After pushing a task, wait for GitHub Actions and retry the task if CI fails",
+		},
+		{
+			Key:       "skip_hooks",
+			Label:     "Skip Git Hooks",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Commit with --no-verify; otherwise hook failures are sent back to Claude",
+		},
+		{
+			Key:       "stash_dirty",
+			Label:     "Stash Uncommitted Changes",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Stash your uncommitted changes when a run starts instead of refusing to run",
+		},
+		{
+			Key:       "front_load_risk",
+			Label:     "Run Risky Tasks First",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Start the riskiest ready task first (complexity, dependents, protected paths, integrations)",
+		},
+		{
+			Key:       "critical_path_first",
+			Label:     "Run Long Chains First",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Start the ready task that heads the longest chain of remaining work instead of following plan order",
+		},
+		{
+			Key:       "adaptive_turns",
+			Label:     "Adapt Max Turns",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Set each task's max turns from what similar completed tasks needed (half to twice the limits below)",
+		},
+		{
+			Key:       "base_drift",
+			Label:     "Base Branch Drift",
+			Default:   "off",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Before each task: off, branch (fast-forward the base from its upstream) or rebase (also rebase local base commits and task branches onto it, rewriting their SHAs)",
+		},
+		{
+			Key:       "sign_off",
+			Label:     "Sign Off Commits (DCO)",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Add a Signed-off-by trailer for your git identity to every commit",
+		},
+		{
+			Key:       "co_author",
+			Label:     "Co-author Trailer",
+			Default:   "off",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "off, forge, model, or Name <email> — credited with Co-authored-by on every commit",
+		},
+		{
+			Key:       "commit_identity",
+			Label:     "Commit Identity (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Author forge's commits as Name <email>, e.g. Forge Bot <forge@team.dev> — empty uses your git config",
+		},
+		{
+			Key:       "changelog",
+			Label:     "Changelog",
+			Default:   "off",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "off, draft (.forge/release-notes.md) or commit (CHANGELOG.md) when the plan completes",
+		},
+		{
+			Key:       "code_review",
+			Label:     "Review Each Task",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "A reviewer model checks the diff before commit; blocking findings trigger a fix",
+		},
+		{
+			Key:       "review_model",
+			Label:     "Review Model (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Model for the reviewer — empty uses the execution model",
+		},
+		{
+			Key:       "checklist",
+			Label:     "Self-review Checklist (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Items Claude must confirm per task, ; separated — e.g. " + strings.Join(executor.SuggestedChecklist, "; "),
+		},
+		{
+			Key:       "agents_md",
+			Label:     "Generate AGENTS.md",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Share project conventions with other agentic tools",
+		},
+		{
+			Key:       "cursor_rules",
+			Label:     "Generate .cursorrules",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Share project conventions with Cursor",
+		},
 		{
 			Key:       "claude_model",
-			Label:     "Claude Model for Execution",
+			Label:     "Model for Execution",
 			Default:   "sonnet",
 			Required:  true,
 			FieldType: FieldText,
 			HelpText:  "Model name: sonnet, opus, etc.",
 		},
+		{
+			Key:       "ollama_url",
+			Label:     "Ollama URL",
+			Default:   provider.DefaultOllamaURL(),
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Ollama only — a remote server works too; $" + provider.EnvOllamaURL + " or $OLLAMA_HOST set the default",
+		},
+		{
+			Key:       "ollama_auth",
+			Label:     "Ollama Auth",
+			Default:   "none",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Ollama only: " + strings.Join(ollamaAuthChoices, ", ") + " — for a server behind an authenticating proxy",
+		},
+		{
+			Key:       "ollama_token_env",
+			Label:     "Ollama Token Variable",
+			Default:   provider.EnvOllamaToken,
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Environment variable holding the token, or user:password for basic auth — never saved",
+		},
+		{
+			Key:       "ollama_ca_cert",
+			Label:     "Ollama CA Certificate (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "PEM file to trust for a server with a private certificate",
+		},
+		{
+			Key:       "ollama_insecure",
+			Label:     "Skip Ollama TLS Verification",
+			Default:   "false",
+			Required:  false,
+			FieldType: FieldToggle,
+			HelpText:  "Accept any certificate from the Ollama server — only for testing",
+		},
+		{
+			Key:       "context_urls",
+			Label:     "Context URLs (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Design docs or API specs to build against, comma-separated — fetched and cached",
+		},
+		{
+			Key:       "webhook_url",
+			Label:     "Event Webhook URL (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Every execution event is POSTed here as JSON while tasks run",
+		},
+		{
+			Key:       "database_url_env",
+			Label:     "Dev Database URL Variable (optional)",
+			Default:   "",
+			Required:  false,
+			FieldType: FieldText,
+			HelpText:  "Env var holding a dev database URL, e.g. DATABASE_URL — its tables inform planning (else migrations are read)",
+		},
 		{
 			Key:       "extra_context",
 			Label:     "Additional Context (optional)",
@@ -259,10 +745,47 @@ func DefaultMaxTurns() MaxTurnsConfig {
 	return MaxTurnsConfig{Small: 20, Medium: 35, Large: 50}
 }
 
+// MaxTurnsKeys are the FieldError keys of the small, medium and large max
+// turns fields.
+var MaxTurnsKeys = [3]string{"max_turns_small", "max_turns_medium", "max_turns_large"}
+
+// MaxTurnsLabels are the labels of the max turns fields.
+var MaxTurnsLabels = [3]string{"Small", "Medium", "Large"}
+
+// Values returns the small, medium and large limits in order.
+func (c MaxTurnsConfig) Values() [3]int {
+	return [3]int{c.Small, c.Medium, c.Large}
+}
+
+// ParseMaxTurns validates the small, medium and large max turns fields:
+// each must be a positive number, and a bigger task must get more turns
+// than a smaller one. Problems are keyed by MaxTurnsKeys.
+func ParseMaxTurns(values [3]string) (MaxTurnsConfig, FieldErrors) {
+	var n [3]int
+	var errs FieldErrors
+	for i, v := range values {
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || parsed <= 0 {
+			errs = append(errs, fieldErr(MaxTurnsKeys[i], "%s max turns must be a positive number", MaxTurnsLabels[i]))
+			continue
+		}
+		n[i] = parsed
+	}
+	for i := 1; i < len(n); i++ {
+		if n[i-1] > 0 && n[i] > 0 && n[i] <= n[i-1] {
+			errs = append(errs, fieldErr(MaxTurnsKeys[i], "%s max turns must be more than %s (%d)",
+				MaxTurnsLabels[i], strings.ToLower(MaxTurnsLabels[i-1]), n[i-1]))
+		}
+	}
+	return MaxTurnsConfig{Small: n[0], Medium: n[1], Large: n[2]}, errs
+}
+
 // ValidateSettings checks that all required fields have values
 // and that values are valid (e.g., max retries is a positive number).
-func ValidateSettings(fields []InputField) []string {
-	var errs []string
+// Each problem names its field; warnings flag values that are valid but
+// probably unintended.
+func ValidateSettings(fields []InputField) FieldErrors {
+	var errs FieldErrors
 	for _, f := range fields {
 		val := f.Value
 		if val == "" {
@@ -271,7 +794,7 @@ func ValidateSettings(fields []InputField) []string {
 
 		// Required check
 		if f.Required && val == "" {
-			errs = append(errs, fmt.Sprintf("%s is required", f.Label))
+			errs = append(errs, fieldErr(f.Key, "%s is required", f.Label))
 			continue
 		}
 
@@ -279,20 +802,152 @@ func ValidateSettings(fields []InputField) []string {
 		if f.FieldType == FieldNumber && val != "" {
 			n, err := strconv.Atoi(val)
 			if err != nil {
-				errs = append(errs, fmt.Sprintf("%s must be a non-negative number", f.Label))
+				errs = append(errs, fieldErr(f.Key, "%s must be a non-negative number", f.Label))
 			} else if n < 0 {
-				errs = append(errs, fmt.Sprintf("%s must be a non-negative number", f.Label))
+				errs = append(errs, fieldErr(f.Key, "%s must be a non-negative number", f.Label))
+			} else if f.Key == "max_retries" && n > maxSensibleRetries {
+				errs = append(errs, FieldError{Field: f.Key, Severity: SeverityWarning,
+					Message: fmt.Sprintf("%s above %d can spend a lot on a task that keeps failing", f.Label, maxSensibleRetries)})
+			}
+		}
+
+		// Shell must be one forge knows how to invoke
+		if f.Key == "shell" && val != "" && !platform.ValidShell(val) {
+			errs = append(errs, fieldErr(f.Key, "Shell must be one of: %s", strings.Join(platform.Shells, ", ")))
+		}
+
+		// Alert must be a kind forge knows how to raise
+		if f.Key == "alert" && !platform.ValidAlert(val) {
+			errs = append(errs, fieldErr(f.Key, "Alert must be one of: %s", strings.Join(platform.Alerts, ", ")))
+		}
+
+		if f.Key == "status_style" && !components.ValidStatusStyle(val) {
+			errs = append(errs, fieldErr(f.Key, "Status style must be one of: %s", strings.Join(components.StatusStyles, ", ")))
+		}
+
+		// Workspace repos must stay inside the project
+		if f.Key == "workspace_repos" {
+			for _, p := range SplitURLs(val) {
+				if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(filepath.ToSlash(p), "../") {
+					errs = append(errs, fieldErr(f.Key, "Workspace repo %q must be a path inside the project", p))
+				}
+			}
+		}
+
+		// Sparse checkout takes directories inside the project, not patterns
+		if f.Key == "sparse_paths" {
+			for _, p := range SplitURLs(val) {
+				if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(filepath.ToSlash(p), "../") || strings.ContainsAny(p, "*?[!") {
+					errs = append(errs, fieldErr(f.Key, "Sparse checkout path %q must be a directory inside the project", p))
+				}
+			}
+		}
+
+		// Co-author must be a built-in identity or Name <email>
+		if f.Key == "co_author" && !executor.ValidCoAuthor(val) {
+			errs = append(errs, fieldErr(f.Key, "Co-author Trailer must be off, forge, model, or Name <email>"))
+		}
+
+		// Commit identity must be Name <email>
+		if f.Key == "commit_identity" && val != "" {
+			if _, _, ok := executor.ParseIdentity(val); !ok {
+				errs = append(errs, fieldErr(f.Key, "Commit Identity must look like Name <email>"))
+			}
+		}
+
+		// Changelog must be a mode forge knows
+		if f.Key == "changelog" && !generator.ValidChangelogMode(val) {
+			errs = append(errs, fieldErr(f.Key, "Changelog must be one of: %s", strings.Join(generator.ChangelogModes, ", ")))
+		}
+
+		if f.Key == "base_drift" && !executor.ValidDriftPolicy(val) {
+			errs = append(errs, fieldErr(f.Key, "Base Branch Drift must be one of: %s", strings.Join(executor.DriftPolicies, ", ")))
+		}
+
+		// Scheduled start must be a time forge can resolve
+		if f.Key == "start_at" && val != "" {
+			if _, err := schedule.Parse(val, time.Now()); err != nil {
+				errs = append(errs, fieldErr(f.Key, "Start At: %v", err))
 			}
 		}
 
+		// Budget needs explicit units so "5" is never misread
+		if f.Key == "max_budget" && val != "" {
+			if _, err := provider.ParseBudget(val); err != nil {
+				errs = append(errs, fieldErr(f.Key, "Max Budget: %v", err))
+			}
+		}
+
+		if f.Key == "max_run_duration" && val != "" {
+			if d, err := time.ParseDuration(val); err != nil || d <= 0 {
+				errs = append(errs, fieldErr(f.Key, "Max Run Duration: invalid duration %q (use e.g. 6h or 90m)", val))
+			}
+		}
+
+		if f.Key == "quiet_hours" && val != "" {
+			if _, err := schedule.ParseWindow(val); err != nil {
+				errs = append(errs, fieldErr(f.Key, "Quiet Hours: %v", err))
+			}
+		}
+
+		if f.Key == "context_urls" {
+			for _, u := range SplitURLs(val) {
+				if !docs.ValidURL(u) {
+					errs = append(errs, fieldErr(f.Key, "Context URLs: %q is not an http(s) URL", u))
+				}
+			}
+		}
+
+		if f.Key == "webhook_url" && val != "" && !docs.ValidURL(val) {
+			errs = append(errs, fieldErr(f.Key, "Event Webhook URL: %q is not an http(s) URL", val))
+		}
+
+		if f.Key == "ollama_url" && val != "" && !docs.ValidURL(val) {
+			errs = append(errs, fieldErr(f.Key, "Ollama URL: %q is not an http(s) URL", val))
+		}
+
+		if f.Key == "ollama_auth" && val != "" && !slices.Contains(ollamaAuthChoices, strings.ToLower(val)) {
+			errs = append(errs, fieldErr(f.Key, "Ollama Auth must be one of: %s", strings.Join(ollamaAuthChoices, ", ")))
+		}
+
+		if f.Key == "ollama_token_env" && val != "" && !envNameRe.MatchString(val) {
+			errs = append(errs, fieldErr(f.Key, "Ollama Token Variable: %q is not an environment variable name", val))
+		}
+
+		if f.Key == "database_url_env" && val != "" && !envNameRe.MatchString(val) {
+			errs = append(errs, fieldErr(f.Key, "Dev Database URL Variable: %q is not an environment variable name", val))
+		}
+
 		// Branch pattern must contain {id}
 		if f.Key == "branch_pattern" && val != "" && !strings.Contains(val, "{id}") {
-			errs = append(errs, "Branch Pattern must contain {id} placeholder")
+			errs = append(errs, fieldErr(f.Key, "Branch Pattern must contain {id} placeholder"))
 		}
 	}
 	return errs
 }
 
+// maxSensibleRetries is the retry count above which ValidateSettings warns.
+const maxSensibleRetries = 10
+
+// envNameRe matches an environment variable name, optionally written $NAME.
+var envNameRe = regexp.MustCompile(`^\$?[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SplitURLs splits a comma- or space-separated list of URLs.
+func SplitURLs(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+}
+
+// SplitChecklist splits a semicolon-separated list of checklist items.
+func SplitChecklist(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // BuildSettingsFromFields converts form fields into a state.Settings struct.
 func BuildSettingsFromFields(fields []InputField, mcpServers []MCPServer, maxTurns MaxTurnsConfig) *state.Settings {
 	s := &state.Settings{}
@@ -308,16 +963,76 @@ func BuildSettingsFromFields(fields []InputField, mcpServers []MCPServer, maxTur
 
 	s.TestCommand = fieldMap["test_command"]
 	s.BuildCommand = fieldMap["build_command"]
+	s.LintCommand = fieldMap["lint_command"]
+	s.MaxBudget, _ = provider.ParseBudget(fieldMap["max_budget"])
+	s.MaxRunDuration = fieldMap["max_run_duration"]
+	s.QuietHours = fieldMap["quiet_hours"]
+	s.Shell = fieldMap["shell"]
+	if alert := fieldMap["alert"]; alert != "off" {
+		s.Alert = alert
+	}
+	if style := fieldMap["status_style"]; style != string(components.StatusEmoji) {
+		s.StatusStyle = style
+	}
+	s.Accessible = fieldMap["accessible"] == "true"
+	s.ASCIIMode = fieldMap["ascii_mode"] == "true"
 	s.BranchPattern = fieldMap["branch_pattern"]
 	s.BaseBranch = fieldMap["base_branch"]
 	s.RemoteURL = fieldMap["remote_url"]
 	s.AutoPR = fieldMap["auto_pr"] == "true"
+	s.DraftPRs = fieldMap["draft_prs"] == "true"
+	s.FileIssues = fieldMap["file_issues"] == "true"
+	if changelog := fieldMap["changelog"]; changelog != "off" {
+		s.Changelog = changelog
+	}
+	s.CodeReview = fieldMap["code_review"] == "true"
+	s.SkipHooks = fieldMap["skip_hooks"] == "true"
+	s.StashDirty = fieldMap["stash_dirty"] == "true"
+	s.FrontLoadRisk = fieldMap["front_load_risk"] == "true"
+	s.CriticalPathFirst = fieldMap["critical_path_first"] == "true"
+	s.AdaptiveTurns = fieldMap["adaptive_turns"] == "true"
+	if drift := fieldMap["base_drift"]; drift != "off" {
+		s.BaseDrift = drift
+	}
+	s.PublishChecks = fieldMap["publish_checks"] == "true"
+	s.WaitForCI = fieldMap["wait_for_ci"] == "true"
+	s.SignOff = fieldMap["sign_off"] == "true"
+	s.CommitIdentity = strings.TrimSpace(fieldMap["commit_identity"])
+	if v := fieldMap["co_author"]; v != "off" {
+		s.CoAuthor = v
+	}
+	s.ReviewModel = fieldMap["review_model"]
+	s.Checklist = SplitChecklist(fieldMap["checklist"])
+	for _, p := range SplitURLs(fieldMap["workspace_repos"]) {
+		s.Repos = append(s.Repos, state.WorkspaceRepo{Path: strings.TrimSuffix(filepath.ToSlash(p), "/")})
+	}
+	for _, p := range SplitURLs(fieldMap["sparse_paths"]) {
+		s.SparsePaths = append(s.SparsePaths, strings.Trim(filepath.ToSlash(p), "/"))
+	}
+	s.GenerateAgentsMD = fieldMap["agents_md"] == "true"
+	s.GenerateCursorRules = fieldMap["cursor_rules"] == "true"
 	s.ClaudeModel = fieldMap["claude_model"]
 	s.ExtraContext = fieldMap["extra_context"]
+	s.ContextURLs = SplitURLs(fieldMap["context_urls"])
+	s.WebhookURL = strings.TrimSpace(fieldMap["webhook_url"])
+	s.DatabaseURLEnv = strings.TrimPrefix(fieldMap["database_url_env"], "$")
 
 	if v, err := strconv.Atoi(fieldMap["max_retries"]); err == nil {
 		s.MaxRetries = v
 	}
+	if v, err := strconv.Atoi(fieldMap["max_change_mb"]); err == nil {
+		s.MaxChangeMB = v
+	}
+	if v, err := strconv.Atoi(fieldMap["warm_start_files"]); err == nil {
+		s.WarmStartFiles = v
+	}
+	s.CodeIndex = fieldMap["code_index"] == "true"
+	s.EmbeddingModel = strings.TrimSpace(fieldMap["embedding_model"])
+	s.CommitPolicy.ScanSecrets = fieldMap["scan_secrets"] == "true"
+	s.CommitPolicy.ProtectWorkflows = fieldMap["allow_workflow_changes"] != "true"
+	if v, err := strconv.Atoi(fieldMap["max_file_mb"]); err == nil {
+		s.CommitPolicy.MaxFileMB = v
+	}
 
 	s.MaxTurns = state.MaxTurnsConfig{
 		Small:  maxTurns.Small,
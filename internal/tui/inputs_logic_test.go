@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/manasm11/forge/internal/provider"
@@ -69,6 +73,30 @@ func TestInferTestCommand(t *testing.T) {
 			},
 			want: "flutter test",
 		},
+		{
+			name: "TypeScript with vitest",
+			snapshot: &state.ProjectSnapshot{
+				Language:       "TypeScript",
+				TestFrameworks: []string{"vitest"},
+			},
+			want: "npx vitest run",
+		},
+		{
+			name: "JavaScript with jest",
+			snapshot: &state.ProjectSnapshot{
+				Language:       "JavaScript",
+				TestFrameworks: []string{"jest"},
+			},
+			want: "npx jest",
+		},
+		{
+			name: "Go with testify keeps go test",
+			snapshot: &state.ProjectSnapshot{
+				Language:       "Go",
+				TestFrameworks: []string{"testify"},
+			},
+			want: "go test ./...",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -255,6 +283,155 @@ func TestValidateSettings(t *testing.T) {
 			},
 			wantErrors: 1,
 		},
+		{
+			name: "known shell",
+			fields: []InputField{
+				{Key: "shell", Value: "pwsh"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "unknown shell",
+			fields: []InputField{
+				{Key: "shell", Value: "fish"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "valid run limits",
+			fields: []InputField{
+				{Key: "max_run_duration", Value: "6h"},
+				{Key: "quiet_hours", Value: "23:00-07:00"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "invalid run limits",
+			fields: []InputField{
+				{Key: "max_run_duration", Value: "6"},
+				{Key: "quiet_hours", Value: "nights"},
+			},
+			wantErrors: 2,
+		},
+		{
+			name: "context URLs",
+			fields: []InputField{
+				{Key: "context_urls", Value: "https://example.com/api.yaml, http://wiki.local/design"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "context URL without scheme",
+			fields: []InputField{
+				{Key: "context_urls", Value: "https://example.com/api.yaml example.com/design"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "database URL variable",
+			fields: []InputField{
+				{Key: "database_url_env", Value: "$DATABASE_URL"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "database URL instead of variable",
+			fields: []InputField{
+				{Key: "database_url_env", Value: "postgres://localhost/app"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "known alert",
+			fields: []InputField{
+				{Key: "alert", Value: "bell"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "unknown alert",
+			fields: []InputField{
+				{Key: "alert", Value: "siren"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "unknown status style",
+			fields: []InputField{
+				{Key: "status_style", Value: "colors"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "workspace repo outside the project",
+			fields: []InputField{
+				{Key: "workspace_repos", Value: "backend, ../frontend"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "webhook URL without a scheme",
+			fields: []InputField{
+				{Key: "webhook_url", Value: "hooks.example.com/forge"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "co-author without an email",
+			fields: []InputField{
+				{Key: "co_author", Value: "Forge Agent"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "sparse checkout pattern",
+			fields: []InputField{
+				{Key: "sparse_paths", Value: "services/billing, services/*"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "unknown base drift policy",
+			fields: []InputField{
+				{Key: "base_drift", Value: "merge"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "unknown changelog mode",
+			fields: []InputField{
+				{Key: "changelog", Value: "publish"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "valid start time",
+			fields: []InputField{
+				{Key: "start_at", Value: "02:00"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "invalid start time",
+			fields: []InputField{
+				{Key: "start_at", Value: "tonight"},
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "valid budget",
+			fields: []InputField{
+				{Key: "max_budget", Value: "$5, 2m tokens"},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "budget without unit",
+			fields: []InputField{
+				{Key: "max_budget", Value: "5"},
+			},
+			wantErrors: 1,
+		},
 		{
 			name: "zero retries is valid",
 			fields: []InputField{
@@ -288,6 +465,27 @@ func TestValidateSettings(t *testing.T) {
 	}
 }
 
+func TestValidateSettings_Fields(t *testing.T) {
+	t.Parallel()
+	errs := ValidateSettings([]InputField{
+		{Key: "test_command", Label: "Test Command", Required: true},
+		{Key: "max_retries", Label: "Max Retries", Value: "25", FieldType: FieldNumber},
+		{Key: "branch_pattern", Label: "Branch Pattern", Value: "forge/task"},
+	})
+	if len(errs) != 3 {
+		t.Fatalf("errors = %v, want 3", errs)
+	}
+	if got := errs.For("test_command"); len(got) != 1 || got[0].Severity != SeverityError {
+		t.Errorf("test_command problems = %v, want one error", got)
+	}
+	if got := errs.For("max_retries"); len(got) != 1 || got[0].Severity != SeverityWarning {
+		t.Errorf("max_retries problems = %v, want one warning", got)
+	}
+	if got := errs.For("branch_pattern"); len(got) != 1 || !strings.Contains(got[0].Message, "{id}") {
+		t.Errorf("branch_pattern problems = %v", got)
+	}
+}
+
 // ============================================================
 // BuildSettingsFromFields
 // ============================================================
@@ -392,6 +590,44 @@ func TestDefaultMaxTurns(t *testing.T) {
 	}
 }
 
+func TestParseMaxTurns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		values     [3]string
+		want       MaxTurnsConfig
+		wantFields []string
+	}{
+		{name: "valid", values: [3]string{"10", " 20 ", "30"}, want: MaxTurnsConfig{Small: 10, Medium: 20, Large: 30}},
+		{name: "not a number", values: [3]string{"ten", "20", "30"}, want: MaxTurnsConfig{Medium: 20, Large: 30},
+			wantFields: []string{"max_turns_small"}},
+		{name: "zero", values: [3]string{"10", "20", "0"}, wantFields: []string{"max_turns_large"},
+			want: MaxTurnsConfig{Small: 10, Medium: 20}},
+		{name: "medium not above small", values: [3]string{"20", "20", "30"}, wantFields: []string{"max_turns_medium"},
+			want: MaxTurnsConfig{Small: 20, Medium: 20, Large: 30}},
+		{name: "descending", values: [3]string{"30", "20", "10"}, wantFields: []string{"max_turns_medium", "max_turns_large"},
+			want: MaxTurnsConfig{Small: 30, Medium: 20, Large: 10}},
+		{name: "order skips an invalid field", values: [3]string{"30", "", "10"}, wantFields: []string{"max_turns_medium"},
+			want: MaxTurnsConfig{Small: 30, Large: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, errs := ParseMaxTurns(tt.values)
+			if got != tt.want {
+				t.Errorf("ParseMaxTurns() = %+v, want %+v", got, tt.want)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("error fields = %v, want %v (%v)", fields, tt.wantFields, errs)
+			}
+		})
+	}
+}
+
 // ============================================================
 // Provider detection + field integration
 // ============================================================
@@ -481,6 +717,166 @@ func TestBuildProviderConfigFromFields_Ollama_EmptyURL(t *testing.T) {
 	}
 }
 
+func TestBuildProviderConfigFromFields_OllamaRemote(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   provider.Config
+	}{
+		{
+			name: "bearer auth with a CA certificate",
+			fields: map[string]string{
+				"provider_type": "ollama", "claude_model": "qwen3-coder", "ollama_url": "https://gpu.example.com",
+				"ollama_auth": "Bearer", "ollama_token_env": "$GPU_TOKEN", "ollama_ca_cert": "/etc/ca.pem", "ollama_insecure": "false",
+			},
+			want: provider.Config{Type: provider.ProviderOllama, Model: "qwen3-coder", OllamaURL: "https://gpu.example.com",
+				OllamaAuth: "bearer", OllamaTokenEnv: "GPU_TOKEN", OllamaCACert: "/etc/ca.pem"},
+		},
+		{
+			name: "defaults are left empty",
+			fields: map[string]string{
+				"provider_type": "ollama", "claude_model": "qwen3-coder", "ollama_url": "http://box:11434",
+				"ollama_auth": "none", "ollama_token_env": provider.EnvOllamaToken, "ollama_insecure": "true",
+			},
+			want: provider.Config{Type: provider.ProviderOllama, Model: "qwen3-coder", OllamaURL: "http://box:11434", OllamaInsecure: true},
+		},
+		{
+			name: "anthropic ignores the Ollama fields",
+			fields: map[string]string{
+				"provider_type": "anthropic", "claude_model": "sonnet", "ollama_auth": "bearer", "ollama_insecure": "true",
+			},
+			want: provider.Config{Type: provider.ProviderAnthropic, Model: "sonnet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := BuildProviderConfigFromFields(tt.fields); got != tt.want {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSettings_OllamaFields(t *testing.T) {
+	t.Parallel()
+	errs := ValidateSettings([]InputField{
+		{Key: "ollama_url", Label: "Ollama URL", Value: "gpu.example.com:11434"},
+		{Key: "ollama_auth", Label: "Ollama Auth", Value: "digest"},
+		{Key: "ollama_token_env", Label: "Ollama Token Variable", Value: "MY-TOKEN"},
+	})
+	for _, key := range []string{"ollama_url", "ollama_auth", "ollama_token_env"} {
+		if got := errs.For(key); len(got) != 1 || got[0].Severity != SeverityError {
+			t.Errorf("%s problems = %v, want one error", key, got)
+		}
+	}
+
+	ok := ValidateSettings([]InputField{
+		{Key: "ollama_url", Label: "Ollama URL", Value: "https://gpu.example.com"},
+		{Key: "ollama_auth", Label: "Ollama Auth", Value: "Basic"},
+		{Key: "ollama_token_env", Label: "Ollama Token Variable", Value: "$GPU_TOKEN"},
+	})
+	if len(ok) != 0 {
+		t.Errorf("valid Ollama fields: %v", ok)
+	}
+}
+
+func TestValidateOllamaAccess(t *testing.T) {
+	t.Setenv("FORGE_TEST_OLLAMA_SET", "secret")
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, []byte("pem"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		cfg  provider.Config
+		want map[string]Severity
+	}{
+		{name: "no auth", cfg: provider.Config{Type: provider.ProviderOllama}},
+		{name: "token set, CA exists", cfg: provider.Config{OllamaAuth: "bearer", OllamaTokenEnv: "FORGE_TEST_OLLAMA_SET", OllamaCACert: ca}},
+		{
+			name: "token empty, CA missing",
+			cfg:  provider.Config{OllamaAuth: "basic", OllamaTokenEnv: "FORGE_TEST_OLLAMA_UNSET", OllamaCACert: ca + ".missing"},
+			want: map[string]Severity{"ollama_token_env": SeverityWarning, "ollama_ca_cert": SeverityError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateOllamaAccess(tt.cfg)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("problems = %v, want %v", errs, tt.want)
+			}
+			for _, e := range errs {
+				if sev, ok := tt.want[e.Field]; !ok || sev != e.Severity {
+					t.Errorf("unexpected problem %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutionRequirements(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Done", Status: state.TaskDone, Description: strings.Repeat("x", 40_000)},
+		{ID: "task-002", Title: "Small", Status: state.TaskPending, Description: "short"},
+		{ID: "task-003", Title: "Big", Status: state.TaskFailed, Description: strings.Repeat("y", 8_000)},
+	}
+	req := ExecutionRequirements(strings.Repeat("c", 4_000), tasks, &state.Settings{TestCommand: "go test ./..."})
+	if !req.Tools || !req.JSON {
+		t.Errorf("requirements = %+v, want tools and JSON", req)
+	}
+	// ~12K chars from the context and task-003; task-001 is done
+	if req.PromptTokens < 3_000 || req.PromptTokens > 3_500 {
+		t.Errorf("PromptTokens = %d, want about 3000", req.PromptTokens)
+	}
+	if got := ExecutionRequirements("", nil, nil).PromptTokens; got == 0 {
+		t.Error("the system prompt should count even without tasks")
+	}
+}
+
+func TestCapabilityFieldErrors(t *testing.T) {
+	t.Parallel()
+	noTools := provider.CapabilityProblem{Feature: provider.FeatureTools, Message: "m does not support tool use", Blocking: true}
+	tight := provider.CapabilityProblem{Feature: provider.FeatureContext, Message: "the prompt fills over half"}
+	tests := []struct {
+		name       string
+		problems   []provider.CapabilityProblem
+		suggestion string
+		pt         provider.ProviderType
+		wantFirst  string
+		wantSev    []Severity
+	}{
+		{name: "no problems", pt: provider.ProviderOllama},
+		{name: "suggestion", problems: []provider.CapabilityProblem{noTools, tight}, suggestion: "qwen3-coder", pt: provider.ProviderOllama,
+			wantFirst: "Model: m does not support tool use — try qwen3-coder", wantSev: []Severity{SeverityError, SeverityWarning}},
+		{name: "ollama without suggestion", problems: []provider.CapabilityProblem{tight}, pt: provider.ProviderOllama,
+			wantFirst: "Model: the prompt fills over half — pull one of: qwen3-coder", wantSev: []Severity{SeverityWarning}},
+		{name: "anthropic without suggestion", problems: []provider.CapabilityProblem{tight}, pt: provider.ProviderAnthropic,
+			wantFirst: "Model: the prompt fills over half", wantSev: []Severity{SeverityWarning}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			errs := CapabilityFieldErrors(tt.problems, tt.suggestion, tt.pt)
+			var sevs []Severity
+			for _, e := range errs {
+				if e.Field != "claude_model" {
+					t.Errorf("field = %q, want claude_model", e.Field)
+				}
+				sevs = append(sevs, e.Severity)
+			}
+			if !reflect.DeepEqual(sevs, tt.wantSev) {
+				t.Errorf("severities = %v, want %v", sevs, tt.wantSev)
+			}
+			if len(errs) > 0 && !strings.HasPrefix(errs[0].Message, tt.wantFirst) {
+				t.Errorf("first message = %q, want prefix %q", errs[0].Message, tt.wantFirst)
+			}
+		})
+	}
+}
+
 func TestOllamaModelNames_FromStatus(t *testing.T) {
 	t.Parallel()
 	status := &provider.OllamaStatus{
@@ -523,10 +919,259 @@ func TestOllamaModelNames_NotAvailable(t *testing.T) {
 	}
 }
 
+func TestModelChoices(t *testing.T) {
+	t.Parallel()
+	fetched := []string{"llama3", "qwen3-coder"}
+	if got := ModelChoices(provider.ProviderOllama, fetched); !reflect.DeepEqual(got, fetched) {
+		t.Errorf("Ollama with fetched models = %v, want %v", got, fetched)
+	}
+	if got := ModelChoices(provider.ProviderOllama, nil); !reflect.DeepEqual(got, provider.RecommendedModels(provider.ProviderOllama)) {
+		t.Errorf("Ollama before fetch = %v, want the recommended models", got)
+	}
+	if got := ModelChoices(provider.ProviderAnthropic, fetched); !reflect.DeepEqual(got, provider.RecommendedModels(provider.ProviderAnthropic)) {
+		t.Errorf("Anthropic = %v, want the recommended models", got)
+	}
+}
+
+func TestNextModel(t *testing.T) {
+	t.Parallel()
+	choices := []string{"sonnet", "opus", "haiku"}
+	tests := []struct {
+		current string
+		choices []string
+		want    string
+	}{
+		{"sonnet", choices, "opus"},
+		{"haiku", choices, "sonnet"},
+		{"my-model", choices, "sonnet"},
+		{"my-model", nil, "my-model"},
+	}
+	for _, tt := range tests {
+		if got := NextModel(tt.current, tt.choices); got != tt.want {
+			t.Errorf("NextModel(%q, %v) = %q, want %q", tt.current, tt.choices, got, tt.want)
+		}
+	}
+}
+
+func TestModelForProvider(t *testing.T) {
+	t.Parallel()
+	anthropic := provider.RecommendedModels(provider.ProviderAnthropic)
+	ollama := []string{"llama3", "qwen3-coder"}
+	tests := []struct {
+		name    string
+		current string
+		pt      provider.ProviderType
+		choices []string
+		want    string
+	}{
+		{"anthropic model to Ollama", "sonnet", provider.ProviderOllama, ollama, "llama3"},
+		{"installed Ollama model kept", "qwen3-coder", provider.ProviderOllama, ollama, "qwen3-coder"},
+		{"Ollama model to Anthropic", "qwen3-coder", provider.ProviderAnthropic, anthropic, "sonnet"},
+		{"full Claude name kept", "claude-sonnet-4-5", provider.ProviderAnthropic, anthropic, "claude-sonnet-4-5"},
+		{"empty", "", provider.ProviderAnthropic, anthropic, "sonnet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ModelForProvider(tt.current, tt.pt, tt.choices); got != tt.want {
+				t.Errorf("ModelForProvider(%q, %s) = %q, want %q", tt.current, tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
 // ============================================================
 // Settings round-trip with provider config
 // ============================================================
 
+func TestSplitURLs(t *testing.T) {
+	t.Parallel()
+	got := SplitURLs(" https://a.example/x,https://b.example/y \n https://c.example/z ,")
+	want := []string{"https://a.example/x", "https://b.example/y", "https://c.example/z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitURLs() = %q, want %q", got, want)
+	}
+	if got := SplitURLs(""); len(got) != 0 {
+		t.Errorf("SplitURLs(\"\") = %q, want none", got)
+	}
+}
+
+func TestBuildSettingsFromFields_AlertOff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "off", want: ""},
+		{value: "", want: ""},
+		{value: "sound", want: "sound"},
+	}
+	for _, tt := range tests {
+		got := BuildSettingsFromFields([]InputField{{Key: "alert", Value: tt.value}}, nil, MaxTurnsConfig{})
+		if got.Alert != tt.want {
+			t.Errorf("alert %q: Alert = %q, want %q", tt.value, got.Alert, tt.want)
+		}
+	}
+}
+
+func TestBuildSettingsFromFields_Changelog(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "off", want: ""},
+		{value: "", want: ""},
+		{value: "commit", want: "commit"},
+	}
+	for _, tt := range tests {
+		got := BuildSettingsFromFields([]InputField{{Key: "changelog", Value: tt.value}}, nil, MaxTurnsConfig{})
+		if got.Changelog != tt.want {
+			t.Errorf("changelog %q: Changelog = %q, want %q", tt.value, got.Changelog, tt.want)
+		}
+	}
+}
+
+func TestBuildSettingsFromFields_WorkspaceRepos(t *testing.T) {
+	t.Parallel()
+	got := BuildSettingsFromFields([]InputField{{Key: "workspace_repos", Value: "backend, apps/web/"}}, nil, MaxTurnsConfig{})
+	want := []state.WorkspaceRepo{{Path: "backend"}, {Path: "apps/web"}}
+	if !reflect.DeepEqual(got.Repos, want) {
+		t.Errorf("Repos = %+v, want %+v", got.Repos, want)
+	}
+}
+
+func TestBuildSettingsFromFields_SkipHooks(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "skip_hooks", Value: "true"}}, nil, MaxTurnsConfig{}); !got.SkipHooks {
+		t.Error("SkipHooks should be set from the skip_hooks toggle")
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.SkipHooks {
+		t.Error("hooks should run by default")
+	}
+}
+
+func TestBuildSettingsFromFields_PublishChecks(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "publish_checks", Value: "true"}}, nil, MaxTurnsConfig{}); !got.PublishChecks {
+		t.Error("PublishChecks should be set from the publish_checks toggle")
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.PublishChecks {
+		t.Error("checks should be off by default")
+	}
+}
+
+func TestBuildSettingsFromFields_WaitForCI(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "wait_for_ci", Value: "true"}}, nil, MaxTurnsConfig{}); !got.WaitForCI {
+		t.Error("WaitForCI should be set from the wait_for_ci toggle")
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.WaitForCI {
+		t.Error("CI should not be awaited by default")
+	}
+}
+
+func TestBuildSettingsFromFields_DraftPRs(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "draft_prs", Value: "true"}}, nil, MaxTurnsConfig{}); !got.DraftPRs {
+		t.Error("DraftPRs should be set from the draft_prs toggle")
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.DraftPRs {
+		t.Error("PRs should not be drafts by default")
+	}
+}
+
+func TestBuildSettingsFromFields_StashDirty(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "stash_dirty", Value: "true"}}, nil, MaxTurnsConfig{}); !got.StashDirty {
+		t.Error("StashDirty should be set from the stash_dirty toggle")
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.StashDirty {
+		t.Error("a dirty worktree should stop the run by default")
+	}
+}
+
+func TestBuildSettingsFromFields_BaseDrift(t *testing.T) {
+	t.Parallel()
+	if got := BuildSettingsFromFields([]InputField{{Key: "base_drift", Value: "rebase"}}, nil, MaxTurnsConfig{}); got.BaseDrift != "rebase" {
+		t.Errorf("BaseDrift = %q, want rebase", got.BaseDrift)
+	}
+	if got := BuildSettingsFromFields(DefaultInputFields(nil), nil, MaxTurnsConfig{}); got.BaseDrift != "" {
+		t.Errorf("BaseDrift = %q, want empty (off) by default", got.BaseDrift)
+	}
+}
+
+func TestBuildSettingsFromFields_SparsePaths(t *testing.T) {
+	t.Parallel()
+	got := BuildSettingsFromFields([]InputField{{Key: "sparse_paths", Value: "services/billing/, libs/common"}}, nil, MaxTurnsConfig{})
+	if want := []string{"services/billing", "libs/common"}; !reflect.DeepEqual(got.SparsePaths, want) {
+		t.Errorf("SparsePaths = %v, want %v", got.SparsePaths, want)
+	}
+}
+
+func TestResolveRepos_KeepsConfiguredValues(t *testing.T) {
+	t.Parallel()
+	previous := []state.WorkspaceRepo{{Path: "web", BaseBranch: "develop", RemoteURL: "git@example.com:web.git",
+		TestCommand: "pnpm test", BuildCommand: "pnpm build"}}
+	got := ResolveRepos(t.TempDir(), []state.WorkspaceRepo{{Path: "web"}}, previous)
+	if !reflect.DeepEqual(got, previous) {
+		t.Errorf("ResolveRepos() = %+v, want %+v", got, previous)
+	}
+}
+
+func TestSplitChecklist(t *testing.T) {
+	t.Parallel()
+	got := SplitChecklist(" Tests added; Errors wrapped, not dropped ;; ")
+	want := []string{"Tests added", "Errors wrapped, not dropped"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitChecklist() = %q, want %q", got, want)
+	}
+	if got := SplitChecklist(""); got != nil {
+		t.Errorf("SplitChecklist(\"\") = %q, want nil", got)
+	}
+}
+
+func TestBuildSettingsFromFields_DatabaseURLEnv(t *testing.T) {
+	t.Parallel()
+	got := BuildSettingsFromFields([]InputField{{Key: "database_url_env", Value: "$DATABASE_URL"}}, nil, MaxTurnsConfig{})
+	if got.DatabaseURLEnv != "DATABASE_URL" {
+		t.Errorf("DatabaseURLEnv = %q, want DATABASE_URL", got.DatabaseURLEnv)
+	}
+}
+
+func TestBuildSettingsFromFields_CommitPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		fields []InputField
+		want   state.CommitPolicy
+	}{
+		{
+			name:   "defaults",
+			fields: DefaultInputFields(nil),
+			want:   state.CommitPolicy{MaxFileMB: 10, ScanSecrets: true, ProtectWorkflows: true},
+		},
+		{
+			name: "relaxed",
+			fields: []InputField{
+				{Key: "max_file_mb", Value: "0"},
+				{Key: "scan_secrets", Value: "false"},
+				{Key: "allow_workflow_changes", Value: "true"},
+			},
+			want: state.CommitPolicy{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := BuildSettingsFromFields(tt.fields, nil, MaxTurnsConfig{}).CommitPolicy
+			if got != tt.want {
+				t.Errorf("CommitPolicy = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestBuildSettingsFromFields_IncludesProvider(t *testing.T) {
 	t.Parallel()
 	// Existing test from M7, extended to verify provider config persists
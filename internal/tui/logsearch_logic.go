@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LogMatch is a log entry that matched a transcript search.
+type LogMatch struct {
+	Line      int    // index into TaskProgress.LogLines
+	Text      string // the entry's first line containing the query
+	Timestamp time.Time
+}
+
+// LogSearchGroup is one task's matches, in log order.
+type LogSearchGroup struct {
+	TaskID  string
+	Title   string
+	Matches []LogMatch
+}
+
+// SearchLogs finds query, ignoring case, in the logs and events of every
+// task in the run. Tasks without matches are left out; the others are
+// ordered by their earliest match, so the task where a message first
+// appeared comes first. A blank query matches nothing.
+func SearchLogs(progress []TaskProgress, query string) []LogSearchGroup {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	var groups []LogSearchGroup
+	for _, tp := range progress {
+		g := LogSearchGroup{TaskID: tp.TaskID, Title: tp.Title}
+		for i, l := range tp.LogLines {
+			if !strings.Contains(strings.ToLower(l.Text), query) {
+				continue
+			}
+			text := l.Text
+			for _, line := range strings.Split(l.Text, "\n") {
+				if strings.Contains(strings.ToLower(line), query) {
+					text = line
+					break
+				}
+			}
+			g.Matches = append(g.Matches, LogMatch{Line: i, Text: strings.TrimSpace(text), Timestamp: l.Timestamp})
+		}
+		if len(g.Matches) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b LogSearchGroup) int {
+		return a.Matches[0].Timestamp.Compare(b.Matches[0].Timestamp)
+	})
+	return groups
+}
+
+// CountLogMatches returns the number of matches across groups.
+func CountLogMatches(groups []LogSearchGroup) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g.Matches)
+	}
+	return n
+}
+
+// LogMatchAt returns the group and match at a position counted across
+// all groups, as the results list shows them.
+func LogMatchAt(groups []LogSearchGroup, index int) (LogSearchGroup, LogMatch, bool) {
+	for _, g := range groups {
+		if index < len(g.Matches) {
+			return g, g.Matches[index], true
+		}
+		index -= len(g.Matches)
+	}
+	return LogSearchGroup{}, LogMatch{}, false
+}
+
+// FormatLogSearch renders search results grouped by task, marking the
+// selected match (counted across groups) with "▸". Lines are cut to
+// width. It returns the rendered lines and the line the selection is on,
+// for scrolling.
+func FormatLogSearch(query string, groups []LogSearchGroup, selected, width int) ([]string, int) {
+	if len(groups) == 0 {
+		return []string{fmt.Sprintf("No log lines match %q in this run.", query)}, 0
+	}
+	lines := []string{fmt.Sprintf("%d match(es) for %q in %d task(s):", CountLogMatches(groups), query, len(groups))}
+	at, n := 0, 0
+	for _, g := range groups {
+		lines = append(lines, "", fmt.Sprintf("%s: %s (%d)", g.TaskID, g.Title, len(g.Matches)))
+		for _, match := range g.Matches {
+			marker := "  "
+			if n == selected {
+				marker, at = "▸ ", len(lines)
+			}
+			line := marker + match.Text
+			if !match.Timestamp.IsZero() {
+				line = marker + match.Timestamp.Format("15:04:05") + "  " + match.Text
+			}
+			if width > 1 && len([]rune(line)) > width {
+				line = string([]rune(line)[:width-1]) + "…"
+			}
+			lines = append(lines, line)
+			n++
+		}
+	}
+	return lines, at
+}
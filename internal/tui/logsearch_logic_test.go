@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchLogs(t *testing.T) {
+	t.Parallel()
+	at := func(sec int) time.Time { return time.Date(2026, 1, 2, 10, 0, sec, 0, time.UTC) }
+	progress := []TaskProgress{
+		{TaskID: "task-001", Title: "Init", LogLines: []LogLine{
+			{Text: "Starting task: Init", Timestamp: at(1)},
+			{Text: "Tests failed", Timestamp: at(9)},
+		}},
+		{TaskID: "task-002", Title: "Auth", LogLines: []LogLine{
+			{Text: "Running Claude Code", Timestamp: at(2)},
+			{Text: "reading files\npanic: nil map WRITE\nretrying", Type: LogClaudeChunk, Timestamp: at(3)},
+		}},
+		{TaskID: "task-003", Title: "Docs", LogLines: []LogLine{{Text: "Starting task: Docs", Timestamp: at(4)}}},
+	}
+
+	groups := SearchLogs(progress, "  nil map write ")
+	if len(groups) != 1 || groups[0].TaskID != "task-002" {
+		t.Fatalf("SearchLogs() = %+v, want one group for task-002", groups)
+	}
+	if m := groups[0].Matches[0]; m.Line != 1 || m.Text != "panic: nil map WRITE" {
+		t.Errorf("match = %+v, want line 1 with the matching line only", m)
+	}
+
+	// The task whose earliest match is oldest comes first
+	groups = SearchLogs(progress, "a")
+	var ids []string
+	for _, g := range groups {
+		ids = append(ids, g.TaskID)
+	}
+	if got := strings.Join(ids, ","); got != "task-001,task-002,task-003" {
+		t.Errorf("group order = %s", got)
+	}
+	groups = SearchLogs(progress, "failed")
+	if len(groups) != 1 || groups[0].TaskID != "task-001" {
+		t.Errorf("SearchLogs(failed) = %+v", groups)
+	}
+
+	if SearchLogs(progress, " ") != nil {
+		t.Error("a blank query should match nothing")
+	}
+}
+
+func TestLogMatchAt(t *testing.T) {
+	t.Parallel()
+	groups := []LogSearchGroup{
+		{TaskID: "task-001", Matches: []LogMatch{{Line: 3}}},
+		{TaskID: "task-002", Matches: []LogMatch{{Line: 0}, {Line: 7}}},
+	}
+	if n := CountLogMatches(groups); n != 3 {
+		t.Errorf("CountLogMatches() = %d, want 3", n)
+	}
+	g, m, ok := LogMatchAt(groups, 2)
+	if !ok || g.TaskID != "task-002" || m.Line != 7 {
+		t.Errorf("LogMatchAt(2) = %s, %+v, %v", g.TaskID, m, ok)
+	}
+	if _, _, ok := LogMatchAt(groups, 3); ok {
+		t.Error("LogMatchAt past the end should fail")
+	}
+}
+
+func TestFormatLogSearch(t *testing.T) {
+	t.Parallel()
+	groups := []LogSearchGroup{
+		{TaskID: "task-001", Title: "Init", Matches: []LogMatch{{Text: "exit status 1"}}},
+		{TaskID: "task-002", Title: "Auth", Matches: []LogMatch{{Text: "exit status 2", Timestamp: time.Date(2026, 1, 2, 10, 4, 5, 0, time.UTC)}}},
+	}
+	lines, at := FormatLogSearch("exit", groups, 1, 80)
+	want := []string{
+		`2 match(es) for "exit" in 2 task(s):`,
+		"",
+		"task-001: Init (1)",
+		"  exit status 1",
+		"",
+		"task-002: Auth (1)",
+		"▸ 10:04:05  exit status 2",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FormatLogSearch() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if at != 6 {
+		t.Errorf("selected line = %d, want 6", at)
+	}
+
+	if lines, _ := FormatLogSearch("nope", nil, 0, 80); !strings.Contains(lines[0], "No log lines match") {
+		t.Errorf("no results = %q", lines)
+	}
+}
@@ -0,0 +1,625 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+// PlanFileTask is one task of the plan file edited with "E" in review: the
+// whole plan as a YAML document, for users who prefer a text editor to the
+// task list.
+//
+//	tasks:
+//	  - id: task-001
+//	    status: done
+//	    title: Set up the module
+//	    complexity: small
+//	  - id: task-002
+//	    status: pending
+//	    title: Add the orders API
+//	    complexity: medium
+//	    labels: [api]
+//	    depends_on: [task-001]
+//	    description: |
+//	      CRUD handlers for orders.
+//	    acceptance_criteria:
+//	      - GET /orders lists orders
+//
+// Pending and failed tasks can be edited, reordered and removed. Other
+// tasks are there for reference and must be left as they are. Entries with
+// a new id (or none) are added under the next free task ID.
+type PlanFileTask struct {
+	ID          string
+	Status      string
+	Title       string
+	Complexity  string
+	Type        state.TaskType
+	Owner       state.TaskOwner
+	Assignee    string
+	Model       string
+	Labels      []string
+	DependsOn   []string
+	Commands    []string
+	Artifacts   []string
+	Description string
+	Criteria    []string
+}
+
+// planFileHeader explains the format at the top of the file.
+const planFileHeader = `# Forge plan v%d. Save and close to apply; an invalid file changes nothing.
+# Pending and failed tasks can be edited, reordered and removed. Done and
+# other tasks are listed for reference only and must stay unchanged.
+# Add a task with a new id (e.g. "new-1", referable from depends_on) or none.
+# Optional keys: type (code, verify, manual), owner (agent, human), assignee,
+# model, labels, commands, artifacts.
+`
+
+// planFileEditable reports whether a task's entry may be changed.
+func planFileEditable(t state.Task) bool {
+	return t.Status == state.TaskPending || t.Status == state.TaskFailed
+}
+
+func planFileEntry(t state.Task) PlanFileTask {
+	e := PlanFileTask{
+		ID:          t.ID,
+		Status:      string(t.Status),
+		Title:       t.Title,
+		Complexity:  t.Complexity,
+		Assignee:    t.Assignee,
+		Model:       t.Model,
+		Labels:      t.Labels,
+		DependsOn:   t.DependsOn,
+		Commands:    t.Commands,
+		Artifacts:   t.Artifacts,
+		Description: t.Description,
+		Criteria:    t.AcceptanceCriteria,
+	}
+	if t.EffectiveType() != state.TaskTypeCode {
+		e.Type = t.Type
+	}
+	if t.ForHuman() {
+		e.Owner = state.OwnerHuman
+	}
+	return e
+}
+
+// FormatPlanFile renders every task but the cancelled ones as a plan file.
+func FormatPlanFile(s *state.State) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, planFileHeader, s.PlanVersion)
+	b.WriteString("tasks:\n")
+	for _, t := range s.Tasks {
+		if t.Status != state.TaskCancelled {
+			writePlanFileEntry(&b, planFileEntry(t))
+		}
+	}
+	return b.String()
+}
+
+func writePlanFileEntry(b *strings.Builder, e PlanFileTask) {
+	fmt.Fprintf(b, "  - id: %s\n", yamlString(e.ID))
+	scalar := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(b, "    %s: %s\n", key, yamlString(value))
+		}
+	}
+	scalar("status", e.Status)
+	fmt.Fprintf(b, "    title: %s\n", yamlString(e.Title))
+	scalar("complexity", e.Complexity)
+	scalar("type", string(e.Type))
+	scalar("owner", string(e.Owner))
+	scalar("assignee", e.Assignee)
+	scalar("model", e.Model)
+	if len(e.Labels) > 0 {
+		fmt.Fprintf(b, "    labels: %s\n", yamlFlowList(e.Labels))
+	}
+	if len(e.DependsOn) > 0 {
+		fmt.Fprintf(b, "    depends_on: %s\n", yamlFlowList(e.DependsOn))
+	}
+	blockList := func(key string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(b, "    %s:\n", key)
+		for _, item := range items {
+			fmt.Fprintf(b, "      - %s\n", yamlString(item))
+		}
+	}
+	blockList("commands", e.Commands)
+	blockList("artifacts", e.Artifacts)
+	if description := strings.TrimRight(e.Description, "\n"); strings.Contains(description, "\n") {
+		b.WriteString("    description: |\n")
+		for _, line := range strings.Split(description, "\n") {
+			if line == "" {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("      " + line + "\n")
+			}
+		}
+	} else {
+		scalar("description", e.Description)
+	}
+	blockList("acceptance_criteria", e.Criteria)
+}
+
+// yamlString quotes s when it would not read back as the same plain
+// scalar.
+func yamlString(s string) string {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s, "\n\t\"") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsRune("-?:,[]{}#&*!|>'%@`", rune(s[0])) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func yamlFlowList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = yamlString(item)
+		if strings.Contains(item, ",") && quoted[i] == item {
+			quoted[i] = strconv.Quote(item)
+		}
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// ParsePlanFile reads a plan file: a top-level "tasks:" list of mappings
+// whose values are plain or quoted scalars, "[a, b]" or "- item" lists,
+// and "|" blocks, with # comments.
+func ParsePlanFile(text string) ([]PlanFileTask, error) {
+	var (
+		entries     []PlanFileTask
+		cur         *PlanFileTask
+		entryIndent = -1
+		listKey     string // field collecting "- item" lines
+		blockKey    string // field collecting a "|" block
+		blockIndent int    // indent of the field that started the block
+		blockLines  []string
+	)
+
+	endBlock := func() {
+		if blockKey == "" {
+			return
+		}
+		cur.Description = dedent(blockLines)
+		blockKey, blockLines = "", nil
+	}
+
+	for n, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimRight(raw, " \t\r")
+		lineNo := n + 1
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+
+		if blockKey != "" {
+			if raw == "" || indent > blockIndent {
+				blockLines = append(blockLines, raw)
+				continue
+			}
+			endBlock()
+		}
+
+		line := strings.TrimSpace(stripYAMLComment(raw))
+		if line == "" {
+			continue
+		}
+		if indent == 0 {
+			if line != "tasks:" {
+				return nil, fmt.Errorf("line %d: expected \"tasks:\"", lineNo)
+			}
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(line, "-"); ok && (item == "" || item[0] == ' ') {
+			item = strings.TrimSpace(item)
+			switch {
+			case entryIndent < 0 || indent == entryIndent:
+				entryIndent = indent
+				entries = append(entries, PlanFileTask{})
+				cur = &entries[len(entries)-1]
+				listKey = ""
+				if item == "" {
+					continue
+				}
+				line, indent = item, indent+2
+			case listKey != "" && indent > entryIndent:
+				value, err := yamlScalar(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				if err := setPlanFileList(cur, listKey, append(planFileList(cur, listKey), value)); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				continue
+			default:
+				return nil, fmt.Errorf("line %d: unexpected list item", lineNo)
+			}
+		}
+
+		if cur == nil || indent <= entryIndent {
+			return nil, fmt.Errorf("line %d: expected a task (\"- id: ...\")", lineNo)
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		listKey = ""
+
+		switch {
+		case value == "|" || value == "|-":
+			if key != "description" {
+				return nil, fmt.Errorf("line %d: only description can be a block", lineNo)
+			}
+			blockKey, blockIndent = key, indent
+		case value == "":
+			if err := setPlanFileList(cur, key, nil); err != nil {
+				if err := setPlanFileScalar(cur, key, ""); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				continue
+			}
+			listKey = key
+		case strings.HasPrefix(value, "["):
+			items, err := yamlParseFlowList(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if err := setPlanFileList(cur, key, items); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		default:
+			s, err := yamlScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if err := setPlanFileScalar(cur, key, s); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		}
+	}
+	if cur != nil {
+		endBlock()
+	}
+	return entries, nil
+}
+
+func setPlanFileScalar(e *PlanFileTask, key, value string) error {
+	switch key {
+	case "id":
+		e.ID = value
+	case "status":
+		e.Status = value
+	case "title":
+		e.Title = value
+	case "complexity":
+		e.Complexity = value
+	case "type":
+		e.Type = state.TaskType(value)
+	case "owner":
+		e.Owner = state.TaskOwner(value)
+	case "assignee":
+		e.Assignee = value
+	case "model":
+		e.Model = value
+	case "description":
+		e.Description = value
+	case "labels", "depends_on", "commands", "artifacts", "acceptance_criteria":
+		return setPlanFileList(e, key, []string{value})
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
+
+func planFileList(e *PlanFileTask, key string) []string {
+	switch key {
+	case "labels":
+		return e.Labels
+	case "depends_on":
+		return e.DependsOn
+	case "commands":
+		return e.Commands
+	case "artifacts":
+		return e.Artifacts
+	case "acceptance_criteria":
+		return e.Criteria
+	}
+	return nil
+}
+
+func setPlanFileList(e *PlanFileTask, key string, items []string) error {
+	switch key {
+	case "labels":
+		e.Labels = items
+	case "depends_on":
+		e.DependsOn = items
+	case "commands":
+		e.Commands = items
+	case "artifacts":
+		e.Artifacts = items
+	case "acceptance_criteria":
+		e.Criteria = items
+	default:
+		return fmt.Errorf("%q is not a list", key)
+	}
+	return nil
+}
+
+// yamlScalar reads a plain, "double" or 'single' quoted scalar.
+func yamlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return s, nil
+}
+
+// yamlParseFlowList reads "[a, "b, c", d]".
+func yamlParseFlowList(s string) ([]string, error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(s, "["), "]")
+	if !ok {
+		return nil, fmt.Errorf("unterminated list %s", s)
+	}
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c != ',':
+				continue
+			}
+		}
+		if item := strings.TrimSpace(inner[start:i]); item != "" {
+			v, err := yamlScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		start = i + 1
+	}
+	return items, nil
+}
+
+// stripYAMLComment drops a trailing # comment that is outside quotes.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" \t-:[,", line[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// dedent removes the common indentation of a "|" block and its trailing
+// blank lines.
+func dedent(lines []string) string {
+	common := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if n := len(l) - len(strings.TrimLeft(l, " \t")); common < 0 || n < common {
+			common = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= common && common > 0 {
+			l = l[common:]
+		}
+		out[i] = l
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n ")
+}
+
+// canonicalPlanFileEntry renders an entry as it reads back from the file,
+// so entries compare equal whatever whitespace the file lost.
+func canonicalPlanFileEntry(e PlanFileTask) string {
+	var b strings.Builder
+	writePlanFileEntry(&b, e)
+	if parsed, err := ParsePlanFile("tasks:\n" + b.String()); err == nil && len(parsed) == 1 {
+		b.Reset()
+		writePlanFileEntry(&b, parsed[0])
+	}
+	return b.String()
+}
+
+// ApplyPlanFile applies an edited plan file to the tasks. Pending and
+// failed tasks take their entries' fields and file order; ones left out
+// are deleted. Entries with unknown ids become new tasks, and depends_on
+// references to them are remapped to the IDs they get. Any change to
+// another task's entry, an invalid field, a dangling dependency or a
+// dependency cycle rejects the whole file. Does not mutate the input.
+func ApplyPlanFile(tasks []state.Task, entries []PlanFileTask, planVersion int) ([]state.Task, error) {
+	existing := make(map[string]state.Task, len(tasks))
+	for _, t := range tasks {
+		existing[t.ID] = t
+	}
+
+	// Allocate IDs for new tasks first so dependencies can point at them.
+	scratch := &state.State{Tasks: slices.Clone(tasks)}
+	newIDs := map[string]string{}
+	assigned := make([]string, len(entries)) // new tasks' IDs, by entry
+	seen := map[string]bool{}
+	for i, e := range entries {
+		if e.ID != "" && seen[e.ID] {
+			return nil, fmt.Errorf("task %s is listed twice", e.ID)
+		}
+		seen[e.ID] = true
+		if _, ok := existing[e.ID]; ok {
+			continue
+		}
+		assigned[i] = scratch.NextTaskID()
+		scratch.Tasks = append(scratch.Tasks, state.Task{ID: assigned[i]})
+		if e.ID != "" {
+			newIDs[e.ID] = assigned[i]
+		}
+	}
+
+	var order []state.Task // editable and new tasks, in file order
+	for i, e := range entries {
+		e.DependsOn = slices.Clone(e.DependsOn)
+		for j, dep := range e.DependsOn {
+			if id, ok := newIDs[dep]; ok {
+				e.DependsOn[j] = id
+			}
+		}
+		if e.Type == state.TaskTypeCode {
+			e.Type = ""
+		}
+		if e.Owner == state.OwnerAgent {
+			e.Owner = ""
+		}
+
+		if id := assigned[i]; id != "" {
+			if e.Status != "" && e.Status != string(state.TaskPending) {
+				return nil, fmt.Errorf("new task %q: status must be pending", e.Title)
+			}
+			e.ID = id
+			t := state.Task{ID: id, Status: state.TaskPending, PlanVersionCreated: planVersion}
+			if err := applyPlanFileEntry(&t, e, planVersion); err != nil {
+				return nil, err
+			}
+			order = append(order, t)
+			continue
+		}
+
+		t := existing[e.ID]
+		if e.Status == "" {
+			e.Status = string(t.Status)
+		}
+		if !planFileEditable(t) {
+			if canonicalPlanFileEntry(planFileEntry(t)) != canonicalPlanFileEntry(e) {
+				return nil, fmt.Errorf("%s is %s; only pending and failed tasks can be edited", t.ID, t.Status)
+			}
+			continue
+		}
+		if e.Status != string(t.Status) {
+			return nil, fmt.Errorf("%s: status can't be changed in the plan file", t.ID)
+		}
+		if err := applyPlanFileEntry(&t, e, planVersion); err != nil {
+			return nil, err
+		}
+		order = append(order, t)
+	}
+
+	// Rebuild: other tasks keep their places, editable slots take the file
+	// order, and whatever is left (new tasks) goes at the end.
+	var result []state.Task
+	for _, t := range tasks {
+		switch {
+		case t.Status == state.TaskCancelled:
+			result = append(result, t)
+		case !seen[t.ID] && !planFileEditable(t):
+			return nil, fmt.Errorf("%s is %s and can't be removed", t.ID, t.Status)
+		case !planFileEditable(t):
+			result = append(result, t)
+		case seen[t.ID]:
+			result = append(result, order[0])
+			order = order[1:]
+		}
+	}
+	result = append(result, order...)
+
+	var deleted []string
+	for _, t := range tasks {
+		if planFileEditable(t) && !seen[t.ID] {
+			deleted = append(deleted, t.ID)
+		}
+	}
+	for _, t := range result {
+		for _, dep := range t.DependsOn {
+			switch {
+			case dep == t.ID:
+				return nil, fmt.Errorf("%s depends on itself", t.ID)
+			case slices.Contains(deleted, dep):
+				return nil, fmt.Errorf("%s depends on %s, which was removed", t.ID, dep)
+			case !slices.ContainsFunc(result, func(o state.Task) bool { return o.ID == dep }):
+				return nil, fmt.Errorf("%s: dependency %q does not exist", t.ID, dep)
+			}
+		}
+	}
+	if cycle := DetectCircularDependencies(result); len(cycle) > 0 {
+		return nil, fmt.Errorf("circular dependency: %s", strings.Join(cycle, " → "))
+	}
+	return result, nil
+}
+
+// applyPlanFileEntry validates an entry and copies it onto t, bumping
+// PlanVersionModified when anything changed.
+func applyPlanFileEntry(t *state.Task, e PlanFileTask, planVersion int) error {
+	name := t.ID
+	if strings.TrimSpace(e.Title) == "" {
+		return fmt.Errorf("%s: title must not be empty", name)
+	}
+	switch e.Complexity {
+	case "small", "medium", "large":
+	default:
+		return fmt.Errorf("%s: complexity must be small, medium, or large (got %q)", name, e.Complexity)
+	}
+	if !state.ValidTaskType(e.Type) {
+		return fmt.Errorf("%s: type must be code, verify or manual (got %q)", name, e.Type)
+	}
+	if !state.ValidTaskOwner(e.Owner) {
+		return fmt.Errorf("%s: owner must be agent or human (got %q)", name, e.Owner)
+	}
+
+	before := canonicalPlanFileEntry(planFileEntry(*t))
+
+	t.Title = e.Title
+	t.Complexity = e.Complexity
+	t.Type = e.Type
+	t.Owner = e.Owner
+	t.Assignee = e.Assignee
+	t.Model = e.Model
+	t.Labels = e.Labels
+	t.DependsOn = e.DependsOn
+	t.Commands = e.Commands
+	t.Artifacts = e.Artifacts
+	t.Description = e.Description
+	t.AcceptanceCriteria = e.Criteria
+
+	if canonicalPlanFileEntry(planFileEntry(*t)) != before {
+		t.PlanVersionModified = planVersion
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func planFileState() *state.State {
+	return &state.State{
+		PlanVersion: 2,
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Set up: module", Complexity: "small", Status: state.TaskDone,
+				Description: "Line one\n\n  indented\n", AcceptanceCriteria: []string{"go build passes"}},
+			{ID: "task-002", Title: "Add API", Complexity: "medium", Status: state.TaskPending,
+				DependsOn: []string{"task-001"}, Labels: []string{"api", "p1, urgent"}, Description: "Handlers # not a comment"},
+			{ID: "task-003", Title: "Old idea", Complexity: "small", Status: state.TaskCancelled},
+			{ID: "task-004", Title: "Check it", Complexity: "large", Status: state.TaskFailed,
+				Type: state.TaskTypeVerify, Commands: []string{"go test ./... -run 'X|Y'"}, Owner: state.OwnerHuman, Assignee: "sam", Model: "opus"},
+		},
+	}
+}
+
+func TestPlanFile_RoundTrip(t *testing.T) {
+	t.Parallel()
+	s := planFileState()
+	text := FormatPlanFile(s)
+	if strings.Contains(text, "task-003") {
+		t.Error("cancelled tasks should be left out")
+	}
+
+	entries, err := ParsePlanFile(text)
+	if err != nil {
+		t.Fatalf("ParsePlanFile: %v\n%s", err, text)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3", len(entries))
+	}
+	for _, e := range entries {
+		want := planFileEntry(*s.FindTask(e.ID))
+		want.Description = strings.TrimRight(want.Description, "\n")
+		if !reflect.DeepEqual(e, want) {
+			t.Errorf("%s round trip:\n got  %+v\n want %+v", e.ID, e, want)
+		}
+	}
+
+	result, err := ApplyPlanFile(s.Tasks, entries, 3)
+	if err != nil {
+		t.Fatalf("ApplyPlanFile(unchanged): %v", err)
+	}
+	for _, task := range result {
+		if task.PlanVersionModified == 3 {
+			t.Errorf("%s marked modified without changes", task.ID)
+		}
+	}
+}
+
+func TestParsePlanFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		want    []PlanFileTask
+		wantErr string
+	}{
+		{
+			name: "block and flow lists, quotes, comments",
+			text: `# header
+tasks:
+  - id: new-1   # added
+    title: 'It''s new'
+    complexity: small
+    depends_on: [task-001, "task-002"]
+    acceptance_criteria:
+      - "a: b"
+      - plain
+    description: |
+      first
+
+      second
+  -
+    title: Bare
+`,
+			want: []PlanFileTask{
+				{ID: "new-1", Title: "It's new", Complexity: "small", DependsOn: []string{"task-001", "task-002"},
+					Criteria: []string{"a: b", "plain"}, Description: "first\n\nsecond"},
+				{Title: "Bare"},
+			},
+		},
+		{name: "unknown key", text: "tasks:\n  - id: x\n    colour: red\n", wantErr: "line 3: unknown key"},
+		{name: "no tasks header", text: "steps:\n", wantErr: "line 1"},
+		{name: "field before any task", text: "tasks:\n    title: x\n", wantErr: "expected a task"},
+		{name: "bad quote", text: "tasks:\n  - title: \"open\n", wantErr: "bad quoted string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePlanFile(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPlanFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		edit    func(text string) string
+		wantIDs []string
+		check   func(t *testing.T, tasks []state.Task)
+		wantErr string
+	}{
+		{
+			name: "edit, add with dependency on the new task, reorder",
+			edit: func(text string) string {
+				text = strings.Replace(text, "title: Add API", "title: Add REST API", 1)
+				return text + `  - id: new-1
+    title: Docs
+    complexity: small
+    depends_on: [task-002]
+  - title: Release
+    complexity: small
+    depends_on: [new-1]
+`
+			},
+			wantIDs: []string{"task-001", "task-002", "task-003", "task-004", "task-005", "task-006"},
+			check: func(t *testing.T, tasks []state.Task) {
+				if tasks[1].Title != "Add REST API" || tasks[1].PlanVersionModified != 3 {
+					t.Errorf("task-002 = %q (v%d), want edited in v3", tasks[1].Title, tasks[1].PlanVersionModified)
+				}
+				if tasks[4].Title != "Docs" || tasks[4].Status != state.TaskPending || tasks[4].PlanVersionCreated != 3 {
+					t.Errorf("new task = %+v", tasks[4])
+				}
+				if deps := tasks[5].DependsOn; len(deps) != 1 || deps[0] != "task-005" {
+					t.Errorf("Release depends on %v, want [task-005]", deps)
+				}
+				if tasks[3].Type != state.TaskTypeVerify || !tasks[3].ForHuman() || tasks[3].PlanVersionModified == 3 {
+					t.Errorf("untouched task-004 changed: %+v", tasks[3])
+				}
+			},
+		},
+		{
+			name: "reorder pending and failed tasks",
+			edit: func(text string) string {
+				i := strings.Index(text, "  - id: task-002")
+				j := strings.Index(text, "  - id: task-004")
+				return text[:i] + text[j:] + text[i:j]
+			},
+			wantIDs: []string{"task-001", "task-004", "task-003", "task-002"},
+		},
+		{
+			name: "removing a pending task deletes it",
+			edit: func(text string) string {
+				i := strings.Index(text, "  - id: task-002")
+				j := strings.Index(text, "  - id: task-004")
+				return text[:i] + text[j:]
+			},
+			wantIDs: []string{"task-001", "task-003", "task-004"},
+		},
+		{
+			name:    "editing a done task is rejected",
+			edit:    func(text string) string { return strings.Replace(text, "go build passes", "go vet passes", 1) },
+			wantErr: "task-001 is done",
+		},
+		{
+			name: "removing a done task is rejected",
+			edit: func(text string) string {
+				i := strings.Index(text, "  - id: task-001")
+				j := strings.Index(text, "  - id: task-002")
+				return text[:i] + text[j:]
+			},
+			wantErr: "can't be removed",
+		},
+		{
+			name:    "status change is rejected",
+			edit:    func(text string) string { return strings.Replace(text, "status: pending", "status: done", 1) },
+			wantErr: "status can't be changed",
+		},
+		{
+			name:    "invalid complexity",
+			edit:    func(text string) string { return strings.Replace(text, "complexity: medium", "complexity: huge", 1) },
+			wantErr: "task-002: complexity",
+		},
+		{
+			name: "dangling dependency",
+			edit: func(text string) string {
+				return strings.Replace(text, "depends_on: [task-001]", "depends_on: [task-009]", 1)
+			},
+			wantErr: "does not exist",
+		},
+		{
+			name: "cycle",
+			edit: func(text string) string {
+				text = strings.Replace(text, "depends_on: [task-001]", "depends_on: [new-1]", 1)
+				return text + "  - id: new-1\n    title: Loop\n    complexity: small\n    depends_on: [task-002]\n"
+			},
+			wantErr: "circular dependency",
+		},
+		{
+			name:    "duplicate id",
+			edit:    func(text string) string { return text + "  - id: task-002\n    title: Again\n    complexity: small\n" },
+			wantErr: "listed twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := planFileState()
+			entries, err := ParsePlanFile(tt.edit(FormatPlanFile(s)))
+			if err != nil {
+				t.Fatalf("ParsePlanFile: %v", err)
+			}
+			result, err := ApplyPlanFile(s.Tasks, entries, 3)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, task := range result {
+				ids = append(ids, task.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+			if s.Tasks[1].Title != "Add API" {
+				t.Error("input mutated")
+			}
+		})
+	}
+}
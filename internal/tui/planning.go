@@ -2,11 +2,23 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/analysis"
 	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/dbschema"
+	"github.com/manasm11/forge/internal/docs"
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/planner"
+	"github.com/manasm11/forge/internal/provider"
 	"github.com/manasm11/forge/internal/state"
 	"github.com/manasm11/forge/internal/tui/components"
 )
@@ -22,11 +34,85 @@ type PlanningModel struct {
 	firstMessageSent bool
 	restartConfirmed bool
 	width, height    int
+
+	// Settled decisions from .forge/answers.yaml (nil = none)
+	decided *planner.AnswerFile
+
+	// What earlier plans agreed, from .forge/memory.md (nil = none)
+	memory *planner.Memory
+
+	// The current plan's .forge/architecture.md ("" = none)
+	architecture string
+
+	// The history of a conversation branch just switched to, sent with the
+	// next first prompt ("" = none)
+	replay string
+
+	// Settings.ContextURLs documents and the database schema, fetched when
+	// the first message is sent
+	docs     string
+	dbSchema string
+
+	// Prompt size debugging, toggled with /debug
+	meter       *PromptMeter
+	promptStats []PromptStats
+	showStats   bool
+
+	// Why the last request to Ollama failed (nil = no panel)
+	diagnosis  *provider.OllamaDiagnosis
+	diagnosing bool
+
+	// The reply being waited for answers claude.JSONFixInstruction or
+	// claude.PlanRefsFixInstruction
+	fixSent bool
+
+	// The reply streaming in, and how many of its plan's tasks were shown
+	streamText    string
+	streamedTasks int
+
+	// A plan update that removes tasks with work on a branch, waiting for
+	// the user to keep or delete the branches (nil = none)
+	pendingUpdate   *claude.PlanUpdateJSON
+	pendingRemovals []*state.Task
+
+	// Alternative plans from /alternatives, compared in place of the chat
+	// until one is picked (nil = none)
+	candidates      *claude.PlanCandidatesJSON
+	candidateCursor int
+}
+
+// PlanProgressMsg carries the tasks of a <final_plan> that is still
+// streaming in, sent each time another one is complete so the review list
+// can fill in before the reply ends.
+type PlanProgressMsg struct {
+	Tasks []claude.PlanTaskJSON
 }
 
 // restartMsg signals that the chat should be restarted.
 type restartMsg struct{}
 
+// conversationBranchMsg reports a /fork, /switch or /branches result.
+// Reload redraws the chat from the current branch's history.
+type conversationBranchMsg struct {
+	Text   string
+	Reload bool
+}
+
+// promptStatsMsg reports the size of a request as it is sent.
+type promptStatsMsg struct {
+	Stats PromptStats
+}
+
+// toggleStatsMsg shows or hides the prompt size panel.
+type toggleStatsMsg struct{}
+
+// ollamaDiagnosisMsg reports the Ollama check run after a failed request.
+// Retry resends the last message once the check passes.
+type ollamaDiagnosisMsg struct {
+	Diagnosis provider.OllamaDiagnosis
+	Retry     bool
+}
+
 // NewPlanningModel creates a new planning phase model.
 func NewPlanningModel(s *state.State, root string, claudeClient claude.Claude, p *tea.Program) PlanningModel {
 	isReplanning := s.PlanVersion > 0 || len(s.Tasks) > 0
@@ -37,32 +123,31 @@ func NewPlanningModel(s *state.State, root string, claudeClient claude.Claude, p
 		claude:       claudeClient,
 		program:      p,
 		isReplanning: isReplanning,
+		meter:        &PromptMeter{},
 	}
 
 	sender := m.createSender()
-	handler := m.createSlashHandler()
-	chat := components.NewChatModel(sender, handler)
+	chat := components.NewChatModel(sender, nil)
+	chat.SetCommands(m.createCommands())
+	chat.SetReferences(components.References{Pattern: TaskRefRe, Resolve: func(id string) (string, bool) {
+		t := s.FindTask(id)
+		if t == nil {
+			return "", false
+		}
+		return FormatTaskReference(t), true
+	}})
 
 	if isReplanning {
 		replanCtx := BuildReplanContext(s)
 		chat.AddMessage(components.RoleSystem, BuildReplanSystemMessage(replanCtx))
 
 		// Restore previous conversation history
-		for _, msg := range s.ConversationHistory {
-			switch msg.Role {
-			case "user":
-				chat.AddMessage(components.RoleUser, msg.Content)
-			case "assistant":
-				chat.AddMessage(components.RoleAssistant, msg.Content)
-			case "system":
-				chat.AddMessage(components.RoleSystem, msg.Content)
-			}
-		}
+		showHistory(&chat, s.ConversationHistory)
 	} else {
 		welcome := "Welcome to Forge! \u2692\n\n" +
 			"I'll help you plan your project through conversation.\n" +
 			"Describe what you want to build and I'll ask questions to understand the details.\n\n" +
-			"Commands: /done \u00b7 /summary \u00b7 /restart"
+			"Commands: /done \u00b7 /preview \u00b7 /summary \u00b7 /todos \u00b7 /analyze \u00b7 /fork \u00b7 /restart \u00b7 /debug"
 		chat.AddMessage(components.RoleSystem, welcome)
 
 		// Show project snapshot if existing project detected
@@ -77,6 +162,12 @@ func NewPlanningModel(s *state.State, root string, claudeClient claude.Claude, p
 			if len(snap.Frameworks) > 0 {
 				details.WriteString(fmt.Sprintf("  Frameworks: %s\n", strings.Join(snap.Frameworks, ", ")))
 			}
+			if len(snap.TestFrameworks) > 0 {
+				details.WriteString(fmt.Sprintf("  Testing: %s\n", strings.Join(snap.TestFrameworks, ", ")))
+			}
+			if len(snap.CISystems) > 0 {
+				details.WriteString(fmt.Sprintf("  CI: %s\n", strings.Join(snap.CISystems, ", ")))
+			}
 			if snap.GitBranch != "" {
 				commitInfo := ""
 				if len(snap.RecentCommits) > 0 {
@@ -84,11 +175,36 @@ func NewPlanningModel(s *state.State, root string, claudeClient claude.Claude, p
 				}
 				details.WriteString(fmt.Sprintf("  Git: %s branch%s\n", snap.GitBranch, commitInfo))
 			}
+			if len(snap.Todos) > 0 {
+				details.WriteString(fmt.Sprintf("  TODOs: %d TODO/FIXME/HACK comments (/todos to review them as candidate tasks)\n", len(snap.Todos)))
+			}
 			details.WriteString("\nI'll suggest changes that fit your existing codebase.")
 			chat.AddMessage(components.RoleSystem, details.String())
 		}
 	}
 
+	decided, err := planner.LoadAnswerFile(filepath.Join(state.ForgeDir(root), planner.AnswerFileName))
+	if err != nil {
+		chat.AddMessage(components.RoleSystem, fmt.Sprintf("Ignoring answer file: %v", err))
+	} else if decided != nil {
+		chat.AddMessage(components.RoleSystem, "Using predetermined decisions from .forge/"+planner.AnswerFileName+"; the planner won't ask about them.")
+	}
+
+	memory, err := planner.LoadMemory(m.memoryPath())
+	if err != nil {
+		chat.AddMessage(components.RoleSystem, fmt.Sprintf("Ignoring project memory: %v", err))
+	} else if memory.PromptSection() != "" {
+		chat.AddMessage(components.RoleSystem, "Using project memory from .forge/"+planner.MemoryFileName+" (decisions, constraints and conventions from earlier plans).")
+	}
+
+	architecture, err := planner.LoadArchitecture(m.architecturePath())
+	if err != nil {
+		chat.AddMessage(components.RoleSystem, fmt.Sprintf("Ignoring architecture document: %v", err))
+	}
+
+	m.decided = decided
+	m.memory = memory
+	m.architecture = architecture
 	m.chat = chat
 	return m
 }
@@ -100,14 +216,46 @@ func (m PlanningModel) Init() tea.Cmd {
 func (m PlanningModel) Update(msg tea.Msg) (PlanningModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.candidates != nil {
+			return m.handleCandidateKey(msg)
+		}
+		if m.pendingUpdate != nil {
+			return m.handleBranchRemovalKey(msg)
+		}
 		switch msg.String() {
 		case "ctrl+n":
 			return m, func() tea.Msg {
 				return TransitionMsg{To: state.PhaseReview}
 			}
+		case "ctrl+r":
+			if m.diagnosis != nil && !m.diagnosing {
+				m.diagnosing = true
+				return m, m.diagnoseOllama(true)
+			}
+		case "esc":
+			if m.diagnosis != nil {
+				m.diagnosis = nil
+				m.SetSize(m.width, m.height)
+				return m, nil
+			}
+		}
+
+	case ollamaDiagnosisMsg:
+		m.diagnosing = false
+		var cmd tea.Cmd
+		if msg.Diagnosis.Problem == provider.OllamaOK {
+			m.diagnosis = nil
+			if msg.Retry {
+				cmd = m.chat.Retry()
+			}
+		} else {
+			m.diagnosis = &msg.Diagnosis
 		}
+		m.SetSize(m.width, m.height)
+		return m, cmd
 
 	case components.StreamStartMsg:
+		m.streamText, m.streamedTasks = "", 0
 		var cmd tea.Cmd
 		m.chat, cmd = m.chat.Update(msg)
 		return m, cmd
@@ -115,9 +263,15 @@ func (m PlanningModel) Update(msg tea.Msg) (PlanningModel, tea.Cmd) {
 	case components.StreamChunkMsg:
 		var cmd tea.Cmd
 		m.chat, cmd = m.chat.Update(msg)
+		m.streamText += msg.Chunk
+		if tasks := claude.PartialPlanTasks(m.streamText); len(tasks) > m.streamedTasks {
+			m.streamedTasks = len(tasks)
+			cmd = tea.Batch(cmd, func() tea.Msg { return PlanProgressMsg{Tasks: tasks} })
+		}
 		return m, cmd
 
 	case components.StreamDoneMsg:
+		m.streamText, m.streamedTasks = "", 0
 		// Let chat handle UI cleanup
 		var cmd tea.Cmd
 		m.chat, cmd = m.chat.Update(msg)
@@ -127,16 +281,24 @@ func (m PlanningModel) Update(msg tea.Msg) (PlanningModel, tea.Cmd) {
 		}
 
 		if msg.Err != nil {
+			if s := m.state.Settings; s != nil && s.Provider.Type == provider.ProviderOllama {
+				cmds = append(cmds, m.diagnoseOllama(false))
+			}
 			return m, tea.Batch(cmds...)
 		}
+		fixing := m.fixSent
+		m.fixSent = false
 
 		// Check for final plan tags (initial planning)
 		plan, err := claude.ExtractFinalPlan(msg.FullText)
 		if err != nil {
-			m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error parsing plan: %v", err))
+			cmds = append(cmds, m.fixJSON("final_plan", "plan", err, fixing))
 			return m, tea.Batch(cmds...)
 		}
 		if plan != nil {
+			for _, w := range plan.Warnings {
+				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Note: %s", w))
+			}
 			if err := m.applyFinalPlan(plan); err != nil {
 				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error applying plan: %v", err))
 				return m, tea.Batch(cmds...)
@@ -147,51 +309,94 @@ func (m PlanningModel) Update(msg tea.Msg) (PlanningModel, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		// A /preview reply: show the task list as a table and carry on
+		preview, err := claude.ExtractPlanPreview(msg.FullText)
+		if err != nil {
+			cmds = append(cmds, m.fixJSON("plan_preview", "plan preview", err, fixing))
+			return m, tea.Batch(cmds...)
+		}
+		if preview != nil {
+			reply := claude.StripTag(msg.FullText, "plan_preview")
+			m.chat.ReplaceLast(strings.TrimSpace(FormatPlanPreview(preview) + "\n\n" + reply))
+			return m, tea.Batch(cmds...)
+		}
+
+		// An /alternatives reply: compare the plans side by side
+		candidates, err := claude.ExtractPlanCandidates(msg.FullText)
+		if err != nil {
+			cmds = append(cmds, m.fixJSON("plan_candidates", "plan alternatives", err, fixing))
+			return m, tea.Batch(cmds...)
+		}
+		if candidates != nil {
+			for _, w := range candidates.Warnings {
+				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Note: %s", w))
+			}
+			names := make([]string, len(candidates.Candidates))
+			for i, c := range candidates.Candidates {
+				names[i] = fmt.Sprintf("%d. %s (%d tasks)", i+1, c.Name, len(c.Plan.Tasks))
+			}
+			reply := claude.StripTag(msg.FullText, "plan_candidates")
+			m.chat.ReplaceLast(strings.TrimSpace("Alternative plans: " + strings.Join(names, ", ") + "\n\n" + reply))
+			m.candidates, m.candidateCursor = candidates, 0
+			return m, tea.Batch(cmds...)
+		}
+
 		// Check for plan update tags (replanning)
 		update, err := claude.ExtractPlanUpdate(msg.FullText)
 		if err != nil {
-			m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error parsing plan update: %v", err))
+			cmds = append(cmds, m.fixJSON("plan_update", "plan update", err, fixing))
 			return m, tea.Batch(cmds...)
 		}
 		if update != nil {
-			// Validate before applying
-			warnings, valErr := ValidatePlanUpdate(m.state, update)
-			if valErr != nil {
-				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf(
-					"The plan update had an issue: %v\nCould you revise the update? Remember, completed tasks must stay as-is.", valErr))
-				return m, tea.Batch(cmds...)
-			}
-			// Show warnings but proceed
-			for _, w := range warnings {
-				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Note: %s", w))
-			}
-			if err := ApplyPlanUpdate(m.state, update); err != nil {
-				m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error applying plan update: %v", err))
+			// Map temporary IDs and loose references to task IDs
+			if err := planner.ResolvePlanUpdateRefs(m.state, update); err != nil {
+				var refErr *planner.UnresolvedRefsError
+				if errors.As(err, &refErr) {
+					cmds = append(cmds, m.fixRefs(refErr, fixing))
+				}
 				return m, tea.Batch(cmds...)
 			}
-			m.state.BumpPlanVersion(update.Summary)
-			_ = state.Save(m.stateRoot, m.state)
-			cmds = append(cmds, func() tea.Msg {
-				return TransitionMsg{To: state.PhaseReview}
-			})
+			cmds = append(cmds, m.applyPlanUpdate(update))
 			return m, tea.Batch(cmds...)
 		}
 
 		return m, tea.Batch(cmds...)
 
+	case promptStatsMsg:
+		m.promptStats = append(m.promptStats, msg.Stats)
+		return m, nil
+
+	case conversationBranchMsg:
+		if msg.Reload {
+			m.chat.ClearMessages()
+			showHistory(&m.chat, m.state.ConversationHistory)
+		}
+		m.chat.Notify(msg.Text)
+		return m, nil
+
+	case toggleStatsMsg:
+		m.showStats = !m.showStats
+		m.SetSize(m.width, m.height)
+		if m.showStats {
+			m.chat.Notify("Showing prompt sizes; /debug hides them.")
+		} else {
+			m.chat.Notify("Prompt sizes hidden.")
+		}
+		return m, nil
+
 	case restartMsg:
+		m.promptStats = nil
 		m.chat.ClearMessages()
 		m.firstMessageSent = false
 		m.restartConfirmed = false
 		if m.isReplanning {
 			replanCtx := BuildReplanContext(m.state)
-			m.chat.AddMessage(components.RoleSystem, fmt.Sprintf(
+			m.chat.Notify(fmt.Sprintf(
 				"Conversation restarted. You have %d completed, %d pending tasks.\nDescribe what changes you'd like.",
 				replanCtx.CompletedCount, replanCtx.PendingCount))
 		} else {
 			m.state.ConversationHistory = nil
-			m.chat.AddMessage(components.RoleSystem,
-				"Chat restarted. Describe what you want to build!")
+			m.chat.Notify("Chat restarted. Describe what you want to build!")
 		}
 		return m, nil
 	}
@@ -202,15 +407,144 @@ func (m PlanningModel) Update(msg tea.Msg) (PlanningModel, tea.Cmd) {
 }
 
 func (m PlanningModel) View() string {
-	return m.chat.View()
+	if m.candidates != nil {
+		return m.renderCandidates()
+	}
+	view := m.chat.View()
+	if m.pendingUpdate != nil {
+		view = m.renderBranchRemovals() + "\n" + view
+	}
+	if m.diagnosis != nil {
+		view = m.renderDiagnosis() + "\n" + view
+	}
+	if m.showStats {
+		view = m.renderStats() + "\n" + view
+	}
+	return view
 }
 
 func (m *PlanningModel) SetSize(w, h int) {
 	m.width = w
 	m.height = h
+	if m.showStats {
+		h -= lipgloss.Height(m.renderStats()) + 1
+	}
+	if m.diagnosis != nil {
+		h -= lipgloss.Height(m.renderDiagnosis()) + 1
+	}
+	if m.pendingUpdate != nil {
+		h -= lipgloss.Height(m.renderBranchRemovals()) + 1
+	}
 	m.chat.SetSize(w, h)
 }
 
+// renderDiagnosis shows why the last Ollama request failed.
+func (m PlanningModel) renderDiagnosis() string {
+	help := "ctrl+r retry · esc dismiss"
+	if m.diagnosing {
+		help = "Checking Ollama again…"
+	}
+	return lipgloss.NewStyle().
+		Foreground(Danger).
+		Border(components.BoxBorder(lipgloss.RoundedBorder())).
+		BorderForeground(Danger).
+		Padding(0, 1).
+		Width(max(20, m.width-2)).
+		Render(FormatOllamaDiagnosis(*m.diagnosis) + "\n" + HelpStyle.Render(help))
+}
+
+// renderCandidates shows the /alternatives plans side by side, the
+// selected one highlighted.
+func (m PlanningModel) renderCandidates() string {
+	help := HelpStyle.Render("←/→ select · enter or 1-9 pick and review · m merge them · esc back to chat")
+	n := len(m.candidates.Candidates)
+	colWidth := max(24, m.width/n)
+	height := max(5, m.height-lipgloss.Height(help))
+	cols := make([]string, n)
+	for i, c := range m.candidates.Candidates {
+		border := Border
+		if i == m.candidateCursor {
+			border = Primary
+		}
+		cols[i] = lipgloss.NewStyle().
+			Border(components.BoxBorder(lipgloss.RoundedBorder())).
+			BorderForeground(border).
+			Padding(0, 1).
+			Width(colWidth - 2).
+			Height(height - 2).
+			MaxHeight(height).
+			Render(FormatCandidate(i+1, c, colWidth-4))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, cols...), help)
+}
+
+// handleCandidateKey selects, picks or merges the /alternatives plans.
+func (m PlanningModel) handleCandidateKey(msg tea.KeyMsg) (PlanningModel, tea.Cmd) {
+	n := len(m.candidates.Candidates)
+	switch msg.String() {
+	case "left", "h", "shift+tab":
+		m.candidateCursor = (m.candidateCursor + n - 1) % n
+	case "right", "l", "tab":
+		m.candidateCursor = (m.candidateCursor + 1) % n
+	case "enter":
+		return m.pickCandidate(m.candidateCursor)
+	case "m":
+		m.candidates = nil
+		m.chat.AddMessage(components.RoleSystem, "Asking for one plan that merges the alternatives…")
+		return m, m.handleSlashCommand("/merge", claude.PlanMergeInstruction)
+	case "esc":
+		m.candidates = nil
+		m.chat.Notify("Alternatives closed. Keep talking, /alternatives to compare again or /done for one plan.")
+	default:
+		if i, err := strconv.Atoi(msg.String()); err == nil && i >= 1 && i <= n {
+			return m.pickCandidate(i - 1)
+		}
+	}
+	return m, nil
+}
+
+// pickCandidate applies alternative i as the plan and moves on to review.
+func (m PlanningModel) pickCandidate(i int) (PlanningModel, tea.Cmd) {
+	c := m.candidates.Candidates[i]
+	m.candidates = nil
+	m.state.AddConversationMessage("system", fmt.Sprintf("The user picked the %q plan.", c.Name))
+	if err := m.applyFinalPlan(&c.Plan); err != nil {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error applying plan: %v", err))
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return TransitionMsg{To: state.PhaseReview}
+	}
+}
+
+// handleAlternatives asks for alternative plans to compare. They are whole
+// plans, so only a first plan can be chosen this way.
+func (m *PlanningModel) handleAlternatives() tea.Cmd {
+	if m.isReplanning {
+		return branchNotice("/alternatives compares whole plans, so it is only available before the first plan. Describe the changes you want instead.", false)
+	}
+	return m.handleSlashCommand("/alternatives", claude.PlanCandidatesInstruction)
+}
+
+// diagnoseOllama checks the configured Ollama server and model in the
+// background.
+func (m PlanningModel) diagnoseOllama(retry bool) tea.Cmd {
+	cfg := m.state.Settings.Provider
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return ollamaDiagnosisMsg{Diagnosis: provider.DiagnoseOllama(ctx, cfg), Retry: retry}
+	}
+}
+
+func (m PlanningModel) renderStats() string {
+	return lipgloss.NewStyle().
+		Foreground(Muted).
+		Border(components.BoxBorder(lipgloss.NormalBorder()), false, false, true, false).
+		BorderForeground(Border).
+		Render(FormatPromptStats(m.promptStats))
+}
+
 // SetProgram sets the tea.Program reference for streaming.
 func (m *PlanningModel) SetProgram(p *tea.Program) {
 	m.program = p
@@ -220,8 +554,10 @@ func (m *PlanningModel) SetProgram(p *tea.Program) {
 func (m *PlanningModel) createSender() components.MessageSender {
 	return func(text string) tea.Cmd {
 		return func() tea.Msg {
-			// Save user message to conversation history
-			m.state.AddConversationMessage("user", text)
+			// Save user message to conversation history, once if retried
+			if h := m.state.ConversationHistory; len(h) == 0 || h[len(h)-1] != (state.ConversationMsg{Role: "user", Content: text}) {
+				m.state.AddConversationMessage("user", text)
+			}
 
 			if m.claude == nil {
 				return components.StreamDoneMsg{
@@ -243,6 +579,14 @@ func (m *PlanningModel) createSender() components.MessageSender {
 				}
 			}
 
+			first := !m.firstMessageSent
+			if first {
+				if m.state.Settings != nil {
+					m.docs = docs.Context(context.Background(), m.stateRoot, m.state.Settings.ContextURLs)
+				}
+				m.dbSchema = dbschema.Context(context.Background(), m.stateRoot, m.state.Settings)
+			}
+			m.recordPromptStats(first, text)
 			if !m.firstMessageSent {
 				m.firstMessageSent = true
 				prompt := m.buildFirstPrompt(text)
@@ -250,9 +594,11 @@ func (m *PlanningModel) createSender() components.MessageSender {
 			} else {
 				resp, err = m.claude.ContinueStreaming(context.Background(), text, onChunk)
 			}
+			m.recordExchange(first, text, resp, err)
 
 			// Save assistant response to conversation history
 			if err == nil && resp != nil {
+				m.meter.Reply(len(resp.Text))
 				m.state.AddConversationMessage("assistant", resp.Text)
 				_ = state.Save(m.stateRoot, m.state)
 			}
@@ -272,73 +618,98 @@ func (m *PlanningModel) createSender() components.MessageSender {
 
 // buildFirstPrompt constructs the initial prompt with system context.
 func (m *PlanningModel) buildFirstPrompt(userMessage string) string {
-	var prompt strings.Builder
+	system, snapshot := m.promptSections()
+	replay := m.replay
+	m.replay = ""
+	return system + snapshot + replay + fmt.Sprintf("\n\nUser: %s", userMessage)
+}
 
-	if m.isReplanning {
-		replanCtx := BuildReplanContext(m.state)
-		prompt.WriteString(BuildReplanPrompt(replanCtx))
+// recordPromptStats measures the request about to be sent and reports it
+// to the /debug panel.
+func (m *PlanningModel) recordPromptStats(first bool, message string) {
+	var stats PromptStats
+	if first {
+		system, snapshot := m.promptSections()
+		stats = m.meter.Start(len(system), len(snapshot), len(message))
 	} else {
-		prompt.WriteString(claude.InitialPlanningPrompt)
+		stats = m.meter.Continue(len(message))
+	}
+	if m.program != nil {
+		m.program.Send(promptStatsMsg{Stats: stats})
+	}
+}
 
-		// Append project context if available
-		if m.state.Snapshot != nil && m.state.Snapshot.IsExisting {
-			snap := m.state.Snapshot
-			prompt.WriteString("\n\nEXISTING PROJECT CONTEXT:\n")
-			if snap.Language != "" {
-				fmt.Fprintf(&prompt, "Language: %s\n", snap.Language)
-			}
-			if len(snap.Frameworks) > 0 {
-				fmt.Fprintf(&prompt, "Frameworks: %s\n", strings.Join(snap.Frameworks, ", "))
-			}
-			if len(snap.Dependencies) > 0 {
-				fmt.Fprintf(&prompt, "Dependencies: %s\n", strings.Join(snap.Dependencies, ", "))
-			}
-			if snap.Structure != "" {
-				fmt.Fprintf(&prompt, "Project Structure:\n%s\n", snap.Structure)
-			}
-			if len(snap.KeyFiles) > 0 {
-				fmt.Fprintf(&prompt, "Key Files: %s\n", strings.Join(snap.KeyFiles, ", "))
-			}
-			if len(snap.RecentCommits) > 0 {
-				prompt.WriteString("Recent Git History:\n")
-				for _, c := range snap.RecentCommits {
-					fmt.Fprintf(&prompt, "  %s\n", c)
-				}
-			}
-			if snap.ReadmeContent != "" {
-				fmt.Fprintf(&prompt, "README Summary:\n%s\n", snap.ReadmeContent)
-			}
-			if snap.ClaudeMD != "" {
-				fmt.Fprintf(&prompt, "CLAUDE.md:\n%s\n", snap.ClaudeMD)
-			}
-		}
+// recordExchange journals the request and response for `forge transcript`.
+// The first request of a session also carries the planning context.
+func (m *PlanningModel) recordExchange(first bool, message string, resp *claude.Response, err error) {
+	x := executor.Exchange{Prompt: message}
+	if first {
+		system, snapshot := m.promptSections()
+		x.System = system + snapshot
+		x.Prompt = "User: " + message
+	}
+	if resp != nil {
+		x.Response = resp.Text
+	}
+	if err != nil {
+		x.Error = err.Error()
 	}
+	journal, jerr := executor.OpenJournal(m.stateRoot)
+	if jerr != nil {
+		return
+	}
+	defer journal.Close()
+	journal.AppendExchange(0, "", x)
+}
 
-	fmt.Fprintf(&prompt, "\n\nUser: %s", userMessage)
-	return prompt.String()
+// promptSections returns the system context (including any answer-file
+// decisions, project memory, the architecture document when replanning, reference documents
+// and database schema) and the existing-project snapshot
+// that open every planning session.
+func (m *PlanningModel) promptSections() (string, string) {
+	if m.isReplanning {
+		return BuildReplanPrompt(BuildReplanContext(m.state)) + m.decided.PromptSection() + m.memory.PromptSection() +
+			planner.ArchitectureSection(m.architecture) + m.docs + m.dbSchema, ""
+	}
+
+	return claude.InitialPlanningPrompt + m.decided.PromptSection() + m.memory.PromptSection() + m.docs + m.dbSchema, claude.ProjectContext(m.state.Snapshot)
 }
 
-// createSlashHandler returns the slash command handler for the planning phase.
-func (m *PlanningModel) createSlashHandler() components.SlashHandler {
-	return func(cmd components.SlashCommand) (tea.Cmd, bool) {
-		switch cmd.Name {
-		case "done":
-			return m.handleSlashCommand("/done", m.doneInstruction()), true
-		case "summary":
-			return m.handleSlashCommand("/summary", "Please summarize your current understanding of the project and what you'd include in the plan."), true
-		case "restart":
-			return m.handleRestart(), true
-		default:
-			return nil, false
-		}
+// createCommands registers the planning phase's slash commands.
+func (m *PlanningModel) createCommands() *components.Commands {
+	ask := func(name, instruction string) func(string) tea.Cmd {
+		return func(string) tea.Cmd { return m.handleSlashCommand("/"+name, instruction) }
 	}
+	return components.NewCommands().
+		Register(components.Command{Name: "done", Description: "generate the plan and review it",
+			Run: func(string) tea.Cmd { return m.handleSlashCommand("/done", m.doneInstruction()) }}).
+		Register(components.Command{Name: "preview", Description: "show a provisional task list and keep talking",
+			Run: ask("preview", claude.PlanPreviewInstruction)}).
+		Register(components.Command{Name: "alternatives", Description: "propose 2 alternative plans to compare and pick from",
+			Run: func(string) tea.Cmd { return m.handleAlternatives() }}).
+		Register(components.Command{Name: "summary", Description: "summarize what the plan would include",
+			Run: ask("summary", "Please summarize your current understanding of the project and what you'd include in the plan.")}).
+		Register(components.Command{Name: "analyze", Description: "run static analysis and coverage, propose cleanup tasks",
+			Run: func(string) tea.Cmd { return m.handleAnalyze() }}).
+		Register(components.Command{Name: "todos", Description: "turn TODO/FIXME/HACK comments into candidate tasks",
+			Run: ask("todos", "List the TODO/FIXME/HACK comments you found in the project, grouped into candidate tasks, and ask which ones I want included in the plan.")}).
+		Register(components.Command{Name: "fork", Args: "<name>", Description: "explore a variant in a new conversation branch",
+			Run: m.handleFork}).
+		Register(components.Command{Name: "switch", Args: "<name>", Description: "change conversation branch",
+			Run: m.handleSwitch}).
+		Register(components.Command{Name: "branches", Description: "list the conversation branches",
+			Run: func(string) tea.Cmd { return branchNotice(FormatConversationBranches(m.state), false) }}).
+		Register(components.Command{Name: "restart", Description: "start the conversation over",
+			Run: func(string) tea.Cmd { return m.handleRestart() }}).
+		Register(components.Command{Name: "debug", Description: "show or hide prompt sizes",
+			Run: func(string) tea.Cmd { return func() tea.Msg { return toggleStatsMsg{} } }})
 }
 
 func (m *PlanningModel) doneInstruction() string {
 	if m.isReplanning {
 		return "The user has requested the updated plan. Based on everything discussed, generate the plan update now. Output inside <plan_update> tags with the JSON format specified."
 	}
-	return "The user has requested the final plan. Based on everything discussed, generate the plan now. Output inside <final_plan> tags with the JSON format specified."
+	return claude.FinalPlanInstruction
 }
 
 // handleSlashCommand sends a command through the streaming sender.
@@ -368,6 +739,8 @@ func (m *PlanningModel) handleSlashCommand(cmdName, instruction string) tea.Cmd
 			}
 		}
 
+		first := !m.firstMessageSent
+		m.recordPromptStats(first, instruction)
 		if !m.firstMessageSent {
 			m.firstMessageSent = true
 			prompt := m.buildFirstPrompt(instruction)
@@ -375,8 +748,10 @@ func (m *PlanningModel) handleSlashCommand(cmdName, instruction string) tea.Cmd
 		} else {
 			resp, err = m.claude.ContinueStreaming(context.Background(), instruction, onChunk)
 		}
+		m.recordExchange(first, instruction, resp, err)
 
 		if err == nil && resp != nil {
+			m.meter.Reply(len(resp.Text))
 			m.state.AddConversationMessage("assistant", resp.Text)
 			_ = state.Save(m.stateRoot, m.state)
 		}
@@ -393,6 +768,194 @@ func (m *PlanningModel) handleSlashCommand(cmdName, instruction string) tea.Cmd
 	}
 }
 
+// fixJSON handles a <tag> block whose JSON could not be used: the first
+// time it asks the model to correct it; if the correction fails too
+// (fixing), it shows the error.
+func (m *PlanningModel) fixJSON(tag, what string, err error, fixing bool) tea.Cmd {
+	if fixing {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error parsing %s: %v", what, err))
+		return nil
+	}
+	m.fixSent = true
+	m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("The %s's JSON could not be used (%v) — asking for a corrected version…", what, err))
+	return m.handleSlashCommand("/fix-json", claude.JSONFixInstruction(tag, err))
+}
+
+// applyPlanUpdate validates a resolved plan update, applies it and moves on
+// to review. An update removing tasks with work on a branch waits in
+// pendingUpdate until the user says what happens to the branches.
+func (m *PlanningModel) applyPlanUpdate(update *claude.PlanUpdateJSON) tea.Cmd {
+	warnings, valErr := ValidatePlanUpdate(m.state, update)
+	var branchErr *BranchRemovalError
+	if errors.As(valErr, &branchErr) {
+		m.pendingUpdate, m.pendingRemovals = update, nil
+		for _, id := range branchErr.TaskIDs {
+			m.pendingRemovals = append(m.pendingRemovals, m.state.FindTask(id))
+		}
+		m.SetSize(m.width, m.height)
+		return nil
+	}
+	if valErr != nil {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf(
+			"The plan update had an issue: %v\nCould you revise the update? Remember, completed tasks must stay as-is.", valErr))
+		return nil
+	}
+	// Show warnings but proceed
+	for _, w := range append(update.Warnings, warnings...) {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Note: %s", w))
+	}
+	if err := ApplyPlanUpdate(m.state, update); err != nil {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Error applying plan update: %v", err))
+		return nil
+	}
+	m.deleteRemovedBranches(update)
+	m.state.BumpPlanVersion(update.Summary)
+	_ = state.Save(m.stateRoot, m.state)
+	m.remember(update.Memory)
+	m.saveArchitecture(update.Architecture)
+	return func() tea.Msg {
+		return TransitionMsg{To: state.PhaseReview}
+	}
+}
+
+// handleBranchRemovalKey answers the confirmation for a plan update that
+// removes tasks with work on a branch.
+func (m PlanningModel) handleBranchRemovalKey(msg tea.KeyMsg) (PlanningModel, tea.Cmd) {
+	var disposition state.BranchDisposition
+	switch msg.String() {
+	case "k":
+		disposition = state.BranchKept
+	case "d":
+		disposition = state.BranchDeleted
+	case "esc":
+		m.pendingUpdate, m.pendingRemovals = nil, nil
+		m.SetSize(m.width, m.height)
+		m.chat.AddMessage(components.RoleSystem, "The plan update was not applied. Tell the model which tasks to keep, or ask for the update again.")
+		return m, nil
+	default:
+		return m, nil
+	}
+
+	update := m.pendingUpdate
+	ids := make([]string, len(m.pendingRemovals))
+	for i, t := range m.pendingRemovals {
+		ids[i] = t.ID
+	}
+	for i := range update.Tasks {
+		if t := &update.Tasks[i]; t.Action == "remove" && slices.Contains(ids, t.ID) {
+			t.BranchDisposition = string(disposition)
+		}
+	}
+	m.pendingUpdate, m.pendingRemovals = nil, nil
+	m.SetSize(m.width, m.height)
+	m.state.AddConversationMessage("system", fmt.Sprintf("The user confirmed removing %s; their branches are %s.", strings.Join(ids, ", "), disposition))
+	return m, m.applyPlanUpdate(update)
+}
+
+// deleteRemovedBranches deletes the local branches of removed tasks the
+// user chose to delete. A branch that can't be deleted is recorded as kept.
+func (m *PlanningModel) deleteRemovedBranches(update *claude.PlanUpdateJSON) {
+	for _, u := range update.Tasks {
+		task := m.state.FindTask(u.ID)
+		if u.Action != "remove" || task == nil || task.BranchDisposition != state.BranchDeleted || task.Branch == "" {
+			continue
+		}
+		git := executor.NewRealGitOps(filepath.Join(m.stateRoot, task.Repo))
+		if err := git.DeleteBranch(context.Background(), task.Branch); err != nil {
+			task.BranchDisposition = state.BranchKept
+			m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Branch %s of %s was kept: %v", task.Branch, task.ID, err))
+		}
+	}
+}
+
+// renderBranchRemovals asks what to do with the branches of removed tasks.
+func (m PlanningModel) renderBranchRemovals() string {
+	return lipgloss.NewStyle().
+		Foreground(Warning).
+		Border(components.BoxBorder(lipgloss.RoundedBorder())).
+		BorderForeground(Warning).
+		Padding(0, 1).
+		Width(max(20, m.width-2)).
+		Render(FormatBranchRemovals(m.pendingRemovals) + "\n" +
+			HelpStyle.Render("k remove the tasks, keep the branches · d remove them and delete the local branches · esc don't apply the update"))
+}
+
+// fixRefs asks the model once to resend a plan update whose dependencies
+// could not be resolved, listing each one; a second failure is shown.
+func (m *PlanningModel) fixRefs(err *planner.UnresolvedRefsError, fixing bool) tea.Cmd {
+	if fixing {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("The plan update's task references could not be resolved:\n- %s",
+			strings.Join(err.Problems, "\n- ")))
+		return nil
+	}
+	m.fixSent = true
+	m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("%d task reference%s in the plan update could not be resolved — asking for a corrected update…",
+		len(err.Problems), pluralize(len(err.Problems))))
+	return m.handleSlashCommand("/fix-refs", claude.PlanRefsFixInstruction(err.Problems))
+}
+
+// handleAnalyze runs the project's static analysis and coverage tools, then
+// sends their findings to the model to propose cleanup and test-gap tasks.
+func (m *PlanningModel) handleAnalyze() tea.Cmd {
+	m.chat.AddMessage(components.RoleSystem, "Running static analysis and reading coverage reports…")
+	return func() tea.Msg {
+		reports, errs := analysis.Run(context.Background(), m.stateRoot)
+		return m.handleSlashCommand("/analyze", analysis.Instruction(reports, errs))()
+	}
+}
+
+// handleFork starts a conversation branch from the current one. The model
+// session carries on as is: up to here, both branches share it.
+func (m *PlanningModel) handleFork(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return branchNotice("Usage: /fork <name>, e.g. /fork microservices", false)
+	}
+	from := m.state.CurrentConversation()
+	if err := m.state.ForkConversation(name); err != nil {
+		return branchNotice(err.Error(), false)
+	}
+	_ = state.Save(m.stateRoot, m.state)
+	return branchNotice(fmt.Sprintf("Forked %q from %q. Explore the variant here; /switch %s goes back.", name, from, from), false)
+}
+
+// handleSwitch makes another conversation branch current. The next message
+// opens a new model session that replays the branch's history.
+func (m *PlanningModel) handleSwitch(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return branchNotice(FormatConversationBranches(m.state), false)
+	}
+	if name == m.state.CurrentConversation() {
+		return branchNotice(fmt.Sprintf("Already on %q.", name), false)
+	}
+	if err := m.state.SwitchConversation(name); err != nil {
+		return branchNotice(err.Error(), false)
+	}
+	_ = state.Save(m.stateRoot, m.state)
+	m.firstMessageSent = false
+	m.replay = FormatConversationReplay(name, m.state.ConversationHistory)
+	return branchNotice(fmt.Sprintf("Switched to conversation branch %q.", name), true)
+}
+
+func branchNotice(text string, reload bool) tea.Cmd {
+	return func() tea.Msg { return conversationBranchMsg{Text: text, Reload: reload} }
+}
+
+// showHistory adds a stored conversation to the chat.
+func showHistory(chat *components.ChatModel, history []state.ConversationMsg) {
+	for _, msg := range history {
+		switch msg.Role {
+		case "user":
+			chat.AddMessage(components.RoleUser, msg.Content)
+		case "assistant":
+			chat.AddMessage(components.RoleAssistant, msg.Content)
+		case "system":
+			chat.AddMessage(components.RoleSystem, msg.Content)
+		}
+	}
+}
+
 func (m *PlanningModel) handleRestart() tea.Cmd {
 	if m.isReplanning && !m.restartConfirmed {
 		m.restartConfirmed = true
@@ -411,9 +974,47 @@ func (m *PlanningModel) applyFinalPlan(plan *claude.PlanJSON) error {
 	if err := state.Save(m.stateRoot, m.state); err != nil {
 		return fmt.Errorf("failed to save state: %w", err)
 	}
+	m.remember(plan.Memory)
+	m.saveArchitecture(plan.Architecture)
 	return nil
 }
 
+func (m *PlanningModel) memoryPath() string {
+	return filepath.Join(state.ForgeDir(m.stateRoot), planner.MemoryFileName)
+}
+
+// remember adds an accepted plan's memory entries to the project memory.
+// A write failure is reported but doesn't undo the plan.
+func (m *PlanningModel) remember(j *claude.MemoryJSON) {
+	added, err := planner.RememberPlan(m.memoryPath(), j)
+	if err != nil {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Could not update the project memory: %v", err))
+		return
+	}
+	if added > 0 {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Added %d entries to the project memory (.forge/%s).", added, planner.MemoryFileName))
+		m.memory, _ = planner.LoadMemory(m.memoryPath())
+	}
+}
+
+func (m *PlanningModel) architecturePath() string {
+	return filepath.Join(state.ForgeDir(m.stateRoot), planner.ArchitectureFileName)
+}
+
+// saveArchitecture replaces the architecture document with an accepted
+// plan's. A write failure is reported but doesn't undo the plan.
+func (m *PlanningModel) saveArchitecture(doc string) {
+	saved, err := planner.SaveArchitecture(m.architecturePath(), doc)
+	if err != nil {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Could not save the architecture document: %v", err))
+		return
+	}
+	if saved {
+		m.chat.AddMessage(components.RoleSystem, fmt.Sprintf("Saved the architecture document to .forge/%s; every task prompt includes it.", planner.ArchitectureFileName))
+		m.architecture, _ = planner.LoadArchitecture(m.architecturePath())
+	}
+}
+
 // formatLOC formats a line count for display (e.g., 3200 -> "3,200").
 func formatLOC(loc int) string {
 	s := fmt.Sprintf("%d", loc)
@@ -2,9 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/provider"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // ApplyInitialPlan converts a PlanJSON into tasks and updates state.
@@ -31,13 +35,38 @@ func ApplyInitialPlan(s *state.State, plan *claude.PlanJSON) error {
 				deps = append(deps, taskIDs[depIdx])
 			}
 		}
-		s.AddTask(pt.Title, pt.Description, pt.Complexity, pt.AcceptanceCriteria, deps)
+		task := s.AddTask(pt.Title, pt.Description, pt.Complexity, pt.AcceptanceCriteria, deps)
+		setTaskType(task, pt.Type, pt.Commands)
+		task.Artifacts = pt.Artifacts
+		task.Repo = pt.Repo
 	}
 
 	s.BumpPlanVersion("Initial plan")
 	return nil
 }
 
+// PartialPlanTasks turns the tasks of a plan still streaming in into
+// tasks, with the IDs and dependencies ApplyInitialPlan will give them, for
+// the read-only list shown while the plan is generated.
+func PartialPlanTasks(tasks []claude.PlanTaskJSON) []state.Task {
+	preview := &state.State{}
+	if err := ApplyInitialPlan(preview, &claude.PlanJSON{ProjectName: "preview", Tasks: tasks}); err != nil {
+		return nil
+	}
+	return preview.Tasks
+}
+
+// setTaskType applies a planner-supplied task type. Unknown types fall back
+// to code so a model typo never turns a task into something that skips Claude.
+func setTaskType(task *state.Task, taskType string, commands []string) {
+	t := state.TaskType(taskType)
+	if !state.ValidTaskType(t) {
+		t = ""
+	}
+	task.Type = t
+	task.Commands = commands
+}
+
 // ApplyPlanUpdate applies a PlanUpdateJSON diff to existing state tasks.
 // Returns an error if any action is invalid (e.g., modifying a completed task).
 func ApplyPlanUpdate(s *state.State, update *claude.PlanUpdateJSON) error {
@@ -69,10 +98,26 @@ func ApplyPlanUpdate(s *state.State, update *claude.PlanUpdateJSON) error {
 			if t.Complexity != "" {
 				task.Complexity = t.Complexity
 			}
+			if t.Type != "" {
+				setTaskType(task, t.Type, t.Commands)
+			}
+			if len(t.Artifacts) > 0 {
+				task.Artifacts = t.Artifacts
+			}
+			if t.Repo != "" {
+				task.Repo = t.Repo
+			}
 			task.PlanVersionModified = s.PlanVersion + 1
 
 		case "add":
-			s.AddTask(t.Title, t.Description, t.Complexity, t.AcceptanceCriteria, t.DependsOn)
+			// planner.ResolvePlanUpdateRefs sets the ID the task will get
+			if t.ID != "" && t.ID != s.NextTaskID() {
+				return fmt.Errorf("add: task %q was resolved as %s but would be created as %s", t.Title, t.ID, s.NextTaskID())
+			}
+			task := s.AddTask(t.Title, t.Description, t.Complexity, t.AcceptanceCriteria, t.DependsOn)
+			setTaskType(task, t.Type, t.Commands)
+			task.Artifacts = t.Artifacts
+			task.Repo = t.Repo
 
 		case "remove":
 			if t.ID == "" {
@@ -92,6 +137,7 @@ func ApplyPlanUpdate(s *state.State, update *claude.PlanUpdateJSON) error {
 			if err := s.CancelTask(t.ID, reason); err != nil {
 				return fmt.Errorf("remove: %w", err)
 			}
+			task.BranchDisposition = state.BranchDisposition(t.BranchDisposition)
 
 		default:
 			return fmt.Errorf("unknown action %q for task %q", t.Action, t.ID)
@@ -100,3 +146,258 @@ func ApplyPlanUpdate(s *state.State, update *claude.PlanUpdateJSON) error {
 
 	return nil
 }
+
+// PromptStats is the size, in characters, of each part of one planning
+// request. Nothing leaves the machine; it only feeds the /debug panel.
+type PromptStats struct {
+	System   int // planning instructions, or the replan context
+	Snapshot int // existing-project section (0 when replanning)
+	History  int // earlier turns the CLI session replays
+	Message  int // the new user message
+}
+
+// Total is the full request size in characters.
+func (p PromptStats) Total() int {
+	return p.System + p.Snapshot + p.History + p.Message
+}
+
+// EstimateTokens approximates a token count from a character count using
+// the common ~4 characters per token rule of thumb.
+func EstimateTokens(chars int) int {
+	return (chars + 3) / 4
+}
+
+// PromptMeter tracks request sizes across a planning session. The first
+// request carries the system context and snapshot; the CLI resends them
+// along with every earlier turn on each continuation.
+type PromptMeter struct {
+	system, snapshot int
+	history          int
+	pending          int // message of the request awaiting a reply
+}
+
+// Start measures the first request of a session, discarding any history.
+func (pm *PromptMeter) Start(system, snapshot, message int) PromptStats {
+	pm.system, pm.snapshot, pm.history, pm.pending = system, snapshot, 0, message
+	return pm.stats(message)
+}
+
+// Continue measures a follow-up request in the same session.
+func (pm *PromptMeter) Continue(message int) PromptStats {
+	pm.pending = message
+	return pm.stats(message)
+}
+
+// Reply records the assistant's answer so the exchange counts as history
+// for the next request.
+func (pm *PromptMeter) Reply(text int) {
+	pm.history += pm.pending + text
+	pm.pending = 0
+}
+
+func (pm *PromptMeter) stats(message int) PromptStats {
+	return PromptStats{System: pm.system, Snapshot: pm.snapshot, History: pm.history, Message: message}
+}
+
+// FormatPromptStats renders the /debug panel for the most recent request
+// and the largest one sent this session.
+func FormatPromptStats(requests []PromptStats) string {
+	if len(requests) == 0 {
+		return "Prompt sizes: no requests sent yet"
+	}
+	last := requests[len(requests)-1]
+	largest := 0
+	for _, r := range requests {
+		largest = max(largest, r.Total())
+	}
+
+	var b strings.Builder
+	b.WriteString("Prompt sizes (last request, ~4 chars/token)\n")
+	row := func(name string, chars int) {
+		fmt.Fprintf(&b, "  %-9s %10s chars  ~%s tokens\n", name, formatLOC(chars), formatLOC(EstimateTokens(chars)))
+	}
+	row("system", last.System)
+	row("snapshot", last.Snapshot)
+	row("history", last.History)
+	row("message", last.Message)
+	row("total", last.Total())
+	fmt.Fprintf(&b, "  %d requests this session · largest ~%s tokens", len(requests), formatLOC(EstimateTokens(largest)))
+	return b.String()
+}
+
+// FormatConversationReplay renders a conversation branch's history for the
+// first prompt after switching to it: the model's session belongs to
+// whichever branch spoke last, so the branch's history is replayed instead.
+// Returns "" for an empty history.
+func FormatConversationReplay(branch string, history []state.ConversationMsg) string {
+	if len(history) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nCONVERSATION SO FAR (branch %q — pick up where it left off):\n", branch)
+	for _, msg := range history {
+		role := "User"
+		switch msg.Role {
+		case "assistant":
+			role = "Assistant"
+		case "system":
+			role = "Note"
+		}
+		fmt.Fprintf(&b, "\n%s: %s\n", role, msg.Content)
+	}
+	return b.String()
+}
+
+// FormatConversationBranches lists the planning conversation's branches
+// for /branches, marking the current one.
+func FormatConversationBranches(s *state.State) string {
+	var b strings.Builder
+	b.WriteString("Conversation branches:\n")
+	for _, name := range s.ConversationBranches() {
+		if name == s.CurrentConversation() {
+			fmt.Fprintf(&b, "  * %s (current, %d message%s)\n", name, len(s.ConversationHistory), pluralize(len(s.ConversationHistory)))
+			continue
+		}
+		for _, f := range s.ConversationForks {
+			if f.Name == name {
+				fmt.Fprintf(&b, "    %s (%d message%s)\n", name, len(f.History), pluralize(len(f.History)))
+			}
+		}
+	}
+	b.WriteString("\n/fork <name> explores a variant · /switch <name> changes branch")
+	return b.String()
+}
+
+const previewTitleWidth = 44
+
+// FormatPlanPreview renders a /preview task list as a table, numbering
+// tasks from 1 as dependencies refer to them.
+func FormatPlanPreview(plan *claude.PlanJSON) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Provisional plan: %d task%s (not final, /done when it looks right)\n\n", len(plan.Tasks), pluralize(len(plan.Tasks)))
+	fmt.Fprintf(&b, "%3s  %-*s  %-7s  %s\n", "#", previewTitleWidth, "Task", "Size", "After")
+	for i, t := range plan.Tasks {
+		title := []rune(t.Title)
+		if len(title) > previewTitleWidth {
+			title = append([]rune(strings.TrimRight(string(title[:previewTitleWidth-1]), " ")), '…')
+		}
+		// pad by runes; %-*s counts bytes
+		title = append(title, []rune(strings.Repeat(" ", previewTitleWidth-len(title)))...)
+		size := t.Complexity
+		if size == "" {
+			size = "-"
+		}
+		after := "-"
+		if len(t.DependsOn) > 0 {
+			deps := make([]string, len(t.DependsOn))
+			for j, d := range t.DependsOn {
+				deps[j] = fmt.Sprint(d + 1)
+			}
+			after = strings.Join(deps, ",")
+		}
+		fmt.Fprintf(&b, "%3d  %s  %-7s  %s\n", i+1, string(title), size, after)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// LongestChain is the number of tasks in a plan's longest dependency
+// chain: how much of it has to run one task after another.
+func LongestChain(tasks []claude.PlanTaskJSON) int {
+	depth := make([]int, len(tasks))
+	longest := 0
+	for i, t := range tasks {
+		depth[i] = 1
+		for _, d := range t.DependsOn {
+			if d >= 0 && d < i {
+				depth[i] = max(depth[i], depth[d]+1)
+			}
+		}
+		longest = max(longest, depth[i])
+	}
+	return longest
+}
+
+// FormatCandidate renders alternative n of an /alternatives reply as a
+// column of the comparison view, task titles cut to width.
+func FormatCandidate(n int, c claude.PlanCandidateJSON, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d. %s\n", n, c.Name)
+	if c.Summary != "" {
+		b.WriteString(c.Summary + "\n")
+	}
+
+	sizes := map[string]int{}
+	for _, t := range c.Plan.Tasks {
+		sizes[t.Complexity]++
+	}
+	fmt.Fprintf(&b, "\n%d task%s · %d small · %d medium · %d large\n", len(c.Plan.Tasks), pluralize(len(c.Plan.Tasks)),
+		sizes["small"], sizes["medium"], sizes["large"])
+	fmt.Fprintf(&b, "Longest chain: %d\n", LongestChain(c.Plan.Tasks))
+	if len(c.Plan.TechStack) > 0 {
+		fmt.Fprintf(&b, "Stack: %s\n", strings.Join(c.Plan.TechStack, ", "))
+	}
+	b.WriteString("\n")
+
+	for i, t := range c.Plan.Tasks {
+		line := []rune(fmt.Sprintf("%2d %s", i+1, t.Title))
+		if width > 1 && len(line) > width {
+			line = append(line[:width-1], '…')
+		}
+		b.WriteString(string(line) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// TaskRefRe matches task IDs mentioned in the planning chat.
+var TaskRefRe = regexp.MustCompile(`\btask-\d{3,}\b`)
+
+// FormatTaskReference renders a task's current details for an expanded
+// reference in the planning chat.
+func FormatTaskReference(t *state.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s · %s [%s]", t.ID, t.Title, t.Status)
+	if t.Complexity != "" {
+		fmt.Fprintf(&b, " (%s)", t.Complexity)
+	}
+	if t.Type != "" && t.Type != state.TaskTypeCode {
+		fmt.Fprintf(&b, " %s task", t.Type)
+	}
+	b.WriteString("\n")
+	if len(t.DependsOn) > 0 {
+		fmt.Fprintf(&b, "After: %s\n", strings.Join(t.DependsOn, ", "))
+	}
+	if t.Description != "" {
+		b.WriteString(t.Description + "\n")
+	}
+	for _, c := range t.AcceptanceCriteria {
+		b.WriteString("  " + components.Glyph("✓", "+") + " " + c + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ollamaProblemLabels name each diagnosis for the error panel.
+var ollamaProblemLabels = map[provider.OllamaProblem]string{
+	provider.OllamaBadConfig:     "auth or TLS settings",
+	provider.OllamaUnreachable:   "daemon not reachable",
+	provider.OllamaBadStatus:     "server error",
+	provider.OllamaNoModelList:   "model list unavailable",
+	provider.OllamaModelNotFound: "model not found",
+}
+
+// FormatOllamaDiagnosis renders the planning chat's Ollama error panel:
+// what was tried, what went wrong and what to do about it.
+func FormatOllamaDiagnosis(d provider.OllamaDiagnosis) string {
+	var b strings.Builder
+	problem := ollamaProblemLabels[d.Problem]
+	if d.HTTPCode != 0 {
+		problem += fmt.Sprintf(" (HTTP %d)", d.HTTPCode)
+	}
+	fmt.Fprintf(&b, "Ollama request failed: %s\n", problem)
+	fmt.Fprintf(&b, "URL:   %s\n", d.URL)
+	fmt.Fprintf(&b, "Model: %s\n", d.Model)
+	if d.Detail != "" {
+		fmt.Fprintf(&b, "Error: %s\n", d.Detail)
+	}
+	b.WriteString(d.Hint())
+	return b.String()
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/provider"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -63,6 +65,24 @@ func TestApplyInitialPlan(t *testing.T) {
 	}
 }
 
+func TestApplyInitialPlan_Repo(t *testing.T) {
+	t.Parallel()
+	plan := &claude.PlanJSON{
+		ProjectName: "shop",
+		Tasks: []claude.PlanTaskJSON{
+			{Title: "Add cart API", Complexity: "small", Repo: "backend"},
+			{Title: "Add cart page", Complexity: "small", Repo: "frontend", DependsOn: []int{0}},
+		},
+	}
+	s := &state.State{}
+	if err := ApplyInitialPlan(s, plan); err != nil {
+		t.Fatal(err)
+	}
+	if s.Tasks[0].Repo != "backend" || s.Tasks[1].Repo != "frontend" {
+		t.Errorf("repos = %q, %q; want backend, frontend", s.Tasks[0].Repo, s.Tasks[1].Repo)
+	}
+}
+
 func TestApplyInitialPlan_MissingProjectName(t *testing.T) {
 	t.Parallel()
 	plan := &claude.PlanJSON{
@@ -108,6 +128,55 @@ func TestApplyInitialPlan_OutOfRangeDependency(t *testing.T) {
 	}
 }
 
+func TestApplyInitialPlan_TaskTypes(t *testing.T) {
+	t.Parallel()
+	plan := &claude.PlanJSON{
+		ProjectName: "test",
+		Tasks: []claude.PlanTaskJSON{
+			{Title: "Code", Complexity: "small"},
+			{Title: "E2E", Complexity: "small", Type: "verify", Commands: []string{"make e2e"}, Artifacts: []string{"e2e-report/*"}},
+			{Title: "Rotate key", Complexity: "small", Type: "manual"},
+			{Title: "Typo", Complexity: "small", Type: "manaul"},
+		},
+	}
+	s := &state.State{}
+	if err := ApplyInitialPlan(s, plan); err != nil {
+		t.Fatalf("ApplyInitialPlan() error: %v", err)
+	}
+
+	want := []state.TaskType{state.TaskTypeCode, state.TaskTypeVerify, state.TaskTypeManual, state.TaskTypeCode}
+	for i, w := range want {
+		if got := s.Tasks[i].EffectiveType(); got != w {
+			t.Errorf("task %d type = %q, want %q", i, got, w)
+		}
+	}
+	if len(s.Tasks[1].Commands) != 1 || s.Tasks[1].Commands[0] != "make e2e" {
+		t.Errorf("verify commands = %v", s.Tasks[1].Commands)
+	}
+	if len(s.Tasks[1].Artifacts) != 1 || s.Tasks[1].Artifacts[0] != "e2e-report/*" {
+		t.Errorf("artifacts = %v", s.Tasks[1].Artifacts)
+	}
+}
+
+func TestPartialPlanTasks(t *testing.T) {
+	t.Parallel()
+	if got := PartialPlanTasks(nil); got != nil {
+		t.Errorf("no tasks yet = %v, want nil", got)
+	}
+
+	got := PartialPlanTasks([]claude.PlanTaskJSON{
+		{Title: "Init", Complexity: "small"},
+		{Title: "API", Complexity: "medium", DependsOn: []int{0, 5}},
+	})
+	if len(got) != 2 || got[0].ID != "task-001" || got[1].ID != "task-002" {
+		t.Fatalf("tasks = %+v", got)
+	}
+	// Dependencies on tasks not streamed yet are left out until they arrive
+	if deps := got[1].DependsOn; len(deps) != 1 || deps[0] != "task-001" {
+		t.Errorf("depends_on = %v, want [task-001]", deps)
+	}
+}
+
 func TestApplyPlanUpdate_ComplexScenario(t *testing.T) {
 	t.Parallel()
 	s := &state.State{
@@ -180,3 +249,201 @@ func TestApplyPlanUpdate_ComplexScenario(t *testing.T) {
 		t.Errorf("task-007 title = %q", task7.Title)
 	}
 }
+
+// ============================================================
+// Prompt size tracking
+// ============================================================
+
+func TestPromptMeter(t *testing.T) {
+	t.Parallel()
+	var pm PromptMeter
+
+	first := pm.Start(1000, 400, 50)
+	if want := (PromptStats{System: 1000, Snapshot: 400, Message: 50}); first != want {
+		t.Errorf("Start() = %+v, want %+v", first, want)
+	}
+	pm.Reply(200)
+
+	second := pm.Continue(30)
+	if want := (PromptStats{System: 1000, Snapshot: 400, History: 250, Message: 30}); second != want {
+		t.Errorf("Continue() = %+v, want %+v", second, want)
+	}
+	pm.Reply(100)
+
+	if got := pm.Continue(10).History; got != 380 {
+		t.Errorf("history after two exchanges = %d, want 380", got)
+	}
+
+	// A new session forgets the old history
+	if got := pm.Start(900, 0, 20); got.History != 0 || got.Total() != 920 {
+		t.Errorf("restarted stats = %+v, want no history and total 920", got)
+	}
+}
+
+func TestFormatPromptStats(t *testing.T) {
+	t.Parallel()
+	if got := FormatPromptStats(nil); !strings.Contains(got, "no requests") {
+		t.Errorf("empty stats = %q", got)
+	}
+
+	got := FormatPromptStats([]PromptStats{
+		{System: 8000, Snapshot: 4000, History: 0, Message: 100},
+		{System: 8000, Snapshot: 4000, History: 2000, Message: 40},
+	})
+	for _, want := range []string{"system", "8,000 chars", "~2,000 tokens", "history", "14,040 chars", "2 requests", "largest ~3,510 tokens"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("panel missing %q\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+	for chars, want := range map[int]int{0: 0, 1: 1, 4: 1, 5: 2, 4000: 1000} {
+		if got := EstimateTokens(chars); got != want {
+			t.Errorf("EstimateTokens(%d) = %d, want %d", chars, got, want)
+		}
+	}
+}
+
+func TestFormatConversationReplay(t *testing.T) {
+	t.Parallel()
+	if got := FormatConversationReplay("main", nil); got != "" {
+		t.Errorf("FormatConversationReplay(nil) = %q, want empty", got)
+	}
+	got := FormatConversationReplay("microservices", []state.ConversationMsg{
+		{Role: "user", Content: "Split it into services"},
+		{Role: "assistant", Content: "Which services?"},
+		{Role: "system", Content: "Plan critique: none"},
+	})
+	for _, want := range []string{`branch "microservices"`, "\nUser: Split it into services\n", "\nAssistant: Which services?\n", "\nNote: Plan critique: none\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatConversationReplay() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatConversationBranches(t *testing.T) {
+	t.Parallel()
+	s := &state.State{}
+	s.AddConversationMessage("user", "Build an order service")
+	if err := s.ForkConversation("microservices"); err != nil {
+		t.Fatal(err)
+	}
+	s.AddConversationMessage("user", "Split it")
+	got := FormatConversationBranches(s)
+	for _, want := range []string{"    main (1 message)\n", "  * microservices (current, 2 messages)\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatConversationBranches() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatPlanPreview(t *testing.T) {
+	t.Parallel()
+	got := FormatPlanPreview(&claude.PlanJSON{Tasks: []claude.PlanTaskJSON{
+		{Title: "Init project", Complexity: "small"},
+		{Title: "Add JWT authentication with refresh tokens and revocation lists", Complexity: "medium", DependsOn: []int{0}},
+		{Title: "Wire it up", DependsOn: []int{0, 1}},
+	}})
+	for _, want := range []string{
+		"Provisional plan: 3 tasks",
+		"  1  Init project                                  small    -\n",
+		"  2  Add JWT authentication with refresh tokens…   medium   1\n",
+		"  3  Wire it up                                    -        1,2",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatPlanPreview() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLongestChain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		tasks []claude.PlanTaskJSON
+		want  int
+	}{
+		{name: "empty", want: 0},
+		{name: "independent", tasks: []claude.PlanTaskJSON{{}, {}, {}}, want: 1},
+		{name: "diamond", tasks: []claude.PlanTaskJSON{{}, {DependsOn: []int{0}}, {DependsOn: []int{0}}, {DependsOn: []int{1, 2}}}, want: 3},
+		{name: "forward and bad references are ignored", tasks: []claude.PlanTaskJSON{{DependsOn: []int{1}}, {DependsOn: []int{-1, 7}}}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := LongestChain(tt.tasks); got != tt.want {
+				t.Errorf("LongestChain() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCandidate(t *testing.T) {
+	t.Parallel()
+	got := FormatCandidate(2, claude.PlanCandidateJSON{
+		Name:    "thorough",
+		Summary: "Adds tests and CI.",
+		Plan: claude.PlanJSON{TechStack: []string{"Go", "SQLite"}, Tasks: []claude.PlanTaskJSON{
+			{Title: "Init", Complexity: "small"},
+			{Title: "Add JWT authentication with refresh tokens", Complexity: "large", DependsOn: []int{0}},
+		}},
+	}, 20)
+	want := "2. thorough\nAdds tests and CI.\n\n2 tasks · 1 small · 0 medium · 1 large\nLongest chain: 2\nStack: Go, SQLite\n\n" +
+		" 1 Init\n 2 Add JWT authenti…"
+	if got != want {
+		t.Errorf("FormatCandidate()\n got  %q\n want %q", got, want)
+	}
+}
+
+func TestFormatTaskReference(t *testing.T) {
+	t.Parallel()
+	task := &state.Task{ID: "task-003", Title: "Add auth", Status: state.TaskPending, Complexity: "medium",
+		DependsOn: []string{"task-001"}, Description: "JWT login", AcceptanceCriteria: []string{"login returns a token"}}
+	want := "task-003 · Add auth [pending] (medium)\nAfter: task-001\nJWT login\n  ✓ login returns a token"
+	if got := FormatTaskReference(task); got != want {
+		t.Errorf("FormatTaskReference() =\n%s\nwant\n%s", got, want)
+	}
+	if !TaskRefRe.MatchString("see task-012.") || TaskRefRe.MatchString("subtask-012x") {
+		t.Error("TaskRefRe should match task IDs as whole words only")
+	}
+}
+
+func TestFormatOllamaDiagnosis(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		d    provider.OllamaDiagnosis
+		want []string
+	}{
+		{
+			name: "daemon down",
+			d: provider.OllamaDiagnosis{URL: "http://localhost:11434", Model: "qwen3-coder", Problem: provider.OllamaUnreachable,
+				Detail: "connection failed: connection refused"},
+			want: []string{"daemon not reachable", "URL:   http://localhost:11434", "Model: qwen3-coder", "connection refused", "ollama serve"},
+		},
+		{
+			name: "http status",
+			d:    provider.OllamaDiagnosis{URL: "http://gpu:11434", Model: "x", Problem: provider.OllamaBadStatus, HTTPCode: 502},
+			want: []string{"server error (HTTP 502)", "answered HTTP 502"},
+		},
+		{
+			name: "model not found",
+			d: provider.OllamaDiagnosis{URL: "http://localhost:11434", Model: "qwen3-coder", Problem: provider.OllamaModelNotFound,
+				Models: []provider.OllamaModel{{Name: "llama3:latest"}}},
+			want: []string{"model not found", "ollama pull qwen3-coder", "llama3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FormatOllamaDiagnosis(tt.d)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("missing %q in:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
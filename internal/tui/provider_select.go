@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/manasm11/forge/internal/provider"
+	"github.com/manasm11/forge/internal/tui/components"
 )
 
 // providerSelectModel is a minimal bubbletea model for inline provider selection.
@@ -73,7 +74,7 @@ func (m providerSelectModel) View() string {
 		if m.choice == provider.ProviderOllama {
 			name = "Ollama (local)"
 		}
-		done := lipgloss.NewStyle().Foreground(Success).Render("  ✓ Selected " + name + " provider")
+		done := lipgloss.NewStyle().Foreground(Success).Render("  " + components.Glyph("✓", "+") + " Selected " + name + " provider")
 		return done + "\n"
 	}
 
@@ -85,7 +86,7 @@ func (m providerSelectModel) View() string {
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(Primary).
-		Render("  ⚒ forge — Select Provider")
+		Render("  " + logo() + " forge " + components.Glyph("—", "-") + " Select Provider")
 
 	// Build option lines
 	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(Secondary)
@@ -97,10 +98,10 @@ func (m providerSelectModel) View() string {
 
 	// Claude option
 	if m.cursor == 0 {
-		lines = append(lines, selectedStyle.Render("  ▸ ☁  Claude (cloud)"))
+		lines = append(lines, selectedStyle.Render("  "+components.Glyph("▸ ☁  ", "> ")+"Claude (cloud)"))
 		lines = append(lines, subtitleStyle.Render("       Standard Claude Code CLI"))
 	} else {
-		lines = append(lines, normalStyle.Render("    ☁  Claude (cloud)"))
+		lines = append(lines, normalStyle.Render("    "+components.Glyph("☁  ", "")+"Claude (cloud)"))
 		lines = append(lines, subtitleStyle.Render("       Standard Claude Code CLI"))
 	}
 
@@ -121,10 +122,10 @@ func (m providerSelectModel) View() string {
 	}
 
 	if m.cursor == 1 {
-		lines = append(lines, selectedStyle.Render("  ▸ 🖥  Ollama (local)"))
+		lines = append(lines, selectedStyle.Render("  "+components.Glyph("▸ 🖥  ", "> ")+"Ollama (local)"))
 		lines = append(lines, subtitleStyle.Render("       "+ollamaSub))
 	} else {
-		lines = append(lines, normalStyle.Render("    🖥  Ollama (local)"))
+		lines = append(lines, normalStyle.Render("    "+components.Glyph("🖥  ", "")+"Ollama (local)"))
 		lines = append(lines, subtitleStyle.Render("       "+ollamaSub))
 	}
 
@@ -142,7 +143,7 @@ func (m providerSelectModel) View() string {
 	}
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(components.BoxBorder(lipgloss.RoundedBorder())).
 		BorderForeground(Border).
 		Width(boxWidth).
 		PaddingLeft(1).
@@ -160,6 +161,7 @@ func (m providerSelectModel) View() string {
 // RunProviderSelection runs an inline bubbletea program for provider selection.
 // Returns the chosen provider type, or an error if the user quit without selecting.
 func RunProviderSelection(ollamaStatus provider.OllamaStatus) (provider.ProviderType, error) {
+	applyASCII(nil) // no settings yet; only the terminal decides
 	m := newProviderSelectModel(ollamaStatus)
 	p := tea.NewProgram(m)
 
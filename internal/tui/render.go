@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/tui/components"
+	"github.com/muesli/termenv"
+)
+
+// clock supplies the time behind elapsed durations, log timestamps and
+// countdowns; DeterministicRendering pins it.
+var clock = time.Now
+
+// DeterministicRendering makes view output byte-for-byte repeatable, for
+// golden tests: the clock is fixed at at and colors are turned off. Widths
+// are whatever the caller passes to SetSize. The returned function restores
+// normal rendering.
+//
+// It changes package-level state, so callers must not run in parallel with
+// anything else that renders.
+func DeterministicRendering(at time.Time) (restore func()) {
+	prevClock := clock
+	prevProfile := lipgloss.ColorProfile()
+	prevDark := lipgloss.HasDarkBackground()
+	restoreComponents := components.SetClock(func() time.Time { return at })
+
+	clock = func() time.Time { return at }
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+
+	return func() {
+		clock = prevClock
+		lipgloss.SetColorProfile(prevProfile)
+		lipgloss.SetHasDarkBackground(prevDark)
+		restoreComponents()
+	}
+}
@@ -39,8 +39,9 @@ func BuildReplanSystemMessage(ctx ReplanContext) string {
 	if ctx.FailedCount > 0 {
 		fmt.Fprintf(&b, "%d failed and may need redesigning.\n", ctx.FailedCount)
 	}
-	b.WriteString("Tell me what changes you'd like to make to the plan.\n\n")
-	b.WriteString("Commands: /done \u00b7 /summary \u00b7 /restart")
+	b.WriteString("Tell me what changes you'd like to make to the plan.\n")
+	b.WriteString("Task IDs in the chat link to the plan: with an empty input, Tab picks one and Enter shows its details.\n\n")
+	b.WriteString("Commands: /done \u00b7 /preview \u00b7 /summary \u00b7 /fork \u00b7 /switch \u00b7 /branches \u00b7 /restart")
 	return b.String()
 }
 
@@ -50,8 +51,49 @@ func BuildReplanPrompt(ctx ReplanContext) string {
 	return fmt.Sprintf(claude.ReplanningPrompt, ctx.SystemContext)
 }
 
+// BranchRemovalError lists the tasks a plan update removes while they have
+// work on a branch (Branch or GitSHA set). The update can only be applied
+// once the user has chosen, for each, whether the branch is kept or
+// deleted (PlanUpdateTaskJSON.BranchDisposition).
+type BranchRemovalError struct {
+	TaskIDs []string
+}
+
+func (e *BranchRemovalError) Error() string {
+	return fmt.Sprintf("removing %s would orphan work on a branch; confirm what to do with it", strings.Join(e.TaskIDs, ", "))
+}
+
+// HasBranchWork reports whether a task has work on a branch that removing
+// it would leave behind.
+func HasBranchWork(t *state.Task) bool {
+	return t.Branch != "" || t.GitSHA != ""
+}
+
+// FormatBranchRemovals describes the branches of tasks a plan update
+// removes, for the confirmation shown before it is applied.
+func FormatBranchRemovals(tasks []*state.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "This update removes %d task%s with work on a branch:\n", len(tasks), pluralize(len(tasks)))
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "  %s %s [%s]", t.ID, t.Title, t.Status)
+		if t.Branch != "" {
+			fmt.Fprintf(&b, " · branch %s", t.Branch)
+		}
+		if t.GitSHA != "" {
+			fmt.Fprintf(&b, " @ %.7s", t.GitSHA)
+		}
+		if t.PRURL != "" {
+			fmt.Fprintf(&b, " · PR %s", t.PRURL)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
 // ValidatePlanUpdate checks a PlanUpdateJSON for logical errors before applying.
-// Returns a list of warnings (non-fatal) and an error (fatal).
+// Returns a list of warnings (non-fatal) and an error (fatal). Removing
+// tasks with work on a branch is a *BranchRemovalError until every one of
+// them has a BranchDisposition.
 func ValidatePlanUpdate(s *state.State, update *claude.PlanUpdateJSON) (warnings []string, err error) {
 	// Build a map of existing task IDs to their status
 	taskMap := make(map[string]*state.Task, len(s.Tasks))
@@ -61,6 +103,7 @@ func ValidatePlanUpdate(s *state.State, update *claude.PlanUpdateJSON) (warnings
 
 	// Track seen IDs to detect duplicates
 	seen := make(map[string]bool)
+	var unconfirmed []string
 
 	for _, t := range update.Tasks {
 		switch t.Action {
@@ -84,6 +127,18 @@ func ValidatePlanUpdate(s *state.State, update *claude.PlanUpdateJSON) (warnings
 			if (t.Action == "modify" || t.Action == "remove") && existing.Status == state.TaskDone {
 				return warnings, fmt.Errorf("cannot %s completed task %q", t.Action, t.ID)
 			}
+			if t.Action == "remove" && existing.Status == state.TaskInProgress {
+				return warnings, fmt.Errorf("cannot remove task %q while it is in progress", t.ID)
+			}
+			if t.Action == "remove" && HasBranchWork(existing) {
+				switch state.BranchDisposition(t.BranchDisposition) {
+				case state.BranchKept, state.BranchDeleted:
+				case "":
+					unconfirmed = append(unconfirmed, t.ID)
+				default:
+					return warnings, fmt.Errorf("unknown branch disposition %q for task %q", t.BranchDisposition, t.ID)
+				}
+			}
 
 			// Warning: "keep" on cancelled task is a no-op but odd
 			if t.Action == "keep" && existing.Status == state.TaskCancelled {
@@ -105,6 +160,9 @@ func ValidatePlanUpdate(s *state.State, update *claude.PlanUpdateJSON) (warnings
 		}
 	}
 
+	if len(unconfirmed) > 0 {
+		return warnings, &BranchRemovalError{TaskIDs: unconfirmed}
+	}
 	return warnings, nil
 }
 
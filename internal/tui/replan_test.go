@@ -2,6 +2,7 @@ package tui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -339,6 +340,81 @@ func TestValidatePlanUpdate(t *testing.T) {
 	}
 }
 
+func TestValidatePlanUpdate_BranchWork(t *testing.T) {
+	t.Parallel()
+
+	baseState := &state.State{
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Auth", Status: state.TaskFailed, Branch: "forge/task-001", GitSHA: "abc1234def"},
+			{ID: "task-002", Title: "API", Status: state.TaskPending},
+			{ID: "task-003", Title: "Cache", Status: state.TaskPending, GitSHA: "0123456789"},
+			{ID: "task-004", Title: "Deploy", Status: state.TaskInProgress, Branch: "forge/task-004"},
+		},
+	}
+	remove := func(id, disposition string) claude.PlanUpdateTaskJSON {
+		return claude.PlanUpdateTaskJSON{ID: id, Action: "remove", BranchDisposition: disposition}
+	}
+
+	tests := []struct {
+		name        string
+		tasks       []claude.PlanUpdateTaskJSON
+		unconfirmed []string // IDs in the *BranchRemovalError
+		wantErr     string   // any other error
+	}{
+		{name: "no branch work", tasks: []claude.PlanUpdateTaskJSON{remove("task-002", "")}},
+		{name: "unconfirmed", tasks: []claude.PlanUpdateTaskJSON{remove("task-001", ""), remove("task-002", ""), remove("task-003", "")},
+			unconfirmed: []string{"task-001", "task-003"}},
+		{name: "confirmed", tasks: []claude.PlanUpdateTaskJSON{remove("task-001", "kept"), remove("task-003", "deleted")}},
+		{name: "unknown disposition", tasks: []claude.PlanUpdateTaskJSON{remove("task-001", "archived")}, wantErr: "unknown branch disposition"},
+		{name: "in progress", tasks: []claude.PlanUpdateTaskJSON{remove("task-004", "kept")}, wantErr: "while it is in progress"},
+		{name: "modify is not a removal", tasks: []claude.PlanUpdateTaskJSON{{ID: "task-001", Action: "modify", Title: "Auth v2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := copyState(baseState)
+			update := &claude.PlanUpdateJSON{Tasks: tt.tasks}
+			_, err := ValidatePlanUpdate(s, update)
+			var branchErr *BranchRemovalError
+			switch {
+			case tt.unconfirmed != nil:
+				if !errors.As(err, &branchErr) || strings.Join(branchErr.TaskIDs, ",") != strings.Join(tt.unconfirmed, ",") {
+					t.Fatalf("error = %v, want removal of %v to need confirmation", err, tt.unconfirmed)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			default:
+				if err := ApplyPlanUpdate(s, update); err != nil {
+					t.Fatalf("ApplyPlanUpdate: %v", err)
+				}
+				for _, u := range update.Tasks {
+					if got := s.FindTask(u.ID).BranchDisposition; string(got) != u.BranchDisposition {
+						t.Errorf("%s disposition = %q, want %q", u.ID, got, u.BranchDisposition)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFormatBranchRemovals(t *testing.T) {
+	t.Parallel()
+	got := FormatBranchRemovals([]*state.Task{
+		{ID: "task-001", Title: "Auth", Status: state.TaskFailed, Branch: "forge/task-001", GitSHA: "abc1234def", PRURL: "https://example.com/pr/3"},
+		{ID: "task-003", Title: "Cache", Status: state.TaskPending, GitSHA: "0123456789"},
+	})
+	want := "This update removes 2 tasks with work on a branch:\n" +
+		"  task-001 Auth [failed] · branch forge/task-001 @ abc1234 · PR https://example.com/pr/3\n" +
+		"  task-003 Cache [pending] @ 0123456"
+	if got != want {
+		t.Errorf("FormatBranchRemovals()\n got  %q\n want %q", got, want)
+	}
+}
+
 // ============================================================
 // MergeConversationHistory
 // ============================================================
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// ReplayModel plays a recorded run back through the execution dashboard,
+// so rendering and progress bugs can be reproduced without running tasks.
+type ReplayModel struct {
+	execution ExecutionModel
+	events    []executor.TaskEvent
+	runID     int
+	speed     float64
+	width     int
+	height    int
+
+	accessible bool
+}
+
+// NewReplayModel prepares a replay of one run's events. s supplies task
+// titles and settings and may be nil; it is never modified.
+func NewReplayModel(s *state.State, root string, runID int, events []executor.TaskEvent, speed float64) *ReplayModel {
+	replayed := BuildReplayState(s, events)
+	m := &ReplayModel{
+		execution: NewExecutionModel(replayed, root, nil),
+		events:    events,
+		runID:     runID,
+		speed:     speed,
+	}
+	m.SetAccessible(Accessible(replayed.Settings))
+	return m
+}
+
+// SetAccessible plays the run back in the screen-reader friendly mode
+// (see Accessible).
+func (m *ReplayModel) SetAccessible(on bool) {
+	m.accessible = on
+	m.execution.SetAccessible(on)
+}
+
+// IsAccessible reports whether the replay uses the accessible mode, from
+// SetAccessible or the recorded settings.
+func (m *ReplayModel) IsAccessible() bool {
+	return m.accessible
+}
+
+// SetProgram sets the tea.Program the replayed events are sent through.
+// Must be called after tea.NewProgram() and before p.Run().
+func (m *ReplayModel) SetProgram(p *tea.Program) {
+	m.execution.SetProgram(p)
+}
+
+func (m *ReplayModel) Init() tea.Cmd {
+	applyASCII(m.execution.state.Settings)
+	return tea.Batch(m.execution.Init(), m.execution.StartReplay(m.events, m.speed))
+}
+
+func (m *ReplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.execution.SetSize(m.width, max(m.height-2, 0))
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case TransitionMsg:
+		// There are no other phases to go to
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.execution, cmd = m.execution.Update(msg)
+	return m, cmd
+}
+
+func (m *ReplayModel) View() string {
+	title := TitleStyle.Render(logo() + " forge replay")
+	info := SubtitleStyle.Render(fmt.Sprintf("run %d · %d events · %gx", m.runID, len(m.events), m.speed))
+	header := lipgloss.NewStyle().
+		Width(m.width).
+		Background(lipgloss.Color("#1F2937")).
+		PaddingLeft(1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Center, title, "  ", info))
+
+	view := lipgloss.JoinVertical(lipgloss.Left, header, m.execution.View())
+	if m.accessible {
+		view = StripBoxDrawing(view)
+	}
+	return view
+}
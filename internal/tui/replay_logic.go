@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// maxReplayGap caps the pause between two replayed events, so a task that
+// spent ten minutes in Claude doesn't stall the replay.
+const maxReplayGap = 2 * time.Second
+
+// BuildReplayState returns a throwaway state holding the tasks a recorded
+// run touched, all pending, in the order they first appear. Titles and
+// settings come from s when it is available.
+func BuildReplayState(s *state.State, events []executor.TaskEvent) *state.State {
+	rs := &state.State{Phase: state.PhaseExecution}
+	if s != nil {
+		rs.ProjectName = s.ProjectName
+		rs.PlanVersion = s.PlanVersion
+		rs.Settings = s.Settings
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if e.TaskID == "" || seen[e.TaskID] {
+			continue
+		}
+		seen[e.TaskID] = true
+
+		task := state.Task{ID: e.TaskID, Title: e.TaskID, Complexity: "medium"}
+		if s != nil {
+			if t := s.FindTask(e.TaskID); t != nil {
+				task.Title = t.Title
+				task.Complexity = t.Complexity
+				task.Type = t.Type
+			}
+		}
+		task.Status = state.TaskPending
+		rs.Tasks = append(rs.Tasks, task)
+	}
+	return rs
+}
+
+// ApplyEventToTasks mirrors an event's status change onto tasks. During a
+// replay there is no runner to own task state, so the dashboard does it.
+func ApplyEventToTasks(tasks []state.Task, event executor.TaskEvent) {
+	for i := range tasks {
+		if tasks[i].ID != event.TaskID {
+			continue
+		}
+		switch event.Type {
+		case executor.EventTaskStart:
+			tasks[i].Status = state.TaskInProgress
+		case executor.EventTaskDone:
+			tasks[i].Status = state.TaskDone
+		case executor.EventTaskFailed:
+			tasks[i].Status = state.TaskFailed
+		case executor.EventTaskSkipped:
+			tasks[i].Status = state.TaskSkipped
+		case executor.EventTaskReset:
+			tasks[i].Status = state.TaskPending
+		}
+		return
+	}
+}
+
+// ReplayDelays returns how long to wait before sending each event: the
+// recorded gap divided by speed, capped at maxGap.
+func ReplayDelays(events []executor.TaskEvent, speed float64, maxGap time.Duration) []time.Duration {
+	if speed <= 0 {
+		speed = 1
+	}
+	delays := make([]time.Duration, len(events))
+	for i := 1; i < len(events); i++ {
+		gap := time.Duration(float64(events[i].Timestamp-events[i-1].Timestamp) * float64(time.Millisecond) / speed)
+		delays[i] = min(max(gap, 0), maxGap)
+	}
+	return delays
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// ============================================================
+// Replay
+// ============================================================
+
+func TestBuildReplayState(t *testing.T) {
+	t.Parallel()
+	s := &state.State{
+		ProjectName: "demo",
+		Settings:    &state.Settings{MaxRetries: 3},
+		Tasks: []state.Task{
+			{ID: "task-001", Title: "Init", Complexity: "small", Status: state.TaskDone},
+			{ID: "task-002", Title: "Auth", Complexity: "large", Status: state.TaskFailed},
+		},
+	}
+	events := []executor.TaskEvent{
+		{TaskID: "task-002", Type: executor.EventTaskStart},
+		{Type: executor.EventError, Message: "push failed"},
+		{TaskID: "task-009", Type: executor.EventTaskStart},
+		{TaskID: "task-002", Type: executor.EventTaskFailed},
+	}
+
+	rs := BuildReplayState(s, events)
+	if len(rs.Tasks) != 2 {
+		t.Fatalf("tasks = %+v, want task-002 and task-009", rs.Tasks)
+	}
+	if rs.Tasks[0].ID != "task-002" || rs.Tasks[0].Title != "Auth" || rs.Tasks[0].Complexity != "large" {
+		t.Errorf("known task = %+v, want title and complexity from state", rs.Tasks[0])
+	}
+	if rs.Tasks[1].Title != "task-009" {
+		t.Errorf("unknown task title = %q, want its ID", rs.Tasks[1].Title)
+	}
+	for _, task := range rs.Tasks {
+		if task.Status != state.TaskPending {
+			t.Errorf("%s status = %s, want pending", task.ID, task.Status)
+		}
+	}
+	if rs.Settings.MaxRetries != 3 || rs.ProjectName != "demo" {
+		t.Errorf("settings/project not carried over: %+v", rs)
+	}
+	if s.Tasks[1].Status != state.TaskFailed {
+		t.Error("original state must not be modified")
+	}
+
+	if got := BuildReplayState(nil, events); len(got.Tasks) != 2 {
+		t.Errorf("replay without state has %d tasks, want 2", len(got.Tasks))
+	}
+}
+
+func TestApplyEventToTasks(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{{ID: "task-001", Status: state.TaskPending}, {ID: "task-002", Status: state.TaskPending}}
+
+	steps := []struct {
+		event executor.TaskEventType
+		want  state.TaskStatus
+	}{
+		{executor.EventTaskStart, state.TaskInProgress},
+		{executor.EventClaudeDone, state.TaskInProgress},
+		{executor.EventTaskFailed, state.TaskFailed},
+		{executor.EventTaskReset, state.TaskPending},
+		{executor.EventTaskDone, state.TaskDone},
+	}
+	for _, step := range steps {
+		ApplyEventToTasks(tasks, executor.TaskEvent{TaskID: "task-001", Type: step.event})
+		if tasks[0].Status != step.want {
+			t.Errorf("after %s status = %s, want %s", step.event, tasks[0].Status, step.want)
+		}
+	}
+	if tasks[1].Status != state.TaskPending {
+		t.Errorf("unrelated task changed to %s", tasks[1].Status)
+	}
+}
+
+func TestReplayDelays(t *testing.T) {
+	t.Parallel()
+	events := []executor.TaskEvent{
+		{Timestamp: 1_000},
+		{Timestamp: 3_000},  // 2s later
+		{Timestamp: 63_000}, // a minute later
+		{Timestamp: 62_000}, // out of order
+	}
+
+	got := ReplayDelays(events, 10, 2*time.Second)
+	want := []time.Duration{0, 200 * time.Millisecond, 2 * time.Second, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delay[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := ReplayDelays(events[:2], 0, time.Minute); got[1] != 2*time.Second {
+		t.Errorf("zero speed should play in real time, got %v", got[1])
+	}
+}
@@ -1,15 +1,22 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/generator"
+	"github.com/manasm11/forge/internal/janitor"
+	"github.com/manasm11/forge/internal/planner"
+	"github.com/manasm11/forge/internal/platform"
 	"github.com/manasm11/forge/internal/state"
 	"github.com/manasm11/forge/internal/tui/components"
 )
@@ -22,8 +29,45 @@ type editorFinishedMsg struct {
 	isNew    bool
 }
 
-// clearConfirmErrMsg clears the confirmation error after a timeout.
-type clearConfirmErrMsg struct{}
+// promptEditedMsg is sent when $EDITOR closes on a task's execution prompt.
+type promptEditedMsg struct {
+	err       error
+	tmpPath   string
+	taskID    string
+	generated string // the prompt forge would build, to detect "no change"
+}
+
+// planEditedMsg is sent when $EDITOR closes on the whole plan.
+type planEditedMsg struct {
+	err     error
+	tmpPath string
+}
+
+// suggestCriteriaMsg asks AppModel to have the planning model tighten the
+// pending tasks' acceptance criteria (the review phase has no client).
+type suggestCriteriaMsg struct{}
+
+// criteriaSuggestionsMsg carries the proposed criteria rewrites.
+type criteriaSuggestionsMsg struct {
+	Edits []planner.CriteriaEdit
+	Err   error
+}
+
+// critiqueMsg carries the critic's review of a freshly planned version.
+type critiqueMsg struct {
+	Findings []string
+	Err      error
+}
+
+// rejectedEdit is an editor file whose content failed validation. It is
+// kept, annotated with the error, so the user can fix it instead of
+// starting over.
+type rejectedEdit struct {
+	tmpPath string
+	label   string              // what was edited, e.g. "edit of task-003"
+	err     error               // a FieldError when the problem is one field
+	reopen  func(error) tea.Msg // builds the message for when $EDITOR closes again
+}
 
 // ReviewModel manages the task review phase.
 type ReviewModel struct {
@@ -31,8 +75,36 @@ type ReviewModel struct {
 	state         *state.State
 	stateRoot     string
 	width, height int
-	confirmErr    string // shown when 'c' is pressed but CanConfirm fails
-	deleteConfirm string // task ID pending delete confirmation
+	status        components.StatusLine // errors stay until dismissed
+	deleteConfirm string                // task ID pending delete confirmation
+
+	// Bulk actions on the tasks marked with space
+	bulkDeleteConfirm bool
+	labeling          bool // reading a label for the marked tasks
+	labelInput        textinput.Model
+
+	// Editor file rejected by validation, kept for e to reopen (nil = none)
+	rejected *rejectedEdit
+
+	// Dependency picker for depTaskID (nil when closed)
+	depPicker *components.PickerModel
+	depTaskID string
+
+	// Second-model plan review (see AppModel.SetCritic)
+	critiquing bool
+	critique   string // system message with the findings, "" until they arrive
+
+	// Acceptance criteria suggestions (t), shown one task at a time as a
+	// diff to accept or skip
+	suggestingCriteria bool
+	criteriaEdits      []planner.CriteriaEdit
+	criteriaTotal      int
+	criteriaAccepted   int
+
+	// The plan is still streaming in: the list is read-only and grows as
+	// tasks arrive (see ShowPartialPlan)
+	generating bool
+	generated  int // tasks received so far
 }
 
 // NewReviewModel creates a new review phase model.
@@ -56,10 +128,57 @@ func (m ReviewModel) Init() tea.Cmd {
 func (m ReviewModel) Update(msg tea.Msg) (ReviewModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.generating {
+			switch msg.String() {
+			case "j", "k", "up", "down", "enter":
+				var cmd tea.Cmd
+				m.taskList, cmd = m.taskList.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
 		// Handle delete confirmation mode
 		if m.deleteConfirm != "" {
 			return m.handleDeleteConfirm(msg)
 		}
+		if m.bulkDeleteConfirm {
+			return m.handleBulkDeleteConfirm(msg)
+		}
+		if m.labeling {
+			return m.handleLabelInput(msg)
+		}
+		if len(m.criteriaEdits) > 0 {
+			return m.handleCriteriaKey(msg)
+		}
+		if m.status.LogOpen() {
+			if s := msg.String(); s == "m" || s == "esc" || s == "q" {
+				m.status.ToggleLog()
+			}
+			return m, nil
+		}
+		if m.depPicker != nil {
+			var cmd tea.Cmd
+			*m.depPicker, cmd = m.depPicker.Update(msg)
+			return m, cmd
+		}
+		if m.rejected != nil {
+			switch msg.String() {
+			case "e":
+				return m, tea.ExecProcess(platform.EditorCommand(m.rejected.tmpPath), m.rejected.reopen)
+			case "esc":
+				_ = janitor.Release(m.stateRoot, m.rejected.tmpPath)
+				m.rejected = nil
+				return m, nil
+			}
+		}
+		if msg.String() == "esc" && m.status.Dismiss() {
+			return m, nil
+		}
+		if marked := m.taskList.Marked(); len(marked) > 0 {
+			if next, cmd, ok := m.handleBulkKey(msg, marked); ok {
+				return next, cmd
+			}
+		}
 
 		switch msg.String() {
 		case "r":
@@ -70,15 +189,31 @@ func (m ReviewModel) Update(msg tea.Msg) (ReviewModel, tea.Cmd) {
 		case "c":
 			errMsg := CanConfirm(m.state.Tasks)
 			if errMsg != "" {
-				m.confirmErr = errMsg
-				return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-					return clearConfirmErrMsg{}
-				})
+				m.status.Push(components.StatusError, errMsg)
+				return m, nil
 			}
 			return m, func() tea.Msg {
 				return TransitionMsg{To: state.PhaseInputs}
 			}
 
+		case "E":
+			return m.startPlanEdit()
+
+		case "t":
+			if m.suggestingCriteria {
+				return m, nil
+			}
+			m.suggestingCriteria = true
+			m.status.Push(components.StatusInfo, "Asking the model to tighten the acceptance criteria of the pending tasks…")
+			return m, func() tea.Msg { return suggestCriteriaMsg{} }
+
+		case "m":
+			m.status.ToggleLog()
+			return m, nil
+
+		case "D":
+			return m.startDependencyPicker(m.taskList.CursorID())
+
 		case "q":
 			return m, tea.Quit
 		}
@@ -89,8 +224,34 @@ func (m ReviewModel) Update(msg tea.Msg) (ReviewModel, tea.Cmd) {
 	case editorFinishedMsg:
 		return m.handleEditorFinished(msg)
 
-	case clearConfirmErrMsg:
-		m.confirmErr = ""
+	case promptEditedMsg:
+		return m.handlePromptEdited(msg)
+
+	case planEditedMsg:
+		return m.handlePlanEdited(msg)
+
+	case components.PickerDoneMsg:
+		return m.handleDependenciesPicked(msg)
+
+	case criteriaSuggestionsMsg:
+		m.suggestingCriteria = false
+		switch {
+		case msg.Err != nil:
+			m.status.Push(components.StatusError, fmt.Sprintf("Criteria suggestions failed: %v", msg.Err))
+		case len(msg.Edits) == 0:
+			m.status.Push(components.StatusInfo, "The acceptance criteria are already verifiable; nothing to tighten.")
+		default:
+			m.criteriaEdits, m.criteriaTotal, m.criteriaAccepted = msg.Edits, len(msg.Edits), 0
+		}
+		return m, nil
+
+	case critiqueMsg:
+		m.critiquing = false
+		if msg.Err != nil {
+			m.critique = fmt.Sprintf("Plan critique failed: %v", msg.Err)
+		} else {
+			m.critique = FormatCritique(msg.Findings)
+		}
 		return m, nil
 	}
 
@@ -108,17 +269,36 @@ func (m ReviewModel) View() string {
 	// Header
 	stats := ComputeTaskStats(m.state.Tasks)
 	header := m.renderReviewHeader(stats)
+	if m.generating {
+		header = m.renderGeneratingHeader()
+	}
+	if critique := m.renderCritique(); critique != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, critique)
+	}
+
+	// Footer, with the latest message above it
+	footer := m.renderFooter()
+	if status := m.status.View(m.width); status != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Left, status, footer)
+	}
 
 	// Task list content
-	contentHeight := m.height - 2 // header + footer
+	contentHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer)
 	if contentHeight < 1 {
 		contentHeight = 1
 	}
 	m.taskList.SetSize(m.width, contentHeight)
+	m.taskList.SetStatusStyle(StatusStyleOf(m.state.Settings))
 	content := m.taskList.View()
-
-	// Footer
-	footer := m.renderFooter()
+	switch {
+	case m.status.LogOpen():
+		content = m.status.LogView(m.width, contentHeight)
+	case len(m.criteriaEdits) > 0:
+		content = lipgloss.NewStyle().MaxHeight(contentHeight).Render(m.renderCriteriaEdit())
+	case m.depPicker != nil:
+		m.depPicker.SetSize(m.width, contentHeight)
+		content = m.depPicker.View()
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, content, footer)
 }
@@ -140,7 +320,37 @@ func (m ReviewModel) renderReviewHeader(stats TaskStats) string {
 	return info
 }
 
+func (m ReviewModel) renderGeneratingHeader() string {
+	n := m.generated
+	return lipgloss.NewStyle().
+		Foreground(Warning).
+		PaddingLeft(1).
+		Render(fmt.Sprintf("Generating plan… %d task%s so far", n, pluralize(n)))
+}
+
+// renderCritique shows the critic's findings as a system message above the
+// task list.
+func (m ReviewModel) renderCritique() string {
+	text := m.critique
+	if m.critiquing {
+		text = "Critic is reviewing the plan…"
+	}
+	if text == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(Warning).
+		Width(m.width).
+		PaddingLeft(1).
+		Render(text)
+}
+
 func (m ReviewModel) renderFooter() string {
+	if m.generating {
+		return StatusBar.Width(m.width).Render(HelpStyle.Render(
+			"j/k navigate · Enter details · esc back to chat · the plan can be edited once it is complete"))
+	}
+
 	if m.deleteConfirm != "" {
 		prompt := lipgloss.NewStyle().
 			Foreground(Warning).
@@ -149,16 +359,48 @@ func (m ReviewModel) renderFooter() string {
 		return StatusBar.Width(m.width).Render(prompt)
 	}
 
-	if m.confirmErr != "" {
+	if m.bulkDeleteConfirm {
+		prompt := lipgloss.NewStyle().
+			Foreground(Warning).
+			Bold(true).
+			Render(fmt.Sprintf("Delete %d marked task%s? (y/n)", len(m.taskList.Marked()), pluralize(len(m.taskList.Marked()))))
+		return StatusBar.Width(m.width).Render(prompt)
+	}
+
+	if m.labeling {
+		return StatusBar.Width(m.width).Render(fmt.Sprintf("Label for %d task%s: %s  %s",
+			len(m.taskList.Marked()), pluralize(len(m.taskList.Marked())), m.labelInput.View(), HelpStyle.Render("enter add · esc cancel")))
+	}
+
+	if len(m.criteriaEdits) > 0 {
+		return StatusBar.Width(m.width).Render(HelpStyle.Render(
+			"y accept · n skip · A accept all remaining · esc skip the rest"))
+	}
+
+	if m.depPicker != nil {
+		return StatusBar.Width(m.width).Render(HelpStyle.Render(
+			"j/k navigate · space toggle · enter save · esc cancel · [-] would create a cycle"))
+	}
+
+	if m.rejected != nil {
 		errMsg := lipgloss.NewStyle().
 			Foreground(Danger).
 			Bold(true).
-			Render(m.confirmErr)
-		return StatusBar.Width(m.width).Render(errMsg)
+			Render(fmt.Sprintf("Your %s was not applied: %v", m.rejected.label, m.rejected.err))
+		return StatusBar.Width(m.width).Render(errMsg + "  " + HelpStyle.Render("e reopen your edit · esc discard it"))
 	}
 
 	help := HelpStyle.Render(
-		"j/k navigate · Enter details · e edit · d delete · n new · J/K reorder · r replan · c confirm · q quit")
+		"j/k navigate · Enter details · space mark · e edit · E edit plan · p prompt · d delete · n new · y duplicate · J/K reorder · f start here · t tighten criteria · r replan · m messages · c confirm · q quit")
+	if m.taskList.DetailVisible() {
+		help = HelpStyle.Render(
+			"j/k navigate · Enter close details · D dependencies · e edit · p prompt · d delete · J/K reorder · c confirm · q quit")
+	}
+	if marked := m.taskList.Marked(); len(marked) > 0 {
+		help = HelpStyle.Render(fmt.Sprintf(
+			"%d marked · space mark · d delete · 1/2/3 small/medium/large · l label · a depend on %s · esc unmark",
+			len(marked), m.taskList.CursorID()))
+	}
 
 	return StatusBar.Width(m.width).Render(help)
 }
@@ -174,6 +416,12 @@ func (m ReviewModel) handleTaskAction(msg components.TaskActionMsg) (ReviewModel
 		return m, nil
 	case "new":
 		return m.startNew()
+	case "duplicate":
+		return m.duplicate(msg.TaskID)
+	case "prompt":
+		return m.startPromptEdit(msg.TaskID)
+	case "start_from":
+		return m.startFrom(msg.TaskID)
 	case "reorder_up":
 		return m.reorder(msg.TaskID, -1)
 	case "reorder_down":
@@ -192,10 +440,199 @@ func (m ReviewModel) handleDeleteConfirm(msg tea.KeyMsg) (ReviewModel, tea.Cmd)
 
 	result, err := DeleteTask(m.state.Tasks, taskID)
 	if err != nil {
-		m.confirmErr = err.Error()
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
-		})
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
+	}
+
+	m.state.Tasks = result
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	return m, nil
+}
+
+// handleBulkKey runs a bulk action on the marked tasks. Keys that aren't
+// bulk actions (navigation, space) report false and fall through.
+func (m ReviewModel) handleBulkKey(msg tea.KeyMsg, marked []string) (ReviewModel, tea.Cmd, bool) {
+	switch msg.String() {
+	case "d":
+		m.bulkDeleteConfirm = true
+		return m, nil, true
+
+	case "1", "2", "3":
+		complexity := map[string]string{"1": "small", "2": "medium", "3": "large"}[msg.String()]
+		result, err := BulkSetComplexity(m.state.Tasks, marked, complexity, m.state.PlanVersion)
+		next, cmd := m.applyBulk(result, err)
+		return next, cmd, true
+
+	case "l":
+		m.labeling = true
+		m.labelInput = textinput.New()
+		m.labelInput.Placeholder = "frontend"
+		m.labelInput.CharLimit = 64
+		m.labelInput.Focus()
+		return m, textinput.Blink, true
+
+	case "a": // the marked tasks depend on the task under the cursor
+		result, err := BulkAddDependency(m.state.Tasks, marked, m.taskList.CursorID(), m.state.PlanVersion)
+		next, cmd := m.applyBulk(result, err)
+		return next, cmd, true
+
+	case "esc":
+		m.taskList.ClearMarks()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+func (m ReviewModel) handleBulkDeleteConfirm(msg tea.KeyMsg) (ReviewModel, tea.Cmd) {
+	m.bulkDeleteConfirm = false
+	if msg.String() != "y" {
+		return m, nil
+	}
+	result, err := BulkDelete(m.state.Tasks, m.taskList.Marked())
+	return m.applyBulk(result, err)
+}
+
+// handleLabelInput reads the label; enter adds it to the marked tasks, esc
+// aborts.
+func (m ReviewModel) handleLabelInput(msg tea.KeyMsg) (ReviewModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.labeling = false
+		return m, nil
+
+	case "enter":
+		m.labeling = false
+		result, err := BulkAddLabel(m.state.Tasks, m.taskList.Marked(), m.labelInput.Value(), m.state.PlanVersion)
+		return m.applyBulk(result, err)
+	}
+
+	var cmd tea.Cmd
+	m.labelInput, cmd = m.labelInput.Update(msg)
+	return m, cmd
+}
+
+// applyBulk saves the outcome of a bulk action. Marks are kept so several
+// actions can be applied to the same tasks; deleted tasks drop out.
+func (m ReviewModel) applyBulk(result []state.Task, err error) (ReviewModel, tea.Cmd) {
+	if err != nil {
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
+	}
+
+	m.state.Tasks = result
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	return m, nil
+}
+
+// handleCriteriaKey accepts or skips the criteria suggestion on screen.
+func (m ReviewModel) handleCriteriaKey(msg tea.KeyMsg) (ReviewModel, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.acceptCriteria(m.criteriaEdits[0])
+		m.criteriaEdits = m.criteriaEdits[1:]
+	case "n":
+		m.criteriaEdits = m.criteriaEdits[1:]
+	case "A":
+		for _, edit := range m.criteriaEdits {
+			m.acceptCriteria(edit)
+		}
+		m.criteriaEdits = nil
+	case "esc":
+		m.criteriaEdits = nil
+	default:
+		return m, nil
+	}
+	if len(m.criteriaEdits) == 0 {
+		_ = state.Save(m.stateRoot, m.state)
+		m.refreshList()
+		m.status.Push(components.StatusInfo, fmt.Sprintf("Accepted %d of %d criteria suggestion%s.",
+			m.criteriaAccepted, m.criteriaTotal, pluralize(m.criteriaTotal)))
+	}
+	return m, nil
+}
+
+// acceptCriteria applies one suggestion to the plan.
+func (m *ReviewModel) acceptCriteria(edit planner.CriteriaEdit) {
+	result, err := SetAcceptanceCriteria(m.state.Tasks, edit.TaskID, edit.New, m.state.PlanVersion)
+	if err != nil {
+		m.status.Push(components.StatusError, err.Error())
+		return
+	}
+	m.state.Tasks = result
+	m.criteriaAccepted++
+}
+
+// renderCriteriaEdit shows the criteria suggestion on screen as a diff.
+func (m ReviewModel) renderCriteriaEdit() string {
+	edit := m.criteriaEdits[0]
+	n := m.criteriaTotal - len(m.criteriaEdits) + 1
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s %s", edit.TaskID, edit.Title)) +
+			lipgloss.NewStyle().Foreground(Muted).Render(fmt.Sprintf("  acceptance criteria, suggestion %d of %d", n, m.criteriaTotal)),
+		"",
+	}
+	for _, line := range strings.Split(FormatCriteriaDiff(edit), "\n") {
+		style := lipgloss.NewStyle()
+		switch {
+		case strings.HasPrefix(line, "- "):
+			style = style.Foreground(Danger)
+		case strings.HasPrefix(line, "+ "):
+			style = style.Foreground(Success)
+		}
+		lines = append(lines, style.Width(m.width-2).Render(line))
+	}
+	return lipgloss.NewStyle().PaddingLeft(1).Render(strings.Join(lines, "\n"))
+}
+
+// startDependencyPicker opens a checkbox list of the other tasks to choose
+// the task's dependencies. Tasks that already depend on it are disabled,
+// so the choice can't create a cycle.
+func (m ReviewModel) startDependencyPicker(taskID string) (ReviewModel, tea.Cmd) {
+	task := m.state.FindTask(taskID)
+	if task == nil {
+		return m, nil
+	}
+	if task.Status != state.TaskPending && task.Status != state.TaskFailed {
+		m.status.Push(components.StatusError, fmt.Sprintf("cannot edit %s task %q", task.Status, taskID))
+		return m, nil
+	}
+
+	dependents := state.Dependents(m.state.Tasks, taskID)
+	var items []components.PickerItem
+	for _, t := range m.state.Tasks {
+		if t.ID == taskID || t.Status == state.TaskCancelled {
+			continue
+		}
+		item := components.PickerItem{
+			ID:      t.ID,
+			Label:   fmt.Sprintf("%s %s", t.ID, t.Title),
+			Checked: slices.Contains(task.DependsOn, t.ID),
+		}
+		if dependents[t.ID] {
+			item.Disabled = "depends on " + taskID
+		}
+		items = append(items, item)
+	}
+
+	picker := components.NewPickerModel(fmt.Sprintf("Dependencies of %s: %s", taskID, task.Title), items)
+	m.depPicker = &picker
+	m.depTaskID = taskID
+	return m, nil
+}
+
+func (m ReviewModel) handleDependenciesPicked(msg components.PickerDoneMsg) (ReviewModel, tea.Cmd) {
+	taskID := m.depTaskID
+	m.depPicker, m.depTaskID = nil, ""
+	if msg.Cancelled {
+		return m, nil
+	}
+
+	result, err := SetDependencies(m.state.Tasks, taskID, msg.Selected, m.state.PlanVersion)
+	if err != nil {
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
 	}
 
 	m.state.Tasks = result
@@ -204,13 +641,54 @@ func (m ReviewModel) handleDeleteConfirm(msg tea.KeyMsg) (ReviewModel, tea.Cmd)
 	return m, nil
 }
 
+// rejectEdit keeps an editor file that failed validation, with the error
+// written into it as comments, for e to reopen. A file kept earlier is
+// discarded.
+func (m ReviewModel) rejectEdit(tmpPath, label string, err error, reopen func(error) tea.Msg) (ReviewModel, tea.Cmd) {
+	if m.rejected != nil && m.rejected.tmpPath != tmpPath {
+		_ = janitor.Release(m.stateRoot, m.rejected.tmpPath)
+	}
+	if data, err := os.ReadFile(tmpPath); err == nil {
+		_ = os.WriteFile(tmpPath, []byte(AnnotateEditError(string(data), err)), 0600)
+	}
+	m.rejected = &rejectedEdit{tmpPath: tmpPath, label: label, err: err, reopen: reopen}
+	return m, nil
+}
+
+// startFrom makes execution begin at the selected task, skipping earlier
+// pending tasks for now. Choosing the first task again restores them.
+func (m ReviewModel) startFrom(taskID string) (ReviewModel, tea.Cmd) {
+	if _, err := m.state.StartFrom(taskID); err != nil {
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
+	}
+
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	m.taskList.SetCursorByID(taskID)
+	return m, nil
+}
+
+// duplicate copies a task and moves the cursor to the copy, ready for e.
+func (m ReviewModel) duplicate(taskID string) (ReviewModel, tea.Cmd) {
+	result, id, err := DuplicateTask(m.state.Tasks, taskID, m.state.PlanVersion)
+	if err != nil {
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
+	}
+
+	m.state.Tasks = result
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	m.taskList.SetCursorByID(id)
+	return m, nil
+}
+
 func (m ReviewModel) reorder(taskID string, direction int) (ReviewModel, tea.Cmd) {
 	result, err := ReorderTask(m.state.Tasks, taskID, direction)
 	if err != nil {
-		m.confirmErr = err.Error()
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
-		})
+		m.status.Push(components.StatusError, err.Error())
+		return m, nil
 	}
 
 	m.state.Tasks = result
@@ -227,19 +705,14 @@ func (m ReviewModel) startEdit(taskID string) (ReviewModel, tea.Cmd) {
 	}
 
 	// Create temp file with task data
-	tmpDir := os.TempDir()
-	tmpPath := filepath.Join(tmpDir, fmt.Sprintf("forge-edit-%s.txt", taskID))
-
 	content := formatEditTemplate(task)
-	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
-		m.confirmErr = fmt.Sprintf("Failed to create temp file: %v", err)
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
-		})
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, fmt.Sprintf("forge-edit-%s-*.txt", taskID), "edit "+taskID, []byte(content))
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to create temp file: %v", err))
+		return m, nil
 	}
 
-	editor := getEditor()
-	c := exec.Command(editor, tmpPath)
+	c := platform.EditorCommand(tmpPath)
 
 	return m, tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorFinishedMsg{
@@ -251,20 +724,134 @@ func (m ReviewModel) startEdit(taskID string) (ReviewModel, tea.Cmd) {
 	})
 }
 
-func (m ReviewModel) startNew() (ReviewModel, tea.Cmd) {
-	tmpDir := os.TempDir()
-	tmpPath := filepath.Join(tmpDir, "forge-new-task.txt")
+// startPromptEdit opens the exact prompt the task's first attempt will
+// send, or its current override, in $EDITOR.
+func (m ReviewModel) startPromptEdit(taskID string) (ReviewModel, tea.Cmd) {
+	task := m.state.FindTask(taskID)
+	if task == nil {
+		return m, nil
+	}
 
-	content := formatNewTemplate()
-	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
-		m.confirmErr = fmt.Sprintf("Failed to create temp file: %v", err)
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
+	// context.md is only written when inputs are confirmed; generate it
+	// the same way if this is the first pass through review.
+	contextContent := generator.GenerateContextFile(m.state)
+	if data, err := os.ReadFile(filepath.Join(state.ForgeDir(m.stateRoot), "context.md")); err == nil {
+		contextContent = string(data)
+	}
+	contextContent += executor.APIContext(*task, m.state.Snapshot)
+	generated := executor.BuildTaskExecutionPrompt(contextContent, *task, m.state.Settings)
+
+	content := executor.TaskPrompt(contextContent, *task, m.state.Settings)
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, fmt.Sprintf("forge-prompt-%s-*.md", taskID), "prompt "+taskID, []byte(content))
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to create temp file: %v", err))
+		return m, nil
+	}
+
+	c := platform.EditorCommand(tmpPath)
+
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return promptEditedMsg{
+			err:       err,
+			tmpPath:   tmpPath,
+			taskID:    taskID,
+			generated: generated,
+		}
+	})
+}
+
+func (m ReviewModel) handlePromptEdited(msg promptEditedMsg) (ReviewModel, tea.Cmd) {
+	defer janitor.Release(m.stateRoot, msg.tmpPath)
+
+	if msg.err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Editor error: %v", msg.err))
+		return m, nil
+	}
+
+	data, err := os.ReadFile(msg.tmpPath)
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to read temp file: %v", err))
+		return m, nil
+	}
+
+	task := m.state.FindTask(msg.taskID)
+	if task == nil {
+		return m, nil
+	}
+	task.PromptOverride = ResolvePromptOverride(string(data), msg.generated)
+
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	m.taskList.SetCursorByID(msg.taskID)
+	return m, nil
+}
+
+// startPlanEdit opens the whole plan as a YAML file (FormatPlanFile) in
+// $EDITOR.
+func (m ReviewModel) startPlanEdit() (ReviewModel, tea.Cmd) {
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, "forge-plan-*.yaml", "edit plan", []byte(FormatPlanFile(m.state)))
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to create temp file: %v", err))
+		return m, nil
+	}
+
+	c := platform.EditorCommand(tmpPath)
+
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return planEditedMsg{err: err, tmpPath: tmpPath}
+	})
+}
+
+// handlePlanEdited applies the edited plan file. Nothing changes unless
+// the whole file is valid.
+func (m ReviewModel) handlePlanEdited(msg planEditedMsg) (ReviewModel, tea.Cmd) {
+	if m.rejected != nil && m.rejected.tmpPath == msg.tmpPath {
+		m.rejected = nil // reopened; kept again below if still invalid
+	}
+
+	fail := func(format string, args ...any) (ReviewModel, tea.Cmd) {
+		_ = janitor.Release(m.stateRoot, msg.tmpPath)
+		m.status.Push(components.StatusError, fmt.Sprintf(format, args...))
+		return m, nil
+	}
+	reject := func(format string, args ...any) (ReviewModel, tea.Cmd) {
+		return m.rejectEdit(msg.tmpPath, "plan edit", fmt.Errorf(format, args...), func(err error) tea.Msg {
+			return planEditedMsg{err: err, tmpPath: msg.tmpPath}
 		})
 	}
 
-	editor := getEditor()
-	c := exec.Command(editor, tmpPath)
+	if msg.err != nil {
+		return fail("Editor error: %v", msg.err)
+	}
+	data, err := os.ReadFile(msg.tmpPath)
+	if err != nil {
+		return fail("Failed to read temp file: %v", err)
+	}
+	entries, err := ParsePlanFile(string(data))
+	if err != nil {
+		return reject("invalid plan file: %v", err)
+	}
+	result, err := ApplyPlanFile(m.state.Tasks, entries, m.state.PlanVersion)
+	if err != nil {
+		return reject("%v", err)
+	}
+
+	_ = janitor.Release(m.stateRoot, msg.tmpPath)
+	m.state.Tasks = result
+	_ = state.Save(m.stateRoot, m.state)
+	m.refreshList()
+	return m, nil
+}
+
+func (m ReviewModel) startNew() (ReviewModel, tea.Cmd) {
+	content := formatNewTemplate()
+	tmpPath, err := janitor.CreateTemp(m.stateRoot, "forge-new-task-*.txt", "new task", []byte(content))
+	if err != nil {
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to create temp file: %v", err))
+		return m, nil
+	}
+
+	c := platform.EditorCommand(tmpPath)
 
 	return m, tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorFinishedMsg{
@@ -276,39 +863,70 @@ func (m ReviewModel) startNew() (ReviewModel, tea.Cmd) {
 }
 
 func (m ReviewModel) handleEditorFinished(msg editorFinishedMsg) (ReviewModel, tea.Cmd) {
-	// Clean up temp file on exit
-	defer os.Remove(msg.tmpPath)
+	if m.rejected != nil && m.rejected.tmpPath == msg.tmpPath {
+		m.rejected = nil // reopened; kept again below if still invalid
+	}
+
+	// Clean up temp file on exit, unless it is kept for e to reopen
+	keep := false
+	defer func() {
+		if !keep {
+			janitor.Release(m.stateRoot, msg.tmpPath)
+		}
+	}()
 
 	if msg.err != nil {
-		m.confirmErr = fmt.Sprintf("Editor error: %v", msg.err)
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
-		})
+		m.status.Push(components.StatusError, fmt.Sprintf("Editor error: %v", msg.err))
+		return m, nil
 	}
 
 	data, err := os.ReadFile(msg.tmpPath)
 	if err != nil {
-		m.confirmErr = fmt.Sprintf("Failed to read temp file: %v", err)
-		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-			return clearConfirmErrMsg{}
+		m.status.Push(components.StatusError, fmt.Sprintf("Failed to read temp file: %v", err))
+		return m, nil
+	}
+
+	label := "edit of " + msg.taskID
+	if msg.isNew {
+		label = "new task"
+	}
+	reject := func(err error) (ReviewModel, tea.Cmd) {
+		keep = true
+		return m.rejectEdit(msg.tmpPath, label, err, func(err error) tea.Msg {
+			return editorFinishedMsg{err: err, tmpPath: msg.tmpPath, taskID: msg.taskID, isNew: msg.isNew}
 		})
 	}
 
 	parsed := parseEditTemplate(string(data))
 
+	if !state.ValidTaskType(parsed.taskType) {
+		return reject(fieldErr("type", "type must be code, verify or manual (got %q)", parsed.taskType))
+	}
+	if !state.ValidTaskOwner(parsed.owner) {
+		return reject(fieldErr("owner", "owner must be agent or human (got %q)", parsed.owner))
+	}
+
 	if msg.isNew {
 		// Validate and add new task
 		if err := ValidateNewTask(m.state.Tasks, parsed.title, parsed.description, parsed.complexity, parsed.criteria, parsed.dependsOn); err != nil {
-			m.confirmErr = fmt.Sprintf("Invalid task: %v", err)
-			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-				return clearConfirmErrMsg{}
-			})
+			return reject(err)
 		}
-		m.state.AddTask(parsed.title, parsed.description, parsed.complexity, parsed.criteria, parsed.dependsOn)
+		task := m.state.AddTask(parsed.title, parsed.description, parsed.complexity, parsed.criteria, parsed.dependsOn)
+		task.Labels = parsed.labels
+		task.Type = parsed.taskType
+		task.Commands = parsed.commands
+		task.Artifacts = parsed.artifacts
+		task.Model = parsed.model
+		task.Owner = parsed.owner
+		task.Assignee = parsed.assignee
 	} else {
 		// Update existing task
 		task := m.state.FindTask(msg.taskID)
 		if task != nil {
+			title, complexity := cmp.Or(parsed.title, task.Title), cmp.Or(parsed.complexity, task.Complexity)
+			if err := ValidateNewTask(m.state.Tasks, title, parsed.description, complexity, parsed.criteria, parsed.dependsOn); err != nil {
+				return reject(err)
+			}
 			if parsed.title != "" {
 				task.Title = parsed.title
 			}
@@ -318,6 +936,13 @@ func (m ReviewModel) handleEditorFinished(msg editorFinishedMsg) (ReviewModel, t
 			task.Description = parsed.description
 			task.AcceptanceCriteria = parsed.criteria
 			task.DependsOn = parsed.dependsOn
+			task.Labels = parsed.labels
+			task.Type = parsed.taskType
+			task.Commands = parsed.commands
+			task.Artifacts = parsed.artifacts
+			task.Model = parsed.model
+			task.Owner = parsed.owner
+			task.Assignee = parsed.assignee
 			task.PlanVersionModified = m.state.PlanVersion
 		}
 	}
@@ -329,6 +954,23 @@ func (m ReviewModel) handleEditorFinished(msg editorFinishedMsg) (ReviewModel, t
 
 // --- Helpers ---
 
+// ShowPartialPlan shows the tasks of a plan that is still being generated,
+// keeping the cursor where it was. The final plan replaces the model when
+// it arrives.
+func (m *ReviewModel) ShowPartialPlan(tasks []claude.PlanTaskJSON) {
+	m.generating = true
+	m.generated = len(tasks)
+	cursorID := m.taskList.CursorID()
+	items := buildReviewItems(&state.State{Tasks: PartialPlanTasks(tasks)})
+	for i := range items {
+		items[i].Editable = false
+	}
+	m.taskList.SetItems(items)
+	if cursorID != "" {
+		m.taskList.SetCursorByID(cursorID)
+	}
+}
+
 func (m *ReviewModel) refreshList() {
 	cursorID := m.taskList.CursorID()
 	items := buildReviewItems(m.state)
@@ -363,22 +1005,6 @@ func buildReviewItems(s *state.State) []components.TaskListItem {
 	return items
 }
 
-func getEditor() string {
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
-	}
-	if editor := os.Getenv("VISUAL"); editor != "" {
-		return editor
-	}
-	// Fallback chain
-	for _, name := range []string{"nano", "vi", "notepad"} {
-		if _, err := exec.LookPath(name); err == nil {
-			return name
-		}
-	}
-	return "nano"
-}
-
 // --- Edit Template Formatting/Parsing ---
 
 func formatEditTemplate(task *state.Task) string {
@@ -388,6 +1014,21 @@ func formatEditTemplate(task *state.Task) string {
 	fmt.Fprintf(&b, "Status: %s (do not change)\n", task.Status)
 	fmt.Fprintf(&b, "title: %s\n", task.Title)
 	fmt.Fprintf(&b, "complexity: %s\n", task.Complexity)
+	fmt.Fprintf(&b, "labels: %s\n", strings.Join(task.Labels, ", "))
+	fmt.Fprintf(&b, "type: %s\n", task.EffectiveType())
+	if task.ForHuman() {
+		fmt.Fprintf(&b, "owner: %s\n", state.OwnerHuman)
+	} else {
+		fmt.Fprintf(&b, "owner: %s\n", state.OwnerAgent)
+	}
+	fmt.Fprintf(&b, "assignee: %s\n", task.Assignee)
+	for _, c := range task.Commands {
+		fmt.Fprintf(&b, "command: %s\n", c)
+	}
+	for _, a := range task.Artifacts {
+		fmt.Fprintf(&b, "artifact: %s\n", a)
+	}
+	fmt.Fprintf(&b, "model: %s\n", task.Model)
 
 	if len(task.DependsOn) > 0 {
 		b.WriteString("depends_on:\n")
@@ -415,6 +1056,15 @@ func formatNewTemplate() string {
 
 	b.WriteString("title: \n")
 	b.WriteString("complexity: medium\n")
+	b.WriteString("labels: \n")
+	b.WriteString("type: code\n")
+	b.WriteString("# type: code, verify (add \"command: ...\" lines) or manual (description = instructions)\n")
+	b.WriteString("# artifact: coverage.out   (repeatable; globs kept in .forge/artifacts/ on success)\n")
+	b.WriteString("model: \n")
+	b.WriteString("# model: e.g. opus or haiku; empty runs the task on the provider's model\n")
+	b.WriteString("owner: agent\n")
+	b.WriteString("# owner: agent (forge runs it) or human (done outside forge, then marked done)\n")
+	b.WriteString("assignee: \n")
 	b.WriteString("depends_on:\n")
 
 	b.WriteString("\n## Description\n")
@@ -429,6 +1079,13 @@ func formatNewTemplate() string {
 type parsedTemplate struct {
 	title       string
 	complexity  string
+	labels      []string
+	taskType    state.TaskType
+	commands    []string
+	artifacts   []string
+	model       string
+	owner       state.TaskOwner
+	assignee    string
 	dependsOn   []string
 	description string
 	criteria    []string
@@ -462,6 +1119,30 @@ func parseEditTemplate(content string) parsedTemplate {
 				result.title = strings.TrimSpace(strings.TrimPrefix(trimmed, "title:"))
 			} else if strings.HasPrefix(trimmed, "complexity:") {
 				result.complexity = strings.TrimSpace(strings.TrimPrefix(trimmed, "complexity:"))
+			} else if strings.HasPrefix(trimmed, "labels:") {
+				for _, l := range strings.Split(strings.TrimPrefix(trimmed, "labels:"), ",") {
+					if l = strings.TrimSpace(l); l != "" {
+						result.labels = append(result.labels, l)
+					}
+				}
+			} else if strings.HasPrefix(trimmed, "type:") {
+				result.taskType = state.TaskType(strings.TrimSpace(strings.TrimPrefix(trimmed, "type:")))
+			} else if strings.HasPrefix(trimmed, "command:") {
+				if c := strings.TrimSpace(strings.TrimPrefix(trimmed, "command:")); c != "" {
+					result.commands = append(result.commands, c)
+				}
+			} else if strings.HasPrefix(trimmed, "owner:") {
+				if o := state.TaskOwner(strings.TrimSpace(strings.TrimPrefix(trimmed, "owner:"))); o != state.OwnerAgent {
+					result.owner = o
+				}
+			} else if strings.HasPrefix(trimmed, "model:") {
+				result.model = strings.TrimSpace(strings.TrimPrefix(trimmed, "model:"))
+			} else if strings.HasPrefix(trimmed, "assignee:") {
+				result.assignee = strings.TrimSpace(strings.TrimPrefix(trimmed, "assignee:"))
+			} else if strings.HasPrefix(trimmed, "artifact:") {
+				if a := strings.TrimSpace(strings.TrimPrefix(trimmed, "artifact:")); a != "" {
+					result.artifacts = append(result.artifacts, a)
+				}
 			} else if strings.HasPrefix(trimmed, "- ") && !strings.HasPrefix(trimmed, "- task") {
 				// Skip non-task dependency lines
 			} else if strings.HasPrefix(trimmed, "- task") || strings.HasPrefix(trimmed, "- task-") {
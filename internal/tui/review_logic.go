@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/manasm11/forge/internal/planner"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -76,7 +79,8 @@ func BuildTaskDisplayList(tasks []state.Task) []TaskDisplayItem {
 }
 
 // ReorderTask moves a task in the given direction among pending tasks.
-// Only pending tasks can be reordered. Done tasks are pinned at the top.
+// Only pending tasks can be reordered, and never ahead of a task they
+// depend on. Done tasks are pinned at the top.
 // direction: -1 = up, +1 = down.
 // Returns the updated full task slice (does not mutate input).
 func ReorderTask(tasks []state.Task, taskID string, direction int) ([]state.Task, error) {
@@ -119,6 +123,10 @@ func ReorderTask(tasks []state.Task, taskID string, direction int) ([]state.Task
 	if swapIdx == -1 {
 		return nil, fmt.Errorf("cannot move task further in that direction")
 	}
+	earlier, later := tasks[min(taskIdx, swapIdx)], tasks[max(taskIdx, swapIdx)]
+	if err := checkReorderDeps(earlier.ID, later.ID, later.DependsOn); err != nil {
+		return nil, err
+	}
 
 	// Create a copy and swap
 	result := make([]state.Task, len(tasks))
@@ -128,6 +136,15 @@ func ReorderTask(tasks []state.Task, taskID string, direction int) ([]state.Task
 	return result, nil
 }
 
+// checkReorderDeps rejects a swap that would put the later task ahead of
+// the earlier one when it depends on it.
+func checkReorderDeps(earlierID, laterID string, laterDeps []string) error {
+	if slices.Contains(laterDeps, earlierID) {
+		return fmt.Errorf("%s depends on %s; it can't run before it", laterID, earlierID)
+	}
+	return nil
+}
+
 // DeleteTask removes a pending task from the slice.
 // Returns error if task is done/in-progress or not found.
 // Also removes this task's ID from any other task's DependsOn list.
@@ -174,19 +191,191 @@ func DeleteTask(tasks []state.Task, taskID string) ([]state.Task, error) {
 	return result, nil
 }
 
+// BulkDelete removes several pending tasks, as DeleteTask does for one.
+// Nothing is removed if any of them can't be. Does not mutate the input.
+func BulkDelete(tasks []state.Task, taskIDs []string) ([]state.Task, error) {
+	result := tasks
+	for _, id := range taskIDs {
+		var err error
+		if result, err = DeleteTask(result, id); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// BulkSetComplexity sets the complexity of several editable tasks. Does not
+// mutate the input.
+func BulkSetComplexity(tasks []state.Task, taskIDs []string, complexity string, planVersion int) ([]state.Task, error) {
+	switch complexity {
+	case "small", "medium", "large":
+	default:
+		return nil, fmt.Errorf("complexity must be small, medium, or large (got %q)", complexity)
+	}
+	return bulkEdit(tasks, taskIDs, planVersion, func(t *state.Task) {
+		t.Complexity = complexity
+	})
+}
+
+// BulkAddLabel adds a label to several editable tasks, skipping ones that
+// already have it. Does not mutate the input.
+func BulkAddLabel(tasks []state.Task, taskIDs []string, label string, planVersion int) ([]state.Task, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil, fmt.Errorf("label must not be empty")
+	}
+	return bulkEdit(tasks, taskIDs, planVersion, func(t *state.Task) {
+		if !slices.Contains(t.Labels, label) {
+			t.Labels = append(slices.Clone(t.Labels), label)
+		}
+	})
+}
+
+// BulkAddDependency makes several editable tasks depend on depID. A task
+// can't depend on itself, and the change is rejected if it would create a
+// dependency cycle. Does not mutate the input.
+func BulkAddDependency(tasks []state.Task, taskIDs []string, depID string, planVersion int) ([]state.Task, error) {
+	if !slices.ContainsFunc(tasks, func(t state.Task) bool { return t.ID == depID }) {
+		return nil, fmt.Errorf("task %q not found", depID)
+	}
+	if slices.Contains(taskIDs, depID) {
+		return nil, fmt.Errorf("%s can't depend on itself — unmark it first", depID)
+	}
+	result, err := bulkEdit(tasks, taskIDs, planVersion, func(t *state.Task) {
+		if !slices.Contains(t.DependsOn, depID) {
+			t.DependsOn = append(slices.Clone(t.DependsOn), depID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cycle := DetectCircularDependencies(result); len(cycle) > 0 {
+		return nil, fmt.Errorf("circular dependency: %s", strings.Join(cycle, " → "))
+	}
+	return result, nil
+}
+
+// SetDependencies replaces an editable task's dependencies, as chosen in
+// the dependency picker. Does not mutate the input.
+func SetDependencies(tasks []state.Task, taskID string, deps []string, planVersion int) ([]state.Task, error) {
+	dependents := state.Dependents(tasks, taskID)
+	for _, dep := range deps {
+		switch {
+		case dep == taskID:
+			return nil, fmt.Errorf("%s can't depend on itself", taskID)
+		case !slices.ContainsFunc(tasks, func(t state.Task) bool { return t.ID == dep }):
+			return nil, fmt.Errorf("dependency %q does not exist", dep)
+		case dependents[dep]:
+			return nil, fmt.Errorf("%s already depends on %s", dep, taskID)
+		}
+	}
+	return bulkEdit(tasks, []string{taskID}, planVersion, func(t *state.Task) {
+		t.DependsOn = slices.Clone(deps)
+	})
+}
+
+// bulkEdit applies fn to copies of the given tasks, which must all be
+// pending or failed, and marks them modified in planVersion.
+func bulkEdit(tasks []state.Task, taskIDs []string, planVersion int, fn func(*state.Task)) ([]state.Task, error) {
+	if len(taskIDs) == 0 {
+		return nil, fmt.Errorf("no tasks selected")
+	}
+	result := slices.Clone(tasks)
+	for _, id := range taskIDs {
+		i := slices.IndexFunc(result, func(t state.Task) bool { return t.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("task %q not found", id)
+		}
+		if s := result[i].Status; s != state.TaskPending && s != state.TaskFailed {
+			return nil, fmt.Errorf("cannot edit %s task %q", s, id)
+		}
+		fn(&result[i])
+		result[i].PlanVersionModified = planVersion
+	}
+	return result, nil
+}
+
+// DuplicateTask copies a task as a new pending task right after it, titled
+// "<title> (copy)". The plan fields (description, criteria, complexity,
+// dependencies, type, commands, artifacts, model, labels, owner) are kept; the
+// execution history and any prompt override are not. Returns the updated
+// slice and the copy's ID. Does not mutate the input.
+func DuplicateTask(tasks []state.Task, taskID string, planVersion int) ([]state.Task, string, error) {
+	i := slices.IndexFunc(tasks, func(t state.Task) bool { return t.ID == taskID })
+	if i < 0 {
+		return nil, "", fmt.Errorf("task %q not found", taskID)
+	}
+	src := tasks[i]
+	dup := state.Task{
+		ID:                  (&state.State{Tasks: tasks}).NextTaskID(),
+		Title:               src.Title + " (copy)",
+		Description:         src.Description,
+		AcceptanceCriteria:  slices.Clone(src.AcceptanceCriteria),
+		DependsOn:           slices.Clone(src.DependsOn),
+		Complexity:          src.Complexity,
+		Labels:              slices.Clone(src.Labels),
+		Type:                src.Type,
+		Commands:            slices.Clone(src.Commands),
+		Artifacts:           slices.Clone(src.Artifacts),
+		Model:               src.Model,
+		Owner:               src.Owner,
+		Assignee:            src.Assignee,
+		Repo:                src.Repo,
+		Status:              state.TaskPending,
+		PlanVersionCreated:  planVersion,
+		PlanVersionModified: planVersion,
+	}
+	return slices.Insert(slices.Clone(tasks), i+1, dup), dup.ID, nil
+}
+
+// editErrorPrefix starts the comment lines AnnotateEditError adds.
+const editErrorPrefix = "# forge: "
+
+// AnnotateEditError writes why an edit was rejected at the top of the
+// edited file, replacing the notes of an earlier attempt. A FieldError is
+// also noted right under its "key:" line. The edit template and the plan
+// file both ignore these comment lines.
+func AnnotateEditError(content string, err error) string {
+	var fe FieldError
+	targeted := errors.As(err, &fe)
+
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, editErrorPrefix) {
+			continue
+		}
+		lines = append(lines, line)
+		if targeted && strings.HasPrefix(strings.TrimSpace(line), fe.Field+":") {
+			lines = append(lines, editErrorPrefix+"↑ "+fe.Message)
+			targeted = false
+		}
+	}
+
+	var b strings.Builder
+	for i, line := range strings.Split(err.Error(), "\n") {
+		if i == 0 {
+			line = "NOT APPLIED: " + line
+		}
+		b.WriteString(editErrorPrefix + line + "\n")
+	}
+	b.WriteString(editErrorPrefix + "fix the file and save to try again; these lines are ignored\n")
+	return b.String() + strings.Join(lines, "\n")
+}
+
 // ValidateNewTask checks that a manually added task has valid fields.
 // Title must be non-empty. Complexity must be small/medium/large.
-// DependsOn IDs must reference existing tasks.
+// DependsOn IDs must reference existing tasks. The error is a FieldError
+// naming the edit template key at fault.
 func ValidateNewTask(tasks []state.Task, title, description, complexity string, criteria []string, dependsOn []string) error {
 	if strings.TrimSpace(title) == "" {
-		return fmt.Errorf("title must not be empty")
+		return fieldErr("title", "title must not be empty")
 	}
 
 	switch complexity {
 	case "small", "medium", "large":
 		// valid
 	default:
-		return fmt.Errorf("complexity must be small, medium, or large (got %q)", complexity)
+		return fieldErr("complexity", "complexity must be small, medium, or large (got %q)", complexity)
 	}
 
 	// Check that all dependencies exist
@@ -196,7 +385,7 @@ func ValidateNewTask(tasks []state.Task, title, description, complexity string,
 	}
 	for _, dep := range dependsOn {
 		if !taskIDs[dep] {
-			return fmt.Errorf("dependency %q does not exist", dep)
+			return fieldErr("depends_on", "dependency %q does not exist", dep)
 		}
 	}
 
@@ -204,12 +393,16 @@ func ValidateNewTask(tasks []state.Task, title, description, complexity string,
 }
 
 // FormatTaskDetail produces the expanded detail text for a task.
-// Includes: title, complexity, dependencies (resolved to titles), description, acceptance criteria.
+// Includes: title, complexity, dependencies (resolved to titles), risk for
+// pending tasks, description, acceptance criteria.
 func FormatTaskDetail(task state.Task, allTasks []state.Task) string {
 	var b strings.Builder
 
 	fmt.Fprintf(&b, "%s: %s\n", task.ID, task.Title)
 	fmt.Fprintf(&b, "Complexity: %s", task.Complexity)
+	if t := task.EffectiveType(); t != state.TaskTypeCode {
+		fmt.Fprintf(&b, " · Type: %s", t)
+	}
 
 	if len(task.DependsOn) > 0 {
 		depTitles := ResolveDependencyTitles(task.DependsOn, allTasks)
@@ -217,6 +410,15 @@ func FormatTaskDetail(task state.Task, allTasks []state.Task) string {
 	}
 	b.WriteString("\n")
 
+	if len(task.Labels) > 0 {
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(task.Labels, ", "))
+	}
+
+	if task.Status == state.TaskPending {
+		risk := state.TaskRisk(task, allTasks)
+		fmt.Fprintf(&b, "Risk: %s (%d) — %s\n", risk.Level(), risk.Score, strings.Join(risk.Reasons, "; "))
+	}
+
 	if task.Description != "" {
 		fmt.Fprintf(&b, "%s\n", task.Description)
 	}
@@ -228,9 +430,58 @@ func FormatTaskDetail(task state.Task, allTasks []state.Task) string {
 		}
 	}
 
+	if len(task.Commands) > 0 {
+		b.WriteString("Commands:\n")
+		for _, c := range task.Commands {
+			fmt.Fprintf(&b, "$ %s\n", c)
+		}
+	}
+
+	if task.ForHuman() {
+		fmt.Fprintf(&b, "Owner: human%s — forge won't run it; mark it done once finished\n", FormatAssignee(task.Assignee))
+	} else if task.Assignee != "" {
+		fmt.Fprintf(&b, "Assignee: %s\n", task.Assignee)
+	}
+
+	if task.Status == state.TaskSkipped && task.SkippedReason != "" {
+		fmt.Fprintf(&b, "Skipped: %s\n", task.SkippedReason)
+	}
+
+	if len(task.Artifacts) > 0 {
+		fmt.Fprintf(&b, "Artifacts: %s\n", strings.Join(task.Artifacts, ", "))
+	}
+	if len(task.CollectedArtifacts) > 0 {
+		fmt.Fprintf(&b, "Collected (.forge/artifacts/%s/):\n", task.ID)
+		for _, a := range task.CollectedArtifacts {
+			fmt.Fprintf(&b, "  %s\n", a)
+		}
+	}
+
+	if task.Model != "" {
+		fmt.Fprintf(&b, "Model: %s\n", task.Model)
+	}
+
+	if task.Handoff != "" {
+		fmt.Fprintf(&b, "Handoff note:\n%s\n", task.Handoff)
+	}
+
+	if task.PromptOverride != "" {
+		b.WriteString("Execution prompt: custom override (p to view or edit)\n")
+	}
+
 	return b.String()
 }
 
+// ResolvePromptOverride decides what to store after the user edits a task's
+// execution prompt. Clearing the file, or leaving the generated prompt
+// unchanged, removes the override so the prompt keeps following task edits.
+func ResolvePromptOverride(edited, generated string) string {
+	if strings.TrimSpace(edited) == "" || strings.TrimSpace(edited) == strings.TrimSpace(generated) {
+		return ""
+	}
+	return edited
+}
+
 // ResolveDependencyTitles maps task IDs in DependsOn to their titles.
 // Returns "task-001: Init project" format. Unknown IDs return "task-XXX: (unknown)".
 func ResolveDependencyTitles(dependsOn []string, allTasks []state.Task) []string {
@@ -364,3 +615,65 @@ func DetectCircularDependencies(tasks []state.Task) []string {
 
 	return nil
 }
+
+// SetAcceptanceCriteria replaces the criteria of a pending or failed task,
+// e.g. with an accepted suggestion. Does not mutate the input.
+func SetAcceptanceCriteria(tasks []state.Task, taskID string, criteria []string, planVersion int) ([]state.Task, error) {
+	if len(criteria) == 0 {
+		return nil, fmt.Errorf("acceptance criteria must not be empty")
+	}
+	return bulkEdit(tasks, []string{taskID}, planVersion, func(t *state.Task) {
+		t.AcceptanceCriteria = slices.Clone(criteria)
+	})
+}
+
+// FormatCriteriaDiff shows a criteria suggestion as a line diff: "- " for
+// a criterion it drops, "+ " for one it adds and "  " for one it keeps.
+func FormatCriteriaDiff(edit planner.CriteriaEdit) string {
+	old, next := edit.Old, edit.New
+	// lcs[i][j] is the longest common subsequence of old[i:] and next[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(next)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(next) - 1; j >= 0; j-- {
+			if old[i] == next[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(old) || j < len(next) {
+		switch {
+		case i < len(old) && j < len(next) && old[i] == next[j]:
+			lines = append(lines, "  "+old[i])
+			i, j = i+1, j+1
+		case j == len(next) || (i < len(old) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+old[i])
+			i++
+		default:
+			lines = append(lines, "+ "+next[j])
+			j++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatCritique renders the critic's findings as the system message shown
+// in review and kept in the planning conversation.
+func FormatCritique(findings []string) string {
+	if len(findings) == 0 {
+		return "Plan critique: no missing steps, risky orderings or testing gaps found."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan critique (%d):", len(findings))
+	for _, f := range findings {
+		b.WriteString("\n  • " + f)
+	}
+	return b.String()
+}
@@ -1,9 +1,13 @@
 package tui
 
 import (
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/manasm11/forge/internal/planner"
 	"github.com/manasm11/forge/internal/state"
 )
 
@@ -134,6 +138,16 @@ func TestReorderTask(t *testing.T) {
 			direction: -1,
 			wantErr:   true,
 		},
+		{
+			name: "cannot move a task above its dependency",
+			tasks: []state.Task{
+				{ID: "task-001", Status: state.TaskPending},
+				{ID: "task-002", Status: state.TaskPending, DependsOn: []string{"task-001"}},
+			},
+			taskID:    "task-002",
+			direction: -1,
+			wantErr:   true,
+		},
 		{
 			name: "cannot move last pending task down",
 			tasks: []state.Task{
@@ -301,6 +315,282 @@ func TestDeleteTask(t *testing.T) {
 	}
 }
 
+// ============================================================
+// Bulk actions
+// ============================================================
+
+func bulkTasks() []state.Task {
+	return []state.Task{
+		{ID: "task-001", Status: state.TaskDone, Complexity: "small"},
+		{ID: "task-002", Status: state.TaskPending, Complexity: "small"},
+		{ID: "task-003", Status: state.TaskPending, Complexity: "medium", DependsOn: []string{"task-002"}, Labels: []string{"api"}},
+		{ID: "task-004", Status: state.TaskFailed, Complexity: "large"},
+	}
+}
+
+func TestBulkDelete(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		ids     []string
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "deletes all and cleans dependencies", ids: []string{"task-002", "task-004"}, wantIDs: []string{"task-001", "task-003"}},
+		{name: "done task aborts the whole delete", ids: []string{"task-002", "task-001"}, wantErr: true},
+		{name: "unknown task", ids: []string{"task-999"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks := bulkTasks()
+			result, err := BulkDelete(tasks, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tasks) != 4 {
+				t.Fatalf("input mutated: %d tasks", len(tasks))
+			}
+			if tt.wantErr {
+				return
+			}
+			var ids []string
+			for _, task := range result {
+				ids = append(ids, task.ID)
+				if len(task.DependsOn) > 0 {
+					t.Errorf("%s.DependsOn = %v, want cleaned", task.ID, task.DependsOn)
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("remaining = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestBulkSetComplexity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		ids        []string
+		complexity string
+		wantErr    bool
+	}{
+		{name: "pending and failed tasks", ids: []string{"task-002", "task-004"}, complexity: "medium"},
+		{name: "done task", ids: []string{"task-001"}, complexity: "large", wantErr: true},
+		{name: "invalid complexity", ids: []string{"task-002"}, complexity: "huge", wantErr: true},
+		{name: "nothing selected", complexity: "small", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks := bulkTasks()
+			result, err := BulkSetComplexity(tasks, tt.ids, tt.complexity, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, task := range result {
+				if slices.Contains(tt.ids, task.ID) {
+					if task.Complexity != tt.complexity || task.PlanVersionModified != 3 {
+						t.Errorf("%s = %q (modified v%d), want %q (v3)", task.ID, task.Complexity, task.PlanVersionModified, tt.complexity)
+					}
+				}
+			}
+			if tasks[1].Complexity != "small" {
+				t.Error("input mutated")
+			}
+		})
+	}
+}
+
+func TestBulkAddLabel(t *testing.T) {
+	t.Parallel()
+	tasks := bulkTasks()
+	result, err := BulkAddLabel(tasks, []string{"task-002", "task-003"}, " api ", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result[1].Labels; len(got) != 1 || got[0] != "api" {
+		t.Errorf("task-002 labels = %v, want [api]", got)
+	}
+	if got := result[2].Labels; len(got) != 1 {
+		t.Errorf("task-003 labels = %v, want api once", got)
+	}
+	if tasks[1].Labels != nil {
+		t.Error("input mutated")
+	}
+
+	if _, err := BulkAddLabel(tasks, []string{"task-002"}, "  ", 2); err == nil {
+		t.Error("empty label accepted")
+	}
+}
+
+func TestBulkAddDependency(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		ids     []string
+		dep     string
+		wantErr string
+	}{
+		{name: "adds dependency", ids: []string{"task-003", "task-004"}, dep: "task-001"},
+		{name: "existing dependency kept once", ids: []string{"task-003"}, dep: "task-002"},
+		{name: "self dependency", ids: []string{"task-002", "task-003"}, dep: "task-002", wantErr: "itself"},
+		{name: "cycle", ids: []string{"task-002"}, dep: "task-003", wantErr: "circular"},
+		{name: "unknown dependency", ids: []string{"task-002"}, dep: "task-999", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := BulkAddDependency(bulkTasks(), tt.ids, tt.dep, 2)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, task := range result {
+				if !slices.Contains(tt.ids, task.ID) {
+					continue
+				}
+				n := 0
+				for _, d := range task.DependsOn {
+					if d == tt.dep {
+						n++
+					}
+				}
+				if n != 1 {
+					t.Errorf("%s.DependsOn = %v, want %s once", task.ID, task.DependsOn, tt.dep)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateTask(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Orders endpoint", Status: state.TaskDone, Complexity: "large",
+			AcceptanceCriteria: []string{"GET /orders works"}, DependsOn: []string{"task-000"},
+			Labels: []string{"api"}, Branch: "forge/task-001", GitSHA: "abc", Retries: 2, PromptOverride: "custom"},
+		{ID: "task-002", Title: "Docs", Status: state.TaskPending},
+	}
+	result, id, err := DuplicateTask(tasks, "task-001", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "task-003" || len(result) != 3 || result[1].ID != id {
+		t.Fatalf("copy %s at %v, want task-003 right after task-001", id, result)
+	}
+	dup := result[1]
+	if dup.Title != "Orders endpoint (copy)" || dup.Status != state.TaskPending || dup.Complexity != "large" ||
+		!slices.Equal(dup.AcceptanceCriteria, []string{"GET /orders works"}) || !slices.Equal(dup.Labels, []string{"api"}) ||
+		!slices.Equal(dup.DependsOn, []string{"task-000"}) || dup.PlanVersionCreated != 5 {
+		t.Errorf("copy = %+v", dup)
+	}
+	if dup.Branch != "" || dup.GitSHA != "" || dup.Retries != 0 || dup.PromptOverride != "" {
+		t.Errorf("execution history copied: %+v", dup)
+	}
+	dup.AcceptanceCriteria[0] = "changed"
+	if tasks[0].AcceptanceCriteria[0] != "GET /orders works" || len(tasks) != 2 {
+		t.Error("input mutated")
+	}
+
+	if _, _, err := DuplicateTask(tasks, "task-404", 5); err == nil {
+		t.Error("duplicating an unknown task should fail")
+	}
+}
+
+func TestAnnotateEditError(t *testing.T) {
+	t.Parallel()
+	template := formatNewTemplate()
+
+	once := AnnotateEditError(template, fmt.Errorf("owner must be agent or human"))
+	if !strings.HasPrefix(once, "# forge: NOT APPLIED: owner must be agent or human\n") {
+		t.Errorf("annotation missing:\n%s", once)
+	}
+	twice := AnnotateEditError(once, ValidateNewTask(nil, "", "", "medium", nil, nil))
+	if strings.Contains(twice, "owner must be") || strings.Count(twice, "NOT APPLIED") != 1 {
+		t.Errorf("earlier annotation should be replaced:\n%s", twice)
+	}
+	if !strings.Contains(twice, "title: \n# forge: ↑ title must not be empty\n") {
+		t.Errorf("field error should be noted under its line:\n%s", twice)
+	}
+	if stripped := AnnotateEditError(twice, fmt.Errorf("x")); !strings.HasSuffix(stripped, template) {
+		t.Error("the edit itself should be kept unchanged")
+	}
+
+	if got := parseEditTemplate(twice); got.title != "" || got.complexity != "medium" {
+		t.Errorf("annotated template parsed as %+v", got)
+	}
+	plan := AnnotateEditError(FormatPlanFile(planFileState()), fmt.Errorf("task-001 is done"))
+	if entries, err := ParsePlanFile(plan); err != nil || len(entries) != 3 {
+		t.Errorf("annotated plan file: %d entries, %v", len(entries), err)
+	}
+}
+
+func TestEditTemplate_Model(t *testing.T) {
+	t.Parallel()
+	task := &state.Task{ID: "task-001", Title: "Design schema", Complexity: "large", Model: "opus"}
+	if got := parseEditTemplate(formatEditTemplate(task)); got.model != "opus" {
+		t.Errorf("model = %q, want opus", got.model)
+	}
+	task.Model = ""
+	if got := parseEditTemplate(formatEditTemplate(task)); got.model != "" {
+		t.Errorf("model = %q, want none", got.model)
+	}
+	if got := parseEditTemplate(formatNewTemplate()); got.model != "" {
+		t.Errorf("new template model = %q, want none", got.model)
+	}
+}
+
+func TestSetDependencies(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		taskID  string
+		deps    []string
+		wantErr string
+	}{
+		{name: "replaces dependencies", taskID: "task-003", deps: []string{"task-001", "task-004"}},
+		{name: "clears dependencies", taskID: "task-003"},
+		{name: "cycle", taskID: "task-002", deps: []string{"task-003"}, wantErr: "task-003 already depends on task-002"},
+		{name: "self", taskID: "task-002", deps: []string{"task-002"}, wantErr: "itself"},
+		{name: "unknown", taskID: "task-002", deps: []string{"task-404"}, wantErr: "does not exist"},
+		{name: "done task", taskID: "task-001", deps: []string{"task-002"}, wantErr: "cannot edit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks := bulkTasks()
+			result, err := SetDependencies(tasks, tt.taskID, tt.deps, 4)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, task := range result {
+				if task.ID == tt.taskID && (!slices.Equal(task.DependsOn, tt.deps) || task.PlanVersionModified != 4) {
+					t.Errorf("%s.DependsOn = %v (v%d), want %v (v4)", task.ID, task.DependsOn, task.PlanVersionModified, tt.deps)
+				}
+			}
+			if len(tasks[2].DependsOn) != 1 {
+				t.Error("input mutated")
+			}
+		})
+	}
+}
+
 // ============================================================
 // ValidateNewTask
 // ============================================================
@@ -412,6 +702,91 @@ func TestFormatTaskDetail(t *testing.T) {
 	}
 }
 
+func TestFormatTaskDetail_PromptOverride(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-001", Title: "Init", Complexity: "small"}
+	if strings.Contains(FormatTaskDetail(task, nil), "Execution prompt") {
+		t.Error("detail should not mention the prompt without an override")
+	}
+	task.PromptOverride = "custom"
+	if !strings.Contains(FormatTaskDetail(task, nil), "Execution prompt: custom override") {
+		t.Error("detail should flag a prompt override")
+	}
+}
+
+func TestFormatTaskDetail_Risk(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Title: "Add webhooks", Complexity: "large", Status: state.TaskPending},
+		{ID: "task-002", Title: "Docs", Complexity: "small", Status: state.TaskPending, DependsOn: []string{"task-001"}},
+	}
+	want := "Risk: medium (6) — large complexity (+3); 1 dependent task(s) (+1); external integration: webhooks (+2)"
+	if got := FormatTaskDetail(tasks[0], tasks); !strings.Contains(got, want) {
+		t.Errorf("detail missing %q\ngot: %s", want, got)
+	}
+	tasks[0].Status = state.TaskDone
+	if strings.Contains(FormatTaskDetail(tasks[0], tasks), "Risk:") {
+		t.Error("detail should only score pending tasks")
+	}
+}
+
+func TestFormatTaskDetail_SkippedReason(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-001", Title: "Init", Status: state.TaskSkipped, SkippedReason: "skipped for now: starting from task-003"}
+	if !strings.Contains(FormatTaskDetail(task, nil), "Skipped: skipped for now: starting from task-003") {
+		t.Error("detail should show why the task was skipped")
+	}
+}
+
+func TestFormatTaskDetail_Labels(t *testing.T) {
+	t.Parallel()
+	task := state.Task{ID: "task-001", Title: "Init", Labels: []string{"frontend", "p1"}}
+	if !strings.Contains(FormatTaskDetail(task, nil), "Labels: frontend, p1") {
+		t.Error("detail should list the labels")
+	}
+}
+
+func TestFormatTaskDetail_Owner(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		task state.Task
+		want string
+	}{
+		{name: "human", task: state.Task{Owner: state.OwnerHuman, Assignee: "@alice"}, want: "Owner: human · @alice — forge won't run it"},
+		{name: "assigned agent task", task: state.Task{Assignee: "bob"}, want: "Assignee: bob\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatTaskDetail(tt.task, nil); !strings.Contains(got, tt.want) {
+				t.Errorf("FormatTaskDetail() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePromptOverride(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		edited string
+		want   string
+	}{
+		{name: "unchanged", edited: "generated prompt\n", want: ""},
+		{name: "cleared", edited: "  \n", want: ""},
+		{name: "edited", edited: "generated prompt\nAlso update the README.\n", want: "generated prompt\nAlso update the README.\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ResolvePromptOverride(tt.edited, "generated prompt"); got != tt.want {
+				t.Errorf("ResolvePromptOverride() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatTaskDetail_NoDependencies(t *testing.T) {
 	t.Parallel()
 	task := state.Task{
@@ -427,6 +802,25 @@ func TestFormatTaskDetail_NoDependencies(t *testing.T) {
 	}
 }
 
+func TestFormatTaskDetail_TaskType(t *testing.T) {
+	t.Parallel()
+	verify := state.Task{
+		ID: "task-002", Title: "E2E", Complexity: "small",
+		Type: state.TaskTypeVerify, Commands: []string{"make e2e"},
+	}
+	detail := FormatTaskDetail(verify, []state.Task{verify})
+	for _, want := range []string{"Type: verify", "$ make e2e"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail missing %q:\n%s", want, detail)
+		}
+	}
+
+	code := state.Task{ID: "task-001", Title: "Init", Complexity: "small"}
+	if strings.Contains(FormatTaskDetail(code, []state.Task{code}), "Type:") {
+		t.Error("code tasks should not show a type")
+	}
+}
+
 // ============================================================
 // ResolveDependencyTitles
 // ============================================================
@@ -645,3 +1039,81 @@ func TestDetectCircularDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCritique(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		findings []string
+		want     string
+	}{
+		{name: "no findings", findings: nil, want: "Plan critique: no missing steps, risky orderings or testing gaps found."},
+		{
+			name:     "findings",
+			findings: []string{"No integration tests.", "Deploy runs first."},
+			want:     "Plan critique (2):\n  • No integration tests.\n  • Deploy runs first.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatCritique(tt.findings); got != tt.want {
+				t.Errorf("FormatCritique() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ============================================================
+// Criteria suggestions
+// ============================================================
+
+func TestFormatCriteriaDiff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		old, new []string
+		want     string
+	}{
+		{name: "rewrite", old: []string{"works well"}, new: []string{"GET /users returns 200"},
+			want: "- works well\n+ GET /users returns 200"},
+		{name: "keeps common lines in place", old: []string{"a", "vague", "c"}, new: []string{"a", "b1", "b2", "c", "d"},
+			want: "  a\n- vague\n+ b1\n+ b2\n  c\n+ d"},
+		{name: "from none", new: []string{"make test passes"}, want: "+ make test passes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FormatCriteriaDiff(planner.CriteriaEdit{Old: tt.old, New: tt.new})
+			if got != tt.want {
+				t.Errorf("FormatCriteriaDiff()\n got  %q\n want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAcceptanceCriteria(t *testing.T) {
+	t.Parallel()
+	tasks := []state.Task{
+		{ID: "task-001", Status: state.TaskDone, AcceptanceCriteria: []string{"builds"}},
+		{ID: "task-002", Status: state.TaskPending, AcceptanceCriteria: []string{"works"}},
+	}
+
+	got, err := SetAcceptanceCriteria(tasks, "task-002", []string{"make test passes"}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := got[1].AcceptanceCriteria; !reflect.DeepEqual(c, []string{"make test passes"}) || got[1].PlanVersionModified != 3 {
+		t.Errorf("task-002 = %+v", got[1])
+	}
+	if tasks[1].AcceptanceCriteria[0] != "works" {
+		t.Error("the input was mutated")
+	}
+
+	if _, err := SetAcceptanceCriteria(tasks, "task-001", []string{"x"}, 3); err == nil {
+		t.Error("a done task's criteria should not change")
+	}
+	if _, err := SetAcceptanceCriteria(tasks, "task-002", nil, 3); err == nil {
+		t.Error("empty criteria should be rejected")
+	}
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/tui/components"
+)
+
+// RunsModel is the Runs tab: a list of past execution runs under their
+// totals, with a drill-down into each run's journal.
+type RunsModel struct {
+	stateRoot string
+	runs      []state.RunSummary
+	rows      []RunRow
+	cursor    int
+	detail    bool
+	logStream components.LogStreamModel
+	width     int
+	height    int
+}
+
+// NewRunsModel creates the Runs tab for the project at root.
+func NewRunsModel(root string) RunsModel {
+	return RunsModel{
+		stateRoot: root,
+		logStream: components.NewLogStreamModel(),
+	}
+}
+
+// SetRuns refreshes the list from state.
+func (m *RunsModel) SetRuns(runs []state.RunSummary) {
+	m.runs = append([]state.RunSummary(nil), runs...)
+	m.rows = BuildRunRows(m.runs)
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(len(m.rows)-1, 0)
+	}
+}
+
+// SetSize updates the component dimensions.
+func (m *RunsModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Update handles navigation within the tab.
+func (m RunsModel) Update(msg tea.KeyMsg) (RunsModel, tea.Cmd) {
+	if m.detail {
+		switch msg.String() {
+		case "esc", "backspace":
+			m.detail = false
+		default:
+			var cmd tea.Cmd
+			m.logStream, cmd = m.logStream.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "enter":
+		if m.cursor < len(m.rows) {
+			m.openDetail(m.rows[m.cursor].ID)
+		}
+	}
+	return m, nil
+}
+
+// openDetail loads the selected run's journal into the log view.
+func (m *RunsModel) openDetail(runID int) {
+	var run state.RunSummary
+	for _, r := range m.runs {
+		if r.ID == runID {
+			run = r
+		}
+	}
+
+	var events []executor.TaskEvent
+	if entries, err := executor.ReadJournal(executor.JournalPath(m.stateRoot)); err == nil {
+		events = executor.EventsForRun(entries, runID)
+	}
+	m.logStream.SetLines(toComponentLogLines(RunDetailLines(run, events)))
+	m.detail = true
+}
+
+// View renders the runs list, or the selected run's journal.
+func (m RunsModel) View() string {
+	if m.detail {
+		m.logStream.SetSize(m.width, m.height)
+		return m.logStream.View()
+	}
+
+	if len(m.rows) == 0 {
+		return lipgloss.NewStyle().Foreground(Muted).Render("  No runs recorded yet")
+	}
+
+	// Totals over every run, then as many rows as fit below them
+	lines := []string{lipgloss.NewStyle().Foreground(Muted).Render("  " + FormatRunTotals(state.AggregateRuns(m.runs)))}
+	height := max(m.height-1, 1)
+	start := 0
+	if len(m.rows) > height && m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	for i := start; i < len(m.rows) && len(lines) <= height; i++ {
+		style := lipgloss.NewStyle().Foreground(Text)
+		if i == m.cursor {
+			style = style.Foreground(Secondary).Bold(true)
+		}
+		lines = append(lines, style.Render(FormatRunRow(m.rows[i], i == m.cursor)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HelpText returns the footer hint for the tab.
+func (m RunsModel) HelpText() string {
+	if m.detail {
+		return "  esc back · tab tasks"
+	}
+	return "  j/k navigate · enter journal · tab tasks"
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// RunRow is one line of the Runs tab.
+type RunRow struct {
+	ID          int
+	Started     string // local date and time
+	PlanVersion int
+	Attempted   int
+	Outcome     string // e.g. "completed · 3/4 done"
+	Duration    string // "—" while in progress
+	SuccessRate int    // percent of attempted tasks that completed
+}
+
+// RunSuccessRate returns the percentage of attempted tasks that completed.
+func RunSuccessRate(run state.RunSummary) int {
+	attempted := run.Completed + run.Failed
+	if attempted == 0 {
+		return 0
+	}
+	return run.Completed * 100 / attempted
+}
+
+// BuildRunRows converts persisted runs into display rows, newest first.
+func BuildRunRows(runs []state.RunSummary) []RunRow {
+	rows := make([]RunRow, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		attempted := len(run.TaskIDs)
+		if attempted == 0 {
+			attempted = run.Completed + run.Failed
+		}
+
+		duration := "—"
+		if run.FinishedAt != nil {
+			duration = FormatElapsed(run.Duration())
+		}
+
+		rows = append(rows, RunRow{
+			ID:          run.ID,
+			Started:     run.StartedAt.Local().Format("2006-01-02 15:04"),
+			PlanVersion: run.PlanVersion,
+			Attempted:   attempted,
+			Outcome:     fmt.Sprintf("%s · %d/%d done", run.Status, run.Completed, attempted),
+			Duration:    duration,
+			SuccessRate: RunSuccessRate(run),
+		})
+	}
+	return rows
+}
+
+// FormatRunTotals renders the totals over every recorded run, shown above
+// the runs list: "3 runs · 7 done, 1 failed · 2 retries · 12:04 · $1.20".
+func FormatRunTotals(t state.RunTotals) string {
+	runs := "runs"
+	if t.Runs == 1 {
+		runs = "run"
+	}
+	parts := []string{fmt.Sprintf("%d %s", t.Runs, runs)}
+
+	counts := []string{fmt.Sprintf("%d done", t.Completed)}
+	if t.Failed > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", t.Failed))
+	}
+	if t.Skipped > 0 {
+		counts = append(counts, fmt.Sprintf("%d skipped", t.Skipped))
+	}
+	parts = append(parts, strings.Join(counts, ", "))
+
+	if t.Retries > 0 {
+		parts = append(parts, fmt.Sprintf("%d retries", t.Retries))
+	}
+	parts = append(parts, FormatElapsed(t.Duration))
+	if t.TokensUsed > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens", t.TokensUsed))
+	}
+	if t.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", t.CostUSD))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatRunRow renders a row as a fixed-width line for the runs list.
+func FormatRunRow(row RunRow, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = "> "
+	}
+	return fmt.Sprintf("%s#%-3d %s  plan v%-2d %-28s %3d%%  %s",
+		cursor, row.ID, row.Started, row.PlanVersion, row.Outcome, row.SuccessRate, row.Duration)
+}
+
+// RunDetailLines builds the drill-down view of a run from its journal
+// events. Streaming Claude chunks are left out to keep the view readable;
+// the full text is in the task logs.
+func RunDetailLines(run state.RunSummary, events []executor.TaskEvent) []LogLine {
+	lines := []LogLine{{
+		Text: fmt.Sprintf("Run #%d · plan v%d · %s", run.ID, run.PlanVersion, run.StartedAt.Local().Format("2006-01-02 15:04")),
+		Type: LogInfo,
+	}}
+	if len(run.TaskIDs) > 0 {
+		lines = append(lines, LogLine{
+			Text: "Tasks: " + strings.Join(run.TaskIDs, ", ") + " (logs in .forge/logs/)",
+			Type: LogInfo,
+		})
+	}
+
+	if len(events) == 0 {
+		lines = append(lines, LogLine{Text: "No journal entries for this run", Type: LogWarning})
+		return lines
+	}
+
+	for _, e := range events {
+		if e.Type == executor.EventClaudeChunk {
+			continue
+		}
+		line := EventToLogLine(e)
+		if line == nil {
+			continue
+		}
+		if e.TaskID != "" {
+			line.Text = e.TaskID + ": " + line.Text
+		}
+		lines = append(lines, *line)
+	}
+	return lines
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/state"
+)
+
+// ============================================================
+// RunSuccessRate / BuildRunRows
+// ============================================================
+
+func TestRunSuccessRate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  state.RunSummary
+		want int
+	}{
+		{"nothing attempted", state.RunSummary{}, 0},
+		{"all done", state.RunSummary{Completed: 4}, 100},
+		{"mixed", state.RunSummary{Completed: 3, Failed: 1}, 75},
+		{"skips don't count", state.RunSummary{Completed: 1, Failed: 1, Skipped: 5}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := RunSuccessRate(tt.run); got != tt.want {
+				t.Errorf("RunSuccessRate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRunRows(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	end := start.Add(90 * time.Second)
+	runs := []state.RunSummary{
+		{ID: 1, PlanVersion: 1, Status: state.RunCompleted, StartedAt: start, FinishedAt: &end,
+			TaskIDs: []string{"task-001", "task-002"}, Completed: 1, Failed: 1},
+		{ID: 2, PlanVersion: 2, Status: state.RunInProgress, StartedAt: start.Add(time.Hour)},
+	}
+
+	rows := BuildRunRows(runs)
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(rows))
+	}
+	if rows[0].ID != 2 || rows[1].ID != 1 {
+		t.Errorf("rows should be newest first, got IDs %d, %d", rows[0].ID, rows[1].ID)
+	}
+	if rows[0].Duration != "—" {
+		t.Errorf("in-progress duration = %q, want —", rows[0].Duration)
+	}
+
+	r := rows[1]
+	if r.Attempted != 2 || r.SuccessRate != 50 || r.Duration != "1:30" || r.PlanVersion != 1 {
+		t.Errorf("row = %+v", r)
+	}
+	if r.Started != "2026-03-01 12:00" {
+		t.Errorf("Started = %q", r.Started)
+	}
+	if !strings.Contains(r.Outcome, "completed") || !strings.Contains(r.Outcome, "1/2 done") {
+		t.Errorf("Outcome = %q", r.Outcome)
+	}
+
+	line := FormatRunRow(r, true)
+	for _, want := range []string{"> ", "#1", "plan v1", "50%", "1:30"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("FormatRunRow() = %q, missing %q", line, want)
+		}
+	}
+}
+
+func TestFormatRunTotals(t *testing.T) {
+	t.Parallel()
+	got := FormatRunTotals(state.RunTotals{Runs: 3, Completed: 7, Failed: 1, Retries: 2,
+		TokensUsed: 5400, CostUSD: 1.2, Duration: 12*time.Minute + 4*time.Second})
+	if want := "3 runs · 7 done, 1 failed · 2 retries · 12:04 · 5400 tokens · $1.20"; got != want {
+		t.Errorf("FormatRunTotals() = %q, want %q", got, want)
+	}
+	if got := FormatRunTotals(state.RunTotals{Runs: 1}); got != "1 run · 0 done · 0:00" {
+		t.Errorf("FormatRunTotals(one empty run) = %q", got)
+	}
+}
+
+// ============================================================
+// RunDetailLines
+// ============================================================
+
+func TestRunDetailLines(t *testing.T) {
+	t.Parallel()
+	run := state.RunSummary{ID: 3, PlanVersion: 2, TaskIDs: []string{"task-001"}}
+
+	t.Run("no events", func(t *testing.T) {
+		t.Parallel()
+		lines := RunDetailLines(run, nil)
+		last := lines[len(lines)-1]
+		if last.Type != LogWarning || !strings.Contains(last.Text, "No journal entries") {
+			t.Errorf("last line = %+v, want warning", last)
+		}
+	})
+
+	t.Run("events without chunks", func(t *testing.T) {
+		t.Parallel()
+		events := []executor.TaskEvent{
+			{TaskID: "task-001", Type: executor.EventClaudeStart},
+			{TaskID: "task-001", Type: executor.EventClaudeChunk, Detail: "thinking..."},
+			{TaskID: "task-001", Type: executor.EventTestPassed},
+		}
+		lines := RunDetailLines(run, events)
+
+		var text []string
+		for _, l := range lines {
+			text = append(text, l.Text)
+		}
+		joined := strings.Join(text, "\n")
+		if strings.Contains(joined, "thinking") {
+			t.Error("claude chunks should be omitted")
+		}
+		if !strings.Contains(joined, "task-001: Tests passed") {
+			t.Errorf("missing prefixed event line:\n%s", joined)
+		}
+		if !strings.Contains(lines[0].Text, "Run #3 · plan v2") {
+			t.Errorf("header = %q", lines[0].Text)
+		}
+	})
+}
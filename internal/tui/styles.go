@@ -1,6 +1,9 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manasm11/forge/internal/tui/components"
+)
 
 var (
 	// Colors
@@ -40,3 +43,8 @@ var (
 	PhaseLabelStyle = lipgloss.NewStyle().
 			Foreground(Muted)
 )
+
+// logo is forge's mark in titles.
+func logo() string {
+	return components.Glyph("⚒", "#")
+}
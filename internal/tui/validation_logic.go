@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks a validation problem.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // blocks saving
+	SeverityWarning Severity = "warning" // shown, but saving may go ahead
+)
+
+// FieldError is a validation problem tied to the field it is about, so the
+// inputs and review screens can point at that field.
+type FieldError struct {
+	Field    string // InputField.Key, or an edit template key such as "complexity"
+	Message  string
+	Severity Severity
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors is everything wrong with a form.
+type FieldErrors []FieldError
+
+// Blocking reports whether any of the problems is an error.
+func (errs FieldErrors) Blocking() bool {
+	for _, e := range errs {
+		if e.Severity != SeverityWarning {
+			return true
+		}
+	}
+	return false
+}
+
+// For returns the problems with one field.
+func (errs FieldErrors) For(field string) FieldErrors {
+	var out FieldErrors
+	for _, e := range errs {
+		if e.Field == field {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Summary counts the problems, e.g. "2 errors, 1 warning".
+func (errs FieldErrors) Summary() string {
+	var nErr, nWarn int
+	for _, e := range errs {
+		if e.Severity == SeverityWarning {
+			nWarn++
+		} else {
+			nErr++
+		}
+	}
+	var parts []string
+	if nErr > 0 {
+		parts = append(parts, fmt.Sprintf("%d error%s", nErr, pluralize(nErr)))
+	}
+	if nWarn > 0 {
+		parts = append(parts, fmt.Sprintf("%d warning%s", nWarn, pluralize(nWarn)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fieldErr(field, format string, args ...any) FieldError {
+	return FieldError{Field: field, Message: fmt.Sprintf(format, args...), Severity: SeverityError}
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manasm11/forge/internal/state"
+)
+
+func TestFieldErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		errs        FieldErrors
+		wantBlock   bool
+		wantSummary string
+	}{
+		{name: "none", wantSummary: ""},
+		{name: "warnings only", errs: FieldErrors{{Field: "a", Severity: SeverityWarning}}, wantSummary: "1 warning"},
+		{
+			name: "errors and warnings",
+			errs: FieldErrors{
+				{Field: "a", Severity: SeverityError},
+				{Field: "b", Severity: SeverityError},
+				{Field: "a", Severity: SeverityWarning},
+			},
+			wantBlock:   true,
+			wantSummary: "2 errors, 1 warning",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.errs.Blocking(); got != tt.wantBlock {
+				t.Errorf("Blocking() = %v, want %v", got, tt.wantBlock)
+			}
+			if got := tt.errs.Summary(); got != tt.wantSummary {
+				t.Errorf("Summary() = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+
+	errs := FieldErrors{{Field: "a", Message: "1"}, {Field: "b", Message: "2"}, {Field: "a", Message: "3"}}
+	if got := errs.For("a"); len(got) != 2 || got[1].Message != "3" {
+		t.Errorf("For(a) = %v", got)
+	}
+}
+
+func TestValidateNewTask_Field(t *testing.T) {
+	t.Parallel()
+	existing := []state.Task{{ID: "task-001"}}
+	tests := []struct {
+		name       string
+		title      string
+		complexity string
+		deps       []string
+		wantField  string
+	}{
+		{name: "title", complexity: "small", wantField: "title"},
+		{name: "complexity", title: "T", complexity: "huge", wantField: "complexity"},
+		{name: "dependency", title: "T", complexity: "small", deps: []string{"task-404"}, wantField: "depends_on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var fe FieldError
+			err := ValidateNewTask(existing, tt.title, "", tt.complexity, nil, tt.deps)
+			if !errors.As(err, &fe) || fe.Field != tt.wantField || fe.Severity != SeverityError {
+				t.Errorf("error = %#v, want a FieldError for %q", err, tt.wantField)
+			}
+		})
+	}
+}
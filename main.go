@@ -2,22 +2,260 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/manasm11/forge/internal/analysis"
 	"github.com/manasm11/forge/internal/claude"
+	"github.com/manasm11/forge/internal/cli"
+	"github.com/manasm11/forge/internal/codeindex"
+	"github.com/manasm11/forge/internal/dbschema"
+	"github.com/manasm11/forge/internal/docs"
 	"github.com/manasm11/forge/internal/executor"
+	"github.com/manasm11/forge/internal/janitor"
+	"github.com/manasm11/forge/internal/planner"
 	"github.com/manasm11/forge/internal/preflight"
 	"github.com/manasm11/forge/internal/provider"
 	"github.com/manasm11/forge/internal/scanner"
+	"github.com/manasm11/forge/internal/schedule"
+	"github.com/manasm11/forge/internal/server"
 	"github.com/manasm11/forge/internal/state"
+	"github.com/manasm11/forge/internal/taskcmd"
+	"github.com/manasm11/forge/internal/transcript"
 	"github.com/manasm11/forge/internal/tui"
 )
 
+// cliOptions holds the parsed command line.
+type cliOptions struct {
+	command     string     // "" (interactive session), "run", "transcript", "replay", "plan", "cleanup", "task", "serve", "completion" or "help"
+	at          string     // run: delayed start time
+	from        string     // run: task to start at; earlier pending tasks are skipped for now
+	taskID      string     // transcript: task to export ("" = planning session)
+	taskArgs    []string   // task: action and its arguments, handled by taskcmd
+	out         string     // transcript, plan: output file ("" = stdout)
+	journal     string     // replay: journal file ("" = .forge/journal.jsonl)
+	runID       int        // replay: run to play back (0 = latest)
+	speed       float64    // replay: playback speed multiplier
+	prompt      string     // plan: project description
+	answers     stringList // plan: scripted answers, in order
+	answersFile string     // plan: file of scripted answers, one per line
+	decisions   string     // plan: YAML answer file of settled decisions
+	contextURLs stringList // plan: reference documents to fetch into the prompt
+	analyze     bool       // plan: include static analysis and coverage findings
+	model       string     // plan: model to plan with
+	critic      string     // interactive, plan: model that reviews each plan ("" = off)
+	accessible  bool       // interactive, replay: screen-reader friendly output
+	all         bool       // cleanup: also remove resources of running sessions
+	shell       string     // completion: shell to generate the script for
+	socket      string     // serve: socket path ("" = .forge/forge.sock)
+	helpPath    string     // help: command to describe ("" = forge itself)
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ", ") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// commands builds forge's command tree, binding flags into opts.
+func commands(opts *cliOptions) *cli.Command {
+	tasks := &cli.Command{
+		Name:     "task",
+		Synopsis: "ACTION [flags]",
+		Summary:  "Manage the plan's tasks without the TUI",
+		Commands: taskcmd.Commands(),
+	}
+	cancel, _ := tasks.Find([]string{"cancel"})
+
+	return &cli.Command{
+		Name:     "forge",
+		Synopsis: "[--critic MODEL] [--accessible] [COMMAND]",
+		Summary:  "Plan a project with Claude, review the tasks, then let forge build them one branch at a time. Without a command it opens the interactive session.",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&opts.critic, "critic", "", "model that reviews each new plan for gaps (default: off)")
+			fs.BoolVar(&opts.accessible, "accessible", false, "linear, screen-reader friendly output without box drawing or the alternate screen")
+		},
+		Commands: []*cli.Command{
+			{
+				Name:     "run",
+				Synopsis: "[--at TIME] [--from TASK-ID]",
+				Summary:  "Jump straight to execution of the current plan",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&opts.at, "at", "", "start execution at HH:MM, +duration or an RFC 3339 time")
+					fs.StringVar(&opts.from, "from", "", "start at this task, skipping earlier pending tasks for now")
+				},
+			},
+			{
+				Name:     "transcript",
+				Synopsis: "[-o FILE] [TASK-ID]",
+				Summary:  "Export the prompts and responses of a task, or of the latest planning session, as markdown",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&opts.out, "o", "", "write the markdown to a file instead of stdout")
+				},
+				Args: cli.ArgTaskID,
+			},
+			{
+				Name:     "replay",
+				Synopsis: "[--speed N] [--run ID] [JOURNAL]",
+				Summary:  "Play back a recorded run in the execution dashboard",
+				Flags: func(fs *flag.FlagSet) {
+					fs.Float64Var(&opts.speed, "speed", 10, "playback speed multiplier")
+					fs.IntVar(&opts.runID, "run", 0, "run to replay (default: the latest)")
+				},
+				Args: cli.ArgFile,
+			},
+			{
+				Name:     "plan",
+				Synopsis: "--prompt TEXT [--answer TEXT]... [--answers FILE] [--out FILE]",
+				Summary:  "Run planning without the TUI and write the plan JSON",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&opts.prompt, "prompt", "", "what to build (required)")
+					fs.Var(&opts.answers, "answer", "reply to the planner's next question (repeatable)")
+					fs.StringVar(&opts.answersFile, "answers", "", "file of replies, one per line")
+					fs.StringVar(&opts.decisions, "answer-file", "", "YAML of settled decisions (default: .forge/answers.yaml if present)")
+					fs.Var(&opts.contextURLs, "context-url", "design doc or API spec to plan against (repeatable; default: Settings.ContextURLs)")
+					fs.BoolVar(&opts.analyze, "analyze", false, "run vet/unused-code tools and read coverage reports into the prompt")
+					fs.StringVar(&opts.out, "out", "", "write the plan JSON to a file instead of stdout")
+					fs.StringVar(&opts.model, "model", "sonnet", "model to plan with")
+					fs.StringVar(&opts.critic, "critic", "", "model that reviews the plan; findings go to stderr")
+				},
+			},
+			{
+				Name:     "cleanup",
+				Synopsis: "[--all]",
+				Summary:  "Remove temp files left behind by forge sessions",
+				Flags: func(fs *flag.FlagSet) {
+					fs.BoolVar(&opts.all, "all", false, "also remove temp files of forge sessions that are still running")
+				},
+			},
+			tasks,
+			{
+				Name:     "cancel",
+				Synopsis: cancel.Synopsis,
+				Summary:  "Short for `forge task cancel`",
+				Flags:    cancel.Flags,
+				Args:     cli.ArgTaskID,
+				Raw:      true,
+			},
+			{
+				Name:     "serve",
+				Synopsis: "[--socket PATH]",
+				Summary:  "Serve the plan and runs over a local JSON-RPC socket for editor extensions",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&opts.socket, "socket", "", "Unix socket to listen on (default: .forge/forge.sock)")
+				},
+			},
+			{
+				Name:     "completion",
+				Synopsis: "bash|zsh|fish",
+				Summary:  "Print a shell completion script",
+			},
+			{
+				Name:     "help",
+				Synopsis: "[COMMAND]...",
+				Summary:  "Show help for a command",
+			},
+		},
+	}
+}
+
+// parseArgs parses the command line against the command tree. --help on
+// any command, and `forge help COMMAND`, become the "help" command.
+func parseArgs(args []string) (cliOptions, error) {
+	var opts cliOptions
+	inv, err := commands(&opts).Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return cliOptions{command: "help", helpPath: inv.Path}, nil
+	}
+	if err != nil {
+		return cliOptions{}, err
+	}
+
+	command, _, _ := strings.Cut(inv.Path, " ")
+	opts.command = command
+	switch command {
+	case "":
+		if len(inv.Args) > 0 {
+			return cliOptions{}, fmt.Errorf("unexpected argument %q", inv.Args[0])
+		}
+	case "transcript":
+		if len(inv.Args) > 1 {
+			return cliOptions{}, fmt.Errorf("transcript: unexpected argument %q", inv.Args[1])
+		}
+		if len(inv.Args) == 1 {
+			opts.taskID = inv.Args[0]
+		}
+	case "replay":
+		if len(inv.Args) > 1 {
+			return cliOptions{}, fmt.Errorf("replay: unexpected argument %q", inv.Args[1])
+		}
+		if opts.speed <= 0 {
+			return cliOptions{}, fmt.Errorf("replay: --speed must be positive")
+		}
+		if len(inv.Args) == 1 {
+			opts.journal = inv.Args[0]
+		}
+	case "plan":
+		if len(inv.Args) > 0 {
+			return cliOptions{}, fmt.Errorf("plan: unexpected argument %q", inv.Args[0])
+		}
+		if strings.TrimSpace(opts.prompt) == "" {
+			return cliOptions{}, fmt.Errorf("plan: --prompt is required")
+		}
+	case "task":
+		// Subcommands are Raw; taskcmd parses their flags
+		opts.taskArgs = append(strings.Fields(strings.TrimPrefix(inv.Path, "task")), inv.Args...)
+	case "cancel":
+		opts.command, opts.taskArgs = "task", append([]string{"cancel"}, inv.Args...)
+	case "completion":
+		if len(inv.Args) != 1 {
+			return cliOptions{}, fmt.Errorf("completion: expected one of %s", strings.Join(cli.Shells, ", "))
+		}
+		opts.shell = inv.Args[0]
+	case "help":
+		opts.helpPath = strings.Join(inv.Args, " ")
+	default:
+		if len(inv.Args) > 0 {
+			return cliOptions{}, fmt.Errorf("%s: unexpected argument %q", command, inv.Args[0])
+		}
+	}
+	return opts, nil
+}
+
 func main() {
+	opts, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Help and completion scripts come from the command tree alone
+	switch opts.command {
+	case "help":
+		if err := commands(&cliOptions{}).WriteHelp(os.Stdout, opts.helpPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		return
+	case "completion":
+		script, err := commands(&cliOptions{}).Completion(opts.shell)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Print(script)
+		return
+	}
+
 	// 1. Determine project root (current working directory)
 	root, err := os.Getwd()
 	if err != nil {
@@ -25,6 +263,60 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Transcript export only reads .forge/, so it skips the preflight checks
+	if opts.command == "transcript" {
+		if err := exportTranscript(root, opts.taskID, opts.out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Headless planning writes a plan file and leaves .forge/ alone
+	if opts.command == "plan" {
+		if err := planHeadless(root, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Replays render recorded events and never run tasks
+	if opts.command == "replay" {
+		if err := replayJournal(root, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Cleanup only touches tracked temp files
+	if opts.command == "cleanup" {
+		if err := cleanup(root, opts.all); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The editor server runs tasks itself, without the TUI
+	if opts.command == "serve" {
+		if err := serve(root, opts.socket); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Task commands edit state.json; a running session picks them up before its next task
+	if opts.command == "task" {
+		if err := taskcmd.Run(root, opts.taskArgs, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 2. Run preflight checks
 	results := preflight.RunAll()
 	allPassed := true
@@ -36,9 +328,23 @@ func main() {
 			allPassed = false
 		}
 	}
+	if disk := preflight.CheckDiskSpace(root, preflight.MinFreeDisk); disk.Found {
+		fmt.Printf("  \u2713 %s (%s)\n", disk.Name, disk.Version)
+	} else {
+		fmt.Printf("  \u2717 %s \u2014 %s\n", disk.Name, disk.Error)
+		allPassed = false
+	}
+	if preflight.UsesLFS(root) {
+		if lfs := preflight.EnsureLFS(root); lfs.Found {
+			fmt.Printf("  \u2713 %s (%s)\n", lfs.Name, lfs.Version)
+		} else {
+			fmt.Printf("  \u2717 %s \u2014 %s\n", lfs.Name, lfs.Error)
+			allPassed = false
+		}
+	}
 
 	if !allPassed {
-		fmt.Fprintln(os.Stderr, "\nPlease install all required tools before running forge.")
+		fmt.Fprintln(os.Stderr, "\nPlease install all required tools and free up disk space before running forge.")
 		os.Exit(1)
 	}
 	fmt.Println("  \u2713 All checks passed")
@@ -58,9 +364,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	if opts.command == "run" {
+		if err := prepareRun(root, s, opts.at, opts.from, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if s == nil {
 		// 4a. New forge session — scan the project directory
-		snapshot := scanner.Scan(root)
+		snapshot := scanner.ScanCached(root)
 
 		// Auto-initialize git if not a git repo
 		gitResult := scanner.InitGit(root)
@@ -127,6 +440,15 @@ func main() {
 		if s.Settings != nil && s.Settings.Provider.Type != "" {
 			selectedProvider = s.Settings.Provider.Type
 		}
+
+		// Bring the code search index up to date with the project
+		if embed := codeindex.OllamaEmbedder(s.Settings); embed != nil {
+			if _, n, err := codeindex.Refresh(context.Background(), root, codeindex.Model(s.Settings), embed); err != nil {
+				fmt.Printf("  Warning: code search index not updated: %v\n\n", err)
+			} else if n > 0 {
+				fmt.Printf("  Indexed %d changed file(s) for code search\n\n", n)
+			}
+		}
 	}
 
 	// 5. Create Claude client (sonnet model for planning, --max-turns 1 default)
@@ -148,6 +470,7 @@ func main() {
 		OllamaURL: ollamaURL,
 	})
 
+	var critic claude.Claude
 	if c, err := claude.NewClient("claude", 5*time.Minute, model); err != nil {
 		// Don't exit — let the TUI start and show error when user tries to chat
 		fmt.Printf("  Warning: %v\n", err)
@@ -156,6 +479,16 @@ func main() {
 	} else {
 		// Set provider-specific environment variables
 		claudeClient = c.WithEnvVars(providerEnvVars)
+		if opts.critic != "" {
+			critic = c.WithModel(opts.critic).WithEnvVars(providerEnvVars)
+		}
+	}
+
+	// Remove temp files a crashed session left behind
+	if removed, err := janitor.Clean(root, janitor.Orphaned); err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	} else if len(removed) > 0 {
+		fmt.Printf("  Removed %d temp file(s) left by a previous forge session\n", len(removed))
 	}
 
 	// 6. Create Claude executor for task execution
@@ -163,27 +496,358 @@ func main() {
 
 	// 7. Create app model with state and claude client
 	app := tui.NewAppModel(s, root, claudeClient, claudeExec)
+	if critic != nil {
+		app.SetCritic(critic)
+	}
+	accessible := opts.accessible || tui.Accessible(s.Settings)
+	app.SetAccessible(accessible)
 
-	// 7. Run bubbletea
-	p := tea.NewProgram(&app, tea.WithAltScreen())
+	// 7. Run bubbletea. Accessible mode stays on the normal screen so the
+	// printed event log remains in the scrollback.
+	var progOpts []tea.ProgramOption
+	if !accessible {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(&app, progOpts...)
 
 	// Set the program reference for streaming support
 	app.SetProgram(p)
 
 	finalModel, err := p.Run()
+	janitor.Clean(root, janitor.Owned)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
 		os.Exit(1)
 	}
 
 	// 8. On exit, save final state
-	if m, ok := finalModel.(*tui.AppModel); ok {
+	if m, ok := finalModel.(*tui.AppModel); ok && m.StateConflict() {
+		fmt.Fprintln(os.Stderr, "Note: .forge/state.json was changed by another process during the run; left it as is.")
+	} else if ok {
 		if saveErr := state.Save(root, m.State()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not save state on exit: %v\n", saveErr)
 		}
 	}
 }
 
+// prepareRun moves a planned and configured session straight to execution,
+// optionally starting at a given task and holding the start until the
+// given time.
+func prepareRun(root string, s *state.State, at, from string, now time.Time) error {
+	if s == nil {
+		return fmt.Errorf("no forge session here — run forge first to plan the project")
+	}
+	if s.Settings == nil {
+		return fmt.Errorf("execution settings are not configured — finish the inputs phase first")
+	}
+
+	if from != "" {
+		deferred, err := s.StartFrom(from)
+		if err != nil {
+			return err
+		}
+		if len(deferred) > 0 {
+			fmt.Printf("  Starting from %s; skipped for now: %s\n", from, strings.Join(deferred, ", "))
+		}
+	}
+
+	s.ScheduledStart = nil
+	if at != "" {
+		t, err := schedule.Parse(at, now)
+		if err != nil {
+			return err
+		}
+		s.ScheduledStart = &t
+		fmt.Printf("  Execution scheduled for %s\n", t.Format("Mon Jan 2 15:04"))
+	}
+	s.Phase = state.PhaseExecution
+
+	return state.Save(root, s)
+}
+
+// serve listens for editor clients until interrupted. Runs they start use
+// the same runner setup as the execution dashboard.
+func serve(root, socket string) error {
+	if socket == "" {
+		socket = server.SocketPath(root)
+	}
+	l, err := server.Listen(socket)
+	if err != nil {
+		return err
+	}
+	if err := janitor.Track(root, socket, "editor socket"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	defer janitor.Release(root, socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("  Serving forge on %s (Ctrl+C to stop)\n", socket)
+	return server.New(root, headlessExecute(root)).Serve(ctx, l)
+}
+
+// headlessExecute builds runs for forge serve.
+func headlessExecute(root string) server.ExecuteFunc {
+	claudeExec := executor.NewRealClaudeExecutor(root)
+	return func(ctx context.Context, s *state.State, onEvent func(executor.TaskEvent), confirm <-chan executor.ManualConfirmation) error {
+		contextContent := ""
+		if data, err := os.ReadFile(filepath.Join(root, ".forge", "context.md")); err == nil {
+			contextContent = string(data)
+		}
+		contextContent += docs.Context(ctx, root, s.Settings.ContextURLs)
+		journal, _ := executor.OpenJournal(root)
+		defer journal.Close()
+		webhook := executor.NewWebhook(s.Settings.WebhookURL, nil)
+		defer webhook.Close()
+
+		return executor.NewRunner(executor.RunnerConfig{
+			State:       s,
+			StateRoot:   root,
+			Git:         executor.NewRealGitOps(root),
+			Tests:       executor.NewRealTestRunner(root).WithShell(s.Settings.Shell),
+			Claude:      claudeExec,
+			ContextFile: contextContent,
+			BaseBranch:  s.Settings.BaseBranch,
+			RemoteURL:   s.Settings.RemoteURL,
+			Issues:      executor.NewGhIssueFiler(root),
+			PRs:         executor.NewGhPRCreator(root),
+			Checks:      executor.NewGhCheckPublisher(root),
+			CI:          executor.NewGhCIWatcher(),
+			Workspace:   executor.RealWorkspace(s.Settings.Shell),
+			Embed:       codeindex.OllamaEmbedder(s.Settings),
+			Journal:     journal,
+			Webhook:     webhook,
+			Confirm:     confirm,
+			OnEvent:     onEvent,
+		}).Run(ctx)
+	}
+}
+
+// cleanup removes the temp files tracked in .forge/resources.json. Without
+// all, files belonging to a forge that is still running are kept.
+func cleanup(root string, all bool) error {
+	which := janitor.Orphaned
+	if all {
+		which = janitor.All
+	}
+	removed, err := janitor.Clean(root, which)
+	for _, r := range removed {
+		fmt.Printf("  Removed %s (%s)\n", r.Path, r.Purpose)
+	}
+	if err != nil {
+		return err
+	}
+
+	left, err := janitor.Load(root)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(left) > 0:
+		fmt.Printf("  Kept %d file(s) in use by a running forge (pid %d); use --all to remove them too\n", len(left), left[0].PID)
+	case len(removed) == 0:
+		fmt.Println("  Nothing to clean up")
+	}
+	return nil
+}
+
+// exportTranscript writes the redacted prompt/response transcript of a task,
+// or of the latest planning session when taskID is empty, as markdown.
+func exportTranscript(root, taskID, out string) error {
+	s, err := state.Load(root)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if s == nil {
+		return fmt.Errorf("no forge session here")
+	}
+
+	entries, err := executor.ReadJournal(executor.JournalPath(root))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	env := transcript.EnvMap(os.Environ())
+	var settingsEnv map[string]string
+	if s.Settings != nil {
+		settingsEnv = s.Settings.EnvVars
+	}
+	redactor := transcript.NewRedactor(env, settingsEnv)
+
+	var md string
+	if taskID == "" {
+		md, err = transcript.Planning(s, entries, redactor)
+	} else {
+		md, err = transcript.Task(s, entries, taskID, redactor)
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = fmt.Print(md)
+		return err
+	}
+	return os.WriteFile(out, []byte(md), 0644)
+}
+
+// replayJournal plays one recorded run back through the execution dashboard.
+func replayJournal(root string, opts cliOptions) error {
+	path := opts.journal
+	if path == "" {
+		path = executor.JournalPath(root)
+	}
+	entries, err := executor.ReadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	runID := opts.runID
+	if runID == 0 {
+		runID = executor.LatestRunID(entries)
+	}
+	events := executor.EventsForRun(entries, runID)
+	if len(events) == 0 {
+		return fmt.Errorf("no events recorded for run %d in %s", runID, path)
+	}
+
+	// State only supplies task titles; a replay works without it
+	s, _ := state.Load(root)
+
+	m := tui.NewReplayModel(s, root, runID, events, opts.speed)
+	if opts.accessible {
+		m.SetAccessible(true)
+	}
+	var progOpts []tea.ProgramOption
+	if !m.IsAccessible() {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(m, progOpts...)
+	m.SetProgram(p)
+	_, err = p.Run()
+	return err
+}
+
+// planHeadless runs the planning conversation without the TUI and writes
+// the resulting plan as JSON.
+func planHeadless(root string, opts cliOptions) error {
+	return planWith(root, opts, func() (claude.Claude, claude.Claude, error) {
+		client, err := claude.NewClient("claude", 5*time.Minute, opts.model)
+		if err != nil {
+			return nil, nil, err
+		}
+		var critic claude.Claude
+		if opts.critic != "" {
+			critic = client.WithModel(opts.critic)
+		}
+		return client, critic, nil
+	})
+}
+
+// planWith is planHeadless with the clients opened by connect: the
+// planner, and the critic when --critic is set.
+func planWith(root string, opts cliOptions, connect func() (client, critic claude.Claude, err error)) error {
+	answers := []string(opts.answers)
+	if opts.answersFile != "" {
+		f, err := os.Open(opts.answersFile)
+		if err != nil {
+			return err
+		}
+		fromFile, err := planner.ReadAnswers(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("reading answers: %w", err)
+		}
+		answers = append(answers, fromFile...)
+	}
+
+	decisionsPath := opts.decisions
+	if decisionsPath == "" {
+		decisionsPath = filepath.Join(state.ForgeDir(root), planner.AnswerFileName)
+	} else if _, err := os.Stat(decisionsPath); err != nil {
+		return err
+	}
+	decided, err := planner.LoadAnswerFile(decisionsPath)
+	if err != nil {
+		return err
+	}
+	memory, err := planner.LoadMemory(filepath.Join(state.ForgeDir(root), planner.MemoryFileName))
+	if err != nil {
+		return err
+	}
+
+	var settings *state.Settings
+	if s, err := state.Load(root); err == nil && s != nil {
+		settings = s.Settings
+	}
+	contextURLs := []string(opts.contextURLs)
+	if len(contextURLs) == 0 && settings != nil {
+		contextURLs = settings.ContextURLs
+	}
+	for _, u := range contextURLs {
+		if !docs.ValidURL(u) {
+			return fmt.Errorf("--context-url %q is not an http(s) URL", u)
+		}
+	}
+
+	client, critic, err := connect()
+	if err != nil {
+		return err
+	}
+
+	var snap *scanner.ProjectSnapshot
+	if s := scanner.ScanCached(root); s.IsExisting {
+		snap = &s
+	}
+
+	var findings string
+	if opts.analyze {
+		findings = analysis.Section(analysis.Run(context.Background(), root))
+	}
+
+	plan, err := planner.Run(context.Background(), client, planner.Options{
+		Prompt:   opts.prompt,
+		Answers:  answers,
+		Snapshot: snap,
+		Decided:  decided,
+		Memory:   memory,
+		Docs:     docs.Context(context.Background(), root, contextURLs),
+		Schema:   dbschema.Context(context.Background(), root, settings),
+		Analysis: findings,
+	})
+	if err != nil {
+		return err
+	}
+	for _, w := range plan.Warnings {
+		fmt.Fprintf(os.Stderr, "  Note: %s\n", w)
+	}
+	plan.SchemaVersion = claude.PlanSchemaVersion // checked against it
+
+	if critic != nil {
+		// Runs after planning finished, so its session can't disturb the planner's
+		findings, err := planner.Critique(context.Background(), critic, plan)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, tui.FormatCritique(findings))
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if opts.out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.out, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "  Plan %q with %d tasks written to %s\n", plan.ProjectName, len(plan.Tasks), opts.out)
+	return nil
+}
+
 func joinFrameworks(frameworks []string) string {
 	if len(frameworks) == 0 {
 		return ""
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/manasm11/forge/internal/claude"
+)
+
+func TestPlanWith_NoStateFile(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir() // a fresh directory: no .forge/state.json
+	out := filepath.Join(t.TempDir(), "plan.json")
+	mock := claude.NewMockClaude(claude.MockResponse{Text: `<final_plan>
+{"project_name": "cli", "description": "A CLI", "tech_stack": ["Go"],
+ "tasks": [{"title": "Init", "description": "Set up module", "acceptance_criteria": ["builds"], "complexity": "small", "depends_on": []}]}
+</final_plan>`})
+
+	err := planWith(root, cliOptions{prompt: "build a cli", out: out}, func() (claude.Claude, claude.Claude, error) {
+		return mock, nil, nil
+	})
+	if err != nil {
+		t.Fatalf("planWith() error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plan claude.PlanJSON
+	if err := json.Unmarshal(data, &plan); err != nil || plan.ProjectName != "cli" || len(plan.Tasks) != 1 {
+		t.Errorf("plan = %+v (%v), want the mocked plan", plan, err)
+	}
+}